@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+	"github.com/xkarasb/blog/pkg/utils"
+)
+
+// listPageSize is how many users list-users reads per query
+const listPageSize = 100
+
+// adminCommand manages users without the http api: `server admin create|promote|list-users`.
+// It is the only way to get an admin, registration never hands out that role.
+func adminCommand(args []string, env *commandEnv) error {
+	cli := &adminCLI{
+		service: service.NewAdminService(repository.NewBlogRepository(env.db), nil, nil, nil),
+		in:      os.Stdin,
+		out:     os.Stdout,
+		prompt:  os.Stderr,
+	}
+	return cli.run(context.Background(), args)
+}
+
+// createAdminCommand is `admin create` under the name it had before the admin subcommands
+func createAdminCommand(args []string, env *commandEnv) error {
+	return adminCommand(append([]string{"create"}, args...), env)
+}
+
+type adminCLI struct {
+	service *service.AdminService
+	in      io.Reader
+	// out gets the results, prompt the questions, so --json output stays parseable
+	out    io.Writer
+	prompt io.Writer
+}
+
+func (c *adminCLI) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("admin: expected create, promote or list-users")
+	}
+	switch args[0] {
+	case "create":
+		return c.create(ctx, args[1:])
+	case "promote":
+		return c.promote(ctx, args[1:])
+	case "list-users":
+		return c.listUsers(ctx, args[1:])
+	default:
+		return usageErrorf("admin: unknown subcommand %q, expected create, promote or list-users", args[0])
+	}
+}
+
+func (c *adminCLI) create(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("admin create", flag.ContinueOnError)
+	email := flags.String("email", "", "admin email, an existing user is promoted")
+	password := flags.String("password", "", "password of a new account, at least 8 characters, read from stdin when omitted")
+	asJSON := flags.Bool("json", false, "print the user as JSON")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageErrorf("admin create: --email is required")
+	}
+	if *password == "" {
+		var err error
+		if *password, err = c.readPassword(); err != nil {
+			return err
+		}
+	}
+
+	user, err := c.service.CreateAdmin(ctx, *email, *password)
+	if err != nil {
+		if err == errors.ErrorServiceIncorrectData {
+			return usageErrorf("admin create: the password needs at least 8 characters")
+		}
+		return fmt.Errorf("admin create: %w", err)
+	}
+	return c.printUser(&dto.UserResponse{UserId: user.UserId, Email: user.Email, Role: user.Role}, *asJSON)
+}
+
+func (c *adminCLI) promote(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("admin promote", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the user")
+	role := flags.String("role", string(types.Author), "new role: reader, author or admin")
+	asJSON := flags.Bool("json", false, "print the user as JSON")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageErrorf("admin promote: --email is required")
+	}
+	if err := utils.Validate(&dto.ChangeRoleRequest{Role: types.Role(*role)}); err != nil {
+		return usageErrorf("admin promote: --role must be reader, author or admin, got %q", *role)
+	}
+
+	user, err := c.service.SetRoleByEmail(ctx, *email, types.Role(*role))
+	if err != nil {
+		return fmt.Errorf("admin promote %s: %w", *email, err)
+	}
+	return c.printUser(user, *asJSON)
+}
+
+func (c *adminCLI) listUsers(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("admin list-users", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the users as JSON")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	var users []dto.UserResponse
+	for {
+		page, err := c.service.GetUsers(ctx, listPageSize, len(users))
+		if err != nil {
+			return fmt.Errorf("admin list-users: %w", err)
+		}
+		users = append(users, page.Users...)
+		if len(page.Users) < listPageSize || len(users) >= page.Total {
+			break
+		}
+	}
+
+	if *asJSON {
+		if users == nil {
+			users = []dto.UserResponse{}
+		}
+		return c.printJSON(&dto.GetUsersResponse{Users: users, Total: len(users), Limit: len(users)})
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER_ID\tEMAIL\tROLE")
+	for _, user := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", user.UserId, user.Email, user.Role)
+	}
+	return w.Flush()
+}
+
+// readPassword takes the first line of stdin, so `echo "$PASSWORD" | server admin create` keeps it out of ps
+func (c *adminCLI) readPassword() (string, error) {
+	fmt.Fprint(c.prompt, "Password: ")
+	line, err := bufio.NewReader(c.in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", usageErrorf("admin create: --password is required when stdin has none")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *adminCLI) printUser(user *dto.UserResponse, asJSON bool) error {
+	if asJSON {
+		return c.printJSON(user)
+	}
+	_, err := fmt.Fprintf(c.out, "%s is %s now (%s)\n", user.Email, article(user.Role), user.UserId)
+	return err
+}
+
+func (c *adminCLI) printJSON(v json.Marshaler) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "%s\n", data)
+	return err
+}
+
+func article(role types.Role) string {
+	if role == types.Admin || role == types.Author {
+		return "an " + string(role)
+	}
+	return "a " + string(role)
+}
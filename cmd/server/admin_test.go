@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/pkg/db/postgres"
+)
+
+var userColumns = []string{"user_id", "email", "password_hash", "role"}
+
+func newTestCLI(t *testing.T, stdin string) (*adminCLI, sqlmock.Sqlmock, *bytes.Buffer) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	rep := repository.NewBlogRepository(&postgres.DB{DB: sqlx.NewDb(db, "postgres")})
+	out := &bytes.Buffer{}
+	return &adminCLI{
+		service: service.NewAdminService(rep, nil, nil, nil),
+		in:      strings.NewReader(stdin),
+		out:     out,
+		prompt:  &bytes.Buffer{},
+	}, mock, out
+}
+
+func TestAdminCLI_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no subcommand", nil, "expected create, promote or list-users"},
+		{"unknown subcommand", []string{"delete"}, `unknown subcommand "delete"`},
+		{"unknown flag", []string{"list-users", "--all"}, "admin list-users: flag provided but not defined: -all"},
+		{"create without email", []string{"create", "--password", "change-me-now"}, "--email is required"},
+		{"create without password on stdin", []string{"create", "--email", "admin@example.com"}, "--password is required"},
+		{"promote without email", []string{"promote", "--role", "admin"}, "--email is required"},
+		{"promote to unknown role", []string{"promote", "--email", "a@example.com", "--role", "owner"}, `got "owner"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, mock, _ := newTestCLI(t, "")
+
+			err := cli.run(context.Background(), tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Equal(t, exitUsage, exitCode(err))
+			assert.NoError(t, mock.ExpectationsWereMet(), "nothing reaches the database")
+		})
+	}
+}
+
+func TestAdminCLI_Create(t *testing.T) {
+	userId := uuid.New()
+
+	t.Run("password from stdin", func(t *testing.T) {
+		cli, mock, out := newTestCLI(t, "change-me-now\n")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WithArgs("admin@example.com").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO users`).
+			WithArgs("admin@example.com", sqlmock.AnyArg(), "admin").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "admin@example.com", "hash", "admin"))
+
+		require.NoError(t, cli.run(context.Background(), []string{"create", "--email", "admin@example.com", "--json"}))
+
+		var user dto.UserResponse
+		require.NoError(t, json.Unmarshal(out.Bytes(), &user))
+		assert.Equal(t, dto.UserResponse{UserId: userId, Email: "admin@example.com", Role: "admin"}, user)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("existing user is promoted", func(t *testing.T) {
+		cli, mock, out := newTestCLI(t, "")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WithArgs("author@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "author@example.com", "hash", "author"))
+		mock.ExpectQuery(`UPDATE users SET role = \$2 WHERE user_id = \$1`).WithArgs(userId, "admin").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "author@example.com", "hash", "admin"))
+
+		require.NoError(t, cli.run(context.Background(), []string{"create", "--email", "author@example.com", "--password", "unused-here"}))
+
+		assert.Equal(t, fmt.Sprintf("author@example.com is an admin now (%s)\n", userId), out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("short password", func(t *testing.T) {
+		cli, mock, _ := newTestCLI(t, "")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WillReturnError(sql.ErrNoRows)
+
+		err := cli.run(context.Background(), []string{"create", "--email", "admin@example.com", "--password", "short"})
+		assert.Equal(t, exitUsage, exitCode(err))
+	})
+}
+
+func TestAdminCLI_Promote(t *testing.T) {
+	userId := uuid.New()
+
+	t.Run("role changed", func(t *testing.T) {
+		cli, mock, out := newTestCLI(t, "")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WithArgs("reader@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "reader@example.com", "hash", "reader"))
+		mock.ExpectQuery(`UPDATE users SET role = \$2 WHERE user_id = \$1`).WithArgs(userId, "author").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "reader@example.com", "hash", "author"))
+
+		require.NoError(t, cli.run(context.Background(), []string{"promote", "--email", "reader@example.com"}))
+
+		assert.Equal(t, fmt.Sprintf("reader@example.com is an author now (%s)\n", userId), out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		cli, mock, out := newTestCLI(t, "")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WithArgs("nobody@example.com").WillReturnError(sql.ErrNoRows)
+
+		err := cli.run(context.Background(), []string{"promote", "--email", "nobody@example.com", "--role", "admin", "--json"})
+		assert.Equal(t, exitNotFound, exitCode(err))
+		assert.Empty(t, out.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		cli, mock, _ := newTestCLI(t, "")
+		mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1`).WillReturnError(sql.ErrConnDone)
+
+		err := cli.run(context.Background(), []string{"promote", "--email", "reader@example.com"})
+		assert.Equal(t, exitFailed, exitCode(err))
+	})
+}
+
+func TestAdminCLI_ListUsers(t *testing.T) {
+	cli, mock, out := newTestCLI(t, "")
+	rows := sqlmock.NewRows(userColumns)
+	ids := make([]uuid.UUID, listPageSize+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+		if i < listPageSize {
+			rows.AddRow(ids[i], fmt.Sprintf("user%03d@example.com", i), "hash", "reader")
+		}
+	}
+	// the users come in pages, the second one is read from where the first ended
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(ids)))
+	mock.ExpectQuery(`SELECT \* FROM users ORDER BY email LIMIT \$1 OFFSET \$2`).WithArgs(listPageSize, 0).WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(ids)))
+	mock.ExpectQuery(`SELECT \* FROM users ORDER BY email LIMIT \$1 OFFSET \$2`).WithArgs(listPageSize, listPageSize).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(ids[listPageSize], "zoe@example.com", "hash", "admin"))
+
+	require.NoError(t, cli.run(context.Background(), []string{"list-users", "--json"}))
+
+	var resp dto.GetUsersResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp))
+	assert.Equal(t, len(ids), resp.Total)
+	require.Len(t, resp.Users, len(ids))
+	assert.Equal(t, "zoe@example.com", resp.Users[listPageSize].Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdminCLI_ListUsersTable(t *testing.T) {
+	cli, mock, out := newTestCLI(t, "")
+	userId := uuid.New()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM users ORDER BY email`).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userId, "admin@example.com", "hash", "admin"))
+
+	require.NoError(t, cli.run(context.Background(), []string{"list-users"}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"USER_ID", "EMAIL", "ROLE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{userId.String(), "admin@example.com", "admin"}, strings.Fields(lines[1]))
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, exitCode(flag.ErrHelp))
+	assert.Equal(t, exitUsage, exitCode(usageErrorf("bad")))
+	assert.Equal(t, exitNotFound, exitCode(fmt.Errorf("promote: %w", sql.ErrNoRows)))
+	assert.Equal(t, exitFailed, exitCode(sql.ErrConnDone))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/pkg/storage/minio"
+)
+
+func backupCommand(args []string, env *commandEnv) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := flags.String("output", "", "backup location: a directory or s3://bucket/prefix")
+	incremental := flags.Bool("incremental", false, "export only posts and images changed since the previous run")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *output == "" {
+		return errors.New("backup: --output is required")
+	}
+
+	archive, err := openArchive(*output, env.client)
+	if err != nil {
+		return err
+	}
+
+	backupService := service.NewBackupService(repository.NewBlogRepository(env.db), env.images, archive)
+	summary, err := backupService.Backup(context.Background(), *incremental)
+	if err != nil {
+		return err
+	}
+	return printSummary(summary)
+}
+
+func restoreCommand(args []string, env *commandEnv) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	input := flags.String("input", "", "backup location: a directory or s3://bucket/prefix")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("restore: --input is required")
+	}
+
+	archive, err := openArchive(*input, env.client)
+	if err != nil {
+		return err
+	}
+
+	backupService := service.NewBackupService(repository.NewBlogRepository(env.db), env.images, archive)
+	summary, err := backupService.Restore(context.Background())
+	if err != nil {
+		return err
+	}
+	return printSummary(summary)
+}
+
+func openArchive(location string, client *minio.MinIOClient) (service.BackupArchive, error) {
+	if strings.HasPrefix(location, "s3://") {
+		if client == nil {
+			return nil, errors.New("s3:// backup locations need STORAGE_BACKEND=minio")
+		}
+		return repository.NewS3Archive(client, location)
+	}
+	return repository.NewDirArchive(location)
+}
+
+func printSummary(summary json.Marshaler) error {
+	if _, err := json.MarshalToWriter(summary, os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+
+	"github.com/xkarasb/blog/internal/config"
+	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/storage/minio"
+)
+
+// commandEnv is what main sets up before a command runs
+type commandEnv struct {
+	cfg *config.Config
+	db  *postgres.DB
+	// images is the storage STORAGE_BACKEND picked, client is nil unless that is MinIO
+	images storage.ImageStorage
+	client *minio.MinIOClient
+}
+
+type command func(args []string, env *commandEnv) error
+
+// commands are run instead of the http server when the binary gets a subcommand: `server backup --output ./dump`
+var commands = map[string]command{
+	"backup":       backupCommand,
+	"restore":      restoreCommand,
+	"admin":        adminCommand,
+	"create-admin": createAdminCommand,
+	"seed":         seedCommand,
+}
+
+// exit codes of a failed command, so scripts tell a typo from a missing user from a broken database
+const (
+	exitFailed   = 1
+	exitUsage    = 2
+	exitNotFound = 3
+)
+
+// usageError is a command called with arguments it cannot run with
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// parseFlags turns a bad flag into a usageError, flag.ErrHelp is passed on for -h
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && err != flag.ErrHelp {
+		return &usageError{fmt.Sprintf("%s: %s", flags.Name(), err)}
+	}
+	return err
+}
+
+// exitCode is what the process exits with after a command failed with err
+func exitCode(err error) int {
+	var usage *usageError
+	switch {
+	case err == flag.ErrHelp:
+		return 0
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, sql.ErrNoRows):
+		return exitNotFound
+	default:
+		return exitFailed
+	}
+}
+
+func runCommand(name string, args []string, env *commandEnv) error {
+	cmd, ok := commands[name]
+	if !ok {
+		return usageErrorf("unknown command %q", name)
+	}
+	return cmd(args, env)
+}
@@ -1,26 +1,157 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/xkarasb/blog/internal/config"
+	"github.com/xkarasb/blog/internal/core/repository"
 	"github.com/xkarasb/blog/internal/core/servers"
+	"github.com/xkarasb/blog/pkg/cache"
 	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/logging"
+	"github.com/xkarasb/blog/pkg/mailer"
+	"github.com/xkarasb/blog/pkg/storage"
 	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/storage/s3"
+	"github.com/xkarasb/blog/pkg/tracing"
+	"github.com/xkarasb/blog/pkg/version"
 )
 
 func main() {
-	appCfg, err := config.NewConfig()
+	// -config goes before a subcommand: `server -config dev.yaml backup`
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	configPath := flags.String("config", os.Getenv("CONFIG_PATH"), "YAML file with settings the environment does not set")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	appCfg, err := config.NewConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(os.Stderr, "  -", problem)
+		}
+		os.Exit(1)
+	}
+	logHandler, err := logging.NewHandler(appCfg.LoggingConfig, os.Stderr)
+	if err != nil {
+		panic(err)
+	}
+	slog.SetDefault(slog.New(logHandler))
+	build := version.Get()
+	slog.Info("build", slog.String("version", build.Version), slog.String("commit", build.Commit),
+		slog.String("build_date", build.Date), slog.String("go_version", build.GoVersion))
+	slog.Info("effective configuration", slog.Any("config", *appCfg))
 	db, err := postgres.New(appCfg.PostgresConfig)
-	storage, err := minio.NewMinIOClient(appCfg.MinIOConfig)
+	backend, err := openStorage(appCfg)
 
 	if err != nil {
 		panic(err)
 	}
 
-	serv := servers.NewHttpServer(appCfg.HttpServerConfig, db, storage, appCfg.Docs)
+	if len(args) > 0 {
+		if err := runCommand(args[0], args[1:], &commandEnv{appCfg, db, backend.images, backend.minio}); err != nil {
+			code := exitCode(err)
+			if code != 0 {
+				slog.Error(err.Error())
+			}
+			os.Exit(code)
+		}
+		return
+	}
+
+	tracer, shutdownTracing, err := tracing.New(context.Background(), appCfg.TracingConfig)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("flush spans", slog.String("error", err.Error()))
+		}
+	}()
+
+	sink, err := events.New(appCfg.EventsConfig)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := sink.Close(); err != nil {
+			slog.Error("close event sink", slog.String("error", err.Error()))
+		}
+	}()
+
+	listings := cache.New(appCfg.CacheConfig)
+	defer func() {
+		if err := listings.Close(); err != nil {
+			slog.Error("close cache", slog.String("error", err.Error()))
+		}
+	}()
+
+	serv := servers.NewHttpServer(appCfg.HttpServerConfig, servers.HttpServerOptions{
+		DB:             db,
+		Storage:        backend.minio,
+		S3:             backend.s3,
+		ImageStorage:   backend.images,
+		ImageLinkTTL:   appCfg.PresignTTL,
+		Mailer:         mailer.New(appCfg.MailerConfig),
+		EventSink:      sink,
+		Cache:          listings,
+		Docs:           appCfg.Docs,
+		Settings:       appCfg.Settings(),
+		TracerProvider: tracer,
+	})
+
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+		<-stop
+		slog.Info("Shutting down")
+		if err := serv.Stop(); err != nil {
+			slog.Error(err.Error())
+		}
+	}()
 
 	if err = serv.Start(); err != nil {
 		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// imageBackend is the image storage STORAGE_BACKEND names with the client behind it, the other client is nil
+type imageBackend struct {
+	images storage.ImageStorage
+	minio  *minio.MinIOClient
+	s3     *s3.S3Client
+}
+
+func openStorage(cfg *config.Config) (*imageBackend, error) {
+	switch cfg.Backend {
+	case storage.BackendMinIO:
+		client, err := minio.NewMinIOClient(cfg.MinIOConfig, cfg.Public)
+		if err != nil {
+			return nil, err
+		}
+		return &imageBackend{images: repository.NewMinIORepository(client), minio: client}, nil
+	case storage.BackendS3:
+		client, err := s3.NewS3Client(context.Background(), cfg.S3Config)
+		if err != nil {
+			return nil, err
+		}
+		return &imageBackend{images: repository.NewS3Repository(client, cfg.Public), s3: client}, nil
+	case storage.BackendFS:
+		images, err := repository.NewFSRepository(cfg.Dir, cfg.Public, cfg.HttpServerConfig.Secret)
+		if err != nil {
+			return nil, err
+		}
+		return &imageBackend{images: images}, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q, use %s, %s or %s", cfg.Backend, storage.BackendMinIO, storage.BackendS3, storage.BackendFS)
 	}
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/core/service"
+)
+
+// seedCommand fills a development database: `server seed --authors 3 --posts 5`, only with ALLOW_SEED set
+func seedCommand(args []string, env *commandEnv) error {
+	if !env.cfg.AllowSeed {
+		return usageErrorf("seed: refusing to run without ALLOW_SEED=TRUE, it is meant for development databases")
+	}
+
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	opts := service.SeedOptions{}
+	flags.IntVar(&opts.Authors, "authors", 3, "authors to create")
+	flags.IntVar(&opts.Readers, "readers", 5, "readers to create")
+	flags.IntVar(&opts.PostsPerAuthor, "posts", 5, "posts per author, in turn published, published, draft, scheduled and archived")
+	flags.StringVar(&opts.Password, "password", "seed-password", "password of every seeded user")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if opts.Authors < 0 || opts.Readers < 0 || opts.PostsPerAuthor < 0 {
+		return usageErrorf("seed: --authors, --readers and --posts cannot be negative")
+	}
+
+	seedService := service.NewSeedService(repository.NewBlogRepository(env.db), env.images, nil)
+	summary, err := seedService.Seed(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	return printSummary(summary)
+}
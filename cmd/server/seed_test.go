@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/config"
+)
+
+func TestSeedCommand_NeedsAllowSeed(t *testing.T) {
+	// without ALLOW_SEED nothing is touched, the nil database would panic otherwise
+	err := seedCommand(nil, &commandEnv{cfg: &config.Config{}})
+
+	assert.ErrorContains(t, err, "ALLOW_SEED")
+	assert.Equal(t, exitUsage, exitCode(err))
+}
+
+func TestSeedCommand_NegativeCounts(t *testing.T) {
+	err := seedCommand([]string{"--posts", "-1"}, &commandEnv{cfg: &config.Config{AllowSeed: true}})
+
+	assert.Equal(t, exitUsage, exitCode(err))
+}
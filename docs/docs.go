@@ -15,290 +15,303 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login a user",
-                "consumes": [
-                    "application/json"
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Page through logins, account changes, publishing and deletions, latest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Login",
+                "summary": "Audit log",
                 "parameters": [
                     {
-                        "description": "Login data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UserLoginRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only events of this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "register",
+                            "login",
+                            "login_failed",
+                            "refresh",
+                            "logout",
+                            "password_reset",
+                            "publish_post",
+                            "delete_post",
+                            "delete_image"
+                        ],
+                        "type": "string",
+                        "description": "Only this action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Events to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UserLoginResponse"
+                            "$ref": "#/definitions/AuditLogPage"
                         }
                     },
                     "400": {
-                        "description": "Incorrect body"
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Email or password incorrect"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/auth/refresh-token": {
-            "post": {
-                "description": "Get access token by refresh token",
-                "consumes": [
-                    "application/json"
+        "/admin/cache": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Hits, misses and hit ratio of the user and published posts caches of this instance since it started",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Invoke refresh token",
-                "parameters": [
-                    {
-                        "description": "Refresh token data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/TokenRefreshRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Cache statistics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/TokenRefreshResponse"
+                            "$ref": "#/definitions/CacheStatsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Registrate a new user",
-                "consumes": [
-                    "application/json"
+        "/admin/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Every setting the server runs with by its environment variable, whichever source it came from. Secrets that are set read ***",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Registration",
-                "parameters": [
-                    {
-                        "description": "Registration data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UserRegistrationRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Effective configuration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UserRegistrationResponse"
+                            "$ref": "#/definitions/ConfigResponse"
                         }
                     },
-                    "400": {
-                        "description": "Incorrect email format\\nIncorrect body"
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "User alredy exsist"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/post/{postId}": {
-            "put": {
+        "/admin/jobs": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Edit post",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Runs, errors, skipped overlapping ticks and durations of the background jobs of this instance since it started",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Poster"
-                ],
-                "parameters": [
-                    {
-                        "description": "Edit post data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/EditPostRequest"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Background jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/PostDetails"
+                            "$ref": "#/definitions/JobsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Access denied"
-                    },
-                    "404": {
-                        "description": "Post not found"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/post/{postId}/images": {
+        "/admin/maintenance/cleanup-images": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add Image",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Remove stored images no post refers to and that are older than ORPHAN_MIN_AGE, with dry_run they are only listed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Poster"
+                    "Admin"
                 ],
+                "summary": "Clean up orphaned images",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Image",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Only list the orphans",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/AddImageResonse"
+                            "$ref": "#/definitions/CleanupImages"
                         }
                     },
                     "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Access denied"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Post not found"
+                    "502": {
+                        "description": "Storage timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/post/{postId}/images/{imageId}": {
-            "delete": {
+        "/admin/maintenance/purge-tokens": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add Image",
+                "description": "Delete sessions, password reset and preview tokens that expired more than TOKEN_PURGE_GRACE ago, as the background job does every TOKEN_PURGE_INTERVAL",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Poster"
-                ],
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Image ID",
-                        "name": "imageId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Purge expired tokens",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeleteImageResonse"
+                            "$ref": "#/definitions/PurgeTokensResponse"
                         }
                     },
-                    "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Access denied"
-                    },
-                    "404": {
-                        "description": "Post/Image not found"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/post/{postId}/status": {
+        "/admin/posts/{postId}/status": {
             "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Publish post",
+                "description": "Publish or unpublish any post regardless of its author",
                 "consumes": [
                     "application/json"
                 ],
@@ -306,19 +319,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Poster"
+                    "Admin"
                 ],
-                "summary": "Publicate post",
+                "summary": "Set post status",
                 "parameters": [
-                    {
-                        "description": "Publish post data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UpdatePostStatusRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
@@ -326,6 +330,15 @@ const docTemplate = `{
                         "name": "postId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetPostStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -336,63 +349,99 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Access denied"
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "404": {
-                        "description": "Post not found"
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/posts": {
+        "/admin/reports": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Read all posts",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Posts with reports nobody handled yet, the most reported first, with their reports counted by reason",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reader"
+                    "Admin"
+                ],
+                "summary": "Moderation queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Posts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Read post",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/PostResponse"
-                            }
+                            "$ref": "#/definitions/ReportsPage"
                         }
                     },
                     "400": {
-                        "description": "Incorrect body\\nRefresh token expired or incorrect"
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    "403": {
-                        "description": "Access denied"
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Post not found"
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/reports/{postId}/resolve": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create new post",
+                "description": "Close the open reports of a post, dismiss leaves the post as it is and unpublish takes it back to draft. Both are kept in the audit log.",
                 "consumes": [
                     "application/json"
                 ],
@@ -400,17 +449,253 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Poster"
+                    "Admin"
                 ],
-                "summary": "Create post",
+                "summary": "Resolve reports",
                 "parameters": [
                     {
-                        "description": "Create post data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CreatePostRequest"
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ResolveReportsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ResolveReportsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nIncorrect status",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found\\nNo open reports of the post",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Page through every registered user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Users to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UsersPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{userId}/role": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the role of any user, admins cannot change their own role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Change user role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChangeRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "API keys of the author, newest first, expired ones too; the keys themselves are not shown again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ApiKeys"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied\\nAPI keys cannot be used here",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Make a key for machine clients like a static site generator, sent as ` + "`" + `Authorization: ApiKey \u003ckey\u003e` + "`" + `. Keys read whatever the author can, with the write scope they may change things too. The key is only in this response. Logging in is needed, API keys cannot make keys nor reach the account under /users/me.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "Name, scopes and expiry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateApiKeyRequest"
                         }
                     }
                 ],
@@ -418,210 +703,5045 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/CreatedApiKey"
                         }
                     },
                     "400": {
-                        "description": "Incorrect body"
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nexpires_at must be in the future",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied\\nAPI keys cannot be used here",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an API key, requests with it are refused from now on",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "403": {
-                        "description": "Incorrect user"
+                        "description": "Access denied\\nAPI keys cannot be used here",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    "409": {
-                        "description": "Idempotency key already used"
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Send a password reset link, the answer does not depend on whether the email is registered",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ForgotPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserLoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email or password incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke the refresh token and clear the refresh_token cookie, a token that is no longer valid is ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token data",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/TokenRefreshRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Required when the token comes from the cookie",
+                        "name": "X-Requested-With",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing X-Requested-With header",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Profile of the user the access token belongs to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh-token": {
+            "post": {
+                "description": "Get access token by refresh token. With AUTH_COOKIE_MODE the body may leave the token out, the refresh_token cookie is used then and the X-Requested-With header is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Invoke refresh token",
+                "parameters": [
+                    {
+                        "description": "Refresh token data",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/TokenRefreshRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Required when the token comes from the cookie",
+                        "name": "X-Requested-With",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TokenRefreshResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing X-Requested-With header",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Registrate a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registration",
+                "parameters": [
+                    {
+                        "description": "Registration data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect email format\\nIncorrect body",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User alredy exsist",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password using the emailed token, all refresh tokens are revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ResetPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nReset token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/role": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Switch the current reader to the author role, the answer carries a new access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Become an author",
+                "parameters": [
+                    {
+                        "description": "Requested role, only author",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChangeOwnRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ChangeOwnRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devices the user is logged in on, the one used last first; current marks the session of the access token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Sessions"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log out every device but the one of the access token. An access token issued before sessions has none, then every device is logged out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log out other sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RevokedSessions"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log out one device, its refresh token stops working at once and its access tokens when they expire. Logging out the current session also clears the refresh_token cookie.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log out a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "get": {
+                "description": "Follow the link mailed at registration, authors publish only with a verified email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the mailed link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/VerifyEmailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Verification token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mail another verification link, one per VERIFY_RESEND_INTERVAL counting the one of the registration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend verification",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ResendVerificationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already verified",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Verification mail sent recently, Retry-After tells how long to wait",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Authors with at least one published post and how many they have, most published first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "List authors",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Author"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{authorId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Display name, avatar and number of published posts of an author, an author who has not published yet has 0",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Author profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "authorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Author"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{authorId}/posts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Published posts of one author, an author who has not published yet gets an empty list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Posts of an author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "authorId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the Markdown, without content_html",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/PostResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{authorId}/subscribe": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a notification whenever the author publishes a post, subscribing again changes nothing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Subscribe to author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "authorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscribeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cannot subscribe to yourself",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop the notifications about the posts of an author, an author you do not follow is left as is",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Unsubscribe from author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "authorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscribeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Page through the posts published by authors you follow, latest first, unread=true leaves out those marked read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Notifications to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/NotificationsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark the listed notifications read, a request without body or ids marks all of them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Mark notifications read",
+                "parameters": [
+                    {
+                        "description": "Notifications to mark, at most 100",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/MarkNotificationsReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MarkNotificationsReadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Read all posts, authors get their own posts and everyone else the published ones, liked=true lists the posts you like instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Read post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only posts with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only posts you like, latest like first",
+                        "name": "liked",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "title"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Column to order by, liked posts keep the like order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Direction of the order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "published",
+                            "archived",
+                            "scheduled"
+                        ],
+                        "type": "string",
+                        "description": "Only your posts with this status, authors only",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Content and content_html of the posts, without it they carry only the excerpt",
+                        "name": "full",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the Markdown, without content_html",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/PostResponse"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect\\nIncorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create new post, a retry with the same idempotency key and payload returns the post created before",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Create post",
+                "parameters": [
+                    {
+                        "description": "Create post data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created by an earlier request with this key",
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the post"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Idempotency key already used for another post",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "All your posts that are not in the trash with their images, as one JSON document or with format=zip as a zip archive\nholding a directory per post with index.md, the content behind a YAML front matter, and the files of its images.\nBoth are streamed, an export failing on the way is cut off instead of ending normally.",
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Export posts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or zip",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostsExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add the posts of an export, the JSON document or the zip archive of GET /posts/export, as drafts of yours.\nEach post is added on its own: a malformed one, one with the title and content of a post you have or one\nfailing to be stored is skipped and listed with its error. Images of a zip are uploaded again and their\nlinks in the content point to the new ones, a JSON export carries no image files and keeps its links.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Import posts",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Export as JSON or zip",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ImportPostsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "File must be a posts export, JSON or zip",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload larger than MAX_IMPORT_BYTES",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over title and content of published posts ordered by relevance, mine=true lets an author search their own posts of any status too",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Search posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query, at least 2 characters",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also match own posts of any status",
+                        "name": "mine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Posts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the Markdown, without content_html",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SearchPostsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events, a post.published event with the post as data whenever one is published, and a comment every heartbeat interval.\nThe stream ends when the client falls behind or the server stops, EventSource reconnects by itself.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Stream of published posts",
+                "responses": {
+                    "200": {
+                        "description": "data of every post.published event",
+                        "schema": {
+                            "$ref": "#/definitions/PublishedPostEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Streaming not supported",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/trending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Published posts ranked by a score of their recent views and likes, a like counting as several views and older activity less. Posts nobody viewed or liked lately follow newest first. The ranking is the same for everyone and refreshed about once a minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Trending posts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Posts to return, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Content and content_html of the posts, without it they carry only the excerpt",
+                        "name": "full",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the Markdown, without content_html",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TrendingPostResponse"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Read a published post or one of your own posts, reading someone else's post counts a view once per day",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Read one post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only the Markdown, without content_html",
+                        "name": "raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edit post",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "parameters": [
+                    {
+                        "description": "Edit post data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/EditPostRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when sent again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Post changed since expected_updated_at, the body is the current post",
+                        "schema": {
+                            "$ref": "#/definitions/PostDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a post to the trash, it disappears from every listing and can be restored until purge_at",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Delete post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TrashPostResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edit some fields of a post, the ones left out keep their value",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/PatchPostRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when sent again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/PostDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Post changed since expected_updated_at, the body is the current post",
+                        "schema": {
+                            "$ref": "#/definitions/PostDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Images of a post in their order. Readers see the images of published posts, a draft is found only by its author",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Post images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostImages"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add an image, or several at once under \"images\". A bulk upload answers with a result per file,\n201 when all of them were added and 207 when some were not, each of those with its error.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "image",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Images of a bulk upload",
+                        "name": "images",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/AddImageResonse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the image, not set for a bulk upload"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Bulk upload, some files were not added",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/AddImageResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Post already has IMAGES_PER_POST images",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES, or past STORAGE_QUOTA_BYTES with the quota left",
+                        "schema": {
+                            "$ref": "#/definitions/QuotaExceededResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Image must be jpeg, png, gif or webp",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Storage timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put the images of your post in the order given, in one go. The list names every image of the post exactly once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Reorder images",
+                "parameters": [
+                    {
+                        "description": "Image IDs in their new order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReorderImagesRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostImages"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nimage_ids must list every image of the post exactly once",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images/{imageId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put a new file in place of an image of your post, keeping its id, position and caption. A file of the same type\nkeeps the URL too, public URLs get ?v= with the time of the change so caches fetch the new file.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Replace image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Image"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post/Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES, or past STORAGE_QUOTA_BYTES with the quota left",
+                        "schema": {
+                            "$ref": "#/definitions/QuotaExceededResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Image must be jpeg, png, gif or webp",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Storage timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add Image",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/DeleteImageResonse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nRefresh token expired or incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post/Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Storage timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change caption and position of an image of your post",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Update image",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateImageRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Image"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nCaption longer than 500 characters or position below 1",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post/Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/like": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Like a published post or one of your own posts, liking it again changes nothing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Like post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/LikeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take your like of a post back, a post you do not like is left as is",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Unlike post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/LikeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/preview-token": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Make a link anyone can read your post at without signing in, drafts included, until it expires or is revoked.\nThe token is shown only this once, every call makes another link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Create preview link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PreviewToken"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop every preview link of your post",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Revoke preview links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RevokePreviewTokens"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report a published post as spam or abuse to the admins. You report a post once, reporting it again keeps your first report.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "Report post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReportPostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a post back from the trash with the status it had, past the retention window the post is gone",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Restore post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RestorePostResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Earlier versions of your post, every edit and status change keeps the version it replaced, latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Post revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Revisions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostRevisionsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/revisions/{revisionId}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copy title and content of a revision back into your post, the status and tags stay as they are and the replaced version becomes a new revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Restore revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Revision ID",
+                        "name": "revisionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found\\nRevision not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Views of your post per day, every reader counts once per day",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Post statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostStats"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publish a draft, take a published post back to draft or archive it, archived posts can only return to draft",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Change post status",
+                "parameters": [
+                    {
+                        "description": "Target status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdatePostStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when sent again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/UpdatePostStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed\\nIncorrect status",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied\\nEmail not verified, code email_not_verified",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/authors/{authorId}": {
+            "get": {
+                "description": "Display name, avatar and number of published posts of an author for visitors without an account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public author profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Author ID",
+                        "name": "authorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Author"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    },
+                    "404": {
+                        "description": "Author not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/posts": {
+            "get": {
+                "description": "Published posts for visitors without an account, authors are shown by display name only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only posts with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/PublicPost"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    }
+                }
+            }
+        },
+        "/public/posts/{postId}": {
+            "get": {
+                "description": "One published post for visitors without an account, drafts and other hidden posts are not found",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PublicPost"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since the ETag in If-None-Match"
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/preview/{token}": {
+            "get": {
+                "description": "The post a preview link was made for, whatever its status. Unknown, expired and revoked links are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Preview post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the preview link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PostResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Page through the authors you follow, latest subscription first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "List subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Subscriptions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Distinct tags with the number of published posts using them, most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reader"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Display name, avatar, email and role of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Own profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete your account for good after entering your password again. Your likes, subscriptions,\nnotifications and avatar go with it and you are logged out everywhere. Depending on\nACCOUNT_DELETION_POLICY your posts stay under a \"Deleted user\" or are deleted with their images.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Delete account",
+                "parameters": [
+                    {
+                        "description": "Current password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DeleteAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Password incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the display name others see you by, up to 50 characters. An empty one shows your masked email again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "description": "Profile changes",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the picture shown next to your name, it replaces the one you had. The same types and size limit as images of posts apply.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Upload avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Avatar",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Incorrect body\\nBroken rules, listed in fields with code validation_failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Image must be jpeg, png, gif or webp",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Storage timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Everything the blog keeps about you as one JSON document: your profile without credentials, your likes,\nand your posts that are not in the trash with their images. It is streamed, an export failing on the way\nis cut off instead of ending normally.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Export own data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/AccountExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/quota": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bytes of images you store over all your posts and the most you may, limit_bytes is 0 without a quota.\nDeleting images or purging trashed posts frees their bytes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Poster"
+                ],
+                "summary": "Storage quota",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StorageQuotaResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "AccountExport": {
+            "description": "Everything the blog keeps about the current user, as GET /users/me/export streams it. Credentials are left out.",
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ExportedLike"
+                    }
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ExportedPost"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/UserResponse"
+                }
+            }
+        },
+        "AddImageResonse": {
+            "type": "object",
+            "required": [
+                "image_id",
+                "image_url"
+            ],
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "image_id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "AddImageResult": {
+            "description": "Outcome of one file of a bulk upload, image_id and image_url when it was stored and error when it was not",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "image_id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ApiKey": {
+            "description": "API key of the user, the key itself is only shown once when it is made",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TypeApiKeyScope"
+                    }
+                }
+            }
+        },
+        "ApiKeys": {
+            "description": "API keys of the user, newest first",
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ApiKey"
+                    }
+                }
+            }
+        },
+        "AuditEntry": {
+            "description": "Security relevant event, user_id and target_id are left out when unknown",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/TypeAuditAction"
+                },
+                "audit_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditLogPage": {
+            "description": "Page of the audit log, latest first",
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditEntry"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Author": {
+            "description": "Profile of an author with the number of their published posts",
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Alice"
+                },
+                "posts_count": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "CacheStats": {
+            "description": "Lookups of one cache since the start, hit_ratio is 0 before the first lookup",
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hit_ratio": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CacheStatsResponse": {
+            "description": "Hit ratios of the caches in front of the database",
+            "type": "object",
+            "properties": {
+                "posts": {
+                    "$ref": "#/definitions/CacheStats"
+                },
+                "users": {
+                    "$ref": "#/definitions/CacheStats"
+                }
+            }
+        },
+        "ChangeOwnRoleRequest": {
+            "description": "Request the author role for the current reader",
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "author"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TypeUserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "ChangeOwnRoleResponse": {
+            "description": "New role with an access token issued after the change",
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "role": {
+                    "$ref": "#/definitions/TypeUserRole"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChangeRoleRequest": {
+            "description": "Request to change the role of any user",
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "reader",
+                        "author",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TypeUserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "CleanupImages": {
+            "description": "Outcome of a cleanup of orphaned images, with dry_run the orphans are only listed",
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "orphans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OrphanImage"
+                    }
+                },
+                "scanned": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ConfigResponse": {
+            "description": "Effective settings by their environment variable, secrets replaced by ***",
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CreateApiKeyRequest": {
+            "description": "Name to tell the key apart, scopes beyond read and when it expires, no expires_at for never",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TypeApiKeyScope"
+                    }
+                }
+            }
+        },
+        "CreatePostRequest": {
+            "description": "Request payload for creating a new post",
+            "type": "object",
+            "required": [
+                "content",
+                "idempotency_key",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "description": "Excerpt is shown in listings instead of the content, without one they take the start of the content",
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags are stored lowercase without duplicates",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreatePostResponse": {
+            "description": "The created post with its details, the same as an edit answers",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "indempotency_key": {
+                    "description": "LegacyIdempotencyKey repeats IdempotencyKey under the misspelled name clients read before, deprecated",
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "word_count": {
+                    "description": "WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too",
+                    "type": "integer"
+                }
+            }
+        },
+        "CreatedApiKey": {
+            "description": "New API key, send key as ` + "`" + `Authorization: ApiKey \u003ckey\u003e` + "`" + `; it cannot be shown again",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TypeApiKeyScope"
+                    }
+                }
+            }
+        },
+        "DeleteAccountRequest": {
+            "description": "Deletion of the own account, the password is asked for again",
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeleteImageResonse": {
+            "type": "object",
+            "required": [
+                "image_id"
+            ],
+            "properties": {
+                "image_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "EditPostRequest": {
+            "description": "Request payload for editing a post, tags and excerpt replace the current ones and leaving them out keeps them. An empty excerpt goes back to the one taken from the start of the content. With expected_updated_at set the edit only applies to that version of the post, a newer one answers 409 with the post as it is now.",
+            "type": "object",
+            "required": [
+                "content",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "expected_updated_at": {
+                    "description": "ExpectedUpdatedAt is the updated_at of the post the edit was made on",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "ErrorResponse": {
+            "description": "Error returned by the api",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code names errors clients handle without parsing the message, most errors have none",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields are the fields of the body that broke a rule, only with code validation_failed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                }
+            }
+        },
+        "ExportedLike": {
+            "description": "Post the user likes and when they liked it",
+            "type": "object",
+            "properties": {
+                "liked_at": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExportedPost": {
+            "description": "Post of an export with its Markdown content and its images in their order",
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Image"
+                    }
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "FieldError": {
+            "description": "A field of the body that broke one of its rules",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the json name, inside lists with the index, e.g. tags[2]",
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be a valid email"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "email"
+                }
+            }
+        },
+        "ForgotPasswordRequest": {
+            "description": "Request a password reset link by email",
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "ForgotPasswordResponse": {
+            "description": "Always the same answer whether the email is registered or not",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "Image": {
+            "description": "Image of a post with its place among the images of the post, the time it was added and the time its file was last replaced",
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "image_id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ImportError": {
+            "description": "Post of an import that was skipped, entry is posts[\u003cindex\u003e] of a JSON export or the directory of a zip",
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "ImportPostsResponse": {
+            "description": "Outcome of an import, every post skipped is listed with the reason",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ImportError"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "JobStats": {
+            "description": "Runs of one background job since the start, durations in milliseconds, an interval of 0 disables it",
+            "type": "object",
+            "properties": {
+                "avg_duration_ms": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "interval_ms": {
+                    "type": "integer"
+                },
+                "last_duration_ms": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "runs": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "JobsResponse": {
+            "description": "The background jobs of this instance by name",
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/JobStats"
+                    }
+                }
+            }
+        },
+        "LikeResponse": {
+            "description": "Like state of a post after the request",
+            "type": "object",
+            "properties": {
+                "liked": {
+                    "type": "boolean"
+                },
+                "likes_count": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "MarkNotificationsReadRequest": {
+            "description": "Notifications to mark read, an empty list or no body marks every notification read",
+            "type": "object",
+            "properties": {
+                "notification_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "MarkNotificationsReadResponse": {
+            "description": "Number of notifications that were unread before the request",
+            "type": "object",
+            "properties": {
+                "marked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Notification": {
+            "description": "Notification of a post published by an author you follow, read_at is null until it is marked read",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "notification_id": {
+                    "type": "string"
+                },
+                "post": {
+                    "$ref": "#/definitions/NotificationPost"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/TypeNotificationType"
+                }
+            }
+        },
+        "NotificationPost": {
+            "description": "Post the notification is about",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/UserResponse"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "NotificationsPage": {
+            "description": "Page of your notifications, latest first, with the number of unread ones overall",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Notification"
+                    }
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "OrphanImage": {
+            "description": "Stored object without an images row",
+            "type": "object",
+            "properties": {
+                "last_modified": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "PatchPostRequest": {
+            "description": "Request payload for a partial edit, only the fields present are changed and the others keep their value. An empty title or content is rejected rather than taken for a missing one, an empty excerpt goes back to the one taken from the start of the content. expected_updated_at works as in a full edit.",
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "excerpt": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "expected_updated_at": {
+                    "description": "ExpectedUpdatedAt is the updated_at of the post the edit was made on",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "PostDetails": {
+            "description": "Response with updated post details",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "indempotency_key": {
+                    "description": "LegacyIdempotencyKey repeats IdempotencyKey under the misspelled name clients read before, deprecated",
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "word_count": {
+                    "description": "WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too",
+                    "type": "integer"
+                }
+            }
+        },
+        "PostImages": {
+            "description": "Images of a post in their order",
+            "type": "object",
+            "properties": {
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Image"
+                    }
+                }
+            }
+        },
+        "PostResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/UserResponse"
+                },
+                "content": {
+                    "description": "Content is left out of listings unless they are asked for with ?full=true",
+                    "type": "string"
+                },
+                "content_html": {
+                    "description": "ContentHTML is Content rendered from Markdown and sanitized, left out with ?raw=true",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AddImageResonse"
+                    }
+                },
+                "likes_count": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "description": "WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too",
+                    "type": "integer"
+                }
+            }
+        },
+        "PostRevision": {
+            "description": "Post as it was before an edit made at edited_at by edited_by",
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "edited_by": {
+                    "type": "string"
+                },
+                "revision_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "PostRevisionsPage": {
+            "description": "Page of revisions of a post, latest first",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PostRevision"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PostStats": {
+            "description": "View statistics of a post, every reader counts once per day",
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ViewDay"
+                    }
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PostsExport": {
+            "description": "Every live post of an author with its images, as GET /posts/export streams it",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ExportedPost"
+                    }
+                }
+            }
+        },
+        "PreviewToken": {
+            "description": "Link letting anyone holding it read the post whatever its status, until it expires or is revoked",
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/api/v1/public/preview/Vq3k..."
+                }
+            }
+        },
+        "PublicAuthor": {
+            "description": "Author of a public post, the email is never shown to anonymous visitors",
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "PublicPost": {
+            "description": "Published post as seen without signing in",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/PublicAuthor"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AddImageResonse"
+                    }
+                },
+                "likes_count": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PublishedPostEvent": {
+            "description": "Data of a post.published event of GET /posts/stream, the post has just been published",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "PurgeTokensResponse": {
+            "description": "Tokens one purge removed and what every purge of this instance removed since it started",
+            "type": "object",
+            "properties": {
+                "purged": {
+                    "$ref": "#/definitions/PurgedTokens"
+                },
+                "total": {
+                    "$ref": "#/definitions/PurgedTokens"
+                }
+            }
+        },
+        "PurgedTokens": {
+            "description": "Expired tokens removed, refresh tokens are cleared on the user and the others deleted",
+            "type": "object",
+            "properties": {
+                "idempotency_keys": {
+                    "description": "IdempotencyKeys are the stored responses of requests sent with an Idempotency-Key",
+                    "type": "integer"
+                },
+                "password_resets": {
+                    "type": "integer"
+                },
+                "preview_tokens": {
+                    "type": "integer"
+                },
+                "refresh_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "QuotaExceededResponse": {
+            "description": "Upload refused for the storage quota, with how much of it is left",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "limit_bytes": {
+                    "type": "integer"
+                },
+                "remaining_bytes": {
+                    "type": "integer"
+                },
+                "used_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ReorderImagesRequest": {
+            "description": "Every image of the post once, in the order they are to be shown",
+            "type": "object",
+            "properties": {
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "Report": {
+            "description": "Report of the user as it is kept, a repeated report returns the first one",
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "$ref": "#/definitions/TypeReportReason"
+                },
+                "report_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReportPostRequest": {
+            "description": "Report of a post as spam or abuse, a user reports a post once",
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Links to a scam shop"
+                },
+                "reason": {
+                    "enum": [
+                        "spam",
+                        "abuse",
+                        "copyright",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TypeReportReason"
+                        }
+                    ]
+                }
+            }
+        },
+        "ReportReasons": {
+            "description": "Open reports of a post by reason",
+            "type": "object",
+            "properties": {
+                "abuse": {
+                    "type": "integer"
+                },
+                "copyright": {
+                    "type": "integer"
+                },
+                "other": {
+                    "type": "integer"
+                },
+                "spam": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ReportedPost": {
+            "description": "Post waiting for moderation with its open reports",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "first_reported_at": {
+                    "type": "string"
+                },
+                "last_reported_at": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "$ref": "#/definitions/ReportReasons"
+                },
+                "reports": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReportsPage": {
+            "description": "Page of the moderation queue, most reported posts first",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReportedPost"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ResendVerificationResponse": {
+            "description": "Another verification link is on its way",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ResetPasswordRequest": {
+            "description": "Set a new password with the token from the reset link",
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "ResetPasswordResponse": {
+            "description": "Password has been changed, every session has to log in again",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ResolveReportsRequest": {
+            "description": "Decision on the open reports of a post, unpublish takes it back to draft",
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "dismiss",
+                        "unpublish"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TypeModerationAction"
+                        }
+                    ]
+                }
+            }
+        },
+        "ResolveReportsResponse": {
+            "description": "Reports closed by the decision and the status the post has after it",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/TypeModerationAction"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                }
+            }
+        },
+        "RestorePostResponse": {
+            "description": "Post taken back from the trash",
+            "type": "object",
+            "properties": {
+                "post_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                }
+            }
+        },
+        "RevokePreviewTokens": {
+            "description": "Preview links of the post that stopped working",
+            "type": "object",
+            "properties": {
+                "post_id": {
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "RevokedSessions": {
+            "description": "Number of sessions logged out",
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SearchPostResponse": {
+            "description": "Post matching a search with its relevance, higher is better",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/UserResponse"
+                },
+                "content": {
+                    "description": "Content is left out of listings unless they are asked for with ?full=true",
+                    "type": "string"
+                },
+                "content_html": {
+                    "description": "ContentHTML is Content rendered from Markdown and sanitized, left out with ?raw=true",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AddImageResonse"
+                    }
+                },
+                "likes_count": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "description": "WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too",
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "AddImageResonse": {
+        },
+        "SearchPostsPage": {
+            "description": "Page of search results ordered by relevance",
             "type": "object",
-            "required": [
-                "image_id",
-                "image_url"
-            ],
             "properties": {
-                "image_id": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
+                "offset": {
+                    "type": "integer"
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SearchPostResponse"
+                    }
                 }
             }
         },
-        "CreatePostRequest": {
-            "description": "Request payload for creating a new post",
+        "Session": {
+            "description": "Device the user is logged in on, current is the one the request came from",
             "type": "object",
-            "required": [
-                "content",
-                "idempotency_key",
-                "title"
-            ],
             "properties": {
-                "content": {
+                "created_at": {
                     "type": "string"
                 },
-                "idempotency_key": {
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
                     "type": "string"
                 },
-                "title": {
+                "ip": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "user_agent": {
                     "type": "string"
                 }
             }
         },
-        "CreatePostResponse": {
-            "description": "Response with ID of the created post",
+        "Sessions": {
+            "description": "Sessions of the user, the one used last first",
             "type": "object",
             "properties": {
-                "post_id": {
-                    "type": "string"
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Session"
+                    }
                 }
             }
         },
-        "DeleteImageResonse": {
+        "SetPostStatusRequest": {
+            "description": "Request to set any status of any post",
             "type": "object",
             "required": [
-                "image_id"
+                "status"
             ],
             "properties": {
-                "image_id": {
-                    "type": "string"
+                "status": {
+                    "enum": [
+                        "published",
+                        "draft",
+                        "archived"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TypePostStatus"
+                        }
+                    ]
                 }
             }
         },
-        "EditPostRequest": {
-            "description": "Request payload for editing a post",
+        "StorageQuotaResponse": {
+            "description": "Bytes of images the user has stored over all their posts, limit_bytes is 0 when there is no quota",
             "type": "object",
-            "required": [
-                "content",
-                "title"
-            ],
             "properties": {
-                "content": {
-                    "type": "string"
+                "limit_bytes": {
+                    "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "used_bytes": {
+                    "type": "integer"
                 }
             }
         },
-        "PostDetails": {
-            "description": "Response with updated post details",
+        "SubscribeResponse": {
+            "description": "Subscription state of an author after the request",
             "type": "object",
             "properties": {
                 "author_id": {
                     "type": "string"
                 },
-                "content": {
-                    "type": "string"
+                "subscribed": {
+                    "type": "boolean"
                 },
-                "created_at": {
-                    "type": "string"
+                "subscribers_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Subscription": {
+            "description": "Author you follow and since when",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/UserResponse"
                 },
-                "indempotency_key": {
+                "subscribed_at": {
                     "type": "string"
+                }
+            }
+        },
+        "SubscriptionsPage": {
+            "description": "Page of the authors you follow, latest subscription first",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
                 },
-                "post_id": {
-                    "type": "string"
+                "offset": {
+                    "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/TypePostStatus"
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Subscription"
+                    }
                 },
-                "title": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Tag": {
+            "description": "Tag with the number of published posts using it",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "TokenRefreshRequest": {
+            "description": "Request to refresh access token using refresh token",
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "TokenRefreshResponse": {
+            "description": "Response with new access token",
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string"
                 },
-                "updated_at": {
+                "expires_in": {
+                    "type": "integer"
+                }
+            }
+        },
+        "TrashPostResponse": {
+            "description": "Post moved to the trash, it can be restored until purge_at",
+            "type": "object",
+            "properties": {
+                "post_id": {
+                    "type": "string"
+                },
+                "purge_at": {
                     "type": "string"
                 }
             }
         },
-        "PostResponse": {
+        "TrendingPostResponse": {
+            "description": "Post trending with its score, recent views and likes weighted by their age. Posts without any follow newest first with a score of 0.",
             "type": "object",
             "properties": {
                 "author": {
                     "$ref": "#/definitions/UserResponse"
                 },
                 "content": {
+                    "description": "Content is left out of listings unless they are asked for with ?full=true",
+                    "type": "string"
+                },
+                "content_html": {
+                    "description": "ContentHTML is Content rendered from Markdown and sanitized, left out with ?raw=true",
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "excerpt": {
+                    "type": "string"
+                },
                 "images": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/AddImageResonse"
                     }
                 },
+                "likes_count": {
+                    "type": "integer"
+                },
                 "post_id": {
                     "type": "string"
                 },
+                "publish_at": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
                 "status": {
                     "$ref": "#/definitions/TypePostStatus"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "description": "WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too",
+                    "type": "integer"
                 }
             }
         },
-        "TokenRefreshRequest": {
-            "description": "Request to refresh access token using refresh token",
-            "type": "object",
-            "required": [
-                "refresh_token"
+        "TypeApiKeyScope": {
+            "type": "string",
+            "enum": [
+                "read",
+                "write"
             ],
-            "properties": {
-                "refresh_token": {
-                    "type": "string"
-                }
-            }
+            "x-enum-varnames": [
+                "ScopeRead",
+                "ScopeWrite"
+            ]
         },
-        "TokenRefreshResponse": {
-            "description": "Response with new access token",
-            "type": "object",
-            "properties": {
-                "access_token": {
-                    "type": "string"
-                }
-            }
+        "TypeAuditAction": {
+            "type": "string",
+            "enum": [
+                "register",
+                "login",
+                "login_failed",
+                "refresh",
+                "logout",
+                "password_reset",
+                "verify_email",
+                "publish_post",
+                "delete_post",
+                "delete_image",
+                "unpublish_post",
+                "dismiss_reports",
+                "delete_account",
+                "create_api_key",
+                "revoke_api_key"
+            ],
+            "x-enum-varnames": [
+                "AuditRegister",
+                "AuditLogin",
+                "AuditLoginFailed",
+                "AuditRefresh",
+                "AuditLogout",
+                "AuditPasswordReset",
+                "AuditVerifyEmail",
+                "AuditPublishPost",
+                "AuditDeletePost",
+                "AuditDeleteImage",
+                "AuditUnpublishPost",
+                "AuditDismissReports",
+                "AuditDeleteAccount",
+                "AuditCreateApiKey",
+                "AuditRevokeApiKey"
+            ]
+        },
+        "TypeModerationAction": {
+            "type": "string",
+            "enum": [
+                "dismiss",
+                "unpublish"
+            ],
+            "x-enum-varnames": [
+                "ModerationDismiss",
+                "ModerationUnpublish"
+            ]
+        },
+        "TypeNotificationType": {
+            "type": "string",
+            "enum": [
+                "post.published"
+            ],
+            "x-enum-varnames": [
+                "NotificationPostPublished"
+            ]
         },
         "TypePostStatus": {
             "type": "string",
             "enum": [
                 "draft",
-                "published"
+                "published",
+                "archived",
+                "scheduled"
             ],
             "x-enum-varnames": [
                 "DraftStatus",
-                "PublishedStatus"
+                "PublishedStatus",
+                "ArchivedStatus",
+                "ScheduledStatus"
+            ]
+        },
+        "TypeReportReason": {
+            "type": "string",
+            "enum": [
+                "spam",
+                "abuse",
+                "copyright",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ReportSpam",
+                "ReportAbuse",
+                "ReportCopyright",
+                "ReportOther"
             ]
         },
         "TypeUserRole": {
             "type": "string",
             "enum": [
                 "author",
-                "reader"
+                "reader",
+                "admin"
             ],
             "x-enum-varnames": [
                 "Author",
-                "Reader"
+                "Reader",
+                "Admin"
             ]
         },
+        "UpdateImageRequest": {
+            "description": "Caption and place of an image, the fields left out keep their value. An empty caption removes it, a position moves the image there and the ones after it down, past the last it becomes the last.",
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
         "UpdatePostStatusRequest": {
-            "description": "Request to change post status (publish/unpublish)",
+            "description": "Request to change post status (publish/unpublish/archive/schedule), publish_at is required for scheduled",
             "type": "object",
             "required": [
                 "status"
             ],
             "properties": {
+                "publish_at": {
+                    "type": "string"
+                },
                 "status": {
                     "enum": [
                         "published",
-                        "draft"
+                        "draft",
+                        "archived",
+                        "scheduled"
                     ],
                     "allOf": [
                         {
@@ -631,6 +5751,32 @@ const docTemplate = `{
                 }
             }
         },
+        "UpdatePostStatusResponse": {
+            "description": "Response with ID and resulting status of the post",
+            "type": "object",
+            "properties": {
+                "post_id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/TypePostStatus"
+                }
+            }
+        },
+        "UpdateProfileRequest": {
+            "description": "Changes of the profile of the current user, fields left out stay as they are. An empty display_name goes back to the masked email",
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Alice"
+                }
+            }
+        },
         "UserLoginRequest": {
             "description": "Request payload for user authentication",
             "type": "object",
@@ -655,6 +5801,10 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "expires_in": {
+                    "description": "ExpiresIn is the lifetime of the access token in seconds",
+                    "type": "integer"
+                },
                 "refresh_token": {
                     "type": "string"
                 },
@@ -699,6 +5849,10 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "expires_in": {
+                    "description": "ExpiresIn is the lifetime of the access token in seconds",
+                    "type": "integer"
+                },
                 "refresh_token": {
                     "type": "string"
                 },
@@ -708,20 +5862,78 @@ const docTemplate = `{
             }
         },
         "UserResponse": {
+            "description": "Public user profile, the email is only shown to the user themself and to admins",
             "type": "object",
             "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Alice"
+                },
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "description": "EmailVerified is only shown to the user themself",
+                    "type": "boolean"
+                },
+                "role": {
+                    "$ref": "#/definitions/TypeUserRole"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
+        },
+        "UsersPage": {
+            "description": "Page of registered users",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserResponse"
+                    }
+                }
+            }
+        },
+        "VerifyEmailResponse": {
+            "description": "Email address has been verified, the author can publish",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ViewDay": {
+            "description": "Distinct readers of a post on one day",
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "BearerAuth": {
-            "description": "Enter: Bearer {jwt_token}",
+            "description": "Enter: Bearer {jwt_token}, machine clients may send ApiKey {key} instead",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -1,24 +1,176 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/joho/godotenv"
 	"github.com/xkarasb/blog/internal/core/servers"
+	"github.com/xkarasb/blog/pkg/cache"
 	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/logging"
+	"github.com/xkarasb/blog/pkg/mailer"
+	"github.com/xkarasb/blog/pkg/storage"
 	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/storage/s3"
+	"github.com/xkarasb/blog/pkg/tracing"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	servers.HttpServerConfig
 	postgres.PostgresConfig
 	minio.MinIOConfig
+	s3.S3Config
+	storage.Config
+	mailer.MailerConfig
+	events.EventsConfig
+	cache.CacheConfig
+	tracing.TracingConfig
+	logging.LoggingConfig
+
+	// DevMode accepts a weak SECRET, for development machines only
+	DevMode bool `env:"DEV_MODE" env-default:"FALSE"`
+	// AllowSeed lets `server seed` fill the database with made-up users and posts, never set it in production
+	AllowSeed bool `env:"ALLOW_SEED" env-default:"FALSE"`
 }
 
-func NewConfig() (*Config, error) {
+// NewConfig reads the settings from the environment and .env. A YAML file at path, empty for none,
+// holds the same names as the environment, e.g. `PORT: 8080`, and gives whatever the other two leave unset.
+// Any setting can also be read from a file named by NAME_FILE, e.g. SECRET_FILE for a mounted secret,
+// which wins over NAME itself.
+func NewConfig(path string) (*Config, error) {
 	cfg := Config{}
-	err := cleanenv.ReadConfig(".env", &cfg)
-	if err != nil {
+	names := envNames(reflect.TypeOf(cfg), map[string]string{})
+	if path != "" {
+		if err := readYAML(path, names); err != nil {
+			return nil, err
+		}
+	}
+	// .env overrides the environment, as it always did
+	if err := godotenv.Overload(".env"); err != nil {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+	if err := readFiles(names); err != nil {
+		return nil, err
+	}
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
 	return &cfg, nil
 }
+
+// readYAML exports the values of the file the environment has no variable for, so cleanenv
+// reads them like any other. Names outside names and their _FILE forms are rejected, a typo would go unnoticed otherwise.
+func readYAML(path string, names map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	for name, value := range values {
+		separator, ok := names[name]
+		if _, file := names[strings.TrimSuffix(name, fileSuffix)]; file {
+			ok = true
+		}
+		if !ok {
+			return fmt.Errorf("unknown setting %q in %s", name, path)
+		}
+		if _, set := os.LookupEnv(name); set {
+			continue
+		}
+		env, err := yamlValue(value, separator)
+		if err != nil {
+			return fmt.Errorf("setting %q in %s: %w", name, path, err)
+		}
+		if err := os.Setenv(name, env); err != nil {
+			return fmt.Errorf("set environment: %w", err)
+		}
+	}
+	return nil
+}
+
+// fileSuffix marks a variable holding the path of the value instead of the value itself
+const fileSuffix = "_FILE"
+
+// readFiles exports the content of the file NAME_FILE points to as NAME, without the trailing newline
+// editors and secret mounts tend to add. Every file that cannot be read is reported.
+func readFiles(names map[string]string) error {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var errs []error
+	for _, name := range sorted {
+		path := os.Getenv(name + fileSuffix)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", name, fileSuffix, err))
+			continue
+		}
+		if err := os.Setenv(name, strings.TrimRight(string(data), "\r\n")); err != nil {
+			errs = append(errs, fmt.Errorf("set environment: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// envNames collects the variables of t with the separator of their list values
+func envNames(t reflect.Type, names map[string]string) map[string]string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			envNames(field.Type, names)
+			continue
+		}
+		if name := field.Tag.Get("env"); name != "" {
+			separator := field.Tag.Get("env-separator")
+			if separator == "" {
+				separator = ","
+			}
+			names[name] = separator
+		}
+	}
+	return names
+}
+
+// yamlValue turns a value of the file into its environment form, lists are joined by separator
+func yamlValue(value interface{}, separator string) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			s, err := yamlValue(item, separator)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, separator), nil
+	case map[string]interface{}:
+		return "", errors.New("nested values are not supported")
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAML = `
+PORT: 9090
+GZIP: false
+REQUEST_TIMEOUT: 10s
+AUTOCERT_DOMAINS:
+  - blog.example.com
+  - www.blog.example.com
+POSTGRES_HOST: db.internal
+POSTGRES_MAX_OPEN_CONNS: 50
+MINIO_ENDPOINT: minio.internal:9000
+MINIO_SSL: true
+`
+
+// inDir runs the test in a directory holding an empty .env and the YAML file, it returns the path of the latter.
+// The variables NewConfig exports from the file are unset again once the test ends, DEV_MODE accepts the default SECRET.
+func inDir(t *testing.T, yaml string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), nil, 0o600))
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+	t.Chdir(dir)
+
+	for name := range envNames(reflect.TypeOf(Config{}), map[string]string{}) {
+		for _, name := range []string{name, name + fileSuffix} {
+			if _, set := os.LookupEnv(name); !set {
+				t.Setenv(name, "")
+				require.NoError(t, os.Unsetenv(name))
+			}
+		}
+	}
+	t.Setenv("DEV_MODE", "TRUE")
+	return path
+}
+
+func TestNewConfig_FileOnly(t *testing.T) {
+	path := inDir(t, testYAML)
+
+	cfg, err := NewConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 9090, cfg.HttpServerConfig.Port)
+	assert.False(t, cfg.Gzip)
+	assert.Equal(t, 10*time.Second, cfg.RequestTimeout)
+	assert.Equal(t, []string{"blog.example.com", "www.blog.example.com"}, cfg.AutocertDomains)
+	assert.Equal(t, "db.internal", cfg.PostgresConfig.Host)
+	assert.Equal(t, 50, cfg.MaxOpenConns)
+	assert.Equal(t, "minio.internal:9000", cfg.MinIOConfig.Endpoint)
+	assert.True(t, cfg.UseSSL)
+	// whatever the file leaves out keeps its default
+	assert.Equal(t, "127.0.0.1", cfg.Address)
+	assert.Equal(t, "5432", cfg.PostgresConfig.Port)
+}
+
+func TestNewConfig_EnvOnly(t *testing.T) {
+	inDir(t, testYAML)
+	t.Setenv("PORT", "7070")
+	t.Setenv("POSTGRES_HOST", "postgres")
+
+	cfg, err := NewConfig("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 7070, cfg.HttpServerConfig.Port)
+	assert.Equal(t, "postgres", cfg.PostgresConfig.Host)
+	assert.True(t, cfg.Gzip)
+	assert.Equal(t, 30*time.Second, cfg.RequestTimeout)
+	assert.Equal(t, "localhost:9000", cfg.MinIOConfig.Endpoint)
+}
+
+func TestNewConfig_EnvOverridesFile(t *testing.T) {
+	path := inDir(t, testYAML)
+	t.Setenv("PORT", "7070")
+	t.Setenv("GZIP", "TRUE")
+	t.Setenv("AUTOCERT_DOMAINS", "env.example.com")
+
+	cfg, err := NewConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7070, cfg.HttpServerConfig.Port)
+	assert.True(t, cfg.Gzip)
+	assert.Equal(t, []string{"env.example.com"}, cfg.AutocertDomains)
+	assert.Equal(t, "db.internal", cfg.PostgresConfig.Host)
+	assert.Equal(t, 10*time.Second, cfg.RequestTimeout)
+}
+
+func TestNewConfig_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "unknown setting", yaml: "PROT: 9090\n", want: `unknown setting "PROT"`},
+		{name: "nested", yaml: "POSTGRES_HOST:\n  name: db\n", want: "nested values"},
+		{name: "not yaml", yaml: "PORT: [9090\n", want: "parse config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := inDir(t, tt.yaml)
+
+			_, err := NewConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		inDir(t, "")
+
+		_, err := NewConfig("missing.yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read config file")
+	})
+}
+
+func TestNewConfig_SecretFiles(t *testing.T) {
+	path := inDir(t, "MINIO_SECRET_FILE: minio-secret\n")
+	require.NoError(t, os.WriteFile(".env", []byte("SECRET=from-dotenv\n"), 0o600))
+	require.NoError(t, os.WriteFile("jwt-secret", []byte("from-file\n"), 0o600))
+	require.NoError(t, os.WriteFile("postgres-password", []byte("db-password\r\n"), 0o600))
+	require.NoError(t, os.WriteFile("minio-secret", []byte("minio-password"), 0o600))
+	t.Setenv("SECRET_FILE", "jwt-secret")
+	t.Setenv("POSTGRES_PASSWORD", "from-env")
+	t.Setenv("POSTGRES_PASSWORD_FILE", "postgres-password")
+
+	cfg, err := NewConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-file", cfg.HttpServerConfig.Secret)
+	assert.Equal(t, "db-password", cfg.PostgresConfig.Password)
+	assert.Equal(t, "minio-password", cfg.MinIOConfig.Secret)
+}
+
+func TestNewConfig_SecretFilesMissing(t *testing.T) {
+	inDir(t, "")
+	t.Setenv("SECRET_FILE", "missing-secret")
+	t.Setenv("MINIO_SECRET_FILE", "missing-minio")
+
+	_, err := NewConfig("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SECRET_FILE: open missing-secret")
+	assert.Contains(t, err.Error(), "MINIO_SECRET_FILE: open missing-minio")
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces a secret that is set, an empty one stays empty so a missing secret shows
+const RedactedValue = "***"
+
+// Settings returns every setting by its variable name, in the form the environment takes it. Fields tagged
+// `secret:"true"` are replaced by RedactedValue, a test makes sure no secret field goes without the tag.
+func (cfg *Config) Settings() map[string]string {
+	settings := map[string]string{}
+	collectSettings(reflect.ValueOf(cfg).Elem(), settings)
+	return settings
+}
+
+func collectSettings(v reflect.Value, settings map[string]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectSettings(v.Field(i), settings)
+			continue
+		}
+		name := field.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		value := settingValue(v.Field(i), field.Tag.Get("env-separator"))
+		if field.Tag.Get("secret") == "true" && value != "" {
+			value = RedactedValue
+		}
+		settings[name] = value
+	}
+}
+
+// settingValue formats v like the variable it was read from, lists joined by separator
+func settingValue(v reflect.Value, separator string) string {
+	if v.Kind() != reflect.Slice {
+		return fmt.Sprint(v.Interface())
+	}
+	if separator == "" {
+		separator = ","
+	}
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(items, separator)
+}
+
+// LogValue logs the settings of Settings, sorted by name
+func (cfg Config) LogValue() slog.Value {
+	settings := cfg.Settings()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]slog.Attr, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, slog.String(name, settings[name]))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfig_SecretFieldsTagged fails for a field named like a secret that does not say whether it is one,
+// `secret:"false"` marks the ones that only look like it
+func TestConfig_SecretFieldsTagged(t *testing.T) {
+	var check func(t reflect.Type)
+	check = func(typ reflect.Type) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				check(field.Type)
+				continue
+			}
+			name := strings.ToLower(field.Name + " " + field.Tag.Get("env"))
+			if !strings.Contains(name, "secret") && !strings.Contains(name, "password") {
+				continue
+			}
+			_, tagged := field.Tag.Lookup("secret")
+			assert.True(t, tagged, "%s.%s needs a secret tag", typ.Name(), field.Name)
+		}
+	}
+	check(reflect.TypeOf(Config{}))
+}
+
+func TestConfig_Settings(t *testing.T) {
+	cfg := validConfig()
+	cfg.PostgresConfig.Password = "db-password"
+	cfg.MinIOConfig.AccessKey = "minio-key"
+	cfg.MinIOConfig.Secret = "minio-secret"
+	cfg.AutocertDomains = []string{"blog.example.com", "www.blog.example.com"}
+
+	settings := cfg.Settings()
+
+	assert.Equal(t, RedactedValue, settings["SECRET"])
+	assert.Equal(t, RedactedValue, settings["POSTGRES_PASSWORD"])
+	assert.Equal(t, RedactedValue, settings["MINIO_ACCESSKEY"])
+	assert.Equal(t, RedactedValue, settings["MINIO_SECRET"])
+	assert.Equal(t, "", settings["SECRET_PREVIOUS"], "a secret that is not set shows as such")
+	assert.Equal(t, "8080", settings["PORT"])
+	assert.Equal(t, "localhost:9000", settings["MINIO_ENDPOINT"])
+	assert.Equal(t, "2h0m0s", settings["ACCESS_TTL"])
+	assert.Equal(t, "blog.example.com,www.blog.example.com", settings["AUTOCERT_DOMAINS"])
+	assert.Equal(t, "https://blog.example.com/reset-password", settings["PASSWORD_RESET_URL"])
+	assert.Contains(t, settings, "DEV_MODE")
+	assert.Len(t, settings, len(envNames(reflect.TypeOf(cfg), map[string]string{})))
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := validConfig()
+	cfg.PostgresConfig.Password = "db-password"
+	buf := &bytes.Buffer{}
+
+	slog.New(slog.NewJSONHandler(buf, nil)).Info("effective configuration", slog.Any("config", cfg))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	logged, ok := record["config"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "localhost:9000", logged["MINIO_ENDPOINT"])
+	assert.Equal(t, RedactedValue, logged["POSTGRES_PASSWORD"])
+	assert.NotContains(t, buf.String(), cfg.HttpServerConfig.Secret)
+	assert.NotContains(t, buf.String(), "db-password")
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/logging"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+// minSecretLength is what SECRET needs outside DEV_MODE, shorter HMAC keys can be guessed offline from any token
+const minSecretLength = 32
+
+// Validate checks every setting NewConfig read and returns all problems at once joined into one error,
+// one line each, so a broken deployment is fixed in a single round
+func (cfg *Config) Validate() error {
+	errs := []error{cfg.HttpServerConfig.Validate()}
+	add := func(err error) {
+		errs = append(errs, err)
+	}
+
+	add(checkPort("PORT", cfg.HttpServerConfig.Port))
+	if cfg.TLSRedirect {
+		add(checkPort("TLS_REDIRECT_PORT", cfg.TLSRedirectPort))
+	}
+	if cfg.GRPCPort != 0 {
+		add(checkPort("GRPC_PORT", cfg.GRPCPort))
+		if cfg.GRPCPort == cfg.HttpServerConfig.Port {
+			add(fmt.Errorf("GRPC_PORT and PORT must differ, both are %d", cfg.GRPCPort))
+		}
+	}
+	add(cfg.checkSecret())
+	// the operator token alone opens heap dumps of the process, it has to be as hard to guess as SECRET
+	if cfg.DebugEndpoints && cfg.OperatorToken != "" && !cfg.DevMode && len(cfg.OperatorToken) < minSecretLength {
+		add(fmt.Errorf("OPERATOR_TOKEN must have at least %d characters, e.g. from `openssl rand -hex 32`", minSecretLength))
+	}
+	add(checkURL("PASSWORD_RESET_URL", cfg.PasswordResetURL))
+	add(checkURL("VERIFY_EMAIL_URL", cfg.VerifyEmailURL))
+
+	add(required("POSTGRES_HOST", cfg.PostgresConfig.Host))
+	add(required("POSTGRES_USER", cfg.Username))
+	add(required("POSTGRES_DB", cfg.DbName))
+	if port, err := strconv.Atoi(cfg.PostgresConfig.Port); err != nil {
+		add(fmt.Errorf("POSTGRES_PORT must be a number, got %q", cfg.PostgresConfig.Port))
+	} else {
+		add(checkPort("POSTGRES_PORT", port))
+	}
+
+	add(cfg.checkStorage())
+
+	if cfg.SMTPHost != "" {
+		add(checkPort("SMTP_PORT", cfg.SMTPPort))
+		if _, err := mail.ParseAddress(cfg.From); err != nil {
+			add(fmt.Errorf("SMTP_FROM must be an email address, got %q", cfg.From))
+		}
+	}
+
+	switch cfg.Sink {
+	case events.SinkLog:
+	case events.SinkNATS:
+		// a comma separated list of servers is fine, nats.go splits it
+		add(required("NATS_URL", cfg.NATSURL))
+	default:
+		add(fmt.Errorf("EVENTS_SINK must be %s or %s, got %q", events.SinkLog, events.SinkNATS, cfg.Sink))
+	}
+	if cfg.OutboxBatchSize < 1 {
+		add(fmt.Errorf("OUTBOX_BATCH_SIZE must be at least 1, got %d", cfg.OutboxBatchSize))
+	}
+	if cfg.MaxSessions < 0 {
+		add(fmt.Errorf("MAX_SESSIONS must not be negative, got %d", cfg.MaxSessions))
+	}
+	if cfg.IdempotencyKeyTTL <= 0 {
+		add(fmt.Errorf("IDEMPOTENCY_KEY_TTL must be positive, got %s", cfg.IdempotencyKeyTTL))
+	}
+	if cfg.StorageQuotaBytes < 0 {
+		add(fmt.Errorf("STORAGE_QUOTA_BYTES must not be negative, got %d", cfg.StorageQuotaBytes))
+	}
+	if cfg.TokenPurgeBatchSize < 1 {
+		add(fmt.Errorf("TOKEN_PURGE_BATCH_SIZE must be at least 1, got %d", cfg.TokenPurgeBatchSize))
+	}
+
+	if cfg.PasswordHashMemory < hash.MinMemory {
+		add(fmt.Errorf("PASSWORD_HASH_MEMORY must be at least %d KiB, got %d", hash.MinMemory, cfg.PasswordHashMemory))
+	}
+	if cfg.PasswordHashIterations < hash.MinIterations {
+		add(fmt.Errorf("PASSWORD_HASH_ITERATIONS must be at least %d, got %d", hash.MinIterations, cfg.PasswordHashIterations))
+	}
+	if cfg.PasswordHashParallelism < 1 {
+		add(fmt.Errorf("PASSWORD_HASH_PARALLELISM must be at least 1, got %d", cfg.PasswordHashParallelism))
+	}
+
+	if cfg.TracingConfig.Endpoint != "" {
+		add(checkURL("OTEL_EXPORTER_OTLP_ENDPOINT", cfg.TracingConfig.Endpoint))
+	}
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		add(fmt.Errorf("OTEL_SAMPLING_RATIO must be between 0 and 1, got %v", cfg.SamplingRatio))
+	}
+
+	_, err := logging.NewHandler(cfg.LoggingConfig, io.Discard)
+	add(err)
+
+	return errors.Join(errs...)
+}
+
+func (cfg *Config) checkSecret() error {
+	switch {
+	case cfg.HttpServerConfig.Secret == "":
+		return errors.New("SECRET is required, it signs the access and refresh tokens")
+	case !cfg.DevMode && len(cfg.HttpServerConfig.Secret) < minSecretLength:
+		return fmt.Errorf("SECRET must have at least %d characters, e.g. from `openssl rand -hex 32`, or set DEV_MODE=TRUE on a development machine", minSecretLength)
+	}
+	return nil
+}
+
+// checkStorage checks the settings of the backend STORAGE_BACKEND picks, the others are not used
+func (cfg *Config) checkStorage() error {
+	var errs []error
+	switch cfg.Backend {
+	case storage.BackendMinIO:
+		errs = append(errs, required("MINIO_BUCKET", cfg.BucketName))
+		switch {
+		case cfg.MinIOConfig.Endpoint == "":
+			errs = append(errs, errors.New("MINIO_ENDPOINT is required"))
+		case strings.Contains(cfg.MinIOConfig.Endpoint, "://"):
+			errs = append(errs, fmt.Errorf("MINIO_ENDPOINT must be host:port without a scheme, got %q; set MINIO_SSL=TRUE for https", cfg.MinIOConfig.Endpoint))
+		case strings.Contains(cfg.MinIOConfig.Endpoint, "/"):
+			errs = append(errs, fmt.Errorf("MINIO_ENDPOINT must be host:port without a path, got %q; the bucket goes into MINIO_BUCKET", cfg.MinIOConfig.Endpoint))
+		}
+		if cfg.MaxAttempts < 1 {
+			errs = append(errs, fmt.Errorf("MINIO_MAX_ATTEMPTS must be at least 1, got %d", cfg.MaxAttempts))
+		}
+	case storage.BackendS3:
+		errs = append(errs, required("S3_REGION", cfg.Region), required("S3_BUCKET", cfg.Bucket))
+		if cfg.S3Config.Endpoint != "" {
+			errs = append(errs, checkURL("S3_ENDPOINT", cfg.S3Config.Endpoint))
+		}
+		if cfg.BaseURL != "" {
+			errs = append(errs, checkURL("S3_BASE_URL", cfg.BaseURL))
+		}
+	case storage.BackendFS:
+		errs = append(errs, required("STORAGE_DIR", cfg.Dir))
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE_BACKEND must be %s, %s or %s, got %q", storage.BackendMinIO, storage.BackendS3, storage.BackendFS, cfg.Backend))
+	}
+	if !cfg.Public && cfg.PresignTTL <= 0 {
+		errs = append(errs, fmt.Errorf("MINIO_PRESIGN_TTL must be positive with PUBLIC_BUCKET=FALSE, got %s", cfg.PresignTTL))
+	}
+	return errors.Join(errs...)
+}
+
+func required(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	return nil
+}
+
+func checkPort(name string, port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%s must be between 1 and 65535, got %d", name, port)
+	}
+	return nil
+}
+
+// checkURL accepts absolute http and https URLs
+func checkURL(name, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL, got %q", name, value)
+	}
+	return nil
+}
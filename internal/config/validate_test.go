@@ -0,0 +1,149 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/servers"
+	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/logging"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/storage/s3"
+	"github.com/xkarasb/blog/pkg/tracing"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// validConfig is a production configuration every rule accepts
+func validConfig() Config {
+	return Config{
+		HttpServerConfig: servers.HttpServerConfig{
+			Port:             8080,
+			Secret:           strings.Repeat("s", minSecretLength),
+			AccessTTL:        2 * time.Hour,
+			RefreshTTL:       168 * time.Hour,
+			MaxSessions:      10,
+			TLSRedirectPort:  80,
+			PasswordResetURL: "https://blog.example.com/reset-password",
+			VerifyEmailURL:   "https://blog.example.com/api/auth/verify-email",
+			OutboxBatchSize:  100,
+
+			IdempotencyKeyTTL: 24 * time.Hour,
+
+			AccountDeletionPolicy: types.DeletionAnonymize,
+			TokenPurgeBatchSize:   1000,
+
+			PasswordHashMemory:      65536,
+			PasswordHashIterations:  3,
+			PasswordHashParallelism: 2,
+		},
+		PostgresConfig: postgres.PostgresConfig{Username: "blog", Host: "localhost", Port: "5432", DbName: "blog"},
+		MinIOConfig:    minio.MinIOConfig{Endpoint: "localhost:9000", BucketName: "images", MaxAttempts: 3},
+		S3Config:       s3.S3Config{Region: "us-east-1", Bucket: "images"},
+		Config:         storage.Config{Backend: storage.BackendMinIO, Dir: "./media", Public: true, PresignTTL: 15 * time.Minute},
+		EventsConfig:   events.EventsConfig{Sink: events.SinkLog, NATSURL: "nats://localhost:4222"},
+		TracingConfig:  tracing.TracingConfig{SamplingRatio: 1, ServiceName: "blog"},
+		LoggingConfig:  logging.LoggingConfig{Level: "info", Format: logging.FormatText},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   string
+	}{
+		{"valid", func(cfg *Config) {}, ""},
+		{"port zero", func(cfg *Config) { cfg.HttpServerConfig.Port = 0 }, "PORT must be between 1 and 65535, got 0"},
+		{"port too high", func(cfg *Config) { cfg.HttpServerConfig.Port = 70000 }, "PORT must be between 1 and 65535"},
+		{"redirect port", func(cfg *Config) {
+			cfg.AutocertDomains = []string{"blog.example.com"}
+			cfg.TLSRedirect, cfg.TLSRedirectPort = true, 0
+		}, "TLS_REDIRECT_PORT must be between 1 and 65535"},
+		{"grpc port", func(cfg *Config) { cfg.GRPCPort = 9090 }, ""},
+		{"grpc port too high", func(cfg *Config) { cfg.GRPCPort = 70000 }, "GRPC_PORT must be between 1 and 65535"},
+		{"grpc port taken", func(cfg *Config) { cfg.GRPCPort = 8080 }, "GRPC_PORT and PORT must differ"},
+		{"certificate without key", func(cfg *Config) { cfg.TLSCertFile = "cert.pem" }, "TLS_CERT_FILE and TLS_KEY_FILE must be set together"},
+		{"empty secret", func(cfg *Config) { cfg.HttpServerConfig.Secret = "" }, "SECRET is required"},
+		{"weak secret", func(cfg *Config) { cfg.HttpServerConfig.Secret = "secret" }, "SECRET must have at least 32 characters"},
+		{"weak secret in dev mode", func(cfg *Config) { cfg.HttpServerConfig.Secret, cfg.DevMode = "secret", true }, ""},
+		{"empty secret in dev mode", func(cfg *Config) { cfg.HttpServerConfig.Secret, cfg.DevMode = "", true }, "SECRET is required"},
+		{"weak operator token", func(cfg *Config) { cfg.DebugEndpoints, cfg.OperatorToken = true, "operator" }, "OPERATOR_TOKEN must have at least 32 characters"},
+		{"operator token without debug endpoints", func(cfg *Config) { cfg.OperatorToken = "operator" }, ""},
+		{"debug endpoints for admins only", func(cfg *Config) { cfg.DebugEndpoints = true }, ""},
+		{"reset url without scheme", func(cfg *Config) { cfg.PasswordResetURL = "blog.example.com/reset" }, "PASSWORD_RESET_URL must be an http or https URL"},
+		{"verify url without scheme", func(cfg *Config) { cfg.VerifyEmailURL = "blog.example.com/verify" }, "VERIFY_EMAIL_URL must be an http or https URL"},
+		{"postgres host", func(cfg *Config) { cfg.PostgresConfig.Host = "" }, "POSTGRES_HOST is required"},
+		{"postgres user", func(cfg *Config) { cfg.Username = "" }, "POSTGRES_USER is required"},
+		{"postgres db", func(cfg *Config) { cfg.DbName = " " }, "POSTGRES_DB is required"},
+		{"postgres port not a number", func(cfg *Config) { cfg.PostgresConfig.Port = "postgres" }, `POSTGRES_PORT must be a number, got "postgres"`},
+		{"postgres port out of range", func(cfg *Config) { cfg.PostgresConfig.Port = "0" }, "POSTGRES_PORT must be between 1 and 65535"},
+		{"minio endpoint with scheme", func(cfg *Config) { cfg.MinIOConfig.Endpoint = "http://minio:9000" }, "MINIO_ENDPOINT must be host:port without a scheme"},
+		{"minio endpoint with path", func(cfg *Config) { cfg.MinIOConfig.Endpoint = "minio:9000/images" }, "MINIO_ENDPOINT must be host:port without a path"},
+		{"minio endpoint", func(cfg *Config) { cfg.MinIOConfig.Endpoint = "" }, "MINIO_ENDPOINT is required"},
+		{"minio bucket", func(cfg *Config) { cfg.BucketName = "" }, "MINIO_BUCKET is required"},
+		{"minio attempts", func(cfg *Config) { cfg.MaxAttempts = 0 }, "MINIO_MAX_ATTEMPTS must be at least 1"},
+		{"minio not checked for s3", func(cfg *Config) {
+			cfg.Backend = storage.BackendS3
+			cfg.MinIOConfig.Endpoint = "http://minio:9000"
+		}, ""},
+		{"s3 bucket", func(cfg *Config) { cfg.Backend, cfg.Bucket = storage.BackendS3, "" }, "S3_BUCKET is required"},
+		{"s3 endpoint", func(cfg *Config) { cfg.Backend, cfg.S3Config.Endpoint = storage.BackendS3, "minio:9000" }, "S3_ENDPOINT must be an http or https URL"},
+		{"s3 base url", func(cfg *Config) { cfg.Backend, cfg.BaseURL = storage.BackendS3, "cdn.example.com" }, "S3_BASE_URL must be an http or https URL"},
+		{"fs dir", func(cfg *Config) { cfg.Backend, cfg.Dir = storage.BackendFS, "" }, "STORAGE_DIR is required"},
+		{"unknown backend", func(cfg *Config) { cfg.Backend = "gcs" }, `STORAGE_BACKEND must be minio, s3 or fs, got "gcs"`},
+		{"private without presign ttl", func(cfg *Config) { cfg.Public, cfg.PresignTTL = false, 0 }, "MINIO_PRESIGN_TTL must be positive"},
+		{"smtp port", func(cfg *Config) { cfg.SMTPHost, cfg.SMTPPort, cfg.From = "smtp.example.com", 0, "blog@example.com" }, "SMTP_PORT must be between 1 and 65535"},
+		{"smtp from", func(cfg *Config) { cfg.SMTPHost, cfg.SMTPPort, cfg.From = "smtp.example.com", 587, "blog" }, "SMTP_FROM must be an email address"},
+		{"nats sink", func(cfg *Config) { cfg.Sink = events.SinkNATS }, ""},
+		{"nats url", func(cfg *Config) { cfg.Sink, cfg.NATSURL = events.SinkNATS, "" }, "NATS_URL is required"},
+		{"unknown events sink", func(cfg *Config) { cfg.Sink = "kafka" }, `EVENTS_SINK must be log or nats, got "kafka"`},
+		{"deletion policy", func(cfg *Config) { cfg.AccountDeletionPolicy = "keep" }, `ACCOUNT_DELETION_POLICY must be anonymize or delete, got "keep"`},
+		{"outbox batch size", func(cfg *Config) { cfg.OutboxBatchSize = 0 }, "OUTBOX_BATCH_SIZE must be at least 1"},
+		{"max sessions", func(cfg *Config) { cfg.MaxSessions = -1 }, "MAX_SESSIONS must not be negative"},
+		{"idempotency key ttl", func(cfg *Config) { cfg.IdempotencyKeyTTL = 0 }, "IDEMPOTENCY_KEY_TTL must be positive"},
+		{"storage quota", func(cfg *Config) { cfg.StorageQuotaBytes = -1 }, "STORAGE_QUOTA_BYTES must not be negative"},
+		{"token purge batch size", func(cfg *Config) { cfg.TokenPurgeBatchSize = 0 }, "TOKEN_PURGE_BATCH_SIZE must be at least 1"},
+		{"password hash memory", func(cfg *Config) { cfg.PasswordHashMemory = 4096 }, "PASSWORD_HASH_MEMORY must be at least 19456 KiB, got 4096"},
+		{"password hash iterations", func(cfg *Config) { cfg.PasswordHashIterations = 1 }, "PASSWORD_HASH_ITERATIONS must be at least 2, got 1"},
+		{"password hash parallelism", func(cfg *Config) { cfg.PasswordHashParallelism = 0 }, "PASSWORD_HASH_PARALLELISM must be at least 1"},
+		{"otel endpoint", func(cfg *Config) { cfg.TracingConfig.Endpoint = "collector:4318" }, "OTEL_EXPORTER_OTLP_ENDPOINT must be an http or https URL"},
+		{"otel ratio", func(cfg *Config) { cfg.SamplingRatio = 1.5 }, "OTEL_SAMPLING_RATIO must be between 0 and 1"},
+		{"log level", func(cfg *Config) { cfg.Level = "verbose" }, "LOG_LEVEL"},
+		{"log format", func(cfg *Config) { cfg.Format = "logfmt" }, "LOG_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestConfig_ValidateListsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.HttpServerConfig.Port = 0
+	cfg.HttpServerConfig.Secret = ""
+	cfg.MinIOConfig.Endpoint = "https://minio:9000"
+	cfg.TLSKeyFile = "key.pem"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	problems := strings.Split(err.Error(), "\n")
+	assert.Len(t, problems, 4)
+	for _, want := range []string{"PORT", "SECRET", "MINIO_ENDPOINT", "TLS_CERT_FILE"} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
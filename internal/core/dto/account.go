@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description	Everything the blog keeps about the current user, as GET /users/me/export streams it. Credentials are left out.
+type AccountExport struct {
+	ExportedAt time.Time      `json:"exported_at"`
+	User       UserResponse   `json:"user"`
+	Likes      []ExportedLike `json:"likes"`
+	Posts      []ExportedPost `json:"posts"`
+} //	@name	AccountExport
+
+// @Description	Post the user likes and when they liked it
+type ExportedLike struct {
+	PostId  uuid.UUID `json:"post_id" db:"post_id"`
+	LikedAt time.Time `json:"liked_at" db:"created_at"`
+} //	@name	ExportedLike
+
+// @Description	Deletion of the own account, the password is asked for again
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+} //	@name	DeleteAccountRequest
+
+// DeletedAccountDB is what a deleted account leaves in the storage, its stored avatar_url and the
+// image_url of every image that went with its posts
+//
+//easyjson:skip
+type DeletedAccountDB struct {
+	AvatarUrl *string
+	ImageUrls []string
+}
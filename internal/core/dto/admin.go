@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// @Description	Page of registered users
+type GetUsersResponse struct {
+	Users  []UserResponse `json:"users"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+} //	@name	UsersPage
+
+// @Description	Request to change the role of any user
+type ChangeRoleRequest struct {
+	Role types.Role `json:"role" validate:"required,oneof=reader author admin"`
+} //	@name	ChangeRoleRequest
+
+// @Description	Request to set any status of any post
+type SetPostStatusRequest struct {
+	Status types.PostStatus `json:"status" validate:"required,oneof=published draft archived"`
+} //	@name	SetPostStatusRequest
+
+// @Description	Effective settings by their environment variable, secrets replaced by ***
+type ConfigResponse struct {
+	Settings map[string]string `json:"settings"`
+} //	@name	ConfigResponse
+
+// @Description	Lookups of one cache since the start, hit_ratio is 0 before the first lookup
+type CacheStats struct {
+	Enabled  bool    `json:"enabled"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+} //	@name	CacheStats
+
+// @Description	Hit ratios of the caches in front of the database
+type CacheStatsResponse struct {
+	Users CacheStats `json:"users"`
+	Posts CacheStats `json:"posts"`
+} //	@name	CacheStatsResponse
+
+// @Description	Runs of one background job since the start, durations in milliseconds, an interval of 0 disables it
+type JobStats struct {
+	Name           string     `json:"name"`
+	IntervalMs     int64      `json:"interval_ms"`
+	Runs           uint64     `json:"runs"`
+	Errors         uint64     `json:"errors"`
+	Skipped        uint64     `json:"skipped"`
+	Running        bool       `json:"running"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	AvgDurationMs  int64      `json:"avg_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+} //	@name	JobStats
+
+// @Description	The background jobs of this instance by name
+type JobsResponse struct {
+	Jobs []JobStats `json:"jobs"`
+} //	@name	JobsResponse
+
+// @Description	Expired tokens removed, refresh tokens are cleared on the user and the others deleted
+type PurgedTokens struct {
+	RefreshTokens  int64 `json:"refresh_tokens"`
+	PasswordResets int64 `json:"password_resets"`
+	PreviewTokens  int64 `json:"preview_tokens"`
+	// IdempotencyKeys are the stored responses of requests sent with an Idempotency-Key
+	IdempotencyKeys int64 `json:"idempotency_keys"`
+} //	@name	PurgedTokens
+
+// @Description	Tokens one purge removed and what every purge of this instance removed since it started
+type PurgeTokensResponse struct {
+	Purged PurgedTokens `json:"purged"`
+	Total  PurgedTokens `json:"total"`
+} //	@name	PurgeTokensResponse
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// ApiKeyDB is one row of api_keys, a key a machine client sends instead of an access token. Only the
+// hash of the key is stored. ExpiresAt is nil for keys that do not expire.
+//
+//easyjson:skip
+type ApiKeyDB struct {
+	KeyId      uuid.UUID      `db:"key_id"`
+	UserId     uuid.UUID      `db:"user_id"`
+	Name       string         `db:"name"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedAt  time.Time      `db:"created_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+}
+
+// @Description	Name to tell the key apart, scopes beyond read and when it expires, no expires_at for never
+type CreateApiKeyRequest struct {
+	Name      string        `json:"name" validate:"required,max=100"`
+	Scopes    []types.Scope `json:"scopes" validate:"omitempty,dive,oneof=read write"`
+	ExpiresAt *time.Time    `json:"expires_at"`
+} //	@name	CreateApiKeyRequest
+
+// @Description	API key of the user, the key itself is only shown once when it is made
+type ApiKeyResponse struct {
+	KeyId      uuid.UUID     `json:"key_id"`
+	Name       string        `json:"name"`
+	Scopes     []types.Scope `json:"scopes"`
+	CreatedAt  time.Time     `json:"created_at"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+} //	@name	ApiKey
+
+// @Description	New API key, send key as `Authorization: ApiKey <key>`; it cannot be shown again
+type CreateApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+} //	@name	CreatedApiKey
+
+// @Description	API keys of the user, newest first
+type GetApiKeysResponse struct {
+	ApiKeys []ApiKeyResponse `json:"api_keys"`
+} //	@name	ApiKeys
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// AuditEntryDB is one row of audit_log, UserId is nil when the actor is unknown, e.g. a failed login
+// with an unregistered email
+//
+//easyjson:skip
+type AuditEntryDB struct {
+	AuditId   uuid.UUID         `db:"audit_id"`
+	UserId    *uuid.UUID        `db:"user_id"`
+	Action    types.AuditAction `db:"action"`
+	TargetId  *uuid.UUID        `db:"target_id"`
+	IP        string            `db:"ip"`
+	UserAgent string            `db:"user_agent"`
+	CreatedAt time.Time         `db:"created_at"`
+}
+
+// AuditFilter narrows the audit log, zero values mean any user and action
+//
+//easyjson:skip
+type AuditFilter struct {
+	UserId *uuid.UUID
+	Action types.AuditAction
+}
+
+// @Description	Security relevant event, user_id and target_id are left out when unknown
+type AuditEntryResponse struct {
+	AuditId   uuid.UUID         `json:"audit_id"`
+	UserId    *uuid.UUID        `json:"user_id,omitempty"`
+	Action    types.AuditAction `json:"action"`
+	TargetId  *uuid.UUID        `json:"target_id,omitempty"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	CreatedAt time.Time         `json:"created_at"`
+} //	@name	AuditEntry
+
+// @Description	Page of the audit log, latest first
+type GetAuditLogResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+	Total   int                  `json:"total"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+} //	@name	AuditLogPage
@@ -14,9 +14,11 @@ type RegistrateUserRequest struct {
 
 // @Description	Response with authentication tokens after registration
 type RegistrateUserResponse struct {
-	Id           uuid.UUID `json:"user_id"`
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
+	Id          uuid.UUID `json:"user_id"`
+	AccessToken string    `json:"access_token"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
 } //	@name	UserRegistrationResponse
 
 // @Description	Request payload for user authentication
@@ -27,9 +29,11 @@ type LoginUserRequest struct {
 
 // @Description	Response with authentication tokens after login
 type LoginUserResponse struct {
-	Id           uuid.UUID `json:"user_id"`
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
+	Id          uuid.UUID `json:"user_id"`
+	AccessToken string    `json:"access_token"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
 } //	@name	UserLoginResponse
 
 // @Description	Request to refresh access token using refresh token
@@ -40,4 +44,49 @@ type RefreshRequest struct {
 // @Description	Response with new access token
 type RefreshResponse struct {
 	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
 } //	@name	TokenRefreshResponse
+
+// @Description	Request a password reset link by email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+} //	@name	ForgotPasswordRequest
+
+// @Description	Always the same answer whether the email is registered or not
+type ForgotPasswordResponse struct {
+	Message string `json:"message"`
+} //	@name	ForgotPasswordResponse
+
+// @Description	Set a new password with the token from the reset link
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+} //	@name	ResetPasswordRequest
+
+// @Description	Password has been changed, every session has to log in again
+type ResetPasswordResponse struct {
+	Message string `json:"message"`
+} //	@name	ResetPasswordResponse
+
+// @Description	Email address has been verified, the author can publish
+type VerifyEmailResponse struct {
+	Message string `json:"message"`
+} //	@name	VerifyEmailResponse
+
+// @Description	Another verification link is on its way
+type ResendVerificationResponse struct {
+	Message string `json:"message"`
+} //	@name	ResendVerificationResponse
+
+// @Description	Request the author role for the current reader
+type ChangeOwnRoleRequest struct {
+	Role types.Role `json:"role" validate:"required,oneof=author"`
+} //	@name	ChangeOwnRoleRequest
+
+// @Description	New role with an access token issued after the change
+type ChangeOwnRoleResponse struct {
+	Id          uuid.UUID  `json:"user_id"`
+	Role        types.Role `json:"role"`
+	AccessToken string     `json:"access_token"`
+	ExpiresIn   int64      `json:"expires_in"`
+} //	@name	ChangeOwnRoleResponse
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/google/uuid"
+
+// AuthorDB is an author with the number of their published posts
+//
+//easyjson:skip
+type AuthorDB struct {
+	UserId      uuid.UUID `db:"user_id"`
+	Email       string    `db:"email"`
+	DisplayName *string   `db:"display_name"`
+	AvatarUrl   *string   `db:"avatar_url"`
+	PostsCount  int       `db:"posts_count"`
+}
+
+// @Description	Profile of an author with the number of their published posts
+type AuthorResponse struct {
+	UserId      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name" example:"Alice"`
+	AvatarUrl   string    `json:"avatar_url,omitempty"`
+	PostsCount  int       `json:"posts_count"`
+} //	@name	Author
+
+//easyjson:json
+type AuthorsResponse []*AuthorResponse
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// UserRecord is a users row as it is written into a backup. Sessions and API keys are not backed up,
+// users of a restored database log in again and make new keys; the refresh tokens older backups carry are ignored.
+type UserRecord struct {
+	UserId       uuid.UUID  `json:"user_id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"password_hash"`
+	Role         types.Role `json:"role"`
+	DisplayName  *string    `json:"display_name,omitempty"`
+	AvatarUrl    *string    `json:"avatar_url,omitempty"`
+	// EmailVerified is nil in backups from before email verification, their users count as verified
+	EmailVerified *bool `json:"email_verified,omitempty"`
+}
+
+// PostRecord is a posts row as it is written into a backup
+type PostRecord struct {
+	PostId         uuid.UUID        `json:"post_id"`
+	AuthorId       uuid.UUID        `json:"author_id"`
+	IdempotencyKey string           `json:"idempotency_key"`
+	Title          string           `json:"title"`
+	Content        string           `json:"content"`
+	Excerpt        *string          `json:"excerpt,omitempty"`
+	Status         types.PostStatus `json:"status"`
+	PublishAt      *time.Time       `json:"publish_at,omitempty"`
+	DeletedAt      *time.Time       `json:"deleted_at,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	ImportHash     *string          `json:"import_hash,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// ImageRecord is an images row as it is written into a backup
+type ImageRecord struct {
+	ImageId     uuid.UUID `json:"image_id"`
+	PostId      uuid.UUID `json:"post_id"`
+	ImageUrl    string    `json:"image_url"`
+	ObjectName  string    `json:"object_name"`
+	ContentType string    `json:"content_type"`
+	Position    int       `json:"position,omitempty"`
+	Caption     *string   `json:"caption,omitempty"`
+	SizeBytes   int64     `json:"size_bytes,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BackupRun describes one backup written into the archive
+type BackupRun struct {
+	Id             string    `json:"id"`
+	Incremental    bool      `json:"incremental"`
+	StartedAt      time.Time `json:"started_at"`
+	PostsWatermark time.Time `json:"posts_watermark"`
+	ImageWatermark time.Time `json:"images_watermark"`
+	Users          int       `json:"users"`
+	Posts          int       `json:"posts"`
+	Images         int       `json:"images"`
+	Objects        int       `json:"objects"`
+	Bytes          int64     `json:"bytes"`
+}
+
+// BackupManifest lists every run stored in an archive, oldest first
+type BackupManifest struct {
+	Version int         `json:"version"`
+	Runs    []BackupRun `json:"runs"`
+}
+
+// BackupSummary is reported after a backup or restore
+type BackupSummary struct {
+	Run     string `json:"run,omitempty"`
+	Users   int    `json:"users"`
+	Posts   int    `json:"posts"`
+	Images  int    `json:"images"`
+	Objects int    `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
@@ -4,11 +4,12 @@ package dto
 
 import (
 	json "encoding/json"
-
+	uuid "github.com/google/uuid"
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
 	types "github.com/xkarasb/blog/pkg/types"
+	time "time"
 )
 
 // suppress unused package warning
@@ -19,7 +20,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(in *jlexer.Lexer, out *UserResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(in *jlexer.Lexer, out *ViewDayResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -33,19 +34,17 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(in *jlexer.Lexer,
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "user_id":
+		case "day":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.UserId).UnmarshalText(data))
-				}
+				out.Day = string(in.String())
 			}
-		case "email":
+		case "views":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Email = string(in.String())
+				out.Views = int(in.Int())
 			}
 		default:
 			in.SkipRecursive()
@@ -57,47 +56,47 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto(out *jwriter.Writer, in UserResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto(out *jwriter.Writer, in ViewDayResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"user_id\":"
+		const prefix string = ",\"day\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.UserId).MarshalText())
+		out.String(string(in.Day))
 	}
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"views\":"
 		out.RawString(prefix)
-		out.String(string(in.Email))
+		out.Int(int(in.Views))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserResponse) MarshalJSON() ([]byte, error) {
+func (v ViewDayResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v ViewDayResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserResponse) UnmarshalJSON(data []byte) error {
+func (v *ViewDayResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *ViewDayResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(in *jlexer.Lexer, out *RegistrateUserResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(in *jlexer.Lexer, out *VersionResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -111,25 +110,29 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "user_id":
+		case "version":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.Id).UnmarshalText(data))
-				}
+				out.Version = string(in.String())
 			}
-		case "access_token":
+		case "commit":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.AccessToken = string(in.String())
+				out.Commit = string(in.String())
 			}
-		case "refresh_token":
+		case "build_date":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.RefreshToken = string(in.String())
+				out.BuildDate = string(in.String())
+			}
+		case "go_version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.GoVersion = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -141,52 +144,57 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto1(out *jwriter.Writer, in RegistrateUserResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto1(out *jwriter.Writer, in VersionResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"user_id\":"
+		const prefix string = ",\"version\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.Id).MarshalText())
+		out.String(string(in.Version))
 	}
 	{
-		const prefix string = ",\"access_token\":"
+		const prefix string = ",\"commit\":"
 		out.RawString(prefix)
-		out.String(string(in.AccessToken))
+		out.String(string(in.Commit))
 	}
 	{
-		const prefix string = ",\"refresh_token\":"
+		const prefix string = ",\"build_date\":"
 		out.RawString(prefix)
-		out.String(string(in.RefreshToken))
+		out.String(string(in.BuildDate))
+	}
+	{
+		const prefix string = ",\"go_version\":"
+		out.RawString(prefix)
+		out.String(string(in.GoVersion))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RegistrateUserResponse) MarshalJSON() ([]byte, error) {
+func (v VersionResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RegistrateUserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VersionResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RegistrateUserResponse) UnmarshalJSON(data []byte) error {
+func (v *VersionResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RegistrateUserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VersionResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto1(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(in *jlexer.Lexer, out *RegistrateUserRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(in *jlexer.Lexer, out *VerifyEmailResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -200,23 +208,11 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "email":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Email = string(in.String())
-			}
-		case "password":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Password = string(in.String())
-			}
-		case "role":
+		case "message":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Role = types.Role(in.String())
+				out.Message = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -228,52 +224,42 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto2(out *jwriter.Writer, in RegistrateUserRequest) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto2(out *jwriter.Writer, in VerifyEmailResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"message\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
-	}
-	{
-		const prefix string = ",\"password\":"
-		out.RawString(prefix)
-		out.String(string(in.Password))
-	}
-	{
-		const prefix string = ",\"role\":"
-		out.RawString(prefix)
-		out.String(string(in.Role))
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RegistrateUserRequest) MarshalJSON() ([]byte, error) {
+func (v VerifyEmailResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RegistrateUserRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VerifyEmailResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RegistrateUserRequest) UnmarshalJSON(data []byte) error {
+func (v *VerifyEmailResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RegistrateUserRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VerifyEmailResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto2(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(in *jlexer.Lexer, out *RefreshResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(in *jlexer.Lexer, out *UserResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -287,11 +273,43 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "access_token":
+		case "user_id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.AccessToken = string(in.String())
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.UserId).UnmarshalText(data))
+				}
+			}
+		case "display_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DisplayName = string(in.String())
+			}
+		case "avatar_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvatarUrl = string(in.String())
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		case "email_verified":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.EmailVerified = bool(in.Bool())
 			}
 		default:
 			in.SkipRecursive()
@@ -303,42 +321,67 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto3(out *jwriter.Writer, in RefreshResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto3(out *jwriter.Writer, in UserResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"access_token\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.AccessToken))
+		out.RawText((in.UserId).MarshalText())
+	}
+	{
+		const prefix string = ",\"display_name\":"
+		out.RawString(prefix)
+		out.String(string(in.DisplayName))
+	}
+	if in.AvatarUrl != "" {
+		const prefix string = ",\"avatar_url\":"
+		out.RawString(prefix)
+		out.String(string(in.AvatarUrl))
+	}
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	if in.Role != "" {
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	if in.EmailVerified {
+		const prefix string = ",\"email_verified\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.EmailVerified))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RefreshResponse) MarshalJSON() ([]byte, error) {
+func (v UserResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RefreshResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RefreshResponse) UnmarshalJSON(data []byte) error {
+func (v *UserResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RefreshResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto3(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(in *jlexer.Lexer, out *RefreshRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(in *jlexer.Lexer, out *UserRecord) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -352,11 +395,73 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "refresh_token":
+		case "user_id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.RefreshToken = string(in.String())
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.UserId).UnmarshalText(data))
+				}
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "password_hash":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PasswordHash = string(in.String())
+			}
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		case "display_name":
+			if in.IsNull() {
+				in.Skip()
+				out.DisplayName = nil
+			} else {
+				if out.DisplayName == nil {
+					out.DisplayName = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.DisplayName = string(in.String())
+				}
+			}
+		case "avatar_url":
+			if in.IsNull() {
+				in.Skip()
+				out.AvatarUrl = nil
+			} else {
+				if out.AvatarUrl == nil {
+					out.AvatarUrl = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.AvatarUrl = string(in.String())
+				}
+			}
+		case "email_verified":
+			if in.IsNull() {
+				in.Skip()
+				out.EmailVerified = nil
+			} else {
+				if out.EmailVerified == nil {
+					out.EmailVerified = new(bool)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.EmailVerified = bool(in.Bool())
+				}
 			}
 		default:
 			in.SkipRecursive()
@@ -368,42 +473,72 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto4(out *jwriter.Writer, in RefreshRequest) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto4(out *jwriter.Writer, in UserRecord) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"refresh_token\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.RefreshToken))
+		out.RawText((in.UserId).MarshalText())
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password_hash\":"
+		out.RawString(prefix)
+		out.String(string(in.PasswordHash))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	if in.DisplayName != nil {
+		const prefix string = ",\"display_name\":"
+		out.RawString(prefix)
+		out.String(string(*in.DisplayName))
+	}
+	if in.AvatarUrl != nil {
+		const prefix string = ",\"avatar_url\":"
+		out.RawString(prefix)
+		out.String(string(*in.AvatarUrl))
+	}
+	if in.EmailVerified != nil {
+		const prefix string = ",\"email_verified\":"
+		out.RawString(prefix)
+		out.Bool(bool(*in.EmailVerified))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RefreshRequest) MarshalJSON() ([]byte, error) {
+func (v UserRecord) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RefreshRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserRecord) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RefreshRequest) UnmarshalJSON(data []byte) error {
+func (v *UserRecord) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RefreshRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserRecord) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto4(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(in *jlexer.Lexer, out *PublishPostResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(in *jlexer.Lexer, out *UpdateProfileRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -417,12 +552,18 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "post_id":
+		case "display_name":
 			if in.IsNull() {
 				in.Skip()
+				out.DisplayName = nil
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.PostId).UnmarshalText(data))
+				if out.DisplayName == nil {
+					out.DisplayName = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.DisplayName = string(in.String())
 				}
 			}
 		default:
@@ -435,42 +576,43 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto5(out *jwriter.Writer, in PublishPostResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto5(out *jwriter.Writer, in UpdateProfileRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"post_id\":"
+	if in.DisplayName != nil {
+		const prefix string = ",\"display_name\":"
+		first = false
 		out.RawString(prefix[1:])
-		out.RawText((in.PostId).MarshalText())
+		out.String(string(*in.DisplayName))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PublishPostResponse) MarshalJSON() ([]byte, error) {
+func (v UpdateProfileRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PublishPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UpdateProfileRequest) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PublishPostResponse) UnmarshalJSON(data []byte) error {
+func (v *UpdateProfileRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PublishPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UpdateProfileRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto5(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(in *jlexer.Lexer, out *PublishPostRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(in *jlexer.Lexer, out *UpdateImageRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -484,11 +626,33 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "status":
+		case "caption":
 			if in.IsNull() {
 				in.Skip()
+				out.Caption = nil
 			} else {
-				out.Status = types.PostStatus(in.String())
+				if out.Caption == nil {
+					out.Caption = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Caption = string(in.String())
+				}
+			}
+		case "position":
+			if in.IsNull() {
+				in.Skip()
+				out.Position = nil
+			} else {
+				if out.Position == nil {
+					out.Position = new(int)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Position = int(in.Int())
+				}
 			}
 		default:
 			in.SkipRecursive()
@@ -500,74 +664,11162 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto6(out *jwriter.Writer, in PublishPostRequest) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto6(out *jwriter.Writer, in UpdateImageRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"status\":"
+		const prefix string = ",\"caption\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Status))
+		if in.Caption == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.Caption))
+		}
+	}
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		if in.Position == nil {
+			out.RawString("null")
+		} else {
+			out.Int(int(*in.Position))
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PublishPostRequest) MarshalJSON() ([]byte, error) {
+func (v UpdateImageRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PublishPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UpdateImageRequest) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PublishPostRequest) UnmarshalJSON(data []byte) error {
+func (v *UpdateImageRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PublishPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UpdateImageRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto6(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(in *jlexer.Lexer, out *LoginUserResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(in *jlexer.Lexer, out *TrendingPostsResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
 		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		switch key {
-		case "user_id":
-			if in.IsNull() {
-				in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(TrendingPostsResponse, 0, 0)
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.Id).UnmarshalText(data))
-				}
+				*out = TrendingPostsResponse{}
 			}
-		case "access_token":
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v1 TrendingPostResponse
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.AccessToken = string(in.String())
+				(v1).UnmarshalEasyJSON(in)
+			}
+			*out = append(*out, v1)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(out *jwriter.Writer, in TrendingPostsResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v2, v3 := range in {
+			if v2 > 0 {
+				out.RawByte(',')
+			}
+			(v3).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TrendingPostsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrendingPostsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TrendingPostsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrendingPostsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(in *jlexer.Lexer, out *TrendingPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "score":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Score = float64(in.Float64())
+			}
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "content_html":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ContentHTML = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]AddImageResponse, 0, 1)
+					} else {
+						out.Images = []AddImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 AddImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v4).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v5 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "views":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Views = int(in.Int())
+			}
+		case "likes_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikesCount = int(in.Int())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "word_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.WordCount = int(in.Int())
+			}
+		case "reading_time_minutes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ReadingTimeMinutes = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(out *jwriter.Writer, in TrendingPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"score\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.Score))
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(in.Author).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.ContentHTML != "" {
+		const prefix string = ",\"content_html\":"
+		out.RawString(prefix)
+		out.String(string(in.ContentHTML))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range in.Images {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				(v7).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Tags {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v9))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"views\":"
+		out.RawString(prefix)
+		out.Int(int(in.Views))
+	}
+	{
+		const prefix string = ",\"likes_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikesCount))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"word_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.WordCount))
+	}
+	{
+		const prefix string = ",\"reading_time_minutes\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReadingTimeMinutes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TrendingPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrendingPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TrendingPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrendingPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(in *jlexer.Lexer, out *TrashPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "purge_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.PurgeAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(out *jwriter.Writer, in TrashPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"purge_at\":"
+		out.RawString(prefix)
+		out.Raw((in.PurgeAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TrashPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrashPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TrashPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrashPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(in *jlexer.Lexer, out *TagsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(TagsResponse, 0, 8)
+			} else {
+				*out = TagsResponse{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v10 *TagResponse
+			if in.IsNull() {
+				in.Skip()
+				v10 = nil
+			} else {
+				if v10 == nil {
+					v10 = new(TagResponse)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*v10).UnmarshalEasyJSON(in)
+				}
+			}
+			*out = append(*out, v10)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(out *jwriter.Writer, in TagsResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v11, v12 := range in {
+			if v11 > 0 {
+				out.RawByte(',')
+			}
+			if v12 == nil {
+				out.RawString("null")
+			} else {
+				(*v12).MarshalEasyJSON(out)
+			}
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(in *jlexer.Lexer, out *TagResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Count = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(out *jwriter.Writer, in TagResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(in *jlexer.Lexer, out *SubscriptionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "subscriptions":
+			if in.IsNull() {
+				in.Skip()
+				out.Subscriptions = nil
+			} else {
+				in.Delim('[')
+				if out.Subscriptions == nil {
+					if !in.IsDelim(']') {
+						out.Subscriptions = make([]SubscriptionResponse, 0, 0)
+					} else {
+						out.Subscriptions = []SubscriptionResponse{}
+					}
+				} else {
+					out.Subscriptions = (out.Subscriptions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 SubscriptionResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v13).UnmarshalEasyJSON(in)
+					}
+					out.Subscriptions = append(out.Subscriptions, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(out *jwriter.Writer, in SubscriptionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"subscriptions\":"
+		out.RawString(prefix[1:])
+		if in.Subscriptions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Subscriptions {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				(v15).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SubscriptionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SubscriptionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SubscriptionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SubscriptionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(in *jlexer.Lexer, out *SubscriptionResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		case "subscribed_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.SubscribedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(out *jwriter.Writer, in SubscriptionResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix[1:])
+		(in.Author).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"subscribed_at\":"
+		out.RawString(prefix)
+		out.Raw((in.SubscribedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SubscriptionResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SubscriptionResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SubscriptionResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SubscriptionResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(in *jlexer.Lexer, out *SubscribeResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "subscribed":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Subscribed = bool(in.Bool())
+			}
+		case "subscribers_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SubscribersCount = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(out *jwriter.Writer, in SubscribeResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"subscribed\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Subscribed))
+	}
+	{
+		const prefix string = ",\"subscribers_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.SubscribersCount))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SubscribeResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SubscribeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SubscribeResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SubscribeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(in *jlexer.Lexer, out *StorageQuotaResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "used_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UsedBytes = int64(in.Int64())
+			}
+		case "limit_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LimitBytes = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(out *jwriter.Writer, in StorageQuotaResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"used_bytes\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.UsedBytes))
+	}
+	{
+		const prefix string = ",\"limit_bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LimitBytes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StorageQuotaResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StorageQuotaResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StorageQuotaResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StorageQuotaResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto16(in *jlexer.Lexer, out *SetPostStatusRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto16(out *jwriter.Writer, in SetPostStatusRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SetPostStatusRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SetPostStatusRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SetPostStatusRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SetPostStatusRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto16(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto17(in *jlexer.Lexer, out *SessionResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "session_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.SessionId).UnmarshalText(data))
+				}
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		case "last_used_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastUsedAt).UnmarshalJSON(data))
+				}
+			}
+		case "current":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Current = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto17(out *jwriter.Writer, in SessionResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"session_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.SessionId).MarshalText())
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_used_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LastUsedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SessionResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SessionResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SessionResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SessionResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto17(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto18(in *jlexer.Lexer, out *SeedSummary) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "password":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Password = string(in.String())
+			}
+		case "users_created":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UsersCreated = int(in.Int())
+			}
+		case "users_existing":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UsersExisting = int(in.Int())
+			}
+		case "posts_created":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PostsCreated = int(in.Int())
+			}
+		case "posts_existing":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PostsExisting = int(in.Int())
+			}
+		case "images_created":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImagesCreated = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto18(out *jwriter.Writer, in SeedSummary) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"users_created\":"
+		out.RawString(prefix)
+		out.Int(int(in.UsersCreated))
+	}
+	{
+		const prefix string = ",\"users_existing\":"
+		out.RawString(prefix)
+		out.Int(int(in.UsersExisting))
+	}
+	{
+		const prefix string = ",\"posts_created\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostsCreated))
+	}
+	{
+		const prefix string = ",\"posts_existing\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostsExisting))
+	}
+	{
+		const prefix string = ",\"images_created\":"
+		out.RawString(prefix)
+		out.Int(int(in.ImagesCreated))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SeedSummary) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SeedSummary) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SeedSummary) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SeedSummary) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto18(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto19(in *jlexer.Lexer, out *SearchPostsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]SearchPostResponse, 0, 0)
+					} else {
+						out.Posts = []SearchPostResponse{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 SearchPostResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v16).UnmarshalEasyJSON(in)
+					}
+					out.Posts = append(out.Posts, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto19(out *jwriter.Writer, in SearchPostsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix[1:])
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Posts {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				(v18).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchPostsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchPostsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchPostsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchPostsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto19(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto20(in *jlexer.Lexer, out *SearchPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "score":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Score = float64(in.Float64())
+			}
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "content_html":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ContentHTML = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]AddImageResponse, 0, 1)
+					} else {
+						out.Images = []AddImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 AddImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v19).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v20 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v20 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v20)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "views":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Views = int(in.Int())
+			}
+		case "likes_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikesCount = int(in.Int())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "word_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.WordCount = int(in.Int())
+			}
+		case "reading_time_minutes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ReadingTimeMinutes = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto20(out *jwriter.Writer, in SearchPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"score\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.Score))
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(in.Author).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.ContentHTML != "" {
+		const prefix string = ",\"content_html\":"
+		out.RawString(prefix)
+		out.String(string(in.ContentHTML))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v21, v22 := range in.Images {
+				if v21 > 0 {
+					out.RawByte(',')
+				}
+				(v22).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Tags {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"views\":"
+		out.RawString(prefix)
+		out.Int(int(in.Views))
+	}
+	{
+		const prefix string = ",\"likes_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikesCount))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"word_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.WordCount))
+	}
+	{
+		const prefix string = ",\"reading_time_minutes\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReadingTimeMinutes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto20(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto21(in *jlexer.Lexer, out *RevokeSessionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "revoked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Revoked = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto21(out *jwriter.Writer, in RevokeSessionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Revoked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokeSessionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokeSessionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokeSessionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokeSessionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto21(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto22(in *jlexer.Lexer, out *RevokePreviewTokensResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "revoked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Revoked = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto22(out *jwriter.Writer, in RevokePreviewTokensResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix)
+		out.Int(int(in.Revoked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokePreviewTokensResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokePreviewTokensResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokePreviewTokensResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokePreviewTokensResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto22(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto23(in *jlexer.Lexer, out *RestorePostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto23(out *jwriter.Writer, in RestorePostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RestorePostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RestorePostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RestorePostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RestorePostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto23(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto24(in *jlexer.Lexer, out *ResolveReportsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "action":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Action = types.ModerationAction(in.String())
+			}
+		case "resolved":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Resolved = int(in.Int())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto24(out *jwriter.Writer, in ResolveReportsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"action\":"
+		out.RawString(prefix)
+		out.String(string(in.Action))
+	}
+	{
+		const prefix string = ",\"resolved\":"
+		out.RawString(prefix)
+		out.Int(int(in.Resolved))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResolveReportsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResolveReportsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResolveReportsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResolveReportsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto24(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto25(in *jlexer.Lexer, out *ResolveReportsRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "action":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Action = types.ModerationAction(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto25(out *jwriter.Writer, in ResolveReportsRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"action\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Action))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResolveReportsRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResolveReportsRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResolveReportsRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResolveReportsRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto25(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto26(in *jlexer.Lexer, out *ResetPasswordResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto26(out *jwriter.Writer, in ResetPasswordResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResetPasswordResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResetPasswordResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResetPasswordResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResetPasswordResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto26(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto27(in *jlexer.Lexer, out *ResetPasswordRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Token = string(in.String())
+			}
+		case "new_password":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.NewPassword = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto27(out *jwriter.Writer, in ResetPasswordRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"new_password\":"
+		out.RawString(prefix)
+		out.String(string(in.NewPassword))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResetPasswordRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResetPasswordRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResetPasswordRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResetPasswordRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto27(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto28(in *jlexer.Lexer, out *ResendVerificationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto28(out *jwriter.Writer, in ResendVerificationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResendVerificationResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResendVerificationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResendVerificationResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResendVerificationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto28(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto29(in *jlexer.Lexer, out *ReportedPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "reports":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Reports = int(in.Int())
+			}
+		case "reasons":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Reasons).UnmarshalEasyJSON(in)
+			}
+		case "comments":
+			if in.IsNull() {
+				in.Skip()
+				out.Comments = nil
+			} else {
+				in.Delim('[')
+				if out.Comments == nil {
+					if !in.IsDelim(']') {
+						out.Comments = make([]string, 0, 4)
+					} else {
+						out.Comments = []string{}
+					}
+				} else {
+					out.Comments = (out.Comments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v25 = string(in.String())
+					}
+					out.Comments = append(out.Comments, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "first_reported_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.FirstReportedAt).UnmarshalJSON(data))
+				}
+			}
+		case "last_reported_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastReportedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto29(out *jwriter.Writer, in ReportedPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix)
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"reports\":"
+		out.RawString(prefix)
+		out.Int(int(in.Reports))
+	}
+	{
+		const prefix string = ",\"reasons\":"
+		out.RawString(prefix)
+		(in.Reasons).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"comments\":"
+		out.RawString(prefix)
+		if in.Comments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v26, v27 := range in.Comments {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v27))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"first_reported_at\":"
+		out.RawString(prefix)
+		out.Raw((in.FirstReportedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_reported_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LastReportedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReportedPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportedPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReportedPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportedPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto29(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto30(in *jlexer.Lexer, out *ReportResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "report_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.ReportId).UnmarshalText(data))
+				}
+			}
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "reason":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Reason = types.ReportReason(in.String())
+			}
+		case "comment":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Comment = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto30(out *jwriter.Writer, in ReportResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"report_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ReportId).MarshalText())
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.String(string(in.Reason))
+	}
+	if in.Comment != "" {
+		const prefix string = ",\"comment\":"
+		out.RawString(prefix)
+		out.String(string(in.Comment))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReportResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReportResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto30(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto31(in *jlexer.Lexer, out *ReportReasons) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "spam":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Spam = int(in.Int())
+			}
+		case "abuse":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Abuse = int(in.Int())
+			}
+		case "copyright":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Copyright = int(in.Int())
+			}
+		case "other":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Other = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto31(out *jwriter.Writer, in ReportReasons) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"spam\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Spam))
+	}
+	{
+		const prefix string = ",\"abuse\":"
+		out.RawString(prefix)
+		out.Int(int(in.Abuse))
+	}
+	{
+		const prefix string = ",\"copyright\":"
+		out.RawString(prefix)
+		out.Int(int(in.Copyright))
+	}
+	{
+		const prefix string = ",\"other\":"
+		out.RawString(prefix)
+		out.Int(int(in.Other))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReportReasons) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportReasons) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReportReasons) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportReasons) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto31(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto32(in *jlexer.Lexer, out *ReportPostRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "reason":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Reason = types.ReportReason(in.String())
+			}
+		case "comment":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Comment = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto32(out *jwriter.Writer, in ReportPostRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Reason))
+	}
+	if in.Comment != "" {
+		const prefix string = ",\"comment\":"
+		out.RawString(prefix)
+		out.String(string(in.Comment))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReportPostRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReportPostRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto32(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto33(in *jlexer.Lexer, out *ReorderImagesRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "image_ids":
+			if in.IsNull() {
+				in.Skip()
+				out.ImageIds = nil
+			} else {
+				in.Delim('[')
+				if out.ImageIds == nil {
+					if !in.IsDelim(']') {
+						out.ImageIds = make([]uuid.UUID, 0, 4)
+					} else {
+						out.ImageIds = []uuid.UUID{}
+					}
+				} else {
+					out.ImageIds = (out.ImageIds)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 uuid.UUID
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						if data := in.UnsafeBytes(); in.Ok() {
+							in.AddError((v28).UnmarshalText(data))
+						}
+					}
+					out.ImageIds = append(out.ImageIds, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto33(out *jwriter.Writer, in ReorderImagesRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"image_ids\":"
+		out.RawString(prefix[1:])
+		if in.ImageIds == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v29, v30 := range in.ImageIds {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				out.RawText((v30).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReorderImagesRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReorderImagesRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReorderImagesRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReorderImagesRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto33(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto34(in *jlexer.Lexer, out *RegistrateUserResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.Id).UnmarshalText(data))
+				}
+			}
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AccessToken = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
+			}
+		case "refresh_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RefreshToken = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto34(out *jwriter.Writer, in RegistrateUserResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.Id).MarshalText())
+	}
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresIn))
+	}
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegistrateUserResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegistrateUserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegistrateUserResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegistrateUserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto34(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto35(in *jlexer.Lexer, out *RegistrateUserRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "password":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Password = string(in.String())
+			}
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto35(out *jwriter.Writer, in RegistrateUserRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegistrateUserRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegistrateUserRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegistrateUserRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegistrateUserRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto35(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto36(in *jlexer.Lexer, out *RefreshResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AccessToken = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto36(out *jwriter.Writer, in RefreshResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresIn))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RefreshResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RefreshResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RefreshResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RefreshResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto36(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto37(in *jlexer.Lexer, out *RefreshRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "refresh_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RefreshToken = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto37(out *jwriter.Writer, in RefreshRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RefreshRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RefreshRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RefreshRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RefreshRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto37(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto38(in *jlexer.Lexer, out *QuotaExceededResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = string(in.String())
+			}
+		case "used_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UsedBytes = int64(in.Int64())
+			}
+		case "limit_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LimitBytes = int64(in.Int64())
+			}
+		case "remaining_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RemainingBytes = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto38(out *jwriter.Writer, in QuotaExceededResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"used_bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.UsedBytes))
+	}
+	{
+		const prefix string = ",\"limit_bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LimitBytes))
+	}
+	{
+		const prefix string = ",\"remaining_bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.RemainingBytes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v QuotaExceededResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v QuotaExceededResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *QuotaExceededResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *QuotaExceededResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto38(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto39(in *jlexer.Lexer, out *PurgedTokens) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "refresh_tokens":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RefreshTokens = int64(in.Int64())
+			}
+		case "password_resets":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PasswordResets = int64(in.Int64())
+			}
+		case "preview_tokens":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PreviewTokens = int64(in.Int64())
+			}
+		case "idempotency_keys":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IdempotencyKeys = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto39(out *jwriter.Writer, in PurgedTokens) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"refresh_tokens\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.RefreshTokens))
+	}
+	{
+		const prefix string = ",\"password_resets\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.PasswordResets))
+	}
+	{
+		const prefix string = ",\"preview_tokens\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.PreviewTokens))
+	}
+	{
+		const prefix string = ",\"idempotency_keys\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.IdempotencyKeys))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PurgedTokens) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PurgedTokens) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PurgedTokens) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PurgedTokens) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto39(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto40(in *jlexer.Lexer, out *PurgeTokensResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "purged":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Purged).UnmarshalEasyJSON(in)
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Total).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto40(out *jwriter.Writer, in PurgeTokensResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"purged\":"
+		out.RawString(prefix[1:])
+		(in.Purged).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		(in.Total).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PurgeTokensResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PurgeTokensResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PurgeTokensResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PurgeTokensResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto40(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto41(in *jlexer.Lexer, out *PublishedPostEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v31 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v31 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v31)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "published_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.PublishedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto41(out *jwriter.Writer, in PublishedPostEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix)
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v32, v33 := range in.Tags {
+				if v32 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v33))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"published_at\":"
+		out.RawString(prefix)
+		out.Raw((in.PublishedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublishedPostEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublishedPostEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublishedPostEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublishedPostEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto41(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto42(in *jlexer.Lexer, out *PublishPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto42(out *jwriter.Writer, in PublishPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublishPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublishPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublishPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublishPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto42(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto43(in *jlexer.Lexer, out *PublishPostRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto43(out *jwriter.Writer, in PublishPostRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublishPostRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto43(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublishPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto43(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublishPostRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto43(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublishPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto43(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto44(in *jlexer.Lexer, out *PublicPostsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(PublicPostsResponse, 0, 8)
+			} else {
+				*out = PublicPostsResponse{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v34 *PublicPostResponse
+			if in.IsNull() {
+				in.Skip()
+				v34 = nil
+			} else {
+				if v34 == nil {
+					v34 = new(PublicPostResponse)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*v34).UnmarshalEasyJSON(in)
+				}
+			}
+			*out = append(*out, v34)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto44(out *jwriter.Writer, in PublicPostsResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v35, v36 := range in {
+			if v35 > 0 {
+				out.RawByte(',')
+			}
+			if v36 == nil {
+				out.RawString("null")
+			} else {
+				(*v36).MarshalEasyJSON(out)
+			}
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublicPostsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto44(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublicPostsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto44(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublicPostsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto44(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublicPostsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto44(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto45(in *jlexer.Lexer, out *PublicPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]AddImageResponse, 0, 1)
+					} else {
+						out.Images = []AddImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 AddImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v37).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v38 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v38 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v38)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "views":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Views = int(in.Int())
+			}
+		case "likes_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikesCount = int(in.Int())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto45(out *jwriter.Writer, in PublicPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(in.Author).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v39, v40 := range in.Images {
+				if v39 > 0 {
+					out.RawByte(',')
+				}
+				(v40).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v41, v42 := range in.Tags {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v42))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"views\":"
+		out.RawString(prefix)
+		out.Int(int(in.Views))
+	}
+	{
+		const prefix string = ",\"likes_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikesCount))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublicPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto45(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublicPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto45(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublicPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto45(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublicPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto45(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto46(in *jlexer.Lexer, out *PublicAuthorResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.UserId).UnmarshalText(data))
+				}
+			}
+		case "display_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DisplayName = string(in.String())
+			}
+		case "avatar_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvatarUrl = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto46(out *jwriter.Writer, in PublicAuthorResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.UserId).MarshalText())
+	}
+	{
+		const prefix string = ",\"display_name\":"
+		out.RawString(prefix)
+		out.String(string(in.DisplayName))
+	}
+	if in.AvatarUrl != "" {
+		const prefix string = ",\"avatar_url\":"
+		out.RawString(prefix)
+		out.String(string(in.AvatarUrl))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PublicAuthorResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto46(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PublicAuthorResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto46(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PublicAuthorResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto46(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PublicAuthorResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto46(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto47(in *jlexer.Lexer, out *PreviewTokenResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Token = string(in.String())
+			}
+		case "url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Url = string(in.String())
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto47(out *jwriter.Writer, in PreviewTokenResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix)
+		out.String(string(in.Token))
+	}
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix)
+		out.String(string(in.Url))
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PreviewTokenResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto47(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PreviewTokenResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto47(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PreviewTokenResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto47(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PreviewTokenResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto47(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto48(in *jlexer.Lexer, out *PostsImport) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]easyjson.RawMessage, 0, 2)
+					} else {
+						out.Posts = []easyjson.RawMessage{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 easyjson.RawMessage
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v43).UnmarshalEasyJSON(in)
+					}
+					out.Posts = append(out.Posts, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto48(out *jwriter.Writer, in PostsImport) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix[1:])
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v44, v45 := range in.Posts {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				(v45).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostsImport) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto48(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostsImport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto48(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostsImport) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto48(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostsImport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto48(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto49(in *jlexer.Lexer, out *PostsExport) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "exported_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ExportedAt).UnmarshalJSON(data))
+				}
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]ExportedPost, 0, 0)
+					} else {
+						out.Posts = []ExportedPost{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v46 ExportedPost
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v46).UnmarshalEasyJSON(in)
+					}
+					out.Posts = append(out.Posts, v46)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto49(out *jwriter.Writer, in PostsExport) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"exported_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExportedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v47, v48 := range in.Posts {
+				if v47 > 0 {
+					out.RawByte(',')
+				}
+				(v48).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostsExport) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto49(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostsExport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto49(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostsExport) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto49(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostsExport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto49(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto50(in *jlexer.Lexer, out *PostStatsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "views":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Views = int(in.Int())
+			}
+		case "days":
+			if in.IsNull() {
+				in.Skip()
+				out.Days = nil
+			} else {
+				in.Delim('[')
+				if out.Days == nil {
+					if !in.IsDelim(']') {
+						out.Days = make([]ViewDayResponse, 0, 2)
+					} else {
+						out.Days = []ViewDayResponse{}
+					}
+				} else {
+					out.Days = (out.Days)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v49 ViewDayResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v49).UnmarshalEasyJSON(in)
+					}
+					out.Days = append(out.Days, v49)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto50(out *jwriter.Writer, in PostStatsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"views\":"
+		out.RawString(prefix)
+		out.Int(int(in.Views))
+	}
+	{
+		const prefix string = ",\"days\":"
+		out.RawString(prefix)
+		if in.Days == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v50, v51 := range in.Days {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				(v51).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostStatsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto50(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostStatsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto50(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostStatsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto50(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostStatsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto50(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto51(in *jlexer.Lexer, out *PostRevisionResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "revision_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.RevisionId).UnmarshalText(data))
+				}
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "edited_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.EditedAt).UnmarshalJSON(data))
+				}
+			}
+		case "edited_by":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.EditedBy).UnmarshalText(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto51(out *jwriter.Writer, in PostRevisionResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revision_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.RevisionId).MarshalText())
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"edited_at\":"
+		out.RawString(prefix)
+		out.Raw((in.EditedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"edited_by\":"
+		out.RawString(prefix)
+		out.RawText((in.EditedBy).MarshalText())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostRevisionResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto51(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostRevisionResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto51(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostRevisionResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto51(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostRevisionResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto51(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto52(in *jlexer.Lexer, out *PostRecord) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "idempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IdempotencyKey = string(in.String())
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+				out.Excerpt = nil
+			} else {
+				if out.Excerpt == nil {
+					out.Excerpt = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Excerpt = string(in.String())
+				}
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "deleted_at":
+			if in.IsNull() {
+				in.Skip()
+				out.DeletedAt = nil
+			} else {
+				if out.DeletedAt == nil {
+					out.DeletedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.DeletedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v52 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v52 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v52)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "import_hash":
+			if in.IsNull() {
+				in.Skip()
+				out.ImportHash = nil
+			} else {
+				if out.ImportHash == nil {
+					out.ImportHash = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.ImportHash = string(in.String())
+				}
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto52(out *jwriter.Writer, in PostRecord) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix)
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"idempotency_key\":"
+		out.RawString(prefix)
+		out.String(string(in.IdempotencyKey))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != nil {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(*in.Excerpt))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	if in.DeletedAt != nil {
+		const prefix string = ",\"deleted_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.DeletedAt).MarshalJSON())
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v53, v54 := range in.Tags {
+				if v53 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v54))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ImportHash != nil {
+		const prefix string = ",\"import_hash\":"
+		out.RawString(prefix)
+		out.String(string(*in.ImportHash))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostRecord) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto52(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostRecord) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto52(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostRecord) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto52(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostRecord) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto52(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto53(in *jlexer.Lexer, out *PatchPostRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+				out.Title = nil
+			} else {
+				if out.Title == nil {
+					out.Title = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Title = string(in.String())
+				}
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+				out.Content = nil
+			} else {
+				if out.Content == nil {
+					out.Content = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Content = string(in.String())
+				}
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+				out.Excerpt = nil
+			} else {
+				if out.Excerpt == nil {
+					out.Excerpt = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Excerpt = string(in.String())
+				}
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v55 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v55 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v55)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expected_updated_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpectedUpdatedAt = nil
+			} else {
+				if out.ExpectedUpdatedAt == nil {
+					out.ExpectedUpdatedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ExpectedUpdatedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto53(out *jwriter.Writer, in PatchPostRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Title != nil {
+		const prefix string = ",\"title\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(*in.Title))
+	}
+	if in.Content != nil {
+		const prefix string = ",\"content\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(*in.Content))
+	}
+	if in.Excerpt != nil {
+		const prefix string = ",\"excerpt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(*in.Excerpt))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v56, v57 := range in.Tags {
+				if v56 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v57))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ExpectedUpdatedAt != nil {
+		const prefix string = ",\"expected_updated_at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.ExpectedUpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PatchPostRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto53(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PatchPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto53(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PatchPostRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto53(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PatchPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto53(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto54(in *jlexer.Lexer, out *OrphanImageResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "last_modified":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastModified).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto54(out *jwriter.Writer, in OrphanImageResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"last_modified\":"
+		out.RawString(prefix)
+		out.Raw((in.LastModified).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OrphanImageResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto54(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OrphanImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto54(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OrphanImageResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto54(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OrphanImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto54(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto55(in *jlexer.Lexer, out *NotificationsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "notifications":
+			if in.IsNull() {
+				in.Skip()
+				out.Notifications = nil
+			} else {
+				in.Delim('[')
+				if out.Notifications == nil {
+					if !in.IsDelim(']') {
+						out.Notifications = make([]NotificationResponse, 0, 0)
+					} else {
+						out.Notifications = []NotificationResponse{}
+					}
+				} else {
+					out.Notifications = (out.Notifications)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 NotificationResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v58).UnmarshalEasyJSON(in)
+					}
+					out.Notifications = append(out.Notifications, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "unread":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Unread = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto55(out *jwriter.Writer, in NotificationsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"notifications\":"
+		out.RawString(prefix[1:])
+		if in.Notifications == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v59, v60 := range in.Notifications {
+				if v59 > 0 {
+					out.RawByte(',')
+				}
+				(v60).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Unread))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto55(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto55(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto55(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto55(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto56(in *jlexer.Lexer, out *NotificationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "notification_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.NotificationId).UnmarshalText(data))
+				}
+			}
+		case "type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Type = types.NotificationType(in.String())
+			}
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Post).UnmarshalEasyJSON(in)
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "read_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ReadAt = nil
+			} else {
+				if out.ReadAt == nil {
+					out.ReadAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ReadAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto56(out *jwriter.Writer, in NotificationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"notification_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.NotificationId).MarshalText())
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		(in.Post).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"read_at\":"
+		out.RawString(prefix)
+		if in.ReadAt == nil {
+			out.RawString("null")
+		} else {
+			out.Raw((*in.ReadAt).MarshalJSON())
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto56(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto56(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto56(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto56(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto57(in *jlexer.Lexer, out *NotificationPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto57(out *jwriter.Writer, in NotificationPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(in.Author).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto57(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto57(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto57(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto57(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto58(in *jlexer.Lexer, out *MarkNotificationsReadResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "marked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Marked = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto58(out *jwriter.Writer, in MarkNotificationsReadResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"marked\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Marked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MarkNotificationsReadResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto58(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MarkNotificationsReadResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto58(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MarkNotificationsReadResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto58(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MarkNotificationsReadResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto58(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto59(in *jlexer.Lexer, out *MarkNotificationsReadRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "notification_ids":
+			if in.IsNull() {
+				in.Skip()
+				out.NotificationIds = nil
+			} else {
+				in.Delim('[')
+				if out.NotificationIds == nil {
+					if !in.IsDelim(']') {
+						out.NotificationIds = make([]uuid.UUID, 0, 4)
+					} else {
+						out.NotificationIds = []uuid.UUID{}
+					}
+				} else {
+					out.NotificationIds = (out.NotificationIds)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v61 uuid.UUID
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						if data := in.UnsafeBytes(); in.Ok() {
+							in.AddError((v61).UnmarshalText(data))
+						}
+					}
+					out.NotificationIds = append(out.NotificationIds, v61)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto59(out *jwriter.Writer, in MarkNotificationsReadRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"notification_ids\":"
+		out.RawString(prefix[1:])
+		if in.NotificationIds == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v62, v63 := range in.NotificationIds {
+				if v62 > 0 {
+					out.RawByte(',')
+				}
+				out.RawText((v63).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MarkNotificationsReadRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto59(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MarkNotificationsReadRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto59(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MarkNotificationsReadRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto59(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MarkNotificationsReadRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto59(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto60(in *jlexer.Lexer, out *LoginUserResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.Id).UnmarshalText(data))
+				}
+			}
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AccessToken = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
 			}
 		case "refresh_token":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.RefreshToken = string(in.String())
+				out.RefreshToken = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto60(out *jwriter.Writer, in LoginUserResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.Id).MarshalText())
+	}
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresIn))
+	}
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginUserResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto60(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginUserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto60(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginUserResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto60(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginUserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto60(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto61(in *jlexer.Lexer, out *LoginUserRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "password":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Password = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto61(out *jwriter.Writer, in LoginUserRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix)
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginUserRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto61(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginUserRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto61(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginUserRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto61(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginUserRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto61(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto62(in *jlexer.Lexer, out *LikeResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "liked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Liked = bool(in.Bool())
+			}
+		case "likes_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikesCount = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto62(out *jwriter.Writer, in LikeResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"liked\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Liked))
+	}
+	{
+		const prefix string = ",\"likes_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikesCount))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LikeResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto62(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LikeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto62(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LikeResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto62(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LikeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto62(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto63(in *jlexer.Lexer, out *JobsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "jobs":
+			if in.IsNull() {
+				in.Skip()
+				out.Jobs = nil
+			} else {
+				in.Delim('[')
+				if out.Jobs == nil {
+					if !in.IsDelim(']') {
+						out.Jobs = make([]JobStats, 0, 0)
+					} else {
+						out.Jobs = []JobStats{}
+					}
+				} else {
+					out.Jobs = (out.Jobs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v64 JobStats
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v64).UnmarshalEasyJSON(in)
+					}
+					out.Jobs = append(out.Jobs, v64)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto63(out *jwriter.Writer, in JobsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"jobs\":"
+		out.RawString(prefix[1:])
+		if in.Jobs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v65, v66 := range in.Jobs {
+				if v65 > 0 {
+					out.RawByte(',')
+				}
+				(v66).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JobsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto63(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JobsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto63(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JobsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto63(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JobsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto63(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto64(in *jlexer.Lexer, out *JobStats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "interval_ms":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IntervalMs = int64(in.Int64())
+			}
+		case "runs":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Runs = uint64(in.Uint64())
+			}
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Errors = uint64(in.Uint64())
+			}
+		case "skipped":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Skipped = uint64(in.Uint64())
+			}
+		case "running":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Running = bool(in.Bool())
+			}
+		case "last_run_at":
+			if in.IsNull() {
+				in.Skip()
+				out.LastRunAt = nil
+			} else {
+				if out.LastRunAt == nil {
+					out.LastRunAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.LastRunAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "last_duration_ms":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastDurationMs = int64(in.Int64())
+			}
+		case "avg_duration_ms":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvgDurationMs = int64(in.Int64())
+			}
+		case "last_error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LastError = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto64(out *jwriter.Writer, in JobStats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"interval_ms\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.IntervalMs))
+	}
+	{
+		const prefix string = ",\"runs\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Runs))
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Errors))
+	}
+	{
+		const prefix string = ",\"skipped\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Skipped))
+	}
+	{
+		const prefix string = ",\"running\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Running))
+	}
+	if in.LastRunAt != nil {
+		const prefix string = ",\"last_run_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastRunAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_duration_ms\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastDurationMs))
+	}
+	{
+		const prefix string = ",\"avg_duration_ms\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.AvgDurationMs))
+	}
+	if in.LastError != "" {
+		const prefix string = ",\"last_error\":"
+		out.RawString(prefix)
+		out.String(string(in.LastError))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JobStats) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto64(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JobStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto64(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JobStats) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto64(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JobStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto64(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto65(in *jlexer.Lexer, out *ImportPostsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "imported":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Imported = int(in.Int())
+			}
+		case "skipped":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Skipped = int(in.Int())
+			}
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]ImportError, 0, 2)
+					} else {
+						out.Errors = []ImportError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v67 ImportError
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v67).UnmarshalEasyJSON(in)
+					}
+					out.Errors = append(out.Errors, v67)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto65(out *jwriter.Writer, in ImportPostsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"imported\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Imported))
+	}
+	{
+		const prefix string = ",\"skipped\":"
+		out.RawString(prefix)
+		out.Int(int(in.Skipped))
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		if in.Errors == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v68, v69 := range in.Errors {
+				if v68 > 0 {
+					out.RawByte(',')
+				}
+				(v69).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImportPostsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto65(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportPostsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto65(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImportPostsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto65(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportPostsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto65(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto66(in *jlexer.Lexer, out *ImportError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "entry":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Entry = string(in.String())
+			}
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto66(out *jwriter.Writer, in ImportError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"entry\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Entry))
+	}
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImportError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto66(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto66(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImportError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto66(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto66(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto67(in *jlexer.Lexer, out *ImageResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "image_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.ImageId).UnmarshalText(data))
+				}
+			}
+		case "image_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImageUrl = string(in.String())
+			}
+		case "position":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Position = int(in.Int())
+			}
+		case "caption":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Caption = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto67(out *jwriter.Writer, in ImageResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"image_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ImageId).MarshalText())
+	}
+	{
+		const prefix string = ",\"image_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ImageUrl))
+	}
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		out.Int(int(in.Position))
+	}
+	{
+		const prefix string = ",\"caption\":"
+		out.RawString(prefix)
+		out.String(string(in.Caption))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImageResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto67(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto67(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImageResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto67(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto67(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto68(in *jlexer.Lexer, out *ImageRecord) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "image_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.ImageId).UnmarshalText(data))
+				}
+			}
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "image_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImageUrl = string(in.String())
+			}
+		case "object_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ObjectName = string(in.String())
+			}
+		case "content_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ContentType = string(in.String())
+			}
+		case "position":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Position = int(in.Int())
+			}
+		case "caption":
+			if in.IsNull() {
+				in.Skip()
+				out.Caption = nil
+			} else {
+				if out.Caption == nil {
+					out.Caption = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Caption = string(in.String())
+				}
+			}
+		case "size_bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SizeBytes = int64(in.Int64())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto68(out *jwriter.Writer, in ImageRecord) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"image_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ImageId).MarshalText())
+	}
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix)
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"image_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ImageUrl))
+	}
+	{
+		const prefix string = ",\"object_name\":"
+		out.RawString(prefix)
+		out.String(string(in.ObjectName))
+	}
+	{
+		const prefix string = ",\"content_type\":"
+		out.RawString(prefix)
+		out.String(string(in.ContentType))
+	}
+	if in.Position != 0 {
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		out.Int(int(in.Position))
+	}
+	if in.Caption != nil {
+		const prefix string = ",\"caption\":"
+		out.RawString(prefix)
+		out.String(string(*in.Caption))
+	}
+	if in.SizeBytes != 0 {
+		const prefix string = ",\"size_bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.SizeBytes))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ImageRecord) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto68(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImageRecord) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto68(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ImageRecord) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto68(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImageRecord) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto68(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto69(in *jlexer.Lexer, out *HealthResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "checks":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Checks = make(map[string]string)
+				} else {
+					out.Checks = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v70 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v70 = string(in.String())
+					}
+					(out.Checks)[key] = v70
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto69(out *jwriter.Writer, in HealthResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	if len(in.Checks) != 0 {
+		const prefix string = ",\"checks\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v71First := true
+			for v71Name, v71Value := range in.Checks {
+				if v71First {
+					v71First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v71Name))
+				out.RawByte(':')
+				out.String(string(v71Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HealthResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto69(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HealthResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto69(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HealthResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto69(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HealthResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto69(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto70(in *jlexer.Lexer, out *GetUsersResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]UserResponse, 0, 0)
+					} else {
+						out.Users = []UserResponse{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v72 UserResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v72).UnmarshalEasyJSON(in)
+					}
+					out.Users = append(out.Users, v72)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto70(out *jwriter.Writer, in GetUsersResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"users\":"
+		out.RawString(prefix[1:])
+		if in.Users == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v73, v74 := range in.Users {
+				if v73 > 0 {
+					out.RawByte(',')
+				}
+				(v74).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetUsersResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto70(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetUsersResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto70(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetUsersResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto70(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetUsersResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto70(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto71(in *jlexer.Lexer, out *GetSessionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "sessions":
+			if in.IsNull() {
+				in.Skip()
+				out.Sessions = nil
+			} else {
+				in.Delim('[')
+				if out.Sessions == nil {
+					if !in.IsDelim(']') {
+						out.Sessions = make([]SessionResponse, 0, 0)
+					} else {
+						out.Sessions = []SessionResponse{}
+					}
+				} else {
+					out.Sessions = (out.Sessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v75 SessionResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v75).UnmarshalEasyJSON(in)
+					}
+					out.Sessions = append(out.Sessions, v75)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto71(out *jwriter.Writer, in GetSessionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"sessions\":"
+		out.RawString(prefix[1:])
+		if in.Sessions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v76, v77 := range in.Sessions {
+				if v76 > 0 {
+					out.RawByte(',')
+				}
+				(v77).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetSessionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto71(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetSessionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto71(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetSessionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto71(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetSessionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto71(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto72(in *jlexer.Lexer, out *GetReportsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]ReportedPostResponse, 0, 0)
+					} else {
+						out.Posts = []ReportedPostResponse{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v78 ReportedPostResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v78).UnmarshalEasyJSON(in)
+					}
+					out.Posts = append(out.Posts, v78)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto72(out *jwriter.Writer, in GetReportsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix[1:])
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v79, v80 := range in.Posts {
+				if v79 > 0 {
+					out.RawByte(',')
+				}
+				(v80).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetReportsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto72(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetReportsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto72(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetReportsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto72(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetReportsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto72(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto73(in *jlexer.Lexer, out *GetPostsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(GetPostsResponse, 0, 8)
+			} else {
+				*out = GetPostsResponse{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v81 *GetPostResponse
+			if in.IsNull() {
+				in.Skip()
+				v81 = nil
+			} else {
+				if v81 == nil {
+					v81 = new(GetPostResponse)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*v81).UnmarshalEasyJSON(in)
+				}
+			}
+			*out = append(*out, v81)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto73(out *jwriter.Writer, in GetPostsResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v82, v83 := range in {
+			if v82 > 0 {
+				out.RawByte(',')
+			}
+			if v83 == nil {
+				out.RawString("null")
+			} else {
+				(*v83).MarshalEasyJSON(out)
+			}
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetPostsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto73(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetPostsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto73(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetPostsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto73(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetPostsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto73(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto74(in *jlexer.Lexer, out *GetPostRevisionsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "revisions":
+			if in.IsNull() {
+				in.Skip()
+				out.Revisions = nil
+			} else {
+				in.Delim('[')
+				if out.Revisions == nil {
+					if !in.IsDelim(']') {
+						out.Revisions = make([]PostRevisionResponse, 0, 0)
+					} else {
+						out.Revisions = []PostRevisionResponse{}
+					}
+				} else {
+					out.Revisions = (out.Revisions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v84 PostRevisionResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v84).UnmarshalEasyJSON(in)
+					}
+					out.Revisions = append(out.Revisions, v84)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto74(out *jwriter.Writer, in GetPostRevisionsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revisions\":"
+		out.RawString(prefix[1:])
+		if in.Revisions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v85, v86 := range in.Revisions {
+				if v85 > 0 {
+					out.RawByte(',')
+				}
+				(v86).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetPostRevisionsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto74(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetPostRevisionsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto74(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetPostRevisionsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto74(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetPostRevisionsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto74(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto75(in *jlexer.Lexer, out *GetPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Author).UnmarshalEasyJSON(in)
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "content_html":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ContentHTML = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]AddImageResponse, 0, 1)
+					} else {
+						out.Images = []AddImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v87 AddImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v87).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v87)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v88 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v88 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v88)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "views":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Views = int(in.Int())
+			}
+		case "likes_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikesCount = int(in.Int())
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "word_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.WordCount = int(in.Int())
+			}
+		case "reading_time_minutes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ReadingTimeMinutes = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto75(out *jwriter.Writer, in GetPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(in.Author).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	if in.Content != "" {
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.ContentHTML != "" {
+		const prefix string = ",\"content_html\":"
+		out.RawString(prefix)
+		out.String(string(in.ContentHTML))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v89, v90 := range in.Images {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				(v90).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v91, v92 := range in.Tags {
+				if v91 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v92))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"views\":"
+		out.RawString(prefix)
+		out.Int(int(in.Views))
+	}
+	{
+		const prefix string = ",\"likes_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikesCount))
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"word_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.WordCount))
+	}
+	{
+		const prefix string = ",\"reading_time_minutes\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReadingTimeMinutes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto75(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto75(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto75(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto75(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto76(in *jlexer.Lexer, out *GetPostImagesResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]ImageResponse, 0, 0)
+					} else {
+						out.Images = []ImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v93 ImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v93).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v93)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto76(out *jwriter.Writer, in GetPostImagesResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix[1:])
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v94, v95 := range in.Images {
+				if v94 > 0 {
+					out.RawByte(',')
+				}
+				(v95).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetPostImagesResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto76(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetPostImagesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto76(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetPostImagesResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto76(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetPostImagesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto76(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto77(in *jlexer.Lexer, out *GetAuditLogResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "entries":
+			if in.IsNull() {
+				in.Skip()
+				out.Entries = nil
+			} else {
+				in.Delim('[')
+				if out.Entries == nil {
+					if !in.IsDelim(']') {
+						out.Entries = make([]AuditEntryResponse, 0, 0)
+					} else {
+						out.Entries = []AuditEntryResponse{}
+					}
+				} else {
+					out.Entries = (out.Entries)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v96 AuditEntryResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v96).UnmarshalEasyJSON(in)
+					}
+					out.Entries = append(out.Entries, v96)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Total = int(in.Int())
+			}
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Limit = int(in.Int())
+			}
+		case "offset":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Offset = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto77(out *jwriter.Writer, in GetAuditLogResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"entries\":"
+		out.RawString(prefix[1:])
+		if in.Entries == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v97, v98 := range in.Entries {
+				if v97 > 0 {
+					out.RawByte(',')
+				}
+				(v98).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetAuditLogResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto77(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetAuditLogResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto77(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetAuditLogResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto77(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetAuditLogResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto77(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto78(in *jlexer.Lexer, out *GetApiKeysResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "api_keys":
+			if in.IsNull() {
+				in.Skip()
+				out.ApiKeys = nil
+			} else {
+				in.Delim('[')
+				if out.ApiKeys == nil {
+					if !in.IsDelim(']') {
+						out.ApiKeys = make([]ApiKeyResponse, 0, 0)
+					} else {
+						out.ApiKeys = []ApiKeyResponse{}
+					}
+				} else {
+					out.ApiKeys = (out.ApiKeys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v99 ApiKeyResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v99).UnmarshalEasyJSON(in)
+					}
+					out.ApiKeys = append(out.ApiKeys, v99)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto78(out *jwriter.Writer, in GetApiKeysResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"api_keys\":"
+		out.RawString(prefix[1:])
+		if in.ApiKeys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v100, v101 := range in.ApiKeys {
+				if v100 > 0 {
+					out.RawByte(',')
+				}
+				(v101).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetApiKeysResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto78(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetApiKeysResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto78(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetApiKeysResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto78(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetApiKeysResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto78(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto79(in *jlexer.Lexer, out *ForgotPasswordResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto79(out *jwriter.Writer, in ForgotPasswordResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForgotPasswordResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto79(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForgotPasswordResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto79(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForgotPasswordResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto79(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForgotPasswordResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto79(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto80(in *jlexer.Lexer, out *ForgotPasswordRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto80(out *jwriter.Writer, in ForgotPasswordRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForgotPasswordRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto80(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForgotPasswordRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto80(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForgotPasswordRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto80(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForgotPasswordRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto80(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto81(in *jlexer.Lexer, out *FieldError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "field":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Field = string(in.String())
+			}
+		case "rule":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Rule = string(in.String())
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto81(out *jwriter.Writer, in FieldError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"field\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"rule\":"
+		out.RawString(prefix)
+		out.String(string(in.Rule))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto81(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto81(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto81(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto81(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto82(in *jlexer.Lexer, out *ExportedPost) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v102 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v102 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v102)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "publish_at":
+			if in.IsNull() {
+				in.Skip()
+				out.PublishAt = nil
+			} else {
+				if out.PublishAt == nil {
+					out.PublishAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.PublishAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]ImageResponse, 0, 0)
+					} else {
+						out.Images = []ImageResponse{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v103 ImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v103).UnmarshalEasyJSON(in)
+					}
+					out.Images = append(out.Images, v103)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto82(out *jwriter.Writer, in ExportedPost) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != "" {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v104, v105 := range in.Tags {
+				if v104 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v105))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.PublishAt != nil {
+		const prefix string = ",\"publish_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.PublishAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v106, v107 := range in.Images {
+				if v106 > 0 {
+					out.RawByte(',')
+				}
+				(v107).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExportedPost) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto82(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExportedPost) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto82(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExportedPost) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto82(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExportedPost) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto82(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto83(in *jlexer.Lexer, out *ExportedLike) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "liked_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LikedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto83(out *jwriter.Writer, in ExportedLike) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"liked_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LikedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ExportedLike) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto83(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ExportedLike) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto83(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ExportedLike) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto83(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ExportedLike) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto83(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto84(in *jlexer.Lexer, out *ErrorResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = string(in.String())
+			}
+		case "fields":
+			if in.IsNull() {
+				in.Skip()
+				out.Fields = nil
+			} else {
+				in.Delim('[')
+				if out.Fields == nil {
+					if !in.IsDelim(']') {
+						out.Fields = make([]FieldError, 0, 1)
+					} else {
+						out.Fields = []FieldError{}
+					}
+				} else {
+					out.Fields = (out.Fields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v108 FieldError
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v108).UnmarshalEasyJSON(in)
+					}
+					out.Fields = append(out.Fields, v108)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto84(out *jwriter.Writer, in ErrorResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Error))
+	}
+	if in.Code != "" {
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	if len(in.Fields) != 0 {
+		const prefix string = ",\"fields\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v109, v110 := range in.Fields {
+				if v109 > 0 {
+					out.RawByte(',')
+				}
+				(v110).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ErrorResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto84(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ErrorResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto84(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ErrorResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto84(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ErrorResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto84(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto85(in *jlexer.Lexer, out *EditPostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "idempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IdempotencyKey = string(in.String())
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v111 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v111 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v111)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "word_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.WordCount = int(in.Int())
+			}
+		case "reading_time_minutes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ReadingTimeMinutes = int(in.Int())
+			}
+		case "indempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LegacyIdempotencyKey = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto85(out *jwriter.Writer, in EditPostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix)
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"idempotency_key\":"
+		out.RawString(prefix)
+		out.String(string(in.IdempotencyKey))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != "" {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v112, v113 := range in.Tags {
+				if v112 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v113))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"word_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.WordCount))
+	}
+	{
+		const prefix string = ",\"reading_time_minutes\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReadingTimeMinutes))
+	}
+	{
+		const prefix string = ",\"indempotency_key\":"
+		out.RawString(prefix)
+		out.String(string(in.LegacyIdempotencyKey))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditPostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto85(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto85(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditPostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto85(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto85(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto86(in *jlexer.Lexer, out *EditPostRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+				out.Excerpt = nil
+			} else {
+				if out.Excerpt == nil {
+					out.Excerpt = new(string)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Excerpt = string(in.String())
+				}
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v114 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v114 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v114)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expected_updated_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpectedUpdatedAt = nil
+			} else {
+				if out.ExpectedUpdatedAt == nil {
+					out.ExpectedUpdatedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ExpectedUpdatedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto86(out *jwriter.Writer, in EditPostRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != nil {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(*in.Excerpt))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v115, v116 := range in.Tags {
+				if v115 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v116))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ExpectedUpdatedAt != nil {
+		const prefix string = ",\"expected_updated_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpectedUpdatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EditPostRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto86(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EditPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto86(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EditPostRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto86(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EditPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto86(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto87(in *jlexer.Lexer, out *DeleteImageResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "image_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.ImageId).UnmarshalText(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto87(out *jwriter.Writer, in DeleteImageResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"image_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ImageId).MarshalText())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeleteImageResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto87(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeleteImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto87(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeleteImageResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto87(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeleteImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto87(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto88(in *jlexer.Lexer, out *DeleteAccountRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "password":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Password = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto88(out *jwriter.Writer, in DeleteAccountRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"password\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Password))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeleteAccountRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto88(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeleteAccountRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto88(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeleteAccountRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto88(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeleteAccountRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto88(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto89(in *jlexer.Lexer, out *CreatePostResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "post_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.PostId).UnmarshalText(data))
+				}
+			}
+		case "author_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.AuthorId).UnmarshalText(data))
+				}
+			}
+		case "idempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IdempotencyKey = string(in.String())
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = types.PostStatus(in.String())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v117 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v117 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v117)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "word_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.WordCount = int(in.Int())
+			}
+		case "reading_time_minutes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ReadingTimeMinutes = int(in.Int())
+			}
+		case "indempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LegacyIdempotencyKey = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto89(out *jwriter.Writer, in CreatePostResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.PostId).MarshalText())
+	}
+	{
+		const prefix string = ",\"author_id\":"
+		out.RawString(prefix)
+		out.RawText((in.AuthorId).MarshalText())
+	}
+	{
+		const prefix string = ",\"idempotency_key\":"
+		out.RawString(prefix)
+		out.String(string(in.IdempotencyKey))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != "" {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v118, v119 := range in.Tags {
+				if v118 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v119))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"word_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.WordCount))
+	}
+	{
+		const prefix string = ",\"reading_time_minutes\":"
+		out.RawString(prefix)
+		out.Int(int(in.ReadingTimeMinutes))
+	}
+	{
+		const prefix string = ",\"indempotency_key\":"
+		out.RawString(prefix)
+		out.String(string(in.LegacyIdempotencyKey))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CreatePostResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto89(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CreatePostResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto89(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CreatePostResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto89(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CreatePostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto89(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto90(in *jlexer.Lexer, out *CreatePostRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "idempotency_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IdempotencyKey = string(in.String())
+			}
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "content":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Content = string(in.String())
+			}
+		case "excerpt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Excerpt = string(in.String())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v120 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v120 = string(in.String())
+					}
+					out.Tags = append(out.Tags, v120)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto90(out *jwriter.Writer, in CreatePostRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"idempotency_key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.IdempotencyKey))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"content\":"
+		out.RawString(prefix)
+		out.String(string(in.Content))
+	}
+	if in.Excerpt != "" {
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v121, v122 := range in.Tags {
+				if v121 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v122))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CreatePostRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto90(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CreatePostRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto90(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CreatePostRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto90(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CreatePostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto90(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto91(in *jlexer.Lexer, out *CreateApiKeyResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Key = string(in.String())
+			}
+		case "key_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.KeyId).UnmarshalText(data))
+				}
+			}
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]types.Scope, 0, 4)
+					} else {
+						out.Scopes = []types.Scope{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v123 types.Scope
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v123 = types.Scope(in.String())
+					}
+					out.Scopes = append(out.Scopes, v123)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "last_used_at":
+			if in.IsNull() {
+				in.Skip()
+				out.LastUsedAt = nil
+			} else {
+				if out.LastUsedAt == nil {
+					out.LastUsedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.LastUsedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto91(out *jwriter.Writer, in CreateApiKeyResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"key_id\":"
+		out.RawString(prefix)
+		out.RawText((in.KeyId).MarshalText())
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v124, v125 := range in.Scopes {
+				if v124 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v125))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.LastUsedAt != nil {
+		const prefix string = ",\"last_used_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastUsedAt).MarshalJSON())
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiresAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CreateApiKeyResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto91(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CreateApiKeyResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto91(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CreateApiKeyResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto91(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CreateApiKeyResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto91(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto92(in *jlexer.Lexer, out *CreateApiKeyRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]types.Scope, 0, 4)
+					} else {
+						out.Scopes = []types.Scope{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v126 types.Scope
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v126 = types.Scope(in.String())
+					}
+					out.Scopes = append(out.Scopes, v126)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto92(out *jwriter.Writer, in CreateApiKeyRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v127, v128 := range in.Scopes {
+				if v127 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v128))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		if in.ExpiresAt == nil {
+			out.RawString("null")
+		} else {
+			out.Raw((*in.ExpiresAt).MarshalJSON())
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CreateApiKeyRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto92(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CreateApiKeyRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto92(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CreateApiKeyRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto92(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CreateApiKeyRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto92(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto93(in *jlexer.Lexer, out *ConfigResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "settings":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Settings = make(map[string]string)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v129 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v129 = string(in.String())
+					}
+					(out.Settings)[key] = v129
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto93(out *jwriter.Writer, in ConfigResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"settings\":"
+		out.RawString(prefix[1:])
+		if in.Settings == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v130First := true
+			for v130Name, v130Value := range in.Settings {
+				if v130First {
+					v130First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v130Name))
+				out.RawByte(':')
+				out.String(string(v130Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConfigResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto93(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConfigResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto93(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConfigResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto93(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConfigResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto93(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto94(in *jlexer.Lexer, out *CleanupImagesResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "dry_run":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DryRun = bool(in.Bool())
+			}
+		case "scanned":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scanned = int(in.Int())
+			}
+		case "orphans":
+			if in.IsNull() {
+				in.Skip()
+				out.Orphans = nil
+			} else {
+				in.Delim('[')
+				if out.Orphans == nil {
+					if !in.IsDelim(']') {
+						out.Orphans = make([]OrphanImageResponse, 0, 1)
+					} else {
+						out.Orphans = []OrphanImageResponse{}
+					}
+				} else {
+					out.Orphans = (out.Orphans)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v131 OrphanImageResponse
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v131).UnmarshalEasyJSON(in)
+					}
+					out.Orphans = append(out.Orphans, v131)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto94(out *jwriter.Writer, in CleanupImagesResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"dry_run\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.DryRun))
+	}
+	{
+		const prefix string = ",\"scanned\":"
+		out.RawString(prefix)
+		out.Int(int(in.Scanned))
+	}
+	{
+		const prefix string = ",\"orphans\":"
+		out.RawString(prefix)
+		if in.Orphans == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v132, v133 := range in.Orphans {
+				if v132 > 0 {
+					out.RawByte(',')
+				}
+				(v133).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CleanupImagesResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto94(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CleanupImagesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto94(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CleanupImagesResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto94(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CleanupImagesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto94(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto95(in *jlexer.Lexer, out *ChangeRoleRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto95(out *jwriter.Writer, in ChangeRoleRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeRoleRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto95(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeRoleRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto95(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeRoleRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto95(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeRoleRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto95(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto96(in *jlexer.Lexer, out *ChangeOwnRoleResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.Id).UnmarshalText(data))
+				}
+			}
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AccessToken = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto96(out *jwriter.Writer, in ChangeOwnRoleResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.Id).MarshalText())
+	}
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix)
+		out.String(string(in.Role))
+	}
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix)
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresIn))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeOwnRoleResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto96(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeOwnRoleResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto96(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeOwnRoleResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto96(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeOwnRoleResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto96(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto97(in *jlexer.Lexer, out *ChangeOwnRoleRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "role":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Role = types.Role(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto97(out *jwriter.Writer, in ChangeOwnRoleRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"role\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Role))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeOwnRoleRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto97(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeOwnRoleRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto97(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeOwnRoleRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto97(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeOwnRoleRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto97(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto98(in *jlexer.Lexer, out *CacheStatsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Users).UnmarshalEasyJSON(in)
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Posts).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto98(out *jwriter.Writer, in CacheStatsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"users\":"
+		out.RawString(prefix[1:])
+		(in.Users).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		(in.Posts).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CacheStatsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto98(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CacheStatsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto98(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CacheStatsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto98(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CacheStatsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto98(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto99(in *jlexer.Lexer, out *CacheStats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "enabled":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Enabled = bool(in.Bool())
+			}
+		case "hits":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Hits = int64(in.Int64())
+			}
+		case "misses":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Misses = int64(in.Int64())
+			}
+		case "hit_ratio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HitRatio = float64(in.Float64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto99(out *jwriter.Writer, in CacheStats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"enabled\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Enabled))
+	}
+	{
+		const prefix string = ",\"hits\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Hits))
+	}
+	{
+		const prefix string = ",\"misses\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Misses))
+	}
+	{
+		const prefix string = ",\"hit_ratio\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.HitRatio))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CacheStats) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto99(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CacheStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto99(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CacheStats) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto99(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CacheStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto99(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto100(in *jlexer.Lexer, out *BackupSummary) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "run":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Run = string(in.String())
+			}
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Users = int(in.Int())
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Posts = int(in.Int())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Images = int(in.Int())
+			}
+		case "objects":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Objects = int(in.Int())
+			}
+		case "bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Bytes = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto100(out *jwriter.Writer, in BackupSummary) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Run != "" {
+		const prefix string = ",\"run\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Run))
+	}
+	{
+		const prefix string = ",\"users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Users))
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		out.Int(int(in.Images))
+	}
+	{
+		const prefix string = ",\"objects\":"
+		out.RawString(prefix)
+		out.Int(int(in.Objects))
+	}
+	{
+		const prefix string = ",\"bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Bytes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BackupSummary) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto100(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BackupSummary) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto100(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BackupSummary) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto100(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BackupSummary) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto100(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto101(in *jlexer.Lexer, out *BackupRun) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Id = string(in.String())
+			}
+		case "incremental":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Incremental = bool(in.Bool())
+			}
+		case "started_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.StartedAt).UnmarshalJSON(data))
+				}
+			}
+		case "posts_watermark":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.PostsWatermark).UnmarshalJSON(data))
+				}
+			}
+		case "images_watermark":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ImageWatermark).UnmarshalJSON(data))
+				}
+			}
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Users = int(in.Int())
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Posts = int(in.Int())
+			}
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Images = int(in.Int())
+			}
+		case "objects":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Objects = int(in.Int())
+			}
+		case "bytes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Bytes = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto101(out *jwriter.Writer, in BackupRun) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Id))
+	}
+	{
+		const prefix string = ",\"incremental\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Incremental))
+	}
+	{
+		const prefix string = ",\"started_at\":"
+		out.RawString(prefix)
+		out.Raw((in.StartedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"posts_watermark\":"
+		out.RawString(prefix)
+		out.Raw((in.PostsWatermark).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"images_watermark\":"
+		out.RawString(prefix)
+		out.Raw((in.ImageWatermark).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"users\":"
+		out.RawString(prefix)
+		out.Int(int(in.Users))
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix)
+		out.Int(int(in.Images))
+	}
+	{
+		const prefix string = ",\"objects\":"
+		out.RawString(prefix)
+		out.Int(int(in.Objects))
+	}
+	{
+		const prefix string = ",\"bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Bytes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v BackupRun) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto101(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v BackupRun) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto101(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *BackupRun) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto101(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *BackupRun) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto101(l, v)
+}
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto102(in *jlexer.Lexer, out *BackupManifest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Version = int(in.Int())
+			}
+		case "runs":
+			if in.IsNull() {
+				in.Skip()
+				out.Runs = nil
+			} else {
+				in.Delim('[')
+				if out.Runs == nil {
+					if !in.IsDelim(']') {
+						out.Runs = make([]BackupRun, 0, 0)
+					} else {
+						out.Runs = []BackupRun{}
+					}
+				} else {
+					out.Runs = (out.Runs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v134 BackupRun
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v134).UnmarshalEasyJSON(in)
+					}
+					out.Runs = append(out.Runs, v134)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -579,128 +11831,140 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(out *jwriter.Writer, in LoginUserResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto102(out *jwriter.Writer, in BackupManifest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"user_id\":"
+		const prefix string = ",\"version\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.Id).MarshalText())
-	}
-	{
-		const prefix string = ",\"access_token\":"
-		out.RawString(prefix)
-		out.String(string(in.AccessToken))
+		out.Int(int(in.Version))
 	}
 	{
-		const prefix string = ",\"refresh_token\":"
+		const prefix string = ",\"runs\":"
 		out.RawString(prefix)
-		out.String(string(in.RefreshToken))
+		if in.Runs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v135, v136 := range in.Runs {
+				if v135 > 0 {
+					out.RawByte(',')
+				}
+				(v136).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v LoginUserResponse) MarshalJSON() ([]byte, error) {
+func (v BackupManifest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto102(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v LoginUserResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto7(w, v)
+func (v BackupManifest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto102(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *LoginUserResponse) UnmarshalJSON(data []byte) error {
+func (v *BackupManifest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto102(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *LoginUserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto7(l, v)
+func (v *BackupManifest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto102(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(in *jlexer.Lexer, out *LoginUserRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto103(in *jlexer.Lexer, out *AuthorsResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
 		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		switch key {
-		case "email":
-			if in.IsNull() {
-				in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(AuthorsResponse, 0, 8)
 			} else {
-				out.Email = string(in.String())
+				*out = AuthorsResponse{}
 			}
-		case "password":
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v137 *AuthorResponse
 			if in.IsNull() {
 				in.Skip()
+				v137 = nil
 			} else {
-				out.Password = string(in.String())
+				if v137 == nil {
+					v137 = new(AuthorResponse)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*v137).UnmarshalEasyJSON(in)
+				}
 			}
-		default:
-			in.SkipRecursive()
+			*out = append(*out, v137)
+			in.WantComma()
 		}
-		in.WantComma()
+		in.Delim(']')
 	}
-	in.Delim('}')
 	if isTopLevel {
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(out *jwriter.Writer, in LoginUserRequest) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"email\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Email))
-	}
-	{
-		const prefix string = ",\"password\":"
-		out.RawString(prefix)
-		out.String(string(in.Password))
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto103(out *jwriter.Writer, in AuthorsResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v138, v139 := range in {
+			if v138 > 0 {
+				out.RawByte(',')
+			}
+			if v139 == nil {
+				out.RawString("null")
+			} else {
+				(*v139).MarshalEasyJSON(out)
+			}
+		}
+		out.RawByte(']')
 	}
-	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v LoginUserRequest) MarshalJSON() ([]byte, error) {
+func (v AuthorsResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto103(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v LoginUserRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto8(w, v)
+func (v AuthorsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto103(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *LoginUserRequest) UnmarshalJSON(data []byte) error {
+func (v *AuthorsResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto103(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *LoginUserRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto8(l, v)
+func (v *AuthorsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto103(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(in *jlexer.Lexer, out *GetPostResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto104(in *jlexer.Lexer, out *AuthorResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -714,80 +11978,31 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(in *jlexer.Lexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "post_id":
+		case "user_id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.PostId).UnmarshalText(data))
+					in.AddError((out.UserId).UnmarshalText(data))
 				}
 			}
-		case "author":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				(out.Author).UnmarshalEasyJSON(in)
-			}
-		case "title":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Title = string(in.String())
-			}
-		case "content":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Content = string(in.String())
-			}
-		case "status":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Status = types.PostStatus(in.String())
-			}
-		case "images":
+		case "display_name":
 			if in.IsNull() {
 				in.Skip()
-				out.Images = nil
 			} else {
-				in.Delim('[')
-				if out.Images == nil {
-					if !in.IsDelim(']') {
-						out.Images = make([]AddImageResponse, 0, 2)
-					} else {
-						out.Images = []AddImageResponse{}
-					}
-				} else {
-					out.Images = (out.Images)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v1 AddImageResponse
-					if in.IsNull() {
-						in.Skip()
-					} else {
-						(v1).UnmarshalEasyJSON(in)
-					}
-					out.Images = append(out.Images, v1)
-					in.WantComma()
-				}
-				in.Delim(']')
+				out.DisplayName = string(in.String())
 			}
-		case "created_at":
+		case "avatar_url":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.Raw(); in.Ok() {
-					in.AddError((out.CreatedAt).UnmarshalJSON(data))
-				}
+				out.AvatarUrl = string(in.String())
 			}
-		case "updated_at":
+		case "posts_count":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.Raw(); in.Ok() {
-					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
-				}
+				out.PostsCount = int(in.Int())
 			}
 		default:
 			in.SkipRecursive()
@@ -799,88 +12014,57 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(in *jlexer.Lexer
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(out *jwriter.Writer, in GetPostResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto104(out *jwriter.Writer, in AuthorResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"post_id\":"
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.PostId).MarshalText())
-	}
-	{
-		const prefix string = ",\"author\":"
-		out.RawString(prefix)
-		(in.Author).MarshalEasyJSON(out)
-	}
-	{
-		const prefix string = ",\"title\":"
-		out.RawString(prefix)
-		out.String(string(in.Title))
-	}
-	{
-		const prefix string = ",\"content\":"
-		out.RawString(prefix)
-		out.String(string(in.Content))
-	}
-	{
-		const prefix string = ",\"status\":"
-		out.RawString(prefix)
-		out.String(string(in.Status))
+		out.RawText((in.UserId).MarshalText())
 	}
 	{
-		const prefix string = ",\"images\":"
+		const prefix string = ",\"display_name\":"
 		out.RawString(prefix)
-		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v2, v3 := range in.Images {
-				if v2 > 0 {
-					out.RawByte(',')
-				}
-				(v3).MarshalEasyJSON(out)
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.DisplayName))
 	}
-	{
-		const prefix string = ",\"created_at\":"
+	if in.AvatarUrl != "" {
+		const prefix string = ",\"avatar_url\":"
 		out.RawString(prefix)
-		out.Raw((in.CreatedAt).MarshalJSON())
+		out.String(string(in.AvatarUrl))
 	}
 	{
-		const prefix string = ",\"updated_at\":"
+		const prefix string = ",\"posts_count\":"
 		out.RawString(prefix)
-		out.Raw((in.UpdatedAt).MarshalJSON())
+		out.Int(int(in.PostsCount))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v GetPostResponse) MarshalJSON() ([]byte, error) {
+func (v AuthorResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto104(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v GetPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto9(w, v)
+func (v AuthorResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto104(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *GetPostResponse) UnmarshalJSON(data []byte) error {
+func (v *AuthorResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto104(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *GetPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto9(l, v)
+func (v *AuthorResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto104(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(in *jlexer.Lexer, out *EditPostResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto105(in *jlexer.Lexer, out *AuditEntryResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -894,45 +12078,63 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(in *jlexer.Lexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "post_id":
+		case "audit_id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.PostId).UnmarshalText(data))
+					in.AddError((out.AuditId).UnmarshalText(data))
 				}
 			}
-		case "author_id":
+		case "user_id":
 			if in.IsNull() {
 				in.Skip()
+				out.UserId = nil
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.AuthorId).UnmarshalText(data))
+				if out.UserId == nil {
+					out.UserId = new(uuid.UUID)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((*out.UserId).UnmarshalText(data))
+					}
 				}
 			}
-		case "indempotency_key":
+		case "action":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.IdempotencyKey = string(in.String())
+				out.Action = types.AuditAction(in.String())
 			}
-		case "title":
+		case "target_id":
 			if in.IsNull() {
 				in.Skip()
+				out.TargetId = nil
 			} else {
-				out.Title = string(in.String())
+				if out.TargetId == nil {
+					out.TargetId = new(uuid.UUID)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((*out.TargetId).UnmarshalText(data))
+					}
+				}
 			}
-		case "content":
+		case "ip":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Content = string(in.String())
+				out.IP = string(in.String())
 			}
-		case "status":
+		case "user_agent":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Status = types.PostStatus(in.String())
+				out.UserAgent = string(in.String())
 			}
 		case "created_at":
 			if in.IsNull() {
@@ -942,14 +12144,6 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(in *jlexer.Lexe
 					in.AddError((out.CreatedAt).UnmarshalJSON(data))
 				}
 			}
-		case "updated_at":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				if data := in.Raw(); in.Ok() {
-					in.AddError((out.UpdatedAt).UnmarshalJSON(data))
-				}
-			}
 		default:
 			in.SkipRecursive()
 		}
@@ -960,77 +12154,72 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(in *jlexer.Lexe
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(out *jwriter.Writer, in EditPostResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto105(out *jwriter.Writer, in AuditEntryResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"post_id\":"
+		const prefix string = ",\"audit_id\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.PostId).MarshalText())
+		out.RawText((in.AuditId).MarshalText())
 	}
-	{
-		const prefix string = ",\"author_id\":"
+	if in.UserId != nil {
+		const prefix string = ",\"user_id\":"
 		out.RawString(prefix)
-		out.RawText((in.AuthorId).MarshalText())
+		out.RawText((*in.UserId).MarshalText())
 	}
 	{
-		const prefix string = ",\"indempotency_key\":"
+		const prefix string = ",\"action\":"
 		out.RawString(prefix)
-		out.String(string(in.IdempotencyKey))
+		out.String(string(in.Action))
 	}
-	{
-		const prefix string = ",\"title\":"
+	if in.TargetId != nil {
+		const prefix string = ",\"target_id\":"
 		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.RawText((*in.TargetId).MarshalText())
 	}
 	{
-		const prefix string = ",\"content\":"
+		const prefix string = ",\"ip\":"
 		out.RawString(prefix)
-		out.String(string(in.Content))
+		out.String(string(in.IP))
 	}
 	{
-		const prefix string = ",\"status\":"
+		const prefix string = ",\"user_agent\":"
 		out.RawString(prefix)
-		out.String(string(in.Status))
+		out.String(string(in.UserAgent))
 	}
 	{
 		const prefix string = ",\"created_at\":"
 		out.RawString(prefix)
 		out.Raw((in.CreatedAt).MarshalJSON())
 	}
-	{
-		const prefix string = ",\"updated_at\":"
-		out.RawString(prefix)
-		out.Raw((in.UpdatedAt).MarshalJSON())
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v EditPostResponse) MarshalJSON() ([]byte, error) {
+func (v AuditEntryResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto105(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v EditPostResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto10(w, v)
+func (v AuditEntryResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto105(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *EditPostResponse) UnmarshalJSON(data []byte) error {
+func (v *AuditEntryResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto105(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *EditPostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto10(l, v)
+func (v *AuditEntryResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto105(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(in *jlexer.Lexer, out *EditPostRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto106(in *jlexer.Lexer, out *ApiKeyResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1044,17 +12233,86 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(in *jlexer.Lexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "title":
+		case "key_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.KeyId).UnmarshalText(data))
+				}
+			}
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]types.Scope, 0, 4)
+					} else {
+						out.Scopes = []types.Scope{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v140 types.Scope
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v140 = types.Scope(in.String())
+					}
+					out.Scopes = append(out.Scopes, v140)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "created_at":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Title = string(in.String())
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
 			}
-		case "content":
+		case "last_used_at":
 			if in.IsNull() {
 				in.Skip()
+				out.LastUsedAt = nil
 			} else {
-				out.Content = string(in.String())
+				if out.LastUsedAt == nil {
+					out.LastUsedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.LastUsedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiresAt = nil
+			} else {
+				if out.ExpiresAt == nil {
+					out.ExpiresAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.ExpiresAt).UnmarshalJSON(data))
+					}
+				}
 			}
 		default:
 			in.SkipRecursive()
@@ -1066,114 +12324,148 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(in *jlexer.Lexe
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(out *jwriter.Writer, in EditPostRequest) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto106(out *jwriter.Writer, in ApiKeyResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"key_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Title))
+		out.RawText((in.KeyId).MarshalText())
 	}
 	{
-		const prefix string = ",\"content\":"
+		const prefix string = ",\"name\":"
 		out.RawString(prefix)
-		out.String(string(in.Content))
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v141, v142 := range in.Scopes {
+				if v141 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v142))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.LastUsedAt != nil {
+		const prefix string = ",\"last_used_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastUsedAt).MarshalJSON())
+	}
+	if in.ExpiresAt != nil {
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiresAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v EditPostRequest) MarshalJSON() ([]byte, error) {
+func (v ApiKeyResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto106(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v EditPostRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto11(w, v)
+func (v ApiKeyResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto106(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *EditPostRequest) UnmarshalJSON(data []byte) error {
+func (v *ApiKeyResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto106(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *EditPostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto11(l, v)
+func (v *ApiKeyResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto106(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(in *jlexer.Lexer, out *DeleteImageResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto107(in *jlexer.Lexer, out *AddImagesResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
 		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		switch key {
-		case "image_id":
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(AddImagesResponse, 0, 1)
+			} else {
+				*out = AddImagesResponse{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v143 AddImageResult
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.ImageId).UnmarshalText(data))
-				}
+				(v143).UnmarshalEasyJSON(in)
 			}
-		default:
-			in.SkipRecursive()
+			*out = append(*out, v143)
+			in.WantComma()
 		}
-		in.WantComma()
+		in.Delim(']')
 	}
-	in.Delim('}')
 	if isTopLevel {
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(out *jwriter.Writer, in DeleteImageResponse) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"image_id\":"
-		out.RawString(prefix[1:])
-		out.RawText((in.ImageId).MarshalText())
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto107(out *jwriter.Writer, in AddImagesResponse) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v144, v145 := range in {
+			if v144 > 0 {
+				out.RawByte(',')
+			}
+			(v145).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
 	}
-	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v DeleteImageResponse) MarshalJSON() ([]byte, error) {
+func (v AddImagesResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto107(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v DeleteImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto12(w, v)
+func (v AddImagesResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto107(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *DeleteImageResponse) UnmarshalJSON(data []byte) error {
+func (v *AddImagesResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto107(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *DeleteImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto12(l, v)
+func (v *AddImagesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto107(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(in *jlexer.Lexer, out *CreatePostResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto108(in *jlexer.Lexer, out *AddImageResult) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1187,14 +12479,40 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(in *jlexer.Lexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "post_id":
+		case "filename":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.PostId).UnmarshalText(data))
+				out.Filename = string(in.String())
+			}
+		case "image_id":
+			if in.IsNull() {
+				in.Skip()
+				out.ImageId = nil
+			} else {
+				if out.ImageId == nil {
+					out.ImageId = new(uuid.UUID)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((*out.ImageId).UnmarshalText(data))
+					}
 				}
 			}
+		case "image_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImageUrl = string(in.String())
+			}
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1205,42 +12523,57 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(in *jlexer.Lexe
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(out *jwriter.Writer, in CreatePostResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto108(out *jwriter.Writer, in AddImageResult) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"post_id\":"
+		const prefix string = ",\"filename\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.PostId).MarshalText())
+		out.String(string(in.Filename))
+	}
+	if in.ImageId != nil {
+		const prefix string = ",\"image_id\":"
+		out.RawString(prefix)
+		out.RawText((*in.ImageId).MarshalText())
+	}
+	if in.ImageUrl != "" {
+		const prefix string = ",\"image_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ImageUrl))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v CreatePostResponse) MarshalJSON() ([]byte, error) {
+func (v AddImageResult) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto108(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v CreatePostResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto13(w, v)
+func (v AddImageResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto108(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *CreatePostResponse) UnmarshalJSON(data []byte) error {
+func (v *AddImageResult) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto108(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *CreatePostResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto13(l, v)
+func (v *AddImageResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto108(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(in *jlexer.Lexer, out *CreatePostRequest) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto109(in *jlexer.Lexer, out *AddImageResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1254,23 +12587,25 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(in *jlexer.Lexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "idempotency_key":
+		case "image_id":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.IdempotencyKey = string(in.String())
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((out.ImageId).UnmarshalText(data))
+				}
 			}
-		case "title":
+		case "image_url":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Title = string(in.String())
+				out.ImageUrl = string(in.String())
 			}
-		case "content":
+		case "caption":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Content = string(in.String())
+				out.Caption = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -1282,52 +12617,52 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(in *jlexer.Lexe
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(out *jwriter.Writer, in CreatePostRequest) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto109(out *jwriter.Writer, in AddImageResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"idempotency_key\":"
+		const prefix string = ",\"image_id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.IdempotencyKey))
+		out.RawText((in.ImageId).MarshalText())
 	}
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"image_url\":"
 		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.String(string(in.ImageUrl))
 	}
-	{
-		const prefix string = ",\"content\":"
+	if in.Caption != "" {
+		const prefix string = ",\"caption\":"
 		out.RawString(prefix)
-		out.String(string(in.Content))
+		out.String(string(in.Caption))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v CreatePostRequest) MarshalJSON() ([]byte, error) {
+func (v AddImageResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto109(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v CreatePostRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto14(w, v)
+func (v AddImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto109(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *CreatePostRequest) UnmarshalJSON(data []byte) error {
+func (v *AddImageResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto109(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *CreatePostRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto14(l, v)
+func (v *AddImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto109(l, v)
 }
-func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(in *jlexer.Lexer, out *AddImageResponse) {
+func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto110(in *jlexer.Lexer, out *AccountExport) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1341,19 +12676,73 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(in *jlexer.Lexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "image_id":
+		case "exported_at":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				if data := in.UnsafeBytes(); in.Ok() {
-					in.AddError((out.ImageId).UnmarshalText(data))
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ExportedAt).UnmarshalJSON(data))
 				}
 			}
-		case "image_url":
+		case "user":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.ImageUrl = string(in.String())
+				(out.User).UnmarshalEasyJSON(in)
+			}
+		case "likes":
+			if in.IsNull() {
+				in.Skip()
+				out.Likes = nil
+			} else {
+				in.Delim('[')
+				if out.Likes == nil {
+					if !in.IsDelim(']') {
+						out.Likes = make([]ExportedLike, 0, 1)
+					} else {
+						out.Likes = []ExportedLike{}
+					}
+				} else {
+					out.Likes = (out.Likes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v146 ExportedLike
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v146).UnmarshalEasyJSON(in)
+					}
+					out.Likes = append(out.Likes, v146)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]ExportedPost, 0, 0)
+					} else {
+						out.Posts = []ExportedPost{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v147 ExportedPost
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v147).UnmarshalEasyJSON(in)
+					}
+					out.Posts = append(out.Posts, v147)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -1365,43 +12754,75 @@ func easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(in *jlexer.Lexe
 		in.Consumed()
 	}
 }
-func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(out *jwriter.Writer, in AddImageResponse) {
+func easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto110(out *jwriter.Writer, in AccountExport) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"image_id\":"
+		const prefix string = ",\"exported_at\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.ImageId).MarshalText())
+		out.Raw((in.ExportedAt).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"image_url\":"
+		const prefix string = ",\"user\":"
 		out.RawString(prefix)
-		out.String(string(in.ImageUrl))
+		(in.User).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"likes\":"
+		out.RawString(prefix)
+		if in.Likes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v148, v149 := range in.Likes {
+				if v148 > 0 {
+					out.RawByte(',')
+				}
+				(v149).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v150, v151 := range in.Posts {
+				if v150 > 0 {
+					out.RawByte(',')
+				}
+				(v151).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v AddImageResponse) MarshalJSON() ([]byte, error) {
+func (v AccountExport) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(&w, v)
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto110(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v AddImageResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto15(w, v)
+func (v AccountExport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson56de76c1EncodeGithubComXkarasbBlogInternalCoreDto110(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *AddImageResponse) UnmarshalJSON(data []byte) error {
+func (v *AccountExport) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(&r, v)
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto110(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *AddImageResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto15(l, v)
+func (v *AccountExport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson56de76c1DecodeGithubComXkarasbBlogInternalCoreDto110(l, v)
 }
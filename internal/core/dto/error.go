@@ -0,0 +1,27 @@
+package dto
+
+// CodeEmailNotVerified is the code of a publish refused until the author verified their email
+const CodeEmailNotVerified = "email_not_verified"
+
+// CodeQuotaExceeded is the code of an upload that would take the author past their storage quota
+const CodeQuotaExceeded = "quota_exceeded"
+
+// CodeValidationFailed is the code of a body whose fields broke their rules, they are listed in fields
+const CodeValidationFailed = "validation_failed"
+
+// @Description	Error returned by the api
+type ErrorResponse struct {
+	Error string `json:"error"`
+	// Code names errors clients handle without parsing the message, most errors have none
+	Code string `json:"code,omitempty"`
+	// Fields are the fields of the body that broke a rule, only with code validation_failed
+	Fields []FieldError `json:"fields,omitempty"`
+} //	@name	ErrorResponse
+
+// @Description	A field of the body that broke one of its rules
+type FieldError struct {
+	// Field is the json name, inside lists with the index, e.g. tags[2]
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Message string `json:"message" example:"must be a valid email"`
+} //	@name	FieldError
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// @Description	Every live post of an author with its images, as GET /posts/export streams it
+type PostsExport struct {
+	AuthorId   uuid.UUID      `json:"author_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Posts      []ExportedPost `json:"posts"`
+} //	@name	PostsExport
+
+// @Description	Post of an export with its Markdown content and its images in their order
+type ExportedPost struct {
+	PostId    uuid.UUID        `json:"post_id"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
+	Excerpt   string           `json:"excerpt,omitempty"`
+	Status    types.PostStatus `json:"status"`
+	Tags      []string         `json:"tags"`
+	PublishAt *time.Time       `json:"publish_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Images    []ImageResponse  `json:"images"`
+} //	@name	ExportedPost
+
+// PostsImport reads the posts of an imported JSON export one at a time, so a malformed one fails alone
+type PostsImport struct {
+	Posts []easyjson.RawMessage `json:"posts"`
+}
+
+// ImportedPost is a post read from an export to be added as a draft. Hash is taken from the title and
+// content it came with, Images are the files bundled with it already put into the storage.
+//
+//easyjson:skip
+type ImportedPost struct {
+	IdempotencyKey string
+	Title          string
+	Content        string
+	Excerpt        string
+	Tags           []string
+	Hash           string
+	Stats          *ReadingStats
+	Images         []ImportedImage
+}
+
+//easyjson:skip
+type ImportedImage struct {
+	ImageId   uuid.UUID
+	ImageUrl  string
+	Caption   *string
+	SizeBytes int64
+}
+
+// PostImport is the outcome of one post of an import, Entry names it within the export. Err is nil
+// when it was added.
+//
+//easyjson:skip
+type PostImport struct {
+	Entry  string
+	PostId uuid.UUID
+	Err    error
+}
+
+// @Description	Outcome of an import, every post skipped is listed with the reason
+type ImportPostsResponse struct {
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Errors   []ImportError `json:"errors"`
+} //	@name	ImportPostsResponse
+
+// @Description	Post of an import that was skipped, entry is posts[<index>] of a JSON export or the directory of a zip
+type ImportError struct {
+	Entry string `json:"entry"`
+	Error string `json:"error"`
+} //	@name	ImportError
@@ -0,0 +1,7 @@
+package dto
+
+// @Description	Liveness or readiness state with per dependency results
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+} //	@name	HealthResponse
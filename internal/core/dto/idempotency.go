@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyDB is one row of idempotency_keys, a request sent with an Idempotency-Key and, once it
+// was answered, its response. Status is 0 while the request runs.
+//
+//easyjson:skip
+type IdempotencyKeyDB struct {
+	UserId       uuid.UUID `db:"user_id"`
+	Key          string    `db:"idempotency_key"`
+	Endpoint     string    `db:"endpoint"`
+	RequestHash  string    `db:"request_hash"`
+	Status       int       `db:"status"`
+	ContentType  string    `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
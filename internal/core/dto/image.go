@@ -11,14 +11,103 @@ type ImageDB struct {
 	ImageId   uuid.UUID `json:"image_id" db:"image_id"`
 	PostId    uuid.UUID `json:"post_id" db:"post_id"`
 	ImageUrl  string    `json:"image_url" db:"image_url"`
+	Position  int       `json:"position" db:"position"`
+	Caption   *string   `json:"caption" db:"caption"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// SizeBytes is what the upload counts against the storage quota of the author
+	SizeBytes int64 `json:"size_bytes" db:"size_bytes"`
+}
+
+//easyjson:skip
+type ImagePatch struct {
+	Caption  *string
+	Position *int
 }
 
 type AddImageResponse struct {
 	ImageId  uuid.UUID `json:"image_id" validate:"required,uuid"`
 	ImageUrl string    `json:"image_url" validate:"required,url"`
+	Caption  string    `json:"caption,omitempty"`
 } //	@name	AddImageResonse
 
+// @Description	Outcome of one file of a bulk upload, image_id and image_url when it was stored and error when it was not
+type AddImageResult struct {
+	Filename string     `json:"filename"`
+	ImageId  *uuid.UUID `json:"image_id,omitempty"`
+	ImageUrl string     `json:"image_url,omitempty"`
+	Error    string     `json:"error,omitempty"`
+} //	@name	AddImageResult
+
+// AddImagesResponse has a result for every file of a bulk upload, in the order they were sent
+//
+//easyjson:json
+type AddImagesResponse []AddImageResult
+
+// ImageUpload is the outcome of one file of a bulk upload, Image is nil when Err is set
+//
+//easyjson:skip
+type ImageUpload struct {
+	Filename string
+	Image    *AddImageResponse
+	Err      error
+}
+
+// @Description	Image of a post with its place among the images of the post, the time it was added and the time its file was last replaced
+type ImageResponse struct {
+	ImageId   uuid.UUID `json:"image_id"`
+	ImageUrl  string    `json:"image_url"`
+	Position  int       `json:"position"`
+	Caption   string    `json:"caption"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+} //	@name	Image
+
+// @Description	Caption and place of an image, the fields left out keep their value. An empty caption removes it,
+// @Description	a position moves the image there and the ones after it down, past the last it becomes the last.
+type UpdateImageRequest struct {
+	Caption  *string `json:"caption" validate:"omitnil,max=500"`
+	Position *int    `json:"position" validate:"omitnil,min=1"`
+} //	@name	UpdateImageRequest
+
+// @Description	Every image of the post once, in the order they are to be shown
+type ReorderImagesRequest struct {
+	ImageIds []uuid.UUID `json:"image_ids"`
+} //	@name	ReorderImagesRequest
+
+// @Description	Images of a post in their order
+type GetPostImagesResponse struct {
+	Images []ImageResponse `json:"images"`
+} //	@name	PostImages
+
 type DeleteImageResponse struct {
 	ImageId uuid.UUID `json:"image_id" validate:"required,uuid"`
 } //	@name	DeleteImageResonse
+
+// @Description	Stored object without an images row
+type OrphanImageResponse struct {
+	Name         string    `json:"name"`
+	LastModified time.Time `json:"last_modified"`
+} //	@name	OrphanImage
+
+// @Description	Outcome of a cleanup of orphaned images, with dry_run the orphans are only listed
+type CleanupImagesResponse struct {
+	DryRun  bool                  `json:"dry_run"`
+	Scanned int                   `json:"scanned"`
+	Orphans []OrphanImageResponse `json:"orphans"`
+} //	@name	CleanupImages
+
+// @Description	Bytes of images the user has stored over all their posts, limit_bytes is 0 when there is no quota
+type StorageQuotaResponse struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	LimitBytes int64 `json:"limit_bytes"`
+} //	@name	StorageQuotaResponse
+
+// @Description	Upload refused for the storage quota, with how much of it is left
+type QuotaExceededResponse struct {
+	Error          string `json:"error"`
+	Code           string `json:"code"`
+	UsedBytes      int64  `json:"used_bytes"`
+	LimitBytes     int64  `json:"limit_bytes"`
+	RemainingBytes int64  `json:"remaining_bytes"`
+} //	@name	QuotaExceededResponse
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/google/uuid"
+
+// @Description	Like state of a post after the request
+type LikeResponse struct {
+	PostId     uuid.UUID `json:"post_id"`
+	Liked      bool      `json:"liked"`
+	LikesCount int       `json:"likes_count"`
+} //	@name	LikeResponse
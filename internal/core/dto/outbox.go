@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// OutboxEventDB is one row of outbox_events, written in the transaction of the change it reports.
+// Seq orders the events, SentAt stays nil until the relay handed the event to the sink.
+//
+//easyjson:skip
+type OutboxEventDB struct {
+	EventId   uuid.UUID       `db:"event_id"`
+	Seq       int64           `db:"seq"`
+	PostId    uuid.UUID       `db:"post_id"`
+	Type      types.EventType `db:"type"`
+	Payload   []byte          `db:"payload"`
+	CreatedAt time.Time       `db:"created_at"`
+	SentAt    *time.Time      `db:"sent_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/xkarasb/blog/pkg/types"
 )
 
@@ -13,12 +14,24 @@ import (
 type PostDB struct {
 	PostId         uuid.UUID        `json:"post_id" db:"post_id"`
 	AuthorId       uuid.UUID        `json:"author_id" db:"author_id"`
-	IdempotencyKey string           `json:"indempotency_key" db:"idempotency_key"`
+	IdempotencyKey string           `json:"idempotency_key" db:"idempotency_key"`
 	Title          string           `json:"title" db:"title"`
 	Content        string           `json:"content" db:"content"`
+	Excerpt        *string          `json:"excerpt,omitempty" db:"excerpt"`
 	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
 	Status         types.PostStatus `json:"status" db:"status"`
+	PublishAt      *time.Time       `json:"publish_at,omitempty" db:"publish_at"`
+	DeletedAt      *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Tags, Views and LikesCount are filled only by queries that select those columns
+	Tags       pq.StringArray `json:"tags,omitempty" db:"tags"`
+	Views      int            `json:"views,omitempty" db:"views"`
+	LikesCount int            `json:"likes_count,omitempty" db:"likes_count"`
+	// WordCount and ReadingTimeMinutes are nil for posts written before they were counted
+	WordCount          *int `json:"word_count,omitempty" db:"word_count"`
+	ReadingTimeMinutes *int `json:"reading_time_minutes,omitempty" db:"reading_time_minutes"`
+	// ImportHash is set on imported posts, the hash of the title and content they came with
+	ImportHash *string `json:"-" db:"import_hash"`
 } //	@name	Post
 
 //easyjson:skip
@@ -27,53 +40,191 @@ type PostUserDB struct {
 	UserDB
 }
 
+//easyjson:skip
+type PostSearchDB struct {
+	PostUserDB
+	Rank float64 `db:"rank"`
+}
+
+// PostTrendingDB is a published post with its score among the trending posts, zero without recent views and likes
+//
+//easyjson:skip
+type PostTrendingDB struct {
+	PostUserDB
+	Score float64 `db:"score"`
+}
+
+// PostListOptions narrows and orders a listing of posts, zero values mean any tag and status, newest first
+//
+//easyjson:skip
+type PostListOptions struct {
+	Tag    string
+	Status types.PostStatus
+	Sort   types.PostSort
+	Order  types.SortOrder
+}
+
+// ReadingStats are the counts stored with the content of a post
+//
+//easyjson:skip
+type ReadingStats struct {
+	WordCount          int
+	ReadingTimeMinutes int
+}
+
+// PostPatch holds the columns an update writes, nil ones keep their current value
+//
+//easyjson:skip
+type PostPatch struct {
+	Title   *string
+	Content *string
+	// Excerpt set to an empty string goes back to the one taken from the content
+	Excerpt *string
+	Status  *types.PostStatus
+	// Stats go with a new Content
+	Stats *ReadingStats
+}
+
 type GetPostResponse struct {
-	PostId    uuid.UUID          `json:"post_id"`
-	Author    UserResponse       `json:"author"`
-	Title     string             `json:"title"`
-	Content   string             `json:"content"`
-	Status    types.PostStatus   `json:"status"`
-	Images    []AddImageResponse `json:"images"`
-	CreatedAt time.Time          `json:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at"`
+	PostId  uuid.UUID    `json:"post_id"`
+	Author  UserResponse `json:"author"`
+	Title   string       `json:"title"`
+	Excerpt string       `json:"excerpt"`
+	// Content is left out of listings unless they are asked for with ?full=true
+	Content string `json:"content,omitempty"`
+	// ContentHTML is Content rendered from Markdown and sanitized, left out with ?raw=true
+	ContentHTML string             `json:"content_html,omitempty"`
+	Status      types.PostStatus   `json:"status"`
+	Images      []AddImageResponse `json:"images"`
+	Tags        []string           `json:"tags"`
+	Views       int                `json:"views"`
+	LikesCount  int                `json:"likes_count"`
+	PublishAt   *time.Time         `json:"publish_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	// WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too
+	WordCount          int `json:"word_count"`
+	ReadingTimeMinutes int `json:"reading_time_minutes"`
 } //	@name	PostResponse
 
+// GetPostsResponse is a listing of posts, named so easyjson writes it without reflection
+//
+//easyjson:json
+type GetPostsResponse []*GetPostResponse
+
+// @Description	Post matching a search with its relevance, higher is better
+type SearchPostResponse struct {
+	GetPostResponse
+	Score float64 `json:"score"`
+} //	@name	SearchPostResponse
+
+// @Description	Page of search results ordered by relevance
+type SearchPostsResponse struct {
+	Posts  []SearchPostResponse `json:"posts"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+} //	@name	SearchPostsPage
+
+// @Description	Post trending with its score, recent views and likes weighted by their age. Posts without any follow newest first with a score of 0.
+type TrendingPostResponse struct {
+	GetPostResponse
+	Score float64 `json:"score"`
+} //	@name	TrendingPostResponse
+
+// TrendingPostsResponse is the ranking of trending posts, best first
+//
+//easyjson:json
+type TrendingPostsResponse []TrendingPostResponse
+
 // @Description	Request payload for creating a new post
 type CreatePostRequest struct {
 	IdempotencyKey string `json:"idempotency_key" validate:"required"`
 	Title          string `json:"title" validate:"required"`
 	Content        string `json:"content" validate:"required"`
+	// Excerpt is shown in listings instead of the content, without one they take the start of the content
+	Excerpt string `json:"excerpt,omitempty" validate:"max=300"`
+	// Tags are stored lowercase without duplicates
+	Tags []string `json:"tags,omitempty" validate:"max=10,dive,required,max=50"`
 } //	@name	CreatePostRequest
 
-// @Description	Response with ID of the created post
+// @Description	The created post with its details, the same as an edit answers
 type CreatePostResponse struct {
-	PostId uuid.UUID `json:"post_id"`
+	EditPostResponse
 } //	@name	CreatePostResponse
 
-// @Description	Request payload for editing a post
+// @Description	Request payload for editing a post, tags and excerpt replace the current ones and leaving them out keeps them.
+// @Description	An empty excerpt goes back to the one taken from the start of the content.
+// @Description	With expected_updated_at set the edit only applies to that version of the post, a newer one answers 409 with the post as it is now.
 type EditPostRequest struct {
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Title   string   `json:"title" validate:"required"`
+	Content string   `json:"content" validate:"required"`
+	Excerpt *string  `json:"excerpt,omitempty" validate:"omitnil,max=300"`
+	Tags    []string `json:"tags,omitempty" validate:"omitnil,max=10,dive,required,max=50"`
+	// ExpectedUpdatedAt is the updated_at of the post the edit was made on
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"`
 } //	@name	EditPostRequest
 
+// @Description	Request payload for a partial edit, only the fields present are changed and the others keep their value.
+// @Description	An empty title or content is rejected rather than taken for a missing one, an empty excerpt goes back to the one
+// @Description	taken from the start of the content. expected_updated_at works as in a full edit.
+type PatchPostRequest struct {
+	Title   *string  `json:"title,omitempty" validate:"omitnil,min=1"`
+	Content *string  `json:"content,omitempty" validate:"omitnil,min=1"`
+	Excerpt *string  `json:"excerpt,omitempty" validate:"omitnil,max=300"`
+	Tags    []string `json:"tags,omitempty" validate:"omitnil,max=10,dive,required,max=50"`
+	// ExpectedUpdatedAt is the updated_at of the post the edit was made on
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"`
+} //	@name	PatchPostRequest
+
 // @Description	Response with updated post details
 type EditPostResponse struct {
 	PostId         uuid.UUID        `json:"post_id"`
 	AuthorId       uuid.UUID        `json:"author_id"`
-	IdempotencyKey string           `json:"indempotency_key"`
+	IdempotencyKey string           `json:"idempotency_key"`
 	Title          string           `json:"title"`
 	Content        string           `json:"content"`
+	Excerpt        string           `json:"excerpt,omitempty"`
 	Status         types.PostStatus `json:"status"`
+	Tags           []string         `json:"tags"`
 	CreatedAt      time.Time        `json:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at"`
+	// WordCount leaves out code blocks, ReadingTimeMinutes counts their lines too
+	WordCount          int `json:"word_count"`
+	ReadingTimeMinutes int `json:"reading_time_minutes"`
+	// LegacyIdempotencyKey repeats IdempotencyKey under the misspelled name clients read before, deprecated
+	LegacyIdempotencyKey string `json:"indempotency_key"`
 } //	@name	PostDetails
 
-// @Description	Request to change post status (publish/unpublish)
+// @Description	Request to change post status (publish/unpublish/archive/schedule), publish_at is required for scheduled
 type PublishPostRequest struct {
-	Status types.PostStatus `json:"status" validate:"required,oneof=published draft"`
+	Status    types.PostStatus `json:"status" validate:"required,oneof=published draft archived scheduled"`
+	PublishAt *time.Time       `json:"publish_at,omitempty" validate:"required_if=Status scheduled"`
 } //	@name	UpdatePostStatusRequest
 
-// @Description	Response with ID of the published post
+// @Description	Response with ID and resulting status of the post
 type PublishPostResponse struct {
-	PostId uuid.UUID `json:"post_id"`
+	PostId    uuid.UUID        `json:"post_id"`
+	Status    types.PostStatus `json:"status"`
+	PublishAt *time.Time       `json:"publish_at,omitempty"`
 } //	@name	UpdatePostStatusResponse
+
+// @Description	Data of a post.published event of GET /posts/stream, the post has just been published
+type PublishedPostEvent struct {
+	PostId      uuid.UUID `json:"post_id"`
+	AuthorId    uuid.UUID `json:"author_id"`
+	Title       string    `json:"title"`
+	Tags        []string  `json:"tags"`
+	PublishedAt time.Time `json:"published_at"`
+} //	@name	PublishedPostEvent
+
+// @Description	Post moved to the trash, it can be restored until purge_at
+type TrashPostResponse struct {
+	PostId  uuid.UUID `json:"post_id"`
+	PurgeAt time.Time `json:"purge_at"`
+} //	@name	TrashPostResponse
+
+// @Description	Post taken back from the trash
+type RestorePostResponse struct {
+	PostId uuid.UUID        `json:"post_id"`
+	Status types.PostStatus `json:"status"`
+} //	@name	RestorePostResponse
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description	Link letting anyone holding it read the post whatever its status, until it expires or is revoked
+type PreviewTokenResponse struct {
+	PostId    uuid.UUID `json:"post_id"`
+	Token     string    `json:"token"`
+	Url       string    `json:"url" example:"/api/v1/public/preview/Vq3k..."`
+	ExpiresAt time.Time `json:"expires_at"`
+} //	@name	PreviewToken
+
+// @Description	Preview links of the post that stopped working
+type RevokePreviewTokensResponse struct {
+	PostId  uuid.UUID `json:"post_id"`
+	Revoked int       `json:"revoked"`
+} //	@name	RevokePreviewTokens
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description	Author of a public post, the email is never shown to anonymous visitors
+type PublicAuthorResponse struct {
+	UserId      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name" example:"alice"`
+	AvatarUrl   string    `json:"avatar_url,omitempty"`
+} //	@name	PublicAuthor
+
+// @Description	Published post as seen without signing in
+type PublicPostResponse struct {
+	PostId     uuid.UUID            `json:"post_id"`
+	Author     PublicAuthorResponse `json:"author"`
+	Title      string               `json:"title"`
+	Content    string               `json:"content"`
+	Images     []AddImageResponse   `json:"images"`
+	Tags       []string             `json:"tags"`
+	Views      int                  `json:"views"`
+	LikesCount int                  `json:"likes_count"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+} //	@name	PublicPost
+
+//easyjson:json
+type PublicPostsResponse []*PublicPostResponse
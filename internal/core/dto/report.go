@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// ReportDB is one row of post_reports, Resolution and the columns with it are set once an admin handled it
+//
+//easyjson:skip
+type ReportDB struct {
+	ReportId   uuid.UUID               `db:"report_id"`
+	PostId     uuid.UUID               `db:"post_id"`
+	UserId     uuid.UUID               `db:"user_id"`
+	Reason     types.ReportReason      `db:"reason"`
+	Comment    string                  `db:"comment"`
+	CreatedAt  time.Time               `db:"created_at"`
+	ResolvedAt *time.Time              `db:"resolved_at"`
+	ResolvedBy *uuid.UUID              `db:"resolved_by"`
+	Resolution *types.ModerationAction `db:"resolution"`
+}
+
+// ReportedPostDB is a post with the reports still waiting for an admin, counted by reason
+//
+//easyjson:skip
+type ReportedPostDB struct {
+	PostId          uuid.UUID        `db:"post_id"`
+	Title           string           `db:"title"`
+	AuthorId        uuid.UUID        `db:"author_id"`
+	Status          types.PostStatus `db:"status"`
+	Reports         int              `db:"reports"`
+	Spam            int              `db:"spam"`
+	Abuse           int              `db:"abuse"`
+	Copyright       int              `db:"copyright"`
+	Other           int              `db:"other"`
+	Comments        pq.StringArray   `db:"comments"`
+	FirstReportedAt time.Time        `db:"first_reported_at"`
+	LastReportedAt  time.Time        `db:"last_reported_at"`
+}
+
+// @Description	Report of a post as spam or abuse, a user reports a post once
+type ReportPostRequest struct {
+	Reason  types.ReportReason `json:"reason" validate:"required,oneof=spam abuse copyright other"`
+	Comment string             `json:"comment,omitempty" validate:"max=1000" example:"Links to a scam shop"`
+} //	@name	ReportPostRequest
+
+// @Description	Report of the user as it is kept, a repeated report returns the first one
+type ReportResponse struct {
+	ReportId  uuid.UUID          `json:"report_id"`
+	PostId    uuid.UUID          `json:"post_id"`
+	Reason    types.ReportReason `json:"reason"`
+	Comment   string             `json:"comment,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+} //	@name	Report
+
+// @Description	Open reports of a post by reason
+type ReportReasons struct {
+	Spam      int `json:"spam"`
+	Abuse     int `json:"abuse"`
+	Copyright int `json:"copyright"`
+	Other     int `json:"other"`
+} //	@name	ReportReasons
+
+// @Description	Post waiting for moderation with its open reports
+type ReportedPostResponse struct {
+	PostId          uuid.UUID        `json:"post_id"`
+	Title           string           `json:"title"`
+	AuthorId        uuid.UUID        `json:"author_id"`
+	Status          types.PostStatus `json:"status"`
+	Reports         int              `json:"reports"`
+	Reasons         ReportReasons    `json:"reasons"`
+	Comments        []string         `json:"comments"`
+	FirstReportedAt time.Time        `json:"first_reported_at"`
+	LastReportedAt  time.Time        `json:"last_reported_at"`
+} //	@name	ReportedPost
+
+// @Description	Page of the moderation queue, most reported posts first
+type GetReportsResponse struct {
+	Posts  []ReportedPostResponse `json:"posts"`
+	Total  int                    `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+} //	@name	ReportsPage
+
+// @Description	Decision on the open reports of a post, unpublish takes it back to draft
+type ResolveReportsRequest struct {
+	Action types.ModerationAction `json:"action" validate:"required,oneof=dismiss unpublish"`
+} //	@name	ResolveReportsRequest
+
+// @Description	Reports closed by the decision and the status the post has after it
+type ResolveReportsResponse struct {
+	PostId   uuid.UUID              `json:"post_id"`
+	Action   types.ModerationAction `json:"action"`
+	Resolved int                    `json:"resolved"`
+	Status   types.PostStatus       `json:"status"`
+} //	@name	ResolveReportsResponse
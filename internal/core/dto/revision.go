@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// PostRevisionDB is a post as it was before an edit, EditedAt and EditedBy describe that edit
+//
+//easyjson:skip
+type PostRevisionDB struct {
+	RevisionId uuid.UUID        `db:"revision_id"`
+	PostId     uuid.UUID        `db:"post_id"`
+	Title      string           `db:"title"`
+	Content    string           `db:"content"`
+	Status     types.PostStatus `db:"status"`
+	EditedAt   time.Time        `db:"edited_at"`
+	EditedBy   uuid.UUID        `db:"edited_by"`
+}
+
+// @Description	Post as it was before an edit made at edited_at by edited_by
+type PostRevisionResponse struct {
+	RevisionId uuid.UUID        `json:"revision_id"`
+	Title      string           `json:"title"`
+	Content    string           `json:"content"`
+	Status     types.PostStatus `json:"status"`
+	EditedAt   time.Time        `json:"edited_at"`
+	EditedBy   uuid.UUID        `json:"edited_by"`
+} //	@name	PostRevision
+
+// @Description	Page of revisions of a post, latest first
+type GetPostRevisionsResponse struct {
+	Revisions []PostRevisionResponse `json:"revisions"`
+	Total     int                    `json:"total"`
+	Limit     int                    `json:"limit"`
+	Offset    int                    `json:"offset"`
+} //	@name	PostRevisionsPage
@@ -0,0 +1,12 @@
+package dto
+
+// SeedSummary is reported after a seed run, what existed before is counted apart from what it created
+type SeedSummary struct {
+	// Password logs in every seeded user
+	Password      string `json:"password"`
+	UsersCreated  int    `json:"users_created"`
+	UsersExisting int    `json:"users_existing"`
+	PostsCreated  int    `json:"posts_created"`
+	PostsExisting int    `json:"posts_existing"`
+	ImagesCreated int    `json:"images_created"`
+}
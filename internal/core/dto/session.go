@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionDB is one row of sessions, a login on one device. Only the hash of its refresh token is stored.
+//
+//easyjson:skip
+type SessionDB struct {
+	SessionId  uuid.UUID `db:"session_id"`
+	UserId     uuid.UUID `db:"user_id"`
+	TokenHash  string    `db:"token_hash"`
+	UserAgent  string    `db:"user_agent"`
+	IP         string    `db:"ip"`
+	CreatedAt  time.Time `db:"created_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+	LastUsedAt time.Time `db:"last_used_at"`
+}
+
+// @Description	Device the user is logged in on, current is the one the request came from
+type SessionResponse struct {
+	SessionId  uuid.UUID `json:"session_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+} //	@name	Session
+
+// @Description	Sessions of the user, the one used last first
+type GetSessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+} //	@name	Sessions
+
+// @Description	Number of sessions logged out
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+} //	@name	RevokedSessions
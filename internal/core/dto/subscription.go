@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// SubscriptionDB is an author a reader follows, with the email of the author
+//
+//easyjson:skip
+type SubscriptionDB struct {
+	AuthorId    uuid.UUID `db:"author_id"`
+	Email       string    `db:"email"`
+	DisplayName *string   `db:"display_name"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// NotificationDB is one row of notifications with the title and author of its post
+//
+//easyjson:skip
+type NotificationDB struct {
+	NotificationId uuid.UUID              `db:"notification_id"`
+	UserId         uuid.UUID              `db:"user_id"`
+	PostId         uuid.UUID              `db:"post_id"`
+	Type           types.NotificationType `db:"type"`
+	CreatedAt      time.Time              `db:"created_at"`
+	ReadAt         *time.Time             `db:"read_at"`
+	Title          string                 `db:"title"`
+	AuthorId       uuid.UUID              `db:"author_id"`
+	AuthorEmail    string                 `db:"author_email"`
+	// AuthorDisplayName is nil while the author has none
+	AuthorDisplayName *string `db:"author_display_name"`
+}
+
+// @Description	Subscription state of an author after the request
+type SubscribeResponse struct {
+	AuthorId         uuid.UUID `json:"author_id"`
+	Subscribed       bool      `json:"subscribed"`
+	SubscribersCount int       `json:"subscribers_count"`
+} //	@name	SubscribeResponse
+
+// @Description	Author you follow and since when
+type SubscriptionResponse struct {
+	Author       UserResponse `json:"author"`
+	SubscribedAt time.Time    `json:"subscribed_at"`
+} //	@name	Subscription
+
+// @Description	Page of the authors you follow, latest subscription first
+type SubscriptionsResponse struct {
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+	Total         int                    `json:"total"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+} //	@name	SubscriptionsPage
+
+// @Description	Post the notification is about
+type NotificationPostResponse struct {
+	PostId uuid.UUID    `json:"post_id"`
+	Title  string       `json:"title"`
+	Author UserResponse `json:"author"`
+} //	@name	NotificationPost
+
+// @Description	Notification of a post published by an author you follow, read_at is null until it is marked read
+type NotificationResponse struct {
+	NotificationId uuid.UUID                `json:"notification_id"`
+	Type           types.NotificationType   `json:"type"`
+	Post           NotificationPostResponse `json:"post"`
+	CreatedAt      time.Time                `json:"created_at"`
+	ReadAt         *time.Time               `json:"read_at"`
+} //	@name	Notification
+
+// @Description	Page of your notifications, latest first, with the number of unread ones overall
+type NotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+	Unread        int                    `json:"unread"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+} //	@name	NotificationsPage
+
+// @Description	Notifications to mark read, an empty list or no body marks every notification read
+type MarkNotificationsReadRequest struct {
+	NotificationIds []uuid.UUID `json:"notification_ids" validate:"max=100"`
+} //	@name	MarkNotificationsReadRequest
+
+// @Description	Number of notifications that were unread before the request
+type MarkNotificationsReadResponse struct {
+	Marked int `json:"marked"`
+} //	@name	MarkNotificationsReadResponse
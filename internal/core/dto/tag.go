@@ -0,0 +1,18 @@
+package dto
+
+// @Description	Tag database model with the number of posts using it
+//
+//easyjson:skip
+type TagDB struct {
+	Name  string `db:"name"`
+	Count int    `db:"count"`
+}
+
+// @Description	Tag with the number of published posts using it
+type TagResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+} //	@name	Tag
+
+//easyjson:json
+type TagsResponse []*TagResponse
@@ -1,25 +1,75 @@
 package dto
 
 import (
-	"time"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/xkarasb/blog/pkg/types"
 )
 
+// DeletedUserId is the user posts of deleted accounts are handed over to, the migrations add it
+var DeletedUserId = uuid.MustParse("00000000-0000-0000-0000-00000000dead")
+
 // easyjson:skip
 //
 //	@Description	UserDB represent user from data base
+//
+// Credentials are never serialized, use UserResponse to send a user to clients
 type UserDB struct {
-	UserId                 uuid.UUID  `json:"user_id" db:"user_id"`
-	Email                  string     `json:"email" db:"email"`
-	PasswordHash           string     `db:"password_hash"`
-	Role                   types.Role `json:"role" db:"role"`
-	RefreshToken           string     `json:"refresh_token" db:"refresh_token"`
-	RefreshTokenExpiryTime time.Time  `db:"refresh_token_expiry_time"`
+	UserId       uuid.UUID  `json:"user_id" db:"user_id"`
+	Email        string     `json:"email" db:"email"`
+	PasswordHash string     `json:"-" db:"password_hash"`
+	Role         types.Role `json:"role" db:"role"`
+	// DisplayName is nil until the user picks one, others see a masked email meanwhile
+	DisplayName *string `json:"display_name,omitempty" db:"display_name"`
+	// AvatarUrl is what the storage returned for the avatar, nil without one
+	AvatarUrl *string `json:"-" db:"avatar_url"`
+	// EmailVerified is set by the link mailed at registration, authors publish only after it
+	EmailVerified bool `json:"email_verified" db:"email_verified"`
 } //	@name	UserDB
 
+// LogValue keeps the credentials out of logs, a text handler would print every field otherwise
+func (u UserDB) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", u.UserId.String()),
+		slog.String("email", u.Email),
+		slog.String("role", string(u.Role)),
+	)
+}
+
+// Name is what others see the user by
+func (u UserDB) Name() string {
+	return DisplayName(u.DisplayName, u.Email)
+}
+
+// DisplayName is the display name a user picked or, without one, their email with all but the first
+// letter before the @ masked, so it never gives the address away
+func DisplayName(displayName *string, email string) string {
+	if displayName != nil && *displayName != "" {
+		return *displayName
+	}
+	local, domain, _ := strings.Cut(email, "@")
+	if local == "" {
+		return "***"
+	}
+	_, size := utf8.DecodeRuneInString(local)
+	return local[:size] + "***@" + domain
+}
+
+// @Description	Public user profile, the email is only shown to the user themself and to admins
 type UserResponse struct {
-	UserId uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserId      uuid.UUID  `json:"user_id"`
+	DisplayName string     `json:"display_name" example:"Alice"`
+	AvatarUrl   string     `json:"avatar_url,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	Role        types.Role `json:"role,omitempty"`
+	// EmailVerified is only shown to the user themself
+	EmailVerified bool `json:"email_verified,omitempty"`
 } //	@name	UserResponse
+
+// @Description	Changes of the profile of the current user, fields left out stay as they are. An empty display_name goes back to the masked email
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name,omitempty" validate:"omitnil,max=50" example:"Alice"`
+} //	@name	UpdateProfileRequest
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestUserDB_MarshalHidesCredentials(t *testing.T) {
+	user := UserDB{
+		UserId:       uuid.New(),
+		Email:        "user@example.com",
+		PasswordHash: "secret_password_hash",
+		Role:         types.Reader,
+	}
+
+	data, err := json.Marshal(user)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), user.PasswordHash)
+	assert.NotContains(t, string(data), "password_hash")
+	assert.Contains(t, string(data), user.Email)
+}
+
+func TestUserDB_LogValueHidesCredentials(t *testing.T) {
+	user := &UserDB{
+		UserId:       uuid.New(),
+		Email:        "user@example.com",
+		PasswordHash: "secret_password_hash",
+		Role:         types.Reader,
+	}
+
+	buf := &bytes.Buffer{}
+	// a text handler formats structs with every field, unlike the JSON one
+	slog.New(slog.NewTextHandler(buf, nil)).Info("login", slog.Any("user", user), slog.Any("copy", *user))
+
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Contains(t, buf.String(), "user.email="+user.Email)
+	assert.Contains(t, buf.String(), "copy.user_id="+user.UserId.String())
+}
+
+func TestDisplayName(t *testing.T) {
+	name := "Alice"
+	empty := ""
+	tests := []struct {
+		displayName *string
+		email       string
+		expected    string
+	}{
+		{&name, "alice@example.com", "Alice"},
+		{nil, "alice@example.com", "a***@example.com"},
+		{&empty, "bob@example.com", "b***@example.com"},
+		{nil, "élodie@example.com", "é***@example.com"},
+		{nil, "", "***"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, DisplayName(tt.displayName, tt.email), tt.email)
+	}
+	assert.NotContains(t, UserDB{Email: "secret.name@example.com"}.Name(), "secret.name")
+}
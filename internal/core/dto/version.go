@@ -0,0 +1,9 @@
+package dto
+
+// @Description	Build of the running server, dev for what the build did not set
+type VersionResponse struct {
+	Version   string `json:"version" example:"v1.4.0"`
+	Commit    string `json:"commit" example:"5f06797"`
+	BuildDate string `json:"build_date" example:"2026-10-15T12:00:00Z"`
+	GoVersion string `json:"go_version" example:"go1.24.5"`
+} //	@name	VersionResponse
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostView is one reader opening a post on a day, Day is midnight UTC
+//
+//easyjson:skip
+type PostView struct {
+	PostId uuid.UUID
+	UserId uuid.UUID
+	Day    time.Time
+}
+
+//easyjson:skip
+type ViewDayDB struct {
+	Day   time.Time `db:"day"`
+	Views int       `db:"views"`
+}
+
+// @Description	Distinct readers of a post on one day
+type ViewDayResponse struct {
+	Day   string `json:"day" example:"2025-01-31"`
+	Views int    `json:"views"`
+} //	@name	ViewDay
+
+// @Description	View statistics of a post, every reader counts once per day
+type PostStatsResponse struct {
+	PostId uuid.UUID         `json:"post_id"`
+	Views  int               `json:"views"`
+	Days   []ViewDayResponse `json:"days"`
+} //	@name	PostStats
@@ -0,0 +1,258 @@
+// Package jobs runs the periodic maintenance of the server, publishing due posts, emptying the trash and
+// the like, each on its own interval in the background.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Func is one run of a job, ctx is cancelled when the runner is stopped without waiting any longer
+type Func func(ctx context.Context) error
+
+// Clock schedules the runs, tests pass one they move by hand
+type Clock interface {
+	Now() time.Time
+	// NewTimer fires once on the channel after d, stop releases it before that
+	NewTimer(d time.Duration) (c <-chan time.Time, stop func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
+// Stats describes the runs of a job since the runner started
+type Stats struct {
+	Name     string
+	Interval time.Duration
+	Runs     uint64
+	Errors   uint64
+	// Skipped counts the ticks that came while the previous run was still going
+	Skipped      uint64
+	Running      bool
+	LastRun      time.Time
+	LastDuration time.Duration
+	// TotalDuration is the time spent in all runs, divided by Runs it is the average
+	TotalDuration time.Duration
+	// LastError is the error of the latest run, empty when it succeeded
+	LastError string
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Func
+	running  atomic.Bool
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Runner runs every registered job on its interval. The first run of a job comes after a random part of
+// its interval, so jobs registered together do not all hit the database at once. A job is never run
+// twice at the same time, a tick that comes while it runs is skipped. A panic fails only that run.
+type Runner struct {
+	clock Clock
+	// jitter is the delay before the first run of a job
+	jitter func(interval time.Duration) time.Duration
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	stopped bool
+	// stop ends the scheduling, cancel the runs
+	stop   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	loops  sync.WaitGroup
+	runs   sync.WaitGroup
+}
+
+// New makes a runner on clock, nil runs it on the wall clock
+func New(clock Clock) *Runner {
+	if clock == nil {
+		clock = realClock{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		clock:  clock,
+		jitter: randomJitter,
+		jobs:   map[string]*job{},
+		stop:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func randomJitter(interval time.Duration) time.Duration {
+	return time.Duration(rand.Int64N(int64(interval)))
+}
+
+// Register adds a job run every interval, a non-positive interval leaves it registered but never run.
+// A job registered after Start is scheduled right away. Names must be unique.
+func (r *Runner) Register(name string, interval time.Duration, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[name]; ok {
+		panic(fmt.Sprintf("jobs: %s registered twice", name))
+	}
+	j := &job{name: name, interval: interval, fn: fn, stats: Stats{Name: name, Interval: interval}}
+	r.jobs[name] = j
+	if r.started && !r.stopped {
+		r.schedule(j)
+	}
+}
+
+// Start schedules every registered job, it is a no-op when started or stopped already
+func (r *Runner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started || r.stopped {
+		return
+	}
+	r.started = true
+	for _, j := range r.jobs {
+		r.schedule(j)
+	}
+}
+
+// schedule starts the loop of a job, r.mu is held
+func (r *Runner) schedule(j *job) {
+	if j.interval <= 0 {
+		return
+	}
+	r.loops.Add(1)
+	go r.loop(j)
+}
+
+func (r *Runner) loop(j *job) {
+	defer r.loops.Done()
+
+	wait := r.jitter(j.interval)
+	for {
+		c, stop := r.clock.NewTimer(wait)
+		select {
+		case <-r.stop:
+			stop()
+			return
+		case <-c:
+		}
+		wait = j.interval
+		r.launch(j)
+	}
+}
+
+// launch runs the job in the background unless its previous run is still going
+func (r *Runner) launch(j *job) {
+	if !j.running.CompareAndSwap(false, true) {
+		j.mu.Lock()
+		j.stats.Skipped++
+		j.mu.Unlock()
+		slog.Debug("job still running, tick skipped", slog.String("job", j.name))
+		return
+	}
+	r.runs.Add(1)
+	go func() {
+		defer r.runs.Done()
+		defer j.running.Store(false)
+		r.run(j)
+	}()
+}
+
+func (r *Runner) run(j *job) {
+	start := r.clock.Now()
+	err := call(r.ctx, j)
+	duration := r.clock.Now().Sub(start)
+
+	j.mu.Lock()
+	j.stats.Runs++
+	j.stats.LastRun = start
+	j.stats.LastDuration = duration
+	j.stats.TotalDuration += duration
+	j.stats.LastError = ""
+	if err != nil {
+		j.stats.Errors++
+		j.stats.LastError = err.Error()
+	}
+	j.mu.Unlock()
+
+	if err != nil {
+		slog.Error("job failed", slog.String("job", j.name), slog.String("error", err.Error()))
+	}
+}
+
+// call runs the job once, a panic becomes its error
+func call(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("job panicked", slog.String("job", j.name), slog.Any("panic", p), slog.String("stack", string(debug.Stack())))
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return j.fn(ctx)
+}
+
+// Stop ends the scheduling and waits for the runs under way. When ctx is done first their context is
+// cancelled and Stop returns ctx.Err() without waiting for them any longer. It is safe to call more than
+// once or before Start.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.stop)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		// the loops launch the runs, none is added once they are gone
+		r.loops.Wait()
+		r.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats of every job ordered by name
+func (r *Runner) Stats() []Stats {
+	r.mu.Lock()
+	jobs := make([]*job, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		jobs = append(jobs, j)
+	}
+	r.mu.Unlock()
+
+	stats := make([]Stats, len(jobs))
+	for i, j := range jobs {
+		j.mu.Lock()
+		stats[i] = j.stats
+		j.mu.Unlock()
+		stats[i].Running = j.running.Load()
+	}
+	sort.Slice(stats, func(i, k int) bool { return stats[i].Name < stats[k].Name })
+	return stats
+}
@@ -0,0 +1,284 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock fires its timers only when the test moves it
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	c       chan time.Time
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	return timer.c, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		stopped := !timer.stopped
+		timer.stopped = true
+		return stopped
+	}
+}
+
+// Advance moves the clock by d and fires the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		switch {
+		case timer.stopped:
+		case !timer.at.After(c.now):
+			timer.c <- c.now
+		default:
+			pending = append(pending, timer)
+		}
+	}
+	c.timers = pending
+}
+
+// waitTimers waits until n timers are armed, the loops arm them from their own goroutines
+func (c *fakeClock) waitTimers(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		armed := 0
+		for _, timer := range c.timers {
+			if !timer.stopped {
+				armed++
+			}
+		}
+		return armed == n
+	}, time.Second, time.Millisecond)
+}
+
+func newTestRunner(clock Clock, jitter time.Duration) *Runner {
+	r := New(clock)
+	r.jitter = func(time.Duration) time.Duration { return jitter }
+	return r
+}
+
+func stats(t *testing.T, r *Runner, name string) Stats {
+	t.Helper()
+	for _, s := range r.Stats() {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no job %s", name)
+	return Stats{}
+}
+
+func TestRunner_RunsOnInterval(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, 10*time.Second)
+	runs := make(chan struct{}, 10)
+	r.Register("tick", time.Minute, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+	r.Start()
+	defer r.Stop(context.Background())
+
+	clock.waitTimers(t, 1)
+	clock.Advance(9 * time.Second)
+	assert.Empty(t, runs, "nothing runs before the jitter")
+
+	clock.Advance(time.Second)
+	<-runs
+	clock.waitTimers(t, 1)
+	clock.Advance(59 * time.Second)
+	assert.Empty(t, runs)
+	clock.Advance(time.Second)
+	<-runs
+
+	require.Eventually(t, func() bool { return stats(t, r, "tick").Runs == 2 }, time.Second, time.Millisecond)
+	s := stats(t, r, "tick")
+	assert.Equal(t, time.Minute, s.Interval)
+	assert.Zero(t, s.Errors)
+	assert.Equal(t, clock.Now(), s.LastRun)
+}
+
+func TestRunner_DisabledJob(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, 0)
+	r.Register("off", 0, func(ctx context.Context) error {
+		t.Error("a disabled job ran")
+		return nil
+	})
+	r.Start()
+
+	clock.Advance(time.Hour)
+	assert.NoError(t, r.Stop(context.Background()))
+	assert.Equal(t, []Stats{{Name: "off"}}, r.Stats())
+}
+
+func TestRunner_SkipsOverlappingRuns(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, time.Second)
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	r.Register("slow", time.Second, func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	r.Start()
+
+	clock.waitTimers(t, 1)
+	clock.Advance(time.Second)
+	<-started
+	for range 3 {
+		clock.waitTimers(t, 1)
+		clock.Advance(time.Second)
+	}
+	clock.waitTimers(t, 1)
+
+	s := stats(t, r, "slow")
+	assert.True(t, s.Running)
+	assert.Equal(t, uint64(3), s.Skipped)
+	assert.Zero(t, s.Runs, "the first run is not over yet")
+	assert.Empty(t, started)
+
+	close(release)
+	require.NoError(t, r.Stop(context.Background()))
+	s = stats(t, r, "slow")
+	assert.False(t, s.Running)
+	assert.Equal(t, uint64(1), s.Runs)
+}
+
+func TestRunner_RecordsErrorsAndPanics(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, time.Second)
+	done := make(chan struct{}, 2)
+	r.Register("failing", time.Second, func(ctx context.Context) error {
+		defer func() { done <- struct{}{} }()
+		return errors.New("database is down")
+	})
+	r.Register("panicking", time.Second, func(ctx context.Context) error {
+		defer func() { done <- struct{}{} }()
+		panic("boom")
+	})
+	r.Start()
+
+	clock.waitTimers(t, 2)
+	clock.Advance(time.Second)
+	<-done
+	<-done
+	require.NoError(t, r.Stop(context.Background()))
+
+	failing := stats(t, r, "failing")
+	assert.Equal(t, uint64(1), failing.Runs)
+	assert.Equal(t, uint64(1), failing.Errors)
+	assert.Equal(t, "database is down", failing.LastError)
+
+	panicking := stats(t, r, "panicking")
+	assert.Equal(t, uint64(1), panicking.Runs)
+	assert.Equal(t, uint64(1), panicking.Errors)
+	assert.Equal(t, "panic: boom", panicking.LastError)
+}
+
+func TestRunner_StopWaitsForRuns(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, time.Second)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var cancelled bool
+	r.Register("slow", time.Minute, func(ctx context.Context) error {
+		close(started)
+		<-release
+		cancelled = ctx.Err() != nil
+		return nil
+	})
+	r.Start()
+
+	clock.waitTimers(t, 1)
+	clock.Advance(time.Second)
+	<-started
+
+	stopped := make(chan error)
+	go func() { stopped <- r.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned with a run under way")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-stopped)
+	assert.False(t, cancelled, "a run that ends in time keeps its context")
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, uint64(1), stats(t, r, "slow").Runs, "nothing is scheduled after Stop")
+}
+
+func TestRunner_StopCancelsRunsOnDeadline(t *testing.T) {
+	clock := newFakeClock()
+	r := newTestRunner(clock, time.Second)
+	started := make(chan struct{})
+	ended := make(chan error, 1)
+	r.Register("stuck", time.Minute, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		ended <- ctx.Err()
+		return ctx.Err()
+	})
+	r.Start()
+
+	clock.waitTimers(t, 1)
+	clock.Advance(time.Second)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, r.Stop(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, <-ended, context.Canceled)
+}
+
+func TestRunner_StopWithoutStart(t *testing.T) {
+	r := New(nil)
+	r.Register("never", time.Minute, func(ctx context.Context) error { return nil })
+	assert.NoError(t, r.Stop(context.Background()))
+	assert.NoError(t, r.Stop(context.Background()))
+
+	r.Start()
+	assert.Equal(t, uint64(0), stats(t, r, "never").Runs)
+}
+
+func TestRunner_RegisterTwice(t *testing.T) {
+	r := New(nil)
+	r.Register("twice", time.Minute, func(ctx context.Context) error { return nil })
+	assert.Panics(t, func() {
+		r.Register("twice", time.Minute, func(ctx context.Context) error { return nil })
+	})
+}
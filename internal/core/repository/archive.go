@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	minIO "github.com/minio/minio-go/v7"
+	"github.com/xkarasb/blog/pkg/storage/minio"
+)
+
+// DirArchive keeps backup files in a local directory
+type DirArchive struct {
+	root string
+}
+
+func NewDirArchive(root string) (*DirArchive, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirArchive{root}, nil
+}
+
+func (a *DirArchive) Create(name string) (io.WriteCloser, error) {
+	fullPath := filepath.Join(a.root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return nil, err
+	}
+	// write next to the target and rename on close so a crashed run never leaves a half-written file behind
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), filepath.Base(fullPath)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{tmp, fullPath}, nil
+}
+
+func (a *DirArchive) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(a.root, filepath.FromSlash(name)))
+}
+
+type atomicFile struct {
+	*os.File
+	target string
+}
+
+func (f *atomicFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), f.target)
+}
+
+// S3Archive keeps backup files in an S3 compatible bucket reachable by the MinIO client
+type S3Archive struct {
+	storage *minio.MinIOClient
+	bucket  string
+	prefix  string
+}
+
+// 5 MiB is the smallest part S3 accepts, it bounds memory used by streaming uploads of unknown size
+const archivePartSize = 5 * 1024 * 1024
+
+// NewS3Archive accepts a location like s3://bucket/some/prefix
+func NewS3Archive(storage *minio.MinIOClient, location string) (*S3Archive, error) {
+	rest, ok := strings.CutPrefix(location, "s3://")
+	if !ok {
+		return nil, fmt.Errorf("invalid s3 location %q", location)
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid s3 location %q", location)
+	}
+
+	ctx := context.Background()
+	exists, err := storage.Client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := storage.Client.MakeBucket(ctx, bucket, minIO.MakeBucketOptions{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Archive{storage, bucket, strings.Trim(prefix, "/")}, nil
+}
+
+func (a *S3Archive) key(name string) string {
+	return path.Join(a.prefix, name)
+}
+
+func (a *S3Archive) Create(name string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	w := &s3Writer{pw: pw, done: make(chan error, 1)}
+
+	go func() {
+		_, err := a.storage.Client.PutObject(context.Background(), a.bucket, a.key(name), pr, -1, minIO.PutObjectOptions{
+			PartSize: archivePartSize,
+		})
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	return w, nil
+}
+
+func (a *S3Archive) Open(name string) (io.ReadCloser, error) {
+	ctx := context.Background()
+	if _, err := a.storage.Client.StatObject(ctx, a.bucket, a.key(name), minIO.StatObjectOptions{}); err != nil {
+		if minIO.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	return a.storage.Client.GetObject(ctx, a.bucket, a.key(name), minIO.GetObjectOptions{})
+}
+
+type s3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
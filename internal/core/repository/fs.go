@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+// MediaPrefix is where the server serves the files of an FSRepository
+const MediaPrefix = "/media/"
+
+// FSRepository keeps images as files of one directory for deployments without MinIO.
+// Links of a private one carry an expiry and a signature made with secret.
+type FSRepository struct {
+	root   string
+	public bool
+	secret []byte
+	clock  func() time.Time
+}
+
+func NewFSRepository(root string, public bool, secret string) (*FSRepository, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &FSRepository{root, public, []byte(secret), time.Now}, nil
+}
+
+// path maps an object name to its file, a name that could leave the directory does not exist.
+// Avatars are the only objects kept in a directory of their own.
+func (rep *FSRepository) path(objectName string) (string, error) {
+	dir := rep.root
+	if name, ok := strings.CutPrefix(objectName, storage.AvatarPrefix); ok {
+		dir, objectName = filepath.Join(rep.root, strings.TrimSuffix(storage.AvatarPrefix, "/")), name
+	}
+	if objectName == "" || strings.HasPrefix(objectName, ".") || strings.ContainsAny(objectName, `/\`) {
+		return "", os.ErrNotExist
+	}
+	return filepath.Join(dir, objectName), nil
+}
+
+func (rep *FSRepository) PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	fullPath, err := rep.path(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", storageError(ctx, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", err
+	}
+
+	// write next to the target and rename so a failed upload never leaves half an image behind
+	tmp, err := os.CreateTemp(rep.root, ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, &ctxReader{ctx, file}); err != nil {
+		tmp.Close()
+		return "", storageError(ctx, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return "", err
+	}
+
+	if !rep.public {
+		return objectName, nil
+	}
+	return MediaPrefix + objectName, nil
+}
+
+func (rep *FSRepository) DeleteImage(ctx context.Context, objectName string) error {
+	fullPath, err := rep.path(objectName)
+	if err != nil {
+		return nil
+	}
+	// removing what is already gone succeeds like it does in MinIO
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetImage opens the file of an image, its content type follows the extension
+func (rep *FSRepository) GetImage(objectName string) (io.ReadCloser, string, error) {
+	fullPath, err := rep.path(objectName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(objectName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return file, contentType, nil
+}
+
+func (rep *FSRepository) ImageExists(objectName string) (bool, error) {
+	fullPath, err := rep.path(objectName)
+	if err != nil {
+		return false, nil
+	}
+
+	_, err = os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (rep *FSRepository) Public() bool {
+	return rep.public
+}
+
+// PresignGet makes a media link that VerifyLink accepts until ttl runs out
+func (rep *FSRepository) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	expires := rep.clock().Add(ttl).Unix()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {rep.sign(objectName, expires)},
+	}
+	return fmt.Sprintf("%s%s?%s", MediaPrefix, url.PathEscape(objectName), query.Encode()), nil
+}
+
+// VerifyLink reports whether a request for the object may be served, every one may for a public storage
+func (rep *FSRepository) VerifyLink(objectName string, query url.Values) bool {
+	if rep.public {
+		return true
+	}
+
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || rep.clock().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(query.Get("signature")), []byte(rep.sign(objectName, expires)))
+}
+
+func (rep *FSRepository) sign(objectName string, expires int64) string {
+	mac := hmac.New(sha256.New, rep.secret)
+	fmt.Fprintf(mac, "%s\n%d", objectName, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ListImages lists the files of the directory, unfinished uploads are hidden like every dot file
+func (rep *FSRepository) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	entries, err := os.ReadDir(rep.root)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return storageError(ctx, err)
+		}
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := emit(storage.ImageObject{Name: entry.Name(), LastModified: info.ModTime()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ctxReader stops a copy once its ctx is done
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+func TestFSRepository_Images(t *testing.T) {
+	tests := []struct {
+		name     string
+		public   bool
+		expected string
+	}{
+		{name: "public", public: true, expected: "/media/pic.png"},
+		{name: "private", public: false, expected: "pic.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			repo, err := NewFSRepository(root, tt.public, "secret")
+			require.NoError(t, err)
+			assert.Equal(t, tt.public, repo.Public())
+
+			link, err := repo.PutImage(context.Background(), "pic.png", bytes.NewReader([]byte("png")), 3, "image/png")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, link)
+
+			exists, err := repo.ImageExists("pic.png")
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			file, contentType, err := repo.GetImage("pic.png")
+			require.NoError(t, err)
+			data, err := io.ReadAll(file)
+			file.Close()
+			require.NoError(t, err)
+			assert.Equal(t, "png", string(data))
+			assert.Equal(t, "image/png", contentType)
+
+			require.NoError(t, repo.DeleteImage(context.Background(), "pic.png"))
+			exists, err = repo.ImageExists("pic.png")
+			require.NoError(t, err)
+			assert.False(t, exists)
+			// deleting twice is fine like in MinIO
+			assert.NoError(t, repo.DeleteImage(context.Background(), "pic.png"))
+
+			// no temporary files are left behind
+			entries, err := os.ReadDir(root)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestFSRepository_NamesStayInRoot(t *testing.T) {
+	root := t.TempDir()
+	repo, err := NewFSRepository(filepath.Join(root, "media"), true, "secret")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "outside"), []byte("secret"), 0o644))
+
+	for _, name := range []string{"../outside", "..", ".", "", "a/b", `a\b`, ".upload-1", "avatars/", "avatars/../outside", "avatars/a/b"} {
+		_, err := repo.PutImage(context.Background(), name, strings.NewReader("x"), 1, "image/png")
+		assert.ErrorIs(t, err, os.ErrNotExist, name)
+		_, _, err = repo.GetImage(name)
+		assert.ErrorIs(t, err, os.ErrNotExist, name)
+		exists, err := repo.ImageExists(name)
+		assert.NoError(t, err)
+		assert.False(t, exists, name)
+	}
+}
+
+func TestFSRepository_Avatars(t *testing.T) {
+	root := t.TempDir()
+	repo, err := NewFSRepository(root, true, "secret")
+	require.NoError(t, err)
+
+	link, err := repo.PutImage(context.Background(), "avatars/me.png", strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "/media/avatars/me.png", link)
+	_, err = os.Stat(filepath.Join(root, "avatars", "me.png"))
+	require.NoError(t, err)
+
+	var names []string
+	require.NoError(t, repo.ListImages(context.Background(), func(obj storage.ImageObject) error {
+		names = append(names, obj.Name)
+		return nil
+	}))
+	assert.Empty(t, names, "avatars are no images of posts")
+
+	require.NoError(t, repo.DeleteImage(context.Background(), "avatars/me.png"))
+	exists, err := repo.ImageExists("avatars/me.png")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestFSRepository_PresignedLinks(t *testing.T) {
+	now := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	repo, err := NewFSRepository(t.TempDir(), false, "secret")
+	require.NoError(t, err)
+	repo.clock = func() time.Time { return now }
+
+	link, err := repo.PresignGet("pic.png", time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/media/pic.png", parsed.Path)
+	query := parsed.Query()
+
+	assert.True(t, repo.VerifyLink("pic.png", query))
+	assert.False(t, repo.VerifyLink("other.png", query), "a signature is bound to its object")
+	assert.False(t, repo.VerifyLink("pic.png", url.Values{}))
+
+	tampered := url.Values{"expires": {"99999999999"}, "signature": query["signature"]}
+	assert.False(t, repo.VerifyLink("pic.png", tampered))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, repo.VerifyLink("pic.png", query), "the link expired")
+
+	other, err := NewFSRepository(t.TempDir(), false, "another secret")
+	require.NoError(t, err)
+	other.clock = repo.clock
+	assert.False(t, other.VerifyLink("pic.png", query))
+}
+
+func TestFSRepository_ListImages(t *testing.T) {
+	root := t.TempDir()
+	repo, err := NewFSRepository(root, true, "secret")
+	require.NoError(t, err)
+
+	_, err = repo.PutImage(context.Background(), "pic.png", strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+	modified := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "pic.png"), modified, modified))
+	// an upload in progress and a nested directory are not images
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".upload-1"), []byte("x"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "nested"), 0o755))
+
+	var listed []storage.ImageObject
+	err = repo.ListImages(context.Background(), func(obj storage.ImageObject) error {
+		listed = append(listed, obj)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "pic.png", listed[0].Name)
+	assert.True(t, modified.Equal(listed[0].LastModified))
+}
@@ -1,41 +1,214 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	minIO "github.com/minio/minio-go/v7"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/storage"
 	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// MinIOObjectClient is the part of the MinIO client the repository works with
+type MinIOObjectClient interface {
+	PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minIO.PutObjectOptions) (minIO.UploadInfo, error)
+	RemoveObject(ctx context.Context, bucketName, objectName string, opts minIO.RemoveObjectOptions) error
+	StatObject(ctx context.Context, bucketName, objectName string, opts minIO.StatObjectOptions) (minIO.ObjectInfo, error)
+	GetObject(ctx context.Context, bucketName, objectName string, opts minIO.GetObjectOptions) (*minIO.Object, error)
+	PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error)
+	ListObjects(ctx context.Context, bucketName string, opts minIO.ListObjectsOptions) <-chan minIO.ObjectInfo
+}
+
 type MinIORepository struct {
-	Storage *minio.MinIOClient
+	client MinIOObjectClient
+	bucket string
+	// public buckets are read directly, for a private one PutImage returns the object name to presign later
+	public bool
+	// timeout bounds every upload and removal on top of the caller's ctx, zero disables it
+	timeout time.Duration
+	// attempts is how often an upload or removal is tried when it fails transiently
+	attempts int
+	backoff  time.Duration
+	// sleep waits between attempts, it defaults to sleepContext
+	sleep   func(ctx context.Context, d time.Duration) error
+	retries atomic.Int64
 }
 
 func NewMinIORepository(storage *minio.MinIOClient) *MinIORepository {
-	return &MinIORepository{storage}
+	return &MinIORepository{
+		client:   storage.Client,
+		bucket:   storage.BucketName,
+		public:   storage.Public,
+		timeout:  storage.OpTimeout,
+		attempts: storage.MaxAttempts,
+		backoff:  retryBackoff,
+	}
 }
 
-func (rep *MinIORepository) PutImage(objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
-	info, err := rep.Storage.Client.PutObject(
-		context.Background(),
-		rep.Storage.BucketName,
-		objectName,
-		file,
-		fileSize,
-		minIO.PutObjectOptions{
+// Retries counts the attempts repeated after a transient failure since the start
+func (rep *MinIORepository) Retries() int64 {
+	return rep.retries.Load()
+}
+
+// retry runs op until it succeeds, fails for good or uses up the attempts, with a jittered
+// exponential backoff between the tries
+func (rep *MinIORepository) retry(ctx context.Context, name string, op func() error) error {
+	sleep := rep.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= rep.attempts || !retryable(ctx, err) {
+			return err
+		}
+
+		rep.retries.Add(1)
+		slog.Warn("retry image storage", slog.String("operation", name), slog.Int("attempt", attempt), slog.String("error", err.Error()))
+		if err := sleep(ctx, backoffDelay(rep.backoff, attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+func (rep *MinIORepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if rep.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, rep.timeout)
+}
+
+// startSpan traces one call to the storage, every retried attempt gets a span of its own
+func (rep *MinIORepository) startSpan(ctx context.Context, operation, objectName string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, tracerName, operation, semconv.AWSS3Bucket(rep.bucket), semconv.AWSS3Key(objectName))
+}
+
+// storageError tells an operation stopped by its ctx apart from one the storage refused
+func storageError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", errors.ErrorRepositoryStorageTimeout, err)
+	}
+	return err
+}
+
+func (rep *MinIORepository) PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	ctx, cancel := rep.withTimeout(ctx)
+	defer cancel()
+
+	// a stream can be sent only once, so only uploads small enough to hold in memory are retried
+	put := func(body io.Reader) (info minIO.UploadInfo, err error) {
+		ctx, span := rep.startSpan(ctx, "PutObject", objectName)
+		defer func() { tracing.End(span, err) }()
+		return rep.client.PutObject(ctx, rep.bucket, objectName, body, fileSize, minIO.PutObjectOptions{
 			ContentType: contentType,
-		},
-	)
+		})
+	}
+
+	var info minIO.UploadInfo
+	var err error
+	if fileSize >= 0 && fileSize <= retryBufferLimit && rep.attempts > 1 {
+		var data []byte
+		if data, err = io.ReadAll(io.LimitReader(file, fileSize)); err != nil {
+			return "", err
+		}
+		err = rep.retry(ctx, "put", func() error {
+			info, err = put(bytes.NewReader(data))
+			return err
+		})
+	} else {
+		info, err = put(file)
+	}
 
 	if err != nil {
-		return "", err
+		return "", storageError(ctx, err)
 	}
 
+	if !rep.public {
+		return objectName, nil
+	}
 	return fmt.Sprintf("/%s/%s", info.Bucket, objectName), nil
 }
 
-func (rep *MinIORepository) DeleteImage(objectName string) error {
-	return rep.Storage.Client.RemoveObject(context.Background(), rep.Storage.BucketName, objectName, minIO.RemoveObjectOptions{})
+func (rep *MinIORepository) Public() bool {
+	return rep.public
+}
+
+// PresignGet makes a link that reads the object without credentials until ttl runs out
+func (rep *MinIORepository) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	link, err := rep.client.PresignedGetObject(context.Background(), rep.bucket, objectName, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return link.String(), nil
+}
+
+func (rep *MinIORepository) DeleteImage(ctx context.Context, objectName string) error {
+	ctx, cancel := rep.withTimeout(ctx)
+	defer cancel()
+
+	err := rep.retry(ctx, "remove", func() (err error) {
+		ctx, span := rep.startSpan(ctx, "RemoveObject", objectName)
+		defer func() { tracing.End(span, err) }()
+		return rep.client.RemoveObject(ctx, rep.bucket, objectName, minIO.RemoveObjectOptions{})
+	})
+	if err != nil {
+		return storageError(ctx, err)
+	}
+	return nil
+}
+
+func (rep *MinIORepository) GetImage(objectName string) (io.ReadCloser, string, error) {
+	info, err := rep.client.StatObject(context.Background(), rep.bucket, objectName, minIO.StatObjectOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+
+	obj, err := rep.client.GetObject(context.Background(), rep.bucket, objectName, minIO.GetObjectOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	return obj, info.ContentType, nil
+}
+
+func (rep *MinIORepository) ImageExists(objectName string) (bool, error) {
+	_, err := rep.client.StatObject(context.Background(), rep.bucket, objectName, minIO.StatObjectOptions{})
+	if err != nil {
+		if minIO.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (rep *MinIORepository) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	// cancelling stops the listing goroutine of the client when emit fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range rep.client.ListObjects(ctx, rep.bucket, minIO.ListObjectsOptions{}) {
+		if obj.Err != nil {
+			return storageError(ctx, obj.Err)
+		}
+		// a prefix holds nested objects, images are never nested
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if err := emit(storage.ImageObject{Name: obj.Key, LastModified: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	minIO "github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+type fakeMinIOClient struct {
+	objects map[string][]byte
+}
+
+func (c *fakeMinIOClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minIO.PutObjectOptions) (minIO.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minIO.UploadInfo{}, err
+	}
+	c.objects[objectName] = data
+	return minIO.UploadInfo{Bucket: bucketName, Key: objectName, Size: int64(len(data))}, nil
+}
+
+func (c *fakeMinIOClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minIO.RemoveObjectOptions) error {
+	delete(c.objects, objectName)
+	return nil
+}
+
+func (c *fakeMinIOClient) StatObject(ctx context.Context, bucketName, objectName string, opts minIO.StatObjectOptions) (minIO.ObjectInfo, error) {
+	if _, ok := c.objects[objectName]; !ok {
+		return minIO.ObjectInfo{}, minIO.ErrorResponse{Code: "NoSuchKey"}
+	}
+	return minIO.ObjectInfo{Key: objectName}, nil
+}
+
+func (c *fakeMinIOClient) GetObject(ctx context.Context, bucketName, objectName string, opts minIO.GetObjectOptions) (*minIO.Object, error) {
+	return nil, minIO.ErrorResponse{Code: "NotImplemented"}
+}
+
+// ListObjects lists a key with a slash as its prefix like a listing that is not recursive
+func (c *fakeMinIOClient) ListObjects(ctx context.Context, bucketName string, opts minIO.ListObjectsOptions) <-chan minIO.ObjectInfo {
+	objects := make(chan minIO.ObjectInfo, len(c.objects))
+	for _, key := range slices.Sorted(maps.Keys(c.objects)) {
+		if prefix, _, nested := strings.Cut(key, "/"); nested {
+			objects <- minIO.ObjectInfo{Key: prefix + "/"}
+			continue
+		}
+		objects <- minIO.ObjectInfo{Key: key, Size: int64(len(c.objects[key]))}
+	}
+	close(objects)
+	return objects
+}
+
+func (c *fakeMinIOClient) PresignedGetObject(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values) (*url.URL, error) {
+	return &url.URL{
+		Scheme:   "http",
+		Host:     "minio:9000",
+		Path:     "/" + bucketName + "/" + objectName,
+		RawQuery: url.Values{"X-Amz-Expires": {expires.String()}}.Encode(),
+	}, nil
+}
+
+func TestMinIORepository_PutImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		public   bool
+		expected string
+	}{
+		{name: "public bucket", public: true, expected: "/images/pic"},
+		{name: "private bucket", public: false, expected: "pic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeMinIOClient{objects: map[string][]byte{}}
+			repo := &MinIORepository{client: client, bucket: "images", public: tt.public}
+
+			link, err := repo.PutImage(context.Background(), "pic", bytes.NewReader([]byte("png")), 3, "image/png")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, link)
+			assert.Equal(t, []byte("png"), client.objects["pic"])
+		})
+	}
+}
+
+func TestMinIORepository_PresignGet(t *testing.T) {
+	repo := &MinIORepository{client: &fakeMinIOClient{objects: map[string][]byte{}}, bucket: "images"}
+
+	link, err := repo.PresignGet("pic", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "http://minio:9000/images/pic?X-Amz-Expires=15m0s", link)
+}
+
+func TestMinIORepository_ImageExists(t *testing.T) {
+	repo := &MinIORepository{client: &fakeMinIOClient{objects: map[string][]byte{"pic": []byte("png")}}, bucket: "images"}
+
+	exists, err := repo.ImageExists("pic")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.ImageExists("missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// blockingMinIOClient hangs every upload and removal until its ctx is done, like a stalled storage
+type blockingMinIOClient struct {
+	fakeMinIOClient
+}
+
+func (c *blockingMinIOClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minIO.PutObjectOptions) (minIO.UploadInfo, error) {
+	<-ctx.Done()
+	return minIO.UploadInfo{}, ctx.Err()
+}
+
+func (c *blockingMinIOClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minIO.RemoveObjectOptions) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestMinIORepository_Cancellation(t *testing.T) {
+	t.Run("operation timeout", func(t *testing.T) {
+		repo := &MinIORepository{client: &blockingMinIOClient{}, bucket: "images", timeout: 10 * time.Millisecond}
+
+		_, err := repo.PutImage(context.Background(), "pic", bytes.NewReader([]byte("png")), 3, "image/png")
+		assert.ErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = repo.DeleteImage(context.Background(), "pic")
+		assert.ErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+	})
+
+	t.Run("caller gone", func(t *testing.T) {
+		repo := &MinIORepository{client: &blockingMinIOClient{}, bucket: "images", timeout: time.Hour}
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := repo.PutImage(ctx, "pic", bytes.NewReader([]byte("png")), 3, "image/png")
+		assert.ErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		repo := &MinIORepository{client: &fakeMinIOClient{objects: map[string][]byte{}}, bucket: "images", timeout: time.Hour}
+
+		_, err := repo.PutImage(context.Background(), "pic", iotest.ErrReader(io.ErrUnexpectedEOF), 3, "image/png")
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.NotErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+	})
+}
+
+// flakyMinIOClient fails the first failures calls with err and then stores like fakeMinIOClient
+type flakyMinIOClient struct {
+	fakeMinIOClient
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyMinIOClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minIO.PutObjectOptions) (minIO.UploadInfo, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		// a failed attempt may have consumed part of the body
+		io.CopyN(io.Discard, reader, 1)
+		return minIO.UploadInfo{}, c.err
+	}
+	return c.fakeMinIOClient.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+}
+
+func (c *flakyMinIOClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minIO.RemoveObjectOptions) error {
+	c.calls++
+	if c.calls <= c.failures {
+		return c.err
+	}
+	return c.fakeMinIOClient.RemoveObject(ctx, bucketName, objectName, opts)
+}
+
+func TestMinIORepository_Retry(t *testing.T) {
+	unavailable := minIO.ErrorResponse{StatusCode: 503, Code: "ServiceUnavailable"}
+	denied := minIO.ErrorResponse{StatusCode: 403, Code: "AccessDenied"}
+	reset := &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET}
+
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		size          int64
+		expectedCalls int
+		expectedErr   error
+	}{
+		{name: "recovers from 5xx", failures: 2, err: unavailable, size: 3, expectedCalls: 3},
+		{name: "recovers from a dropped connection", failures: 1, err: reset, size: 3, expectedCalls: 2},
+		{name: "gives up after the attempts", failures: 5, err: unavailable, size: 3, expectedCalls: 3, expectedErr: unavailable},
+		{name: "4xx is final", failures: 1, err: denied, size: 3, expectedCalls: 1, expectedErr: denied},
+		{name: "stream of unknown size is sent once", failures: 1, err: unavailable, size: -1, expectedCalls: 1, expectedErr: unavailable},
+		{name: "large upload is sent once", failures: 1, err: unavailable, size: retryBufferLimit + 1, expectedCalls: 1, expectedErr: unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &flakyMinIOClient{fakeMinIOClient: fakeMinIOClient{objects: map[string][]byte{}}, failures: tt.failures, err: tt.err}
+			var waits []time.Duration
+			repo := &MinIORepository{
+				client:   client,
+				bucket:   "images",
+				public:   true,
+				attempts: 3,
+				backoff:  100 * time.Millisecond,
+				sleep: func(ctx context.Context, d time.Duration) error {
+					waits = append(waits, d)
+					return nil
+				},
+			}
+
+			_, err := repo.PutImage(context.Background(), "pic", strings.NewReader("png"), tt.size, "image/png")
+			assert.Equal(t, tt.expectedCalls, client.calls)
+			assert.Equal(t, int64(tt.expectedCalls-1), repo.Retries())
+			assert.Len(t, waits, tt.expectedCalls-1)
+			for i, wait := range waits {
+				// the backoff doubles and the jitter keeps it in the upper half
+				base := 100 * time.Millisecond << i
+				assert.GreaterOrEqual(t, wait, base/2)
+				assert.LessOrEqual(t, wait, base)
+			}
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			// the retried attempt sends the whole body again
+			assert.Equal(t, []byte("png"), client.objects["pic"])
+		})
+	}
+}
+
+func TestMinIORepository_RetryRemove(t *testing.T) {
+	client := &flakyMinIOClient{
+		fakeMinIOClient: fakeMinIOClient{objects: map[string][]byte{"pic": []byte("png")}},
+		failures:        1,
+		err:             minIO.ErrorResponse{StatusCode: 500, Code: "InternalError"},
+	}
+	repo := &MinIORepository{client: client, bucket: "images", attempts: 3, backoff: time.Millisecond}
+
+	require.NoError(t, repo.DeleteImage(context.Background(), "pic"))
+	assert.Equal(t, 2, client.calls)
+	assert.NotContains(t, client.objects, "pic")
+}
+
+func TestMinIORepository_RetryStopsWithContext(t *testing.T) {
+	client := &flakyMinIOClient{fakeMinIOClient: fakeMinIOClient{objects: map[string][]byte{}}, failures: 5, err: minIO.ErrorResponse{StatusCode: 503}}
+	ctx, cancel := context.WithCancel(context.Background())
+	repo := &MinIORepository{
+		client:   client,
+		bucket:   "images",
+		attempts: 5,
+		backoff:  time.Hour,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return sleepContext(ctx, d)
+		},
+	}
+
+	_, err := repo.PutImage(ctx, "pic", strings.NewReader("png"), 3, "image/png")
+	assert.ErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestMinIORepository_ListImages(t *testing.T) {
+	client := &fakeMinIOClient{objects: map[string][]byte{"pic.png": []byte("png"), "backups/run/manifest.json": []byte("{}")}}
+	repo := &MinIORepository{client: client, bucket: "images"}
+
+	var names []string
+	err := repo.ListImages(context.Background(), func(obj storage.ImageObject) error {
+		names = append(names, obj.Name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pic.png"}, names)
+}
@@ -1,16 +1,27 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/db/postgres"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/tracing"
 	"github.com/xkarasb/blog/pkg/types"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
+// tracerName is the component of the spans around database and storage calls
+const tracerName = "github.com/xkarasb/blog/internal/core/repository"
+
 type PostgresRepository struct {
 	DB *postgres.DB
 }
@@ -21,20 +32,44 @@ func NewBlogRepository(db *postgres.DB) *PostgresRepository {
 	}
 }
 
-func (rep *PostgresRepository) AddNewUser(email, password_hash, role, refreshToken string) (*dto.UserDB, error) {
+// timed runs the queries of one call within the query timeout of the database, a query cut short
+// by it fails with errors.ErrorRepositoryQueryTimeout. Maintenance work passes its own ctx instead.
+// The call is traced as a span named statement under the span in ctx.
+func (rep *PostgresRepository) timed(ctx context.Context, statement string, query func(ctx context.Context) error) (err error) {
+	ctx, span := tracing.Start(ctx, tracerName, statement,
+		semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(statement))
+	defer func() { tracing.End(span, err) }()
+
+	cancel := context.CancelFunc(func() {})
+	if rep.DB.QueryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, rep.DB.QueryTimeout)
+	}
+	defer cancel()
+
+	err = query(ctx)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: %w", errors.ErrorRepositoryQueryTimeout, err)
+	}
+	return err
+}
+
+func (rep *PostgresRepository) AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error) {
 	user := &dto.UserDB{}
 
-	query := `INSERT INTO users (email, password_hash, role, refresh_token, refresh_token_expiry_time) VALUES ($1, $2, $3, $4, $5) RETURNING *;`
-	refreshTokenExpire := time.Now().Add(time.Duration(time.Hour * 24 * 7))
+	query := `INSERT INTO users (email, password_hash, role) VALUES ($1, $2, $3) RETURNING *;`
 
-	err := rep.DB.Get(user, query, email, password_hash, role, refreshToken, refreshTokenExpire)
+	err := rep.timed(ctx, "AddNewUser", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, user, query, email, password_hash, role)
+	})
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok {
 			switch pgErr.Code {
 			case "23505":
 				return nil, errors.ErrorRepositoryUserAlreadyExsist
 			case "23514":
-				return nil, errors.ErrorRepositoryBadRole
+				if pgErr.Constraint == "users_role_check" {
+					return nil, errors.ErrorRepositoryBadRole
+				}
 			}
 		}
 
@@ -43,67 +78,257 @@ func (rep *PostgresRepository) AddNewUser(email, password_hash, role, refreshTok
 	return user, nil
 }
 
-func (rep *PostgresRepository) GetUserByEmail(email string) (*dto.UserDB, error) {
+func (rep *PostgresRepository) GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error) {
 	user := &dto.UserDB{}
 
 	query := `SELECT * FROM users WHERE email = $1;`
-	err := rep.DB.Get(user, query, email)
+	err := rep.timed(ctx, "GetUserByEmail", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, user, query, email)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (rep *PostgresRepository) GetUserById(id uuid.UUID) (*dto.UserDB, error) {
+func (rep *PostgresRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
 	user := &dto.UserDB{}
 
 	query := `SELECT * FROM users WHERE user_id = $1;`
-	err := rep.DB.Get(user, query, id)
+	err := rep.timed(ctx, "GetUserById", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, user, query, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// VerifyEmail marks the email of a user verified, sql.ErrNoRows when the user is gone or has another email now
+func (rep *PostgresRepository) VerifyEmail(ctx context.Context, id uuid.UUID, email string) error {
+	var verified uuid.UUID
+
+	query := `UPDATE users SET email_verified = TRUE WHERE user_id = $1 AND email = $2 RETURNING user_id;`
+	return rep.timed(ctx, "VerifyEmail", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &verified, query, id, email)
+	})
+}
+
+// UpdatePasswordHash swaps oldHash for newHash, a user whose hash changed in between keeps the new one
+func (rep *PostgresRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	query := `UPDATE users SET password_hash = $3 WHERE user_id = $1 AND password_hash = $2;`
+	return rep.timed(ctx, "UpdatePasswordHash", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, id, oldHash, newHash)
+		return err
+	})
+}
+
+func (rep *PostgresRepository) GetUsers(ctx context.Context, limit, offset int) ([]*dto.UserDB, error) {
+	var users []*dto.UserDB
+
+	query := `SELECT * FROM users ORDER BY email LIMIT $1 OFFSET $2;`
+	err := rep.timed(ctx, "GetUsers", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &users, query, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (rep *PostgresRepository) CountUsers(ctx context.Context) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM users;`
+	err := rep.timed(ctx, "CountUsers", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (rep *PostgresRepository) UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error) {
+	user := &dto.UserDB{}
+
+	query := `UPDATE users SET role = $2 WHERE user_id = $1 RETURNING *;`
+	err := rep.timed(ctx, "UpdateUserRole", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, user, query, id, role)
+	})
 	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23514" && pgErr.Constraint == "users_role_check" {
+			return nil, errors.ErrorRepositoryBadRole
+		}
 		return nil, err
 	}
 	return user, nil
 }
 
-func (rep *PostgresRepository) UpdateRefreshToken(id uuid.UUID, refreshToken string) (*dto.UserDB, error) {
+// UpdateDisplayName sets the display name of the user, nil takes it away
+func (rep *PostgresRepository) UpdateDisplayName(ctx context.Context, id uuid.UUID, displayName *string) (*dto.UserDB, error) {
 	user := &dto.UserDB{}
 
-	query := `UPDATE users SET refresh_token = $2 WHERE user_id = $1 RETURNING *;`
-	err := rep.DB.Get(user, query, id, refreshToken)
+	query := `UPDATE users SET display_name = $2 WHERE user_id = $1 RETURNING *;`
+	err := rep.timed(ctx, "UpdateDisplayName", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, user, query, id, displayName)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (rep *PostgresRepository) GetRefreshToken(id uuid.UUID) (string, error) {
-	var token string
-	query := `SELECT refresh_token FROM users WHERE user_id = $1;`
-	err := rep.DB.Get(&token, query, id)
+// SetAvatar makes avatarUrl the avatar of the user and returns the one it replaced, nil when there was none.
+// The row is locked while it is read, so of two uploads at once each gets the avatar the other replaced.
+func (rep *PostgresRepository) SetAvatar(ctx context.Context, id uuid.UUID, avatarUrl string) (*string, error) {
+	var previous *string
+
+	query := `WITH previous AS (SELECT avatar_url FROM users WHERE user_id = $1 FOR UPDATE)
+UPDATE users SET avatar_url = $2 WHERE user_id = $1 RETURNING (SELECT avatar_url FROM previous);`
+	err := rep.timed(ctx, "SetAvatar", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &previous, query, id, avatarUrl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// DeleteUser removes the user in one transaction and returns what they leave in the storage. With heir
+// set the posts of the user and the revisions they made are handed over to heir, otherwise they go with
+// the user and so do their images. Everything else of the user is removed by the foreign keys, the refresh
+// token with the row. The row is locked first, posts cannot be added to the user meanwhile.
+func (rep *PostgresRepository) DeleteUser(ctx context.Context, id, heir uuid.UUID) (*dto.DeletedAccountDB, error) {
+	deleted := &dto.DeletedAccountDB{}
+	err := rep.timed(ctx, "DeleteUser", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		query := `SELECT avatar_url FROM users WHERE user_id = $1 FOR UPDATE;`
+		if err := tx.GetContext(ctx, &deleted.AvatarUrl, query, id); err != nil {
+			return err
+		}
+
+		if heir == uuid.Nil {
+			query = `SELECT i.image_url FROM images i JOIN posts p ON p.post_id = i.post_id WHERE p.author_id = $1;`
+			if err := tx.SelectContext(ctx, &deleted.ImageUrls, query, id); err != nil {
+				return err
+			}
+		} else {
+			query = `UPDATE posts SET author_id = $2 WHERE author_id = $1;`
+			if _, err := tx.ExecContext(ctx, query, id, heir); err != nil {
+				return err
+			}
+			query = `UPDATE post_revisions SET edited_by = $2 WHERE edited_by = $1;`
+			if _, err := tx.ExecContext(ctx, query, id, heir); err != nil {
+				return err
+			}
+		}
+
+		query = `DELETE FROM users WHERE user_id = $1;`
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+	return deleted, nil
 }
 
-func (rep *PostgresRepository) GetPostByIdempotencyKey(idempotencyKey string) (*dto.PostDB, error) {
+func (rep *PostgresRepository) GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error) {
 	post := &dto.PostDB{}
 
-	query := `SELECT * FROM posts WHERE idempotency_key = $1;`
-	err := rep.DB.Get(post, query, idempotencyKey)
+	query := `SELECT p.*, ` + postTags + ` FROM posts p WHERE p.idempotency_key = $1;`
+	err := rep.timed(ctx, "GetPostByIdempotencyKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, idempotencyKey)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return post, nil
 }
 
+// postTags selects the sorted tag names of the post aliased p as the tags column of PostDB
+const postTags = `ARRAY(SELECT t.name FROM post_tags pt JOIN tags t ON t.tag_id = pt.tag_id
+WHERE pt.post_id = p.post_id ORDER BY t.name) AS tags`
+
+// postViews counts the reader-days of the post aliased p as the views column of PostDB
+const postViews = `(SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = p.post_id) AS views`
+
+// postLikes counts the likes of the post aliased p as the likes_count column of PostDB
+const postLikes = `(SELECT COUNT(*) FROM post_likes pl WHERE pl.post_id = p.post_id) AS likes_count`
+
+// postWithAuthor is the column list of PostUserDB for posts p joined with their author u
+const postWithAuthor = `p.*, u.*, ` + postTags + `, ` + postViews + `, ` + postLikes
+
+// setPostTags replaces the tags of a post, unknown tags are created on the way
+func setPostTags(ctx context.Context, tx *sqlx.Tx, postId uuid.UUID, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1;`, postId); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING;`
+	if _, err := tx.ExecContext(ctx, query, pq.Array(tags)); err != nil {
+		return err
+	}
+	query = `INSERT INTO post_tags (post_id, tag_id) SELECT $1, tag_id FROM tags WHERE name = ANY($2);`
+	_, err := tx.ExecContext(ctx, query, postId, pq.Array(tags))
+	return err
+}
+
+// postEvent is the payload of an outbox event about the post aliased p
+const postEvent = `jsonb_build_object('post_id', p.post_id, 'author_id', p.author_id, 'title', p.title,
+'status', p.status, 'publish_at', p.publish_at)`
+
+// addPostEvent queues an event about the post in the transaction that changed it, so the event is sent
+// exactly when the change is committed. previous is the status before a status change, empty otherwise.
+func addPostEvent(ctx context.Context, tx *sqlx.Tx, eventType types.EventType, postId uuid.UUID, previous types.PostStatus) error {
+	query := `INSERT INTO outbox_events (post_id, type, payload)
+SELECT p.post_id, $2, ` + postEvent + ` || jsonb_strip_nulls(jsonb_build_object('previous_status', NULLIF($3, '')))
+FROM posts p WHERE p.post_id = $1;`
+	_, err := tx.ExecContext(ctx, query, postId, eventType, previous)
+	return err
+}
+
+// CreatePost writes the post, its tags and a post.created event in one transaction, an empty excerpt
+// is stored as none and nil stats leave the counts to be filled in later
 func (rep *PostgresRepository) CreatePost(
-	authorId uuid.UUID, idempotencyKey, title, content string) (*dto.PostDB, error) {
+	ctx context.Context, authorId uuid.UUID, idempotencyKey, title, content, excerpt string, stats *dto.ReadingStats, tags []string) (*dto.PostDB, error) {
 	post := &dto.PostDB{}
 
-	query := `INSERT INTO posts (author_id, idempotency_key, title, content) VALUES ($1, $2, $3, $4) RETURNING *;`
+	err := rep.timed(ctx, "CreatePost", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
 
-	err := rep.DB.Get(post, query, authorId, idempotencyKey, title, content)
+		var wordCount, readingTime *int
+		if stats != nil {
+			wordCount, readingTime = &stats.WordCount, &stats.ReadingTimeMinutes
+		}
+		query := `INSERT INTO posts (author_id, idempotency_key, title, content, excerpt, word_count, reading_time_minutes)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7) RETURNING *;`
+		if err := tx.GetContext(ctx, post, query, authorId, idempotencyKey, title, content, excerpt, wordCount, readingTime); err != nil {
+			return err
+		}
+		if err := setPostTags(ctx, tx, post.PostId, tags); err != nil {
+			return err
+		}
+		if err := addPostEvent(ctx, tx, types.EventPostCreated, post.PostId, ""); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
 	if err != nil {
 		pgErr, ok := err.(*pq.Error)
 		if ok && pgErr.Code == "23505" {
@@ -111,36 +336,288 @@ func (rep *PostgresRepository) CreatePost(
 		}
 		return nil, err
 	}
+	post.Tags = slices.Sorted(slices.Values(tags))
+
+	return post, nil
+}
+
+func (rep *PostgresRepository) GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post := &dto.PostDB{}
+
+	query := `SELECT p.*, ` + postTags + ` FROM posts p WHERE p.post_id = $1 AND p.deleted_at IS NULL;`
+	err := rep.timed(ctx, "GetPostById", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// GetPostWithAuthorById is GetPostById joined with the author, tags and views for a single post page
+func (rep *PostgresRepository) GetPostWithAuthorById(ctx context.Context, id uuid.UUID) (*dto.PostUserDB, error) {
+	post := &dto.PostUserDB{}
+
+	query := `SELECT ` + postWithAuthor + ` FROM posts p
+LEFT JOIN users u ON u.user_id = p.author_id
+WHERE p.post_id = $1 AND p.deleted_at IS NULL;`
+	err := rep.timed(ctx, "GetPostWithAuthorById", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// UpdatePost writes the columns set in the patch and the tags in one transaction together with a revision
+// holding the previous version, nil tags leave the current ones in place. A non-nil expectedUpdatedAt makes
+// the update apply only to that version of the post, any other one is sql.ErrNoRows. A patch that changes
+// the status queues a post.status_changed event with it.
+func (rep *PostgresRepository) UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error) {
+	post := &dto.PostDB{}
+
+	err := rep.timed(ctx, "UpdatePost", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := updatePost(ctx, tx, post, id, editorId, patch, tags, expectedUpdatedAt); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23514" && pgErr.Constraint == "posts_status_check" {
+			return nil, errors.ErrorRepositoryBadStatus
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
+func updatePost(ctx context.Context, tx *sqlx.Tx, post *dto.PostDB, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) error {
+	// the row lock keeps a concurrent edit from slipping between the copy and the update
+	query := `WITH previous AS (SELECT post_id, title, content, status FROM posts WHERE post_id = $1 AND deleted_at IS NULL FOR UPDATE)
+INSERT INTO post_revisions (post_id, title, content, status, edited_by)
+SELECT post_id, title, content, status, $2 FROM previous RETURNING status;`
+	var previous types.PostStatus
+	if err := tx.GetContext(ctx, &previous, query, id, editorId); err != nil {
+		return err
+	}
+
+	// updated_at is a timestamp without time zone holding UTC, the cast drops the zone of the parameter
+	var expected *time.Time
+	if expectedUpdatedAt != nil {
+		utc := expectedUpdatedAt.UTC()
+		expected = &utc
+	}
+	args := []any{id, expected}
+	set := []string{}
+	if patch.Title != nil {
+		args = append(args, *patch.Title)
+		set = append(set, fmt.Sprintf("title = $%d", len(args)))
+	}
+	if patch.Content != nil {
+		args = append(args, *patch.Content)
+		set = append(set, fmt.Sprintf("content = $%d", len(args)))
+	}
+	if patch.Excerpt != nil {
+		args = append(args, *patch.Excerpt)
+		set = append(set, fmt.Sprintf("excerpt = NULLIF($%d, '')", len(args)))
+	}
+	if patch.Stats != nil {
+		args = append(args, patch.Stats.WordCount, patch.Stats.ReadingTimeMinutes)
+		set = append(set, fmt.Sprintf("word_count = $%d, reading_time_minutes = $%d", len(args)-1, len(args)))
+	}
+	if patch.Status != nil {
+		args = append(args, *patch.Status)
+		set = append(set, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(set) == 0 {
+		// a tags only edit still touches the row, so it moves updated_at and gets the version check
+		set = append(set, "updated_at = NOW()")
+	}
+	query = `UPDATE posts p SET ` + strings.Join(set, ", ") + ` WHERE p.post_id = $1 AND p.deleted_at IS NULL
+AND ($2::timestamp IS NULL OR p.updated_at = $2::timestamp)
+RETURNING p.*;`
+	if err := tx.GetContext(ctx, post, query, args...); err != nil {
+		return err
+	}
+
+	if tags != nil {
+		if err := setPostTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+	}
+	if patch.Status != nil && post.Status != previous {
+		if err := addPostEvent(ctx, tx, types.EventPostStatusChanged, id, previous); err != nil {
+			return err
+		}
+	}
+	query = `SELECT ` + postTags + ` FROM posts p WHERE p.post_id = $1;`
+	return tx.GetContext(ctx, &post.Tags, query, id)
+}
+
+// FillReadingStats stores the word count and reading time set on posts that were read without any. A post
+// changed since it was read is skipped, its edit stored counts of its own.
+func (rep *PostgresRepository) FillReadingStats(ctx context.Context, posts []*dto.PostDB) error {
+	postIds := make([]string, len(posts))
+	updated := make([]string, len(posts))
+	words := make([]int64, len(posts))
+	minutes := make([]int64, len(posts))
+	for i, post := range posts {
+		postIds[i] = post.PostId.String()
+		// updated_at is a timestamp without time zone holding UTC
+		updated[i] = post.UpdatedAt.UTC().Format(time.RFC3339Nano)
+		words[i] = int64(*post.WordCount)
+		minutes[i] = int64(*post.ReadingTimeMinutes)
+	}
+
+	query := `UPDATE posts p SET word_count = s.word_count, reading_time_minutes = s.reading_time_minutes
+FROM unnest($1::uuid[], $2::timestamp[], $3::int[], $4::int[]) AS s(post_id, updated_at, word_count, reading_time_minutes)
+WHERE p.post_id = s.post_id AND p.updated_at = s.updated_at AND p.word_count IS NULL;`
+	return rep.timed(ctx, "FillReadingStats", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, pq.Array(postIds), pq.Array(updated), pq.Array(words), pq.Array(minutes))
+		return err
+	})
+}
+
+// SchedulePost sets the post to go out at publishAt, a post that was not scheduled before gets a
+// post.status_changed event in the same transaction
+func (rep *PostgresRepository) SchedulePost(ctx context.Context, id uuid.UUID, publishAt time.Time) (*dto.PostDB, error) {
+	post := &dto.PostDB{}
+	err := rep.timed(ctx, "SchedulePost", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var previous types.PostStatus
+		query := `SELECT status FROM posts WHERE post_id = $1 AND deleted_at IS NULL FOR UPDATE;`
+		if err := tx.GetContext(ctx, &previous, query, id); err != nil {
+			return err
+		}
+		query = `UPDATE posts SET status = 'scheduled', publish_at = $2 WHERE post_id = $1 RETURNING *;`
+		if err := tx.GetContext(ctx, post, query, id, publishAt); err != nil {
+			return err
+		}
+		if previous != types.Scheduled {
+			if err := addPostEvent(ctx, tx, types.EventPostStatusChanged, id, previous); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
 	return post, nil
 }
 
-func (rep *PostgresRepository) GetPostById(id uuid.UUID) (*dto.PostDB, error) {
+// PublishDuePosts publishes scheduled posts with publish_at not after now, a zero now means the database clock,
+// and returns them with their tags. Every published post gets a post.status_changed event in the same statement.
+func (rep *PostgresRepository) PublishDuePosts(ctx context.Context, now time.Time) ([]*dto.PostDB, error) {
+	var posts []*dto.PostDB
+	query := `WITH due AS (UPDATE posts SET status = 'published'
+WHERE status = 'scheduled' AND publish_at <= COALESCE($1, NOW()) AND deleted_at IS NULL RETURNING *),
+events AS (INSERT INTO outbox_events (post_id, type, payload)
+SELECT p.post_id, $2, ` + postEvent + ` || jsonb_build_object('previous_status', 'scheduled') FROM due p)
+SELECT p.*, ` + postTags + ` FROM due p;`
+	err := rep.timed(ctx, "PublishDuePosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, sql.NullTime{Time: now, Valid: !now.IsZero()}, types.EventPostStatusChanged)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// TrashPost moves the post to the trash and queues a post.deleted event in one transaction, readers
+// lose the post here already, purging it later reports nothing more
+func (rep *PostgresRepository) TrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
 	post := &dto.PostDB{}
+	query := `UPDATE posts SET deleted_at = NOW() WHERE post_id = $1 AND deleted_at IS NULL RETURNING *;`
+	err := rep.timed(ctx, "TrashPost", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := tx.GetContext(ctx, post, query, id); err != nil {
+			return err
+		}
+		if err := addPostEvent(ctx, tx, types.EventPostDeleted, id, ""); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
 
-	query := `SELECT * FROM posts WHERE post_id = $1;`
-	err := rep.DB.Get(post, query, id)
+func (rep *PostgresRepository) GetTrashedPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post := &dto.PostDB{}
+	query := `SELECT * FROM posts WHERE post_id = $1 AND deleted_at IS NOT NULL;`
+	err := rep.timed(ctx, "GetTrashedPostById", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return post, nil
 }
 
-func (rep *PostgresRepository) UpdatePost(id uuid.UUID, title, content string, status types.PostStatus) (*dto.PostDB, error) {
+func (rep *PostgresRepository) UntrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
 	post := &dto.PostDB{}
-	query := `UPDATE posts SET title = $2, content = $3, status = $4 WHERE post_id = $1 RETURNING *;`
-	err := rep.DB.Get(post, query, id, title, content, status)
+	query := `UPDATE posts SET deleted_at = NULL WHERE post_id = $1 AND deleted_at IS NOT NULL RETURNING *;`
+	err := rep.timed(ctx, "UntrashPost", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return post, nil
 }
 
-func (rep *PostgresRepository) CreateImage(imageId, postId uuid.UUID, imageUrl string) (*dto.ImageDB, error) {
+func (rep *PostgresRepository) GetExpiredTrash(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `SELECT post_id FROM posts WHERE deleted_at < $1;`
+	err := rep.timed(ctx, "GetExpiredTrash", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &ids, query, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PurgePost removes a trashed post for good, images rows go with it by cascade
+func (rep *PostgresRepository) PurgePost(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM posts WHERE post_id = $1 AND deleted_at IS NOT NULL;`
+	err := rep.timed(ctx, "PurgePost", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, id)
+		return err
+	})
+	return err
+}
+
+func (rep *PostgresRepository) CreateImage(ctx context.Context, imageId, postId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
 	image := &dto.ImageDB{}
 
-	query := `INSERT INTO images (image_id, post_id, image_url) VALUES ($1, $2, $3) RETURNING *;`
+	// a new image goes after the others of the post
+	query := `INSERT INTO images (image_id, post_id, image_url, size_bytes, position)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), 0) + 1 FROM images WHERE post_id = $2)) RETURNING *;`
 
-	err := rep.DB.Get(image, query, imageId, postId, imageUrl)
+	err := rep.timed(ctx, "CreateImage", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, image, query, imageId, postId, imageUrl, size)
+	})
 	if err != nil {
 		pgErr, ok := err.(*pq.Error)
 		if ok && pgErr.Code == "23505" {
@@ -151,36 +628,249 @@ func (rep *PostgresRepository) CreateImage(imageId, postId uuid.UUID, imageUrl s
 	return image, nil
 }
 
-func (rep *PostgresRepository) DeleteImage(imageId uuid.UUID) (*dto.ImageDB, error) {
+func (rep *PostgresRepository) DeleteImage(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
 	image := &dto.ImageDB{}
 	query := `DELETE FROM images WHERE image_id = $1 RETURNING *`
-	err := rep.DB.Get(image, query, imageId)
+	err := rep.timed(ctx, "DeleteImage", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, image, query, imageId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+
+}
+
+func (rep *PostgresRepository) GetImageById(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
+	image := &dto.ImageDB{}
+	query := `SELECT * FROM images WHERE image_id = $1;`
+	err := rep.timed(ctx, "GetImageById", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, image, query, imageId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// ReplaceImage points an image of a post to imageUrl of size bytes and marks it as changed now,
+// sql.ErrNoRows when the post has no such image
+func (rep *PostgresRepository) ReplaceImage(ctx context.Context, postId, imageId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	image := &dto.ImageDB{}
+	query := `UPDATE images SET image_url = $3, size_bytes = $4, updated_at = NOW() WHERE image_id = $1 AND post_id = $2 RETURNING *;`
+	err := rep.timed(ctx, "ReplaceImage", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, image, query, imageId, postId, imageUrl, size)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return image, nil
+}
+
+// GetExistingImageIds returns those of ids that still have an images row, it belongs to maintenance
+// and runs within ctx only
+func (rep *PostgresRepository) GetExistingImageIds(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+
+	existing := []uuid.UUID{}
+	query := `SELECT image_id FROM images WHERE image_id = ANY($1::uuid[]);`
+	if err := rep.DB.SelectContext(ctx, &existing, query, pq.Array(raw)); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetUserImageBytes sums the images of all posts of the user, trashed ones included as their
+// files are still stored
+func (rep *PostgresRepository) GetUserImageBytes(ctx context.Context, userId uuid.UUID) (int64, error) {
+	var used int64
+	query := `SELECT COALESCE(SUM(i.size_bytes), 0) FROM images i JOIN posts p ON p.post_id = i.post_id WHERE p.author_id = $1;`
+	err := rep.timed(ctx, "GetUserImageBytes", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &used, query, userId)
+	})
+	return used, err
+}
+
+// CountPostImages returns how many images a post has
+func (rep *PostgresRepository) CountPostImages(ctx context.Context, postId uuid.UUID) (int, error) {
+	var count int
+	err := rep.timed(ctx, "CountPostImages", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, `SELECT COUNT(*) FROM images WHERE post_id = $1;`, postId)
+	})
+	return count, err
+}
+
+// GetPostImages lists the images of a post by position, images sharing one in the order they were added
+func (rep *PostgresRepository) GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error) {
+	var images []*dto.ImageDB
+
+	query := `SELECT * FROM images WHERE post_id = $1 ORDER BY position, created_at, image_id;`
+	err := rep.timed(ctx, "GetPostImages", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &images, query, postId)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// UpdateImage changes caption and position of an image of the post, sql.ErrNoRows when the post has no
+// such image. A position moves the image there and renumbers the images of the post from one.
+func (rep *PostgresRepository) UpdateImage(ctx context.Context, postId, imageId uuid.UUID, patch *dto.ImagePatch) (*dto.ImageDB, error) {
+	image := &dto.ImageDB{}
+	err := rep.timed(ctx, "UpdateImage", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		ids, err := lockPostImages(ctx, tx, postId)
+		if err != nil {
+			return err
+		}
+		at := slices.Index(ids, imageId)
+		if at < 0 {
+			return sql.ErrNoRows
+		}
 
+		if patch.Position != nil {
+			to := min(*patch.Position, len(ids)) - 1
+			ids = slices.Insert(slices.Delete(ids, at, at+1), to, imageId)
+			if err := setImagePositions(ctx, tx, ids); err != nil {
+				return err
+			}
+		}
+		if patch.Caption != nil {
+			query := `UPDATE images SET caption = NULLIF($2, '') WHERE image_id = $1;`
+			if _, err := tx.ExecContext(ctx, query, imageId, *patch.Caption); err != nil {
+				return err
+			}
+		}
+		if err := tx.GetContext(ctx, image, `SELECT * FROM images WHERE image_id = $1;`, imageId); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
 }
 
-func (rep *PostgresRepository) GetPostImages(postId uuid.UUID) ([]*dto.ImageDB, error) {
+// ReorderImages puts the images of the post in the order of ids, which must name each of them once.
+// Anything else is ErrorRepositoryImageOrder and changes nothing.
+func (rep *PostgresRepository) ReorderImages(ctx context.Context, postId uuid.UUID, ids []uuid.UUID) ([]*dto.ImageDB, error) {
 	var images []*dto.ImageDB
+	err := rep.timed(ctx, "ReorderImages", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
 
-	query := `SELECT * FROM images WHERE post_id = $1;`
-	err := rep.DB.Select(&images, query, postId)
+		current, err := lockPostImages(ctx, tx, postId)
+		if err != nil {
+			return err
+		}
+		if !sameImages(current, ids) {
+			return errors.ErrorRepositoryImageOrder
+		}
+		if err := setImagePositions(ctx, tx, ids); err != nil {
+			return err
+		}
 
+		query := `SELECT * FROM images WHERE post_id = $1 ORDER BY position, created_at, image_id;`
+		if err := tx.SelectContext(ctx, &images, query, postId); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
 	if err != nil {
 		return nil, err
 	}
 	return images, nil
 }
 
-func (rep *PostgresRepository) GetPublishedPosts() ([]*dto.PostUserDB, error) {
+// lockPostImages returns the ids of the images of a post in their order, locked until tx ends so
+// concurrent reorders of the post wait for each other
+func lockPostImages(ctx context.Context, tx *sqlx.Tx, postId uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `SELECT image_id FROM images WHERE post_id = $1 ORDER BY position, created_at, image_id FOR UPDATE;`
+	if err := tx.SelectContext(ctx, &ids, query, postId); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// setImagePositions numbers the images of ids from one in the order given
+func setImagePositions(ctx context.Context, tx *sqlx.Tx, ids []uuid.UUID) error {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+	query := `UPDATE images i SET position = o.position
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(image_id, position) WHERE i.image_id = o.image_id;`
+	_, err := tx.ExecContext(ctx, query, pq.Array(raw))
+	return err
+}
+
+// sameImages reports whether ids lists each of current exactly once
+func sameImages(current, ids []uuid.UUID) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] || !slices.Contains(current, id) {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// hasTag matches posts p carrying the tag bound to the given parameter, an empty tag matches every post
+func hasTag(param string) string {
+	return `(` + param + ` = '' OR EXISTS (SELECT 1 FROM post_tags pt JOIN tags t ON t.tag_id = pt.tag_id
+WHERE pt.post_id = p.post_id AND t.name = ` + param + `))`
+}
+
+// postSortColumns whitelists what a listing can be ordered by, the sort asked for never reaches the query as is
+var postSortColumns = map[types.PostSort]string{
+	types.SortCreatedAt: "p.created_at",
+	types.SortUpdatedAt: "p.updated_at",
+	types.SortTitle:     "p.title",
+}
+
+// orderPosts is the ORDER BY of a listing of posts p, anything unknown falls back to newest first
+func orderPosts(opts dto.PostListOptions) string {
+	column, ok := postSortColumns[opts.Sort]
+	if !ok {
+		column = postSortColumns[types.SortCreatedAt]
+	}
+	direction := "DESC"
+	if opts.Order == types.Asc {
+		direction = "ASC"
+	}
+	// post_id keeps posts with equal values in a stable order
+	return `ORDER BY ` + column + ` ` + direction + `, p.post_id`
+}
+
+func (rep *PostgresRepository) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
 	var posts []*dto.PostUserDB
 
-	query := `SELECT p.*, u.* FROM posts p
+	query := `SELECT ` + postWithAuthor + ` FROM posts p
 LEFT JOIN users u ON u.user_id = p.author_id
-WHERE p.status = 'published';`
-	err := rep.DB.Select(&posts, query)
+WHERE p.status = 'published' AND p.deleted_at IS NULL AND ` + hasTag("$1") + `
+` + orderPosts(opts) + `;`
+	err := rep.timed(ctx, "GetPublishedPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, opts.Tag)
+	})
 
 	if err != nil {
 		return nil, err
@@ -188,16 +878,1051 @@ WHERE p.status = 'published';`
 	return posts, nil
 }
 
-func (rep *PostgresRepository) GetUserPosts(userId uuid.UUID) ([]*dto.PostUserDB, error) {
+// GetUserPosts lists the live posts of the user, an empty status in opts matches every status
+func (rep *PostgresRepository) GetUserPosts(ctx context.Context, userId uuid.UUID, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
 	var posts []*dto.PostUserDB
 
-	query := `SELECT p.*, u.* FROM posts p
+	query := `SELECT ` + postWithAuthor + ` FROM posts p
 LEFT JOIN users u ON u.user_id = p.author_id
-WHERE p.author_id = $1;`
-	err := rep.DB.Select(&posts, query, userId)
+WHERE p.author_id = $1 AND p.deleted_at IS NULL AND ` + hasTag("$2") + ` AND ($3 = '' OR p.status = $3)
+` + orderPosts(opts) + `;`
+	err := rep.timed(ctx, "GetUserPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, userId, opts.Tag, opts.Status)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 	return posts, nil
 }
+
+// ExportAuthorPosts passes the live posts of an author with their tags and images to fn, oldest first.
+// Posts are read row by row, the images of all of them up front. Like a backup it runs within ctx only.
+func (rep *PostgresRepository) ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error {
+	var images []*dto.ImageDB
+	query := `SELECT i.* FROM images i JOIN posts p ON p.post_id = i.post_id
+WHERE p.author_id = $1 AND p.deleted_at IS NULL ORDER BY i.post_id, i.position, i.created_at, i.image_id;`
+	if err := rep.DB.SelectContext(ctx, &images, query, authorId); err != nil {
+		return err
+	}
+	postImages := map[uuid.UUID][]*dto.ImageDB{}
+	for _, image := range images {
+		postImages[image.PostId] = append(postImages[image.PostId], image)
+	}
+
+	query = `SELECT p.*, ` + postTags + ` FROM posts p
+WHERE p.author_id = $1 AND p.deleted_at IS NULL ORDER BY p.created_at, p.post_id;`
+	rows, err := rep.DB.QueryxContext(ctx, query, authorId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post := &dto.PostDB{}
+		if err := rows.StructScan(post); err != nil {
+			return err
+		}
+		if err := fn(post, postImages[post.PostId]); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// HasImportedPost tells whether the author has a live post imported with hash, or one with the same
+// title and content
+func (rep *PostgresRepository) HasImportedPost(ctx context.Context, authorId uuid.UUID, hash, title, content string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM posts WHERE author_id = $1 AND deleted_at IS NULL
+AND (import_hash = $2 OR (title = $3 AND content = $4)));`
+	err := rep.timed(ctx, "HasImportedPost", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &exists, query, authorId, hash, title, content)
+	})
+	return exists, err
+}
+
+// ImportPost writes an imported post as a draft with its tags, its images in their order and a post.created
+// event in one transaction, so a post comes in whole or not at all
+func (rep *PostgresRepository) ImportPost(ctx context.Context, authorId uuid.UUID, imported *dto.ImportedPost) (*dto.PostDB, error) {
+	post := &dto.PostDB{}
+
+	err := rep.timed(ctx, "ImportPost", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var wordCount, readingTime *int
+		if imported.Stats != nil {
+			wordCount, readingTime = &imported.Stats.WordCount, &imported.Stats.ReadingTimeMinutes
+		}
+		query := `INSERT INTO posts (author_id, idempotency_key, title, content, excerpt, word_count, reading_time_minutes, import_hash)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8) RETURNING *;`
+		err = tx.GetContext(ctx, post, query, authorId, imported.IdempotencyKey, imported.Title, imported.Content,
+			imported.Excerpt, wordCount, readingTime, imported.Hash)
+		if err != nil {
+			return err
+		}
+		if err := setPostTags(ctx, tx, post.PostId, imported.Tags); err != nil {
+			return err
+		}
+		query = `INSERT INTO images (image_id, post_id, image_url, position, caption, size_bytes) VALUES ($1, $2, $3, $4, $5, $6);`
+		for i, image := range imported.Images {
+			if _, err := tx.ExecContext(ctx, query, image.ImageId, post.PostId, image.ImageUrl, i+1, image.Caption, image.SizeBytes); err != nil {
+				return err
+			}
+		}
+		if err := addPostEvent(ctx, tx, types.EventPostCreated, post.PostId, ""); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = slices.Sorted(slices.Values(imported.Tags))
+	return post, nil
+}
+
+// GetAuthorPublishedPosts lists the live published posts of one author, an empty tag does not filter
+func (rep *PostgresRepository) GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	var posts []*dto.PostUserDB
+
+	query := `SELECT ` + postWithAuthor + ` FROM posts p
+LEFT JOIN users u ON u.user_id = p.author_id
+WHERE p.author_id = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND ` + hasTag("$2") + `;`
+	err := rep.timed(ctx, "GetAuthorPublishedPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, authorId, tag)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetAuthors lists the authors having at least one live published post, most published first
+func (rep *PostgresRepository) GetAuthors(ctx context.Context) ([]*dto.AuthorDB, error) {
+	var authors []*dto.AuthorDB
+
+	query := `SELECT u.user_id, u.email, u.display_name, u.avatar_url, COUNT(*) AS posts_count FROM users u
+JOIN posts p ON p.author_id = u.user_id
+WHERE u.role = 'author' AND p.status = 'published' AND p.deleted_at IS NULL
+GROUP BY u.user_id ORDER BY posts_count DESC, u.email;`
+	err := rep.timed(ctx, "GetAuthors", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &authors, query)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// GetAuthor is an author with their live published posts counted, those without any as well.
+// A user who is not an author is sql.ErrNoRows.
+func (rep *PostgresRepository) GetAuthor(ctx context.Context, id uuid.UUID) (*dto.AuthorDB, error) {
+	author := &dto.AuthorDB{}
+
+	query := `SELECT u.user_id, u.email, u.display_name, u.avatar_url, COUNT(p.post_id) AS posts_count FROM users u
+LEFT JOIN posts p ON p.author_id = u.user_id AND p.status = 'published' AND p.deleted_at IS NULL
+WHERE u.user_id = $1 AND u.role = 'author'
+GROUP BY u.user_id;`
+	err := rep.timed(ctx, "GetAuthor", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, author, query, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return author, nil
+}
+
+// searchDocument must stay the expression of idx_posts_search or the index is not used
+const searchDocument = `to_tsvector('simple', p.title || ' ' || p.content)`
+
+// searchPosts ranks live posts matching $1 that also satisfy visible, $2 and $3 are limit and offset.
+// statement names the query in traces.
+func (rep *PostgresRepository) searchPosts(ctx context.Context, statement, visible string, args ...interface{}) ([]*dto.PostSearchDB, error) {
+	var posts []*dto.PostSearchDB
+
+	query := `SELECT ` + postWithAuthor + `, ts_rank(` + searchDocument + `, q) AS rank FROM posts p
+LEFT JOIN users u ON u.user_id = p.author_id
+CROSS JOIN plainto_tsquery('simple', $1) q
+WHERE p.deleted_at IS NULL AND ` + visible + ` AND ` + searchDocument + ` @@ q
+ORDER BY rank DESC, p.created_at DESC LIMIT $2 OFFSET $3;`
+	err := rep.timed(ctx, statement, func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, args...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (rep *PostgresRepository) SearchPublishedPosts(ctx context.Context, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	return rep.searchPosts(ctx, "SearchPublishedPosts", `p.status = 'published'`, query, limit, offset)
+}
+
+// SearchPostsWithAuthor also matches every post of the author whatever its status
+func (rep *PostgresRepository) SearchPostsWithAuthor(ctx context.Context, authorId uuid.UUID, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	return rep.searchPosts(ctx, "SearchPostsWithAuthor", `(p.status = 'published' OR p.author_id = $4)`, query, limit, offset, authorId)
+}
+
+// GetTrendingPosts ranks the published posts by their views and likes of the last window, a like counts
+// likeWeight times as much as a view. Both count fully when new and less the older they get, down to nothing
+// at the end of the window. Posts without any follow the ranked ones newest first, so without views and likes
+// at all the ranking is the newest posts.
+func (rep *PostgresRepository) GetTrendingPosts(ctx context.Context, window time.Duration, likeWeight float64, limit int) ([]*dto.PostTrendingDB, error) {
+	var posts []*dto.PostTrendingDB
+
+	query := `SELECT ` + postWithAuthor + `, COALESCE(t.score, 0) AS score FROM posts p
+LEFT JOIN users u ON u.user_id = p.author_id
+LEFT JOIN (
+	SELECT s.post_id, SUM(s.score) AS score FROM (
+		SELECT pv.post_id, GREATEST(0, 1 - EXTRACT(EPOCH FROM NOW() - pv.viewed_on::timestamptz) / $1) AS score
+		FROM post_views pv WHERE pv.viewed_on >= (NOW() - $1 * INTERVAL '1 second')::date
+		UNION ALL
+		SELECT pl.post_id, $2 * GREATEST(0, 1 - EXTRACT(EPOCH FROM NOW() - pl.created_at) / $1)
+		FROM post_likes pl WHERE pl.created_at >= NOW() - $1 * INTERVAL '1 second'
+	) s GROUP BY s.post_id
+) t ON t.post_id = p.post_id
+WHERE p.status = 'published' AND p.deleted_at IS NULL
+ORDER BY score DESC, p.created_at DESC, p.post_id LIMIT $3;`
+	err := rep.timed(ctx, "GetTrendingPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, window.Seconds(), likeWeight, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// RecordViews stores a batch of views, repeats of the same reader and day as well as views
+// of posts or users removed in the meantime are skipped
+func (rep *PostgresRepository) RecordViews(ctx context.Context, views []dto.PostView) error {
+	postIds := make([]string, len(views))
+	userIds := make([]string, len(views))
+	days := make([]string, len(views))
+	for i, view := range views {
+		postIds[i] = view.PostId.String()
+		userIds[i] = view.UserId.String()
+		days[i] = view.Day.Format(time.DateOnly)
+	}
+
+	query := `INSERT INTO post_views (post_id, user_id, viewed_on)
+SELECT v.post_id, v.user_id, v.viewed_on FROM unnest($1::uuid[], $2::uuid[], $3::date[]) AS v(post_id, user_id, viewed_on)
+JOIN posts p ON p.post_id = v.post_id
+JOIN users u ON u.user_id = v.user_id
+ON CONFLICT DO NOTHING;`
+	err := rep.timed(ctx, "RecordViews", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, pq.Array(postIds), pq.Array(userIds), pq.Array(days))
+		return err
+	})
+	return err
+}
+
+func (rep *PostgresRepository) GetPostViewStats(ctx context.Context, postId uuid.UUID) ([]*dto.ViewDayDB, error) {
+	var days []*dto.ViewDayDB
+
+	query := `SELECT viewed_on AS day, COUNT(*) AS views FROM post_views WHERE post_id = $1
+GROUP BY viewed_on ORDER BY viewed_on;`
+	err := rep.timed(ctx, "GetPostViewStats", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &days, query, postId)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// LikePost is a no-op when the user already likes the post, a like of a removed post is sql.ErrNoRows
+func (rep *PostgresRepository) LikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	query := `INSERT INTO post_likes (post_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	err := rep.timed(ctx, "LikePost", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, postId, userId)
+		return err
+	})
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23503" {
+			return sql.ErrNoRows
+		}
+		return err
+	}
+	return nil
+}
+
+// AddReport keeps a report of the post by the user. A user reports a post once, a repeat changes nothing
+// and returns the report kept. A report of a removed post is sql.ErrNoRows.
+func (rep *PostgresRepository) AddReport(ctx context.Context, postId, userId uuid.UUID, reason types.ReportReason, comment string) (*dto.ReportDB, error) {
+	report := &dto.ReportDB{}
+
+	query := `WITH added AS (
+	INSERT INTO post_reports (post_id, user_id, reason, comment) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (post_id, user_id) DO NOTHING RETURNING *
+)
+SELECT * FROM added
+UNION ALL
+SELECT * FROM post_reports WHERE post_id = $1 AND user_id = $2
+LIMIT 1;`
+	err := rep.timed(ctx, "AddReport", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, report, query, postId, userId, reason, comment)
+	})
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23503" {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetReportedPosts pages through the posts with open reports, the most reported first and the latest
+// reported among equals. Trashed posts wait until they are restored.
+func (rep *PostgresRepository) GetReportedPosts(ctx context.Context, limit, offset int) ([]*dto.ReportedPostDB, error) {
+	var posts []*dto.ReportedPostDB
+
+	query := `SELECT r.post_id, p.title, p.author_id, p.status, COUNT(*) AS reports,
+	COUNT(*) FILTER (WHERE r.reason = 'spam') AS spam,
+	COUNT(*) FILTER (WHERE r.reason = 'abuse') AS abuse,
+	COUNT(*) FILTER (WHERE r.reason = 'copyright') AS copyright,
+	COUNT(*) FILTER (WHERE r.reason = 'other') AS other,
+	COALESCE(array_agg(r.comment ORDER BY r.created_at DESC) FILTER (WHERE r.comment <> ''), '{}') AS comments,
+	MIN(r.created_at) AS first_reported_at, MAX(r.created_at) AS last_reported_at
+FROM post_reports r
+JOIN posts p ON p.post_id = r.post_id
+WHERE r.resolved_at IS NULL AND p.deleted_at IS NULL
+GROUP BY r.post_id, p.title, p.author_id, p.status
+ORDER BY reports DESC, last_reported_at DESC, r.post_id
+LIMIT $1 OFFSET $2;`
+	err := rep.timed(ctx, "GetReportedPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (rep *PostgresRepository) CountReportedPosts(ctx context.Context) (int, error) {
+	var total int
+	query := `SELECT COUNT(DISTINCT r.post_id) FROM post_reports r
+JOIN posts p ON p.post_id = r.post_id
+WHERE r.resolved_at IS NULL AND p.deleted_at IS NULL;`
+	err := rep.timed(ctx, "CountReportedPosts", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &total, query)
+	})
+	return total, err
+}
+
+func (rep *PostgresRepository) CountOpenReports(ctx context.Context, postId uuid.UUID) (int, error) {
+	var open int
+	query := `SELECT COUNT(*) FROM post_reports WHERE post_id = $1 AND resolved_at IS NULL;`
+	err := rep.timed(ctx, "CountOpenReports", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &open, query, postId)
+	})
+	return open, err
+}
+
+// ResolveReports closes the open reports of the post with the action of the admin and returns how many
+func (rep *PostgresRepository) ResolveReports(ctx context.Context, postId, adminId uuid.UUID, action types.ModerationAction) (int, error) {
+	var resolved int64
+	query := `UPDATE post_reports SET resolved_at = NOW(), resolved_by = $2, resolution = $3
+WHERE post_id = $1 AND resolved_at IS NULL;`
+	err := rep.timed(ctx, "ResolveReports", func(ctx context.Context) error {
+		res, err := rep.DB.ExecContext(ctx, query, postId, adminId, action)
+		if err != nil {
+			return err
+		}
+		resolved, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(resolved), nil
+}
+
+// UnlikePost is a no-op when the user does not like the post
+func (rep *PostgresRepository) UnlikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	query := `DELETE FROM post_likes WHERE post_id = $1 AND user_id = $2;`
+	err := rep.timed(ctx, "UnlikePost", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, postId, userId)
+		return err
+	})
+	return err
+}
+
+func (rep *PostgresRepository) CountPostLikes(ctx context.Context, postId uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM post_likes WHERE post_id = $1;`
+	err := rep.timed(ctx, "CountPostLikes", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query, postId)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLikedPosts lists the posts the user likes that are still visible to them, latest like first
+func (rep *PostgresRepository) GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	var posts []*dto.PostUserDB
+
+	query := `SELECT ` + postWithAuthor + ` FROM post_likes l
+JOIN posts p ON p.post_id = l.post_id
+LEFT JOIN users u ON u.user_id = p.author_id
+WHERE l.user_id = $1 AND p.deleted_at IS NULL AND (p.status = 'published' OR p.author_id = $1) AND ` + hasTag("$2") + `
+ORDER BY l.created_at DESC;`
+	err := rep.timed(ctx, "GetLikedPosts", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &posts, query, userId, tag)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetUserLikes lists every like of the user, whatever became of the post, oldest first
+func (rep *PostgresRepository) GetUserLikes(ctx context.Context, userId uuid.UUID) ([]*dto.ExportedLike, error) {
+	var likes []*dto.ExportedLike
+
+	query := `SELECT post_id, created_at FROM post_likes WHERE user_id = $1 ORDER BY created_at, post_id;`
+	err := rep.timed(ctx, "GetUserLikes", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &likes, query, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+// GetPostRevisions pages through the revisions of a post, latest first
+func (rep *PostgresRepository) GetPostRevisions(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*dto.PostRevisionDB, error) {
+	var revisions []*dto.PostRevisionDB
+
+	query := `SELECT * FROM post_revisions WHERE post_id = $1 ORDER BY edited_at DESC LIMIT $2 OFFSET $3;`
+	err := rep.timed(ctx, "GetPostRevisions", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &revisions, query, postId, limit, offset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (rep *PostgresRepository) CountPostRevisions(ctx context.Context, postId uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM post_revisions WHERE post_id = $1;`
+	err := rep.timed(ctx, "CountPostRevisions", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query, postId)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (rep *PostgresRepository) GetPostRevision(ctx context.Context, postId, revisionId uuid.UUID) (*dto.PostRevisionDB, error) {
+	revision := &dto.PostRevisionDB{}
+
+	query := `SELECT * FROM post_revisions WHERE post_id = $1 AND revision_id = $2;`
+	err := rep.timed(ctx, "GetPostRevision", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, revision, query, postId, revisionId)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// GetTags counts published posts per tag, tags used only by drafts or trashed posts are left out
+func (rep *PostgresRepository) GetTags(ctx context.Context) ([]*dto.TagDB, error) {
+	var tags []*dto.TagDB
+
+	query := `SELECT t.name, COUNT(*) AS count FROM tags t
+JOIN post_tags pt ON pt.tag_id = t.tag_id
+JOIN posts p ON p.post_id = pt.post_id
+WHERE p.status = 'published' AND p.deleted_at IS NULL
+GROUP BY t.name ORDER BY count DESC, t.name;`
+	err := rep.timed(ctx, "GetTags", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &tags, query)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (rep *PostgresRepository) CreatePasswordReset(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO password_resets (token_hash, user_id, expires_at) VALUES ($1, $2, $3);`
+	err := rep.timed(ctx, "CreatePasswordReset", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, tokenHash, userId, expiresAt)
+		return err
+	})
+	return err
+}
+
+// ResetPassword consumes a reset token and sets the new hash in one transaction, every session is logged out
+func (rep *PostgresRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	var userId uuid.UUID
+	err := rep.timed(ctx, "ResetPassword", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		query := `DELETE FROM password_resets WHERE token_hash = $1 AND expires_at > NOW() RETURNING user_id;`
+		if err := tx.GetContext(ctx, &userId, query, tokenHash); err != nil {
+			return err
+		}
+
+		query = `UPDATE users SET password_hash = $2 WHERE user_id = $1;`
+		if _, err := tx.ExecContext(ctx, query, userId, passwordHash); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM sessions WHERE user_id = $1;`
+		if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM password_resets WHERE user_id = $1;`
+		if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userId, nil
+}
+
+// CreateSession stores a new session of a user. Beyond max sessions the ones used least recently are
+// deleted with it, and so are expired ones; max of zero keeps every session.
+func (rep *PostgresRepository) CreateSession(ctx context.Context, session *dto.SessionDB, max int) (*dto.SessionDB, error) {
+	created := &dto.SessionDB{}
+	err := rep.timed(ctx, "CreateSession", func(ctx context.Context) error {
+		tx, err := rep.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		query := `INSERT INTO sessions (user_id, token_hash, user_agent, ip, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *;`
+		if err := tx.GetContext(ctx, created, query, session.UserId, session.TokenHash, session.UserAgent, session.IP, session.ExpiresAt); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM sessions WHERE user_id = $1 AND (expires_at <= NOW() OR session_id IN (
+SELECT session_id FROM sessions WHERE user_id = $1 AND expires_at > NOW() ORDER BY last_used_at DESC, created_at DESC OFFSET $2));`
+		if max > 0 {
+			if _, err := tx.ExecContext(ctx, query, session.UserId, max); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UseSession finds the unexpired session of a refresh token hash and marks it used now
+func (rep *PostgresRepository) UseSession(ctx context.Context, tokenHash string) (*dto.SessionDB, error) {
+	session := &dto.SessionDB{}
+
+	query := `UPDATE sessions SET last_used_at = NOW() WHERE token_hash = $1 AND expires_at > NOW() RETURNING *;`
+	err := rep.timed(ctx, "UseSession", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, session, query, tokenHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSessions lists the unexpired sessions of a user, the one used last first
+func (rep *PostgresRepository) GetSessions(ctx context.Context, userId uuid.UUID) ([]*dto.SessionDB, error) {
+	sessions := []*dto.SessionDB{}
+
+	query := `SELECT * FROM sessions WHERE user_id = $1 AND expires_at > NOW() ORDER BY last_used_at DESC, created_at DESC;`
+	err := rep.timed(ctx, "GetSessions", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &sessions, query, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// DeleteSession logs a user out of one of their sessions, sql.ErrNoRows when they have no such session
+func (rep *PostgresRepository) DeleteSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	var deleted uuid.UUID
+
+	query := `DELETE FROM sessions WHERE session_id = $1 AND user_id = $2 RETURNING session_id;`
+	return rep.timed(ctx, "DeleteSession", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &deleted, query, sessionId, userId)
+	})
+}
+
+// DeleteSessionByToken ends the session of a refresh token hash and returns its user
+func (rep *PostgresRepository) DeleteSessionByToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userId uuid.UUID
+
+	query := `DELETE FROM sessions WHERE token_hash = $1 RETURNING user_id;`
+	err := rep.timed(ctx, "DeleteSessionByToken", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &userId, query, tokenHash)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userId, nil
+}
+
+// DeleteOtherSessions logs a user out of every session but keep and returns how many there were
+func (rep *PostgresRepository) DeleteOtherSessions(ctx context.Context, userId, keep uuid.UUID) (int, error) {
+	var count int64
+
+	query := `DELETE FROM sessions WHERE user_id = $1 AND session_id <> $2;`
+	err := rep.timed(ctx, "DeleteOtherSessions", func(ctx context.Context) error {
+		res, err := rep.DB.ExecContext(ctx, query, userId, keep)
+		if err != nil {
+			return err
+		}
+		count, err = res.RowsAffected()
+		return err
+	})
+	return int(count), err
+}
+
+// CreatePreviewToken stores a preview link of the post, expired links of the post are dropped with it
+func (rep *PostgresRepository) CreatePreviewToken(ctx context.Context, postId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `WITH expired AS (DELETE FROM post_preview_tokens WHERE post_id = $1 AND expires_at <= NOW())
+INSERT INTO post_preview_tokens (token_hash, post_id, expires_at) VALUES ($2, $1, $3);`
+	return rep.timed(ctx, "CreatePreviewToken", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, postId, tokenHash, expiresAt)
+		return err
+	})
+}
+
+// DeletePreviewTokens revokes every preview link of the post and returns how many still worked
+func (rep *PostgresRepository) DeletePreviewTokens(ctx context.Context, postId uuid.UUID) (int, error) {
+	var revoked int
+	query := `WITH deleted AS (DELETE FROM post_preview_tokens WHERE post_id = $1 RETURNING expires_at)
+SELECT COUNT(*) FROM deleted WHERE expires_at > NOW();`
+	err := rep.timed(ctx, "DeletePreviewTokens", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &revoked, query, postId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return revoked, nil
+}
+
+// GetPreviewPost returns the post of a preview link that has not expired, whatever its status. A trashed
+// post, an unknown or expired link is sql.ErrNoRows.
+func (rep *PostgresRepository) GetPreviewPost(ctx context.Context, tokenHash string) (*dto.PostUserDB, error) {
+	post := &dto.PostUserDB{}
+
+	query := `SELECT ` + postWithAuthor + ` FROM post_preview_tokens t
+JOIN posts p ON p.post_id = t.post_id
+LEFT JOIN users u ON u.user_id = p.author_id
+WHERE t.token_hash = $1 AND t.expires_at > NOW() AND p.deleted_at IS NULL;`
+	err := rep.timed(ctx, "GetPreviewPost", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, post, query, tokenHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// purgeBatch runs one batch of a token purge, query gets the cutoff as $1 and the batch size as $2
+func (rep *PostgresRepository) purgeBatch(ctx context.Context, statement, query string, before time.Time, limit int) (int, error) {
+	var count int64
+	err := rep.timed(ctx, statement, func(ctx context.Context) error {
+		res, err := rep.DB.ExecContext(ctx, query, before, limit)
+		if err != nil {
+			return err
+		}
+		count, err = res.RowsAffected()
+		return err
+	})
+	return int(count), err
+}
+
+// CreateApiKey stores a new API key of a user
+func (rep *PostgresRepository) CreateApiKey(ctx context.Context, key *dto.ApiKeyDB) (*dto.ApiKeyDB, error) {
+	created := &dto.ApiKeyDB{}
+
+	query := `INSERT INTO api_keys (user_id, name, key_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *;`
+	err := rep.timed(ctx, "CreateApiKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, created, query, key.UserId, key.Name, key.KeyHash, key.Scopes, key.ExpiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UseApiKey finds the unexpired API key of a hash and marks it used now
+func (rep *PostgresRepository) UseApiKey(ctx context.Context, keyHash string) (*dto.ApiKeyDB, error) {
+	key := &dto.ApiKeyDB{}
+
+	query := `UPDATE api_keys SET last_used_at = NOW() WHERE key_hash = $1 AND (expires_at IS NULL OR expires_at > NOW()) RETURNING *;`
+	err := rep.timed(ctx, "UseApiKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, key, query, keyHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetApiKeys lists the API keys of a user, expired ones too, the newest first
+func (rep *PostgresRepository) GetApiKeys(ctx context.Context, userId uuid.UUID) ([]*dto.ApiKeyDB, error) {
+	keys := []*dto.ApiKeyDB{}
+
+	query := `SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC;`
+	err := rep.timed(ctx, "GetApiKeys", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &keys, query, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteApiKey revokes an API key of a user, sql.ErrNoRows if the user has no such key
+func (rep *PostgresRepository) DeleteApiKey(ctx context.Context, userId, keyId uuid.UUID) error {
+	var deleted uuid.UUID
+
+	query := `DELETE FROM api_keys WHERE key_id = $1 AND user_id = $2 RETURNING key_id;`
+	return rep.timed(ctx, "DeleteApiKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &deleted, query, keyId, userId)
+	})
+}
+
+// PurgeRefreshTokens deletes at most limit sessions whose refresh token expired before the cutoff, rows
+// other statements hold are left for the next batch
+func (rep *PostgresRepository) PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM sessions WHERE session_id IN (
+SELECT session_id FROM sessions WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED);`
+	return rep.purgeBatch(ctx, "PurgeRefreshTokens", query, before, limit)
+}
+
+// PurgePasswordResets deletes at most limit password reset tokens that expired before the cutoff
+func (rep *PostgresRepository) PurgePasswordResets(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM password_resets WHERE token_hash IN (
+SELECT token_hash FROM password_resets WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED);`
+	return rep.purgeBatch(ctx, "PurgePasswordResets", query, before, limit)
+}
+
+// PurgePreviewTokens deletes at most limit preview links that expired before the cutoff
+func (rep *PostgresRepository) PurgePreviewTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM post_preview_tokens WHERE token_hash IN (
+SELECT token_hash FROM post_preview_tokens WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED);`
+	return rep.purgeBatch(ctx, "PurgePreviewTokens", query, before, limit)
+}
+
+// PurgeIdempotencyKeys deletes at most limit stored responses that expired before the cutoff
+func (rep *PostgresRepository) PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `DELETE FROM idempotency_keys WHERE (user_id, idempotency_key) IN (
+SELECT user_id, idempotency_key FROM idempotency_keys WHERE expires_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED);`
+	return rep.purgeBatch(ctx, "PurgeIdempotencyKeys", query, before, limit)
+}
+
+// ClaimIdempotencyKey stores key for a request that is about to run. It is false when the user holds
+// the key already and it has not expired by now, a key that has is taken over.
+func (rep *PostgresRepository) ClaimIdempotencyKey(ctx context.Context, key *dto.IdempotencyKeyDB, now time.Time) (bool, error) {
+	query := `INSERT INTO idempotency_keys (user_id, idempotency_key, endpoint, request_hash, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE SET endpoint = EXCLUDED.endpoint, request_hash = EXCLUDED.request_hash,
+status = 0, content_type = '', response_body = NULL, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= $5
+RETURNING user_id;`
+	var userId uuid.UUID
+	err := rep.timed(ctx, "ClaimIdempotencyKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &userId, query, key.UserId, key.Key, key.Endpoint, key.RequestHash, now, key.ExpiresAt)
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (rep *PostgresRepository) GetIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) (*dto.IdempotencyKeyDB, error) {
+	stored := &dto.IdempotencyKeyDB{}
+	query := `SELECT * FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2;`
+	err := rep.timed(ctx, "GetIdempotencyKey", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, stored, query, userId, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that claimed the key
+func (rep *PostgresRepository) CompleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error {
+	query := `UPDATE idempotency_keys SET status = $3, content_type = $4, response_body = $5 WHERE user_id = $1 AND idempotency_key = $2;`
+	return rep.timed(ctx, "CompleteIdempotencyKey", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, userId, key, status, contentType, body)
+		return err
+	})
+}
+
+// DeleteIdempotencyKey lets go of a key whose request failed, so a retry runs it again
+func (rep *PostgresRepository) DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2;`
+	return rep.timed(ctx, "DeleteIdempotencyKey", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, userId, key)
+		return err
+	})
+}
+
+func (rep *PostgresRepository) AddAuditEntry(ctx context.Context, entry *dto.AuditEntryDB) error {
+	query := `INSERT INTO audit_log (user_id, action, target_id, ip, user_agent) VALUES ($1, $2, $3, $4, $5);`
+	err := rep.timed(ctx, "AddAuditEntry", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, entry.UserId, entry.Action, entry.TargetId, entry.IP, entry.UserAgent)
+		return err
+	})
+	return err
+}
+
+// auditFilter matches the rows of audit_log a filter bound to $1 and $2 asks for
+const auditFilter = `($1::uuid IS NULL OR user_id = $1) AND ($2 = '' OR action = $2)`
+
+// GetAuditEntries pages through the audit log, latest first
+func (rep *PostgresRepository) GetAuditEntries(ctx context.Context, filter dto.AuditFilter, limit, offset int) ([]*dto.AuditEntryDB, error) {
+	var entries []*dto.AuditEntryDB
+
+	query := `SELECT * FROM audit_log WHERE ` + auditFilter + ` ORDER BY created_at DESC, audit_id LIMIT $3 OFFSET $4;`
+	err := rep.timed(ctx, "GetAuditEntries", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &entries, query, filter.UserId, filter.Action, limit, offset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (rep *PostgresRepository) CountAuditEntries(ctx context.Context, filter dto.AuditFilter) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM audit_log WHERE ` + auditFilter + `;`
+	err := rep.timed(ctx, "CountAuditEntries", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query, filter.UserId, filter.Action)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetPendingEvents returns up to limit unsent events in the order they were written. Changes of one post
+// hold its row lock until they commit, so its events are committed in seq order as well.
+func (rep *PostgresRepository) GetPendingEvents(ctx context.Context, limit int) ([]*dto.OutboxEventDB, error) {
+	var events []*dto.OutboxEventDB
+
+	query := `SELECT * FROM outbox_events WHERE sent_at IS NULL ORDER BY seq LIMIT $1;`
+	err := rep.timed(ctx, "GetPendingEvents", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &events, query, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (rep *PostgresRepository) MarkEventsSent(ctx context.Context, ids []uuid.UUID) error {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+
+	query := `UPDATE outbox_events SET sent_at = NOW() WHERE event_id = ANY($1::uuid[]);`
+	err := rep.timed(ctx, "MarkEventsSent", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, pq.Array(raw))
+		return err
+	})
+	return err
+}
+
+// Subscribe is a no-op when the reader already follows the author, an unknown user is sql.ErrNoRows
+func (rep *PostgresRepository) Subscribe(ctx context.Context, readerId, authorId uuid.UUID) error {
+	query := `INSERT INTO subscriptions (reader_id, author_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	err := rep.timed(ctx, "Subscribe", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, readerId, authorId)
+		return err
+	})
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23503" {
+			return sql.ErrNoRows
+		}
+		if ok && pgErr.Code == "23514" && pgErr.Constraint == "subscriptions_not_self" {
+			return errors.ErrorServiceSelfSubscription
+		}
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe is a no-op when the reader does not follow the author
+func (rep *PostgresRepository) Unsubscribe(ctx context.Context, readerId, authorId uuid.UUID) error {
+	query := `DELETE FROM subscriptions WHERE reader_id = $1 AND author_id = $2;`
+	err := rep.timed(ctx, "Unsubscribe", func(ctx context.Context) error {
+		_, err := rep.DB.ExecContext(ctx, query, readerId, authorId)
+		return err
+	})
+	return err
+}
+
+func (rep *PostgresRepository) CountSubscribers(ctx context.Context, authorId uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM subscriptions WHERE author_id = $1;`
+	err := rep.timed(ctx, "CountSubscribers", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query, authorId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetSubscriptions pages through the authors the reader follows, latest subscription first
+func (rep *PostgresRepository) GetSubscriptions(ctx context.Context, readerId uuid.UUID, limit, offset int) ([]*dto.SubscriptionDB, error) {
+	var subscriptions []*dto.SubscriptionDB
+
+	query := `SELECT s.author_id, u.email, u.display_name, s.created_at FROM subscriptions s
+JOIN users u ON u.user_id = s.author_id
+WHERE s.reader_id = $1 ORDER BY s.created_at DESC, s.author_id LIMIT $2 OFFSET $3;`
+	err := rep.timed(ctx, "GetSubscriptions", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &subscriptions, query, readerId, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (rep *PostgresRepository) CountSubscriptions(ctx context.Context, readerId uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM subscriptions WHERE reader_id = $1;`
+	err := rep.timed(ctx, "CountSubscriptions", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &count, query, readerId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// NotifySubscribers gives every subscriber of the author a notification about the post, in one statement
+// however many there are. Only a published post notifies, and a subscriber notified about it before is skipped,
+// so running it again for the same post adds nothing.
+func (rep *PostgresRepository) NotifySubscribers(ctx context.Context, postId uuid.UUID) (int64, error) {
+	query := `INSERT INTO notifications (user_id, post_id, type)
+SELECT s.reader_id, p.post_id, $2 FROM posts p
+JOIN subscriptions s ON s.author_id = p.author_id
+WHERE p.post_id = $1 AND p.status = 'published' AND p.deleted_at IS NULL
+ON CONFLICT (user_id, post_id) DO NOTHING;`
+	var count int64
+	err := rep.timed(ctx, "NotifySubscribers", func(ctx context.Context) error {
+		res, err := rep.DB.ExecContext(ctx, query, postId, types.NotificationPostPublished)
+		if err != nil {
+			return err
+		}
+		count, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetNotifications pages through the notifications of the user, latest first, unread narrows them to
+// those not marked read
+func (rep *PostgresRepository) GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) ([]*dto.NotificationDB, error) {
+	var notifications []*dto.NotificationDB
+
+	query := `SELECT n.*, p.title, p.author_id, u.email AS author_email, u.display_name AS author_display_name FROM notifications n
+JOIN posts p ON p.post_id = n.post_id
+JOIN users u ON u.user_id = p.author_id
+WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+ORDER BY n.created_at DESC, n.notification_id LIMIT $3 OFFSET $4;`
+	err := rep.timed(ctx, "GetNotifications", func(ctx context.Context) error {
+		return rep.DB.SelectContext(ctx, &notifications, query, userId, unread, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// CountNotifications counts every notification of the user and the unread ones among them
+func (rep *PostgresRepository) CountNotifications(ctx context.Context, userId uuid.UUID) (total, unread int, err error) {
+	var counts struct {
+		Total  int `db:"total"`
+		Unread int `db:"unread"`
+	}
+
+	query := `SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE read_at IS NULL) AS unread
+FROM notifications WHERE user_id = $1;`
+	err = rep.timed(ctx, "CountNotifications", func(ctx context.Context) error {
+		return rep.DB.GetContext(ctx, &counts, query, userId)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return counts.Total, counts.Unread, nil
+}
+
+// MarkNotificationsRead marks the unread notifications of the user among ids read, every unread one
+// without ids. Notifications of other users are left alone, it returns how many were marked.
+func (rep *PostgresRepository) MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (int64, error) {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+
+	query := `UPDATE notifications SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::uuid[]) = 0 OR notification_id = ANY($2::uuid[]));`
+	var count int64
+	err := rep.timed(ctx, "MarkNotificationsRead", func(ctx context.Context) error {
+		res, err := rep.DB.ExecContext(ctx, query, userId, pq.Array(raw))
+		if err != nil {
+			return err
+		}
+		count, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
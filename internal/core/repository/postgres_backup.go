@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+// Export* methods stream rows one by one so a backup never holds a whole table in memory. Backups run as
+// long as they need, the methods here take the context of the run instead of the query timeout.
+
+func (rep *PostgresRepository) ExportUsers(ctx context.Context, fn func(*dto.UserRecord) error) error {
+	rows, err := rep.DB.QueryxContext(ctx, `SELECT * FROM users ORDER BY user_id;`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &dto.UserDB{}
+		if err := rows.StructScan(user); err != nil {
+			return err
+		}
+		err := fn(&dto.UserRecord{
+			UserId:        user.UserId,
+			Email:         user.Email,
+			PasswordHash:  user.PasswordHash,
+			Role:          user.Role,
+			DisplayName:   user.DisplayName,
+			AvatarUrl:     user.AvatarUrl,
+			EmailVerified: &user.EmailVerified,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (rep *PostgresRepository) ExportPosts(ctx context.Context, since time.Time, fn func(*dto.PostRecord) error) error {
+	rows, err := rep.DB.QueryxContext(ctx, `SELECT p.*, `+postTags+` FROM posts p WHERE p.updated_at > $1 ORDER BY p.updated_at;`, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post := &dto.PostDB{}
+		if err := rows.StructScan(post); err != nil {
+			return err
+		}
+		err := fn(&dto.PostRecord{
+			PostId:         post.PostId,
+			AuthorId:       post.AuthorId,
+			IdempotencyKey: post.IdempotencyKey,
+			Title:          post.Title,
+			Content:        post.Content,
+			Excerpt:        post.Excerpt,
+			Status:         post.Status,
+			PublishAt:      post.PublishAt,
+			DeletedAt:      post.DeletedAt,
+			Tags:           post.Tags,
+			ImportHash:     post.ImportHash,
+			CreatedAt:      post.CreatedAt,
+			UpdatedAt:      post.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (rep *PostgresRepository) ExportImages(ctx context.Context, since time.Time, fn func(*dto.ImageRecord) error) error {
+	rows, err := rep.DB.QueryxContext(ctx, `SELECT * FROM images WHERE updated_at > $1 ORDER BY updated_at;`, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image := &dto.ImageDB{}
+		if err := rows.StructScan(image); err != nil {
+			return err
+		}
+		err := fn(&dto.ImageRecord{
+			ImageId:   image.ImageId,
+			PostId:    image.PostId,
+			ImageUrl:  image.ImageUrl,
+			Position:  image.Position,
+			Caption:   image.Caption,
+			SizeBytes: image.SizeBytes,
+			CreatedAt: image.CreatedAt,
+			UpdatedAt: image.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Restore* methods upsert by primary key, so replaying the same backup twice is harmless
+
+func (rep *PostgresRepository) RestoreUser(ctx context.Context, user *dto.UserRecord) error {
+	query := `INSERT INTO users (user_id, email, password_hash, role, display_name, avatar_url, email_verified)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, TRUE))
+ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email, password_hash = EXCLUDED.password_hash,
+role = EXCLUDED.role, display_name = EXCLUDED.display_name, avatar_url = EXCLUDED.avatar_url,
+email_verified = EXCLUDED.email_verified;`
+	_, err := rep.DB.ExecContext(ctx, query, user.UserId, user.Email, user.PasswordHash, user.Role, user.DisplayName, user.AvatarUrl, user.EmailVerified)
+	return err
+}
+
+func (rep *PostgresRepository) RestorePost(ctx context.Context, post *dto.PostRecord) error {
+	tx, err := rep.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO posts (post_id, author_id, idempotency_key, title, content, status, publish_at, deleted_at, created_at, updated_at, excerpt, import_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (post_id) DO UPDATE SET author_id = EXCLUDED.author_id, idempotency_key = EXCLUDED.idempotency_key,
+title = EXCLUDED.title, content = EXCLUDED.content, excerpt = EXCLUDED.excerpt, status = EXCLUDED.status,
+publish_at = EXCLUDED.publish_at, deleted_at = EXCLUDED.deleted_at, created_at = EXCLUDED.created_at,
+import_hash = EXCLUDED.import_hash, word_count = NULL, reading_time_minutes = NULL;`
+	// the counts are not part of a backup, the restored content gets them on its first read
+	_, err = tx.ExecContext(ctx, query, post.PostId, post.AuthorId, post.IdempotencyKey, post.Title, post.Content, post.Status,
+		post.PublishAt, post.DeletedAt, post.CreatedAt, post.UpdatedAt, post.Excerpt, post.ImportHash)
+	if err != nil {
+		return err
+	}
+	if err := setPostTags(ctx, tx, post.PostId, post.Tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (rep *PostgresRepository) RestoreImage(ctx context.Context, image *dto.ImageRecord) error {
+	// backups written before images had a position leave it at zero, they then keep the order they were added in
+	query := `INSERT INTO images (image_id, post_id, image_url, created_at, position, caption, updated_at, size_bytes) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (image_id) DO UPDATE SET post_id = EXCLUDED.post_id, image_url = EXCLUDED.image_url,
+position = EXCLUDED.position, caption = EXCLUDED.caption, updated_at = EXCLUDED.updated_at, size_bytes = EXCLUDED.size_bytes;`
+	// and those written before images could be replaced have them unchanged since they were added
+	updatedAt := image.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = image.CreatedAt
+	}
+	_, err := rep.DB.ExecContext(ctx, query, image.ImageId, image.PostId, image.ImageUrl, image.CreatedAt, image.Position, image.Caption, updatedAt, image.SizeBytes)
+	return err
+}
+
+func (rep *PostgresRepository) UserExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := rep.DB.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1);`, id)
+	return exists, err
+}
+
+func (rep *PostgresRepository) PostExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := rep.DB.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM posts WHERE post_id = $1);`, id)
+	return exists, err
+}
@@ -1,15 +1,21 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/db/postgres"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
 func TestPostgresRepository_AddNewUser(t *testing.T) {
@@ -18,7 +24,7 @@ func TestPostgresRepository_AddNewUser(t *testing.T) {
 		t.Fatalf("failed to create sqlmock: %v", err)
 	}
 	defer db.Close()
-	repo := &PostgresRepository{DB: &postgres.DB{sqlx.NewDb(db, "postgres")}}
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
 
 	tests := []struct {
 		name        string
@@ -33,14 +39,12 @@ func TestPostgresRepository_AddNewUser(t *testing.T) {
 			setupMock: func() {
 				rows := sqlmock.NewRows([]string{
 					"user_id", "email", "password_hash", "role",
-					"refresh_token", "refresh_token_expiry_time",
 				}).AddRow(
 					uuid.New(), "test@example.com", "hashed_password", "user",
-					"refresh_token", "2024-12-31T23:59:59Z",
 				)
 
 				mock.ExpectQuery(`INSERT INTO users`).
-					WithArgs("test@example.com", "password_hash", "user", "refresh_token", sqlmock.AnyArg()).
+					WithArgs("test@example.com", "password_hash", "user").
 					WillReturnRows(rows)
 			},
 			wantErr: false,
@@ -50,19 +54,30 @@ func TestPostgresRepository_AddNewUser(t *testing.T) {
 			email: "existing@example.com",
 			setupMock: func() {
 				mock.ExpectQuery(`INSERT INTO users`).
-					WithArgs("existing@example.com", "password_hash", "user", "refresh_token", sqlmock.AnyArg()).
+					WithArgs("existing@example.com", "password_hash", "user").
 					WillReturnError(&pq.Error{Code: "23505"})
 			},
 			wantErr:     true,
 			expectedErr: errors.ErrorRepositoryUserAlreadyExsist,
 		},
+		{
+			name:  "bad role",
+			email: "test@example.com",
+			setupMock: func() {
+				mock.ExpectQuery(`INSERT INTO users`).
+					WithArgs("test@example.com", "password_hash", "user").
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "users_role_check"})
+			},
+			wantErr:     true,
+			expectedErr: errors.ErrorRepositoryBadRole,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			user, err := repo.AddNewUser(tt.email, "password_hash", "user", "refresh_token")
+			user, err := repo.AddNewUser(context.Background(), tt.email, "password_hash", "user")
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -79,3 +94,1976 @@ func TestPostgresRepository_AddNewUser(t *testing.T) {
 		})
 	}
 }
+
+var sessionColumns = []string{"session_id", "user_id", "token_hash", "user_agent", "ip", "created_at", "expires_at", "last_used_at"}
+
+func TestPostgresRepository_CreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, sessionId := uuid.New(), uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	session := &dto.SessionDB{UserId: userId, TokenHash: "hash", UserAgent: "curl/8.0", IP: "203.0.113.7", ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("beyond the cap", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO sessions \(user_id, token_hash, user_agent, ip, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING \*`).
+			WithArgs(userId, "hash", "curl/8.0", "203.0.113.7", session.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(sessionId, userId, "hash", "curl/8.0", "203.0.113.7", now, session.ExpiresAt, now))
+		mock.ExpectExec(`DELETE FROM sessions WHERE user_id = \$1 AND \(expires_at <= NOW\(\) OR session_id IN \(\s*SELECT session_id FROM sessions WHERE user_id = \$1 AND expires_at > NOW\(\) ORDER BY last_used_at DESC, created_at DESC OFFSET \$2\)\)`).
+			WithArgs(userId, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateSession(context.Background(), session, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, sessionId, created.SessionId)
+		assert.Equal(t, now, created.LastUsedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("without a cap", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO sessions`).
+			WithArgs(userId, "hash", "curl/8.0", "203.0.113.7", session.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(sessionId, userId, "hash", "curl/8.0", "203.0.113.7", now, session.ExpiresAt, now))
+		mock.ExpectCommit()
+
+		_, err := repo.CreateSession(context.Background(), session, 0)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_UseSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, sessionId := uuid.New(), uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	query := `UPDATE sessions SET last_used_at = NOW\(\) WHERE token_hash = \$1 AND expires_at > NOW\(\) RETURNING \*`
+
+	t.Run("live session", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(sessionId, userId, "hash", "", "", now, now.Add(time.Hour), now))
+
+		session, err := repo.UseSession(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, sessionId, session.SessionId)
+		assert.Equal(t, userId, session.UserId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ended or expired", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(sessionColumns))
+
+		_, err := repo.UseSession(context.Background(), "hash")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_GetSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM sessions WHERE user_id = \$1 AND expires_at > NOW\(\) ORDER BY last_used_at DESC, created_at DESC`).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(uuid.New(), userId, "phone", "Mobile", "203.0.113.7", now, now.Add(time.Hour), now.Add(time.Minute)).
+			AddRow(uuid.New(), userId, "laptop", "Desktop", "198.51.100.2", now, now.Add(time.Hour), now))
+
+	sessions, err := repo.GetSessions(context.Background(), userId)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, "Mobile", sessions[0].UserAgent)
+		assert.Equal(t, "198.51.100.2", sessions[1].IP)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_DeleteSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, sessionId := uuid.New(), uuid.New()
+
+	t.Run("one session", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM sessions WHERE session_id = \$1 AND user_id = \$2 RETURNING session_id`).
+			WithArgs(sessionId, userId).
+			WillReturnRows(sqlmock.NewRows([]string{"session_id"}).AddRow(sessionId))
+
+		assert.NoError(t, repo.DeleteSession(context.Background(), userId, sessionId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("session of another user", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM sessions WHERE session_id = \$1 AND user_id = \$2`).
+			WithArgs(sessionId, userId).
+			WillReturnRows(sqlmock.NewRows([]string{"session_id"}))
+
+		assert.ErrorIs(t, repo.DeleteSession(context.Background(), userId, sessionId), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("by refresh token", func(t *testing.T) {
+		mock.ExpectQuery(`DELETE FROM sessions WHERE token_hash = \$1 RETURNING user_id`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+
+		got, err := repo.DeleteSessionByToken(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, userId, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("all but one", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM sessions WHERE user_id = \$1 AND session_id <> \$2`).
+			WithArgs(userId, sessionId).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		count, err := repo.DeleteOtherSessions(context.Background(), userId, sessionId)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var apiKeyColumns = []string{"key_id", "user_id", "name", "key_hash", "scopes", "created_at", "last_used_at", "expires_at"}
+
+func TestPostgresRepository_CreateApiKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, keyId := uuid.New(), uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+	key := &dto.ApiKeyDB{UserId: userId, Name: "ci", KeyHash: "hash", Scopes: []string{"read", "write"}, ExpiresAt: &expires}
+
+	mock.ExpectQuery(`INSERT INTO api_keys \(user_id, name, key_hash, scopes, expires_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING \*`).
+		WithArgs(userId, "ci", "hash", sqlmock.AnyArg(), &expires).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).AddRow(keyId, userId, "ci", "hash", "{read,write}", now, nil, expires))
+
+	created, err := repo.CreateApiKey(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, keyId, created.KeyId)
+	assert.Equal(t, []string{"read", "write"}, []string(created.Scopes))
+	assert.Nil(t, created.LastUsedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_UseApiKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, keyId := uuid.New(), uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	query := `UPDATE api_keys SET last_used_at = NOW\(\) WHERE key_hash = \$1 AND \(expires_at IS NULL OR expires_at > NOW\(\)\) RETURNING \*`
+
+	t.Run("live key", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(apiKeyColumns).AddRow(keyId, userId, "ci", "hash", "{read}", now, now, nil))
+
+		key, err := repo.UseApiKey(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, userId, key.UserId)
+		assert.Equal(t, []string{"read"}, []string(key.Scopes))
+		assert.Nil(t, key.ExpiresAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("revoked or expired", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+
+		_, err := repo.UseApiKey(context.Background(), "hash")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_GetApiKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM api_keys WHERE user_id = \$1 ORDER BY created_at DESC`).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow(uuid.New(), userId, "deploy", "a", "{read,write}", now.Add(time.Hour), nil, nil).
+			AddRow(uuid.New(), userId, "ci", "b", "{read}", now, now, now.Add(time.Minute)))
+
+	keys, err := repo.GetApiKeys(context.Background(), userId)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, "deploy", keys[0].Name)
+		assert.NotNil(t, keys[1].ExpiresAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_DeleteApiKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, keyId := uuid.New(), uuid.New()
+	query := `DELETE FROM api_keys WHERE key_id = \$1 AND user_id = \$2 RETURNING key_id`
+
+	t.Run("own key", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(keyId, userId).
+			WillReturnRows(sqlmock.NewRows([]string{"key_id"}).AddRow(keyId))
+
+		assert.NoError(t, repo.DeleteApiKey(context.Background(), userId, keyId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("key of another user", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(keyId, userId).
+			WillReturnRows(sqlmock.NewRows([]string{"key_id"}))
+
+		assert.ErrorIs(t, repo.DeleteApiKey(context.Background(), userId, keyId), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_VerifyEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+	query := `UPDATE users SET email_verified = TRUE WHERE user_id = \$1 AND email = \$2 RETURNING user_id`
+
+	t.Run("current email", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userId, "author@example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+
+		assert.NoError(t, repo.VerifyEmail(context.Background(), userId, "author@example.com"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("email changed since", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userId, "old@example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		assert.ErrorIs(t, repo.VerifyEmail(context.Background(), userId, "old@example.com"), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET password_hash = \$3 WHERE user_id = \$1 AND password_hash = \$2`).
+		WithArgs(userId, "old_hash", "new_hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdatePasswordHash(context.Background(), userId, "old_hash", "new_hash")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_ResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+
+	t.Run("valid token", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`DELETE FROM password_resets WHERE token_hash = \$1 AND expires_at > NOW\(\) RETURNING user_id`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+		mock.ExpectExec(`UPDATE users SET password_hash = \$2 WHERE user_id = \$1`).
+			WithArgs(userId, "new_hash").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM sessions WHERE user_id = \$1`).
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM password_resets WHERE user_id = \$1`).
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		got, err := repo.ResetPassword(context.Background(), "hash", "new_hash")
+		assert.NoError(t, err)
+		assert.Equal(t, userId, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("expired or unknown token", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`DELETE FROM password_resets`).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.ResetPassword(context.Background(), "hash", "new_hash")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_UpdateUserRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+
+	t.Run("role updated", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{
+			"user_id", "email", "password_hash", "role",
+		}).AddRow(userId, "test@example.com", "hashed_password", "admin")
+		mock.ExpectQuery(`UPDATE users SET role = \$2 WHERE user_id = \$1`).
+			WithArgs(userId, types.Admin).
+			WillReturnRows(rows)
+
+		user, err := repo.UpdateUserRole(context.Background(), userId, types.Admin)
+		assert.NoError(t, err)
+		assert.Equal(t, types.Admin, user.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejected by check constraint", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE users SET role`).
+			WithArgs(userId, types.Role("root")).
+			WillReturnError(&pq.Error{Code: "23514", Constraint: "users_role_check"})
+
+		_, err := repo.UpdateUserRole(context.Background(), userId, types.Role("root"))
+		assert.ErrorIs(t, err, errors.ErrorRepositoryBadRole)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Profile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+	name := "Alice"
+
+	mock.ExpectQuery(`UPDATE users SET display_name = \$2 WHERE user_id = \$1 RETURNING \*`).
+		WithArgs(userId, &name).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "display_name"}).AddRow(userId, "alice@example.com", name))
+	user, err := repo.UpdateDisplayName(context.Background(), userId, &name)
+	assert.NoError(t, err)
+	assert.Equal(t, &name, user.DisplayName)
+
+	t.Run("first avatar", func(t *testing.T) {
+		mock.ExpectQuery(`WITH previous AS \(SELECT avatar_url FROM users WHERE user_id = \$1 FOR UPDATE\)\s+UPDATE users SET avatar_url = \$2 WHERE user_id = \$1 RETURNING \(SELECT avatar_url FROM previous\)`).
+			WithArgs(userId, "/media/avatars/a.png").
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}).AddRow(nil))
+		previous, err := repo.SetAvatar(context.Background(), userId, "/media/avatars/a.png")
+		assert.NoError(t, err)
+		assert.Nil(t, previous)
+	})
+
+	t.Run("replaced avatar", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE users SET avatar_url = \$2`).
+			WithArgs(userId, "/media/avatars/b.png").
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}).AddRow("/media/avatars/a.png"))
+		previous, err := repo.SetAvatar(context.Background(), userId, "/media/avatars/b.png")
+		assert.NoError(t, err)
+		if assert.NotNil(t, previous) {
+			assert.Equal(t, "/media/avatars/a.png", *previous)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE users SET avatar_url = \$2`).
+			WithArgs(userId, "/media/avatars/c.png").
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}))
+		_, err := repo.SetAvatar(context.Background(), userId, "/media/avatars/c.png")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_DeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+
+	t.Run("anonymize", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT avatar_url FROM users WHERE user_id = \$1 FOR UPDATE`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}).AddRow("/media/avatars/a.png"))
+		mock.ExpectExec(`UPDATE posts SET author_id = \$2 WHERE author_id = \$1`).
+			WithArgs(userId, dto.DeletedUserId).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`UPDATE post_revisions SET edited_by = \$2 WHERE edited_by = \$1`).
+			WithArgs(userId, dto.DeletedUserId).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`DELETE FROM users WHERE user_id = \$1`).
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		deleted, err := repo.DeleteUser(context.Background(), userId, dto.DeletedUserId)
+		assert.NoError(t, err)
+		if assert.NotNil(t, deleted) && assert.NotNil(t, deleted.AvatarUrl) {
+			assert.Equal(t, "/media/avatars/a.png", *deleted.AvatarUrl)
+			assert.Empty(t, deleted.ImageUrls, "the images stay with the posts")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT avatar_url FROM users WHERE user_id = \$1 FOR UPDATE`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}).AddRow(nil))
+		mock.ExpectQuery(`SELECT i.image_url FROM images i JOIN posts p ON p.post_id = i.post_id WHERE p.author_id = \$1`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"image_url"}).AddRow("/media/1.png").AddRow("/media/2.png"))
+		mock.ExpectExec(`DELETE FROM users WHERE user_id = \$1`).
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		deleted, err := repo.DeleteUser(context.Background(), userId, uuid.Nil)
+		assert.NoError(t, err)
+		if assert.NotNil(t, deleted) {
+			assert.Nil(t, deleted.AvatarUrl)
+			assert.Equal(t, []string{"/media/1.png", "/media/2.png"}, deleted.ImageUrls)
+		}
+	})
+
+	t.Run("failure rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT avatar_url FROM users`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}).AddRow(nil))
+		mock.ExpectExec(`UPDATE posts SET author_id`).
+			WithArgs(userId, dto.DeletedUserId).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.DeleteUser(context.Background(), userId, dto.DeletedUserId)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT avatar_url FROM users`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"avatar_url"}))
+		mock.ExpectRollback()
+
+		_, err := repo.DeleteUser(context.Background(), userId, uuid.Nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_UpdatePostBadStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, editorId := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO post_revisions`).
+		WithArgs(postId, editorId).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+	mock.ExpectQuery(`UPDATE posts p SET status = \$3 WHERE p.post_id = \$1`).
+		WithArgs(postId, nil, types.PostStatus("hidden")).
+		WillReturnError(&pq.Error{Code: "23514", Constraint: "posts_status_check"})
+	mock.ExpectRollback()
+
+	status := types.PostStatus("hidden")
+	_, err = repo.UpdatePost(context.Background(), postId, editorId, &dto.PostPatch{Status: &status}, nil, nil)
+	assert.ErrorIs(t, err, errors.ErrorRepositoryBadStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_PublishDuePosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	query := `WITH due AS \(UPDATE posts SET status = 'published' WHERE status = 'scheduled' AND publish_at <= COALESCE\(\$1, NOW\(\)\) AND deleted_at IS NULL RETURNING \*\),\s+events AS \(INSERT INTO outbox_events`
+
+	t.Run("database clock", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		mock.ExpectQuery(query).
+			WithArgs(nil, types.EventPostStatusChanged).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "status", "tags"}).
+				AddRow(first, types.Published, "{go,intro}").
+				AddRow(second, types.Published, "{}"))
+
+		posts, err := repo.PublishDuePosts(context.Background(), time.Time{})
+		assert.NoError(t, err)
+		if !assert.Len(t, posts, 2) {
+			return
+		}
+		assert.Equal(t, first, posts[0].PostId)
+		assert.Equal(t, []string{"go", "intro"}, []string(posts[0].Tags))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("injected clock", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WithArgs(now, types.EventPostStatusChanged).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+
+		posts, err := repo.PublishDuePosts(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Empty(t, posts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_PurgeExpiredTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	before := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		purge func(ctx context.Context, before time.Time, limit int) (int, error)
+	}{
+		{
+			name:  "sessions",
+			query: `DELETE FROM sessions WHERE session_id IN \(\s*SELECT session_id FROM sessions WHERE expires_at < \$1 LIMIT \$2 FOR UPDATE SKIP LOCKED\)`,
+			purge: repo.PurgeRefreshTokens,
+		},
+		{
+			name:  "password resets",
+			query: `DELETE FROM password_resets WHERE token_hash IN \(\s*SELECT token_hash FROM password_resets WHERE expires_at < \$1 LIMIT \$2 FOR UPDATE SKIP LOCKED\)`,
+			purge: repo.PurgePasswordResets,
+		},
+		{
+			name:  "preview tokens",
+			query: `DELETE FROM post_preview_tokens WHERE token_hash IN \(\s*SELECT token_hash FROM post_preview_tokens WHERE expires_at < \$1 LIMIT \$2 FOR UPDATE SKIP LOCKED\)`,
+			purge: repo.PurgePreviewTokens,
+		},
+		{
+			name:  "idempotency keys",
+			query: `DELETE FROM idempotency_keys WHERE \(user_id, idempotency_key\) IN \(\s*SELECT user_id, idempotency_key FROM idempotency_keys WHERE expires_at < \$1 LIMIT \$2 FOR UPDATE SKIP LOCKED\)`,
+			purge: repo.PurgeIdempotencyKeys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(tt.query).
+				WithArgs(before, 500).
+				WillReturnResult(sqlmock.NewResult(0, 500))
+
+			count, err := tt.purge(context.Background(), before, 500)
+			assert.NoError(t, err)
+			assert.Equal(t, 500, count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run(tt.name+" failing", func(t *testing.T) {
+			mock.ExpectExec(tt.query).
+				WithArgs(before, 500).
+				WillReturnError(sql.ErrConnDone)
+
+			count, err := tt.purge(context.Background(), before, 500)
+			assert.ErrorIs(t, err, sql.ErrConnDone)
+			assert.Zero(t, count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepository_ClaimIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := &dto.IdempotencyKeyDB{UserId: uuid.New(), Key: "publish-1", Endpoint: "PATCH /posts/1/status",
+		RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour)}
+	query := `INSERT INTO idempotency_keys .+ ON CONFLICT \(user_id, idempotency_key\) DO UPDATE .+ WHERE idempotency_keys.expires_at <= \$5\s+RETURNING user_id`
+
+	t.Run("claimed", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(key.UserId, key.Key, key.Endpoint, key.RequestHash, now, key.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(key.UserId))
+
+		claimed, err := repo.ClaimIdempotencyKey(context.Background(), key, now)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("held", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(key.UserId, key.Key, key.Endpoint, key.RequestHash, now, key.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		claimed, err := repo.ClaimIdempotencyKey(context.Background(), key, now)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+
+		claimed, err := repo.ClaimIdempotencyKey(context.Background(), key, now)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE idempotency_keys SET status = \$3, content_type = \$4, response_body = \$5 WHERE user_id = \$1 AND idempotency_key = \$2`).
+			WithArgs(key.UserId, key.Key, 201, "application/json", []byte(`{}`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.CompleteIdempotencyKey(context.Background(), key.UserId, key.Key, 201, "application/json", []byte(`{}`)))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Trash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId := uuid.New()
+
+	t.Run("trashed post is not found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM posts p WHERE p.post_id = \$1 AND p.deleted_at IS NULL`).
+			WithArgs(postId).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetPostById(context.Background(), postId)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("expired trash", func(t *testing.T) {
+		before := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT post_id FROM posts WHERE deleted_at < \$1`).
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(postId))
+
+		ids, err := repo.GetExpiredTrash(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{postId}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("purge only touches trashed posts", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM posts WHERE post_id = \$1 AND deleted_at IS NOT NULL`).
+			WithArgs(postId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.PurgePost(context.Background(), postId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_PostTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	authorId, postId := uuid.New(), uuid.New()
+	tags := []string{"golang", "db"}
+
+	t.Run("create writes tags in the same transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts`).
+			WithArgs(authorId, "key", "t", "c", "", 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id"}).AddRow(postId, authorId))
+		mock.ExpectExec(`DELETE FROM post_tags WHERE post_id = \$1`).
+			WithArgs(postId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO tags \(name\) SELECT unnest\(\$1::text\[\]\) ON CONFLICT \(name\) DO NOTHING`).
+			WithArgs(pq.Array(tags)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\) SELECT \$1, tag_id FROM tags WHERE name = ANY\(\$2\)`).
+			WithArgs(postId, pq.Array(tags)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO outbox_events \(post_id, type, payload\)`).
+			WithArgs(postId, types.EventPostCreated, types.PostStatus("")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		post, err := repo.CreatePost(context.Background(), authorId, "key", "t", "c", "", &dto.ReadingStats{WordCount: 1, ReadingTimeMinutes: 1}, tags)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"db", "golang"}, []string(post.Tags))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed tag write rolls the post back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id"}).AddRow(postId, authorId))
+		mock.ExpectExec(`DELETE FROM post_tags`).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.CreatePost(context.Background(), authorId, "key", "t", "c", "", nil, tags)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update without tags keeps them", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO post_revisions`).
+			WithArgs(postId, authorId).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+		mock.ExpectQuery(`UPDATE posts p SET title = \$3, content = \$4, status = \$5 WHERE`).
+			WithArgs(postId, nil, "t", "c", types.Published).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "status"}).AddRow(postId, types.Published))
+		mock.ExpectExec(`INSERT INTO outbox_events \(post_id, type, payload\)`).
+			WithArgs(postId, types.EventPostStatusChanged, types.Draft).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT ARRAY\(SELECT t.name FROM post_tags`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow("{db,golang}"))
+		mock.ExpectCommit()
+
+		title, content, status := "t", "c", types.Published
+		post, err := repo.UpdatePost(context.Background(), postId, authorId, &dto.PostPatch{Title: &title, Content: &content, Status: &status}, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"db", "golang"}, []string(post.Tags))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty excerpt is stored as none", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO post_revisions`).
+			WithArgs(postId, authorId).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+		mock.ExpectQuery(`UPDATE posts p SET excerpt = NULLIF\(\$3, ''\) WHERE`).
+			WithArgs(postId, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "excerpt"}).AddRow(postId, nil))
+		mock.ExpectQuery(`SELECT ARRAY\(SELECT t.name FROM post_tags`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow("{}"))
+		mock.ExpectCommit()
+
+		excerpt := ""
+		post, err := repo.UpdatePost(context.Background(), postId, authorId, &dto.PostPatch{Excerpt: &excerpt}, nil, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, post.Excerpt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_SearchPosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, authorId := uuid.New(), uuid.New()
+	rank := `ts_rank\(to_tsvector\('simple', p.title \|\| ' ' \|\| p.content\), q\) AS rank FROM posts p`
+	match := `CROSS JOIN plainto_tsquery\('simple', \$1\) q WHERE p.deleted_at IS NULL AND `
+	order := ` AND to_tsvector\('simple', p.title \|\| ' ' \|\| p.content\) @@ q ORDER BY rank DESC, p.created_at DESC LIMIT \$2 OFFSET \$3`
+
+	t.Run("published", func(t *testing.T) {
+		mock.ExpectQuery(rank+`.*`+match+`p.status = 'published'`+order).
+			WithArgs("go tips", 20, 40).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "title", "rank"}).AddRow(postId, "Go tips", 0.75))
+
+		posts, err := repo.SearchPublishedPosts(context.Background(), "go tips", 20, 40)
+		assert.NoError(t, err)
+		assert.Len(t, posts, 1)
+		assert.Equal(t, postId, posts[0].PostId)
+		assert.Equal(t, 0.75, posts[0].Rank)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("with author", func(t *testing.T) {
+		mock.ExpectQuery(rank+`.*`+match+`\(p.status = 'published' OR p.author_id = \$4\)`+order).
+			WithArgs("go tips", 20, 0, authorId).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "rank"}))
+
+		posts, err := repo.SearchPostsWithAuthor(context.Background(), authorId, "go tips", 20, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, posts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_GetTrendingPosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	liked, viewed, quiet := uuid.New(), uuid.New(), uuid.New()
+	newer, older := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := `SELECT .*, COALESCE\(t.score, 0\) AS score FROM posts p\s+LEFT JOIN users u ON u.user_id = p.author_id\s+LEFT JOIN \(` +
+		`.*FROM post_views pv WHERE pv.viewed_on >= .*\$1.*UNION ALL.*\$2 \* GREATEST.*FROM post_likes pl WHERE pl.created_at >= NOW\(\) - \$1 \* INTERVAL '1 second'.*` +
+		`\) t ON t.post_id = p.post_id\s+WHERE p.status = 'published' AND p.deleted_at IS NULL\s+ORDER BY score DESC, p.created_at DESC, p.post_id LIMIT \$3`
+
+	t.Run("ranked by score", func(t *testing.T) {
+		// two views and a like of a week window weighing 3: the like outranks the views
+		mock.ExpectQuery(query).
+			WithArgs(float64(7*24*60*60), 3.0, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "title", "created_at", "score"}).
+				AddRow(liked, "Liked", older, 2.5).
+				AddRow(viewed, "Viewed", older, 1.75).
+				AddRow(quiet, "Quiet", newer, 0.0))
+
+		posts, err := repo.GetTrendingPosts(context.Background(), 7*24*time.Hour, 3, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, posts, 3) {
+			assert.Equal(t, []uuid.UUID{liked, viewed, quiet}, []uuid.UUID{posts[0].PostId, posts[1].PostId, posts[2].PostId})
+			assert.Equal(t, []float64{2.5, 1.75, 0}, []float64{posts[0].Score, posts[1].Score, posts[2].Score})
+			assert.Equal(t, "Liked", posts[0].Title)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no views or likes", func(t *testing.T) {
+		// every score is zero, the newest published post comes first
+		mock.ExpectQuery(query).
+			WithArgs(float64(60*60), 1.5, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "created_at", "score"}).
+				AddRow(quiet, newer, 0.0).
+				AddRow(liked, older, 0.0))
+
+		posts, err := repo.GetTrendingPosts(context.Background(), time.Hour, 1.5, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, posts, 2) {
+			assert.Equal(t, quiet, posts[0].PostId)
+			assert.Equal(t, 0.0, posts[0].Score)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_PreviewTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId := uuid.New()
+	expiresAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("create", func(t *testing.T) {
+		mock.ExpectExec(`WITH expired AS \(DELETE FROM post_preview_tokens WHERE post_id = \$1 AND expires_at <= NOW\(\)\)\s+INSERT INTO post_preview_tokens \(token_hash, post_id, expires_at\) VALUES \(\$2, \$1, \$3\)`).
+			WithArgs(postId, "hash", expiresAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.CreatePreviewToken(context.Background(), postId, "hash", expiresAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mock.ExpectQuery(`WITH deleted AS \(DELETE FROM post_preview_tokens WHERE post_id = \$1 RETURNING expires_at\)\s+SELECT COUNT\(\*\) FROM deleted WHERE expires_at > NOW\(\)`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		revoked, err := repo.DeletePreviewTokens(context.Background(), postId)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	post := `SELECT .* FROM post_preview_tokens t\s+JOIN posts p ON p.post_id = t.post_id\s+LEFT JOIN users u ON u.user_id = p.author_id\s+` +
+		`WHERE t.token_hash = \$1 AND t.expires_at > NOW\(\) AND p.deleted_at IS NULL`
+
+	t.Run("read", func(t *testing.T) {
+		mock.ExpectQuery(post).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "status"}).AddRow(postId, "draft"))
+
+		found, err := repo.GetPreviewPost(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, postId, found.PostId)
+		assert.Equal(t, types.Draft, found.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("expired or unknown", func(t *testing.T) {
+		mock.ExpectQuery(post).
+			WithArgs("other").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+
+		_, err := repo.GetPreviewPost(context.Background(), "other")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_PostViews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, userId := uuid.New(), uuid.New()
+	day := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("record", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO post_views \(post_id, user_id, viewed_on\)\s+SELECT .* FROM unnest\(\$1::uuid\[\], \$2::uuid\[\], \$3::date\[\]\).*ON CONFLICT DO NOTHING`).
+			WithArgs(pq.Array([]string{postId.String()}), pq.Array([]string{userId.String()}), pq.Array([]string{"2025-01-31"})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.RecordViews(context.Background(), []dto.PostView{{PostId: postId, UserId: userId, Day: day}})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT viewed_on AS day, COUNT\(\*\) AS views FROM post_views WHERE post_id = \$1\s+GROUP BY viewed_on ORDER BY viewed_on`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"day", "views"}).AddRow(day, 3))
+
+		days, err := repo.GetPostViewStats(context.Background(), postId)
+		assert.NoError(t, err)
+		assert.Equal(t, []*dto.ViewDayDB{{Day: day, Views: 3}}, days)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Likes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, userId := uuid.New(), uuid.New()
+	insert := `INSERT INTO post_likes \(post_id, user_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`
+
+	t.Run("like twice", func(t *testing.T) {
+		mock.ExpectExec(insert).WithArgs(postId, userId).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insert).WithArgs(postId, userId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.NoError(t, repo.LikePost(context.Background(), postId, userId))
+		assert.NoError(t, repo.LikePost(context.Background(), postId, userId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("like removed post", func(t *testing.T) {
+		mock.ExpectExec(insert).WithArgs(postId, userId).WillReturnError(&pq.Error{Code: "23503"})
+
+		assert.ErrorIs(t, repo.LikePost(context.Background(), postId, userId), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unlike", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM post_likes WHERE post_id = \$1 AND user_id = \$2`).
+			WithArgs(postId, userId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.NoError(t, repo.UnlikePost(context.Background(), postId, userId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM post_likes WHERE post_id = \$1`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := repo.CountPostLikes(context.Background(), postId)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("liked posts", func(t *testing.T) {
+		mock.ExpectQuery(`AS likes_count FROM post_likes l\s+JOIN posts p ON p.post_id = l.post_id.*WHERE l.user_id = \$1 AND p.deleted_at IS NULL AND \(p.status = 'published' OR p.author_id = \$1\).*ORDER BY l.created_at DESC`).
+			WithArgs(userId, "golang").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "likes_count"}).AddRow(postId, 2))
+
+		posts, err := repo.GetLikedPosts(context.Background(), userId, "golang")
+		assert.NoError(t, err)
+		assert.Len(t, posts, 1)
+		assert.Equal(t, 2, posts[0].LikesCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Reports(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, userId, adminId := uuid.New(), uuid.New(), uuid.New()
+	insert := `INSERT INTO post_reports \(post_id, user_id, reason, comment\) VALUES \(\$1, \$2, \$3, \$4\)\s+ON CONFLICT \(post_id, user_id\) DO NOTHING`
+
+	t.Run("report", func(t *testing.T) {
+		reportId := uuid.New()
+		mock.ExpectQuery(insert).
+			WithArgs(postId, userId, types.ReportSpam, "casino links").
+			WillReturnRows(sqlmock.NewRows([]string{"report_id", "post_id", "user_id", "reason", "comment", "created_at", "resolved_at", "resolved_by", "resolution"}).
+				AddRow(reportId, postId, userId, "spam", "casino links", time.Now(), nil, nil, nil))
+
+		report, err := repo.AddReport(context.Background(), postId, userId, types.ReportSpam, "casino links")
+		assert.NoError(t, err)
+		assert.Equal(t, reportId, report.ReportId)
+		assert.Equal(t, types.ReportSpam, report.Reason)
+		assert.Nil(t, report.Resolution)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("report removed post", func(t *testing.T) {
+		mock.ExpectQuery(insert).WithArgs(postId, userId, types.ReportAbuse, "").WillReturnError(&pq.Error{Code: "23503"})
+
+		_, err := repo.AddReport(context.Background(), postId, userId, types.ReportAbuse, "")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		mock.ExpectQuery(`FROM post_reports r\s+JOIN posts p ON p.post_id = r.post_id\s+WHERE r.resolved_at IS NULL AND p.deleted_at IS NULL\s+GROUP BY`).
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "title", "author_id", "status", "reports", "spam", "abuse", "copyright", "other",
+				"comments", "first_reported_at", "last_reported_at"}).
+				AddRow(postId, "Buy now", uuid.New(), "published", 3, 2, 1, 0, 0, "{\"casino links\"}", time.Now(), time.Now()))
+
+		posts, err := repo.GetReportedPosts(context.Background(), 20, 0)
+		assert.NoError(t, err)
+		if !assert.Len(t, posts, 1) {
+			return
+		}
+		assert.Equal(t, 3, posts[0].Reports)
+		assert.Equal(t, 2, posts[0].Spam)
+		assert.Equal(t, []string{"casino links"}, []string(posts[0].Comments))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE post_reports SET resolved_at = NOW\(\), resolved_by = \$2, resolution = \$3\s+WHERE post_id = \$1 AND resolved_at IS NULL`).
+			WithArgs(postId, adminId, types.ModerationDismiss).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		resolved, err := repo.ResolveReports(context.Background(), postId, adminId, types.ModerationDismiss)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, resolved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_PostRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, revisionId, editorId := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("update keeps the previous version in the same transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH previous AS \(SELECT post_id, title, content, status FROM posts WHERE post_id = \$1 AND deleted_at IS NULL FOR UPDATE\)\s+INSERT INTO post_revisions \(post_id, title, content, status, edited_by\)\s+SELECT post_id, title, content, status, \$2 FROM previous RETURNING status`).
+			WithArgs(postId, editorId).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+		mock.ExpectQuery(`UPDATE posts p SET title`).
+			WithArgs(postId, nil, "t").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		title := "t"
+		_, err := repo.UpdatePost(context.Background(), postId, editorId, &dto.PostPatch{Title: &title}, nil, nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("page", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM post_revisions WHERE post_id = \$1 ORDER BY edited_at DESC LIMIT \$2 OFFSET \$3`).
+			WithArgs(postId, 20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"revision_id", "post_id", "title", "edited_by"}).AddRow(revisionId, postId, "old", editorId))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM post_revisions WHERE post_id = \$1`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		revisions, err := repo.GetPostRevisions(context.Background(), postId, 20, 0)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 1)
+		assert.Equal(t, "old", revisions[0].Title)
+		count, err := repo.CountPostRevisions(context.Background(), postId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("revision of another post", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM post_revisions WHERE post_id = \$1 AND revision_id = \$2`).
+			WithArgs(postId, revisionId).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetPostRevision(context.Background(), postId, revisionId)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_UpdatePostStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, editorId := uuid.New(), uuid.New()
+	expected := time.Date(2025, 1, 31, 15, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO post_revisions`).
+		WithArgs(postId, editorId).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+	mock.ExpectQuery(`WHERE p.post_id = \$1 AND p.deleted_at IS NULL\s+AND \(\$2::timestamp IS NULL OR p.updated_at = \$2::timestamp\)`).
+		WithArgs(postId, expected.UTC(), "t").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	title := "t"
+	_, err = repo.UpdatePost(context.Background(), postId, editorId, &dto.PostPatch{Title: &title}, nil, &expected)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_UpdatePostPartial(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, editorId := uuid.New(), uuid.New()
+	title, content := "new title", ""
+
+	tests := []struct {
+		name   string
+		patch  *dto.PostPatch
+		tags   []string
+		update string
+		args   []driver.Value
+	}{
+		{
+			name:   "title only",
+			patch:  &dto.PostPatch{Title: &title},
+			update: `UPDATE posts p SET title = \$3 WHERE`,
+			args:   []driver.Value{postId, nil, title},
+		},
+		{
+			name:   "empty content is written",
+			patch:  &dto.PostPatch{Content: &content},
+			update: `UPDATE posts p SET content = \$3 WHERE`,
+			args:   []driver.Value{postId, nil, ""},
+		},
+		{
+			name:   "content is counted",
+			patch:  &dto.PostPatch{Content: &title, Stats: &dto.ReadingStats{WordCount: 2, ReadingTimeMinutes: 1}},
+			update: `UPDATE posts p SET content = \$3, word_count = \$4, reading_time_minutes = \$5 WHERE`,
+			args:   []driver.Value{postId, nil, title, 2, 1},
+		},
+		{
+			name:   "tags only touches the row",
+			patch:  &dto.PostPatch{},
+			tags:   []string{"go"},
+			update: `UPDATE posts p SET updated_at = NOW\(\) WHERE`,
+			args:   []driver.Value{postId, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO post_revisions`).
+				WithArgs(postId, editorId).
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+			mock.ExpectQuery(tt.update).
+				WithArgs(tt.args...).
+				WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(postId))
+			if tt.tags != nil {
+				mock.ExpectExec(`DELETE FROM post_tags`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO tags`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO post_tags`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectQuery(`SELECT ARRAY\(SELECT t.name FROM post_tags`).
+				WithArgs(postId).
+				WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow("{go}"))
+			mock.ExpectCommit()
+
+			post, err := repo.UpdatePost(context.Background(), postId, editorId, tt.patch, tt.tags, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, postId, post.PostId)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepository_FillReadingStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId := uuid.New()
+	words, minutes := 420, 3
+	updatedAt := time.Date(2025, 12, 16, 15, 4, 5, 6000, time.FixedZone("MSK", 3*60*60))
+
+	mock.ExpectExec(`UPDATE posts p SET word_count = s.word_count, reading_time_minutes = s.reading_time_minutes
+FROM unnest\(\$1::uuid\[\], \$2::timestamp\[\], \$3::int\[\], \$4::int\[\]\)`).
+		WithArgs(pq.Array([]string{postId.String()}), pq.Array([]string{"2025-12-16T12:04:05.000006Z"}),
+			pq.Array([]int64{420}), pq.Array([]int64{3})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.FillReadingStats(context.Background(), []*dto.PostDB{{PostId: postId, UpdatedAt: updatedAt, WordCount: &words, ReadingTimeMinutes: &minutes}})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_Authors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	authorId := uuid.New()
+
+	name, avatar := "Alice", "/media/avatars/a.png"
+	mock.ExpectQuery(`SELECT u.user_id, u.email, u.display_name, u.avatar_url, COUNT\(\*\) AS posts_count FROM users u\s+JOIN posts p ON p.author_id = u.user_id\s+WHERE u.role = 'author' AND p.status = 'published' AND p.deleted_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "display_name", "avatar_url", "posts_count"}).AddRow(authorId, "author@example.com", name, avatar, 2))
+	authors, err := repo.GetAuthors(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.AuthorDB{{UserId: authorId, Email: "author@example.com", DisplayName: &name, AvatarUrl: &avatar, PostsCount: 2}}, authors)
+
+	// an author without published posts has a profile all the same, anyone else has none
+	mock.ExpectQuery(`LEFT JOIN posts p ON p.author_id = u.user_id AND p.status = 'published' AND p.deleted_at IS NULL\s+WHERE u.user_id = \$1 AND u.role = 'author'`).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "display_name", "avatar_url", "posts_count"}).AddRow(authorId, "author@example.com", nil, nil, 0))
+	author, err := repo.GetAuthor(context.Background(), authorId)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.AuthorDB{UserId: authorId, Email: "author@example.com"}, author)
+	mock.ExpectQuery(`WHERE u.user_id = \$1 AND u.role = 'author'`).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	_, err = repo.GetAuthor(context.Background(), authorId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	mock.ExpectQuery(`WHERE p.author_id = \$1 AND p.status = 'published' AND p.deleted_at IS NULL AND`).
+		WithArgs(authorId, "").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+	posts, err := repo.GetAuthorPublishedPosts(context.Background(), authorId, "")
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_ListOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+
+	t.Run("published newest first by default", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE p.status = 'published' AND p.deleted_at IS NULL AND .+\s+ORDER BY p.created_at DESC, p.post_id;$`).
+			WithArgs("golang").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+
+		_, err := repo.GetPublishedPosts(context.Background(), dto.PostListOptions{Tag: "golang"})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("own posts by title with a status", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE p.author_id = \$1 AND p.deleted_at IS NULL AND .+ AND \(\$3 = '' OR p.status = \$3\)\s+ORDER BY p.title ASC, p.post_id;$`).
+			WithArgs(userId, "", types.Draft).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+
+		_, err := repo.GetUserPosts(context.Background(), userId, dto.PostListOptions{Status: types.Draft, Sort: types.SortTitle, Order: types.Asc})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown sort never reaches the query", func(t *testing.T) {
+		mock.ExpectQuery(`ORDER BY p.updated_at DESC, p.post_id;$`).
+			WithArgs(userId, "", types.PostStatus("")).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+		mock.ExpectQuery(`ORDER BY p.created_at DESC, p.post_id;$`).
+			WithArgs(userId, "", types.PostStatus("")).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}))
+
+		_, err := repo.GetUserPosts(context.Background(), userId, dto.PostListOptions{Sort: types.SortUpdatedAt, Order: types.Desc})
+		assert.NoError(t, err)
+		_, err = repo.GetUserPosts(context.Background(), userId, dto.PostListOptions{Sort: types.PostSort("1; DROP TABLE posts"), Order: types.SortOrder("sideways")})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_GetPostImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, imageId := uuid.New(), uuid.New()
+	added := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	caption := "A caption"
+
+	mock.ExpectQuery(`SELECT \* FROM images WHERE post_id = \$1 ORDER BY position, created_at, image_id`).
+		WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "post_id", "image_url", "position", "caption", "created_at"}).
+			AddRow(imageId, postId, "http://images/1", 1, caption, added))
+
+	images, err := repo.GetPostImages(context.Background(), postId)
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.ImageDB{{ImageId: imageId, PostId: postId, ImageUrl: "http://images/1", Position: 1, Caption: &caption, CreatedAt: added}}, images)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_ImageOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, first, second, third := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	locked := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"image_id"}).AddRow(first).AddRow(second).AddRow(third)
+	}
+	lock := `SELECT image_id FROM images WHERE post_id = \$1 ORDER BY position, created_at, image_id FOR UPDATE`
+	positions := `UPDATE images i SET position = o.position\s+FROM unnest\(\$1::uuid\[\]\) WITH ORDINALITY`
+
+	t.Run("moving an image renumbers the others", func(t *testing.T) {
+		position, caption := 1, ""
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(postId).WillReturnRows(locked())
+		mock.ExpectExec(positions).
+			WithArgs(pq.Array([]string{third.String(), first.String(), second.String()})).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`UPDATE images SET caption = NULLIF\(\$2, ''\) WHERE image_id = \$1`).
+			WithArgs(third, "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT \* FROM images WHERE image_id = \$1`).
+			WithArgs(third).
+			WillReturnRows(sqlmock.NewRows([]string{"image_id", "position"}).AddRow(third, 1))
+		mock.ExpectCommit()
+
+		image, err := repo.UpdateImage(context.Background(), postId, third, &dto.ImagePatch{Caption: &caption, Position: &position})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, image.Position)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a position past the last makes it the last", func(t *testing.T) {
+		position := 10
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(postId).WillReturnRows(locked())
+		mock.ExpectExec(positions).
+			WithArgs(pq.Array([]string{second.String(), third.String(), first.String()})).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectQuery(`SELECT \* FROM images WHERE image_id = \$1`).
+			WithArgs(first).
+			WillReturnRows(sqlmock.NewRows([]string{"image_id", "position"}).AddRow(first, 3))
+		mock.ExpectCommit()
+
+		_, err := repo.UpdateImage(context.Background(), postId, first, &dto.ImagePatch{Position: &position})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("image of another post", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(postId).WillReturnRows(locked())
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateImage(context.Background(), postId, uuid.New(), &dto.ImagePatch{})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).WithArgs(postId).WillReturnRows(locked())
+		mock.ExpectExec(positions).
+			WithArgs(pq.Array([]string{third.String(), second.String(), first.String()})).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectQuery(`SELECT \* FROM images WHERE post_id = \$1 ORDER BY position, created_at, image_id`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"image_id", "position"}).AddRow(third, 1).AddRow(second, 2).AddRow(first, 3))
+		mock.ExpectCommit()
+
+		images, err := repo.ReorderImages(context.Background(), postId, []uuid.UUID{third, second, first})
+		assert.NoError(t, err)
+		assert.Len(t, images, 3)
+		assert.Equal(t, third, images[0].ImageId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	for name, ids := range map[string][]uuid.UUID{
+		"missing image":   {third, second},
+		"foreign image":   {third, second, uuid.New()},
+		"image twice":     {third, second, second},
+		"one image extra": {third, second, first, uuid.New()},
+	} {
+		t.Run(name+" changes nothing", func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lock).WithArgs(postId).WillReturnRows(locked())
+			mock.ExpectRollback()
+
+			_, err := repo.ReorderImages(context.Background(), postId, ids)
+			assert.ErrorIs(t, err, errors.ErrorRepositoryImageOrder)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepository_ReplaceImage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, imageId := uuid.New(), uuid.New()
+	query := `UPDATE images SET image_url = \$3, size_bytes = \$4, updated_at = NOW\(\) WHERE image_id = \$1 AND post_id = \$2 RETURNING \*`
+
+	t.Run("points the image to the new object", func(t *testing.T) {
+		updatedAt := time.Now()
+		mock.ExpectQuery(query).
+			WithArgs(imageId, postId, "/images/"+imageId.String()+".png", int64(2048)).
+			WillReturnRows(sqlmock.NewRows([]string{"image_id", "post_id", "image_url", "size_bytes", "updated_at"}).
+				AddRow(imageId, postId, "/images/"+imageId.String()+".png", 2048, updatedAt))
+
+		image, err := repo.ReplaceImage(context.Background(), postId, imageId, "/images/"+imageId.String()+".png", 2048)
+		assert.NoError(t, err)
+		assert.Equal(t, "/images/"+imageId.String()+".png", image.ImageUrl)
+		assert.Equal(t, int64(2048), image.SizeBytes)
+		assert.Equal(t, updatedAt, image.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("image of another post", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(imageId, postId, "/images/x.png", int64(10)).
+			WillReturnRows(sqlmock.NewRows([]string{"image_id"}))
+
+		_, err := repo.ReplaceImage(context.Background(), postId, imageId, "/images/x.png", 10)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_GetUserImageBytes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId := uuid.New()
+	query := `SELECT COALESCE\(SUM\(i.size_bytes\), 0\) FROM images i JOIN posts p ON p.post_id = i.post_id WHERE p.author_id = \$1`
+
+	mock.ExpectQuery(query).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(int64(3 << 20)))
+
+	used, err := repo.GetUserImageBytes(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3<<20), used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_GetExistingImageIds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	known, missing := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT image_id FROM images WHERE image_id = ANY\(\$1::uuid\[\]\)`).
+		WithArgs(pq.Array([]string{known.String(), missing.String()})).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id"}).AddRow(known.String()))
+
+	ids, err := repo.GetExistingImageIds(context.Background(), []uuid.UUID{known, missing})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{known}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_QueryTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres"), QueryTimeout: 20 * time.Millisecond}}
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE email = \$1;`).
+		WithArgs("slow@example.com").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	user, err := repo.GetUserByEmail(context.Background(), "slow@example.com")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, errors.ErrorRepositoryQueryTimeout)
+}
+
+func TestPostgresRepository_AuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+
+	userId := uuid.New()
+	// a failed login of an unknown email has no user
+	mock.ExpectExec(`INSERT INTO audit_log \(user_id, action, target_id, ip, user_agent\)`).
+		WithArgs(nil, types.AuditLoginFailed, nil, "203.0.113.7", "curl/8.0").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = repo.AddAuditEntry(context.Background(), &dto.AuditEntryDB{Action: types.AuditLoginFailed, IP: "203.0.113.7", UserAgent: "curl/8.0"})
+	assert.NoError(t, err)
+
+	createdAt := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	filter := dto.AuditFilter{UserId: &userId, Action: types.AuditLogin}
+	mock.ExpectQuery(`SELECT \* FROM audit_log WHERE \(\$1::uuid IS NULL OR user_id = \$1\) AND \(\$2 = '' OR action = \$2\) ORDER BY created_at DESC`).
+		WithArgs(&userId, types.AuditLogin, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"audit_id", "user_id", "action", "target_id", "ip", "user_agent", "created_at"}).
+			AddRow(uuid.New(), userId, "login", nil, "203.0.113.7", "curl/8.0", createdAt))
+	entries, err := repo.GetAuditEntries(context.Background(), filter, 20, 0)
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.Equal(t, userId, *entries[0].UserId)
+	assert.Nil(t, entries[0].TargetId)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM audit_log WHERE`).
+		WithArgs(nil, types.AuditAction("")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	count, err := repo.CountAuditEntries(context.Background(), dto.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_Outbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	postId, eventId := uuid.New(), uuid.New()
+
+	t.Run("trash queues post.deleted in the same transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE posts SET deleted_at = NOW\(\) WHERE post_id = \$1 AND deleted_at IS NULL`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(postId))
+		mock.ExpectExec(`INSERT INTO outbox_events \(post_id, type, payload\)`).
+			WithArgs(postId, types.EventPostDeleted, types.PostStatus("")).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.TrashPost(context.Background(), postId)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rescheduling is no status change", func(t *testing.T) {
+		publishAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM posts WHERE post_id = \$1 AND deleted_at IS NULL FOR UPDATE`).
+			WithArgs(postId).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Scheduled))
+		mock.ExpectQuery(`UPDATE posts SET status = 'scheduled', publish_at = \$2 WHERE post_id = \$1`).
+			WithArgs(postId, publishAt).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "status"}).AddRow(postId, types.Scheduled))
+		mock.ExpectCommit()
+
+		_, err := repo.SchedulePost(context.Background(), postId, publishAt)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pending events", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM outbox_events WHERE sent_at IS NULL ORDER BY seq LIMIT \$1`).
+			WithArgs(100).
+			WillReturnRows(sqlmock.NewRows([]string{"event_id", "seq", "post_id", "type", "payload"}).
+				AddRow(eventId, 7, postId, "post.created", []byte(`{"status": "draft"}`)))
+
+		events, err := repo.GetPendingEvents(context.Background(), 100)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, types.EventPostCreated, events[0].Type)
+			assert.JSONEq(t, `{"status": "draft"}`, string(events[0].Payload))
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mark sent", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE outbox_events SET sent_at = NOW\(\) WHERE event_id = ANY\(\$1::uuid\[\]\)`).
+			WithArgs(pq.Array([]string{eventId.String()})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkEventsSent(context.Background(), []uuid.UUID{eventId}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Subscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	readerId, authorId := uuid.New(), uuid.New()
+	insert := `INSERT INTO subscriptions \(reader_id, author_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`
+
+	t.Run("subscribe twice", func(t *testing.T) {
+		mock.ExpectExec(insert).WithArgs(readerId, authorId).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insert).WithArgs(readerId, authorId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.NoError(t, repo.Subscribe(context.Background(), readerId, authorId))
+		assert.NoError(t, repo.Subscribe(context.Background(), readerId, authorId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("subscribe to unknown user", func(t *testing.T) {
+		mock.ExpectExec(insert).WithArgs(readerId, authorId).WillReturnError(&pq.Error{Code: "23503"})
+
+		assert.ErrorIs(t, repo.Subscribe(context.Background(), readerId, authorId), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("subscribe to yourself", func(t *testing.T) {
+		mock.ExpectExec(insert).WithArgs(readerId, readerId).
+			WillReturnError(&pq.Error{Code: "23514", Constraint: "subscriptions_not_self"})
+
+		assert.ErrorIs(t, repo.Subscribe(context.Background(), readerId, readerId), errors.ErrorServiceSelfSubscription)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM subscriptions WHERE reader_id = \$1 AND author_id = \$2`).
+			WithArgs(readerId, authorId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.NoError(t, repo.Unsubscribe(context.Background(), readerId, authorId))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count subscribers", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM subscriptions WHERE author_id = \$1`).
+			WithArgs(authorId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1200))
+
+		count, err := repo.CountSubscribers(context.Background(), authorId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1200, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		since := time.Date(2025, 12, 14, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT s.author_id, u.email, u.display_name, s.created_at FROM subscriptions s\s+JOIN users u ON u.user_id = s.author_id\s+WHERE s.reader_id = \$1 ORDER BY s.created_at DESC, s.author_id LIMIT \$2 OFFSET \$3`).
+			WithArgs(readerId, 20, 40).
+			WillReturnRows(sqlmock.NewRows([]string{"author_id", "email", "display_name", "created_at"}).AddRow(authorId, "author@example.com", "Alice", since))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM subscriptions WHERE reader_id = \$1`).
+			WithArgs(readerId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+
+		subscriptions, err := repo.GetSubscriptions(context.Background(), readerId, 20, 40)
+		assert.NoError(t, err)
+		name := "Alice"
+		assert.Equal(t, []*dto.SubscriptionDB{{AuthorId: authorId, Email: "author@example.com", DisplayName: &name, CreatedAt: since}}, subscriptions)
+		count, err := repo.CountSubscriptions(context.Background(), readerId)
+		assert.NoError(t, err)
+		assert.Equal(t, 41, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_Notifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	userId, postId, authorId := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("notify subscribers", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO notifications \(user_id, post_id, type\)\s+SELECT s.reader_id, p.post_id, \$2 FROM posts p\s+JOIN subscriptions s ON s.author_id = p.author_id\s+WHERE p.post_id = \$1 AND p.status = 'published' AND p.deleted_at IS NULL\s+ON CONFLICT \(user_id, post_id\) DO NOTHING`).
+			WithArgs(postId, types.NotificationPostPublished).
+			WillReturnResult(sqlmock.NewResult(0, 3000))
+
+		count, err := repo.NotifySubscribers(context.Background(), postId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3000), count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("list unread", func(t *testing.T) {
+		notificationId := uuid.New()
+		created := time.Date(2025, 12, 14, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT n.\*, p.title, p.author_id, u.email AS author_email, u.display_name AS author_display_name FROM notifications n\s+JOIN posts p ON p.post_id = n.post_id\s+JOIN users u ON u.user_id = p.author_id\s+WHERE n.user_id = \$1 AND \(NOT \$2 OR n.read_at IS NULL\)\s+ORDER BY n.created_at DESC, n.notification_id LIMIT \$3 OFFSET \$4`).
+			WithArgs(userId, true, 20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"notification_id", "user_id", "post_id", "type", "created_at", "read_at", "title", "author_id", "author_email"}).
+				AddRow(notificationId, userId, postId, "post.published", created, nil, "Hello", authorId, "author@example.com"))
+
+		notifications, err := repo.GetNotifications(context.Background(), userId, true, 20, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []*dto.NotificationDB{{
+			NotificationId: notificationId,
+			UserId:         userId,
+			PostId:         postId,
+			Type:           types.NotificationPostPublished,
+			CreatedAt:      created,
+			Title:          "Hello",
+			AuthorId:       authorId,
+			AuthorEmail:    "author@example.com",
+		}}, notifications)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) AS total, COUNT\(\*\) FILTER \(WHERE read_at IS NULL\) AS unread\s+FROM notifications WHERE user_id = \$1`).
+			WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "unread"}).AddRow(7, 2))
+
+		total, unread, err := repo.CountNotifications(context.Background(), userId)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, total)
+		assert.Equal(t, 2, unread)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	markRead := `UPDATE notifications SET read_at = NOW\(\)\s+WHERE user_id = \$1 AND read_at IS NULL AND \(cardinality\(\$2::uuid\[\]\) = 0 OR notification_id = ANY\(\$2::uuid\[\]\)\)`
+
+	t.Run("mark listed read", func(t *testing.T) {
+		id := uuid.New()
+		mock.ExpectExec(markRead).
+			WithArgs(userId, pq.Array([]string{id.String()})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		count, err := repo.MarkNotificationsRead(context.Background(), userId, []uuid.UUID{id})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mark all read", func(t *testing.T) {
+		mock.ExpectExec(markRead).
+			WithArgs(userId, pq.Array([]string{})).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+
+		count, err := repo.MarkNotificationsRead(context.Background(), userId, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_ExportAuthorPosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	authorId := uuid.New()
+	withImage, withoutImage := uuid.New(), uuid.New()
+	imageId := uuid.New()
+
+	mock.ExpectQuery(`SELECT i\.\* FROM images i JOIN posts p ON p\.post_id = i\.post_id\s+WHERE p\.author_id = \$1 AND p\.deleted_at IS NULL`).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "post_id", "image_url"}).
+			AddRow(imageId, withImage, "/images/"+imageId.String()+".png"))
+	mock.ExpectQuery(`SELECT p\.\*, .+ FROM posts p\s+WHERE p\.author_id = \$1 AND p\.deleted_at IS NULL ORDER BY p\.created_at, p\.post_id`).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id"}).
+			AddRow(withImage, authorId).
+			AddRow(withoutImage, authorId))
+
+	images := map[uuid.UUID]int{}
+	var order []uuid.UUID
+	err = repo.ExportAuthorPosts(context.Background(), authorId, func(post *dto.PostDB, postImages []*dto.ImageDB) error {
+		order = append(order, post.PostId)
+		images[post.PostId] = len(postImages)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{withImage, withoutImage}, order)
+	assert.Equal(t, map[uuid.UUID]int{withImage: 1, withoutImage: 0}, images)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRepository_ImportPost(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	authorId, postId := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+	caption := "The view"
+	imported := &dto.ImportedPost{
+		IdempotencyKey: "import-1",
+		Title:          "Hello",
+		Content:        "![view](/images/a.png)",
+		Tags:           []string{"go"},
+		Hash:           "abc",
+		Stats:          &dto.ReadingStats{WordCount: 1, ReadingTimeMinutes: 1},
+		Images: []dto.ImportedImage{
+			{ImageId: first, ImageUrl: "/images/a.png", Caption: &caption, SizeBytes: 10},
+			{ImageId: second, ImageUrl: "/images/b.png", SizeBytes: 20},
+		},
+	}
+
+	t.Run("post, tags and images in one transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts \(author_id, idempotency_key, title, content, excerpt, word_count, reading_time_minutes, import_hash\)`).
+			WithArgs(authorId, "import-1", "Hello", "![view](/images/a.png)", "", 1, 1, "abc").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id", "status"}).AddRow(postId, authorId, types.Draft))
+		mock.ExpectExec(`DELETE FROM post_tags`).WithArgs(postId).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO tags`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO post_tags`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO images`).WithArgs(first, postId, "/images/a.png", 1, &caption, int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO images`).WithArgs(second, postId, "/images/b.png", 2, nil, int64(20)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO outbox_events`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		post, err := repo.ImportPost(context.Background(), authorId, imported)
+		assert.NoError(t, err)
+		assert.Equal(t, postId, post.PostId)
+		assert.Equal(t, []string{"go"}, []string(post.Tags))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a failing image takes the post back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO posts`).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(postId))
+		mock.ExpectExec(`DELETE FROM post_tags`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO tags`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO post_tags`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO images`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.ImportPost(context.Background(), authorId, imported)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresRepository_HasImportedPost(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := &PostgresRepository{DB: &postgres.DB{DB: sqlx.NewDb(db, "postgres")}}
+	authorId := uuid.New()
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM posts WHERE author_id = \$1 AND deleted_at IS NULL\s+AND \(import_hash = \$2 OR \(title = \$3 AND content = \$4\)\)\)`).
+		WithArgs(authorId, "abc", "Hello", "World").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repo.HasImportedPost(context.Background(), authorId, "abc", "Hello", "World")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
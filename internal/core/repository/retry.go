@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	minIO "github.com/minio/minio-go/v7"
+)
+
+const (
+	// retryBufferLimit is the largest upload kept in memory so it can be sent again, bigger ones get one attempt
+	retryBufferLimit = 16 << 20
+	retryBackoff     = 200 * time.Millisecond
+)
+
+// retryable reports whether a failed MinIO call may succeed when repeated: the network failed
+// or the server answered 5xx. Errors the caller caused, like a missing bucket, are final.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if resp := minIO.ToErrorResponse(err); resp.StatusCode != 0 {
+		return resp.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoffDelay doubles the wait with every attempt and picks it at random from its upper half
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
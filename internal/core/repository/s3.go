@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsS3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/storage/s3"
+)
+
+// S3ObjectClient is the part of the S3 client the repository works with
+type S3ObjectClient interface {
+	PutObject(ctx context.Context, params *awsS3.PutObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *awsS3.DeleteObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.DeleteObjectOutput, error)
+	HeadObject(ctx context.Context, params *awsS3.HeadObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.HeadObjectOutput, error)
+	GetObject(ctx context.Context, params *awsS3.GetObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *awsS3.ListObjectsV2Input, optFns ...func(*awsS3.Options)) (*awsS3.ListObjectsV2Output, error)
+}
+
+type S3Presigner interface {
+	PresignGetObject(ctx context.Context, params *awsS3.GetObjectInput, optFns ...func(*awsS3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+// S3Repository keeps images in an AWS S3 bucket. Links of a public one start with the base url,
+// which may be a CloudFront distribution in front of the bucket.
+type S3Repository struct {
+	client  S3ObjectClient
+	presign S3Presigner
+	bucket  string
+	baseURL string
+	public  bool
+}
+
+func NewS3Repository(storage *s3.S3Client, public bool) *S3Repository {
+	return &S3Repository{storage.Client, storage.Presign, storage.Bucket, storage.BaseURL, public}
+}
+
+func (rep *S3Repository) PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	// the request is signed over its body, which needs a body that can be read twice
+	body, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	_, err := rep.client.PutObject(ctx, &awsS3.PutObjectInput{
+		Bucket:        aws.String(rep.bucket),
+		Key:           aws.String(objectName),
+		Body:          body,
+		ContentLength: aws.Int64(fileSize),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return "", storageError(ctx, err)
+	}
+
+	if !rep.public {
+		return objectName, nil
+	}
+	return rep.baseURL + "/" + url.PathEscape(objectName), nil
+}
+
+func (rep *S3Repository) DeleteImage(ctx context.Context, objectName string) error {
+	_, err := rep.client.DeleteObject(ctx, &awsS3.DeleteObjectInput{
+		Bucket: aws.String(rep.bucket),
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return storageError(ctx, err)
+	}
+	return nil
+}
+
+func (rep *S3Repository) GetImage(objectName string) (io.ReadCloser, string, error) {
+	out, err := rep.client.GetObject(context.Background(), &awsS3.GetObjectInput{
+		Bucket: aws.String(rep.bucket),
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return out.Body, aws.ToString(out.ContentType), nil
+}
+
+func (rep *S3Repository) ImageExists(objectName string) (bool, error) {
+	_, err := rep.client.HeadObject(context.Background(), &awsS3.HeadObjectInput{
+		Bucket: aws.String(rep.bucket),
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (rep *S3Repository) Public() bool {
+	return rep.public
+}
+
+// PresignGet makes a link that reads the object without credentials until ttl runs out
+func (rep *S3Repository) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	req, err := rep.presign.PresignGetObject(context.Background(), &awsS3.GetObjectInput{
+		Bucket: aws.String(rep.bucket),
+		Key:    aws.String(objectName),
+	}, awsS3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (rep *S3Repository) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	// the delimiter keeps nested objects out of the listing
+	pages := awsS3.NewListObjectsV2Paginator(rep.client, &awsS3.ListObjectsV2Input{
+		Bucket:    aws.String(rep.bucket),
+		Delimiter: aws.String("/"),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return storageError(ctx, err)
+		}
+		for _, obj := range page.Contents {
+			if err := emit(storage.ImageObject{Name: aws.ToString(obj.Key), LastModified: aws.ToTime(obj.LastModified)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
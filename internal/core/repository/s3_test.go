@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsS3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeS3Client struct {
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (c *fakeS3Client) PutObject(ctx context.Context, params *awsS3.PutObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.PutObjectOutput, error) {
+	// the sdk rewinds the body to sign it
+	if _, ok := params.Body.(io.Seeker); !ok {
+		return nil, io.ErrNoProgress
+	}
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != aws.ToInt64(params.ContentLength) {
+		return nil, io.ErrShortWrite
+	}
+	c.objects[aws.ToString(params.Key)] = data
+	c.types[aws.ToString(params.Key)] = aws.ToString(params.ContentType)
+	return &awsS3.PutObjectOutput{}, nil
+}
+
+func (c *fakeS3Client) DeleteObject(ctx context.Context, params *awsS3.DeleteObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.DeleteObjectOutput, error) {
+	delete(c.objects, aws.ToString(params.Key))
+	return &awsS3.DeleteObjectOutput{}, nil
+}
+
+func (c *fakeS3Client) HeadObject(ctx context.Context, params *awsS3.HeadObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.HeadObjectOutput, error) {
+	if _, ok := c.objects[aws.ToString(params.Key)]; !ok {
+		return nil, &types.NotFound{}
+	}
+	return &awsS3.HeadObjectOutput{}, nil
+}
+
+func (c *fakeS3Client) GetObject(ctx context.Context, params *awsS3.GetObjectInput, optFns ...func(*awsS3.Options)) (*awsS3.GetObjectOutput, error) {
+	data, ok := c.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &awsS3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader(data)),
+		ContentType: aws.String(c.types[aws.ToString(params.Key)]),
+	}, nil
+}
+
+func (c *fakeS3Client) ListObjectsV2(ctx context.Context, params *awsS3.ListObjectsV2Input, optFns ...func(*awsS3.Options)) (*awsS3.ListObjectsV2Output, error) {
+	out := &awsS3.ListObjectsV2Output{}
+	for _, key := range slices.Sorted(maps.Keys(c.objects)) {
+		out.Contents = append(out.Contents, types.Object{Key: aws.String(key), Size: aws.Int64(int64(len(c.objects[key])))})
+	}
+	return out, nil
+}
+
+type fakeS3Presigner struct{}
+
+func (fakeS3Presigner) PresignGetObject(ctx context.Context, params *awsS3.GetObjectInput, optFns ...func(*awsS3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	opts := awsS3.PresignOptions{}
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	return &v4.PresignedHTTPRequest{
+		URL: "https://" + aws.ToString(params.Bucket) + ".s3.amazonaws.com/" + aws.ToString(params.Key) + "?X-Amz-Expires=" + opts.Expires.String(),
+	}, nil
+}
+
+func TestS3Repository_Images(t *testing.T) {
+	tests := []struct {
+		name     string
+		public   bool
+		expected string
+	}{
+		{name: "public bucket", public: true, expected: "https://cdn.example.com/pic.png"},
+		{name: "private bucket", public: false, expected: "pic.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeS3Client{objects: map[string][]byte{}, types: map[string]string{}}
+			repo := &S3Repository{client: client, presign: fakeS3Presigner{}, bucket: "images", baseURL: "https://cdn.example.com", public: tt.public}
+			assert.Equal(t, tt.public, repo.Public())
+
+			// uploads arrive as a stream that cannot be rewound
+			link, err := repo.PutImage(context.Background(), "pic.png", io.MultiReader(strings.NewReader("png")), 3, "image/png")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, link)
+
+			exists, err := repo.ImageExists("pic.png")
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			file, contentType, err := repo.GetImage("pic.png")
+			require.NoError(t, err)
+			data, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, "png", string(data))
+			assert.Equal(t, "image/png", contentType)
+
+			require.NoError(t, repo.DeleteImage(context.Background(), "pic.png"))
+			exists, err = repo.ImageExists("pic.png")
+			require.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestS3Repository_PresignGet(t *testing.T) {
+	repo := &S3Repository{client: &fakeS3Client{}, presign: fakeS3Presigner{}, bucket: "images"}
+
+	link, err := repo.PresignGet("pic.png", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "https://images.s3.amazonaws.com/pic.png?X-Amz-Expires=15m0s", link)
+}
@@ -1,87 +1,709 @@
 package servers
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 	"github.com/xkarasb/blog/docs"
+	"github.com/xkarasb/blog/internal/core/jobs"
 	"github.com/xkarasb/blog/internal/core/repository"
 	"github.com/xkarasb/blog/internal/core/service"
+	blogrpc "github.com/xkarasb/blog/internal/transport/grpc"
 	mw "github.com/xkarasb/blog/internal/transport/http/middlewares"
 	"github.com/xkarasb/blog/internal/transport/http/routers"
+	"github.com/xkarasb/blog/pkg/apiversion"
+	"github.com/xkarasb/blog/pkg/cache"
 	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/mailer"
+	"github.com/xkarasb/blog/pkg/markdown"
+	"github.com/xkarasb/blog/pkg/storage"
 	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/storage/s3"
+	"github.com/xkarasb/blog/pkg/tracing"
+	"github.com/xkarasb/blog/pkg/types"
+	"github.com/xkarasb/blog/pkg/version"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 type HttpServerConfig struct {
 	Address string `env:"ADDRESS" env-default:"127.0.0.1"`
 	Port    int    `env:"PORT" env-default:"8080"`
-	Secret  string `env:"SECRET" env-default:"secret"`
+	Secret  string `env:"SECRET" env-default:"secret" secret:"true"`
 	Docs    bool   `env:"DOCS" env-default:"TRUE"`
+	// DocsUser and DocsPassword open the swagger UI with basic auth, admins get in with their token either
+	// way. DocsBaseURL is where its "Try it out" sends requests, e.g. https://blog.example.com, empty for
+	// Address:Port.
+	DocsUser     string `env:"DOCS_USER"`
+	DocsPassword string `env:"DOCS_PASSWORD" secret:"true"`
+	DocsBaseURL  string `env:"DOCS_BASE_URL"`
+	// DebugEndpoints serves pprof under /debug/pprof/ and expvar at /debug/vars to admins and to requests
+	// with OperatorToken as bearer token, without it they do not exist
+	DebugEndpoints bool   `env:"DEBUG_ENDPOINTS" env-default:"FALSE"`
+	OperatorToken  string `env:"OPERATOR_TOKEN" secret:"true"`
+
+	// SecretPrevious verifies the tokens signed before SECRET was rotated, clear it once they expired
+	SecretPrevious string `env:"SECRET_PREVIOUS" secret:"true"`
+
+	HealthTimeout time.Duration `env:"HEALTH_TIMEOUT" env-default:"2s"`
+
+	// TLSCertFile and TLSKeyFile serve HTTPS with a certificate of their own, both or neither are set
+	TLSCertFile string `env:"TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"TLS_KEY_FILE"`
+	// AutocertDomains get their certificates from Let's Encrypt instead, kept in AutocertCacheDir across restarts
+	AutocertDomains  []string `env:"AUTOCERT_DOMAINS" env-separator:","`
+	AutocertCacheDir string   `env:"AUTOCERT_CACHE_DIR" env-default:"autocert"`
+	// TLSRedirect also listens for plain HTTP on TLSRedirectPort and sends every request to HTTPS
+	TLSRedirect     bool `env:"TLS_REDIRECT" env-default:"FALSE"`
+	TLSRedirectPort int  `env:"TLS_REDIRECT_PORT" env-default:"80"`
+
+	// GRPCPort serves the gRPC api on Address as well, zero leaves it off
+	GRPCPort int `env:"GRPC_PORT" env-default:"0"`
+
+	// a client gets ReadHeaderTimeout for its headers and ReadTimeout for the whole request,
+	// slow ones are dropped instead of holding a connection forever
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"30s"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"60s"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" env-default:"120s"`
+	MaxHeaderBytes    int           `env:"HTTP_MAX_HEADER_BYTES" env-default:"65536"`
+	// UploadTimeout replaces ReadTimeout and WriteTimeout for image uploads, and RequestTimeout for them
+	UploadTimeout time.Duration `env:"UPLOAD_TIMEOUT" env-default:"5m"`
+	// ExportTimeout does the same for exports of the posts of an author
+	ExportTimeout time.Duration `env:"EXPORT_TIMEOUT" env-default:"10m"`
+	// RequestTimeout cancels the context of an api request, storage and database calls made for it
+	// give up and the client gets 504. AdminRequestTimeout replaces it for /admin/, zero for no limit.
+	RequestTimeout      time.Duration `env:"REQUEST_TIMEOUT" env-default:"30s"`
+	AdminRequestTimeout time.Duration `env:"ADMIN_REQUEST_TIMEOUT" env-default:"5m"`
+	// MaxBodyBytes caps JSON request bodies, MaxUploadBytes image uploads and MaxImportBytes imports
+	// of posts, zero for no limit
+	MaxBodyBytes   int64 `env:"MAX_BODY_BYTES" env-default:"1048576"`
+	MaxUploadBytes int64 `env:"MAX_UPLOAD_BYTES" env-default:"26214400"`
+	MaxImportBytes int64 `env:"MAX_IMPORT_BYTES" env-default:"104857600"`
+	// StrictJSON rejects JSON bodies with unknown or repeated fields and data behind the object,
+	// off by default since clients sending extra fields used to be served
+	StrictJSON bool `env:"STRICT_JSON" env-default:"FALSE"`
+
+	// Gzip compresses api responses of GzipMinBytes and more for clients accepting it
+	Gzip         bool `env:"GZIP" env-default:"TRUE"`
+	GzipMinBytes int  `env:"GZIP_MIN_BYTES" env-default:"1024"`
+
+	AccessTTL  time.Duration `env:"ACCESS_TTL" env-default:"2h"`
+	RefreshTTL time.Duration `env:"REFRESH_TTL" env-default:"168h"`
+	// MaxSessions per user, a login beyond it ends the session used least recently; zero for no limit
+	MaxSessions int `env:"MAX_SESSIONS" env-default:"10"`
+
+	// UserCacheTTL of zero loads the user from the database for every authorized request
+	UserCacheTTL  time.Duration `env:"USER_CACHE_TTL" env-default:"30s"`
+	UserCacheSize int           `env:"USER_CACHE_SIZE" env-default:"10000"`
+
+	// argon2id params of new password hashes, memory is in KiB; raising them rehashes a password at its next login
+	PasswordHashMemory      uint32 `env:"PASSWORD_HASH_MEMORY" env-default:"65536" secret:"false"`
+	PasswordHashIterations  uint32 `env:"PASSWORD_HASH_ITERATIONS" env-default:"3" secret:"false"`
+	PasswordHashParallelism uint8  `env:"PASSWORD_HASH_PARALLELISM" env-default:"2" secret:"false"`
+
+	// AuthCookieMode also hands the refresh token to browsers as an HttpOnly cookie
+	AuthCookieMode bool `env:"AUTH_COOKIE_MODE" env-default:"FALSE"`
+
+	// tokens of another deployment sharing the secret fail on iss and aud
+	JWTIssuer   string        `env:"JWT_ISSUER" env-default:"blog"`
+	JWTAudience string        `env:"JWT_AUDIENCE" env-default:"blog-api"`
+	JWTLeeway   time.Duration `env:"JWT_LEEWAY" env-default:"30s"`
+	// JWTAllowLegacy keeps tokens minted without iss, aud and jti valid while they run out
+	JWTAllowLegacy bool `env:"JWT_ALLOW_LEGACY" env-default:"FALSE"`
+
+	PasswordResetTTL time.Duration `env:"PASSWORD_RESET_TTL" env-default:"1h" secret:"false"`
+	PasswordResetURL string        `env:"PASSWORD_RESET_URL" env-default:"http://localhost/reset-password" secret:"false"`
+	// RequireVerifiedEmail lets authors publish only once they followed the link mailed at registration
+	RequireVerifiedEmail bool          `env:"REQUIRE_VERIFIED_EMAIL" env-default:"TRUE"`
+	VerifyEmailTTL       time.Duration `env:"VERIFY_EMAIL_TTL" env-default:"72h" secret:"false"`
+	VerifyEmailURL       string        `env:"VERIFY_EMAIL_URL" env-default:"http://localhost/api/auth/verify-email" secret:"false"`
+	// VerifyResendInterval is how long a user waits before another verification mail
+	VerifyResendInterval time.Duration `env:"VERIFY_RESEND_INTERVAL" env-default:"1m"`
+	// AccountDeletionPolicy is anonymize to keep the posts of a deleted account under a "Deleted user"
+	// or delete to remove them with it
+	AccountDeletionPolicy types.DeletionPolicy `env:"ACCOUNT_DELETION_POLICY" env-default:"anonymize"`
+	// PreviewTokenTTL is how long a preview link of a post lets anyone read it
+	PreviewTokenTTL time.Duration `env:"PREVIEW_TOKEN_TTL" env-default:"72h"`
+	// IdempotencyKeyTTL is how long the response to a write sent with an Idempotency-Key is replayed
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" env-default:"24h"`
+
+	SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" env-default:"30s"`
+
+	TrashRetention       time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	TrashCleanupInterval time.Duration `env:"TRASH_CLEANUP_INTERVAL" env-default:"1h"`
+
+	// OrphanCleanupInterval of zero leaves the cleanup of orphaned images to the admin endpoint
+	OrphanCleanupInterval time.Duration `env:"ORPHAN_CLEANUP_INTERVAL" env-default:"0"`
+	OrphanMinAge          time.Duration `env:"ORPHAN_MIN_AGE" env-default:"24h"`
+
+	ViewsBuffer        int           `env:"VIEWS_BUFFER" env-default:"1024"`
+	ViewsBatchSize     int           `env:"VIEWS_BATCH_SIZE" env-default:"100"`
+	ViewsFlushInterval time.Duration `env:"VIEWS_FLUSH_INTERVAL" env-default:"5s"`
+
+	OutboxInterval  time.Duration `env:"OUTBOX_INTERVAL" env-default:"1s"`
+	OutboxBatchSize int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+
+	// TokenPurgeInterval is how often refresh, password reset and preview tokens and idempotency keys that expired more than
+	// TokenPurgeGrace ago are removed, TokenPurgeBatchSize rows per statement
+	TokenPurgeInterval  time.Duration `env:"TOKEN_PURGE_INTERVAL" env-default:"1h"`
+	TokenPurgeGrace     time.Duration `env:"TOKEN_PURGE_GRACE" env-default:"24h"`
+	TokenPurgeBatchSize int           `env:"TOKEN_PURGE_BATCH_SIZE" env-default:"1000"`
+
+	// JobsStopTimeout is how long Stop waits for the background jobs under way before cancelling them
+	JobsStopTimeout time.Duration `env:"JOBS_STOP_TIMEOUT" env-default:"30s"`
+
+	PublicCacheMaxAge time.Duration `env:"PUBLIC_CACHE_MAX_AGE" env-default:"1m"`
+	// StreamHeartbeat is how often GET /posts/stream sends a comment, StreamBuffer how many events a
+	// client may lag behind before its stream is closed
+	StreamHeartbeat time.Duration `env:"STREAM_HEARTBEAT" env-default:"30s"`
+	StreamBuffer    int           `env:"STREAM_BUFFER" env-default:"16"`
+
+	// PostsCacheTTL bounds how stale a cached listing of published posts gets, zero turns the cache off
+	PostsCacheTTL time.Duration `env:"POSTS_CACHE_TTL" env-default:"30s"`
+
+	ImagesStripExif bool `env:"IMAGES_STRIP_EXIF" env-default:"TRUE"`
+	// ImagesPerPost caps the images of a post and MaxImageBytes each image, zero for no limit. A bulk upload
+	// stores ImagesUploadWorkers images at once.
+	ImagesPerPost       int   `env:"IMAGES_PER_POST" env-default:"50"`
+	MaxImageBytes       int64 `env:"MAX_IMAGE_BYTES" env-default:"26214400"`
+	ImagesUploadWorkers int   `env:"IMAGES_UPLOAD_WORKERS" env-default:"4"`
+	// StorageQuotaBytes caps the images of all posts of an author together, zero for no quota. Uploads
+	// running at the same time can take an author slightly past it.
+	StorageQuotaBytes int64 `env:"STORAGE_QUOTA_BYTES" env-default:"524288000"`
+
+	// ContentImageHosts may serve the images of rendered posts besides paths of the api host, the MinIO
+	// or S3 host when images are kept there. ContentCacheSize posts keep their HTML, zero renders every time.
+	ContentImageHosts []string `env:"CONTENT_IMAGE_HOSTS" env-separator:","`
+	ContentCacheSize  int      `env:"CONTENT_CACHE_SIZE" env-default:"1000"`
+	// ReadingWordsPerMinute is the reading speed behind reading_time_minutes
+	ReadingWordsPerMinute int `env:"READING_WORDS_PER_MINUTE" env-default:"200"`
+	// GET /posts/trending ranks the views and likes of the last TrendingWindow, a like weighing
+	// TrendingLikeWeight views, and keeps the ranking for TrendingCacheTTL, zero ranks on every request
+	TrendingWindow     time.Duration `env:"TRENDING_WINDOW" env-default:"168h"`
+	TrendingLikeWeight float64       `env:"TRENDING_LIKE_WEIGHT" env-default:"3"`
+	TrendingCacheTTL   time.Duration `env:"TRENDING_CACHE_TTL" env-default:"1m"`
+
+	// security headers of every api, media and swagger response, empty ones are not sent. The swagger UI
+	// runs inline scripts and gets SwaggerContentSecurityPolicy instead.
+	ContentTypeOptions           string `env:"HEADER_CONTENT_TYPE_OPTIONS" env-default:"nosniff"`
+	FrameOptions                 string `env:"HEADER_FRAME_OPTIONS" env-default:"DENY"`
+	ReferrerPolicy               string `env:"HEADER_REFERRER_POLICY" env-default:"no-referrer"`
+	ContentSecurityPolicy        string `env:"CONTENT_SECURITY_POLICY" env-default:"default-src 'none'; frame-ancestors 'none'"`
+	SwaggerContentSecurityPolicy string `env:"SWAGGER_CONTENT_SECURITY_POLICY" env-default:"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"`
+
+	// TrustProxy takes the client address of the audit log from X-Real-IP, only behind a proxy setting it
+	TrustProxy bool `env:"TRUST_PROXY" env-default:"FALSE"`
+}
+
+// Validate rejects settings the server cannot run with, all problems are joined into one error
+func (cfg *HttpServerConfig) Validate() error {
+	var errs []error
+	if cfg.AccessTTL <= 0 {
+		errs = append(errs, fmt.Errorf("ACCESS_TTL must be positive, got %s", cfg.AccessTTL))
+	}
+	// a refresh token that runs out first would leave nothing to renew the access token with
+	if cfg.RefreshTTL <= cfg.AccessTTL {
+		errs = append(errs, fmt.Errorf("REFRESH_TTL (%s) must be longer than ACCESS_TTL (%s)", cfg.RefreshTTL, cfg.AccessTTL))
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if cfg.TLSCertFile != "" && len(cfg.AutocertDomains) > 0 {
+		errs = append(errs, errors.New("TLS_CERT_FILE and AUTOCERT_DOMAINS exclude each other"))
+	}
+	if cfg.TLSRedirect && !cfg.tlsEnabled() {
+		errs = append(errs, errors.New("TLS_REDIRECT needs TLS_CERT_FILE or AUTOCERT_DOMAINS"))
+	}
+	if cfg.DocsUser != "" && cfg.DocsPassword == "" {
+		errs = append(errs, errors.New("DOCS_USER needs DOCS_PASSWORD"))
+	}
+	if cfg.DocsBaseURL != "" {
+		if base, err := url.Parse(cfg.DocsBaseURL); err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+			errs = append(errs, fmt.Errorf("DOCS_BASE_URL must be an absolute http or https URL, got %q", cfg.DocsBaseURL))
+		}
+	}
+	if cfg.AccountDeletionPolicy != types.DeletionAnonymize && cfg.AccountDeletionPolicy != types.DeletionDelete {
+		errs = append(errs, fmt.Errorf("ACCOUNT_DELETION_POLICY must be anonymize or delete, got %q", cfg.AccountDeletionPolicy))
+	}
+	return errors.Join(errs...)
+}
+
+// Repository is everything the services need from the database
+type Repository interface {
+	service.AuthRepository
+	service.ReaderRepository
+	service.PosterRepository
+	service.AdminRepository
+	service.SchedulerRepository
+	service.TrashRepository
+	service.ViewRepository
+	service.OrphanRepository
+	service.AuditRecorder
+	service.OutboxRepository
+	service.SubscriptionRepository
+	service.NotificationRepository
+	service.ProfileRepository
+	service.AccountRepository
+	service.TokenPurgeRepository
+	service.IdempotencyRepository
+}
+
+// HttpServerOptions carries dependencies of the server. Production passes DB, ImageStorage and the client
+// behind it, tests may replace the repositories and the listener to run many servers in one process.
+type HttpServerOptions struct {
+	DB *postgres.DB
+	// Storage and S3 are checked by the readiness probe when images are kept there
+	Storage *minio.MinIOClient
+	S3      *s3.S3Client
+	// ImageStorage keeps uploaded images, a private one hands out links valid for ImageLinkTTL
+	ImageStorage storage.ImageStorage
+	ImageLinkTTL time.Duration
+	// Mailer defaults to one that only logs
+	Mailer service.Mailer
+	// EventSink receives the post events of the outbox after the notifications of subscribers were made,
+	// it defaults to one that only logs
+	EventSink service.EventSink
+	// Cache keeps the listings of published posts, without one they are read from the database every time
+	Cache cache.Cache
+
+	// Repository overrides the one built on top of DB
+	Repository Repository
+
+	// Listener is used instead of listening on Address:Port, pass one bound to port 0 in tests
+	Listener net.Listener
+	// RedirectListener is used instead of listening on Address:TLSRedirectPort
+	RedirectListener net.Listener
+	// GRPCListener is used instead of listening on Address:GRPCPort, it serves gRPC even without GRPC_PORT
+	GRPCListener net.Listener
+	// Clock drives the background jobs and the day of a view, nil leaves the scheduler to the database clock
+	Clock func() time.Time
+	Docs  bool
+	// Settings is the redacted configuration GET /admin/config shows
+	Settings map[string]string
+	// TracerProvider traces every api request, a nil or no-op one keeps the middleware out of the chain
+	TracerProvider trace.TracerProvider
 }
 
 type HttpServer struct {
-	cfg  *HttpServerConfig
-	http *http.Server
+	cfg       *HttpServerConfig
+	http      *http.Server
+	listener  net.Listener
+	scheduler *service.PublishScheduler
+	cleaner   *service.TrashCleaner
+	orphans   *service.OrphanCleaner
+	views     *service.ViewCounter
+	outbox    *service.OutboxRelay
+	// jobs runs the scheduler, the cleaners and the relay on their intervals
+	jobs      *jobs.Runner
+	published *service.PublishHub
+	// redirect sends plain HTTP to HTTPS, nil without TLS_REDIRECT
+	redirect         *http.Server
+	redirectListener net.Listener
+	// grpc serves the gRPC api, nil without GRPC_PORT
+	grpc         *grpc.Server
+	grpcAddr     string
+	grpcListener net.Listener
 }
 
 //	@securityDefinitions.apikey	BearerAuth
 //	@in							header
 //	@name						Authorization
-//	@description				Enter: Bearer {jwt_token}
+//	@description				Enter: Bearer {jwt_token}, machine clients may send ApiKey {key} instead
 
-func NewHttpServer(cfg HttpServerConfig, db *postgres.DB, storage *minio.MinIOClient, isDoc bool) *HttpServer {
+func NewHttpServer(cfg HttpServerConfig, opts HttpServerOptions) *HttpServer {
+	rootRouter := http.NewServeMux()
 	apiRouter := http.NewServeMux()
 
-	dbRepo := repository.NewBlogRepository(db)
-	storRepo := repository.NewMinIORepository(storage)
+	dbRepo := opts.Repository
+	if dbRepo == nil {
+		dbRepo = repository.NewBlogRepository(opts.DB)
+	}
+	storRepo := opts.ImageStorage
+	var links *service.ImageLinks
+	// a private storage is read only through links signed for every response
+	if !storRepo.Public() {
+		links = service.NewImageLinks(storRepo, opts.ImageLinkTTL)
+	}
+
+	mail := opts.Mailer
+	if mail == nil {
+		mail = &mailer.LogMailer{}
+	}
+	sink := opts.EventSink
+	if sink == nil {
+		sink = &events.LogSink{}
+	}
+
+	authService := service.NewAuthService(dbRepo, mail, dbRepo, service.AuthConfig{
+		Secret:               cfg.Secret,
+		PreviousSecret:       cfg.SecretPrevious,
+		PasswordResetTTL:     cfg.PasswordResetTTL,
+		PasswordResetURL:     cfg.PasswordResetURL,
+		VerifyEmailTTL:       cfg.VerifyEmailTTL,
+		VerifyEmailURL:       cfg.VerifyEmailURL,
+		VerifyResendInterval: cfg.VerifyResendInterval,
+		AccessTTL:            cfg.AccessTTL,
+		RefreshTTL:           cfg.RefreshTTL,
+		MaxSessions:          cfg.MaxSessions,
+		UserCacheTTL:         cfg.UserCacheTTL,
+		UserCacheSize:        cfg.UserCacheSize,
+		Passwords: hash.Params{
+			Memory:      cfg.PasswordHashMemory,
+			Iterations:  cfg.PasswordHashIterations,
+			Parallelism: cfg.PasswordHashParallelism,
+		},
+		Tokens: jwt.Options{
+			Issuer:      cfg.JWTIssuer,
+			Audience:    cfg.JWTAudience,
+			Leeway:      cfg.JWTLeeway,
+			AllowLegacy: cfg.JWTAllowLegacy,
+		},
+	})
+	views := service.NewViewCounter(dbRepo, cfg.ViewsBuffer, cfg.ViewsBatchSize, cfg.ViewsFlushInterval, opts.Clock)
+	listings := service.NewPostListCache(opts.Cache, cfg.PostsCacheTTL)
+	content := service.NewContentRenderer(markdown.New(cfg.ContentImageHosts), cfg.ContentCacheSize)
+	reading := service.NewReadingTime(cfg.ReadingWordsPerMinute)
+	trending := service.NewTrending(cfg.TrendingWindow, cfg.TrendingLikeWeight, cfg.TrendingCacheTTL, nil)
+	readerService := service.NewReaderService(dbRepo, views, links, listings, content, reading, trending)
+	published := service.NewPublishHub(cfg.StreamBuffer)
+	posterService := service.NewPosterService(dbRepo, storRepo, dbRepo, links, cfg.ImagesStripExif, cfg.TrashRetention, listings, published, reading, service.ImageLimits{
+		PerPost: cfg.ImagesPerPost,
+		Bytes:   cfg.MaxImageBytes,
+		Workers: cfg.ImagesUploadWorkers,
+		Quota:   cfg.StorageQuotaBytes,
+	}, cfg.PreviewTokenTTL, cfg.RequireVerifiedEmail)
+	subscriptionService := service.NewSubscriptionService(dbRepo)
+	profileService := service.NewProfileService(dbRepo, storRepo, links, authService.Users(), listings, cfg.ImagesStripExif, cfg.MaxImageBytes)
+	accountService := service.NewAccountService(dbRepo, storRepo, links, authService.Users(), listings, dbRepo, cfg.AccountDeletionPolicy)
+	adminService := service.NewAdminService(dbRepo, authService.Users(), listings, dbRepo)
+	orphans := service.NewOrphanCleaner(dbRepo, storRepo, cfg.OrphanMinAge, opts.Clock)
+	scheduler := service.NewPublishScheduler(dbRepo, listings, published, opts.Clock)
+	cleaner := service.NewTrashCleaner(dbRepo, storRepo, cfg.TrashRetention, opts.Clock)
+	outbox := service.NewOutboxRelay(dbRepo, service.NewNotificationFanout(dbRepo, sink), cfg.OutboxBatchSize)
+	tokens := service.NewTokenPurger(dbRepo, cfg.TokenPurgeBatchSize, cfg.TokenPurgeGrace, opts.Clock)
+	idempotency := service.NewIdempotencyKeys(dbRepo, cfg.IdempotencyKeyTTL, opts.Clock)
 
-	authService := service.NewAuthService(dbRepo, "secret")
-	readerService := service.NewReaderService(dbRepo)
-	posterService := service.NewPosterService(dbRepo, storRepo)
+	runner := jobs.New(nil)
+	runner.Register("scheduler", cfg.SchedulerInterval, scheduler.Run)
+	runner.Register("trash_cleaner", cfg.TrashCleanupInterval, cleaner.Run)
+	runner.Register("orphan_cleaner", cfg.OrphanCleanupInterval, orphans.Run)
+	runner.Register("outbox_relay", cfg.OutboxInterval, outbox.Run)
+	runner.Register("token_purger", cfg.TokenPurgeInterval, tokens.Run)
 
 	authMMan := mw.NewAuthMiddlewareManager(authService) //AuthMiddleWareManager - создаёт объект, где хранится секрет, для более гибкой работы с мидлварами и передачи их в роутеры
 
-	authRouter := routers.GetAuthRouter(authService)
-	readRouter := routers.GetReaderRouter(readerService, authMMan)
-	posterRouter := routers.GetPosterRouter(posterService)
+	authRouter := routers.GetAuthRouter(authService, authMMan, cfg.AuthCookieMode, cfg.RefreshTTL)
+	postsRouter := routers.GetPostsRouter(readerService, posterService, subscriptionService, profileService, accountService, published, authMMan, idempotency, cfg.MaxBodyBytes, cfg.MaxUploadBytes, cfg.MaxImportBytes, cfg.RequestTimeout, cfg.UploadTimeout, cfg.ExportTimeout, cfg.StreamHeartbeat)
+	adminRouter := routers.GetAdminRouter(adminService, orphans, tokens, runner, opts.Settings)
+	publicRouter := routers.GetPublicRouter(readerService, cfg.PublicCacheMaxAge)
+
+	// roles are checked per route inside, anything not matched elsewhere needs a user.
+	// The posts router applies the request timeout itself, uploads get longer.
+	apiRouter.Handle("/", authMMan.AuthMiddleware(postsRouter))
+	bodyLimit := mw.BodyLimit(cfg.MaxBodyBytes)
+	timeout := mw.Timeout(cfg.RequestTimeout)
+	jsonBody := mw.ContentType(mw.MediaTypeJSON)
+	apiRouter.Handle("/auth/", timeout(bodyLimit(jsonBody(authRouter))))
+	// administration is for logged in admins, an API key of one gets nowhere
+	apiRouter.Handle("/admin/", authMMan.AuthMiddleware(authMMan.RequireSession(authMMan.RequireRole(types.Admin)(mw.Timeout(cfg.AdminRequestTimeout)(bodyLimit(jsonBody(adminRouter)))))))
+	// anonymous visitors read published posts here, nothing under /public/ may need a user
+	apiRouter.Handle("/public/", timeout(publicRouter))
+
+	headers := mw.SecurityHeaders{
+		ContentTypeOptions:    cfg.ContentTypeOptions,
+		FrameOptions:          cfg.FrameOptions,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+	}
+	secure := mw.SecureHeaders(headers)
+
+	// OPTIONS and methods a path is not served for are answered before auth, which would want a token first
+	methods := mw.Methods(postsRouter, authRouter, adminRouter, publicRouter)
+	var api http.Handler = mw.JSONHandler(mw.StrictJSON(cfg.StrictJSON)(methods(apiRouter)))
+	if cfg.Gzip {
+		api = mw.Gzip(cfg.GzipMinBytes)(api)
+	}
+	api = mw.Head(api)
+	versioned := func(version apiversion.Version) http.Handler {
+		// headers go first, errors of the outer middlewares carry them as well
+		handler := secure(http.StripPrefix(version.Prefix(), mw.RequestId(mw.Logger(mw.Recover(mw.ClientInfo(cfg.TrustProxy)(mw.APIVersion(version)(api)))))))
+		if !tracing.Enabled(opts.TracerProvider) {
+			return handler
+		}
+		// the span is opened first, so the repositories called by the handler nest their spans under it,
+		// it sees the path with its secrets redacted
+		return mw.RedactURL(otelhttp.NewHandler(mw.Unredact(handler), version.Prefix(),
+			otelhttp.WithTracerProvider(opts.TracerProvider),
+			otelhttp.WithPropagators(otel.GetTextMapPropagator()),
+			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+				return r.Method + " " + operation
+			}),
+		))
+	}
 
-	apiRouter.Handle("/", authMMan.AuthMiddleware(readRouter))
-	// Поменял ендпоинт т.к стандартный пакет не может сравнивать схожие ендпоинты в разных роутерах, что приводит к неверному поведению
-	apiRouter.Handle("/post/", authMMan.AuthMiddleware(authMMan.AuthorOnlyMiddleware(posterRouter)))
-	apiRouter.Handle("/auth/", authRouter)
+	rootRouter.Handle(apiversion.V1.Prefix()+"/", versioned(apiversion.V1))
+	// older clients know only /api, it serves the current version until they moved over
+	rootRouter.Handle(apiversion.Unversioned.Prefix()+"/", versioned(apiversion.Unversioned))
 
-	router := mw.Logger(mw.JSONHandler(apiRouter))
+	// files of the fs storage, MinIO serves its objects itself
+	if fsRepo, ok := storRepo.(*repository.FSRepository); ok {
+		media := routers.GetMediaRouter(fsRepo)
+		rootRouter.Handle(repository.MediaPrefix, mw.RequestId(mw.Logger(mw.Recover(secure(mw.Methods(media)(media))))))
+	}
 
-	http.DefaultServeMux.Handle("/api/", http.StripPrefix("/api", router))
+	if cfg.DebugEndpoints {
+		gauges := map[string]expvar.Var{
+			"goroutines": expvar.Func(func() any { return runtime.NumGoroutine() }),
+		}
+		if opts.DB != nil {
+			gauges["db_open_connections"] = expvar.Func(func() any { return opts.DB.Stats().OpenConnections })
+		}
+		debug := routers.GetDebugRouter(gauges)
+		operator := authMMan.RequireOperator(cfg.OperatorToken)
+		rootRouter.Handle("/debug/", mw.RequestId(mw.Logger(mw.Recover(secure(mw.Methods(debug)(operator(debug)))))))
+	}
+
+	// probes live outside /api so they never go through auth
+	healthDeps := map[string]service.HealthDependency{}
+	if opts.DB != nil {
+		healthDeps["postgres"] = opts.DB
+	}
+	if opts.Storage != nil {
+		healthDeps["minio"] = opts.Storage
+	}
+	if opts.S3 != nil {
+		healthDeps["s3"] = opts.S3
+	}
+	healthService := service.NewHealthService(cfg.HealthTimeout, healthDeps)
+	health := routers.GetHealthRouter(healthService)
+	healthRouter := mw.Head(mw.JSONHandler(mw.Methods(health)(health)))
+	rootRouter.Handle("/healthz", healthRouter)
+	rootRouter.Handle("/readyz", healthRouter)
+
+	build := version.Get()
+	versionRouter := routers.GetVersionRouter(build)
+	rootRouter.Handle("/version", mw.Head(mw.JSONHandler(mw.Methods(versionRouter)(versionRouter))))
 
 	server := &http.Server{
-		Addr: fmt.Sprintf("%s:%d", cfg.Address, cfg.Port),
+		Addr:              fmt.Sprintf("%s:%d", cfg.Address, cfg.Port),
+		Handler:           mw.AppVersion(build.Version)(rootRouter),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
 	}
+	redirect := newTLS(&cfg, server)
 
-	if isDoc {
+	if opts.Docs {
 		docs.SwaggerInfo.Title = "CPC Blog API"
 		docs.SwaggerInfo.Description = "This is API CPC Blog server"
 		docs.SwaggerInfo.Version = "1.0"
 		docs.SwaggerInfo.Host = server.Addr
-		docs.SwaggerInfo.BasePath = "/api"
+		docs.SwaggerInfo.Schemes = []string{}
+		docs.SwaggerInfo.BasePath = apiversion.Current.Prefix()
+		// Validate made sure it parses
+		if base, err := url.Parse(cfg.DocsBaseURL); cfg.DocsBaseURL != "" && err == nil {
+			docs.SwaggerInfo.Host = base.Host
+			docs.SwaggerInfo.Schemes = []string{base.Scheme}
+			docs.SwaggerInfo.BasePath = strings.TrimSuffix(base.Path, "/") + apiversion.Current.Prefix()
+		}
 
-		http.Handle("/swagger/", httpSwagger.WrapHandler)
+		headers.ContentSecurityPolicy = cfg.SwaggerContentSecurityPolicy
+		docsAccess := authMMan.RequireDocsAccess(cfg.DocsUser, cfg.DocsPassword)
+		rootRouter.Handle("/swagger/", mw.SecureHeaders(headers)(docsAccess(httpSwagger.WrapHandler)))
+	}
+
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != 0 || opts.GRPCListener != nil {
+		grpcServer = blogrpc.NewServer(authService, readerService, posterService, cfg.RequestTimeout)
 	}
-	slog.Info("Start listening http on", slog.String("addr", server.Addr))
 
 	return &HttpServer{
 		&cfg,
 		server,
+		opts.Listener,
+		scheduler,
+		cleaner,
+		orphans,
+		views,
+		outbox,
+		runner,
+		published,
+		redirect,
+		opts.RedirectListener,
+		grpcServer,
+		fmt.Sprintf("%s:%d", cfg.Address, cfg.GRPCPort),
+		opts.GRPCListener,
+	}
+}
+
+// Listen binds the server address, the redirect one with TLS_REDIRECT and the gRPC one with GRPC_PORT,
+// without serving yet, Start calls it when needed
+func (s *HttpServer) Listen() error {
+	if s.listener == nil {
+		listener, err := net.Listen("tcp", s.http.Addr)
+		if err != nil {
+			return err
+		}
+		s.listener = listener
+	}
+	if s.redirect != nil && s.redirectListener == nil {
+		listener, err := net.Listen("tcp", s.redirect.Addr)
+		if err != nil {
+			return err
+		}
+		s.redirectListener = listener
+	}
+	if s.grpc != nil && s.grpcListener == nil {
+		listener, err := net.Listen("tcp", s.grpcAddr)
+		if err != nil {
+			return err
+		}
+		s.grpcListener = listener
+	}
+	return nil
+}
+
+// Addr is the bound address, it is known once Listen or Start was called
+func (s *HttpServer) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// RedirectAddr is the bound address of the redirect listener, empty without TLS_REDIRECT
+func (s *HttpServer) RedirectAddr() string {
+	if s.redirectListener == nil {
+		return ""
+	}
+	return s.redirectListener.Addr().String()
+}
+
+// GRPCAddr is the bound address of the gRPC listener, empty without GRPC_PORT
+func (s *HttpServer) GRPCAddr() string {
+	if s.grpcListener == nil {
+		return ""
 	}
+	return s.grpcListener.Addr().String()
 }
 
+// Start serves until Stop, a certificate that cannot be loaded fails it before anything listens
 func (s *HttpServer) Start() error {
-	return s.http.ListenAndServe()
+	if err := s.loadCertificate(); err != nil {
+		return err
+	}
+	if err := s.Listen(); err != nil {
+		return err
+	}
+
+	s.jobs.Start()
+	s.views.Start()
+
+	if s.redirect != nil {
+		slog.Info("Start redirecting http to https on", slog.String("addr", s.RedirectAddr()))
+		go func() {
+			if err := s.redirect.Serve(s.redirectListener); !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("redirect listener stopped", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	if s.grpc != nil {
+		slog.Info("Start serving grpc on", slog.String("addr", s.GRPCAddr()))
+		go func() {
+			if err := s.grpc.Serve(s.grpcListener); err != nil {
+				slog.Error("grpc listener stopped", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	var err error
+	if s.http.TLSConfig != nil {
+		slog.Info("Start listening https on", slog.String("addr", s.Addr()))
+		// the certificates are in TLSConfig already
+		err = s.http.ServeTLS(s.listener, "", "")
+	} else {
+		slog.Info("Start listening http on", slog.String("addr", s.Addr()))
+		err = s.http.Serve(s.listener)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Scheduler publishes due posts, tests call its Tick instead of waiting for the interval
+func (s *HttpServer) Scheduler() *service.PublishScheduler {
+	return s.scheduler
 }
 
+// TrashCleaner purges expired trash, tests call its Tick instead of waiting for the interval
+func (s *HttpServer) TrashCleaner() *service.TrashCleaner {
+	return s.cleaner
+}
+
+// Outbox publishes post events, tests call its Tick instead of waiting for the interval
+func (s *HttpServer) Outbox() *service.OutboxRelay {
+	return s.outbox
+}
+
+// Jobs runs the background jobs, its Stats are what GET /admin/jobs shows
+func (s *HttpServer) Jobs() *jobs.Runner {
+	return s.jobs
+}
+
+// Views counts post reads, tests call its Flush instead of waiting for the interval
+func (s *HttpServer) Views() *service.ViewCounter {
+	return s.views
+}
+
+// Stop closes the listener and every open connection and stops the background jobs, gRPC calls
+// under way are finished first and the post streams are ended. Jobs still running after
+// JOBS_STOP_TIMEOUT are cancelled. Views queued by the last requests are written before it returns.
 func (s *HttpServer) Stop() error {
-	return s.http.Close()
+	s.published.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.JobsStopTimeout)
+	defer cancel()
+	if err := s.jobs.Stop(ctx); err != nil {
+		slog.Warn("background jobs cancelled on stop", slog.String("error", err.Error()))
+	}
+	if s.grpc != nil {
+		// the calls run at most REQUEST_TIMEOUT, new ones are refused meanwhile
+		s.grpc.GracefulStop()
+	}
+	err := s.http.Close()
+	if s.redirect != nil {
+		if cerr := s.redirect.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	s.views.Stop()
+	// Serve closes the listeners itself, this covers servers stopped before Start
+	for _, listener := range []net.Listener{s.listener, s.redirectListener, s.grpcListener} {
+		if listener == nil {
+			continue
+		}
+		if cerr := listener.Close(); cerr != nil && !errors.Is(cerr, net.ErrClosed) && err == nil {
+			err = cerr
+		}
+	}
+	return err
 }
@@ -0,0 +1,3830 @@
+package servers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	minIO "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/core/servers"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/testing/harness"
+	"github.com/xkarasb/blog/internal/transport/grpc/blogpb"
+	"github.com/xkarasb/blog/pkg/db/postgres"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/storage/minio"
+	"github.com/xkarasb/blog/pkg/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func decode(t *testing.T, resp *http.Response, v interface{}) {
+	t.Helper()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func jsonBody(t *testing.T, v interface{}) *bytes.Reader {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewReader(data)
+}
+
+func TestEndToEnd_AuthorPublishesReaderReads(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+		Email: "author@example.com", Password: "Password123!",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var login dto.LoginUserResponse
+	decode(t, resp, &login)
+	authorToken := login.AccessToken
+
+	resp = h.Do(t, http.MethodPost, "/posts", authorToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "e2e-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	assert.Equal(t, "/api/v1/posts/"+created.PostId.String(), resp.Header.Get("Location"))
+	assert.Equal(t, "Hello", created.Title)
+	assert.Equal(t, types.Draft, created.Status)
+
+	// a retry after a lost response gets the same post, another payload under the key is a conflict
+	resp = h.Do(t, http.MethodPost, "/posts", authorToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "e2e-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var retried dto.CreatePostResponse
+	decode(t, resp, &retried)
+	assert.Equal(t, created.PostId, retried.PostId)
+	resp = h.Do(t, http.MethodPost, "/posts", authorToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "e2e-1", Title: "Hello", Content: "Another world",
+	}))
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp.Body.Close()
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="pic.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+	require.NoError(t, writer.Close())
+
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), authorToken, writer.FormDataContentType(), &upload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var image dto.AddImageResponse
+	decode(t, resp, &image)
+	assert.Equal(t, fmt.Sprintf("/api/v1/posts/%s/images/%s", created.PostId, image.ImageId), resp.Header.Get("Location"))
+	_, stored := h.Storage.Object(image.ImageId.String() + ".png")
+	assert.True(t, stored)
+	assert.Equal(t, "/images/"+image.ImageId.String()+".png", image.ImageUrl)
+
+	var text bytes.Buffer
+	writer = multipart.NewWriter(&text)
+	// a png name does not make text an image
+	header.Set("Content-Disposition", `form-data; name="image"; filename="notes.png"`)
+	part, err = writer.CreatePart(header)
+	require.NoError(t, err)
+	part.Write([]byte("just some notes"))
+	require.NoError(t, writer.Close())
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), authorToken, writer.FormDataContentType(), &text)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), authorToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodGet, "/posts", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts []dto.GetPostResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, created.PostId, posts[0].PostId)
+	assert.Equal(t, types.Published, posts[0].Status)
+	assert.Equal(t, "a***@example.com", posts[0].Author.DisplayName)
+	assert.Empty(t, posts[0].Author.Email)
+	require.Len(t, posts[0].Images, 1)
+	assert.Equal(t, image.ImageId, posts[0].Images[0].ImageId)
+}
+
+func TestHttpServer_ManyInstances(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		t.Run(fmt.Sprintf("instance-%d", i), func(t *testing.T) {
+			t.Parallel()
+			h := harness.New(t)
+
+			resp, err := h.Client.Get("http://" + h.Server.Addr() + "/healthz")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+				Email: uuid.NewString() + "@example.com", Password: "Password123!", Role: types.Reader,
+			}))
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func testConfig() servers.HttpServerConfig {
+	return servers.HttpServerConfig{
+		Secret:        "isolation-secret",
+		HealthTimeout: time.Second,
+		AccessTTL:     time.Hour,
+		RefreshTTL:    24 * time.Hour,
+		MaxSessions:   10,
+
+		AccountDeletionPolicy: types.DeletionAnonymize,
+	}
+}
+
+// startServer serves a server with in-memory repositories, with docs it also serves /swagger/
+func startServer(t *testing.T, cfg servers.HttpServerConfig, docs bool) (string, *harness.MemoryRepository) {
+	t.Helper()
+
+	rep := harness.NewMemoryRepository()
+	server := servers.NewHttpServer(cfg, servers.HttpServerOptions{
+		Repository:   rep,
+		ImageStorage: harness.NewMemoryStorage(time.Now),
+		Listener:     listen(t),
+		Docs:         docs,
+	})
+	serve(t, server)
+	return "http://" + server.Addr(), rep
+}
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return listener
+}
+
+// serve runs server until the test ends
+func serve(t *testing.T, server *servers.HttpServer) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Start()
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, server.Stop())
+		require.NoError(t, <-done)
+	})
+}
+
+func TestHttpServer_GRPC(t *testing.T) {
+	rep := harness.NewMemoryRepository()
+	server := servers.NewHttpServer(testConfig(), servers.HttpServerOptions{
+		Repository:   rep,
+		ImageStorage: harness.NewMemoryStorage(time.Now),
+		Listener:     listen(t),
+		GRPCListener: listen(t),
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Start()
+	}()
+	base := "http://" + server.Addr() + "/api/v1"
+
+	resp, err := http.Post(base+"/auth/register", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	conn, err := grpc.NewClient(server.GRPCAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+
+	login, err := blogpb.NewAuthServiceClient(conn).Login(ctx, &blogpb.LoginRequest{Email: "author@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	authorized := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+login.GetAccessToken())
+
+	req, err := http.NewRequest(http.MethodPost, base+"/posts", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "grpc-1", Title: "Over gRPC", Content: "Same services",
+	}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+login.GetAccessToken())
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp.Body.Close()
+
+	poster := blogpb.NewPosterServiceClient(conn)
+	published, err := poster.PublishPost(authorized, &blogpb.PublishPostRequest{PostId: created.PostId.String(), Status: "published"})
+	require.NoError(t, err)
+	assert.Equal(t, "published", published.GetStatus())
+
+	edited, err := poster.EditPost(authorized, &blogpb.EditPostRequest{PostId: created.PostId.String(), Title: "Edited over gRPC", Content: "Same services", Tags: []string{"Go"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, edited.GetTags())
+
+	reader := blogpb.NewReaderServiceClient(conn)
+	list, err := reader.ListPublishedPosts(authorized, &blogpb.ListPublishedPostsRequest{Tag: "go"})
+	require.NoError(t, err)
+	require.Len(t, list.GetPosts(), 1)
+	assert.Equal(t, "Edited over gRPC", list.GetPosts()[0].GetTitle())
+	assert.Equal(t, "a***@example.com", list.GetPosts()[0].GetAuthor().GetDisplayName())
+	assert.Empty(t, list.GetPosts()[0].GetAuthor().GetEmail())
+
+	_, err = reader.GetPost(ctx, &blogpb.GetPostRequest{PostId: created.PostId.String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// one Stop ends both servers
+	require.NoError(t, server.Stop())
+	require.NoError(t, <-done)
+	_, err = reader.GetPost(authorized, &blogpb.GetPostRequest{PostId: created.PostId.String()})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = http.Get(base + "/public/posts")
+	assert.Error(t, err)
+}
+
+func TestHttpServer_GRPCOff(t *testing.T) {
+	server := servers.NewHttpServer(testConfig(), servers.HttpServerOptions{
+		Repository:   harness.NewMemoryRepository(),
+		ImageStorage: harness.NewMemoryStorage(time.Now),
+		Listener:     listen(t),
+	})
+	serve(t, server)
+	assert.Empty(t, server.GRPCAddr())
+}
+
+func TestHttpServer_SecurityHeaders(t *testing.T) {
+	cfg := testConfig()
+	cfg.ContentTypeOptions = "nosniff"
+	cfg.FrameOptions = "DENY"
+	cfg.ReferrerPolicy = "no-referrer"
+	cfg.ContentSecurityPolicy = "default-src 'none'"
+	cfg.SwaggerContentSecurityPolicy = "default-src 'self'"
+	cfg.DocsUser, cfg.DocsPassword = "docs", "docs-password"
+	base, _ := startServer(t, cfg, true)
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCSP    string
+	}{
+		{name: "answer", path: "/api/v1/public/posts", wantStatus: http.StatusOK, wantCSP: "default-src 'none'"},
+		{name: "error", path: "/api/v1/posts", wantStatus: http.StatusUnauthorized, wantCSP: "default-src 'none'"},
+		{name: "unversioned", path: "/api/public/posts/" + uuid.NewString(), wantStatus: http.StatusNotFound, wantCSP: "default-src 'none'"},
+		{name: "swagger", path: "/swagger/index.html", wantStatus: http.StatusOK, wantCSP: "default-src 'self'"},
+		{name: "swagger refused", path: "/swagger/index.html", wantStatus: http.StatusUnauthorized, wantCSP: "default-src 'self'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, base+tt.path, nil)
+			require.NoError(t, err)
+			if tt.name == "swagger" {
+				req.SetBasicAuth("docs", "docs-password")
+			}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+			assert.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+			assert.Equal(t, "no-referrer", resp.Header.Get("Referrer-Policy"))
+			assert.Equal(t, tt.wantCSP, resp.Header.Get("Content-Security-Policy"))
+		})
+	}
+}
+
+func TestHttpServer_RoutesIsolated(t *testing.T) {
+	// anything registered globally, as expvar or pprof do, must not be served
+	http.HandleFunc("/debug/leaked", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	cfg := testConfig()
+	cfg.DocsUser, cfg.DocsPassword = "docs", "docs-password"
+	withDocs, withDocsRep := startServer(t, cfg, true)
+	withoutDocs, withoutDocsRep := startServer(t, cfg, false)
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	get := func(url string) int {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("docs", "docs-password")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, get(withDocs+"/swagger/index.html"))
+	assert.Equal(t, http.StatusNotFound, get(withoutDocs+"/swagger/index.html"))
+	for _, base := range []string{withDocs, withoutDocs} {
+		assert.Equal(t, http.StatusNotFound, get(base+"/debug/leaked"))
+		assert.Equal(t, http.StatusOK, get(base+"/healthz"))
+	}
+
+	resp, err := client.Post(withDocs+"/api/auth/register", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "only-here@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = withDocsRep.GetUserByEmail(context.Background(), "only-here@example.com")
+	assert.NoError(t, err)
+	_, err = withoutDocsRep.GetUserByEmail(context.Background(), "only-here@example.com")
+	assert.Error(t, err)
+}
+
+func TestHttpServer_Docs(t *testing.T) {
+	cfg := testConfig()
+	cfg.DocsUser, cfg.DocsPassword = "docs", "docs-password"
+	cfg.DocsBaseURL = "https://blog.example.com/"
+	base, rep := startServer(t, cfg, true)
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	get := func(t *testing.T, auth func(r *http.Request)) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, base+"/swagger/doc.json", nil)
+		require.NoError(t, err)
+		auth(req)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	token := func(t *testing.T, path string, body any) string {
+		resp, err := client.Post(base+"/api/v1"+path, "application/json", jsonBody(t, body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var login dto.LoginUserResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+		return login.AccessToken
+	}
+
+	t.Run("without credentials", func(t *testing.T) {
+		resp := get(t, func(r *http.Request) {})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, `Basic realm="docs", charset="UTF-8"`, resp.Header.Get("WWW-Authenticate"))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		resp := get(t, func(r *http.Request) { r.SetBasicAuth("docs", "guess") })
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("reader token", func(t *testing.T) {
+		reader := token(t, "/auth/register", dto.RegistrateUserRequest{Email: "reader@example.com", Password: "Password123!", Role: types.Reader})
+		resp := get(t, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+reader) })
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("admin token", func(t *testing.T) {
+		_, err := service.NewAdminService(rep, nil, nil, nil).CreateAdmin(context.Background(), "root@example.com", "Password123!")
+		require.NoError(t, err)
+		admin := token(t, "/auth/login", dto.LoginUserRequest{Email: "root@example.com", Password: "Password123!"})
+		resp := get(t, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+admin) })
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		resp := get(t, func(r *http.Request) { r.SetBasicAuth("docs", "docs-password") })
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var spec struct {
+			Host     string   `json:"host"`
+			BasePath string   `json:"basePath"`
+			Schemes  []string `json:"schemes"`
+			Paths    map[string]map[string]struct {
+				Responses map[string]struct {
+					Schema *struct {
+						Ref string `json:"$ref"`
+					} `json:"schema"`
+				} `json:"responses"`
+			} `json:"paths"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+		assert.Equal(t, "blog.example.com", spec.Host)
+		assert.Equal(t, "/api/v1", spec.BasePath)
+		assert.Equal(t, []string{"https"}, spec.Schemes)
+
+		// every failure has a JSON body clients can rely on
+		require.NotEmpty(t, spec.Paths)
+		for path, operations := range spec.Paths {
+			for method, operation := range operations {
+				for status, response := range operation.Responses {
+					if status < "400" {
+						continue
+					}
+					if assert.NotNil(t, response.Schema, "%s %s %s", method, path, status) {
+						assert.NotEmpty(t, response.Schema.Ref, "%s %s %s", method, path, status)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestHttpServer_DebugEndpoints(t *testing.T) {
+	cfg := testConfig()
+	cfg.OperatorToken = "operator-token"
+	disabled, _ := startServer(t, cfg, false)
+	cfg.DebugEndpoints = true
+	enabled, rep := startServer(t, cfg, false)
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	get := func(t *testing.T, url, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/vars"} {
+			assert.Equal(t, http.StatusNotFound, get(t, disabled+path, "operator-token").StatusCode, path)
+		}
+	})
+
+	t.Run("without token", func(t *testing.T) {
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/vars"} {
+			assert.Equal(t, http.StatusUnauthorized, get(t, enabled+path, "").StatusCode, path)
+			assert.Equal(t, http.StatusUnauthorized, get(t, enabled+path, "guess").StatusCode, path)
+		}
+	})
+
+	t.Run("operator token", func(t *testing.T) {
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+			assert.Equal(t, http.StatusOK, get(t, enabled+path, "operator-token").StatusCode, path)
+		}
+
+		resp := get(t, enabled+"/debug/vars", "operator-token")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var vars map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&vars))
+		assert.Contains(t, vars, "memstats")
+		var goroutines int
+		require.NoError(t, json.Unmarshal(vars["goroutines"], &goroutines))
+		assert.Positive(t, goroutines)
+	})
+
+	t.Run("admin and reader", func(t *testing.T) {
+		login := func(t *testing.T, path string, body any) string {
+			resp, err := client.Post(enabled+"/api/v1"+path, "application/json", jsonBody(t, body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var login dto.LoginUserResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+			return login.AccessToken
+		}
+		_, err := service.NewAdminService(rep, nil, nil, nil).CreateAdmin(context.Background(), "root@example.com", "Password123!")
+		require.NoError(t, err)
+		admin := login(t, "/auth/login", dto.LoginUserRequest{Email: "root@example.com", Password: "Password123!"})
+		reader := login(t, "/auth/register", dto.RegistrateUserRequest{Email: "reader@example.com", Password: "Password123!", Role: types.Reader})
+
+		assert.Equal(t, http.StatusOK, get(t, enabled+"/debug/vars", admin).StatusCode)
+		assert.Equal(t, http.StatusForbidden, get(t, enabled+"/debug/vars", reader).StatusCode)
+	})
+}
+
+func TestHttpServer_Version(t *testing.T) {
+	base, _ := startServer(t, testConfig(), false)
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(base + "/version")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	// the test binary is built without -ldflags
+	var build map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&build))
+	assert.Equal(t, map[string]string{"version": "dev", "commit": "dev", "build_date": "dev", "go_version": runtime.Version()}, build)
+
+	for _, path := range []string{"/version", "/healthz", "/api/v1/public/posts", "/api/v1/posts"} {
+		resp, err := client.Get(base + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "dev", resp.Header.Get("X-App-Version"), path)
+	}
+}
+
+func TestHttpServer_SlowHeadersDropped(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReadHeaderTimeout = 100 * time.Millisecond
+	base, _ := startServer(t, cfg, false)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(base, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// headers that never end, one line at a time like slowloris
+	_, err = conn.Write([]byte("GET /healthz HTTP/1.1\r\nHost: blog\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	started := time.Now()
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF, "the server must close the connection before the test gives up")
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+// tlsClient trusts the self-signed certificate of testdata and does not follow redirects
+func tlsClient(t *testing.T) *http.Client {
+	t.Helper()
+	pem, err := os.ReadFile("testdata/cert.pem")
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(pem))
+	return &http.Client{
+		Timeout:       5 * time.Second,
+		Transport:     &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+func TestHttpServer_TLS(t *testing.T) {
+	newServer := func(t *testing.T, cfg servers.HttpServerConfig, redirect net.Listener) *servers.HttpServer {
+		listener := listen(t)
+		// redirects point at the port of the https listener
+		cfg.Port = listener.Addr().(*net.TCPAddr).Port
+		return servers.NewHttpServer(cfg, servers.HttpServerOptions{
+			Repository:       harness.NewMemoryRepository(),
+			ImageStorage:     harness.NewMemoryStorage(time.Now),
+			Listener:         listener,
+			RedirectListener: redirect,
+		})
+	}
+	withCert := func() servers.HttpServerConfig {
+		cfg := testConfig()
+		cfg.TLSCertFile = "testdata/cert.pem"
+		cfg.TLSKeyFile = "testdata/key.pem"
+		return cfg
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		server := newServer(t, testConfig(), nil)
+		serve(t, server)
+
+		resp, err := tlsClient(t).Get("https://" + server.Addr() + "/healthz")
+		assert.Error(t, err, "no TLS without a certificate")
+		if err == nil {
+			resp.Body.Close()
+		}
+		resp, err = http.Get("http://" + server.Addr() + "/healthz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, server.RedirectAddr())
+	})
+
+	t.Run("static certificate", func(t *testing.T) {
+		server := newServer(t, withCert(), nil)
+		serve(t, server)
+
+		resp, err := tlsClient(t).Get("https://" + server.Addr() + "/healthz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotNil(t, resp.TLS)
+		assert.GreaterOrEqual(t, resp.TLS.Version, uint16(tls.VersionTLS12))
+	})
+
+	t.Run("unreadable certificate", func(t *testing.T) {
+		cfg := withCert()
+		cfg.TLSKeyFile = filepath.Join(t.TempDir(), "missing.pem")
+		server := newServer(t, cfg, nil)
+
+		err := server.Start()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.pem")
+		require.NoError(t, server.Stop())
+	})
+
+	t.Run("redirect listener", func(t *testing.T) {
+		cfg := withCert()
+		cfg.TLSRedirect = true
+		server := newServer(t, cfg, listen(t))
+		serve(t, server)
+		client := tlsClient(t)
+
+		resp, err := client.Post("http://"+server.RedirectAddr()+"/api/public/posts?limit=1", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusPermanentRedirect, resp.StatusCode)
+		assert.Equal(t, "https://"+server.Addr()+"/api/public/posts?limit=1", resp.Header.Get("Location"))
+
+		resp, err = client.Get("http://" + server.RedirectAddr() + "/healthz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		resp, err = client.Get(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("redirect listener answers acme challenges", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.AutocertDomains = []string{"blog.example.com"}
+		cfg.AutocertCacheDir = t.TempDir()
+		cfg.TLSRedirect = true
+		server := newServer(t, cfg, listen(t))
+		serve(t, server)
+
+		challenge := func(host string) int {
+			req, err := http.NewRequest(http.MethodGet, "http://"+server.RedirectAddr()+"/.well-known/acme-challenge/unknown", nil)
+			require.NoError(t, err)
+			req.Host = host
+			resp, err := tlsClient(t).Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		// an unknown token rather than a redirect, other hosts are refused
+		assert.Equal(t, http.StatusNotFound, challenge("blog.example.com"))
+		assert.Equal(t, http.StatusForbidden, challenge("other.example.com"))
+	})
+}
+
+func TestHttpServer_MaxHeaderBytes(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxHeaderBytes = 1 << 10
+	base, _ := startServer(t, cfg, false)
+
+	req, err := http.NewRequest(http.MethodGet, base+"/healthz", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Padding", strings.Repeat("a", 8<<10))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+}
+
+func TestEndToEnd_AdminManagesUsersAndPosts(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "root@example.com", Password: "Password123!", Role: types.Admin,
+	}))
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "admin must not be self-assigned")
+
+	_, err := service.NewAdminService(h.Repository, nil, nil, nil).CreateAdmin(context.Background(), "root@example.com", "Password123!")
+	require.NoError(t, err)
+	resp = h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+		Email: "root@example.com", Password: "Password123!",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var login dto.LoginUserResponse
+	decode(t, resp, &login)
+	adminToken := login.AccessToken
+
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodGet, "/admin/users", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/admin/users?limit=1", adminToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page dto.GetUsersResponse
+	decode(t, resp, &page)
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Users, 1)
+	assert.Equal(t, "author@example.com", page.Users[0].Email)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "admin-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/admin/posts/%s/status", created.PostId), adminToken, "application/json", jsonBody(t, dto.SetPostStatusRequest{
+		Status: types.Draft,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	post, err := h.Repository.GetPostById(context.Background(), created.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, types.Draft, post.Status)
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/admin/users/%s/role", author.Id), adminToken, "application/json", jsonBody(t, dto.ChangeRoleRequest{
+		Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the role is read on every request, the old token loses author rights at once
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Again", Content: "Again",
+	}))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/admin/audit?action=publish_post", adminToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var audit dto.GetAuditLogResponse
+	decode(t, resp, &audit)
+	require.Equal(t, 1, audit.Total)
+	assert.Equal(t, author.Id, *audit.Entries[0].UserId)
+	assert.Equal(t, created.PostId, *audit.Entries[0].TargetId)
+	assert.Equal(t, "127.0.0.1", audit.Entries[0].IP)
+	assert.Equal(t, "Go-http-client/1.1", audit.Entries[0].UserAgent)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/admin/audit?user_id=%s", author.Id), adminToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &audit)
+	// latest first: publishing comes after registering
+	require.Equal(t, 2, audit.Total)
+	assert.Equal(t, types.AuditPublishPost, audit.Entries[0].Action)
+	assert.Equal(t, types.AuditRegister, audit.Entries[1].Action)
+}
+
+func TestEndToEnd_ReaderBecomesAuthor(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", reader.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "role-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", uuid.New()), reader.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodPatch, "/auth/role", reader.AccessToken, "application/json", jsonBody(t, dto.ChangeOwnRoleRequest{
+		Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var changed dto.ChangeOwnRoleResponse
+	decode(t, resp, &changed)
+	assert.Equal(t, types.Author, changed.Role)
+
+	resp = h.Do(t, http.MethodPost, "/posts", changed.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "role-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), changed.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Hello again", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// there is no way back through this endpoint
+	resp = h.Do(t, http.MethodPatch, "/auth/role", changed.AccessToken, "application/json", jsonBody(t, dto.ChangeOwnRoleRequest{
+		Role: types.Author,
+	}))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestEndToEnd_ArchivedPostsLeaveReaderFeed(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "archive-1", Title: "Old news", Content: "Retired",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	setStatus := func(status types.PostStatus) int {
+		resp := h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+			Status: status,
+		}))
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusBadRequest, setStatus(types.Archived), "drafts cannot be archived")
+	require.Equal(t, http.StatusCreated, setStatus(types.Published))
+	require.Equal(t, http.StatusCreated, setStatus(types.Archived))
+
+	resp = h.Do(t, http.MethodGet, "/posts", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts []dto.GetPostResponse
+	decode(t, resp, &posts)
+	assert.Empty(t, posts)
+
+	resp = h.Do(t, http.MethodGet, "/posts", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, types.Archived, posts[0].Status)
+
+	require.Equal(t, http.StatusBadRequest, setStatus(types.Published), "archived posts go back through draft")
+	require.Equal(t, http.StatusCreated, setStatus(types.Draft))
+}
+
+func TestEndToEnd_ScheduledPostGoesLive(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "schedule-1", Title: "Tomorrow", Content: "Soon",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	publishAt := time.Now().Add(time.Hour)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Scheduled, PublishAt: &publishAt,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var scheduled dto.PublishPostResponse
+	decode(t, resp, &scheduled)
+	assert.Equal(t, types.Scheduled, scheduled.Status)
+
+	readerFeed := func() []dto.GetPostResponse {
+		resp := h.Do(t, http.MethodGet, "/posts", reader.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var posts []dto.GetPostResponse
+		decode(t, resp, &posts)
+		return posts
+	}
+
+	count, err := h.Server.Scheduler().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Empty(t, readerFeed())
+
+	h.Clock.Advance(2 * time.Hour)
+	count, err = h.Server.Scheduler().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	posts := readerFeed()
+	require.Len(t, posts, 1)
+	assert.Equal(t, types.Published, posts[0].Status)
+}
+
+func TestEndToEnd_TrashedPostRestoredThenPurged(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "trash-1", Title: "Oops", Content: "Deleted by mistake",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="pic.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+	require.NoError(t, writer.Close())
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), author.AccessToken, writer.FormDataContentType(), &upload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var image dto.AddImageResponse
+	decode(t, resp, &image)
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	feed := func(token string) []dto.GetPostResponse {
+		resp := h.Do(t, http.MethodGet, "/posts", token, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var posts []dto.GetPostResponse
+		decode(t, resp, &posts)
+		return posts
+	}
+	trash := func() int {
+		return h.Do(t, http.MethodDelete, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "", nil).StatusCode
+	}
+	restore := func() int {
+		return h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/restore", created.PostId), author.AccessToken, "", nil).StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, trash())
+	assert.Empty(t, feed(reader.AccessToken))
+	assert.Empty(t, feed(author.AccessToken))
+	assert.Equal(t, http.StatusNotFound, trash())
+
+	require.Equal(t, http.StatusOK, restore())
+	posts := feed(reader.AccessToken)
+	require.Len(t, posts, 1)
+	assert.Equal(t, types.Published, posts[0].Status)
+
+	require.Equal(t, http.StatusOK, trash())
+	count, err := h.Server.TrashCleaner().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	h.Clock.Advance(721 * time.Hour)
+	count, err = h.Server.TrashCleaner().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, stored := h.Storage.Object(image.ImageId.String() + ".png")
+	assert.False(t, stored)
+	assert.Equal(t, http.StatusNotFound, restore())
+}
+
+func TestEndToEnd_PostLifecycleEvents(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "events-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	// an edit without a status change reports nothing
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Hello again", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp = h.Do(t, http.MethodDelete, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, h.Events.Published())
+
+	count, err := h.Server.Outbox().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	published := h.Events.Published()
+	require.Len(t, published, 3)
+	for i, eventType := range []types.EventType{types.EventPostCreated, types.EventPostStatusChanged, types.EventPostDeleted} {
+		assert.Equal(t, string(eventType), published[i].Type)
+		assert.Equal(t, created.PostId.String(), published[i].Key)
+	}
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(published[1].Payload, &payload))
+	assert.Equal(t, "published", payload["status"])
+	assert.Equal(t, "draft", payload["previous_status"])
+
+	count, err = h.Server.Outbox().Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestEndToEnd_TaggedPostsFilterAndCount(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	publish := func(key string, tags []string) uuid.UUID {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: key, Title: key, Content: "Body", Tags: tags,
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+			Status: types.Published,
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		return created.PostId
+	}
+	goPost := publish("tags-1", []string{"GoLang", "db"})
+	publish("tags-2", []string{"golang"})
+
+	resp = h.Do(t, http.MethodGet, "/posts?tag=db", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts []dto.GetPostResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, goPost, posts[0].PostId)
+	assert.Equal(t, []string{"db", "golang"}, posts[0].Tags)
+
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", goPost), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Retagged", Content: "Body", Tags: []string{"golang"},
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/tags", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var tags []dto.TagResponse
+	decode(t, resp, &tags)
+	assert.Equal(t, []dto.TagResponse{{Name: "golang", Count: 2}}, tags)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "tags-3", Title: "Bad", Content: "Body", Tags: []string{""},
+	}))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEndToEnd_SearchPosts(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	create := func(key, title string) uuid.UUID {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: key, Title: title, Content: "Notes about Postgres",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		return created.PostId
+	}
+	published := create("search-1", "Indexes in Postgres")
+	create("search-2", "Draft about indexes")
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", published), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	search := func(query string) dto.SearchPostsResponse {
+		resp := h.Do(t, http.MethodGet, "/posts/search?"+query, author.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var page dto.SearchPostsResponse
+		decode(t, resp, &page)
+		return page
+	}
+
+	page := search("q=indexes")
+	require.Len(t, page.Posts, 1)
+	assert.Equal(t, published, page.Posts[0].PostId)
+	assert.Positive(t, page.Posts[0].Score)
+
+	page = search("q=indexes&mine=true")
+	assert.Len(t, page.Posts, 2)
+
+	resp = h.Do(t, http.MethodGet, "/posts/search?q=i", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEndToEnd_PostViewsCounted(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "views-1", Title: "Popular", Content: "Read me twice",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	view := func(token string) int {
+		return h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", created.PostId), token, "", nil).StatusCode
+	}
+	assert.Equal(t, http.StatusNotFound, view(reader.AccessToken))
+	require.Equal(t, http.StatusOK, view(author.AccessToken))
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	require.Equal(t, http.StatusOK, view(reader.AccessToken))
+	require.Equal(t, http.StatusOK, view(reader.AccessToken))
+	require.Equal(t, http.StatusOK, view(author.AccessToken))
+	h.Clock.Advance(24 * time.Hour)
+	require.Equal(t, http.StatusOK, view(reader.AccessToken))
+	h.Server.Views().Flush()
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/stats", created.PostId), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var stats dto.PostStatsResponse
+	decode(t, resp, &stats)
+	assert.Equal(t, 2, stats.Views)
+	require.Len(t, stats.Days, 2)
+	assert.Equal(t, 1, stats.Days[0].Views)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", created.PostId), reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var post dto.GetPostResponse
+	decode(t, resp, &post)
+	assert.Equal(t, 2, post.Views)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/stats", created.PostId), reader.AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestEndToEnd_ReaderLikesPosts(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "likes-1", Title: "Likeable", Content: "Worth a like",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	like := func(method, token string) (int, dto.LikeResponse) {
+		resp := h.Do(t, method, fmt.Sprintf("/posts/%s/like", created.PostId), token, "", nil)
+		var res dto.LikeResponse
+		if resp.StatusCode == http.StatusOK {
+			decode(t, resp, &res)
+		}
+		return resp.StatusCode, res
+	}
+	liked := func(token string) []dto.GetPostResponse {
+		resp := h.Do(t, http.MethodGet, "/posts?liked=true", token, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var posts []dto.GetPostResponse
+		decode(t, resp, &posts)
+		return posts
+	}
+
+	status, _ := like(http.MethodPost, reader.AccessToken)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	for range 2 {
+		status, res := like(http.MethodPost, reader.AccessToken)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, res.LikesCount)
+	}
+	status, res := like(http.MethodPost, author.AccessToken)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, res.LikesCount)
+
+	posts := liked(reader.AccessToken)
+	require.Len(t, posts, 1)
+	assert.Equal(t, created.PostId, posts[0].PostId)
+	assert.Equal(t, 2, posts[0].LikesCount)
+
+	for range 2 {
+		status, res := like(http.MethodDelete, reader.AccessToken)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, res.LikesCount)
+	}
+	assert.Empty(t, liked(reader.AccessToken))
+}
+
+// TestEndToEnd_RolesPerRoute checks the role of every route of a post is its own, a reader gets the routes
+// under /posts/{postId} and its /post/{postId} alias that are not only for authors
+func TestEndToEnd_RolesPerRoute(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "roles-1", Title: "Roles", Content: "Per route",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	edit := func() io.Reader {
+		return jsonBody(t, dto.EditPostRequest{Title: "Taken over", Content: "By a reader"})
+	}
+	for name, prefix := range map[string]string{"posts": "/posts/", "deprecated alias": "/post/"} {
+		t.Run(name, func(t *testing.T) {
+			path := prefix + created.PostId.String()
+
+			resp := h.Do(t, http.MethodGet, path+"/images", reader.AccessToken, "", nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			resp = h.Do(t, http.MethodPost, path+"/like", reader.AccessToken, "", nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			resp = h.Do(t, http.MethodDelete, path+"/like", reader.AccessToken, "", nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp = h.Do(t, http.MethodPut, path, reader.AccessToken, "application/json", edit())
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			resp = h.Do(t, http.MethodPatch, path+"/status", reader.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+				Status: types.Draft,
+			}))
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			resp = h.Do(t, http.MethodGet, path+"/stats", reader.AccessToken, "", nil)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+			resp = h.Do(t, http.MethodPut, path, "", "application/json", edit())
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "the token is still checked for the whole group")
+			resp = h.Do(t, http.MethodGet, path+"/images", "", "", nil)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+			resp = h.Do(t, http.MethodPut, path, author.AccessToken, "application/json", edit())
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		})
+	}
+}
+
+func TestEndToEnd_PostRevisions(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "other@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var other dto.RegistrateUserResponse
+	decode(t, resp, &other)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "revisions-1", Title: "First title", Content: "First draft",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Second title", Content: "Rewritten",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	revisions := func(token string) (int, dto.GetPostRevisionsResponse) {
+		resp := h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/revisions", created.PostId), token, "", nil)
+		var page dto.GetPostRevisionsResponse
+		if resp.StatusCode == http.StatusOK {
+			decode(t, resp, &page)
+		}
+		return resp.StatusCode, page
+	}
+
+	status, page := revisions(author.AccessToken)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, page.Total)
+	assert.Equal(t, "First title", page.Revisions[0].Title)
+	status, _ = revisions(other.AccessToken)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	restore := fmt.Sprintf("/posts/%s/revisions/%s/restore", created.PostId, page.Revisions[0].RevisionId)
+	resp = h.Do(t, http.MethodPost, restore, other.AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = h.Do(t, http.MethodPost, restore, author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var restored dto.EditPostResponse
+	decode(t, resp, &restored)
+	assert.Equal(t, "First title", restored.Title)
+	assert.Equal(t, "First draft", restored.Content)
+
+	status, page = revisions(author.AccessToken)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 2, page.Total)
+	assert.Equal(t, "Second title", page.Revisions[0].Title)
+}
+
+func TestEndToEnd_StaleEditConflicts(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "stale-1", Title: "Shared", Content: "Opened in two tabs",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var read dto.GetPostResponse
+	decode(t, resp, &read)
+
+	edit := func(title string) *http.Response {
+		return h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+			Title: title, Content: "Opened in two tabs", ExpectedUpdatedAt: &read.UpdatedAt,
+		}))
+	}
+	resp = edit("From tab 1")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = edit("From tab 2")
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	var current dto.EditPostResponse
+	decode(t, resp, &current)
+	assert.Equal(t, "From tab 1", current.Title)
+	assert.True(t, current.UpdatedAt.After(read.UpdatedAt))
+}
+
+func TestEndToEnd_PatchPostKeepsOmittedFields(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "patch-1", Title: "Draft", Content: "Long text", Tags: []string{"go"},
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	patch := func(body string) *http.Response {
+		return h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", strings.NewReader(body))
+	}
+	resp = patch(`{"title":"Final"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var edited dto.EditPostResponse
+	decode(t, resp, &edited)
+	assert.Equal(t, "Final", edited.Title)
+	assert.Equal(t, "Long text", edited.Content)
+	assert.Equal(t, []string{"go"}, edited.Tags)
+
+	resp = patch(`{"content":""}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	// the full edit still replaces everything
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", created.PostId), author.AccessToken, "application/json", strings.NewReader(`{"title":"Final"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_PublicReadWithoutToken(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "alice@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	create := func(key string) uuid.UUID {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: key, Title: key, Content: "Body",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		return created.PostId
+	}
+	published, draft := create("public-1"), create("public-2")
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", published), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodGet, "/public/posts", "", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+	var posts []dto.PublicPostResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, published, posts[0].PostId)
+	assert.Equal(t, "a***@example.com", posts[0].Author.DisplayName)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/public/posts/%s", published), "", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/public/posts/%s", draft), "", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	req, err := http.NewRequest(http.MethodGet, h.BaseURL+"/public/posts", nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_BrowseAuthors(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author, quiet, reader := register("author@example.com", types.Author), register("quiet@example.com", types.Author), register("reader@example.com", types.Reader)
+
+	resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "authors-1", Title: "Out", Content: "Published",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodGet, "/authors", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var authors []dto.AuthorResponse
+	decode(t, resp, &authors)
+	require.Len(t, authors, 1)
+	assert.Equal(t, "a***@example.com", authors[0].DisplayName)
+	assert.Equal(t, 1, authors[0].PostsCount)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/authors/%s/posts", authors[0].UserId), quiet.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts []dto.GetPostResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, created.PostId, posts[0].PostId)
+
+	resp = h.Do(t, http.MethodGet, "/auth/me", quiet.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var me dto.UserResponse
+	decode(t, resp, &me)
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/authors/%s/posts", me.UserId), reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &posts)
+	assert.Empty(t, posts)
+
+	for _, id := range []string{"not-a-uuid", uuid.NewString()} {
+		resp = h.Do(t, http.MethodGet, fmt.Sprintf("/authors/%s/posts", id), reader.AccessToken, "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp.Body.Close()
+	}
+}
+
+func TestEndToEnd_ProfilesAndAvatars(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author, reader := register("author@example.com", types.Author), register("reader@example.com", types.Reader)
+
+	resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "profile-1", Title: "Faces", Content: "Published",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	// listings never carry the email of an author, a masked one stands in until they pick a name
+	resp = h.Do(t, http.MethodGet, "/posts", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "author@example.com")
+	assert.Contains(t, string(body), `"display_name":"a***@example.com"`)
+
+	name := "Alice"
+	resp = h.Do(t, http.MethodPatch, "/users/me", author.AccessToken, "application/json", jsonBody(t, dto.UpdateProfileRequest{DisplayName: &name}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var profile dto.UserResponse
+	decode(t, resp, &profile)
+	assert.Equal(t, "Alice", profile.DisplayName)
+	assert.Equal(t, "author@example.com", profile.Email)
+
+	uploadAvatar := func() dto.UserResponse {
+		var upload bytes.Buffer
+		writer := multipart.NewWriter(&upload)
+		part, err := writer.CreateFormFile("avatar", "me.png")
+		require.NoError(t, err)
+		part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+		require.NoError(t, writer.Close())
+
+		resp := h.Do(t, http.MethodPost, "/users/me/avatar", author.AccessToken, writer.FormDataContentType(), &upload)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var profile dto.UserResponse
+		decode(t, resp, &profile)
+		return profile
+	}
+	first := uploadAvatar()
+	firstObject := strings.TrimPrefix(first.AvatarUrl, "/images/")
+	_, stored := h.Storage.Object(firstObject)
+	assert.True(t, stored, first.AvatarUrl)
+	second := uploadAvatar()
+	secondObject := strings.TrimPrefix(second.AvatarUrl, "/images/")
+	_, stored = h.Storage.Object(firstObject)
+	assert.False(t, stored, "the replaced avatar is removed")
+	_, stored = h.Storage.Object(secondObject)
+	assert.True(t, stored)
+
+	resp = h.Do(t, http.MethodGet, "/posts", reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts []dto.GetPostResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 1)
+	assert.Equal(t, dto.UserResponse{UserId: author.Id, DisplayName: "Alice", AvatarUrl: second.AvatarUrl}, posts[0].Author)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/public/authors/%s", author.Id), "", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var public dto.AuthorResponse
+	decode(t, resp, &public)
+	assert.Equal(t, dto.AuthorResponse{UserId: author.Id, DisplayName: "Alice", AvatarUrl: second.AvatarUrl, PostsCount: 1}, public)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/authors/%s", author.Id), reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/public/authors/%s", reader.Id), "", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_AccountExportAndDeletion(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author, reader := register("author@example.com", types.Author), register("reader@example.com", types.Reader)
+
+	resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "account-1", Title: "Kept", Content: "Outlives its author",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/like", created.PostId), author.AccessToken, "", nil)
+	require.Less(t, resp.StatusCode, 300)
+	resp.Body.Close()
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	part, err := writer.CreateFormFile("avatar", "me.png")
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+	require.NoError(t, writer.Close())
+	resp = h.Do(t, http.MethodPost, "/users/me/avatar", author.AccessToken, writer.FormDataContentType(), &upload)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var profile dto.UserResponse
+	decode(t, resp, &profile)
+	avatarObject := strings.TrimPrefix(profile.AvatarUrl, "/images/")
+
+	resp = h.Do(t, http.MethodGet, "/users/me/export", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "password")
+	var export dto.AccountExport
+	require.NoError(t, json.Unmarshal(body, &export), string(body))
+	assert.Equal(t, author.Id, export.User.UserId)
+	assert.Equal(t, "author@example.com", export.User.Email)
+	require.Len(t, export.Posts, 1)
+	assert.Equal(t, "Kept", export.Posts[0].Title)
+	require.Len(t, export.Likes, 1)
+	assert.Equal(t, created.PostId, export.Likes[0].PostId)
+
+	resp = h.Do(t, http.MethodDelete, "/users/me", author.AccessToken, "application/json", jsonBody(t, dto.DeleteAccountRequest{Password: "wrong"}))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodDelete, "/users/me", author.AccessToken, "application/json", jsonBody(t, dto.DeleteAccountRequest{Password: "Password123!"}))
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	_, stored := h.Storage.Object(avatarObject)
+	assert.False(t, stored, "the avatar is removed with the account")
+
+	resp = h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+		Email: "author@example.com", Password: "Password123!",
+	}))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "login fails like for an unknown email")
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodPost, "/auth/refresh-token", "", "application/json", jsonBody(t, dto.RefreshRequest{RefreshToken: author.RefreshToken}))
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode, "the refresh token is revoked with the account")
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodGet, "/users/me", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	// the default policy keeps the post under the deleted user
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", created.PostId), reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var post dto.GetPostResponse
+	decode(t, resp, &post)
+	assert.Equal(t, dto.UserResponse{UserId: dto.DeletedUserId, DisplayName: "Deleted user"}, post.Author)
+}
+
+func TestEndToEnd_Sessions(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var laptop dto.RegistrateUserResponse
+	decode(t, resp, &laptop)
+	login := func() dto.LoginUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+			Email: "reader@example.com", Password: "Password123!",
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var device dto.LoginUserResponse
+		decode(t, resp, &device)
+		return device
+	}
+	refresh := func(token string) int {
+		resp := h.Do(t, http.MethodPost, "/auth/refresh-token", "", "application/json", jsonBody(t, dto.RefreshRequest{RefreshToken: token}))
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	phone := login()
+
+	// both devices stay logged in
+	assert.Equal(t, http.StatusOK, refresh(laptop.RefreshToken))
+	assert.Equal(t, http.StatusOK, refresh(phone.RefreshToken))
+
+	resp = h.Do(t, http.MethodGet, "/auth/sessions", phone.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var sessions dto.GetSessionsResponse
+	decode(t, resp, &sessions)
+	require.Len(t, sessions.Sessions, 2)
+	var laptopSession uuid.UUID
+	for _, session := range sessions.Sessions {
+		assert.Equal(t, "Go-http-client/1.1", session.UserAgent)
+		if !session.Current {
+			laptopSession = session.SessionId
+		}
+	}
+	require.NotEqual(t, uuid.Nil, laptopSession, "one session is the current one")
+
+	resp = h.Do(t, http.MethodDelete, "/auth/sessions/"+laptopSession.String(), phone.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, refresh(laptop.RefreshToken), "the laptop is logged out")
+	assert.Equal(t, http.StatusOK, refresh(phone.RefreshToken))
+
+	resp = h.Do(t, http.MethodDelete, "/auth/sessions/"+laptopSession.String(), phone.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	tablet, desktop := login(), login()
+	resp = h.Do(t, http.MethodDelete, "/auth/sessions", phone.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var revoked dto.RevokeSessionsResponse
+	decode(t, resp, &revoked)
+	assert.Equal(t, 2, revoked.Revoked)
+	assert.Equal(t, http.StatusBadRequest, refresh(tablet.RefreshToken))
+	assert.Equal(t, http.StatusBadRequest, refresh(desktop.RefreshToken))
+	assert.Equal(t, http.StatusOK, refresh(phone.RefreshToken))
+
+	// another user cannot end the session
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "other@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var other dto.RegistrateUserResponse
+	decode(t, resp, &other)
+	resp = h.Do(t, http.MethodGet, "/auth/sessions", phone.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &sessions)
+	require.Len(t, sessions.Sessions, 1)
+	resp = h.Do(t, http.MethodDelete, "/auth/sessions/"+sessions.Sessions[0].SessionId.String(), other.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, refresh(phone.RefreshToken))
+}
+
+func TestEndToEnd_ApiKeys(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	createKey := func(req dto.CreateApiKeyRequest) dto.CreateApiKeyResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/api-keys", author.AccessToken, "application/json", jsonBody(t, req))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreateApiKeyResponse
+		decode(t, resp, &created)
+		return created
+	}
+	withKey := func(method, path, key string, body io.Reader) *http.Response {
+		req, err := http.NewRequest(method, h.BaseURL+path, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "ApiKey "+key)
+		resp, err := h.Client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	post := func(key, idempotencyKey string) int {
+		return withKey(http.MethodPost, "/posts", key, jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: idempotencyKey, Title: "Hello", Content: "World",
+		})).StatusCode
+	}
+
+	readKey := createKey(dto.CreateApiKeyRequest{Name: "feed"})
+	assert.Equal(t, []types.Scope{types.ScopeRead}, readKey.Scopes)
+	assert.Equal(t, http.StatusOK, withKey(http.MethodGet, "/posts", readKey.Key, nil).StatusCode)
+	assert.Equal(t, http.StatusForbidden, post(readKey.Key, "key-1"), "a read key cannot write")
+	assert.Equal(t, http.StatusForbidden, withKey(http.MethodPost, "/posts/"+uuid.NewString()+"/like", readKey.Key, nil).StatusCode)
+	assert.Equal(t, http.StatusForbidden, withKey(http.MethodPost, "/authors/"+uuid.NewString()+"/subscribe", readKey.Key, nil).StatusCode)
+
+	writeKey := createKey(dto.CreateApiKeyRequest{Name: "deploy", Scopes: []types.Scope{types.ScopeWrite}})
+	assert.Equal(t, http.StatusCreated, post(writeKey.Key, "key-2"))
+
+	// nor take over the account, whatever its scopes
+	for _, route := range [][2]string{
+		{http.MethodGet, "/users/me"},
+		{http.MethodPatch, "/users/me"},
+		{http.MethodDelete, "/users/me"},
+		{http.MethodGet, "/users/me/export"},
+		{http.MethodGet, "/users/me/quota"},
+		{http.MethodGet, "/admin/users"},
+	} {
+		resp := withKey(route[0], route[1], writeKey.Key, strings.NewReader(`{}`))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, route)
+		var body dto.ErrorResponse
+		decode(t, resp, &body)
+		assert.Equal(t, errors.ErrorHttpSessionRequired.Error(), body.Error, route)
+	}
+	assert.Equal(t, http.StatusOK, h.Do(t, http.MethodGet, "/users/me", author.AccessToken, "", nil).StatusCode)
+
+	// a leaked key cannot mint more keys
+	assert.Equal(t, http.StatusForbidden, withKey(http.MethodPost, "/auth/api-keys", writeKey.Key,
+		jsonBody(t, dto.CreateApiKeyRequest{Name: "more"})).StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/auth/api-keys", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var keys dto.GetApiKeysResponse
+	decode(t, resp, &keys)
+	require.Len(t, keys.ApiKeys, 2)
+	for _, key := range keys.ApiKeys {
+		assert.NotNil(t, key.LastUsedAt, "both keys were used")
+	}
+
+	resp = h.Do(t, http.MethodDelete, "/auth/api-keys/"+readKey.KeyId.String(), author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, withKey(http.MethodGet, "/posts", readKey.Key, nil).StatusCode, "revoked")
+	assert.Equal(t, http.StatusOK, withKey(http.MethodGet, "/posts", writeKey.Key, nil).StatusCode)
+
+	// readers get no keys
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+	resp = h.Do(t, http.MethodPost, "/auth/api-keys", reader.AccessToken, "application/json", jsonBody(t, dto.CreateApiKeyRequest{Name: "feed"}))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_EmailVerification(t *testing.T) {
+	h := harness.New(t, func(cfg *servers.HttpServerConfig) { cfg.RequireVerifiedEmail = true })
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	var mail harness.Mail
+	select {
+	case mail = <-h.Mailer.Mails:
+	case <-time.After(time.Second):
+		t.Fatal("no verification mail")
+	}
+	assert.Equal(t, "author@example.com", mail.To)
+	_, link, ok := strings.Cut(mail.Body, "http://localhost/api")
+	require.True(t, ok, mail.Body)
+	link = strings.TrimSpace(link)
+
+	// drafts are fine, publishing waits for the email
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "verify-1", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	publish := func() *http.Response {
+		return h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+			Status: types.Published,
+		}))
+	}
+	resp = publish()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	var refused dto.ErrorResponse
+	decode(t, resp, &refused)
+	assert.Equal(t, dto.CodeEmailNotVerified, refused.Code)
+
+	resp = h.Do(t, http.MethodPost, "/auth/verify-email/resend", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "the registration mail was just sent")
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodGet, "/auth/verify-email?token=made-up", "", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodGet, link, "", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodGet, "/auth/me", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var me dto.UserResponse
+	decode(t, resp, &me)
+	assert.True(t, me.EmailVerified)
+
+	resp = publish()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+	resp = h.Do(t, http.MethodPost, "/auth/verify-email/resend", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_ListingSortAndStatus(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	var ids []uuid.UUID
+	for _, title := range []string{"Beta", "Alpha", "Gamma"} {
+		resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: "sort-" + title, Title: title, Content: "Body",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		ids = append(ids, created.PostId)
+	}
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", ids[2]), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	titles := func(query string) []string {
+		resp := h.Do(t, http.MethodGet, "/posts"+query, author.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var posts []dto.GetPostResponse
+		decode(t, resp, &posts)
+		res := make([]string, len(posts))
+		for i, post := range posts {
+			res[i] = post.Title
+		}
+		return res
+	}
+	assert.Equal(t, []string{"Alpha", "Beta", "Gamma"}, titles("?sort=title&order=asc"))
+	assert.Equal(t, []string{"Beta", "Alpha"}, titles("?sort=title&order=desc&status=draft"))
+
+	resp = h.Do(t, http.MethodGet, "/posts?sort=author", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_ListPostImages(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author, reader := register("author@example.com", types.Author), register("reader@example.com", types.Reader)
+
+	resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "images-1", Title: "Pictures", Content: "Two of them",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	var uploaded []uuid.UUID
+	for _, name := range []string{"first.png", "second.png"} {
+		var upload bytes.Buffer
+		writer := multipart.NewWriter(&upload)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="image"; filename="`+name+`"`)
+		header.Set("Content-Type", "image/png")
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+		require.NoError(t, writer.Close())
+
+		resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), author.AccessToken, writer.FormDataContentType(), &upload)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var image dto.AddImageResponse
+		decode(t, resp, &image)
+		uploaded = append(uploaded, image.ImageId)
+	}
+
+	path := fmt.Sprintf("/posts/%s/images", created.PostId)
+	resp = h.Do(t, http.MethodGet, path, author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var images dto.GetPostImagesResponse
+	decode(t, resp, &images)
+	require.Len(t, images.Images, 2)
+	assert.Equal(t, uploaded[0], images.Images[0].ImageId)
+	assert.Equal(t, uploaded[1], images.Images[1].ImageId)
+	assert.False(t, images.Images[0].CreatedAt.IsZero())
+
+	// the draft stays hidden from readers until it is published
+	resp = h.Do(t, http.MethodGet, path, reader.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+		Status: types.Published,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodGet, path, reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &images)
+	assert.Len(t, images.Images, 2)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/images", uuid.New()), reader.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_OrderPostImages(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	createPost := func(key string) uuid.UUID {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: key, Title: "Gallery", Content: "Pictures",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		return created.PostId
+	}
+	upload := func(postId uuid.UUID) uuid.UUID {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="image"; filename="image.png"`)
+		header.Set("Content-Type", "image/png")
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+		require.NoError(t, writer.Close())
+
+		resp := h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", postId), author.AccessToken, writer.FormDataContentType(), &body)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var image dto.AddImageResponse
+		decode(t, resp, &image)
+		return image.ImageId
+	}
+	postId, otherId := createPost("gallery-1"), createPost("gallery-2")
+	first, second, third := upload(postId), upload(postId), upload(postId)
+	foreign := upload(otherId)
+	path := fmt.Sprintf("/posts/%s/images", postId)
+	order := func() []uuid.UUID {
+		resp := h.Do(t, http.MethodGet, path, author.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var images dto.GetPostImagesResponse
+		decode(t, resp, &images)
+		ids := make([]uuid.UUID, len(images.Images))
+		for i, image := range images.Images {
+			ids[i] = image.ImageId
+			assert.Equal(t, i+1, image.Position)
+		}
+		return ids
+	}
+	assert.Equal(t, []uuid.UUID{first, second, third}, order(), "images are added last")
+
+	resp = h.Do(t, http.MethodPut, path+"/order", author.AccessToken, "application/json",
+		jsonBody(t, dto.ReorderImagesRequest{ImageIds: []uuid.UUID{third, first, second}}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, []uuid.UUID{third, first, second}, order())
+
+	for name, ids := range map[string][]uuid.UUID{
+		"missing": {first, second},
+		"foreign": {first, second, foreign},
+		"twice":   {first, second, second},
+	} {
+		resp = h.Do(t, http.MethodPut, path+"/order", author.AccessToken, "application/json", jsonBody(t, dto.ReorderImagesRequest{ImageIds: ids}))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name)
+		resp.Body.Close()
+	}
+	assert.Equal(t, []uuid.UUID{third, first, second}, order(), "a rejected order changes nothing")
+
+	position, caption := 1, "The second one"
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("%s/%s", path, second), author.AccessToken, "application/json",
+		jsonBody(t, dto.UpdateImageRequest{Caption: &caption, Position: &position}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var image dto.ImageResponse
+	decode(t, resp, &image)
+	assert.Equal(t, caption, image.Caption)
+	assert.Equal(t, []uuid.UUID{second, third, first}, order())
+
+	resp = h.Do(t, http.MethodGet, "/posts/"+postId.String(), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var post dto.GetPostResponse
+	decode(t, resp, &post)
+	require.Len(t, post.Images, 3)
+	assert.Equal(t, second, post.Images[0].ImageId, "the post lists its images in order")
+	assert.Equal(t, caption, post.Images[0].Caption)
+
+	long := strings.Repeat("x", 501)
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("%s/%s", path, second), author.AccessToken, "application/json",
+		jsonBody(t, dto.UpdateImageRequest{Caption: &long}))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp = h.Do(t, http.MethodPatch, fmt.Sprintf("%s/%s", path, foreign), author.AccessToken, "application/json",
+		jsonBody(t, dto.UpdateImageRequest{Caption: &caption}))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEndToEnd_CleanupOrphanedImages(t *testing.T) {
+	h := harness.New(t)
+
+	_, err := service.NewAdminService(h.Repository, nil, nil, nil).CreateAdmin(context.Background(), "root@example.com", "Password123!")
+	require.NoError(t, err)
+	resp := h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+		Email: "root@example.com", Password: "Password123!",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var login dto.LoginUserResponse
+	decode(t, resp, &login)
+
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "orphans-1", Title: "Pictures", Content: "One will be lost",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	var images []dto.AddImageResponse
+	for range 2 {
+		var upload bytes.Buffer
+		writer := multipart.NewWriter(&upload)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="image"; filename="pic.png"`)
+		header.Set("Content-Type", "image/png")
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+		require.NoError(t, writer.Close())
+
+		resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), author.AccessToken, writer.FormDataContentType(), &upload)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var image dto.AddImageResponse
+		decode(t, resp, &image)
+		images = append(images, image)
+	}
+	kept, lost := images[0].ImageId.String()+".png", images[1].ImageId.String()+".png"
+
+	// the row goes away behind the back of the api, the object stays
+	_, err = h.Repository.DeleteImage(context.Background(), images[1].ImageId)
+	require.NoError(t, err)
+
+	resp = h.Do(t, http.MethodPost, "/admin/maintenance/cleanup-images", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	cleanup := func(query string) dto.CleanupImagesResponse {
+		resp := h.Do(t, http.MethodPost, "/admin/maintenance/cleanup-images"+query, login.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var res dto.CleanupImagesResponse
+		decode(t, resp, &res)
+		return res
+	}
+
+	// an upload that just happened may still be waiting for its row
+	res := cleanup("?dry_run=true")
+	assert.Equal(t, 2, res.Scanned)
+	assert.Empty(t, res.Orphans)
+
+	h.Clock.Advance(25 * time.Hour)
+	res = cleanup("?dry_run=true")
+	assert.True(t, res.DryRun)
+	require.Len(t, res.Orphans, 1)
+	assert.Equal(t, lost, res.Orphans[0].Name)
+	_, ok := h.Storage.Object(lost)
+	assert.True(t, ok, "a dry run removes nothing")
+
+	res = cleanup("")
+	assert.False(t, res.DryRun)
+	require.Len(t, res.Orphans, 1)
+	_, ok = h.Storage.Object(lost)
+	assert.False(t, ok)
+	_, ok = h.Storage.Object(kept)
+	assert.True(t, ok)
+}
+
+func TestHttpServerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  time.Duration
+		refresh time.Duration
+		wantErr bool
+	}{
+		{"defaults", 2 * time.Hour, 7 * 24 * time.Hour, false},
+		{"refresh equal to access", time.Hour, time.Hour, true},
+		{"refresh shorter than access", time.Hour, time.Minute, true},
+		{"no access lifetime", 0, time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := servers.HttpServerConfig{AccessTTL: tt.access, RefreshTTL: tt.refresh, AccountDeletionPolicy: types.DeletionAnonymize}
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHttpServerConfig_ValidateTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *servers.HttpServerConfig)
+		wantErr bool
+	}{
+		{"plain", func(cfg *servers.HttpServerConfig) {}, false},
+		{"static certificate with redirect", func(cfg *servers.HttpServerConfig) {
+			cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSRedirect = "cert.pem", "key.pem", true
+		}, false},
+		{"autocert", func(cfg *servers.HttpServerConfig) { cfg.AutocertDomains = []string{"blog.example.com"} }, false},
+		{"certificate without key", func(cfg *servers.HttpServerConfig) { cfg.TLSCertFile = "cert.pem" }, true},
+		{"key without certificate", func(cfg *servers.HttpServerConfig) { cfg.TLSKeyFile = "key.pem" }, true},
+		{"certificate and autocert", func(cfg *servers.HttpServerConfig) {
+			cfg.TLSCertFile, cfg.TLSKeyFile = "cert.pem", "key.pem"
+			cfg.AutocertDomains = []string{"blog.example.com"}
+		}, true},
+		{"redirect without TLS", func(cfg *servers.HttpServerConfig) { cfg.TLSRedirect = true }, true},
+		{"unknown deletion policy", func(cfg *servers.HttpServerConfig) { cfg.AccountDeletionPolicy = "keep" }, true},
+		{"docs credentials", func(cfg *servers.HttpServerConfig) { cfg.DocsUser, cfg.DocsPassword = "docs", "docs-password" }, false},
+		{"docs user without password", func(cfg *servers.HttpServerConfig) { cfg.DocsUser = "docs" }, true},
+		{"docs base url", func(cfg *servers.HttpServerConfig) { cfg.DocsBaseURL = "https://blog.example.com" }, false},
+		{"docs base url without scheme", func(cfg *servers.HttpServerConfig) { cfg.DocsBaseURL = "blog.example.com" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEndToEnd_PostRoutesAndAliases(t *testing.T) {
+	h := harness.New(t)
+
+	tokens := map[types.Role]string{}
+	for _, role := range []types.Role{types.Author, types.Reader} {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: string(role) + "@example.com", Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var registered dto.RegistrateUserResponse
+		decode(t, resp, &registered)
+		tokens[role] = registered.AccessToken
+	}
+
+	// the post may be gone or in any state, only whether the route was found matters
+	postId := uuid.New()
+	routes := []struct {
+		method     string
+		path       string
+		authorOnly bool
+	}{
+		{http.MethodGet, "/images", false},
+		{http.MethodPost, "/like", false},
+		{http.MethodDelete, "/like", false},
+		{http.MethodPost, "/images", true},
+		{http.MethodPut, "", true},
+		{http.MethodPatch, "", true},
+		{http.MethodDelete, "/images/" + uuid.NewString(), true},
+		{http.MethodPatch, "/images/" + uuid.NewString(), true},
+		{http.MethodPut, "/images/order", true},
+		{http.MethodPut, "/images/" + uuid.NewString(), true},
+		{http.MethodPatch, "/status", true},
+		{http.MethodDelete, "", true},
+		{http.MethodPost, "/restore", true},
+		{http.MethodGet, "/stats", true},
+		{http.MethodGet, "/revisions", true},
+		{http.MethodPost, "/revisions/" + uuid.NewString() + "/restore", true},
+	}
+
+	for _, route := range routes {
+		for _, prefix := range []string{"/posts/", "/post/"} {
+			path := prefix + postId.String() + route.path
+			t.Run(route.method+" "+path, func(t *testing.T) {
+				resp := h.Do(t, route.method, path, tokens[types.Author], "application/json", strings.NewReader("{}"))
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.NotEqual(t, http.StatusMethodNotAllowed, resp.StatusCode)
+				assert.NotEqual(t, "404 page not found\n", string(body), "route must exist")
+
+				if prefix == "/post/" {
+					assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+					assert.Equal(t, fmt.Sprintf(`</api/v1/posts/%s%s>; rel="successor-version"`, postId, route.path), resp.Header.Get("Link"))
+				} else {
+					assert.Empty(t, resp.Header.Get("Deprecation"))
+				}
+
+				resp = h.Do(t, route.method, path, tokens[types.Reader], "application/json", strings.NewReader("{}"))
+				if route.authorOnly {
+					assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				} else {
+					assert.NotEqual(t, http.StatusForbidden, resp.StatusCode)
+				}
+
+				resp = h.Do(t, route.method, path, "", "application/json", strings.NewReader("{}"))
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			})
+		}
+	}
+}
+
+func TestEndToEnd_BodyLimits(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "limits-1", Title: "Limits", Content: "Big bodies",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	imagesPath := fmt.Sprintf("/posts/%s/images", created.PostId)
+
+	image := func(size int) (string, []byte) {
+		var upload bytes.Buffer
+		writer := multipart.NewWriter(&upload)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="image"; filename="big.png"`)
+		header.Set("Content-Type", "image/png")
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		part.Write([]byte("\x89PNG\r\n\x1a\n"))
+		part.Write(bytes.Repeat([]byte{0}, size))
+		require.NoError(t, writer.Close())
+		return writer.FormDataContentType(), upload.Bytes()
+	}
+	hugeJSON := []byte(`{"idempotency_key":"limits-2","title":"t","content":"` + strings.Repeat("a", 2<<20) + `"}`)
+	imageType, hugeImage := image(26 << 20)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+		// streamed bodies have no Content-Length, the handler runs and hits the limit while reading
+		streamed bool
+	}{
+		{"declared json", "/posts", "application/json", hugeJSON, false},
+		{"streamed json", "/posts", "application/json", hugeJSON, true},
+		{"streamed json to auth", "/auth/login", "application/json", hugeJSON, true},
+		{"declared upload", imagesPath, imageType, hugeImage, false},
+		{"streamed upload", imagesPath, imageType, hugeImage, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = bytes.NewReader(tt.body)
+			if tt.streamed {
+				body = struct{ io.Reader }{body}
+			}
+			resp := h.Do(t, http.MethodPost, tt.path, author.AccessToken, tt.contentType, body)
+			assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			var apiErr dto.ErrorResponse
+			decode(t, resp, &apiErr)
+			assert.Equal(t, "request body too large", apiErr.Error)
+
+			// the same client goes on working
+			resp = h.Do(t, http.MethodGet, "/posts/"+created.PostId.String(), author.AccessToken, "", nil)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	// images are held to their own limit, not the one of JSON bodies
+	contentType, body := image(2 << 20)
+	resp = h.Do(t, http.MethodPost, imagesPath, author.AccessToken, contentType, bytes.NewReader(body))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestEndToEnd_ContentTypes(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "types-1", Title: "Types", Content: "Content types",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	postPath := "/posts/" + created.PostId.String()
+
+	login := `{"email":"author@example.com","password":"Password123!"}`
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"login as text", http.MethodPost, "/auth/login", "text/plain", login, http.StatusUnsupportedMediaType},
+		{"login without type", http.MethodPost, "/auth/login", "", login, http.StatusUnsupportedMediaType},
+		{"login with charset", http.MethodPost, "/auth/login", "application/json; charset=utf-8", login, http.StatusOK},
+		{"create post as form", http.MethodPost, "/posts", "application/x-www-form-urlencoded", `{"idempotency_key":"types-2","title":"t","content":"c"}`, http.StatusUnsupportedMediaType},
+		{"edit post as multipart", http.MethodPut, postPath, "multipart/form-data; boundary=x", `{"title":"t","content":"c"}`, http.StatusUnsupportedMediaType},
+		{"deprecated alias", http.MethodPatch, "/post/" + created.PostId.String(), "text/plain", `{"title":"t"}`, http.StatusUnsupportedMediaType},
+		{"upload as json", http.MethodPost, postPath + "/images", "application/json", `{"image":"x"}`, http.StatusUnsupportedMediaType},
+		{"upload without type", http.MethodPost, postPath + "/images", "", "--x--", http.StatusUnsupportedMediaType},
+		{"like without body", http.MethodPost, postPath + "/like", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			resp := h.Do(t, tt.method, tt.path, author.AccessToken, tt.contentType, body)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != http.StatusUnsupportedMediaType {
+				return
+			}
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			var apiErr dto.ErrorResponse
+			decode(t, resp, &apiErr)
+			assert.Equal(t, "unsupported content type", apiErr.Error)
+		})
+	}
+}
+
+func TestEndToEnd_GzipResponses(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	content := strings.Repeat("A long and very compressible post. ", 500)
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "gzip-1", Title: "Long", Content: content,
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	// the transport would ask for gzip itself and hide the encoding
+	client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{DisableCompression: true}}
+	defer client.CloseIdleConnections()
+	get := func(path, acceptEncoding string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, h.BaseURL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+author.AccessToken)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp = get("/posts/"+created.PostId.String(), "gzip")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	var post dto.GetPostResponse
+	require.NoError(t, json.NewDecoder(body).Decode(&post))
+	resp.Body.Close()
+	assert.Equal(t, content, post.Content)
+
+	resp = get("/posts/"+created.PostId.String(), "")
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	post = dto.GetPostResponse{}
+	decode(t, resp, &post)
+	resp.Body.Close()
+	assert.Equal(t, content, post.Content)
+
+	// too short to be worth it
+	resp = get("/tags", "gzip")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	resp.Body.Close()
+}
+
+func TestEndToEnd_ConditionalPostReads(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "etag-1", Title: "Polled", Content: "Often",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	get := func(path, ifNoneMatch string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, h.BaseURL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+author.AccessToken)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := h.Client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	for _, path := range []string{"/posts", "/posts/" + created.PostId.String()} {
+		t.Run(path, func(t *testing.T) {
+			resp := get(path, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			etag := resp.Header.Get("ETag")
+			require.NotEmpty(t, etag)
+
+			resp = get(path, etag)
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+			content := "Often, edited for " + path
+			resp = h.Do(t, http.MethodPatch, "/posts/"+created.PostId.String(), author.AccessToken, "application/json", jsonBody(t, dto.PatchPostRequest{Content: &content}))
+			resp.Body.Close()
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+			resp = get(path, etag)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+		})
+	}
+}
+
+func TestEndToEnd_APIVersionPrefixes(t *testing.T) {
+	h := harness.New(t)
+	base := strings.TrimSuffix(h.BaseURL, "/v1")
+	require.True(t, strings.HasSuffix(base, "/api"))
+
+	do := func(method, url, token string, body io.Reader) *http.Response {
+		req, err := http.NewRequest(method, url, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := h.Client.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// registered through one prefix, logged in through the other
+	resp := do(http.MethodPost, base+"/v1/auth/register", "", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	resp = do(http.MethodPost, base+"/auth/login", "", jsonBody(t, dto.LoginUserRequest{
+		Email: "author@example.com", Password: "Password123!",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var login dto.LoginUserResponse
+	decode(t, resp, &login)
+
+	resp = do(http.MethodPost, base+"/posts", login.AccessToken, jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "version-1", Title: "Versioned", Content: "Both ways",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	for _, path := range []string{"/posts", "/posts/" + created.PostId.String(), "/tags", "/auth/me"} {
+		t.Run(path, func(t *testing.T) {
+			versioned := do(http.MethodGet, base+"/v1"+path, login.AccessToken, nil)
+			alias := do(http.MethodGet, base+path, login.AccessToken, nil)
+			defer versioned.Body.Close()
+			defer alias.Body.Close()
+			versionedBody, err := io.ReadAll(versioned.Body)
+			require.NoError(t, err)
+			aliasBody, err := io.ReadAll(alias.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, versioned.StatusCode)
+			assert.Equal(t, versioned.StatusCode, alias.StatusCode)
+			assert.JSONEq(t, string(versionedBody), string(aliasBody))
+		})
+	}
+
+	for _, path := range []string{"/v2/posts", "/v0/auth/login", "/v10"} {
+		resp := do(http.MethodGet, base+path, login.AccessToken, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		var apiErr dto.ErrorResponse
+		decode(t, resp, &apiErr)
+		resp.Body.Close()
+		assert.Equal(t, "unknown api version", apiErr.Error, path)
+	}
+}
+
+func TestEndToEnd_TracesAddImage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	// the repositories start their spans from the global provider
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	var stored []string
+	objects := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		stored = append(stored, r.URL.Path)
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	}))
+	defer objects.Close()
+	client, err := minIO.New(strings.TrimPrefix(objects.URL, "http://"), &minIO.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	cfg := testConfig()
+	server := servers.NewHttpServer(cfg, servers.HttpServerOptions{
+		DB:             &postgres.DB{DB: sqlx.NewDb(db, "postgres")},
+		ImageStorage:   repository.NewMinIORepository(&minio.MinIOClient{Client: client, BucketName: "images", Public: true, MaxAttempts: 1}),
+		Listener:       listen(t),
+		TracerProvider: provider,
+	})
+	serve(t, server)
+
+	authorId, postId, imageId := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectQuery(`SELECT \* FROM users WHERE user_id`).WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "role"}).AddRow(authorId, "author@example.com", types.Author))
+	mock.ExpectQuery(`FROM posts p WHERE p.post_id`).WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id", "status"}).AddRow(postId, authorId, types.Draft))
+	mock.ExpectQuery(`INSERT INTO images`).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "post_id", "image_url"}).AddRow(imageId, postId, "/images/"+imageId.String()+".png"))
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="pic.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\nfake"))
+	require.NoError(t, writer.Close())
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/api/v1/posts/%s/images", server.Addr(), postId), &upload)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+jwt.NewAccessToken(authorId, jwt.Keys{Primary: cfg.Secret}, time.Hour, jwt.Options{}))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, stored, 1)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	// the request span ends after the response went out
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		_, ok := spans["POST /api/v1"]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	request := spans["POST /api/v1"]
+	assert.Equal(t, trace.SpanKindServer, request.SpanKind())
+	assert.False(t, request.Parent().IsValid())
+	for _, name := range []string{"GetPostById", "PutObject", "CreateImage"} {
+		span, ok := spans[name]
+		require.True(t, ok, name)
+		assert.Equal(t, request.SpanContext().TraceID(), span.SpanContext().TraceID(), name)
+		assert.Equal(t, request.SpanContext().SpanID(), span.Parent().SpanID(), name)
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind(), name)
+	}
+	assert.True(t, spans["GetPostById"].EndTime().Before(spans["PutObject"].StartTime()))
+	assert.True(t, spans["PutObject"].EndTime().Before(spans["CreateImage"].StartTime()))
+}
+
+func TestEndToEnd_UploadPostImages(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "gallery", Title: "Gallery", Content: "Pictures",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	path := fmt.Sprintf("/posts/%s/images", created.PostId)
+
+	upload := func(contents ...string) (*http.Response, dto.AddImagesResponse) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for i, content := range contents {
+			part, err := writer.CreateFormFile("images", fmt.Sprintf("%d.png", i))
+			require.NoError(t, err)
+			part.Write([]byte(content))
+		}
+		require.NoError(t, writer.Close())
+
+		resp := h.Do(t, http.MethodPost, path, author.AccessToken, writer.FormDataContentType(), &body)
+		var results dto.AddImagesResponse
+		if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusMultiStatus {
+			decode(t, resp, &results)
+		}
+		return resp, results
+	}
+	png := "\x89PNG\r\n\x1a\nfake"
+
+	resp, results := upload(png, png)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Len(t, results, 2)
+	added := []uuid.UUID{*results[0].ImageId, *results[1].ImageId}
+
+	// the post takes four images, the text file is refused and the last image does not fit
+	resp, results = upload(png, "just text", png, png)
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	require.Len(t, results, 4)
+	assert.Equal(t, "0.png", results[0].Filename)
+	require.NotNil(t, results[0].ImageId)
+	assert.Equal(t, errors.ErrorHttpUnsupportedImage.Error(), results[1].Error)
+	require.NotNil(t, results[2].ImageId)
+	assert.Nil(t, results[3].ImageId)
+	assert.Equal(t, errors.ErrorHttpImageLimit.Error(), results[3].Error)
+	added = append(added, *results[0].ImageId, *results[2].ImageId)
+
+	resp = h.Do(t, http.MethodGet, path, author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var images dto.GetPostImagesResponse
+	decode(t, resp, &images)
+	ids := make([]uuid.UUID, len(images.Images))
+	for i, image := range images.Images {
+		ids[i] = image.ImageId
+	}
+	assert.Equal(t, added, ids, "images keep the order they were sent in")
+
+	resp, _ = upload(png)
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+}
+
+func TestEndToEnd_StorageQuota(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\nfake"
+	quota := int64(2 * len(png))
+	h := harness.New(t, func(cfg *servers.HttpServerConfig) { cfg.StorageQuotaBytes = quota })
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "gallery", Title: "Gallery", Content: "Pictures",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+	path := fmt.Sprintf("/posts/%s/images", created.PostId)
+
+	upload := func(content string) *http.Response {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("image", "image.png")
+		require.NoError(t, err)
+		part.Write([]byte(content))
+		require.NoError(t, writer.Close())
+		return h.Do(t, http.MethodPost, path, author.AccessToken, writer.FormDataContentType(), &body)
+	}
+	usage := func() dto.StorageQuotaResponse {
+		resp := h.Do(t, http.MethodGet, "/users/me/quota", author.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var quota dto.StorageQuotaResponse
+		decode(t, resp, &quota)
+		return quota
+	}
+
+	assert.Equal(t, dto.StorageQuotaResponse{LimitBytes: quota}, usage())
+	resp = upload(png)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var first dto.AddImageResponse
+	decode(t, resp, &first)
+	require.Equal(t, http.StatusCreated, upload(png).StatusCode)
+	assert.Equal(t, dto.StorageQuotaResponse{UsedBytes: quota, LimitBytes: quota}, usage())
+
+	resp = upload(png)
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	var refused dto.QuotaExceededResponse
+	decode(t, resp, &refused)
+	assert.Equal(t, dto.QuotaExceededResponse{
+		Error: errors.ErrorHttpQuotaExceeded.Error(), Code: dto.CodeQuotaExceeded, UsedBytes: quota, LimitBytes: quota,
+	}, refused)
+
+	resp = h.Do(t, http.MethodDelete, fmt.Sprintf("%s/%s", path, first.ImageId), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, dto.StorageQuotaResponse{UsedBytes: quota / 2, LimitBytes: quota}, usage())
+	assert.Equal(t, http.StatusCreated, upload(png).StatusCode)
+}
+
+func TestEndToEnd_ReplacePostImage(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "screenshot", Title: "Screenshot", Content: "Look",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	form := func(content string) (string, *bytes.Buffer) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("image", "screenshot.png")
+		require.NoError(t, err)
+		part.Write([]byte(content))
+		require.NoError(t, writer.Close())
+		return writer.FormDataContentType(), &body
+	}
+	contentType, body := form("\x89PNG\r\n\x1a\ntypo")
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", created.PostId), author.AccessToken, contentType, body)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var added dto.AddImageResponse
+	decode(t, resp, &added)
+	path := fmt.Sprintf("/posts/%s/images/%s", created.PostId, added.ImageId)
+	stored := func(name string) string {
+		file, _, err := h.Storage.GetImage(name)
+		if err != nil {
+			return ""
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	contentType, body = form("\x89PNG\r\n\x1a\nfixed")
+	resp = h.Do(t, http.MethodPut, path, author.AccessToken, contentType, body)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var replaced dto.ImageResponse
+	decode(t, resp, &replaced)
+	assert.Equal(t, added.ImageId, replaced.ImageId)
+	assert.Equal(t, 1, replaced.Position)
+	assert.True(t, strings.HasPrefix(replaced.ImageUrl, added.ImageUrl+"?v="), replaced.ImageUrl)
+	assert.Equal(t, "\x89PNG\r\n\x1a\nfixed", stored(added.ImageId.String()+".png"))
+
+	contentType, body = form("GIF89a now a gif")
+	resp = h.Do(t, http.MethodPut, path, author.AccessToken, contentType, body)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &replaced)
+	assert.Contains(t, replaced.ImageUrl, added.ImageId.String()+".gif?v=")
+	assert.Equal(t, "GIF89a now a gif", stored(added.ImageId.String()+".gif"))
+	assert.Empty(t, stored(added.ImageId.String()+".png"), "the old object is removed")
+
+	contentType, body = form("just text")
+	resp = h.Do(t, http.MethodPut, path, author.AccessToken, contentType, body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/images", created.PostId), author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var images dto.GetPostImagesResponse
+	decode(t, resp, &images)
+	require.Len(t, images.Images, 1)
+	assert.Equal(t, replaced.ImageUrl, images.Images[0].ImageUrl)
+}
+
+func TestEndToEnd_ExportPosts(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author := register("author@example.com", types.Author)
+	other := register("other@example.com", types.Author)
+	reader := register("reader@example.com", types.Reader)
+
+	create := func(token, title string) dto.CreatePostResponse {
+		resp := h.Do(t, http.MethodPost, "/posts", token, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: title, Title: title, Content: "About " + title,
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		return created
+	}
+	gallery := create(author.AccessToken, "Gallery")
+	create(author.AccessToken, "Notes")
+	create(other.AccessToken, "Not mine")
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	part, err := writer.CreateFormFile("image", "cat.png")
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\ncat"))
+	require.NoError(t, writer.Close())
+	resp := h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", gallery.PostId), author.AccessToken, writer.FormDataContentType(), &upload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/posts/export", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), ".json")
+	var export dto.PostsExport
+	decode(t, resp, &export)
+	assert.Equal(t, author.Id, export.AuthorId)
+	require.Len(t, export.Posts, 2)
+	assert.Equal(t, "Gallery", export.Posts[0].Title)
+	require.Len(t, export.Posts[0].Images, 1)
+	assert.Equal(t, "Notes", export.Posts[1].Title)
+
+	resp = h.Do(t, http.MethodGet, "/posts/export?format=zip", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var indexes, images int
+	for _, file := range archive.File {
+		assert.NotContains(t, file.Name, "not-mine", "posts of other authors are never exported")
+		switch {
+		case strings.HasSuffix(file.Name, "/index.md"):
+			indexes++
+		case strings.Contains(file.Name, "/images/"):
+			images++
+		}
+	}
+	assert.Equal(t, 2, indexes)
+	assert.Equal(t, 1, images)
+
+	resp = h.Do(t, http.MethodGet, "/posts/export?format=tar", author.AccessToken, "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = h.Do(t, http.MethodGet, "/posts/export", reader.AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestEndToEnd_ImportPosts(t *testing.T) {
+	h := harness.New(t)
+
+	register := func(email string, role types.Role) dto.RegistrateUserResponse {
+		resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: role,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var user dto.RegistrateUserResponse
+		decode(t, resp, &user)
+		return user
+	}
+	author := register("author@example.com", types.Author)
+	other := register("other@example.com", types.Author)
+	reader := register("reader@example.com", types.Reader)
+
+	resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "gallery", Title: "Gallery", Content: "Pictures", Tags: []string{"cats"},
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var gallery dto.CreatePostResponse
+	decode(t, resp, &gallery)
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "notes", Title: "Notes", Content: "Words",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	part, err := writer.CreateFormFile("image", "cat.png")
+	require.NoError(t, err)
+	part.Write([]byte("\x89PNG\r\n\x1a\ncat"))
+	require.NoError(t, writer.Close())
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/images", gallery.PostId), author.AccessToken, writer.FormDataContentType(), &upload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var image dto.AddImageResponse
+	decode(t, resp, &image)
+	resp = h.Do(t, http.MethodPut, fmt.Sprintf("/posts/%s", gallery.PostId), author.AccessToken, "application/json", jsonBody(t, dto.EditPostRequest{
+		Title: "Gallery", Content: "![cat](" + image.ImageUrl + ")",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/posts/export?format=zip", author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	archive, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	importFile := func(token string, data []byte) *http.Response {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "posts.zip")
+		require.NoError(t, err)
+		part.Write(data)
+		require.NoError(t, writer.Close())
+		return h.Do(t, http.MethodPost, "/posts/import", token, writer.FormDataContentType(), &body)
+	}
+
+	resp = importFile(other.AccessToken, archive)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var summary dto.ImportPostsResponse
+	decode(t, resp, &summary)
+	assert.Equal(t, dto.ImportPostsResponse{Imported: 2, Errors: []dto.ImportError{}}, summary)
+
+	resp = h.Do(t, http.MethodGet, "/posts?full=true", other.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var posts dto.GetPostsResponse
+	decode(t, resp, &posts)
+	require.Len(t, posts, 2)
+	var copied *dto.GetPostResponse
+	for _, post := range posts {
+		assert.Equal(t, other.Id, post.Author.UserId)
+		assert.Equal(t, types.Draft, post.Status)
+		if post.Title == "Gallery" {
+			copied = post
+		}
+	}
+	require.NotNil(t, copied)
+	assert.Equal(t, []string{"cats"}, copied.Tags)
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s/images", copied.PostId), other.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var images dto.GetPostImagesResponse
+	decode(t, resp, &images)
+	require.Len(t, images.Images, 1)
+	assert.NotEqual(t, image.ImageId, images.Images[0].ImageId)
+	assert.Equal(t, "![cat]("+images.Images[0].ImageUrl+")", copied.Content, "the content links the copied image")
+
+	resp = importFile(other.AccessToken, archive)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &summary)
+	assert.Equal(t, 0, summary.Imported)
+	assert.Equal(t, 2, summary.Skipped)
+
+	resp = importFile(other.AccessToken, []byte("not an export"))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = importFile(reader.AccessToken, archive)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = importFile(other.AccessToken, bytes.Repeat([]byte("x"), 9<<20))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestEndToEnd_TrendingPosts(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "reader@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reader dto.RegistrateUserResponse
+	decode(t, resp, &reader)
+
+	var ids []uuid.UUID
+	for _, title := range []string{"Older", "Newer", "Unpublished"} {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: "trending-" + title, Title: title, Content: title + " content",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		ids = append(ids, created.PostId)
+		if title != "Unpublished" {
+			resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+				Status: types.Published,
+			}))
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+		h.Clock.Advance(time.Minute)
+	}
+	trending := func() []uuid.UUID {
+		resp := h.Do(t, http.MethodGet, "/posts/trending", reader.AccessToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var posts dto.TrendingPostsResponse
+		decode(t, resp, &posts)
+		res := make([]uuid.UUID, len(posts))
+		for i, post := range posts {
+			res[i] = post.PostId
+			assert.Empty(t, post.Content, "only the excerpt without full=true")
+		}
+		return res
+	}
+
+	assert.Equal(t, []uuid.UUID{ids[1], ids[0]}, trending(), "nothing viewed or liked yet, newest first")
+
+	resp = h.Do(t, http.MethodPost, fmt.Sprintf("/posts/%s/like", ids[0]), reader.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []uuid.UUID{ids[0], ids[1]}, trending())
+
+	resp = h.Do(t, http.MethodGet, "/posts/trending?limit=0", reader.AccessToken, "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEndToEnd_PreviewLinks(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "editor@example.com", Password: "Password123!", Role: types.Reader,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var editor dto.RegistrateUserResponse
+	decode(t, resp, &editor)
+
+	var drafts []uuid.UUID
+	for _, title := range []string{"Draft A", "Draft B"} {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: "preview-" + title, Title: title, Content: title + " content",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		drafts = append(drafts, created.PostId)
+	}
+	tokenPath := fmt.Sprintf("/posts/%s/preview-token", drafts[0])
+
+	resp = h.Do(t, http.MethodPost, tokenPath, editor.AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "only authors make links")
+
+	resp = h.Do(t, http.MethodPost, tokenPath, author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var preview dto.PreviewTokenResponse
+	decode(t, resp, &preview)
+	require.True(t, strings.HasPrefix(preview.Url, "/api/v1/public/preview/"), preview.Url)
+	previewPath := strings.TrimPrefix(preview.Url, "/api/v1")
+
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", drafts[0]), editor.AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "the draft stays hidden from the editor's account")
+
+	for _, token := range []string{"", editor.AccessToken} {
+		resp = h.Do(t, http.MethodGet, previewPath, token, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		var post dto.GetPostResponse
+		decode(t, resp, &post)
+		assert.Equal(t, drafts[0], post.PostId, "the link of A reads A only")
+		assert.Equal(t, "Draft A", post.Title)
+		assert.Equal(t, types.Draft, post.Status)
+	}
+
+	resp = h.Do(t, http.MethodGet, "/public/preview/"+drafts[1].String(), "", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodDelete, tokenPath, author.AccessToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var revoked dto.RevokePreviewTokensResponse
+	decode(t, resp, &revoked)
+	assert.Equal(t, 1, revoked.Revoked)
+
+	resp = h.Do(t, http.MethodGet, previewPath, "", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "a revoked link reads nothing")
+}
+
+func TestEndToEnd_ReportsModerated(t *testing.T) {
+	h := harness.New(t)
+
+	admin, err := service.NewAdminService(h.Repository, nil, nil, nil).CreateAdmin(context.Background(), "root@example.com", "Password123!")
+	require.NoError(t, err)
+	resp := h.Do(t, http.MethodPost, "/auth/login", "", "application/json", jsonBody(t, dto.LoginUserRequest{
+		Email: "root@example.com", Password: "Password123!",
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var login dto.LoginUserResponse
+	decode(t, resp, &login)
+	adminToken := login.AccessToken
+
+	resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	var readers []dto.RegistrateUserResponse
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		resp = h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+			Email: email, Password: "Password123!", Role: types.Reader,
+		}))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var reader dto.RegistrateUserResponse
+		decode(t, resp, &reader)
+		readers = append(readers, reader)
+	}
+
+	var ids []uuid.UUID
+	for _, title := range []string{"Spam", "Rant", "Draft"} {
+		resp := h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: "report-" + title, Title: title, Content: title + " content",
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.CreatePostResponse
+		decode(t, resp, &created)
+		ids = append(ids, created.PostId)
+		if title != "Draft" {
+			resp = h.Do(t, http.MethodPatch, fmt.Sprintf("/posts/%s/status", created.PostId), author.AccessToken, "application/json", jsonBody(t, dto.PublishPostRequest{
+				Status: types.Published,
+			}))
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+	}
+	spam, rant, draft := ids[0], ids[1], ids[2]
+
+	report := func(token string, postId uuid.UUID, req dto.ReportPostRequest) *http.Response {
+		return h.Do(t, http.MethodPost, fmt.Sprintf("/post/%s/report", postId), token, "application/json", jsonBody(t, req))
+	}
+	for _, reader := range readers {
+		resp = report(reader.AccessToken, spam, dto.ReportPostRequest{Reason: types.ReportSpam, Comment: "casino links"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	resp = report(readers[0].AccessToken, spam, dto.ReportPostRequest{Reason: types.ReportAbuse})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var kept dto.ReportResponse
+	decode(t, resp, &kept)
+	assert.Equal(t, types.ReportSpam, kept.Reason, "a repeated report keeps the first one")
+	resp = report(readers[0].AccessToken, rant, dto.ReportPostRequest{Reason: types.ReportAbuse})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = report(readers[0].AccessToken, draft, dto.ReportPostRequest{Reason: types.ReportSpam})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "a draft cannot be reported")
+	resp = report(readers[0].AccessToken, uuid.New(), dto.ReportPostRequest{Reason: types.ReportSpam})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp = report(readers[0].AccessToken, rant, dto.ReportPostRequest{Reason: "boring"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = h.Do(t, http.MethodGet, "/admin/reports", readers[0].AccessToken, "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	queue := func() dto.GetReportsResponse {
+		resp := h.Do(t, http.MethodGet, "/admin/reports", adminToken, "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var page dto.GetReportsResponse
+		decode(t, resp, &page)
+		return page
+	}
+	page := queue()
+	require.Equal(t, 2, page.Total)
+	assert.Equal(t, spam, page.Posts[0].PostId, "the most reported first")
+	assert.Equal(t, 2, page.Posts[0].Reports)
+	assert.Equal(t, dto.ReportReasons{Spam: 2}, page.Posts[0].Reasons)
+	assert.Equal(t, []string{"casino links", "casino links"}, page.Posts[0].Comments)
+	assert.Equal(t, rant, page.Posts[1].PostId)
+
+	resolve := func(postId uuid.UUID, action types.ModerationAction) *http.Response {
+		return h.Do(t, http.MethodPost, fmt.Sprintf("/admin/reports/%s/resolve", postId), adminToken, "application/json", jsonBody(t, dto.ResolveReportsRequest{
+			Action: action,
+		}))
+	}
+	resp = resolve(rant, types.ModerationDismiss)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = resolve(rant, types.ModerationDismiss)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "nothing left to resolve")
+
+	resp = resolve(spam, types.ModerationUnpublish)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var resolved dto.ResolveReportsResponse
+	decode(t, resp, &resolved)
+	assert.Equal(t, dto.ResolveReportsResponse{PostId: spam, Action: types.ModerationUnpublish, Resolved: 2, Status: types.Draft}, resolved)
+	resp = h.Do(t, http.MethodGet, fmt.Sprintf("/posts/%s", spam), readers[1].AccessToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "the unpublished post is gone for readers")
+	assert.Empty(t, queue().Posts)
+
+	resp = h.Do(t, http.MethodGet, "/admin/audit?action=unpublish_post", adminToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var audit dto.GetAuditLogResponse
+	decode(t, resp, &audit)
+	require.Equal(t, 1, audit.Total)
+	assert.Equal(t, spam, *audit.Entries[0].TargetId)
+	assert.Equal(t, admin.UserId, *audit.Entries[0].UserId, "the acting admin is recorded")
+	resp = h.Do(t, http.MethodGet, "/admin/audit?action=dismiss_reports", adminToken, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decode(t, resp, &audit)
+	assert.Equal(t, 1, audit.Total)
+}
+
+func TestEndToEnd_IdempotencyKeys(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+
+	resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+		IdempotencyKey: "draft", Title: "Hello", Content: "World",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.CreatePostResponse
+	decode(t, resp, &created)
+
+	edit := func(key, title string) *http.Response {
+		req, err := http.NewRequest(http.MethodPut, h.BaseURL+"/posts/"+created.PostId.String(),
+			jsonBody(t, dto.EditPostRequest{Title: title, Content: "World"}))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+author.AccessToken)
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := h.Client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	read := func(resp *http.Response) string {
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	first := edit("edit-1", "Second")
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+	firstBody := read(first)
+	assert.Empty(t, first.Header.Get("Idempotent-Replayed"))
+
+	again := edit("edit-1", "Second")
+	require.Equal(t, http.StatusCreated, again.StatusCode)
+	assert.Equal(t, "true", again.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, firstBody, read(again))
+
+	assert.Equal(t, http.StatusConflict, edit("edit-1", "Third").StatusCode, "another body under the same key")
+	assert.Equal(t, http.StatusCreated, edit("edit-2", "Third").StatusCode)
+
+	// the key is free again once it expired
+	h.Clock.Advance(25 * time.Hour)
+	resp = edit("edit-1", "Fourth")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+}
+
+func TestEndToEnd_Methods(t *testing.T) {
+	h := harness.New(t)
+
+	resp := h.Do(t, http.MethodPost, "/auth/register", "", "application/json", jsonBody(t, dto.RegistrateUserRequest{
+		Email: "author@example.com", Password: "Password123!", Role: types.Author,
+	}))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var author dto.RegistrateUserResponse
+	decode(t, resp, &author)
+	for i := range 10 {
+		resp = h.Do(t, http.MethodPost, "/posts", author.AccessToken, "application/json", jsonBody(t, dto.CreatePostRequest{
+			IdempotencyKey: fmt.Sprintf("post-%d", i), Title: "Long", Content: strings.Repeat("words ", 100),
+		}))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	send := func(method, url, token string) *http.Response {
+		req, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		// the length of HEAD is that of the body as it is, not compressed
+		req.Header.Set("Accept-Encoding", "identity")
+		resp, err := h.Client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	root := "http://" + h.Server.Addr()
+
+	t.Run("options", func(t *testing.T) {
+		tests := []struct {
+			group string
+			url   string
+			allow string
+		}{
+			{group: "reader", url: h.BaseURL + "/posts", allow: "GET, HEAD, OPTIONS, POST"},
+			{group: "poster", url: h.BaseURL + "/posts/" + uuid.NewString(), allow: "DELETE, GET, HEAD, OPTIONS, PATCH, PUT"},
+			{group: "uploads", url: h.BaseURL + "/posts/" + uuid.NewString() + "/images", allow: "GET, HEAD, OPTIONS, POST"},
+			{group: "images", url: h.BaseURL + "/posts/" + uuid.NewString() + "/images/" + uuid.NewString(), allow: "DELETE, OPTIONS, PATCH, PUT"},
+			{group: "subscriptions", url: h.BaseURL + "/authors/" + uuid.NewString() + "/subscribe", allow: "DELETE, OPTIONS, POST"},
+			{group: "profile", url: h.BaseURL + "/users/me", allow: "DELETE, GET, HEAD, OPTIONS, PATCH"},
+			{group: "auth", url: h.BaseURL + "/auth/sessions", allow: "DELETE, GET, HEAD, OPTIONS"},
+			{group: "admin", url: h.BaseURL + "/admin/users", allow: "GET, HEAD, OPTIONS"},
+			{group: "public", url: h.BaseURL + "/public/posts", allow: "GET, HEAD, OPTIONS"},
+			{group: "unversioned", url: root + "/api/public/posts", allow: "GET, HEAD, OPTIONS"},
+			{group: "health", url: root + "/healthz", allow: "GET, HEAD, OPTIONS"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.group, func(t *testing.T) {
+				// no token, a client finds out what a path takes before it signs in
+				resp := send(http.MethodOptions, tt.url, "")
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.Equal(t, tt.allow, resp.Header.Get("Allow"))
+			})
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		tests := []struct {
+			group  string
+			method string
+			url    string
+			allow  string
+		}{
+			{group: "reader", method: http.MethodDelete, url: h.BaseURL + "/posts", allow: "GET, HEAD, OPTIONS, POST"},
+			{group: "poster", method: http.MethodPost, url: h.BaseURL + "/posts/" + uuid.NewString() + "/status", allow: "OPTIONS, PATCH"},
+			{group: "auth", method: http.MethodGet, url: h.BaseURL + "/auth/login", allow: "OPTIONS, POST"},
+			{group: "admin", method: http.MethodDelete, url: h.BaseURL + "/admin/users", allow: "GET, HEAD, OPTIONS"},
+			{group: "public", method: http.MethodPost, url: h.BaseURL + "/public/posts", allow: "GET, HEAD, OPTIONS"},
+			{group: "health", method: http.MethodPost, url: root + "/readyz", allow: "GET, HEAD, OPTIONS"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.group, func(t *testing.T) {
+				for _, token := range []string{"", author.AccessToken} {
+					resp := send(tt.method, tt.url, token)
+					assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+					assert.Equal(t, tt.allow, resp.Header.Get("Allow"))
+				}
+			})
+		}
+
+		resp := send(http.MethodGet, h.BaseURL+"/nope", author.AccessToken)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "unknown paths stay 404")
+		assert.Empty(t, resp.Header.Get("Allow"))
+	})
+
+	t.Run("head", func(t *testing.T) {
+		for _, url := range []string{h.BaseURL + "/posts", h.BaseURL + "/users/me", root + "/healthz"} {
+			get := send(http.MethodGet, url, author.AccessToken)
+			require.Equal(t, http.StatusOK, get.StatusCode)
+			body, err := io.ReadAll(get.Body)
+			require.NoError(t, err)
+
+			head := send(http.MethodHead, url, author.AccessToken)
+			assert.Equal(t, http.StatusOK, head.StatusCode, url)
+			assert.Equal(t, int64(len(body)), head.ContentLength, url)
+			assert.Equal(t, get.Header.Get("Content-Type"), head.Header.Get("Content-Type"), url)
+		}
+		assert.Equal(t, http.StatusUnauthorized, send(http.MethodHead, h.BaseURL+"/posts", "").StatusCode, "HEAD needs what GET needs")
+	})
+}
+
+// tracedServer serves the api over a mocked database with the spans going to the returned recorder
+func tracedServer(t *testing.T) (*servers.HttpServer, sqlmock.Sqlmock, *tracetest.SpanRecorder, servers.HttpServerConfig) {
+	return tracedServerWith(t, testConfig())
+}
+
+// tracedServerWith is tracedServer serving cfg
+func tracedServerWith(t *testing.T, cfg servers.HttpServerConfig) (*servers.HttpServer, sqlmock.Sqlmock, *tracetest.SpanRecorder, servers.HttpServerConfig) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	images, err := repository.NewFSRepository(t.TempDir(), true, "")
+	require.NoError(t, err)
+
+	server := servers.NewHttpServer(cfg, servers.HttpServerOptions{
+		DB:             &postgres.DB{DB: sqlx.NewDb(db, "postgres")},
+		ImageStorage:   images,
+		Listener:       listen(t),
+		TracerProvider: provider,
+	})
+	serve(t, server)
+	return server, mock, recorder, cfg
+}
+
+// assertQueriesInRequest waits for the request span named request and checks that every query
+// is a span of its own right under it
+func assertQueriesInRequest(t *testing.T, recorder *tracetest.SpanRecorder, request string, queries ...string) {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		_, ok := spans[request]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	parent := spans[request]
+	for _, name := range queries {
+		span, ok := spans[name]
+		require.True(t, ok, name)
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID(), name)
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), name)
+	}
+}
+
+func TestEndToEnd_TracesGetPost(t *testing.T) {
+	server, mock, recorder, cfg := tracedServer(t)
+
+	authorId, postId := uuid.New(), uuid.New()
+	mock.ExpectQuery(`SELECT \* FROM users WHERE user_id`).WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "role"}).AddRow(authorId, "author@example.com", types.Author))
+	mock.ExpectQuery(`FROM posts p\s+LEFT JOIN users u`).WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id", "title", "content", "status", "word_count", "reading_time_minutes"}).
+			AddRow(postId, authorId, "Hello", "World", types.Draft, 1, 1))
+	mock.ExpectQuery(`FROM images`).WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"image_id", "post_id", "image_url"}))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/api/v1/posts/%s", server.Addr(), postId), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+jwt.NewAccessToken(authorId, jwt.Keys{Primary: cfg.Secret}, time.Hour, jwt.Options{}))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assertQueriesInRequest(t, recorder, "GET /api/v1", "GetUserById", "GetPostWithAuthorById", "GetPostImages")
+}
+
+func TestEndToEnd_TracesEditPost(t *testing.T) {
+	server, mock, recorder, cfg := tracedServer(t)
+
+	authorId, postId := uuid.New(), uuid.New()
+	mock.ExpectQuery(`SELECT \* FROM users WHERE user_id`).WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "role"}).AddRow(authorId, "author@example.com", types.Author))
+	mock.ExpectQuery(`FROM posts p WHERE p.post_id`).WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id", "status"}).AddRow(postId, authorId, types.Draft))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO post_revisions`).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(types.Draft))
+	mock.ExpectQuery(`UPDATE posts p SET`).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "author_id", "title", "content", "status"}).
+			AddRow(postId, authorId, "Hello", "Again", types.Draft))
+	mock.ExpectQuery(`FROM posts p WHERE p.post_id`).WithArgs(postId).
+		WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow("{}"))
+	mock.ExpectCommit()
+
+	body := jsonBody(t, dto.EditPostRequest{Title: "Hello", Content: "Again"})
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("http://%s/api/v1/posts/%s", server.Addr(), postId), body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+jwt.NewAccessToken(authorId, jwt.Keys{Primary: cfg.Secret}, time.Hour, jwt.Options{}))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assertQueriesInRequest(t, recorder, "PUT /api/v1", "GetUserById", "GetPostById", "UpdatePost")
+}
+
+func TestEndToEnd_RequestTimeoutCancelsQuery(t *testing.T) {
+	cfg := testConfig()
+	cfg.RequestTimeout = 100 * time.Millisecond
+	server, mock, recorder, _ := tracedServerWith(t, cfg)
+
+	authorId, postId := uuid.New(), uuid.New()
+	mock.ExpectQuery(`SELECT \* FROM users WHERE user_id`).WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "role"}).AddRow(authorId, "author@example.com", types.Author))
+	mock.ExpectQuery(`FROM posts p\s+LEFT JOIN users u`).WithArgs(postId).
+		WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(postId))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/api/v1/posts/%s", server.Addr(), postId), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+jwt.NewAccessToken(authorId, jwt.Keys{Primary: cfg.Secret}, time.Hour, jwt.Options{}))
+	started := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	// the query was given up at the deadline, not waited out
+	require.Less(t, time.Since(started), 5*time.Second)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			if span.Name() == "GetPostWithAuthorById" {
+				return span.Status().Code == otelcodes.Error
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEndToEnd_PreviewTokenNotRecorded(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	server, mock, recorder, _ := tracedServer(t)
+
+	token := "s3cr3t-preview-token"
+	mock.ExpectQuery(`FROM post_preview_tokens`).WillReturnError(sql.ErrNoRows)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/api/v1/public/preview/%s", server.Addr(), token))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var request sdktrace.ReadOnlySpan
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			if span.Name() == "GET /api/v1" {
+				request = span
+			}
+		}
+		return request != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, request.Attributes(), attribute.String("url.path", "/api/v1/public/preview/{token}"))
+	for _, span := range recorder.Ended() {
+		assert.NotContains(t, span.Name(), token)
+		for _, attr := range span.Attributes() {
+			assert.NotContains(t, attr.Value.Emit(), token, string(attr.Key))
+		}
+	}
+	assert.Contains(t, logs.String(), "/public/preview/{token}")
+	assert.NotContains(t, logs.String(), token)
+}
@@ -0,0 +1,86 @@
+package servers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func (cfg *HttpServerConfig) tlsEnabled() bool {
+	return cfg.TLSCertFile != "" || len(cfg.AutocertDomains) > 0
+}
+
+// newTLS prepares server for autocert and builds the redirect server of TLS_REDIRECT. A static
+// certificate is only read by Start, so a broken file fails the start rather than the constructor.
+func newTLS(cfg *HttpServerConfig, server *http.Server) *http.Server {
+	var certs *autocert.Manager
+	if len(cfg.AutocertDomains) > 0 {
+		certs = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		server.TLSConfig = certs.TLSConfig()
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+	}
+
+	if !cfg.TLSRedirect {
+		return nil
+	}
+	handler := redirectToHTTPS(cfg.Port)
+	// Let's Encrypt asks for its http-01 challenges on port 80
+	if certs != nil {
+		handler = certs.HTTPHandler(handler)
+	}
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Address, cfg.TLSRedirectPort),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// loadCertificate reads TLS_CERT_FILE and TLS_KEY_FILE, without them it does nothing
+func (s *HttpServer) loadCertificate() error {
+	if s.cfg.TLSCertFile == "" {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	s.http.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return nil
+}
+
+// redirectToHTTPS sends a request to the same host and path on the https port, 308 keeps the method and body
+func redirectToHTTPS(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if host == "" {
+			http.Error(w, "missing host", http.StatusBadRequest)
+			return
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
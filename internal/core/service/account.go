@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type AccountRepository interface {
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	GetUserLikes(ctx context.Context, userId uuid.UUID) ([]*dto.ExportedLike, error)
+	ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error
+	// DeleteUser hands the posts over to heir, or deletes them with uuid.Nil
+	DeleteUser(ctx context.Context, id, heir uuid.UUID) (*dto.DeletedAccountDB, error)
+}
+
+// AccountService gives users what the blog keeps about them and deletes their account on request
+type AccountService struct {
+	rep  AccountRepository
+	stor storage.ImageStorage
+	// links is nil for a public bucket
+	links *ImageLinks
+	// users holds the users behind access tokens, a deleted one is forgotten there
+	users *UserCache
+	// listings show the posts and authors of a deleted account, they are dropped with it
+	listings *PostListCache
+	// recorder keeps the deletions, nil records nothing
+	recorder AuditRecorder
+	// policy tells whether posts outlive their deleted author under dto.DeletedUserId
+	policy types.DeletionPolicy
+}
+
+func NewAccountService(rep AccountRepository, stor storage.ImageStorage, links *ImageLinks, users *UserCache, listings *PostListCache, recorder AuditRecorder, policy types.DeletionPolicy) *AccountService {
+	return &AccountService{rep, stor, links, users, listings, recorder, policy}
+}
+
+// ExportData writes everything about the user to w as the JSON document dto.AccountExport: the user
+// without credentials, their likes, and their live posts with images one at a time. The user and
+// their likes are read before anything is written.
+func (s *AccountService) ExportData(ctx context.Context, userId uuid.UUID, w io.Writer) error {
+	user, err := s.rep.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	avatar, err := s.links.AvatarLink(user.AvatarUrl)
+	if err != nil {
+		return err
+	}
+	likes, err := s.rep.GetUserLikes(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, `{"exported_at":%q,"user":`, time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+	profile := &dto.UserResponse{
+		UserId:      user.UserId,
+		DisplayName: user.Name(),
+		AvatarUrl:   avatar,
+		Email:       user.Email,
+		Role:        user.Role,
+	}
+	if _, err := json.MarshalToWriter(profile, w); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `,"likes":[`); err != nil {
+		return err
+	}
+	for i, like := range likes {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := json.MarshalToWriter(like, w); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, `],"posts":[`); err != nil {
+		return err
+	}
+
+	separator := ""
+	err = s.rep.ExportAuthorPosts(ctx, userId, func(post *dto.PostDB, images []*dto.ImageDB) error {
+		exported, err := exportedPost(s.links, post, images)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ","
+		_, err = json.MarshalToWriter(exported, w)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+// DeleteAccount deletes the user once they entered their password again. Under the anonymize policy
+// their posts stay under dto.DeletedUserId, under delete they go with the account. The database is
+// changed in one transaction, the stored avatar and images are removed after it committed.
+func (s *AccountService) DeleteAccount(ctx context.Context, userId uuid.UUID, req *dto.DeleteAccountRequest) error {
+	user, err := s.rep.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if ok, _ := hash.Compare(user.PasswordHash, req.Password); !ok {
+		return errors.ErrorServiceWrongPassword
+	}
+
+	heir := dto.DeletedUserId
+	if s.policy == types.DeletionDelete {
+		heir = uuid.Nil
+	}
+	deleted, err := s.rep.DeleteUser(ctx, userId, heir)
+	if err != nil {
+		return err
+	}
+	s.users.Forget(userId)
+	s.listings.Invalidate()
+	audit(ctx, s.recorder, types.AuditDeleteAccount, uuid.Nil, userId)
+
+	// the account is gone already, a client leaving now must not keep its files in the storage
+	s.purge(context.WithoutCancel(ctx), userId, deleted)
+	return nil
+}
+
+// purge removes the objects a deleted account left behind. The rows are gone, so a failure cannot be
+// undone and is only logged; images left over are found by the orphan cleanup later on.
+func (s *AccountService) purge(ctx context.Context, userId uuid.UUID, deleted *dto.DeletedAccountDB) {
+	objects := make([]string, 0, len(deleted.ImageUrls)+1)
+	if deleted.AvatarUrl != nil {
+		objects = append(objects, avatarObject(*deleted.AvatarUrl))
+	}
+	for _, imageUrl := range deleted.ImageUrls {
+		objects = append(objects, objectName(imageUrl))
+	}
+
+	for _, name := range objects {
+		if err := s.stor.DeleteImage(ctx, name); err != nil {
+			slogctx.Logger(ctx).Error("object of deleted account not removed",
+				slog.String("user_id", userId.String()), slog.String("object", name), slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// fakeAccountRepository keeps one user with a post and its image, DeleteUser records the heir it got
+type fakeAccountRepository struct {
+	user   *dto.UserDB
+	post   *dto.PostDB
+	image  *dto.ImageDB
+	likes  []*dto.ExportedLike
+	heir   *uuid.UUID
+	failed bool
+}
+
+func (f *fakeAccountRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	if f.user == nil || f.user.UserId != id {
+		return nil, sql.ErrNoRows
+	}
+	copied := *f.user
+	return &copied, nil
+}
+
+func (f *fakeAccountRepository) GetUserLikes(ctx context.Context, userId uuid.UUID) ([]*dto.ExportedLike, error) {
+	return f.likes, nil
+}
+
+func (f *fakeAccountRepository) ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error {
+	if f.post == nil || f.post.AuthorId != authorId {
+		return nil
+	}
+	return fn(f.post, []*dto.ImageDB{f.image})
+}
+
+func (f *fakeAccountRepository) DeleteUser(ctx context.Context, id, heir uuid.UUID) (*dto.DeletedAccountDB, error) {
+	if f.failed || f.user == nil || f.user.UserId != id {
+		return nil, sql.ErrNoRows
+	}
+	deleted := &dto.DeletedAccountDB{AvatarUrl: f.user.AvatarUrl}
+	if heir == uuid.Nil {
+		deleted.ImageUrls = []string{f.image.ImageUrl}
+		f.post = nil
+	} else {
+		f.post.AuthorId = heir
+	}
+	f.heir = &heir
+	f.user = nil
+	return deleted, nil
+}
+
+func newAccountFixture(t *testing.T) (*fakeAccountRepository, *recordingStorage) {
+	t.Helper()
+	passwordHash, err := hash.Hash("Password123!")
+	require.NoError(t, err)
+
+	userId := uuid.New()
+	avatar := "/images/avatars/me.png"
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: userId, Title: "Hello", Content: "World",
+		Status: types.Published, CreatedAt: created, UpdatedAt: created}
+	imageId := uuid.New()
+	rep := &fakeAccountRepository{
+		user: &dto.UserDB{UserId: userId, Email: "alice@example.com", PasswordHash: passwordHash,
+			Role: types.Author, AvatarUrl: &avatar},
+		post:  post,
+		image: &dto.ImageDB{ImageId: imageId, PostId: post.PostId, ImageUrl: "/images/" + imageId.String() + ".png", CreatedAt: created, UpdatedAt: created},
+		likes: []*dto.ExportedLike{{PostId: uuid.New(), LikedAt: created}},
+	}
+	stor := &recordingStorage{put: map[string]string{
+		"avatars/me.png":          "avatar",
+		imageId.String() + ".png": "image",
+	}}
+	return rep, stor
+}
+
+func TestAccountService_ExportData(t *testing.T) {
+	rep, stor := newAccountFixture(t)
+	s := NewAccountService(rep, stor, nil, nil, nil, nil, types.DeletionAnonymize)
+
+	var out bytes.Buffer
+	require.NoError(t, s.ExportData(context.Background(), rep.user.UserId, &out))
+	assert.NotContains(t, out.String(), rep.user.PasswordHash)
+	assert.NotContains(t, out.String(), "refresh")
+
+	var export dto.AccountExport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &export), out.String())
+	assert.Equal(t, rep.user.UserId, export.User.UserId)
+	assert.Equal(t, "alice@example.com", export.User.Email)
+	assert.Equal(t, "/images/avatars/me.png", export.User.AvatarUrl)
+	require.Len(t, export.Likes, 1)
+	assert.Equal(t, rep.likes[0].PostId, export.Likes[0].PostId)
+	require.Len(t, export.Posts, 1)
+	assert.Equal(t, "Hello", export.Posts[0].Title)
+	require.Len(t, export.Posts[0].Images, 1)
+	assert.Equal(t, rep.image.ImageUrl, export.Posts[0].Images[0].ImageUrl)
+}
+
+func TestAccountService_ExportData_UnknownUser(t *testing.T) {
+	rep, stor := newAccountFixture(t)
+	s := NewAccountService(rep, stor, nil, nil, nil, nil, types.DeletionAnonymize)
+
+	var out bytes.Buffer
+	assert.ErrorIs(t, s.ExportData(context.Background(), uuid.New(), &out), sql.ErrNoRows)
+	assert.Zero(t, out.Len(), "nothing is written before the user is found")
+}
+
+func TestAccountService_DeleteAccount(t *testing.T) {
+	t.Run("anonymize keeps the posts under the deleted user", func(t *testing.T) {
+		rep, stor := newAccountFixture(t)
+		userId, post := rep.user.UserId, rep.post
+		recorder := &fakeAuditRecorder{}
+		s := NewAccountService(rep, stor, nil, nil, nil, recorder, types.DeletionAnonymize)
+
+		require.NoError(t, s.DeleteAccount(context.Background(), userId, &dto.DeleteAccountRequest{Password: "Password123!"}))
+		require.NotNil(t, rep.heir)
+		assert.Equal(t, dto.DeletedUserId, *rep.heir)
+		assert.Equal(t, dto.DeletedUserId, post.AuthorId)
+		assert.Equal(t, []string{"avatars/me.png"}, stor.deleted, "the images stay with the posts")
+		assert.Equal(t, []types.AuditAction{types.AuditDeleteAccount}, recorder.actions())
+		assert.Nil(t, recorder.entries[0].UserId)
+		assert.Equal(t, userId, *recorder.entries[0].TargetId)
+	})
+
+	t.Run("delete removes the posts and their images", func(t *testing.T) {
+		rep, stor := newAccountFixture(t)
+		imageObject := rep.image.ImageId.String() + ".png"
+		s := NewAccountService(rep, stor, nil, nil, nil, nil, types.DeletionDelete)
+
+		require.NoError(t, s.DeleteAccount(context.Background(), rep.user.UserId, &dto.DeleteAccountRequest{Password: "Password123!"}))
+		require.NotNil(t, rep.heir)
+		assert.Equal(t, uuid.Nil, *rep.heir)
+		assert.Nil(t, rep.post)
+		assert.ElementsMatch(t, []string{"avatars/me.png", imageObject}, stor.deleted)
+		assert.Empty(t, stor.put)
+	})
+
+	t.Run("wrong password changes nothing", func(t *testing.T) {
+		rep, stor := newAccountFixture(t)
+		s := NewAccountService(rep, stor, nil, nil, nil, nil, types.DeletionDelete)
+
+		err := s.DeleteAccount(context.Background(), rep.user.UserId, &dto.DeleteAccountRequest{Password: "wrong"})
+		assert.ErrorIs(t, err, errors.ErrorServiceWrongPassword)
+		assert.Nil(t, rep.heir)
+		assert.NotNil(t, rep.user)
+		assert.Empty(t, stor.deleted)
+	})
+
+	t.Run("failed deletion keeps the files", func(t *testing.T) {
+		rep, stor := newAccountFixture(t)
+		rep.failed = true
+		recorder := &fakeAuditRecorder{}
+		s := NewAccountService(rep, stor, nil, nil, nil, recorder, types.DeletionDelete)
+
+		err := s.DeleteAccount(context.Background(), rep.user.UserId, &dto.DeleteAccountRequest{Password: "Password123!"})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Empty(t, stor.deleted)
+		assert.Empty(t, recorder.entries)
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type AdminRepository interface {
+	AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error)
+	GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error)
+	GetUsers(ctx context.Context, limit, offset int) ([]*dto.UserDB, error)
+	CountUsers(ctx context.Context) (int, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error)
+	UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error)
+	GetAuditEntries(ctx context.Context, filter dto.AuditFilter, limit, offset int) ([]*dto.AuditEntryDB, error)
+	CountAuditEntries(ctx context.Context, filter dto.AuditFilter) (int, error)
+	GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error)
+	GetReportedPosts(ctx context.Context, limit, offset int) ([]*dto.ReportedPostDB, error)
+	CountReportedPosts(ctx context.Context) (int, error)
+	CountOpenReports(ctx context.Context, postId uuid.UUID) (int, error)
+	ResolveReports(ctx context.Context, postId, adminId uuid.UUID, action types.ModerationAction) (int, error)
+}
+
+type AdminService struct {
+	rep AdminRepository
+	// users is the cache of AuthService, nil where no requests are authorized
+	users *UserCache
+	// listings is the cache of ReaderService, nil when the listings are not cached
+	listings *PostListCache
+	// recorder keeps the moderation of reported posts, nil records nothing
+	recorder AuditRecorder
+}
+
+func NewAdminService(rep AdminRepository, users *UserCache, listings *PostListCache, recorder AuditRecorder) *AdminService {
+	return &AdminService{rep, users, listings, recorder}
+}
+
+func (s *AdminService) GetUsers(ctx context.Context, limit, offset int) (*dto.GetUsersResponse, error) {
+	total, err := s.rep.CountUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usersDB, err := s.rep.GetUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dto.UserResponse, len(usersDB))
+	for i, user := range usersDB {
+		users[i] = dto.UserResponse{
+			UserId:      user.UserId,
+			DisplayName: user.Name(),
+			Email:       user.Email,
+			Role:        user.Role,
+		}
+	}
+
+	return &dto.GetUsersResponse{
+		Users:  users,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// GetAuditLog pages through the audit log latest first, filter narrows it to a user or an action
+func (s *AdminService) GetAuditLog(ctx context.Context, filter dto.AuditFilter, limit, offset int) (*dto.GetAuditLogResponse, error) {
+	total, err := s.rep.CountAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entriesDB, err := s.rep.GetAuditEntries(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]dto.AuditEntryResponse, len(entriesDB))
+	for i, entry := range entriesDB {
+		entries[i] = dto.AuditEntryResponse{
+			AuditId:   entry.AuditId,
+			UserId:    entry.UserId,
+			Action:    entry.Action,
+			TargetId:  entry.TargetId,
+			IP:        entry.IP,
+			UserAgent: entry.UserAgent,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return &dto.GetAuditLogResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+// ChangeUserRole sets the role of any user except the calling admin, so the last admin cannot lock everyone out
+func (s *AdminService) ChangeUserRole(ctx context.Context, adminId, userId uuid.UUID, req *dto.ChangeRoleRequest) (*dto.UserResponse, error) {
+	if adminId == userId {
+		return nil, errors.ErrorServiceNoAccess
+	}
+
+	user, err := s.rep.UpdateUserRole(ctx, userId, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	s.users.Forget(userId)
+
+	return &dto.UserResponse{
+		UserId:      user.UserId,
+		DisplayName: user.Name(),
+		Email:       user.Email,
+		Role:        user.Role,
+	}, nil
+}
+
+// SetPostStatus moves any post to any status regardless of its author, the revision it leaves names the admin
+func (s *AdminService) SetPostStatus(ctx context.Context, adminId, postId uuid.UUID, req *dto.SetPostStatusRequest) (*dto.EditPostResponse, error) {
+	postDB, err := s.rep.UpdatePost(ctx, postId, adminId, &dto.PostPatch{Status: &req.Status}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the previous status is not known here, any change may have taken a post off the listings
+	s.listings.Invalidate()
+
+	return editPostResponse(postDB, nil), nil
+}
+
+// GetCacheStats reports the hits and misses of the user and listing caches of this process
+func (s *AdminService) GetCacheStats() *dto.CacheStatsResponse {
+	return &dto.CacheStatsResponse{
+		Users: cacheStats(s.users.enabled(), s.users.Hits(), s.users.Misses()),
+		Posts: cacheStats(s.listings != nil, s.listings.Hits(), s.listings.Misses()),
+	}
+}
+
+func cacheStats(enabled bool, hits, misses int64) dto.CacheStats {
+	stats := dto.CacheStats{Enabled: enabled, Hits: hits, Misses: misses}
+	if hits+misses > 0 {
+		stats.HitRatio = float64(hits) / float64(hits+misses)
+	}
+	return stats
+}
+
+// SetRoleByEmail changes the role of the user with that email, sql.ErrNoRows when there is none
+func (s *AdminService) SetRoleByEmail(ctx context.Context, email string, role types.Role) (*dto.UserResponse, error) {
+	user, err := s.rep.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = s.rep.UpdateUserRole(ctx, user.UserId, role)
+	if err != nil {
+		return nil, err
+	}
+	s.users.Forget(user.UserId)
+
+	return &dto.UserResponse{
+		UserId:      user.UserId,
+		DisplayName: user.Name(),
+		Email:       user.Email,
+		Role:        user.Role,
+	}, nil
+}
+
+// CreateAdmin bootstraps an operator account, an existing user with that email is promoted instead
+func (s *AdminService) CreateAdmin(ctx context.Context, email, password string) (*dto.UserDB, error) {
+	user, err := s.rep.GetUserByEmail(ctx, email)
+	if err == nil {
+		return s.rep.UpdateUserRole(ctx, user.UserId, types.Admin)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if len(password) < 8 {
+		return nil, errors.ErrorServiceIncorrectData
+	}
+	passwordHash, err := hash.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+	// no refresh token, the admin logs in like everybody else
+	// an admin created here has no refresh token until the first login
+	return s.rep.AddNewUser(ctx, email, passwordHash, string(types.Admin))
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// apiKeyPrefix starts every API key, so secret scanners and people can tell one from other tokens
+const apiKeyPrefix = "blog_"
+
+// ApiKeyRepository keeps the API keys of users, found by the hash of the key
+type ApiKeyRepository interface {
+	CreateApiKey(ctx context.Context, key *dto.ApiKeyDB) (*dto.ApiKeyDB, error)
+	UseApiKey(ctx context.Context, keyHash string) (*dto.ApiKeyDB, error)
+	GetApiKeys(ctx context.Context, userId uuid.UUID) ([]*dto.ApiKeyDB, error)
+	DeleteApiKey(ctx context.Context, userId, keyId uuid.UUID) error
+}
+
+// CreateApiKey makes a key for machine clients of the user. It can read whatever the user can, and
+// write too if req asks for the write scope. The key is in the response only, just its hash is stored.
+func (s *AuthService) CreateApiKey(ctx context.Context, userId uuid.UUID, req *dto.CreateApiKeyRequest) (*dto.CreateApiKeyResponse, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.ErrorServiceApiKeyExpired
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + token
+
+	scopes := pq.StringArray{string(types.ScopeRead)}
+	if slices.Contains(req.Scopes, types.ScopeWrite) {
+		scopes = append(scopes, string(types.ScopeWrite))
+	}
+	created, err := s.rep.CreateApiKey(ctx, &dto.ApiKeyDB{
+		UserId:    userId,
+		Name:      strings.TrimSpace(req.Name),
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	audit(ctx, s.recorder, types.AuditCreateApiKey, userId, created.KeyId)
+
+	return &dto.CreateApiKeyResponse{ApiKeyResponse: apiKeyResponse(created), Key: key}, nil
+}
+
+// ApiKeys lists the keys of the user without the keys themselves
+func (s *AuthService) ApiKeys(ctx context.Context, userId uuid.UUID) (*dto.GetApiKeysResponse, error) {
+	keys, err := s.rep.GetApiKeys(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.GetApiKeysResponse{ApiKeys: make([]dto.ApiKeyResponse, 0, len(keys))}
+	for _, key := range keys {
+		res.ApiKeys = append(res.ApiKeys, apiKeyResponse(key))
+	}
+	return res, nil
+}
+
+// RevokeApiKey deletes a key of the user, requests with it are refused at once
+func (s *AuthService) RevokeApiKey(ctx context.Context, userId, keyId uuid.UUID) error {
+	err := s.rep.DeleteApiKey(ctx, userId, keyId)
+	if err == sql.ErrNoRows {
+		return errors.ErrorServiceApiKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	audit(ctx, s.recorder, types.AuditRevokeApiKey, userId, keyId)
+	return nil
+}
+
+// AuthorizeApiKey returns the user of an API key and the scopes of the key. Unknown, revoked and
+// expired keys are errors.ErrorInvalidToken.
+func (s *AuthService) AuthorizeApiKey(ctx context.Context, key string) (*dto.UserDB, []types.Scope, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, nil, errors.ErrorInvalidToken
+	}
+
+	apiKey, err := s.rep.UseApiKey(ctx, hashToken(key))
+	if err == sql.ErrNoRows {
+		return nil, nil, errors.ErrorInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.users.Get(ctx, apiKey.UserId, s.rep.GetUserById)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, apiKeyScopes(apiKey), nil
+}
+
+func apiKeyScopes(key *dto.ApiKeyDB) []types.Scope {
+	scopes := make([]types.Scope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, types.Scope(scope))
+	}
+	return scopes
+}
+
+func apiKeyResponse(key *dto.ApiKeyDB) dto.ApiKeyResponse {
+	return dto.ApiKeyResponse{
+		KeyId:      key.KeyId,
+		Name:       key.Name,
+		Scopes:     apiKeyScopes(key),
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+	}
+}
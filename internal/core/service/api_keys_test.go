@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestAuthService_ApiKeys(t *testing.T) {
+	ctx := context.Background()
+	setup := func() (*AuthService, *fakeAuthRepository, *dto.UserDB) {
+		author := &dto.UserDB{UserId: uuid.New(), Email: "author@example.com", Role: types.Author}
+		rep := newFakeAuthRepository(author)
+		return newTestAuthService(rep, newFakeMailer()), rep, author
+	}
+
+	t.Run("read only by default", func(t *testing.T) {
+		s, rep, author := setup()
+		created, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: " ci "})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, apiKeyPrefix), created.Key)
+		assert.Equal(t, "ci", created.Name)
+		assert.Equal(t, []types.Scope{types.ScopeRead}, created.Scopes)
+
+		require.Len(t, rep.apiKeys, 1)
+		assert.Equal(t, hashToken(created.Key), rep.apiKeys[0].KeyHash, "only the hash is stored")
+
+		user, scopes, err := s.AuthorizeApiKey(ctx, created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, author.UserId, user.UserId)
+		assert.Equal(t, []types.Scope{types.ScopeRead}, scopes)
+		assert.NotNil(t, rep.apiKeys[0].LastUsedAt)
+	})
+
+	t.Run("write scope", func(t *testing.T) {
+		s, _, author := setup()
+		created, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "deploy", Scopes: []types.Scope{types.ScopeWrite}})
+		require.NoError(t, err)
+
+		_, scopes, err := s.AuthorizeApiKey(ctx, created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, []types.Scope{types.ScopeRead, types.ScopeWrite}, scopes)
+	})
+
+	t.Run("listing hides the key", func(t *testing.T) {
+		s, _, author := setup()
+		first, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "first"})
+		require.NoError(t, err)
+		_, err = s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "second"})
+		require.NoError(t, err)
+		_, err = s.CreateApiKey(ctx, uuid.New(), &dto.CreateApiKeyRequest{Name: "someone else"})
+		require.NoError(t, err)
+
+		keys, err := s.ApiKeys(ctx, author.UserId)
+		require.NoError(t, err)
+		require.Len(t, keys.ApiKeys, 2)
+		assert.Equal(t, "second", keys.ApiKeys[0].Name)
+		assert.Equal(t, first.KeyId, keys.ApiKeys[1].KeyId)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		s, rep, author := setup()
+		past := time.Now().Add(-time.Minute)
+		_, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "ci", ExpiresAt: &past})
+		assert.ErrorIs(t, err, errors.ErrorServiceApiKeyExpired)
+
+		soon := time.Now().Add(time.Hour)
+		created, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "ci", ExpiresAt: &soon})
+		require.NoError(t, err)
+		_, _, err = s.AuthorizeApiKey(ctx, created.Key)
+		require.NoError(t, err)
+
+		*rep.apiKeys[0].ExpiresAt = past
+		_, _, err = s.AuthorizeApiKey(ctx, created.Key)
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+	})
+
+	t.Run("revocation", func(t *testing.T) {
+		s, _, author := setup()
+		created, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "ci"})
+		require.NoError(t, err)
+
+		err = s.RevokeApiKey(ctx, uuid.New(), created.KeyId)
+		assert.ErrorIs(t, err, errors.ErrorServiceApiKeyNotFound, "keys of others are not found")
+		require.NoError(t, s.RevokeApiKey(ctx, author.UserId, created.KeyId))
+		assert.ErrorIs(t, s.RevokeApiKey(ctx, author.UserId, created.KeyId), errors.ErrorServiceApiKeyNotFound)
+
+		_, _, err = s.AuthorizeApiKey(ctx, created.Key)
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+	})
+
+	t.Run("other tokens are no keys", func(t *testing.T) {
+		s, _, author := setup()
+		created, err := s.CreateApiKey(ctx, author.UserId, &dto.CreateApiKeyRequest{Name: "ci"})
+		require.NoError(t, err)
+
+		_, _, err = s.AuthorizeApiKey(ctx, strings.TrimPrefix(created.Key, apiKeyPrefix))
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+		_, _, err = s.AuthorizeApiKey(ctx, apiKeyPrefix+"made-up")
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+	})
+}
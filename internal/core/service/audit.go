@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/clientctx"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// AuditRecorder keeps security relevant events, who did what and from where
+type AuditRecorder interface {
+	AddAuditEntry(ctx context.Context, entry *dto.AuditEntryDB) error
+}
+
+// audit records an action of userId on targetId, either may be uuid.Nil when unknown. The action already
+// happened, so a failing recorder is only logged and never fails the caller.
+func audit(ctx context.Context, rec AuditRecorder, action types.AuditAction, userId, targetId uuid.UUID) {
+	if rec == nil {
+		return
+	}
+
+	client := clientctx.FromContext(ctx)
+	entry := &dto.AuditEntryDB{
+		Action:    action,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	if userId != uuid.Nil {
+		entry.UserId = &userId
+	}
+	if targetId != uuid.Nil {
+		entry.TargetId = &targetId
+	}
+
+	if err := rec.AddAuditEntry(ctx, entry); err != nil {
+		slogctx.Logger(ctx).Error("audit entry not recorded", slog.String("action", string(action)), slog.String("error", err.Error()))
+	}
+}
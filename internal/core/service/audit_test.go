@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	gerrors "errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/clientctx"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakeAuditRecorder struct {
+	entries []*dto.AuditEntryDB
+	err     error
+}
+
+func (f *fakeAuditRecorder) AddAuditEntry(ctx context.Context, entry *dto.AuditEntryDB) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditRecorder) actions() []types.AuditAction {
+	actions := make([]types.AuditAction, len(f.entries))
+	for i, entry := range f.entries {
+		actions[i] = entry.Action
+	}
+	return actions
+}
+
+func newAuditedAuthService(rep AuthRepository, recorder AuditRecorder) *AuthService {
+	return NewAuthService(rep, newFakeMailer(), recorder, AuthConfig{
+		Secret:     "test-secret",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 24 * time.Hour,
+		Tokens:     jwt.Options{Issuer: "blog", Audience: "blog-api"},
+	})
+}
+
+var testClient = clientctx.Client{IP: "203.0.113.7", UserAgent: "test-agent"}
+
+func TestAuthService_Audit(t *testing.T) {
+	passwordHash, err := hash.Hash("password")
+	require.NoError(t, err)
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", PasswordHash: passwordHash, Role: types.Reader}
+	recorder := &fakeAuditRecorder{}
+	s := newAuditedAuthService(newFakeAuthRepository(user), recorder)
+	ctx := clientctx.WithClient(context.Background(), testClient)
+
+	_, err = s.LoginUser(ctx, &dto.LoginUserRequest{Email: "nobody@example.com", Password: "password"})
+	require.Error(t, err)
+	_, err = s.LoginUser(ctx, &dto.LoginUserRequest{Email: user.Email, Password: "wrong"})
+	require.Error(t, err)
+	login, err := s.LoginUser(ctx, &dto.LoginUserRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	_, err = s.RefreshToken(ctx, &dto.RefreshRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+	require.NoError(t, s.Logout(ctx, login.RefreshToken))
+	registered, err := s.RegistrateUser(ctx, &dto.RegistrateUserRequest{Email: "new@example.com", Password: "password", Role: types.Reader})
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.AuditAction{
+		types.AuditLoginFailed, types.AuditLoginFailed, types.AuditLogin, types.AuditRefresh, types.AuditLogout, types.AuditRegister,
+	}, recorder.actions())
+
+	// an unknown email names nobody
+	assert.Nil(t, recorder.entries[0].UserId)
+	assert.Equal(t, user.UserId, *recorder.entries[1].UserId)
+	assert.Equal(t, registered.Id, *recorder.entries[5].UserId)
+	for _, entry := range recorder.entries {
+		assert.Equal(t, testClient.IP, entry.IP)
+		assert.Equal(t, testClient.UserAgent, entry.UserAgent)
+		assert.Nil(t, entry.TargetId)
+	}
+}
+
+func TestPosterService_Audit(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Draft}
+	image := &dto.ImageDB{ImageId: uuid.New(), PostId: post.PostId, ImageUrl: "images/" + post.PostId.String() + "/a.jpg"}
+	rep := newFakePosterRepository(post)
+	rep.images[image.ImageId] = image
+	recorder := &fakeAuditRecorder{}
+	s := NewPosterService(rep, fakePosterStorage{}, recorder, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	ctx := clientctx.WithClient(context.Background(), testClient)
+
+	_, err := s.PublishPost(ctx, uuid.New(), post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.Error(t, err)
+	_, err = s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.NoError(t, err)
+	// going back to draft is no publication
+	_, err = s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Draft})
+	require.NoError(t, err)
+	_, err = s.DeleteImage(ctx, authorId, post.PostId, image.ImageId)
+	require.NoError(t, err)
+	_, err = s.TrashPost(ctx, authorId, post.PostId)
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.AuditAction{types.AuditPublishPost, types.AuditDeleteImage, types.AuditDeletePost}, recorder.actions())
+	targets := []uuid.UUID{post.PostId, image.ImageId, post.PostId}
+	for i, entry := range recorder.entries {
+		assert.Equal(t, authorId, *entry.UserId)
+		assert.Equal(t, targets[i], *entry.TargetId)
+		assert.Equal(t, testClient.IP, entry.IP)
+	}
+}
+
+func TestAudit_FailureIsNotFatal(t *testing.T) {
+	logs := &bytes.Buffer{}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	passwordHash, err := hash.Hash("password")
+	require.NoError(t, err)
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", PasswordHash: passwordHash, Role: types.Author}
+	recorder := &fakeAuditRecorder{err: gerrors.New("audit_log is gone")}
+	s := newAuditedAuthService(newFakeAuthRepository(user), recorder)
+
+	login, err := s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: user.UserId, Title: "t", Content: "c", Status: types.Published}
+	poster := NewPosterService(newFakePosterRepository(post), fakePosterStorage{}, recorder, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	_, err = poster.TrashPost(context.Background(), user.UserId, post.PostId)
+	require.NoError(t, err)
+
+	assert.Empty(t, recorder.entries)
+	assert.Contains(t, logs.String(), "audit entry not recorded")
+	assert.Contains(t, logs.String(), "audit_log is gone")
+}
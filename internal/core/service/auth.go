@@ -1,35 +1,145 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
 	"regexp"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/clientctx"
 	"github.com/xkarasb/blog/pkg/errors"
 	"github.com/xkarasb/blog/pkg/hash"
 	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
 type AuthRepository interface {
-	AddNewUser(email, password_hash, role, refreshToken string) (*dto.UserDB, error)
-	GetUserByEmail(email string) (*dto.UserDB, error)
-	GetUserById(id uuid.UUID) (*dto.UserDB, error)
-	UpdateRefreshToken(id uuid.UUID, refreshToken string) (*dto.UserDB, error)
+	SessionRepository
+	ApiKeyRepository
+	VerificationRepository
+	AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error)
+	GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error)
+	CreatePasswordReset(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+	// UpdatePasswordHash replaces the hash only while it is still oldHash, so a password changed meanwhile is kept
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error
+}
+
+// SessionRepository keeps a session per device a user logged in on, found by the hash of its refresh token
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *dto.SessionDB, max int) (*dto.SessionDB, error)
+	UseSession(ctx context.Context, tokenHash string) (*dto.SessionDB, error)
+	GetSessions(ctx context.Context, userId uuid.UUID) ([]*dto.SessionDB, error)
+	DeleteSession(ctx context.Context, userId, sessionId uuid.UUID) error
+	DeleteSessionByToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	DeleteOtherSessions(ctx context.Context, userId, keep uuid.UUID) (int, error)
+}
+
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type AuthConfig struct {
+	Secret string
+	// PreviousSecret still verifies tokens while Secret is being rotated, new tokens never use it
+	PreviousSecret   string
+	PasswordResetTTL time.Duration
+	// PasswordResetURL is the frontend page receiving ?token=
+	PasswordResetURL string
+	VerifyEmailTTL   time.Duration
+	// VerifyEmailURL receives ?token= of the link mailed at registration, the api endpoint or a frontend page calling it
+	VerifyEmailURL string
+	// VerifyResendInterval is how long a user waits between verification mails
+	VerifyResendInterval time.Duration
+	AccessTTL            time.Duration
+	// RefreshTTL is how long a login lasts without entering the password again
+	RefreshTTL time.Duration
+	// MaxSessions a user may have at once, a login beyond it ends the session used least recently;
+	// zero for no limit
+	MaxSessions int
+	// Tokens are the issuer, audience and clock skew of access and refresh tokens
+	Tokens jwt.Options
+	// UserCacheTTL is how long AuthorizeUser reuses a loaded user, zero loads it for every request
+	UserCacheTTL  time.Duration
+	UserCacheSize int
+	// Passwords are the argon2id params of new hashes, zero uses hash.DefaultParams
+	Passwords hash.Params
 }
 
 type AuthService struct {
 	rep    AuthRepository
-	secret string
+	keys   jwt.Keys
+	mailer Mailer
+	cfg    AuthConfig
+	users  *UserCache
+	// recorder keeps logins and other account events, nil records nothing
+	recorder AuditRecorder
+	// dummyHash is checked when no user has the email, so an unknown email takes as long as a wrong password
+	dummyHash string
+	resends   *resendThrottle
 }
 
-func NewAuthService(rep AuthRepository, secret string) *AuthService {
+func NewAuthService(rep AuthRepository, mailer Mailer, recorder AuditRecorder, cfg AuthConfig) *AuthService {
+	if cfg.Passwords == (hash.Params{}) {
+		cfg.Passwords = hash.DefaultParams
+	}
+	// the hash of an empty password never matches, as registration refuses those
+	dummyHash, err := cfg.Passwords.Hash("")
+	if err != nil {
+		panic(err)
+	}
 	return &AuthService{
 		rep,
-		secret,
+		jwt.Keys{Primary: cfg.Secret, Previous: cfg.PreviousSecret},
+		mailer,
+		cfg,
+		NewUserCache(cfg.UserCacheTTL, cfg.UserCacheSize, nil),
+		recorder,
+		dummyHash,
+		newResendThrottle(cfg.VerifyResendInterval),
 	}
 }
 
+// Users is the cache of authorized users, services changing a user outside this one forget it there
+func (s *AuthService) Users() *UserCache {
+	return s.users
+}
+
+// accessToken issues an access token for the user and session together with its lifetime in seconds
+func (s *AuthService) accessToken(id, sessionId uuid.UUID) (string, int64) {
+	return jwt.NewSessionAccessToken(id, sessionId, s.keys, s.cfg.AccessTTL, s.cfg.Tokens), int64(s.cfg.AccessTTL / time.Second)
+}
+
+// startSession logs the user in on the device of the request, the refresh token is stored only as a hash
+func (s *AuthService) startSession(ctx context.Context, user *dto.UserDB) (string, *dto.SessionDB, error) {
+	token, err := jwt.NewRefreshToken(user.Email, s.keys, s.cfg.RefreshTTL, s.cfg.Tokens)
+	if err != nil {
+		return "", nil, err
+	}
+	client := clientctx.FromContext(ctx)
+	session, err := s.rep.CreateSession(ctx, &dto.SessionDB{
+		UserId:    user.UserId,
+		TokenHash: hashToken(token),
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	}, s.cfg.MaxSessions)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, session, nil
+}
+
 func (s *AuthService) validateEmail(email string) bool {
 	basicPattern := `^[^@]+@[^@]+\.[^@]+$`
 	basicRegex := regexp.MustCompile(basicPattern)
@@ -37,118 +147,298 @@ func (s *AuthService) validateEmail(email string) bool {
 }
 
 func (s *AuthService) validatePassword(source, db string) bool {
-	res, err := hash.CheckPasswordHash(source, db)
+	res, err := hash.Compare(db, source)
 	if err != nil {
 		return false
 	}
 	return res
 }
 
-func (s *AuthService) RegistrateUser(user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error) {
+func (s *AuthService) RegistrateUser(ctx context.Context, user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error) {
 	if !s.validateEmail(user.Email) {
 		return nil, errors.ErrorServiceEmailInvalid
 	}
 
-	passwordHash, err := hash.HashPassword(user.Password)
+	passwordHash, err := s.cfg.Passwords.Hash(user.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := jwt.NewRefreshToken(user.Email, s.secret)
+	newUser, err := s.rep.AddNewUser(ctx, user.Email, passwordHash, string(user.Role))
 	if err != nil {
 		return nil, err
 	}
-	newUser, err := s.rep.AddNewUser(user.Email, passwordHash, string(user.Role), refreshToken)
-
+	refreshToken, session, err := s.startSession(ctx, newUser)
 	if err != nil {
 		return nil, err
 	}
-	accessToken := jwt.NewAccessToken(newUser.UserId, s.secret, time.Duration(time.Hour*2))
+	audit(ctx, s.recorder, types.AuditRegister, newUser.UserId, uuid.Nil)
+	s.resends.allow(newUser.UserId, time.Now())
+	s.sendVerification(newUser)
+	accessToken, expiresIn := s.accessToken(newUser.UserId, session.SessionId)
 
 	resUser := &dto.RegistrateUserResponse{
 		Id:           newUser.UserId,
 		AccessToken:  accessToken,
-		RefreshToken: newUser.RefreshToken,
+		ExpiresIn:    expiresIn,
+		RefreshToken: refreshToken,
 	}
 
 	return resUser, nil
 }
 
-func (s *AuthService) LoginUser(user *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
-	dbUser, err := s.rep.GetUserByEmail(user.Email)
-
+// LoginUser answers an unknown email and a wrong password alike, with ErrorServiceInvalidCredentials after
+// one password compare, so neither the error nor the time it took tells whether an account exists. A hash
+// of bcrypt or of older argon2id params is replaced once the password matched.
+func (s *AuthService) LoginUser(ctx context.Context, user *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
+	dbUser, err := s.rep.GetUserByEmail(ctx, user.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.validatePassword(user.Password, s.dummyHash)
+		audit(ctx, s.recorder, types.AuditLoginFailed, uuid.Nil, uuid.Nil)
+		return nil, errors.ErrorServiceInvalidCredentials
+	}
 	if err != nil {
-		return nil, errors.ErrorRepositoryEmailNotExsist
+		return nil, err
 	}
 
 	if !s.validatePassword(user.Password, dbUser.PasswordHash) {
-		return nil, errors.ErrorRepositoryEmailNotExsist
+		audit(ctx, s.recorder, types.AuditLoginFailed, dbUser.UserId, uuid.Nil)
+		return nil, errors.ErrorServiceInvalidCredentials
 	}
+	s.rehashPassword(ctx, dbUser, user.Password)
 
-	refreshToken, err := jwt.NewRefreshToken(dbUser.Email, s.secret)
+	refreshToken, session, err := s.startSession(ctx, dbUser)
 	if err != nil {
 		return nil, err
 	}
+	audit(ctx, s.recorder, types.AuditLogin, dbUser.UserId, uuid.Nil)
 
-	dbUser, err = s.rep.UpdateRefreshToken(dbUser.UserId, refreshToken)
-	if err != nil {
-		return nil, errors.ErrorRepositoryEmailNotExsist
-	}
-
-	accessToken := jwt.NewAccessToken(dbUser.UserId, s.secret, time.Duration(time.Hour*2))
+	accessToken, expiresIn := s.accessToken(dbUser.UserId, session.SessionId)
 
 	resUser := &dto.LoginUserResponse{
 		Id:           dbUser.UserId,
 		AccessToken:  accessToken,
-		RefreshToken: dbUser.RefreshToken,
+		ExpiresIn:    expiresIn,
+		RefreshToken: refreshToken,
 	}
 	return resUser, nil
 }
 
-func (s *AuthService) RefreshToken(token *dto.RefreshRequest) (*dto.RefreshResponse, error) {
-	claims, err := jwt.ValidateToken(token.RefreshToken, s.secret)
-
+// rehashPassword stores the password hashed with the current params, a failure only costs another try on the
+// next login
+func (s *AuthService) rehashPassword(ctx context.Context, user *dto.UserDB, password string) {
+	if !s.cfg.Passwords.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	passwordHash, err := s.cfg.Passwords.Hash(password)
+	if err == nil {
+		err = s.rep.UpdatePasswordHash(ctx, user.UserId, user.PasswordHash, passwordHash)
+	}
 	if err != nil {
+		slog.Warn("rehash password", slog.String("user_id", user.UserId.String()), slog.String("error", err.Error()))
+	}
+}
+
+// RefreshToken issues an access token for the session of a refresh token, which is marked used
+func (s *AuthService) RefreshToken(ctx context.Context, token *dto.RefreshRequest) (*dto.RefreshResponse, error) {
+	if _, err := jwt.ValidateToken(token.RefreshToken, s.keys, s.cfg.Tokens); err != nil {
 		return nil, err
 	}
 
-	email, ok := (*claims)["sub"].(string)
-	if !ok {
+	session, err := s.rep.UseSession(ctx, hashToken(token.RefreshToken))
+	if err == sql.ErrNoRows {
 		return nil, errors.ErrorInvalidToken
 	}
-	dbUser, err := s.rep.GetUserByEmail(email)
-
 	if err != nil {
 		return nil, err
 	}
+	audit(ctx, s.recorder, types.AuditRefresh, session.UserId, uuid.Nil)
 
-	if dbUser.RefreshToken != token.RefreshToken {
-		return nil, errors.ErrorInvalidToken
+	accessToken, expiresIn := s.accessToken(session.UserId, session.SessionId)
+
+	return &dto.RefreshResponse{AccessToken: accessToken, ExpiresIn: expiresIn}, nil
+}
+
+// Logout ends the session of a refresh token, one that ended already changes nothing
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	if _, err := jwt.ValidateToken(refreshToken, s.keys, s.cfg.Tokens); err != nil {
+		return err
+	}
+
+	userId, err := s.rep.DeleteSessionByToken(ctx, hashToken(refreshToken))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	audit(ctx, s.recorder, types.AuditLogout, userId, uuid.Nil)
+	return nil
+}
+
+// Sessions lists where the user is logged in, current is the session of the request
+func (s *AuthService) Sessions(ctx context.Context, userId, current uuid.UUID) (*dto.GetSessionsResponse, error) {
+	sessions, err := s.rep.GetSessions(ctx, userId)
+	if err != nil {
+		return nil, err
 	}
 
-	accessToken := jwt.NewAccessToken(dbUser.UserId, s.secret, time.Duration(time.Hour*2))
+	res := &dto.GetSessionsResponse{Sessions: make([]dto.SessionResponse, 0, len(sessions))}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, dto.SessionResponse{
+			SessionId:  session.SessionId,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.SessionId == current,
+		})
+	}
+	return res, nil
+}
 
-	return &dto.RefreshResponse{AccessToken: accessToken}, nil
+// RevokeSession logs the user out of one of their sessions, its refresh token stops working at once
+// and its access tokens once they expire
+func (s *AuthService) RevokeSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	err := s.rep.DeleteSession(ctx, userId, sessionId)
+	if err == sql.ErrNoRows {
+		return errors.ErrorServiceSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	audit(ctx, s.recorder, types.AuditLogout, userId, sessionId)
+	return nil
 }
 
-func (s *AuthService) AuthorizeUser(token string) (*dto.UserDB, error) {
-	claims, err := jwt.ValidateToken(token, s.secret)
+// RevokeOtherSessions logs the user out everywhere but in current. A token issued before sessions has
+// no current session, then every session ends.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userId, current uuid.UUID) (*dto.RevokeSessionsResponse, error) {
+	revoked, err := s.rep.DeleteOtherSessions(ctx, userId, current)
 	if err != nil {
 		return nil, err
 	}
+	if revoked > 0 {
+		audit(ctx, s.recorder, types.AuditLogout, userId, uuid.Nil)
+	}
+	return &dto.RevokeSessionsResponse{Revoked: revoked}, nil
+}
+
+func (s *AuthService) AuthorizeUser(ctx context.Context, token string) (*dto.UserDB, error) {
+	user, _, err := s.AuthorizeSession(ctx, token)
+	return user, err
+}
+
+// AuthorizeSession returns the user of an access token and the session it was issued for, uuid.Nil for
+// tokens issued before sessions. A session that ended is not looked up, its access tokens run out.
+func (s *AuthService) AuthorizeSession(ctx context.Context, token string) (*dto.UserDB, uuid.UUID, error) {
+	claims, err := jwt.ValidateToken(token, s.keys, s.cfg.Tokens)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
 
 	raw, ok := (*claims)["sub"].(string)
 	if !ok {
-		return nil, errors.ErrorInvalidToken
+		return nil, uuid.Nil, errors.ErrorInvalidToken
 	}
 	id, err := uuid.Parse(raw)
 	if err != nil {
-		return nil, errors.ErrorInvalidToken
+		return nil, uuid.Nil, errors.ErrorInvalidToken
 	}
-	data, err := s.rep.GetUserById(id)
+	data, err := s.users.Get(ctx, id, s.rep.GetUserById)
 
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	return data, jwt.SessionId(claims), nil
+}
+
+// BecomeAuthor is the only self-service role change: a reader turns into an author, never the other way.
+// The new access token belongs to the session of the old one.
+func (s *AuthService) BecomeAuthor(ctx context.Context, user *dto.UserDB, sessionId uuid.UUID, req *dto.ChangeOwnRoleRequest) (*dto.ChangeOwnRoleResponse, error) {
+	if req.Role != types.Author {
+		return nil, errors.ErrorRepositoryBadRole
+	}
+	if user.Role != types.Reader {
+		return nil, errors.ErrorServiceNoAccess
+	}
+
+	dbUser, err := s.rep.UpdateUserRole(ctx, user.UserId, types.Author)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+	s.users.Forget(dbUser.UserId)
+
+	accessToken, expiresIn := s.accessToken(dbUser.UserId, sessionId)
+
+	return &dto.ChangeOwnRoleResponse{
+		Id:          dbUser.UserId,
+		Role:        dbUser.Role,
+		AccessToken: accessToken,
+		ExpiresIn:   expiresIn,
+	}, nil
+}
+
+// randomToken is an unguessable URL safe token for links like password resets and previews
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashToken is what is stored of a randomToken, a leaked table does not open the links
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ForgotPassword mails a single use reset link. Unknown emails are not an error,
+// callers must answer the same way in both cases.
+func (s *AuthService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	user, err := s.rep.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.rep.CreatePasswordReset(ctx, user.UserId, hashToken(token), time.Now().Add(s.cfg.PasswordResetTTL)); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use the link below to set a new password, it expires in %s:\n\n%s?token=%s\n", s.cfg.PasswordResetTTL, s.cfg.PasswordResetURL, token)
+	// sending in background keeps response time close to the unknown email path
+	go func() {
+		if err := s.mailer.Send(user.Email, "Password reset", body); err != nil {
+			slog.Error("password reset mail failed", slog.String("user_id", user.UserId.String()), slog.String("error", err.Error()))
+		}
+	}()
+	return nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	passwordHash, err := s.cfg.Passwords.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	userId, err := s.rep.ResetPassword(ctx, hashToken(req.Token), passwordHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.ErrorInvalidToken
+		}
+		return err
+	}
+	s.users.Forget(userId)
+	audit(ctx, s.recorder, types.AuditPasswordReset, userId, uuid.Nil)
+	return nil
 }
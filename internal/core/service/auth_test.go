@@ -0,0 +1,655 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/clientctx"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeAuthRepository struct {
+	users  map[uuid.UUID]*dto.UserDB
+	resets map[string]uuid.UUID
+	// sessions are kept in the order they were made
+	sessions []*dto.SessionDB
+	apiKeys  []*dto.ApiKeyDB
+}
+
+func newFakeAuthRepository(users ...*dto.UserDB) *fakeAuthRepository {
+	rep := &fakeAuthRepository{users: map[uuid.UUID]*dto.UserDB{}, resets: map[string]uuid.UUID{}}
+	for _, user := range users {
+		rep.users[user.UserId] = user
+	}
+	return rep
+}
+
+func (f *fakeAuthRepository) VerifyEmail(ctx context.Context, id uuid.UUID, email string) error {
+	user, ok := f.users[id]
+	if !ok || user.Email != email {
+		return sql.ErrNoRows
+	}
+	user.EmailVerified = true
+	return nil
+}
+
+func (f *fakeAuthRepository) AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: email, PasswordHash: password_hash, Role: types.Role(role)}
+	f.users[user.UserId] = user
+	return user, nil
+}
+
+func (f *fakeAuthRepository) GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeAuthRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeAuthRepository) UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	user.Role = role
+	return user, nil
+}
+
+func (f *fakeAuthRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	if user, ok := f.users[id]; ok && user.PasswordHash == oldHash {
+		user.PasswordHash = newHash
+	}
+	return nil
+}
+
+func (f *fakeAuthRepository) CreatePasswordReset(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	f.resets[tokenHash] = userId
+	return nil
+}
+
+func (f *fakeAuthRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	userId, ok := f.resets[tokenHash]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	delete(f.resets, tokenHash)
+	f.users[userId].PasswordHash = passwordHash
+	f.sessions = slices.DeleteFunc(f.sessions, func(session *dto.SessionDB) bool { return session.UserId == userId })
+	return userId, nil
+}
+
+// CreateSession ends the oldest sessions beyond limit, the tests use them in the order they were made
+func (f *fakeAuthRepository) CreateSession(ctx context.Context, session *dto.SessionDB, limit int) (*dto.SessionDB, error) {
+	created := *session
+	created.SessionId = uuid.New()
+	created.CreatedAt = time.Now()
+	created.LastUsedAt = created.CreatedAt
+	f.sessions = append(f.sessions, &created)
+
+	if limit > 0 {
+		var mine []*dto.SessionDB
+		for _, other := range f.sessions {
+			if other.UserId == session.UserId {
+				mine = append(mine, other)
+			}
+		}
+		for _, old := range mine[:max(len(mine)-limit, 0)] {
+			f.sessions = slices.DeleteFunc(f.sessions, func(other *dto.SessionDB) bool { return other == old })
+		}
+	}
+	return &created, nil
+}
+
+func (f *fakeAuthRepository) UseSession(ctx context.Context, tokenHash string) (*dto.SessionDB, error) {
+	for _, session := range f.sessions {
+		if session.TokenHash == tokenHash && session.ExpiresAt.After(time.Now()) {
+			session.LastUsedAt = time.Now()
+			return session, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeAuthRepository) GetSessions(ctx context.Context, userId uuid.UUID) ([]*dto.SessionDB, error) {
+	var sessions []*dto.SessionDB
+	for _, session := range slices.Backward(f.sessions) {
+		if session.UserId == userId {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeAuthRepository) DeleteSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	before := len(f.sessions)
+	f.sessions = slices.DeleteFunc(f.sessions, func(session *dto.SessionDB) bool {
+		return session.UserId == userId && session.SessionId == sessionId
+	})
+	if len(f.sessions) == before {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (f *fakeAuthRepository) DeleteSessionByToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	for i, session := range f.sessions {
+		if session.TokenHash == tokenHash {
+			f.sessions = slices.Delete(f.sessions, i, i+1)
+			return session.UserId, nil
+		}
+	}
+	return uuid.Nil, sql.ErrNoRows
+}
+
+func (f *fakeAuthRepository) DeleteOtherSessions(ctx context.Context, userId, keep uuid.UUID) (int, error) {
+	before := len(f.sessions)
+	f.sessions = slices.DeleteFunc(f.sessions, func(session *dto.SessionDB) bool {
+		return session.UserId == userId && session.SessionId != keep
+	})
+	return before - len(f.sessions), nil
+}
+
+func (f *fakeAuthRepository) CreateApiKey(ctx context.Context, key *dto.ApiKeyDB) (*dto.ApiKeyDB, error) {
+	created := *key
+	created.KeyId = uuid.New()
+	created.CreatedAt = time.Now()
+	f.apiKeys = append(f.apiKeys, &created)
+	return &created, nil
+}
+
+func (f *fakeAuthRepository) UseApiKey(ctx context.Context, keyHash string) (*dto.ApiKeyDB, error) {
+	for _, key := range f.apiKeys {
+		if key.KeyHash == keyHash && (key.ExpiresAt == nil || key.ExpiresAt.After(time.Now())) {
+			now := time.Now()
+			key.LastUsedAt = &now
+			return key, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeAuthRepository) GetApiKeys(ctx context.Context, userId uuid.UUID) ([]*dto.ApiKeyDB, error) {
+	var keys []*dto.ApiKeyDB
+	for _, key := range slices.Backward(f.apiKeys) {
+		if key.UserId == userId {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (f *fakeAuthRepository) DeleteApiKey(ctx context.Context, userId, keyId uuid.UUID) error {
+	before := len(f.apiKeys)
+	f.apiKeys = slices.DeleteFunc(f.apiKeys, func(key *dto.ApiKeyDB) bool {
+		return key.UserId == userId && key.KeyId == keyId
+	})
+	if len(f.apiKeys) == before {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// addSession stores a session of a refresh token made by the test
+func (f *fakeAuthRepository) addSession(userId uuid.UUID, refreshToken string) {
+	f.sessions = append(f.sessions, &dto.SessionDB{SessionId: uuid.New(), UserId: userId, TokenHash: hashToken(refreshToken),
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: time.Now()})
+}
+
+// userSessions counts the sessions of a user
+func (f *fakeAuthRepository) userSessions(userId uuid.UUID) int {
+	count := 0
+	for _, session := range f.sessions {
+		if session.UserId == userId {
+			count++
+		}
+	}
+	return count
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent chan sentMail
+}
+
+func newFakeMailer() *fakeMailer {
+	return &fakeMailer{sent: make(chan sentMail, 4)}
+}
+
+func (f *fakeMailer) Send(to, subject, body string) error {
+	f.sent <- sentMail{to, subject, body}
+	return nil
+}
+
+// testPasswords are the cheapest params Validate accepts
+var testPasswords = hash.Params{Memory: hash.MinMemory, Iterations: hash.MinIterations, Parallelism: 1}
+
+func newTestAuthService(rep AuthRepository, mailer Mailer) *AuthService {
+	return NewAuthService(rep, mailer, nil, AuthConfig{
+		Secret:               "test-secret",
+		PasswordResetTTL:     time.Hour,
+		PasswordResetURL:     "http://blog.test/reset",
+		VerifyEmailTTL:       time.Hour,
+		VerifyEmailURL:       "http://blog.test/verify-email",
+		VerifyResendInterval: time.Minute,
+		AccessTTL:            15 * time.Minute,
+		RefreshTTL:           24 * time.Hour,
+		Tokens:               jwt.Options{Issuer: "blog", Audience: "blog-api", Leeway: time.Minute},
+		Passwords:            testPasswords,
+	})
+}
+
+var resetTokenPattern = regexp.MustCompile(`\?token=([A-Za-z0-9_-]+)`)
+
+func TestAuthService_PasswordResetFlow(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", PasswordHash: "old"}
+	rep := newFakeAuthRepository(user)
+	rep.addSession(user.UserId, "refresh")
+	mailer := newFakeMailer()
+	s := newTestAuthService(rep, mailer)
+
+	require.NoError(t, s.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{Email: user.Email}))
+
+	var mail sentMail
+	select {
+	case mail = <-mailer.sent:
+	case <-time.After(time.Second):
+		t.Fatal("reset mail was not sent")
+	}
+	assert.Equal(t, user.Email, mail.to)
+	match := resetTokenPattern.FindStringSubmatch(mail.body)
+	require.Len(t, match, 2)
+	token := match[1]
+
+	// only the hash is stored
+	require.Len(t, rep.resets, 1)
+	for stored := range rep.resets {
+		assert.NotEqual(t, token, stored)
+		assert.Equal(t, hashToken(token), stored)
+	}
+
+	require.NoError(t, s.ResetPassword(context.Background(), &dto.ResetPasswordRequest{Token: token, NewPassword: "NewPassword1!"}))
+	ok, err := hash.Compare(user.PasswordHash, "NewPassword1!")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, rep.userSessions(user.UserId), "a reset logs out every device")
+
+	// single use
+	err = s.ResetPassword(context.Background(), &dto.ResetPasswordRequest{Token: token, NewPassword: "Another1!!"})
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+}
+
+func TestAuthService_ForgotPasswordUnknownEmail(t *testing.T) {
+	rep := newFakeAuthRepository()
+	mailer := newFakeMailer()
+	s := newTestAuthService(rep, mailer)
+
+	assert.NoError(t, s.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{Email: "nobody@example.com"}))
+	assert.Empty(t, rep.resets)
+	select {
+	case <-mailer.sent:
+		t.Fatal("mail sent for an unknown email")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAuthService_ResetPasswordUnknownToken(t *testing.T) {
+	s := newTestAuthService(newFakeAuthRepository(), newFakeMailer())
+
+	err := s.ResetPassword(context.Background(), &dto.ResetPasswordRequest{Token: "made-up", NewPassword: "NewPassword1!"})
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+}
+
+func TestAuthService_BecomeAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    types.Role
+		request types.Role
+		wantErr error
+	}{
+		{"reader becomes author", types.Reader, types.Author, nil},
+		{"author cannot change again", types.Author, types.Author, errors.ErrorServiceNoAccess},
+		{"admin cannot demote itself", types.Admin, types.Author, errors.ErrorServiceNoAccess},
+		{"only author can be requested", types.Reader, types.Admin, errors.ErrorRepositoryBadRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: tt.role}
+			rep := newFakeAuthRepository(user)
+			s := newTestAuthService(rep, newFakeMailer())
+			sessionId := uuid.New()
+
+			resp, err := s.BecomeAuthor(context.Background(), &dto.UserDB{UserId: user.UserId, Role: tt.role}, sessionId, &dto.ChangeOwnRoleRequest{Role: tt.request})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.role, user.Role)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, types.Author, resp.Role)
+			assert.Equal(t, types.Author, user.Role)
+
+			authorized, session, err := s.AuthorizeSession(context.Background(), resp.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, user.UserId, authorized.UserId)
+			assert.Equal(t, sessionId, session, "the new token stays in the session")
+		})
+	}
+}
+
+func TestAuthService_AuthorizeUserOtherDeployment(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: types.Reader}
+	rep := newFakeAuthRepository(user)
+	s := newTestAuthService(rep, newFakeMailer())
+
+	staging := jwt.NewAccessToken(user.UserId, s.keys, time.Hour, jwt.Options{Issuer: "blog-staging", Audience: "blog-api"})
+	_, err := s.AuthorizeUser(context.Background(), staging)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+
+	refresh, err := jwt.NewRefreshToken(user.Email, s.keys, time.Hour, jwt.Options{Issuer: "blog", Audience: "staging-api"})
+	require.NoError(t, err)
+	rep.addSession(user.UserId, refresh)
+	_, err = s.RefreshToken(context.Background(), &dto.RefreshRequest{RefreshToken: refresh})
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+
+	own := jwt.NewAccessToken(user.UserId, s.keys, time.Hour, s.cfg.Tokens)
+	authorized, err := s.AuthorizeUser(context.Background(), own)
+	require.NoError(t, err)
+	assert.Equal(t, user.UserId, authorized.UserId)
+}
+
+func TestAuthService_SecretRotation(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: types.Reader}
+	rep := newFakeAuthRepository(user)
+	before := newTestAuthService(rep, newFakeMailer())
+	token := jwt.NewAccessToken(user.UserId, before.keys, time.Hour, before.cfg.Tokens)
+
+	refresh, err := jwt.NewRefreshToken(user.Email, before.keys, time.Hour, before.cfg.Tokens)
+	require.NoError(t, err)
+	rep.addSession(user.UserId, refresh)
+
+	cfg := before.cfg
+	cfg.Secret, cfg.PreviousSecret = "rotated-secret", before.cfg.Secret
+	rotating := NewAuthService(rep, newFakeMailer(), nil, cfg)
+
+	authorized, err := rotating.AuthorizeUser(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, user.UserId, authorized.UserId)
+	_, err = rotating.RefreshToken(context.Background(), &dto.RefreshRequest{RefreshToken: refresh})
+	require.NoError(t, err)
+
+	cfg.PreviousSecret = ""
+	rotated := NewAuthService(rep, newFakeMailer(), nil, cfg)
+
+	_, err = rotated.AuthorizeUser(context.Background(), token)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+	_, err = rotated.RefreshToken(context.Background(), &dto.RefreshRequest{RefreshToken: refresh})
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+}
+
+func TestAuthService_TokenLifetimes(t *testing.T) {
+	rep := newFakeAuthRepository()
+	s := newTestAuthService(rep, newFakeMailer())
+
+	registered, err := s.RegistrateUser(context.Background(), &dto.RegistrateUserRequest{Email: "user@example.com", Password: "password", Role: types.Reader})
+	require.NoError(t, err)
+	assert.Equal(t, int64(15*60), registered.ExpiresIn)
+	require.Len(t, rep.sessions, 1)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), rep.sessions[0].ExpiresAt, time.Minute)
+
+	claims, err := jwt.ValidateToken(registered.AccessToken, s.keys, s.cfg.Tokens)
+	require.NoError(t, err)
+	exp, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), exp.Time, time.Minute)
+
+	refreshed, err := s.RefreshToken(context.Background(), &dto.RefreshRequest{RefreshToken: registered.RefreshToken})
+	require.NoError(t, err)
+	assert.Equal(t, int64(15*60), refreshed.ExpiresIn)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	rep := newFakeAuthRepository()
+	s := newTestAuthService(rep, newFakeMailer())
+
+	registered, err := s.RegistrateUser(context.Background(), &dto.RegistrateUserRequest{Email: "user@example.com", Password: "password", Role: types.Reader})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Logout(context.Background(), registered.RefreshToken))
+	assert.Zero(t, rep.userSessions(registered.Id))
+
+	_, err = s.RefreshToken(context.Background(), &dto.RefreshRequest{RefreshToken: registered.RefreshToken})
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+	// logging out twice is not an error
+	assert.NoError(t, s.Logout(context.Background(), registered.RefreshToken))
+	assert.ErrorIs(t, s.Logout(context.Background(), "not-a-token"), errors.ErrorInvalidToken)
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	laptop := clientctx.WithClient(context.Background(), clientctx.Client{IP: "198.51.100.2", UserAgent: "Firefox"})
+	phone := clientctx.WithClient(context.Background(), clientctx.Client{IP: "203.0.113.7", UserAgent: "Mobile Safari"})
+	login := &dto.LoginUserRequest{Email: "user@example.com", Password: "password"}
+
+	setup := func(t *testing.T, maxSessions int) (*AuthService, *fakeAuthRepository, uuid.UUID) {
+		rep := newFakeAuthRepository()
+		s := newTestAuthService(rep, newFakeMailer())
+		s.cfg.MaxSessions = maxSessions
+		registered, err := s.RegistrateUser(laptop, &dto.RegistrateUserRequest{Email: login.Email, Password: login.Password, Role: types.Reader})
+		require.NoError(t, err)
+		return s, rep, registered.Id
+	}
+	sessionOf := func(t *testing.T, s *AuthService, accessToken string) uuid.UUID {
+		_, sessionId, err := s.AuthorizeSession(context.Background(), accessToken)
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, sessionId)
+		return sessionId
+	}
+
+	t.Run("devices refresh independently", func(t *testing.T) {
+		s, _, userId := setup(t, 0)
+		first, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+		second, err := s.LoginUser(phone, login)
+		require.NoError(t, err)
+
+		refreshed, err := s.RefreshToken(laptop, &dto.RefreshRequest{RefreshToken: first.RefreshToken})
+		require.NoError(t, err)
+		assert.Equal(t, sessionOf(t, s, first.AccessToken), sessionOf(t, s, refreshed.AccessToken), "a refresh stays in its session")
+		_, err = s.RefreshToken(phone, &dto.RefreshRequest{RefreshToken: second.RefreshToken})
+		require.NoError(t, err)
+
+		current := sessionOf(t, s, second.AccessToken)
+		sessions, err := s.Sessions(phone, userId, current)
+		require.NoError(t, err)
+		require.Len(t, sessions.Sessions, 3, "registering logs in too")
+		assert.Equal(t, current, sessions.Sessions[0].SessionId)
+		assert.True(t, sessions.Sessions[0].Current)
+		assert.Equal(t, "Mobile Safari", sessions.Sessions[0].UserAgent)
+		assert.Equal(t, "203.0.113.7", sessions.Sessions[0].IP)
+		assert.False(t, sessions.Sessions[1].Current)
+		assert.Equal(t, "Firefox", sessions.Sessions[1].UserAgent)
+	})
+
+	t.Run("cap ends the oldest session", func(t *testing.T) {
+		s, rep, userId := setup(t, 2)
+		registered := rep.sessions[0]
+		_, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+		_, err = s.LoginUser(phone, login)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, rep.userSessions(userId))
+		assert.NotContains(t, rep.sessions, registered)
+	})
+
+	t.Run("revoke a session", func(t *testing.T) {
+		s, _, userId := setup(t, 0)
+		first, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+		second, err := s.LoginUser(phone, login)
+		require.NoError(t, err)
+
+		require.NoError(t, s.RevokeSession(laptop, userId, sessionOf(t, s, second.AccessToken)))
+		_, err = s.RefreshToken(phone, &dto.RefreshRequest{RefreshToken: second.RefreshToken})
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken, "the revoked device has to log in again")
+		_, err = s.RefreshToken(laptop, &dto.RefreshRequest{RefreshToken: first.RefreshToken})
+		assert.NoError(t, err)
+
+		err = s.RevokeSession(laptop, userId, sessionOf(t, s, second.AccessToken))
+		assert.ErrorIs(t, err, errors.ErrorServiceSessionNotFound)
+	})
+
+	t.Run("sessions of other users are not found", func(t *testing.T) {
+		s, _, _ := setup(t, 0)
+		first, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+
+		err = s.RevokeSession(phone, uuid.New(), sessionOf(t, s, first.AccessToken))
+		assert.ErrorIs(t, err, errors.ErrorServiceSessionNotFound)
+		_, err = s.RefreshToken(laptop, &dto.RefreshRequest{RefreshToken: first.RefreshToken})
+		assert.NoError(t, err)
+	})
+
+	t.Run("revoke other sessions", func(t *testing.T) {
+		s, rep, userId := setup(t, 0)
+		first, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+		second, err := s.LoginUser(phone, login)
+		require.NoError(t, err)
+
+		resp, err := s.RevokeOtherSessions(phone, userId, sessionOf(t, s, second.AccessToken))
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Revoked)
+		assert.Equal(t, 1, rep.userSessions(userId))
+		_, err = s.RefreshToken(laptop, &dto.RefreshRequest{RefreshToken: first.RefreshToken})
+		assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+		_, err = s.RefreshToken(phone, &dto.RefreshRequest{RefreshToken: second.RefreshToken})
+		assert.NoError(t, err)
+
+		// a token from before sessions keeps none
+		resp, err = s.RevokeOtherSessions(phone, userId, uuid.Nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Revoked)
+		assert.Zero(t, rep.userSessions(userId))
+	})
+
+	t.Run("logout ends only its session", func(t *testing.T) {
+		s, rep, userId := setup(t, 0)
+		first, err := s.LoginUser(laptop, login)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Logout(laptop, first.RefreshToken))
+		assert.Equal(t, 1, rep.userSessions(userId))
+	})
+}
+
+func TestAuthService_LoginFailuresAlike(t *testing.T) {
+	rep := newFakeAuthRepository()
+	s := newTestAuthService(rep, newFakeMailer())
+	_, err := s.RegistrateUser(context.Background(), &dto.RegistrateUserRequest{Email: "user@example.com", Password: "password", Role: types.Reader})
+	require.NoError(t, err)
+
+	_, unknown := s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: "nobody@example.com", Password: "password"})
+	_, wrong := s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: "user@example.com", Password: "wrong"})
+	assert.ErrorIs(t, unknown, errors.ErrorServiceInvalidCredentials)
+	assert.Equal(t, unknown, wrong)
+
+	login, err := s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: "user@example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+}
+
+// failingEmailRepository fails every lookup by email like a database that is down
+type failingEmailRepository struct {
+	*fakeAuthRepository
+}
+
+func (f failingEmailRepository) GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error) {
+	return nil, sql.ErrConnDone
+}
+
+func TestAuthService_LoginDatabaseError(t *testing.T) {
+	s := newTestAuthService(failingEmailRepository{newFakeAuthRepository()}, newFakeMailer())
+
+	_, err := s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: "user@example.com", Password: "password"})
+	assert.ErrorIs(t, err, sql.ErrConnDone, "an outage is not reported as wrong credentials")
+}
+
+// TestAuthService_LoginUnknownEmailComparesPassword times a login with an unknown email against one password
+// compare, without the compare against the dummy hash it would take microseconds
+func TestAuthService_LoginUnknownEmailComparesPassword(t *testing.T) {
+	s := newTestAuthService(newFakeAuthRepository(), newFakeMailer())
+	assert.False(t, s.cfg.Passwords.NeedsRehash(s.dummyHash), "the dummy hash must cost what a stored hash costs")
+
+	fastest := func(fn func()) time.Duration {
+		best := time.Duration(math.MaxInt64)
+		for range 3 {
+			start := time.Now()
+			fn()
+			best = min(best, time.Since(start))
+		}
+		return best
+	}
+	compare := fastest(func() { hash.Compare(s.dummyHash, "password") })
+
+	login := fastest(func() {
+		s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: "nobody@example.com", Password: "password"})
+	})
+	assert.GreaterOrEqual(t, login, compare/2, "login took %s, one compare %s", login, compare)
+}
+
+func TestAuthService_LoginRehashesPassword(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", PasswordHash: string(legacy), Role: types.Reader}
+	rep := newFakeAuthRepository(user)
+	s := newTestAuthService(rep, newFakeMailer())
+
+	_, err = s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: user.Email, Password: "wrong"})
+	require.ErrorIs(t, err, errors.ErrorServiceInvalidCredentials)
+	assert.Equal(t, string(legacy), user.PasswordHash, "a failed login changes nothing")
+
+	_, err = s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	rehashed := user.PasswordHash
+	assert.True(t, strings.HasPrefix(rehashed, "$argon2id$"), rehashed)
+	assert.False(t, testPasswords.NeedsRehash(rehashed))
+
+	_, err = s.LoginUser(context.Background(), &dto.LoginUserRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, rehashed, user.PasswordHash, "a current hash is kept")
+
+	// raising the params replaces the hash again on the next login
+	stronger := newTestAuthService(rep, newFakeMailer())
+	stronger.cfg.Passwords.Iterations++
+	_, err = stronger.LoginUser(context.Background(), &dto.LoginUserRequest{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	assert.NotEqual(t, rehashed, user.PasswordHash)
+	assert.False(t, stronger.cfg.Passwords.NeedsRehash(user.PasswordHash))
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+type BackupRepository interface {
+	ExportUsers(ctx context.Context, fn func(*dto.UserRecord) error) error
+	ExportPosts(ctx context.Context, since time.Time, fn func(*dto.PostRecord) error) error
+	ExportImages(ctx context.Context, since time.Time, fn func(*dto.ImageRecord) error) error
+	RestoreUser(ctx context.Context, user *dto.UserRecord) error
+	RestorePost(ctx context.Context, post *dto.PostRecord) error
+	RestoreImage(ctx context.Context, image *dto.ImageRecord) error
+	UserExists(ctx context.Context, id uuid.UUID) (bool, error)
+	PostExists(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type BackupStorageRepository interface {
+	GetImage(objectName string) (io.ReadCloser, string, error)
+	ImageExists(objectName string) (bool, error)
+	PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error)
+}
+
+// BackupArchive is where backup files live: a local directory or a bucket
+type BackupArchive interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+}
+
+const (
+	manifestName    = "manifest.json"
+	manifestVersion = 1
+	usersFile       = "users.jsonl"
+	postsFile       = "posts.jsonl"
+	imagesFile      = "images.jsonl"
+	objectsDir      = "objects"
+)
+
+type BackupService struct {
+	rep     BackupRepository
+	stor    BackupStorageRepository
+	archive BackupArchive
+}
+
+func NewBackupService(rep BackupRepository, stor BackupStorageRepository, archive BackupArchive) *BackupService {
+	return &BackupService{rep, stor, archive}
+}
+
+// Backup writes a new run into the archive. An incremental run only contains posts and images
+// changed after the watermarks of the previous run; users are always exported in full.
+// Deletions are not tracked, restoring a chain of runs never removes rows.
+func (s *BackupService) Backup(ctx context.Context, incremental bool) (*dto.BackupSummary, error) {
+	manifest, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	run := dto.BackupRun{
+		Id:          now.Format("20060102T150405.000000000Z"),
+		Incremental: incremental && len(manifest.Runs) > 0,
+		StartedAt:   now,
+	}
+	if run.Incremental {
+		last := manifest.Runs[len(manifest.Runs)-1]
+		run.PostsWatermark = last.PostsWatermark
+		run.ImageWatermark = last.ImageWatermark
+	}
+
+	err = s.writeLines(&run, usersFile, func(emit func(json.Marshaler) error) error {
+		return s.rep.ExportUsers(ctx, func(user *dto.UserRecord) error {
+			run.Users++
+			return emit(user)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	postsSince := run.PostsWatermark
+	err = s.writeLines(&run, postsFile, func(emit func(json.Marshaler) error) error {
+		return s.rep.ExportPosts(ctx, postsSince, func(post *dto.PostRecord) error {
+			run.Posts++
+			if post.UpdatedAt.After(run.PostsWatermark) {
+				run.PostsWatermark = post.UpdatedAt
+			}
+			return emit(post)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imagesSince := run.ImageWatermark
+	err = s.writeLines(&run, imagesFile, func(emit func(json.Marshaler) error) error {
+		return s.rep.ExportImages(ctx, imagesSince, func(image *dto.ImageRecord) error {
+			run.Images++
+			// a replaced image is exported again with its new object
+			if image.UpdatedAt.After(run.ImageWatermark) {
+				run.ImageWatermark = image.UpdatedAt
+			}
+			image.ObjectName = objectName(image.ImageUrl)
+			if err := s.copyObjectToArchive(&run, image); err != nil {
+				return err
+			}
+			return emit(image)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the manifest goes last so an interrupted run is never referenced
+	manifest.Runs = append(manifest.Runs, run)
+	if err := s.writeManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	return &dto.BackupSummary{
+		Run:     run.Id,
+		Users:   run.Users,
+		Posts:   run.Posts,
+		Images:  run.Images,
+		Objects: run.Objects,
+		Bytes:   run.Bytes,
+	}, nil
+}
+
+// Restore replays every run of the archive in order. References are validated
+// against the archive and the database before anything is written.
+func (s *BackupService) Restore(ctx context.Context) (*dto.BackupSummary, error) {
+	manifest, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Runs) == 0 {
+		return nil, fmt.Errorf("%s: %w", manifestName, os.ErrNotExist)
+	}
+
+	if err := s.validate(ctx, manifest); err != nil {
+		return nil, err
+	}
+
+	summary := &dto.BackupSummary{}
+	for _, run := range manifest.Runs {
+		err := s.readLines(summary, path.Join(run.Id, usersFile), func(line []byte) error {
+			user := &dto.UserRecord{}
+			if err := json.Unmarshal(line, user); err != nil {
+				return err
+			}
+			summary.Users++
+			return s.rep.RestoreUser(ctx, user)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.readLines(summary, path.Join(run.Id, postsFile), func(line []byte) error {
+			post := &dto.PostRecord{}
+			if err := json.Unmarshal(line, post); err != nil {
+				return err
+			}
+			summary.Posts++
+			return s.rep.RestorePost(ctx, post)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.readLines(summary, path.Join(run.Id, imagesFile), func(line []byte) error {
+			image := &dto.ImageRecord{}
+			if err := json.Unmarshal(line, image); err != nil {
+				return err
+			}
+			if err := s.copyObjectFromArchive(ctx, summary, run.Id, image); err != nil {
+				return err
+			}
+			summary.Images++
+			return s.rep.RestoreImage(ctx, image)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *BackupService) validate(ctx context.Context, manifest *dto.BackupManifest) error {
+	users := map[uuid.UUID]bool{}
+	posts := map[uuid.UUID]bool{}
+	authorRefs := map[uuid.UUID]uuid.UUID{}
+	postRefs := map[uuid.UUID]uuid.UUID{}
+	noop := &dto.BackupSummary{}
+
+	for _, run := range manifest.Runs {
+		err := s.readLines(noop, path.Join(run.Id, usersFile), func(line []byte) error {
+			user := &dto.UserRecord{}
+			if err := json.Unmarshal(line, user); err != nil {
+				return err
+			}
+			users[user.UserId] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.readLines(noop, path.Join(run.Id, postsFile), func(line []byte) error {
+			post := &dto.PostRecord{}
+			if err := json.Unmarshal(line, post); err != nil {
+				return err
+			}
+			posts[post.PostId] = true
+			authorRefs[post.PostId] = post.AuthorId
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.readLines(noop, path.Join(run.Id, imagesFile), func(line []byte) error {
+			image := &dto.ImageRecord{}
+			if err := json.Unmarshal(line, image); err != nil {
+				return err
+			}
+			postRefs[image.ImageId] = image.PostId
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for postId, authorId := range authorRefs {
+		if users[authorId] {
+			continue
+		}
+		exists, err := s.rep.UserExists(ctx, authorId)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: post %s references missing user %s", errors.ErrorServiceBackupInconsistent, postId, authorId)
+		}
+		users[authorId] = true
+	}
+
+	for imageId, postId := range postRefs {
+		if posts[postId] {
+			continue
+		}
+		exists, err := s.rep.PostExists(ctx, postId)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: image %s references missing post %s", errors.ErrorServiceBackupInconsistent, imageId, postId)
+		}
+		posts[postId] = true
+	}
+
+	return nil
+}
+
+func (s *BackupService) copyObjectToArchive(run *dto.BackupRun, image *dto.ImageRecord) error {
+	obj, contentType, err := s.stor.GetImage(image.ObjectName)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+	image.ContentType = contentType
+
+	w, err := s.archive.Create(path.Join(run.Id, objectsDir, image.ObjectName))
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(w, obj)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	run.Objects++
+	run.Bytes += n
+	return nil
+}
+
+func (s *BackupService) copyObjectFromArchive(ctx context.Context, summary *dto.BackupSummary, runId string, image *dto.ImageRecord) error {
+	exists, err := s.stor.ImageExists(image.ObjectName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	r, err := s.archive.Open(path.Join(runId, objectsDir, image.ObjectName))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	counter := &countingReader{r: r}
+	if _, err := s.stor.PutImage(ctx, image.ObjectName, counter, -1, image.ContentType); err != nil {
+		return err
+	}
+	summary.Objects++
+	summary.Bytes += counter.n
+	return nil
+}
+
+func (s *BackupService) writeLines(run *dto.BackupRun, name string, export func(emit func(json.Marshaler) error) error) error {
+	f, err := s.archive.Create(path.Join(run.Id, name))
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	err = export(func(record json.Marshaler) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		run.Bytes += int64(len(data)) + 1
+		w.Write(data)
+		return w.WriteByte('\n')
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *BackupService) readLines(summary *dto.BackupSummary, name string, fn func(line []byte) error) error {
+	f, err := s.archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		summary.Bytes += int64(len(line))
+		if len(line) > 1 {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *BackupService) readManifest() (*dto.BackupManifest, error) {
+	manifest := &dto.BackupManifest{Version: manifestVersion}
+
+	f, err := s.archive.Open(manifestName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := json.UnmarshalFromReader(f, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *BackupService) writeManifest(manifest *dto.BackupManifest) error {
+	f, err := s.archive.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := json.MarshalToWriter(manifest, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
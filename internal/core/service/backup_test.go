@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakeBackupRepository struct {
+	users  map[uuid.UUID]*dto.UserRecord
+	posts  map[uuid.UUID]*dto.PostRecord
+	images map[uuid.UUID]*dto.ImageRecord
+	writes int
+}
+
+func newFakeBackupRepository() *fakeBackupRepository {
+	return &fakeBackupRepository{
+		users:  map[uuid.UUID]*dto.UserRecord{},
+		posts:  map[uuid.UUID]*dto.PostRecord{},
+		images: map[uuid.UUID]*dto.ImageRecord{},
+	}
+}
+
+func (f *fakeBackupRepository) ExportUsers(ctx context.Context, fn func(*dto.UserRecord) error) error {
+	for _, user := range f.users {
+		copied := *user
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeBackupRepository) ExportPosts(ctx context.Context, since time.Time, fn func(*dto.PostRecord) error) error {
+	posts := make([]*dto.PostRecord, 0, len(f.posts))
+	for _, post := range f.posts {
+		if post.UpdatedAt.After(since) {
+			posts = append(posts, post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].UpdatedAt.Before(posts[j].UpdatedAt) })
+	for _, post := range posts {
+		copied := *post
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeBackupRepository) ExportImages(ctx context.Context, since time.Time, fn func(*dto.ImageRecord) error) error {
+	for _, image := range f.images {
+		if !image.UpdatedAt.After(since) {
+			continue
+		}
+		copied := *image
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeBackupRepository) RestoreUser(ctx context.Context, user *dto.UserRecord) error {
+	f.writes++
+	f.users[user.UserId] = user
+	return nil
+}
+
+func (f *fakeBackupRepository) RestorePost(ctx context.Context, post *dto.PostRecord) error {
+	f.writes++
+	f.posts[post.PostId] = post
+	return nil
+}
+
+func (f *fakeBackupRepository) RestoreImage(ctx context.Context, image *dto.ImageRecord) error {
+	f.writes++
+	f.images[image.ImageId] = image
+	return nil
+}
+
+func (f *fakeBackupRepository) UserExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, ok := f.users[id]
+	return ok, nil
+}
+
+func (f *fakeBackupRepository) PostExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, ok := f.posts[id]
+	return ok, nil
+}
+
+type fakeBackupStorage struct {
+	objects map[string][]byte
+}
+
+func (f *fakeBackupStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	return io.NopCloser(bytes.NewReader(f.objects[objectName])), "image/png", nil
+}
+
+func (f *fakeBackupStorage) ImageExists(objectName string) (bool, error) {
+	_, ok := f.objects[objectName]
+	return ok, nil
+}
+
+func (f *fakeBackupStorage) PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	f.objects[objectName] = data
+	return "/images/" + objectName, nil
+}
+
+func seedBackupSource(t *testing.T) (*fakeBackupRepository, *fakeBackupStorage) {
+	t.Helper()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rep := newFakeBackupRepository()
+	stor := &fakeBackupStorage{objects: map[string][]byte{}}
+
+	user := &dto.UserRecord{UserId: uuid.New(), Email: "author@example.com", PasswordHash: "hash", Role: types.Author}
+	rep.users[user.UserId] = user
+
+	post := &dto.PostRecord{PostId: uuid.New(), AuthorId: user.UserId, IdempotencyKey: "k1", Title: "t", Content: "c",
+		Status: types.Published, CreatedAt: base, UpdatedAt: base}
+	rep.posts[post.PostId] = post
+
+	imageId := uuid.New()
+	rep.images[imageId] = &dto.ImageRecord{ImageId: imageId, PostId: post.PostId, ImageUrl: "/images/" + imageId.String(), CreatedAt: base, UpdatedAt: base}
+	stor.objects[imageId.String()] = []byte("png-bytes")
+
+	return rep, stor
+}
+
+func TestBackupService_FullAndIncremental(t *testing.T) {
+	rep, stor := seedBackupSource(t)
+	archive, err := repository.NewDirArchive(t.TempDir())
+	require.NoError(t, err)
+	s := NewBackupService(rep, stor, archive)
+
+	full, err := s.Backup(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, full.Users)
+	assert.Equal(t, 1, full.Posts)
+	assert.Equal(t, 1, full.Images)
+	assert.Equal(t, 1, full.Objects)
+	assert.Greater(t, full.Bytes, int64(len("png-bytes")))
+
+	var authorId uuid.UUID
+	for id := range rep.users {
+		authorId = id
+	}
+	newPost := &dto.PostRecord{PostId: uuid.New(), AuthorId: authorId, IdempotencyKey: "k2", Title: "t2", Content: "c2",
+		Status: types.Draft, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	rep.posts[newPost.PostId] = newPost
+
+	incremental, err := s.Backup(context.Background(), true)
+	require.NoError(t, err)
+	assert.NotEqual(t, full.Run, incremental.Run)
+	assert.Equal(t, 1, incremental.Users)
+	assert.Equal(t, 1, incremental.Posts)
+	assert.Equal(t, 0, incremental.Images)
+
+	for _, image := range rep.images {
+		image.UpdatedAt = time.Now()
+	}
+	replaced, err := s.Backup(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replaced.Images, "a replaced image is exported again")
+	again, err := s.Backup(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Images)
+
+	manifest, err := s.readManifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Runs, 4)
+	assert.False(t, manifest.Runs[0].Incremental)
+	assert.True(t, manifest.Runs[1].Incremental)
+}
+
+func TestBackupService_RestoreIsIdempotent(t *testing.T) {
+	rep, stor := seedBackupSource(t)
+	archive, err := repository.NewDirArchive(t.TempDir())
+	require.NoError(t, err)
+	_, err = NewBackupService(rep, stor, archive).Backup(context.Background(), false)
+	require.NoError(t, err)
+
+	target := newFakeBackupRepository()
+	targetStor := &fakeBackupStorage{objects: map[string][]byte{}}
+	s := NewBackupService(target, targetStor, archive)
+
+	first, err := s.Restore(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Users)
+	assert.Equal(t, 1, first.Posts)
+	assert.Equal(t, 1, first.Images)
+	assert.Equal(t, 1, first.Objects)
+	assert.Equal(t, stor.objects, targetStor.objects)
+
+	second, err := s.Restore(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, second.Objects)
+	assert.Len(t, target.users, 1)
+	assert.Len(t, target.posts, 1)
+	assert.Len(t, target.images, 1)
+}
+
+func TestBackupService_RestoreRejectsDanglingReferences(t *testing.T) {
+	rep, stor := seedBackupSource(t)
+	for id, image := range rep.images {
+		image.PostId = uuid.New()
+		rep.images[id] = image
+	}
+	archive, err := repository.NewDirArchive(t.TempDir())
+	require.NoError(t, err)
+	_, err = NewBackupService(rep, stor, archive).Backup(context.Background(), false)
+	require.NoError(t, err)
+
+	target := newFakeBackupRepository()
+	_, err = NewBackupService(target, &fakeBackupStorage{objects: map[string][]byte{}}, archive).Restore(context.Background())
+	assert.ErrorIs(t, err, errors.ErrorServiceBackupInconsistent)
+	assert.Zero(t, target.writes)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/markdown"
+)
+
+// excerptLength is the most characters of an excerpt, the requests allow authors no longer ones
+const excerptLength = 300
+
+// MarkdownRenderer turns the Markdown of a post into sanitized HTML
+type MarkdownRenderer interface {
+	Render(source string) (string, error)
+}
+
+// excerptOf returns the excerpt the author wrote, or else the start of the content as plain text
+func excerptOf(post *dto.PostDB) string {
+	if post.Excerpt != nil {
+		return *post.Excerpt
+	}
+	return markdown.Excerpt(post.Content, excerptLength)
+}
+
+// ContentRenderer renders the content of posts and keeps the HTML of up to size posts. An entry is good
+// for as long as its post keeps the updated_at it was rendered at, so listings do not render again and
+// an edit is never served stale.
+type ContentRenderer struct {
+	markdown MarkdownRenderer
+	size     int
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]renderedContent
+}
+
+type renderedContent struct {
+	updatedAt time.Time
+	html      string
+}
+
+// NewContentRenderer renders with markdown, a size of zero renders every time
+func NewContentRenderer(markdown MarkdownRenderer, size int) *ContentRenderer {
+	return &ContentRenderer{markdown: markdown, size: size, entries: map[uuid.UUID]renderedContent{}}
+}
+
+// Render returns the HTML of the content of post, a nil renderer returns none
+func (r *ContentRenderer) Render(post *dto.PostDB) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+
+	r.mu.Lock()
+	entry, ok := r.entries[post.PostId]
+	r.mu.Unlock()
+	if ok && entry.updatedAt.Equal(post.UpdatedAt) {
+		return entry.html, nil
+	}
+
+	html, err := r.markdown.Render(post.Content)
+	if err != nil {
+		return "", err
+	}
+	if r.size > 0 {
+		r.store(post.PostId, renderedContent{post.UpdatedAt, html})
+	}
+	return html, nil
+}
+
+func (r *ContentRenderer) store(postId uuid.UUID, entry renderedContent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// a post rendered before only gets its entry replaced, anything else makes room first
+	if _, ok := r.entries[postId]; !ok {
+		for key := range r.entries {
+			if len(r.entries) < r.size {
+				break
+			}
+			delete(r.entries, key)
+		}
+	}
+	r.entries[postId] = entry
+}
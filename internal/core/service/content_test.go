@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/pkg/markdown"
+)
+
+// countingMarkdown renders with the real renderer and counts how often it had to
+type countingMarkdown struct {
+	renders int
+}
+
+func (m *countingMarkdown) Render(source string) (string, error) {
+	m.renders++
+	return markdown.New(nil).Render(source)
+}
+
+func TestContentRenderer(t *testing.T) {
+	md := &countingMarkdown{}
+	content := service.NewContentRenderer(md, 2)
+	post := &dto.PostDB{PostId: uuid.New(), Content: "*first*", UpdatedAt: time.Now()}
+
+	html, err := content.Render(post)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><em>first</em></p>\n", html)
+	_, err = content.Render(post)
+	require.NoError(t, err)
+	assert.Equal(t, 1, md.renders, "the same version is rendered once")
+
+	post.Content = "*edited*"
+	post.UpdatedAt = post.UpdatedAt.Add(time.Second)
+	html, err = content.Render(post)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><em>edited</em></p>\n", html, "an edit is never served stale")
+	assert.Equal(t, 2, md.renders)
+
+	for range 3 {
+		_, err = content.Render(&dto.PostDB{PostId: uuid.New(), Content: "other"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 5, md.renders)
+
+	var none *service.ContentRenderer
+	html, err = none.Render(post)
+	require.NoError(t, err)
+	assert.Empty(t, html)
+}
+
+func TestContentRenderer_NoCache(t *testing.T) {
+	md := &countingMarkdown{}
+	content := service.NewContentRenderer(md, 0)
+	post := &dto.PostDB{PostId: uuid.New(), Content: "text", UpdatedAt: time.Now()}
+
+	for range 2 {
+		_, err := content.Render(post)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, md.renders)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// VerificationRepository flips the flag authors need to publish
+type VerificationRepository interface {
+	// VerifyEmail is sql.ErrNoRows when the user is gone or their email is no longer the one verified
+	VerifyEmail(ctx context.Context, id uuid.UUID, email string) error
+}
+
+// resendThrottle remembers when each user was last mailed a verification link
+type resendThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	sent     map[uuid.UUID]time.Time
+}
+
+func newResendThrottle(interval time.Duration) *resendThrottle {
+	return &resendThrottle{interval: interval, sent: map[uuid.UUID]time.Time{}}
+}
+
+// allow records a mail to the user at now, or returns how long the user has to wait for the next one
+func (t *resendThrottle) allow(id uuid.UUID, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for user, at := range t.sent {
+		if now.Sub(at) >= t.interval {
+			delete(t.sent, user)
+		}
+	}
+	if at, ok := t.sent[id]; ok {
+		return t.interval - now.Sub(at)
+	}
+	t.sent[id] = now
+	return 0
+}
+
+// sendVerification mails the user a link proving they read mail sent to their address. Failures are
+// only logged, the user can ask for another link.
+func (s *AuthService) sendVerification(user *dto.UserDB) {
+	token, err := jwt.NewEmailToken(user.UserId, user.Email, s.keys, s.cfg.VerifyEmailTTL, s.cfg.Tokens)
+	if err != nil {
+		slog.Error("verification token", slog.String("user_id", user.UserId.String()), slog.String("error", err.Error()))
+		return
+	}
+
+	body := fmt.Sprintf("Use the link below to verify your email address, it expires in %s:\n\n%s?token=%s\n", s.cfg.VerifyEmailTTL, s.cfg.VerifyEmailURL, token)
+	go func() {
+		if err := s.mailer.Send(user.Email, "Verify your email address", body); err != nil {
+			slog.Error("verification mail failed", slog.String("user_id", user.UserId.String()), slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// VerifyEmail marks the email of the link verified, following a link twice is no error.
+// An expired or forged token, or one sent to an email the user no longer has, is errors.ErrorInvalidToken.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	userId, email, err := jwt.ValidateEmailToken(token, s.keys, s.cfg.Tokens)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rep.VerifyEmail(ctx, userId, email); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.ErrorInvalidToken
+		}
+		return err
+	}
+	s.users.Forget(userId)
+	audit(ctx, s.recorder, types.AuditVerifyEmail, userId, uuid.Nil)
+	return nil
+}
+
+// ResendVerification mails another link, at most one per VerifyResendInterval and user counting the one
+// of the registration. A throttled user gets ErrorServiceResendTooSoon and how long to wait.
+func (s *AuthService) ResendVerification(ctx context.Context, userId uuid.UUID) (time.Duration, error) {
+	user, err := s.rep.GetUserById(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	if user.EmailVerified {
+		return 0, errors.ErrorServiceEmailVerified
+	}
+	if wait := s.resends.allow(userId, time.Now()); wait > 0 {
+		return wait, errors.ErrorServiceResendTooSoon
+	}
+	s.sendVerification(user)
+	return 0, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+var verifyTokenPattern = regexp.MustCompile(`verify-email\?token=([A-Za-z0-9_.-]+)`)
+
+// verificationMail waits for the next mail and returns the token of its link
+func verificationMail(t *testing.T, mailer *fakeMailer) string {
+	t.Helper()
+	select {
+	case mail := <-mailer.sent:
+		match := verifyTokenPattern.FindStringSubmatch(mail.body)
+		require.Len(t, match, 2, mail.body)
+		return match[1]
+	case <-time.After(time.Second):
+		t.Fatal("verification mail was not sent")
+		return ""
+	}
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	rep := newFakeAuthRepository()
+	mailer := newFakeMailer()
+	s := newTestAuthService(rep, mailer)
+
+	registered, err := s.RegistrateUser(ctx, &dto.RegistrateUserRequest{Email: "author@example.com", Password: "Password123!", Role: types.Author})
+	require.NoError(t, err)
+	assert.False(t, rep.users[registered.Id].EmailVerified)
+	token := verificationMail(t, mailer)
+
+	require.NoError(t, s.VerifyEmail(ctx, token))
+	assert.True(t, rep.users[registered.Id].EmailVerified)
+	assert.NoError(t, s.VerifyEmail(ctx, token), "following the link twice is fine")
+
+	t.Run("forged", func(t *testing.T) {
+		assert.ErrorIs(t, s.VerifyEmail(ctx, token+"x"), errors.ErrorInvalidToken)
+		access, _ := s.accessToken(registered.Id, uuid.New())
+		assert.ErrorIs(t, s.VerifyEmail(ctx, access), errors.ErrorInvalidToken, "an access token verifies nothing")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, err := jwt.NewEmailToken(registered.Id, "author@example.com", s.keys, -time.Hour, s.cfg.Tokens)
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.VerifyEmail(ctx, expired), errors.ErrorInvalidToken)
+	})
+
+	t.Run("sent to another email", func(t *testing.T) {
+		other, err := jwt.NewEmailToken(registered.Id, "old@example.com", s.keys, time.Hour, s.cfg.Tokens)
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.VerifyEmail(ctx, other), errors.ErrorInvalidToken)
+	})
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	ctx := context.Background()
+	rep := newFakeAuthRepository()
+	mailer := newFakeMailer()
+	s := newTestAuthService(rep, mailer)
+
+	registered, err := s.RegistrateUser(ctx, &dto.RegistrateUserRequest{Email: "author@example.com", Password: "Password123!", Role: types.Author})
+	require.NoError(t, err)
+	verificationMail(t, mailer)
+
+	wait, err := s.ResendVerification(ctx, registered.Id)
+	assert.ErrorIs(t, err, errors.ErrorServiceResendTooSoon, "the registration mail counts")
+	assert.True(t, wait > 0 && wait <= time.Minute, wait)
+	select {
+	case <-mailer.sent:
+		t.Fatal("throttled resend sent a mail")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// another user is not throttled by the first one
+	other := &dto.UserDB{UserId: uuid.New(), Email: "other@example.com", Role: types.Author}
+	rep.users[other.UserId] = other
+	_, err = s.ResendVerification(ctx, other.UserId)
+	require.NoError(t, err)
+	require.NoError(t, s.VerifyEmail(ctx, verificationMail(t, mailer)))
+
+	_, err = s.ResendVerification(ctx, other.UserId)
+	assert.ErrorIs(t, err, errors.ErrorServiceEmailVerified)
+}
+
+func TestResendThrottle(t *testing.T) {
+	throttle := newResendThrottle(time.Minute)
+	id := uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Zero(t, throttle.allow(id, now))
+	assert.Equal(t, 40*time.Second, throttle.allow(id, now.Add(20*time.Second)))
+	assert.Zero(t, throttle.allow(uuid.New(), now.Add(20*time.Second)))
+	assert.Zero(t, throttle.allow(id, now.Add(time.Minute)), "allowed again after the interval")
+	assert.Len(t, throttle.sent, 2, "users past the interval are forgotten")
+
+	off := newResendThrottle(0)
+	assert.Zero(t, off.allow(id, now))
+	assert.Zero(t, off.allow(id, now))
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"gopkg.in/yaml.v3"
+)
+
+// exportNameRunes caps the part of an archived post name taken from its title
+const exportNameRunes = 60
+
+// frontMatter heads the Markdown file of a post in an export archive
+type frontMatter struct {
+	PostId    uuid.UUID          `yaml:"post_id"`
+	Title     string             `yaml:"title"`
+	Status    string             `yaml:"status"`
+	Excerpt   string             `yaml:"excerpt,omitempty"`
+	Tags      []string           `yaml:"tags,flow"`
+	PublishAt *time.Time         `yaml:"publish_at,omitempty"`
+	CreatedAt time.Time          `yaml:"created_at"`
+	UpdatedAt time.Time          `yaml:"updated_at"`
+	Images    []frontMatterImage `yaml:"images,omitempty"`
+}
+
+// frontMatterImage is an image of an archived post, File is left out when its object is missing from the storage
+type frontMatterImage struct {
+	File    string `yaml:"file,omitempty"`
+	Url     string `yaml:"url"`
+	Caption string `yaml:"caption,omitempty"`
+}
+
+// ExportPosts writes every live post of the author to w as the JSON document dto.PostsExport, one post
+// at a time. Nothing is written before the first post has been read.
+func (s *PosterService) ExportPosts(ctx context.Context, authorId uuid.UUID, w io.Writer) error {
+	started := false
+	start := func() error {
+		started = true
+		_, err := fmt.Fprintf(w, `{"author_id":%q,"exported_at":%q,"posts":[`, authorId, time.Now().UTC().Format(time.RFC3339Nano))
+		return err
+	}
+
+	err := s.rep.ExportAuthorPosts(ctx, authorId, func(post *dto.PostDB, images []*dto.ImageDB) error {
+		separator := ","
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+			separator = ""
+		}
+		exported, err := exportedPost(s.links, post, images)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		_, err = json.MarshalToWriter(exported, w)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !started {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+func exportedPost(links *ImageLinks, post *dto.PostDB, images []*dto.ImageDB) (*dto.ExportedPost, error) {
+	imagesRes, err := links.imagesResponse(images)
+	if err != nil {
+		return nil, err
+	}
+	exported := &dto.ExportedPost{
+		PostId:    post.PostId,
+		Title:     post.Title,
+		Content:   post.Content,
+		Status:    post.Status,
+		Tags:      []string(post.Tags),
+		PublishAt: post.PublishAt,
+		CreatedAt: post.CreatedAt,
+		UpdatedAt: post.UpdatedAt,
+		Images:    imagesRes.Images,
+	}
+	if exported.Tags == nil {
+		exported.Tags = []string{}
+	}
+	if post.Excerpt != nil {
+		exported.Excerpt = *post.Excerpt
+	}
+	return exported, nil
+}
+
+// ExportPostsArchive writes every live post of the author to w as a zip archive. A post is a directory
+// named by its date and title holding index.md, its content behind a YAML front matter, and the files
+// of its images under images/. Objects are copied from the storage one by one, none is held in memory.
+func (s *PosterService) ExportPostsArchive(ctx context.Context, authorId uuid.UUID, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	err := s.rep.ExportAuthorPosts(ctx, authorId, func(post *dto.PostDB, images []*dto.ImageDB) error {
+		return s.archivePost(ctx, archive, post, images)
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func (s *PosterService) archivePost(ctx context.Context, archive *zip.Writer, post *dto.PostDB, images []*dto.ImageDB) error {
+	dir := exportDir(post)
+	matter := frontMatter{
+		PostId:    post.PostId,
+		Title:     post.Title,
+		Status:    string(post.Status),
+		Tags:      []string(post.Tags),
+		PublishAt: post.PublishAt,
+		CreatedAt: post.CreatedAt,
+		UpdatedAt: post.UpdatedAt,
+	}
+	if post.Excerpt != nil {
+		matter.Excerpt = *post.Excerpt
+	}
+	if matter.Tags == nil {
+		matter.Tags = []string{}
+	}
+
+	var stored []*dto.ImageDB
+	for _, image := range images {
+		link, err := s.links.ImageLink(image)
+		if err != nil {
+			return err
+		}
+		entry := frontMatterImage{Url: link}
+		if image.Caption != nil {
+			entry.Caption = *image.Caption
+		}
+		// an image whose object is gone is still listed, the export does not fail on it
+		exists, err := s.stor.ImageExists(objectName(image.ImageUrl))
+		if err != nil {
+			return err
+		}
+		if exists {
+			entry.File = path.Join("images", objectName(image.ImageUrl))
+			stored = append(stored, image)
+		}
+		matter.Images = append(matter.Images, entry)
+	}
+
+	head, err := yaml.Marshal(matter)
+	if err != nil {
+		return err
+	}
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: path.Join(dir, "index.md"), Method: zip.Deflate, Modified: post.UpdatedAt})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(file, "---\n%s---\n\n%s\n", head, post.Content); err != nil {
+		return err
+	}
+
+	for _, image := range stored {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.archiveImage(archive, path.Join(dir, "images"), image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveImage copies the object of an image into the archive as it is, images are compressed already
+func (s *PosterService) archiveImage(archive *zip.Writer, dir string, image *dto.ImageDB) error {
+	name := objectName(image.ImageUrl)
+	obj, _, err := s.stor.GetImage(name)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: path.Join(dir, name), Method: zip.Store, Modified: image.UpdatedAt})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, obj)
+	return err
+}
+
+// exportDir names the directory of a post in an export archive, its creation date, its title in
+// lowercase letters and digits, and the start of its id so posts of the same day and title differ
+func exportDir(post *dto.PostDB) string {
+	var name strings.Builder
+	dash := false
+	runes := 0
+	for _, r := range strings.ToLower(post.Title) {
+		if runes == exportNameRunes {
+			break
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = name.Len() > 0
+			continue
+		}
+		if dash {
+			name.WriteByte('-')
+			runes++
+			dash = false
+		}
+		name.WriteRune(r)
+		runes++
+	}
+	title := name.String()
+	if title == "" {
+		title = "post"
+	}
+	return fmt.Sprintf("%s-%s-%s", post.CreatedAt.Format(time.DateOnly), title, post.PostId.String()[:8])
+}
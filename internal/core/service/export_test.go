@@ -0,0 +1,196 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// exportFixture has two posts of the author, one of them with two images of which one lost its object,
+// a trashed post of the author and a post of someone else
+type exportFixture struct {
+	authorId      uuid.UUID
+	draft, posted *dto.PostDB
+	trashed       *dto.PostDB
+	foreign       *dto.PostDB
+	kept, lost    uuid.UUID
+	rep           *fakePosterRepository
+	stor          *recordingStorage
+}
+
+func newExportFixture() *exportFixture {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	authorId := uuid.New()
+	excerpt := "Short"
+	publishAt := base.Add(2 * time.Hour)
+	f := &exportFixture{
+		authorId: authorId,
+		posted: &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Hello, World!", Content: "# Hi\n\nFirst post",
+			Excerpt: &excerpt, Status: types.Published, PublishAt: &publishAt, Tags: pq.StringArray{"go", "intro"},
+			CreatedAt: base, UpdatedAt: base.Add(time.Hour)},
+		draft: &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Draft: ideas", Content: "Later",
+			Status: types.Draft, CreatedAt: base.Add(24 * time.Hour), UpdatedAt: base.Add(24 * time.Hour)},
+		foreign: &dto.PostDB{PostId: uuid.New(), AuthorId: uuid.New(), Title: "Not mine", Content: "Theirs",
+			Status: types.Published, CreatedAt: base, UpdatedAt: base},
+		kept: uuid.New(),
+		lost: uuid.New(),
+	}
+	deletedAt := base.Add(48 * time.Hour)
+	f.trashed = &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Gone", Content: "Trashed",
+		Status: types.Draft, DeletedAt: &deletedAt, CreatedAt: base, UpdatedAt: base}
+
+	f.rep = newFakePosterRepository(f.posted, f.draft, f.trashed, f.foreign)
+	caption := "The view"
+	kept, _ := f.rep.CreateImage(context.Background(), f.kept, f.posted.PostId, "/images/"+f.kept.String()+".png", 0)
+	kept.Position, kept.Caption, kept.CreatedAt, kept.UpdatedAt = 1, &caption, base, base
+	lost, _ := f.rep.CreateImage(context.Background(), f.lost, f.posted.PostId, "/images/"+f.lost.String()+".png", 0)
+	lost.Position, lost.CreatedAt, lost.UpdatedAt = 2, base, base
+	foreignImage := uuid.New()
+	f.rep.CreateImage(context.Background(), foreignImage, f.foreign.PostId, "/images/"+foreignImage.String()+".png", 0)
+
+	f.stor = &recordingStorage{put: map[string]string{
+		f.kept.String() + ".png":       pngBytes + "kept",
+		foreignImage.String() + ".png": pngBytes + "foreign",
+	}}
+	return f
+}
+
+func TestPosterService_ExportPosts(t *testing.T) {
+	f := newExportFixture()
+	s := NewPosterService(f.rep, f.stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	var out bytes.Buffer
+	require.NoError(t, s.ExportPosts(context.Background(), f.authorId, &out))
+
+	var export dto.PostsExport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &export), out.String())
+	assert.Equal(t, f.authorId, export.AuthorId)
+	assert.False(t, export.ExportedAt.IsZero())
+	require.Len(t, export.Posts, 2, "trashed posts and those of others are left out")
+
+	posted, draft := export.Posts[0], export.Posts[1]
+	assert.Equal(t, f.posted.PostId, posted.PostId)
+	assert.Equal(t, "# Hi\n\nFirst post", posted.Content)
+	assert.Equal(t, "Short", posted.Excerpt)
+	assert.Equal(t, types.Published, posted.Status)
+	assert.Equal(t, []string{"go", "intro"}, posted.Tags)
+	require.Len(t, posted.Images, 2)
+	assert.Equal(t, f.kept, posted.Images[0].ImageId)
+	assert.Equal(t, "The view", posted.Images[0].Caption)
+	assert.Equal(t, "/images/"+f.kept.String()+".png", posted.Images[0].ImageUrl)
+
+	assert.Equal(t, f.draft.PostId, draft.PostId)
+	assert.Equal(t, types.Draft, draft.Status)
+	assert.Equal(t, []string{}, draft.Tags)
+	assert.Empty(t, draft.Images)
+
+	t.Run("author without posts", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, s.ExportPosts(context.Background(), uuid.New(), &out))
+		var export dto.PostsExport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &export))
+		assert.Empty(t, export.Posts)
+	})
+}
+
+func TestPosterService_ExportPostsArchive(t *testing.T) {
+	f := newExportFixture()
+	s := NewPosterService(f.rep, f.stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	var out bytes.Buffer
+	require.NoError(t, s.ExportPostsArchive(context.Background(), f.authorId, &out))
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	var names []string
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[file.Name] = string(data)
+		names = append(names, file.Name)
+	}
+
+	postedDir := "2025-03-01-hello-world-" + f.posted.PostId.String()[:8]
+	draftDir := "2025-03-02-draft-ideas-" + f.draft.PostId.String()[:8]
+	assert.Equal(t, []string{
+		postedDir + "/index.md",
+		postedDir + "/images/" + f.kept.String() + ".png",
+		draftDir + "/index.md",
+	}, names, "the lost object, trashed posts and those of others are left out")
+	assert.Equal(t, pngBytes+"kept", files[postedDir+"/images/"+f.kept.String()+".png"])
+
+	index := files[postedDir+"/index.md"]
+	require.True(t, strings.HasPrefix(index, "---\n"), index)
+	assert.Contains(t, index, "title: Hello, World!\n")
+	assert.Contains(t, index, "status: published\n")
+	assert.Contains(t, index, "tags: [go, intro]\n")
+	assert.Contains(t, index, "excerpt: Short\n")
+	assert.Contains(t, index, "publish_at: 2025-03-01T12:00:00Z\n")
+	assert.Contains(t, index, "created_at: 2025-03-01T10:00:00Z\n")
+	assert.Contains(t, index, "    - file: images/"+f.kept.String()+".png\n")
+	assert.Contains(t, index, "      caption: The view\n")
+	assert.Contains(t, index, "    - url: /images/"+f.lost.String()+".png\n", "a lost image is listed without a file")
+	assert.True(t, strings.HasSuffix(index, "---\n\n# Hi\n\nFirst post\n"), index)
+
+	draft := files[draftDir+"/index.md"]
+	assert.Contains(t, draft, "tags: []\n")
+	assert.NotContains(t, draft, "images:")
+	assert.NotContains(t, draft, "publish_at")
+}
+
+// brokenStorage fails reading every object
+type brokenStorage struct {
+	*recordingStorage
+}
+
+func (s brokenStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	return nil, "", errors.ErrorRepositoryStorageTimeout
+}
+
+func TestPosterService_ExportPostsArchive_StorageFails(t *testing.T) {
+	f := newExportFixture()
+	s := NewPosterService(f.rep, brokenStorage{f.stor}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	var out bytes.Buffer
+	err := s.ExportPostsArchive(context.Background(), f.authorId, &out)
+	assert.ErrorIs(t, err, errors.ErrorRepositoryStorageTimeout)
+	_, err = zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	assert.Error(t, err, "an archive cut off is no valid zip")
+}
+
+func TestExportDir(t *testing.T) {
+	postId := uuid.MustParse("0badc0de-0000-4000-8000-000000000000")
+	created := time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello, World!", "2025-03-01-hello-world-0badc0de"},
+		{"  Go 1.24 -- what's new?  ", "2025-03-01-go-1-24-what-s-new-0badc0de"},
+		{"Привет мир", "2025-03-01-привет-мир-0badc0de"},
+		{"!!!", "2025-03-01-post-0badc0de"},
+		{strings.Repeat("a", 100), "2025-03-01-" + strings.Repeat("a", 60) + "-0badc0de"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, exportDir(&dto.PostDB{PostId: postId, Title: tt.title, CreatedAt: created}))
+		})
+	}
+}
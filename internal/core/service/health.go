@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+const (
+	HealthOk       = "ok"
+	HealthDegraded = "degraded"
+	// HealthUnavailable is all /readyz tells about a failing dependency, its error names hosts and
+	// ports and goes to the log instead
+	HealthUnavailable = "unavailable"
+)
+
+// HealthDependency is satisfied by *postgres.DB, *minio.MinIOClient and *s3.S3Client
+type HealthDependency interface {
+	PingContext(ctx context.Context) error
+}
+
+type HealthService struct {
+	deps    map[string]HealthDependency
+	timeout time.Duration
+}
+
+func NewHealthService(timeout time.Duration, deps map[string]HealthDependency) *HealthService {
+	return &HealthService{deps, timeout}
+}
+
+// Readiness pings every dependency concurrently, each one bounded by the configured timeout
+func (s *HealthService) Readiness(ctx context.Context) (*dto.HealthResponse, bool) {
+	type result struct {
+		name string
+		err  error
+	}
+
+	results := make(chan result, len(s.deps))
+	for name, dep := range s.deps {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+			results <- result{name, dep.PingContext(ctx)}
+		}()
+	}
+
+	res := &dto.HealthResponse{Status: HealthOk, Checks: make(map[string]string, len(s.deps))}
+	ready := true
+	for range s.deps {
+		r := <-results
+		if r.err != nil {
+			ready = false
+			slogctx.Logger(ctx).Warn("dependency not ready", slog.String("dependency", r.name), slog.String("error", r.err.Error()))
+			res.Checks[r.name] = HealthUnavailable
+		} else {
+			res.Checks[r.name] = HealthOk
+		}
+	}
+	if !ready {
+		res.Status = HealthDegraded
+	}
+	return res, ready
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	gerrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubDependency struct {
+	err   error
+	block bool
+}
+
+func (d *stubDependency) PingContext(ctx context.Context) error {
+	if d.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return d.err
+}
+
+func TestHealthService_Readiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		deps     map[string]HealthDependency
+		ready    bool
+		expected map[string]string
+	}{
+		{
+			name:     "healthy",
+			deps:     map[string]HealthDependency{"postgres": &stubDependency{}, "minio": &stubDependency{}},
+			ready:    true,
+			expected: map[string]string{"postgres": HealthOk, "minio": HealthOk},
+		},
+		{
+			name:     "postgres down",
+			deps:     map[string]HealthDependency{"postgres": &stubDependency{err: gerrors.New("dial tcp 10.0.0.5:5432: connection refused")}, "minio": &stubDependency{}},
+			ready:    false,
+			expected: map[string]string{"postgres": HealthUnavailable, "minio": HealthOk},
+		},
+		{
+			name:     "minio hangs past the timeout",
+			deps:     map[string]HealthDependency{"postgres": &stubDependency{}, "minio": &stubDependency{block: true}},
+			ready:    false,
+			expected: map[string]string{"postgres": HealthOk, "minio": HealthUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHealthService(20*time.Millisecond, tt.deps)
+
+			resp, ready := s.Readiness(context.Background())
+
+			assert.Equal(t, tt.ready, ready)
+			assert.Equal(t, tt.expected, resp.Checks)
+			for _, check := range resp.Checks {
+				assert.NotContains(t, check, "10.0.0.5", "errors of dependencies stay in the log")
+				assert.NotContains(t, check, "connection refused")
+				assert.NotContains(t, check, context.DeadlineExceeded.Error())
+			}
+			if tt.ready {
+				assert.Equal(t, HealthOk, resp.Status)
+			} else {
+				assert.Equal(t, HealthDegraded, resp.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type IdempotencyRepository interface {
+	ClaimIdempotencyKey(ctx context.Context, key *dto.IdempotencyKeyDB, now time.Time) (bool, error)
+	GetIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) (*dto.IdempotencyKeyDB, error)
+	CompleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error
+	DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error
+}
+
+// IdempotencyKeys remembers for ttl the responses of requests sent with an Idempotency-Key, so a client
+// retrying one gets the first answer instead of running it twice. The TokenPurger removes expired keys.
+type IdempotencyKeys struct {
+	rep IdempotencyRepository
+	ttl time.Duration
+	// clock defaults to time.Now
+	clock func() time.Time
+}
+
+func NewIdempotencyKeys(rep IdempotencyRepository, ttl time.Duration, clock func() time.Time) *IdempotencyKeys {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &IdempotencyKeys{rep: rep, ttl: ttl, clock: clock}
+}
+
+// Begin claims the key of the user for a request to endpoint hashing to hash. The first request under
+// a key, or the first after it expired, gets nil and must Complete or Release the key. Any other gets
+// what is stored for the key, with Status 0 while the first request still runs.
+func (k *IdempotencyKeys) Begin(ctx context.Context, userId uuid.UUID, key, endpoint, hash string) (*dto.IdempotencyKeyDB, error) {
+	now := k.clock()
+	claim := &dto.IdempotencyKeyDB{UserId: userId, Key: key, Endpoint: endpoint, RequestHash: hash, CreatedAt: now, ExpiresAt: now.Add(k.ttl)}
+	claimed, err := k.rep.ClaimIdempotencyKey(ctx, claim, now)
+	if err != nil || claimed {
+		return nil, err
+	}
+
+	held, err := k.rep.GetIdempotencyKey(ctx, userId, key)
+	if err == sql.ErrNoRows {
+		// the request holding the key failed and let go of it just now, this one is told to retry
+		return &dto.IdempotencyKeyDB{UserId: userId, Key: key, Endpoint: endpoint, RequestHash: hash}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return held, nil
+}
+
+// Complete stores the response of the request that claimed the key
+func (k *IdempotencyKeys) Complete(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error {
+	return k.rep.CompleteIdempotencyKey(ctx, userId, key, status, contentType, body)
+}
+
+// Release lets go of the key of a request that failed on the server side, a retry then runs it again
+func (k *IdempotencyKeys) Release(ctx context.Context, userId uuid.UUID, key string) error {
+	return k.rep.DeleteIdempotencyKey(ctx, userId, key)
+}
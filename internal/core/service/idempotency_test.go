@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type fakeIdempotencyRepository struct {
+	keys map[string]*dto.IdempotencyKeyDB
+}
+
+func (f *fakeIdempotencyRepository) ClaimIdempotencyKey(ctx context.Context, key *dto.IdempotencyKeyDB, now time.Time) (bool, error) {
+	if held, ok := f.keys[key.UserId.String()+key.Key]; ok && held.ExpiresAt.After(now) {
+		return false, nil
+	}
+	claimed := *key
+	f.keys[key.UserId.String()+key.Key] = &claimed
+	return true, nil
+}
+
+func (f *fakeIdempotencyRepository) GetIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) (*dto.IdempotencyKeyDB, error) {
+	held, ok := f.keys[userId.String()+key]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *held
+	return &copied, nil
+}
+
+func (f *fakeIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error {
+	held := f.keys[userId.String()+key]
+	held.Status, held.ContentType, held.ResponseBody = status, contentType, body
+	return nil
+}
+
+func (f *fakeIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	delete(f.keys, userId.String()+key)
+	return nil
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	setup := func() (*IdempotencyKeys, *fakeIdempotencyRepository, *time.Time) {
+		rep := &fakeIdempotencyRepository{keys: map[string]*dto.IdempotencyKeyDB{}}
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		return NewIdempotencyKeys(rep, 24*time.Hour, func() time.Time { return now }), rep, &now
+	}
+
+	t.Run("first request claims the key", func(t *testing.T) {
+		keys, rep, now := setup()
+		held, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, held)
+
+		stored := rep.keys[userId.String()+"k"]
+		require.NotNil(t, stored)
+		assert.Equal(t, now.Add(24*time.Hour), stored.ExpiresAt)
+		assert.Zero(t, stored.Status)
+	})
+
+	t.Run("repeat gets the stored response", func(t *testing.T) {
+		keys, _, _ := setup()
+		_, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+
+		held, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		assert.Zero(t, held.Status, "still in progress")
+
+		require.NoError(t, keys.Complete(ctx, userId, "k", 200, "application/json", []byte(`{}`)))
+		held, err = keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		assert.Equal(t, 200, held.Status)
+		assert.Equal(t, []byte(`{}`), held.ResponseBody)
+	})
+
+	t.Run("expired key is claimed again", func(t *testing.T) {
+		keys, _, now := setup()
+		_, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		require.NoError(t, keys.Complete(ctx, userId, "k", 200, "application/json", []byte(`{}`)))
+
+		*now = now.Add(24 * time.Hour)
+		held, err := keys.Begin(ctx, userId, "k", "PUT /posts/2", "other")
+		require.NoError(t, err)
+		assert.Nil(t, held)
+	})
+
+	t.Run("released key is claimed again", func(t *testing.T) {
+		keys, _, _ := setup()
+		_, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		require.NoError(t, keys.Release(ctx, userId, "k"))
+
+		held, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, held)
+	})
+
+	t.Run("keys are per user", func(t *testing.T) {
+		keys, _, _ := setup()
+		_, err := keys.Begin(ctx, userId, "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+
+		held, err := keys.Begin(ctx, uuid.New(), "k", "PUT /posts/1", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, held)
+	})
+}
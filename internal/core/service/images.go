@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+// imageExtensions are the accepted image types by the content type sniffed from their bytes
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// sniffImage tells the type of an upload from its first bytes, whatever name or header the client sent.
+// The returned reader yields the whole file again, anything but a supported image is ErrorServiceUnsupportedImage.
+func sniffImage(file io.Reader) (contentType, ext string, body io.Reader, err error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", "", nil, err
+	}
+	head = head[:n]
+
+	contentType = http.DetectContentType(head)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return "", "", nil, errors.ErrorServiceUnsupportedImage
+	}
+	return contentType, ext, io.MultiReader(bytes.NewReader(head), file), nil
+}
+
+// stripExif drops the APP1 segments of a JPEG, they carry EXIF and XMP with the camera and the place a photo
+// was taken. Nothing is re-encoded, the returned reader yields the image without them and the new size.
+// A JPEG that cannot be parsed up to its image data is ErrorServiceUnsupportedImage.
+func stripExif(file io.Reader, size int64) (io.Reader, int64, error) {
+	r := bufio.NewReader(file)
+	var head bytes.Buffer
+	var consumed int64
+
+	soi := make([]byte, 2)
+	if _, err := io.ReadFull(r, soi); err != nil || soi[0] != 0xff || soi[1] != 0xd8 {
+		return nil, 0, errors.ErrorServiceUnsupportedImage
+	}
+	head.Write(soi)
+	consumed += 2
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil || b != 0xff {
+			return nil, 0, errors.ErrorServiceUnsupportedImage
+		}
+		consumed++
+		marker := byte(0xff)
+		// a marker may be preceded by any number of fill bytes
+		for marker == 0xff {
+			if marker, err = r.ReadByte(); err != nil {
+				return nil, 0, errors.ErrorServiceUnsupportedImage
+			}
+			consumed++
+		}
+
+		// from the start of scan on there is only image data
+		if marker == 0xda {
+			head.Write([]byte{0xff, marker})
+			break
+		}
+
+		length := make([]byte, 2)
+		if _, err := io.ReadFull(r, length); err != nil {
+			return nil, 0, errors.ErrorServiceUnsupportedImage
+		}
+		n := int(binary.BigEndian.Uint16(length))
+		if n < 2 {
+			return nil, 0, errors.ErrorServiceUnsupportedImage
+		}
+		segment := make([]byte, n-2)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, 0, errors.ErrorServiceUnsupportedImage
+		}
+		consumed += int64(n)
+
+		if marker == 0xe1 {
+			continue
+		}
+		head.Write([]byte{0xff, marker})
+		head.Write(length)
+		head.Write(segment)
+	}
+
+	return io.MultiReader(&head, r), size - consumed + int64(head.Len()), nil
+}
+
+// putUpload puts an upload of size bytes into stor as <name>.<ext>, JPEGs without their EXIF if strip is set,
+// and returns its link and object name. With maxBytes above zero a larger upload is ErrorServiceImageTooLarge.
+func putUpload(ctx context.Context, stor storage.ImageStorage, name string, file io.Reader, size, maxBytes int64, strip bool) (string, string, error) {
+	if maxBytes > 0 && size > maxBytes {
+		return "", "", errors.ErrorServiceImageTooLarge
+	}
+	contentType, ext, body, err := sniffImage(file)
+	if err != nil {
+		return "", "", err
+	}
+	if strip && contentType == "image/jpeg" {
+		if body, size, err = stripExif(body, size); err != nil {
+			return "", "", err
+		}
+	}
+
+	name += "." + ext
+	link, err := stor.PutImage(ctx, name, body, size, contentType)
+	if err != nil {
+		return "", "", err
+	}
+	return link, name, nil
+}
+
+// objectName is the storage object behind a stored image_url. Images uploaded before objects got
+// an extension are named by the bare id, the stored path names them all the same.
+func objectName(imageUrl string) string {
+	return path.Base(imageUrl)
+}
+
+// avatarObject is the storage object behind a stored avatar_url
+func avatarObject(avatarUrl string) string {
+	return storage.AvatarPrefix + path.Base(avatarUrl)
+}
+
+// ImagePresigner makes time-limited links to objects of a private bucket
+type ImagePresigner interface {
+	PresignGet(objectName string, ttl time.Duration) (string, error)
+}
+
+// ImageLinks turns the image_url stored for an image into the link put into responses. A nil ImageLinks
+// stands for a public bucket where the stored path already is the link, otherwise every response gets
+// a fresh link valid for ttl.
+type ImageLinks struct {
+	presigner ImagePresigner
+	ttl       time.Duration
+}
+
+func NewImageLinks(presigner ImagePresigner, ttl time.Duration) *ImageLinks {
+	return &ImageLinks{presigner, ttl}
+}
+
+func (l *ImageLinks) Link(stored string) (string, error) {
+	if l == nil {
+		return stored, nil
+	}
+	// images uploaded while the bucket was public are stored as /bucket/object
+	return l.presigner.PresignGet(objectName(stored), l.ttl)
+}
+
+// ImageLink is the link to an image. A public link of a replaced image carries the time it was replaced
+// so caches holding the old file miss it, presigned links differ on every response anyway.
+func (l *ImageLinks) ImageLink(image *dto.ImageDB) (string, error) {
+	if l == nil && image.UpdatedAt.After(image.CreatedAt) {
+		return fmt.Sprintf("%s?v=%d", image.ImageUrl, image.UpdatedAt.UnixMilli()), nil
+	}
+	return l.Link(image.ImageUrl)
+}
+
+// AvatarLink is the link to the avatar stored as avatarUrl, empty for a user without one
+func (l *ImageLinks) AvatarLink(avatarUrl *string) (string, error) {
+	if avatarUrl == nil {
+		return "", nil
+	}
+	if l == nil {
+		return *avatarUrl, nil
+	}
+	return l.presigner.PresignGet(avatarObject(*avatarUrl), l.ttl)
+}
+
+func (l *ImageLinks) imagesResponse(images []*dto.ImageDB) (*dto.GetPostImagesResponse, error) {
+	res := &dto.GetPostImagesResponse{Images: make([]dto.ImageResponse, len(images))}
+	for i, image := range images {
+		imageRes, err := l.imageResponse(image)
+		if err != nil {
+			return nil, err
+		}
+		res.Images[i] = *imageRes
+	}
+	return res, nil
+}
+
+func (l *ImageLinks) imageResponse(image *dto.ImageDB) (*dto.ImageResponse, error) {
+	link, err := l.ImageLink(image)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.ImageResponse{
+		ImageId:   image.ImageId,
+		ImageUrl:  link,
+		Position:  image.Position,
+		CreatedAt: image.CreatedAt,
+		UpdatedAt: image.UpdatedAt,
+	}
+	if image.Caption != nil {
+		res.Caption = *image.Caption
+	}
+	return res, nil
+}
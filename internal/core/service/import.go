@@ -0,0 +1,295 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+	"github.com/xkarasb/blog/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// importIndexBytes caps the index.md of a post in an imported archive, images have their own limit
+const importIndexBytes = 4 << 20
+
+// importPost is a post read from an export, images are the files bundled with it in an archive
+type importPost struct {
+	title   string
+	content string
+	excerpt string
+	tags    []string
+	images  []bundledImage
+}
+
+// bundledImage is an image file of an archived post with the link the content knew it by
+type bundledImage struct {
+	file    *zip.File
+	url     string
+	caption string
+}
+
+// ImportPosts adds the posts of an export to those of the author, the JSON document of ExportPosts or the
+// zip archive of ExportPostsArchive as told by its first bytes. Every post becomes a draft with a new
+// idempotency key and is added on its own: a malformed one, one the author has already or one failing to
+// be stored is skipped with its error in the result. Images bundled in an archive are stored anew and
+// their links in the content point to the new ones; a JSON export bundles none, its links stay as they are.
+// A file that is no export at all is ErrorServiceImportFormat.
+func (s *PosterService) ImportPosts(ctx context.Context, authorId uuid.UUID, file io.ReaderAt, size int64) ([]dto.PostImport, error) {
+	head := make([]byte, 512)
+	n, _ := file.ReadAt(head, 0)
+	head = bytes.TrimLeft(head[:n], " \t\r\n")
+
+	var imports []dto.PostImport
+	var err error
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		imports, err = s.importArchive(ctx, authorId, file, size)
+	case bytes.HasPrefix(head, []byte("{")):
+		imports, err = s.importDocument(ctx, authorId, io.NewSectionReader(file, 0, size))
+	default:
+		return nil, errors.ErrorServiceImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, imported := range imports {
+		if imported.Err == nil {
+			s.touched(types.Draft)
+			break
+		}
+	}
+	return imports, nil
+}
+
+// importDocument imports the posts of a JSON export, entries are named by their index
+func (s *PosterService) importDocument(ctx context.Context, authorId uuid.UUID, file io.Reader) ([]dto.PostImport, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	var document dto.PostsImport
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, errors.ErrorServiceImportFormat
+	}
+
+	imports := make([]dto.PostImport, len(document.Posts))
+	for i, raw := range document.Posts {
+		imports[i].Entry = fmt.Sprintf("posts[%d]", i)
+		var exported dto.ExportedPost
+		if err := json.Unmarshal(raw, &exported); err != nil {
+			imports[i].Err = fmt.Errorf("%w: %s", errors.ErrorServiceImportEntry, err)
+			continue
+		}
+		imports[i].PostId, imports[i].Err = s.importPost(ctx, authorId, &importPost{
+			title:   exported.Title,
+			content: exported.Content,
+			excerpt: exported.Excerpt,
+			tags:    exported.Tags,
+		})
+	}
+	return imports, nil
+}
+
+// importArchive imports the posts of a zip export, every directory holding an index.md is one, in the
+// order of the archive
+func (s *PosterService) importArchive(ctx context.Context, authorId uuid.UUID, file io.ReaderAt, size int64) ([]dto.PostImport, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, errors.ErrorServiceImportFormat
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var imports []dto.PostImport
+	for _, f := range archive.File {
+		dir, name := path.Split(f.Name)
+		if name != "index.md" || dir == "" {
+			continue
+		}
+		imported := dto.PostImport{Entry: strings.TrimSuffix(dir, "/")}
+		post, err := readArchivedPost(files, imported.Entry, f)
+		if err != nil {
+			imported.Err = err
+		} else {
+			imported.PostId, imported.Err = s.importPost(ctx, authorId, post)
+		}
+		imports = append(imports, imported)
+	}
+	return imports, nil
+}
+
+// readArchivedPost reads index.md of the post in dir, its front matter and content, and finds the files
+// of its images. A front matter that does not parse or an image file missing is ErrorServiceImportEntry.
+func readArchivedPost(files map[string]*zip.File, dir string, index *zip.File) (*importPost, error) {
+	if index.UncompressedSize64 > importIndexBytes {
+		return nil, fmt.Errorf("%w: index.md larger than %d bytes", errors.ErrorServiceImportEntry, importIndexBytes)
+	}
+	r, err := index.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrorServiceImportEntry, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, importIndexBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrorServiceImportEntry, err)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, "---\n")
+	var head, content string
+	if ok {
+		head, content, ok = strings.Cut(rest, "\n---\n")
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: index.md has no front matter", errors.ErrorServiceImportEntry)
+	}
+	var matter frontMatter
+	if err := yaml.Unmarshal([]byte(head), &matter); err != nil {
+		return nil, fmt.Errorf("%w: front matter: %s", errors.ErrorServiceImportEntry, err)
+	}
+
+	post := &importPost{
+		title:   matter.Title,
+		content: strings.TrimSuffix(strings.TrimPrefix(content, "\n"), "\n"),
+		excerpt: matter.Excerpt,
+		tags:    matter.Tags,
+	}
+	for _, image := range matter.Images {
+		if image.File == "" {
+			continue
+		}
+		name := path.Join(dir, image.File)
+		f, ok := files[name]
+		if !ok || !strings.HasPrefix(name, dir+"/") {
+			return nil, fmt.Errorf("%w: image %s is missing", errors.ErrorServiceImportEntry, image.File)
+		}
+		post.images = append(post.images, bundledImage{f, image.Url, image.Caption})
+	}
+	return post, nil
+}
+
+// importPost adds one post of an import as a draft. Its images are stored first and removed again when
+// the post cannot be added, the post and its images rows are written together. Images taking the author
+// past the storage quota leave the post out with ErrorQuotaExceeded.
+func (s *PosterService) importPost(ctx context.Context, authorId uuid.UUID, post *importPost) (uuid.UUID, error) {
+	post.tags = utils.NormalizeTags(post.tags)
+	err := utils.Validate(&dto.CreatePostRequest{
+		IdempotencyKey: "import",
+		Title:          post.title,
+		Content:        post.content,
+		Excerpt:        strings.TrimSpace(post.excerpt),
+		Tags:           post.tags,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %s", errors.ErrorServiceImportEntry, err)
+	}
+	if s.limits.PerPost > 0 && len(post.images) > s.limits.PerPost {
+		return uuid.Nil, errors.ErrorServiceImageLimit
+	}
+
+	hash := importHash(post.title, post.content)
+	exists, err := s.rep.HasImportedPost(ctx, authorId, hash, post.title, post.content)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if exists {
+		return uuid.Nil, errors.ErrorServiceImportDuplicate
+	}
+	quota, err := s.quotaRoom(ctx, authorId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if quota >= 0 {
+		var size int64
+		for _, image := range post.images {
+			size += int64(image.file.UncompressedSize64)
+		}
+		if size > quota {
+			return uuid.Nil, errors.ErrorQuotaExceeded
+		}
+	}
+
+	imported := &dto.ImportedPost{
+		IdempotencyKey: "import-" + uuid.NewString(),
+		Title:          post.title,
+		Content:        post.content,
+		Excerpt:        strings.TrimSpace(post.excerpt),
+		Tags:           post.tags,
+		Hash:           hash,
+	}
+	var stored []string
+	removeStored := func() {
+		for _, name := range stored {
+			s.removeImage(ctx, name)
+		}
+	}
+	for _, image := range post.images {
+		added, err := s.storeBundledImage(ctx, image.file)
+		if err != nil {
+			removeStored()
+			return uuid.Nil, err
+		}
+		stored = append(stored, objectName(added.link))
+
+		entry := dto.ImportedImage{ImageId: added.imageId, ImageUrl: added.link, SizeBytes: added.size}
+		if image.caption != "" {
+			caption := image.caption
+			entry.Caption = &caption
+		}
+		imported.Images = append(imported.Images, entry)
+		imported.Content = remapLink(imported.Content, image.url, added.link)
+	}
+	stats := s.reading.Stats(imported.Content)
+	imported.Stats = &stats
+
+	postDB, err := s.rep.ImportPost(ctx, authorId, imported)
+	if err != nil {
+		removeStored()
+		return uuid.Nil, err
+	}
+	return postDB.PostId, nil
+}
+
+func (s *PosterService) storeBundledImage(ctx context.Context, f *zip.File) (storedImage, error) {
+	if s.limits.Bytes > 0 && f.UncompressedSize64 > uint64(s.limits.Bytes) {
+		return storedImage{}, errors.ErrorServiceImageTooLarge
+	}
+	r, err := f.Open()
+	if err != nil {
+		return storedImage{}, fmt.Errorf("%w: %s", errors.ErrorServiceImportEntry, err)
+	}
+	defer r.Close()
+	return s.storeImage(ctx, r, int64(f.UncompressedSize64))
+}
+
+// remapLink points the links of an exported image in content to its new object. The content may know
+// a public link without the ?v= it was exported with, that one is replaced as well.
+func remapLink(content, old, link string) string {
+	if old == "" {
+		return content
+	}
+	content = strings.ReplaceAll(content, old, link)
+	if bare, _, ok := strings.Cut(old, "?"); ok && bare != "" {
+		content = strings.ReplaceAll(content, bare, link)
+	}
+	return content
+}
+
+// importHash identifies a post by the title and content it was imported with
+func importHash(title, content string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
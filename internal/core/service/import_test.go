@@ -0,0 +1,240 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// authorPosts returns the posts of the author in the fake by title
+func authorPosts(rep *fakePosterRepository, authorId uuid.UUID) map[string]*dto.PostDB {
+	posts := map[string]*dto.PostDB{}
+	for _, post := range rep.posts {
+		if post.AuthorId == authorId {
+			posts[post.Title] = post
+		}
+	}
+	return posts
+}
+
+// zipOf writes files, pairs of name and content, into a zip archive in their order
+func zipOf(t *testing.T, files ...[2]string) *bytes.Reader {
+	t.Helper()
+	var out bytes.Buffer
+	archive := zip.NewWriter(&out)
+	for _, file := range files {
+		w, err := archive.Create(file[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return bytes.NewReader(out.Bytes())
+}
+
+func TestPosterService_ImportPosts_Archive(t *testing.T) {
+	f := newExportFixture()
+	keptLink, lostLink := "/images/"+f.kept.String()+".png", "/images/"+f.lost.String()+".png"
+	f.posted.Content = "# Hi\n\n![view](" + keptLink + ")\n![gone](" + lostLink + ")"
+	s := NewPosterService(f.rep, f.stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	var export bytes.Buffer
+	require.NoError(t, s.ExportPostsArchive(context.Background(), f.authorId, &export))
+
+	other := uuid.New()
+	imports, err := s.ImportPosts(context.Background(), other, bytes.NewReader(export.Bytes()), int64(export.Len()))
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+	for _, imported := range imports {
+		assert.NoError(t, imported.Err, imported.Entry)
+	}
+	assert.Equal(t, exportDir(f.posted), imports[0].Entry)
+
+	posts := authorPosts(f.rep, other)
+	require.Len(t, posts, 2)
+	posted := posts["Hello, World!"]
+	require.NotNil(t, posted)
+	assert.Equal(t, imports[0].PostId, posted.PostId)
+	assert.Equal(t, types.Draft, posted.Status, "imported posts are drafts whatever they were")
+	assert.True(t, strings.HasPrefix(posted.IdempotencyKey, "import-"))
+	assert.NotEqual(t, f.posted.IdempotencyKey, posted.IdempotencyKey)
+	assert.Equal(t, []string{"go", "intro"}, []string(posted.Tags))
+	require.NotNil(t, posted.Excerpt)
+	assert.Equal(t, "Short", *posted.Excerpt)
+
+	images, _ := f.rep.GetPostImages(context.Background(), posted.PostId)
+	require.Len(t, images, 1, "the image lost before the export is not bundled")
+	image := images[0]
+	assert.NotEqual(t, f.kept, image.ImageId)
+	require.NotNil(t, image.Caption)
+	assert.Equal(t, "The view", *image.Caption)
+	assert.Equal(t, pngBytes+"kept", f.stor.put[objectName(image.ImageUrl)])
+	assert.Equal(t, "# Hi\n\n![view]("+image.ImageUrl+")\n![gone]("+lostLink+")", posted.Content,
+		"links of bundled images point to the new ones, others stay")
+
+	t.Run("importing again skips every post", func(t *testing.T) {
+		objects := len(f.stor.put)
+		imports, err := s.ImportPosts(context.Background(), other, bytes.NewReader(export.Bytes()), int64(export.Len()))
+		require.NoError(t, err)
+		require.Len(t, imports, 2)
+		for _, imported := range imports {
+			assert.ErrorIs(t, imported.Err, errors.ErrorServiceImportDuplicate)
+		}
+		assert.Len(t, authorPosts(f.rep, other), 2)
+		assert.Len(t, f.stor.put, objects, "images of skipped posts are not stored")
+	})
+
+	t.Run("the author the export came from has the posts already", func(t *testing.T) {
+		imports, err := s.ImportPosts(context.Background(), f.authorId, bytes.NewReader(export.Bytes()), int64(export.Len()))
+		require.NoError(t, err)
+		for _, imported := range imports {
+			assert.ErrorIs(t, imported.Err, errors.ErrorServiceImportDuplicate)
+		}
+	})
+}
+
+func TestPosterService_ImportPosts_Document(t *testing.T) {
+	rep := newFakePosterRepository()
+	s := NewPosterService(rep, &recordingStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	authorId := uuid.New()
+
+	document := `{"author_id":"` + uuid.NewString() + `","posts":[
+		{"title":"First","content":"One","status":"published","tags":["Go","go"],"images":[{"image_id":"` + uuid.NewString() + `","image_url":"/images/x.png"}]},
+		{"title":"","content":"No title"},
+		{"title":"Typed","content":"Wrong","tags":5},
+		{"title":"First","content":"One"},
+		{"title":"Second","content":"Two","excerpt":"` + strings.Repeat("e", 301) + `"},
+		{"title":"Third","content":"Three","excerpt":"Custom"}
+	]}`
+	imports, err := s.ImportPosts(context.Background(), authorId, strings.NewReader(document), int64(len(document)))
+	require.NoError(t, err)
+	require.Len(t, imports, 6)
+
+	assert.NoError(t, imports[0].Err)
+	assert.ErrorIs(t, imports[1].Err, errors.ErrorServiceImportEntry, "a post without a title is malformed")
+	assert.ErrorIs(t, imports[2].Err, errors.ErrorServiceImportEntry)
+	assert.Equal(t, "posts[2]", imports[2].Entry)
+	assert.ErrorIs(t, imports[3].Err, errors.ErrorServiceImportDuplicate, "a post earlier in the same import counts")
+	assert.ErrorIs(t, imports[4].Err, errors.ErrorServiceImportEntry, "the excerpt is too long")
+	assert.NoError(t, imports[5].Err)
+
+	posts := authorPosts(rep, authorId)
+	require.Len(t, posts, 2)
+	assert.Equal(t, []string{"go"}, []string(posts["First"].Tags))
+	assert.Equal(t, types.Draft, posts["First"].Status)
+	images, _ := rep.GetPostImages(context.Background(), posts["First"].PostId)
+	assert.Empty(t, images, "a JSON export bundles no image files")
+	require.NotNil(t, posts["Third"].Excerpt)
+	assert.Equal(t, "Custom", *posts["Third"].Excerpt)
+}
+
+func TestPosterService_ImportPosts_MalformedArchive(t *testing.T) {
+	rep := newFakePosterRepository()
+	stor := &recordingStorage{}
+	s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{PerPost: 1}, 0, false)
+	authorId := uuid.New()
+
+	archive := zipOf(t,
+		[2]string{"readme.txt", "not a post"},
+		[2]string{"bare/index.md", "Just content"},
+		[2]string{"missing/index.md", "---\ntitle: Missing\nimages:\n    - file: images/a.png\n---\n\nText\n"},
+		[2]string{"escape/index.md", "---\ntitle: Escape\nimages:\n    - file: ../bare/index.md\n---\n\nText\n"},
+		[2]string{"text/index.md", "---\ntitle: Text\nimages:\n    - file: images/a.png\n---\n\nText\n"},
+		[2]string{"text/images/a.png", "plain text"},
+		[2]string{"many/index.md", "---\ntitle: Many\nimages:\n    - file: images/a.png\n    - file: images/b.png\n---\n\nText\n"},
+		[2]string{"many/images/a.png", pngBytes},
+		[2]string{"many/images/b.png", pngBytes},
+		[2]string{"broken/index.md", "---\ntitle: [unclosed\n---\n\nText\n"},
+		[2]string{"fine/index.md", "---\ntitle: Fine\ntags: [a]\n---\n\nText\n"},
+	)
+	imports, err := s.ImportPosts(context.Background(), authorId, archive, archive.Size())
+	require.NoError(t, err)
+
+	errs := map[string]error{}
+	for _, imported := range imports {
+		errs[imported.Entry] = imported.Err
+	}
+	require.Len(t, errs, 7, "only directories holding index.md are posts")
+	assert.ErrorIs(t, errs["bare"], errors.ErrorServiceImportEntry, "no front matter")
+	assert.ErrorIs(t, errs["missing"], errors.ErrorServiceImportEntry)
+	assert.ErrorIs(t, errs["escape"], errors.ErrorServiceImportEntry, "files of other posts are not taken")
+	assert.ErrorIs(t, errs["text"], errors.ErrorServiceUnsupportedImage)
+	assert.ErrorIs(t, errs["many"], errors.ErrorServiceImageLimit)
+	assert.ErrorIs(t, errs["broken"], errors.ErrorServiceImportEntry)
+	assert.NoError(t, errs["fine"])
+
+	assert.Len(t, authorPosts(rep, authorId), 1)
+	assert.Empty(t, stor.put, "nothing of the skipped posts is stored")
+
+	t.Run("no export", func(t *testing.T) {
+		for _, body := range []string{"hello", "PK\x03\x04 broken", "{not json"} {
+			_, err := s.ImportPosts(context.Background(), authorId, strings.NewReader(body), int64(len(body)))
+			assert.ErrorIs(t, err, errors.ErrorServiceImportFormat, body)
+		}
+	})
+}
+
+func TestPosterService_ImportPosts_Quota(t *testing.T) {
+	rep, stor := newFakePosterRepository(), &recordingStorage{}
+	s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{Quota: int64(len(pngBytes)) + 1}, 0, false)
+
+	authorId := uuid.New()
+	archive := zipOf(t,
+		[2]string{"first/index.md", "---\ntitle: First\nimages:\n    - file: images/a.png\n      url: /images/a.png\n---\n\n![a](/images/a.png)\n"},
+		[2]string{"first/images/a.png", pngBytes},
+		[2]string{"second/index.md", "---\ntitle: Second\nimages:\n    - file: images/b.png\n      url: /images/b.png\n---\n\n![b](/images/b.png)\n"},
+		[2]string{"second/images/b.png", pngBytes},
+	)
+	imports, err := s.ImportPosts(context.Background(), authorId, archive, archive.Size())
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+	assert.NoError(t, imports[0].Err)
+	assert.ErrorIs(t, imports[1].Err, errors.ErrorQuotaExceeded, "the images of the first post count against the second")
+
+	used, _ := rep.GetUserImageBytes(context.Background(), authorId)
+	assert.Equal(t, int64(len(pngBytes)), used, "imported images are counted by their size")
+	assert.Len(t, stor.put, 1, "images of a post over the quota are not stored")
+	assert.Len(t, authorPosts(rep, authorId), 1)
+}
+
+// failingImportRepository cannot write imported posts
+type failingImportRepository struct {
+	*fakePosterRepository
+}
+
+func (f failingImportRepository) ImportPost(ctx context.Context, authorId uuid.UUID, imported *dto.ImportedPost) (*dto.PostDB, error) {
+	return nil, errors.ErrorRepositoryQueryTimeout
+}
+
+func TestPosterService_ImportPosts_RemovesImagesOfFailedPost(t *testing.T) {
+	stor := &recordingStorage{}
+	s := NewPosterService(failingImportRepository{newFakePosterRepository()}, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	archive := zipOf(t,
+		[2]string{"post/index.md", "---\ntitle: Post\nimages:\n    - file: images/a.png\n      url: /images/a.png\n---\n\n![a](/images/a.png)\n"},
+		[2]string{"post/images/a.png", pngBytes},
+	)
+	imports, err := s.ImportPosts(context.Background(), uuid.New(), archive, archive.Size())
+	require.NoError(t, err)
+	require.Len(t, imports, 1)
+	assert.ErrorIs(t, imports[0].Err, errors.ErrorRepositoryQueryTimeout)
+	assert.Len(t, stor.deleted, 1)
+	assert.Empty(t, stor.put)
+}
+
+func TestRemapLink(t *testing.T) {
+	content := "![a](https://cdn/a.png?v=1) ![b](https://cdn/a.png) ![c](https://cdn/c.png)"
+	assert.Equal(t, "![a](https://cdn/n.png) ![b](https://cdn/n.png) ![c](https://cdn/c.png)",
+		remapLink(content, "https://cdn/a.png?v=1", "https://cdn/n.png"))
+	assert.Equal(t, content, remapLink(content, "", "https://cdn/n.png"))
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// ReportPost reports a published post to the admins. A user reports a post once, reporting it again keeps
+// the first report and returns it. Drafts and other posts readers cannot see are sql.ErrNoRows, whoever asks.
+func (s *ReaderService) ReportPost(ctx context.Context, userId, postId uuid.UUID, req *dto.ReportPostRequest) (*dto.ReportResponse, error) {
+	post, err := s.rep.GetPostWithAuthorById(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != types.Published {
+		return nil, sql.ErrNoRows
+	}
+
+	report, err := s.rep.AddReport(ctx, postId, userId, req.Reason, strings.TrimSpace(req.Comment))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReportResponse{
+		ReportId:  report.ReportId,
+		PostId:    report.PostId,
+		Reason:    report.Reason,
+		Comment:   report.Comment,
+		CreatedAt: report.CreatedAt,
+	}, nil
+}
+
+// GetReports pages through the moderation queue, the posts with open reports grouped with their counts
+func (s *AdminService) GetReports(ctx context.Context, limit, offset int) (*dto.GetReportsResponse, error) {
+	total, err := s.rep.CountReportedPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	postsDB, err := s.rep.GetReportedPosts(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]dto.ReportedPostResponse, len(postsDB))
+	for i, post := range postsDB {
+		comments := []string(post.Comments)
+		if comments == nil {
+			comments = []string{}
+		}
+		posts[i] = dto.ReportedPostResponse{
+			PostId:   post.PostId,
+			Title:    post.Title,
+			AuthorId: post.AuthorId,
+			Status:   post.Status,
+			Reports:  post.Reports,
+			Reasons: dto.ReportReasons{
+				Spam:      post.Spam,
+				Abuse:     post.Abuse,
+				Copyright: post.Copyright,
+				Other:     post.Other,
+			},
+			Comments:        comments,
+			FirstReportedAt: post.FirstReportedAt,
+			LastReportedAt:  post.LastReportedAt,
+		}
+	}
+
+	return &dto.GetReportsResponse{
+		Posts:  posts,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ResolveReports closes the open reports of a post. Unpublishing takes the post back to draft through the
+// same transitions an author has, a post that cannot go back to draft is ErrorServiceIncorrectData and its
+// reports stay open. Either decision is audited with the admin, a post without open reports is
+// ErrorServiceNoReports.
+func (s *AdminService) ResolveReports(ctx context.Context, adminId, postId uuid.UUID, req *dto.ResolveReportsRequest) (*dto.ResolveReportsResponse, error) {
+	postDB, err := s.rep.GetPostById(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.rep.CountOpenReports(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	if open == 0 {
+		return nil, errors.ErrorServiceNoReports
+	}
+
+	action := types.AuditDismissReports
+	if req.Action == types.ModerationUnpublish {
+		if !canTransition(postDB.Status, types.Draft) {
+			return nil, errors.ErrorServiceIncorrectData
+		}
+		status := types.Draft
+		postDB, err = s.rep.UpdatePost(ctx, postId, adminId, &dto.PostPatch{Status: &status}, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		s.listings.Invalidate()
+		action = types.AuditUnpublishPost
+	}
+
+	resolved, err := s.rep.ResolveReports(ctx, postId, adminId, req.Action)
+	if err != nil {
+		return nil, err
+	}
+	audit(ctx, s.recorder, action, adminId, postId)
+
+	return &dto.ResolveReportsResponse{
+		PostId:   postId,
+		Action:   req.Action,
+		Resolved: resolved,
+		Status:   postDB.Status,
+	}, nil
+}
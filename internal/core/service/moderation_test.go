@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// fakeModerationRepository keeps the posts and open reports AdminService moderates
+type fakeModerationRepository struct {
+	*fakeAdminRoleRepository
+	posts map[uuid.UUID]*dto.PostDB
+	// open counts the reports of each post nobody resolved yet
+	open map[uuid.UUID]int
+}
+
+func (f *fakeModerationRepository) GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return post, nil
+}
+
+func (f *fakeModerationRepository) UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error) {
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if patch.Status != nil {
+		post.Status = *patch.Status
+	}
+	return post, nil
+}
+
+func (f *fakeModerationRepository) CountOpenReports(ctx context.Context, postId uuid.UUID) (int, error) {
+	return f.open[postId], nil
+}
+
+func (f *fakeModerationRepository) ResolveReports(ctx context.Context, postId, adminId uuid.UUID, action types.ModerationAction) (int, error) {
+	resolved := f.open[postId]
+	delete(f.open, postId)
+	return resolved, nil
+}
+
+func TestReaderService_ReportPost(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+	readerId := uuid.New()
+	draft, published, archived := rep.posts[0], rep.posts[1], rep.posts[2]
+
+	for _, postId := range []uuid.UUID{draft.PostId, archived.PostId, uuid.New()} {
+		_, err := s.ReportPost(context.Background(), readerId, postId, &dto.ReportPostRequest{Reason: types.ReportSpam})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	_, err := s.ReportPost(context.Background(), authorId, draft.PostId, &dto.ReportPostRequest{Reason: types.ReportSpam})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "a draft is not there to report, even for its author")
+
+	first, err := s.ReportPost(context.Background(), readerId, published.PostId, &dto.ReportPostRequest{Reason: types.ReportAbuse, Comment: "  rude  "})
+	require.NoError(t, err)
+	assert.Equal(t, published.PostId, first.PostId)
+	assert.Equal(t, types.ReportAbuse, first.Reason)
+	assert.Equal(t, "rude", first.Comment)
+
+	again, err := s.ReportPost(context.Background(), readerId, published.PostId, &dto.ReportPostRequest{Reason: types.ReportSpam})
+	require.NoError(t, err)
+	assert.Equal(t, first, again, "a repeated report keeps the first one")
+	assert.Len(t, rep.reports, 1)
+}
+
+func TestAdminService_ResolveReports(t *testing.T) {
+	adminId := uuid.New()
+	newService := func(posts ...*dto.PostDB) (*AdminService, *fakeModerationRepository, *fakeAuditRecorder) {
+		rep := &fakeModerationRepository{posts: map[uuid.UUID]*dto.PostDB{}, open: map[uuid.UUID]int{}}
+		for _, post := range posts {
+			rep.posts[post.PostId] = post
+			rep.open[post.PostId] = 2
+		}
+		recorder := &fakeAuditRecorder{}
+		return NewAdminService(rep, nil, nil, recorder), rep, recorder
+	}
+
+	t.Run("dismiss", func(t *testing.T) {
+		post := &dto.PostDB{PostId: uuid.New(), Status: types.Published}
+		s, rep, recorder := newService(post)
+
+		resp, err := s.ResolveReports(context.Background(), adminId, post.PostId, &dto.ResolveReportsRequest{Action: types.ModerationDismiss})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.ResolveReportsResponse{PostId: post.PostId, Action: types.ModerationDismiss, Resolved: 2, Status: types.Published}, resp)
+		assert.Empty(t, rep.open)
+		assert.Equal(t, []types.AuditAction{types.AuditDismissReports}, recorder.actions())
+		assert.Equal(t, adminId, *recorder.entries[0].UserId)
+		assert.Equal(t, post.PostId, *recorder.entries[0].TargetId)
+
+		_, err = s.ResolveReports(context.Background(), adminId, post.PostId, &dto.ResolveReportsRequest{Action: types.ModerationDismiss})
+		assert.ErrorIs(t, err, errors.ErrorServiceNoReports)
+	})
+
+	t.Run("unpublish", func(t *testing.T) {
+		post := &dto.PostDB{PostId: uuid.New(), Status: types.Published}
+		s, rep, recorder := newService(post)
+
+		resp, err := s.ResolveReports(context.Background(), adminId, post.PostId, &dto.ResolveReportsRequest{Action: types.ModerationUnpublish})
+		require.NoError(t, err)
+		assert.Equal(t, types.Draft, resp.Status)
+		assert.Equal(t, types.Draft, rep.posts[post.PostId].Status)
+		assert.Equal(t, []types.AuditAction{types.AuditUnpublishPost}, recorder.actions())
+		assert.Equal(t, adminId, *recorder.entries[0].UserId)
+	})
+
+	t.Run("unpublish a draft", func(t *testing.T) {
+		post := &dto.PostDB{PostId: uuid.New(), Status: types.Draft}
+		s, rep, recorder := newService(post)
+
+		_, err := s.ResolveReports(context.Background(), adminId, post.PostId, &dto.ResolveReportsRequest{Action: types.ModerationUnpublish})
+		assert.ErrorIs(t, err, errors.ErrorServiceIncorrectData)
+		assert.Equal(t, types.Draft, post.Status)
+		assert.Equal(t, 2, rep.open[post.PostId], "the reports stay open")
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("unknown post", func(t *testing.T) {
+		s, _, _ := newService()
+
+		_, err := s.ResolveReports(context.Background(), adminId, uuid.New(), &dto.ResolveReportsRequest{Action: types.ModerationDismiss})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
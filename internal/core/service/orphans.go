@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+// orphanBatchSize is how many objects are looked up in the images table at once
+const orphanBatchSize = 500
+
+type OrphanRepository interface {
+	GetExistingImageIds(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+}
+
+// OrphanCleaner removes stored images no images row refers to anymore, left behind by failed uploads
+// or posts deleted by hand. Objects younger than minAge are kept, their row may still be on its way.
+// Objects not named after an image id are not ours and never touched.
+type OrphanCleaner struct {
+	rep    OrphanRepository
+	stor   storage.ImageStorage
+	minAge time.Duration
+	// clock defaults to time.Now
+	clock func() time.Time
+}
+
+type orphanCandidate struct {
+	id  uuid.UUID
+	obj storage.ImageObject
+}
+
+func NewOrphanCleaner(rep OrphanRepository, stor storage.ImageStorage, minAge time.Duration, clock func() time.Time) *OrphanCleaner {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &OrphanCleaner{rep, stor, minAge, clock}
+}
+
+// imageIdOf reads the image id from an object name, which is the id with an optional extension
+func imageIdOf(objectName string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(strings.TrimSuffix(objectName, path.Ext(objectName)))
+	return id, err == nil
+}
+
+// Clean walks the storage and removes every orphan it finds, with dryRun they are only reported
+func (c *OrphanCleaner) Clean(ctx context.Context, dryRun bool) (*dto.CleanupImagesResponse, error) {
+	res := &dto.CleanupImagesResponse{DryRun: dryRun, Orphans: []dto.OrphanImageResponse{}}
+	cutoff := c.clock().Add(-c.minAge)
+	batch := make([]orphanCandidate, 0, orphanBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(batch))
+		for i, candidate := range batch {
+			ids[i] = candidate.id
+		}
+		existing, err := c.rep.GetExistingImageIds(ctx, ids)
+		if err != nil {
+			return err
+		}
+		known := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			known[id] = true
+		}
+
+		for _, candidate := range batch {
+			if known[candidate.id] {
+				continue
+			}
+			if !dryRun {
+				if err := c.stor.DeleteImage(ctx, candidate.obj.Name); err != nil {
+					return err
+				}
+			}
+			res.Orphans = append(res.Orphans, dto.OrphanImageResponse{
+				Name:         candidate.obj.Name,
+				LastModified: candidate.obj.LastModified,
+			})
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := c.stor.ListImages(ctx, func(obj storage.ImageObject) error {
+		res.Scanned++
+		id, ok := imageIdOf(obj.Name)
+		if !ok || obj.LastModified.After(cutoff) {
+			return nil
+		}
+		batch = append(batch, orphanCandidate{id, obj})
+		if len(batch) < orphanBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Run is one cleanup of the orphan cleaner job
+func (c *OrphanCleaner) Run(ctx context.Context) error {
+	res, err := c.Clean(ctx, false)
+	if err != nil {
+		return err
+	}
+	if len(res.Orphans) > 0 {
+		slog.Info("removed orphaned images", slog.String("job", "orphan_cleaner"), slog.Int("count", len(res.Orphans)))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type fakeOrphanRepository struct {
+	images  map[uuid.UUID]bool
+	batches []int
+}
+
+func (f *fakeOrphanRepository) GetExistingImageIds(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	f.batches = append(f.batches, len(ids))
+	var existing []uuid.UUID
+	for _, id := range ids {
+		if f.images[id] {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+func TestOrphanCleaner_Clean(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	known, orphan, fresh := uuid.New(), uuid.New(), uuid.New()
+	knownName, orphanName, freshName := known.String()+".png", orphan.String()+".jpg", fresh.String()+".png"
+	// objects stored before image names had an extension are orphans too
+	legacy := uuid.New().String()
+
+	newStorage := func() *recordingStorage {
+		return &recordingStorage{
+			put: map[string]string{knownName: "png", orphanName: "jpg", freshName: "png", legacy: "gif", "notes.txt": "txt"},
+			modified: map[string]time.Time{
+				knownName:   now.Add(-48 * time.Hour),
+				orphanName:  now.Add(-48 * time.Hour),
+				freshName:   now.Add(-time.Hour),
+				legacy:      now.Add(-48 * time.Hour),
+				"notes.txt": now.Add(-48 * time.Hour),
+			},
+		}
+	}
+	rep := &fakeOrphanRepository{images: map[uuid.UUID]bool{known: true}}
+	expected := []dto.OrphanImageResponse{
+		{Name: legacy, LastModified: now.Add(-48 * time.Hour)},
+		{Name: orphanName, LastModified: now.Add(-48 * time.Hour)},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		stor := newStorage()
+		c := NewOrphanCleaner(rep, stor, 24*time.Hour, func() time.Time { return now })
+
+		res, err := c.Clean(context.Background(), true)
+		require.NoError(t, err)
+		assert.True(t, res.DryRun)
+		assert.Equal(t, 5, res.Scanned)
+		assert.ElementsMatch(t, expected, res.Orphans)
+		assert.Empty(t, stor.deleted)
+	})
+
+	t.Run("removes orphans", func(t *testing.T) {
+		stor := newStorage()
+		c := NewOrphanCleaner(rep, stor, 24*time.Hour, func() time.Time { return now })
+
+		res, err := c.Clean(context.Background(), false)
+		require.NoError(t, err)
+		assert.False(t, res.DryRun)
+		assert.ElementsMatch(t, expected, res.Orphans)
+		assert.ElementsMatch(t, []string{orphanName, legacy}, stor.deleted)
+		assert.Contains(t, stor.put, knownName)
+		assert.Contains(t, stor.put, freshName)
+		assert.Contains(t, stor.put, "notes.txt")
+	})
+}
+
+func TestOrphanCleaner_Batches(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	stor := &recordingStorage{put: map[string]string{}, modified: map[string]time.Time{}}
+	for range orphanBatchSize + 1 {
+		name := fmt.Sprintf("%s.png", uuid.New())
+		stor.put[name] = "png"
+		stor.modified[name] = now.Add(-48 * time.Hour)
+	}
+	rep := &fakeOrphanRepository{}
+	c := NewOrphanCleaner(rep, stor, 24*time.Hour, func() time.Time { return now })
+
+	res, err := c.Clean(context.Background(), true)
+	require.NoError(t, err)
+	assert.Len(t, res.Orphans, orphanBatchSize+1)
+	assert.Equal(t, []int{orphanBatchSize, 1}, rep.batches)
+}
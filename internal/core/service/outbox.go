@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/events"
+)
+
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]*dto.OutboxEventDB, error)
+	MarkEventsSent(ctx context.Context, ids []uuid.UUID) error
+}
+
+type EventSink interface {
+	Publish(ctx context.Context, event events.Event) error
+}
+
+// OutboxRelay periodically hands the events the repository wrote along with post changes to the sink.
+// An event is marked sent only after the sink took it, so delivery is at least once: a crash between
+// the two sends it again.
+type OutboxRelay struct {
+	rep       OutboxRepository
+	sink      EventSink
+	batchSize int
+}
+
+func NewOutboxRelay(rep OutboxRepository, sink EventSink, batchSize int) *OutboxRelay {
+	return &OutboxRelay{rep, sink, batchSize}
+}
+
+// Tick sends pending events oldest first until the batch is done or the sink fails. The events after a
+// failed one wait for the next tick as well, so the events of a post always arrive in order.
+func (r *OutboxRelay) Tick(ctx context.Context) (int, error) {
+	pending, err := r.rep.GetPendingEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := make([]uuid.UUID, 0, len(pending))
+	for _, event := range pending {
+		err = r.sink.Publish(ctx, events.Event{
+			Id:        event.EventId,
+			Type:      string(event.Type),
+			Key:       event.PostId.String(),
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+		if err != nil {
+			break
+		}
+		sent = append(sent, event.EventId)
+	}
+	if len(sent) > 0 {
+		if merr := r.rep.MarkEventsSent(ctx, sent); merr != nil {
+			return 0, merr
+		}
+	}
+	return len(sent), err
+}
+
+// Run is one tick of the outbox relay job
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	count, err := r.Tick(ctx)
+	if count > 0 {
+		slog.Debug("published events", slog.String("job", "outbox_relay"), slog.Int("count", count))
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakeOutboxRepository struct {
+	events []*dto.OutboxEventDB
+	sent   map[uuid.UUID]bool
+}
+
+func (f *fakeOutboxRepository) add(postId uuid.UUID, eventType types.EventType) uuid.UUID {
+	id := uuid.New()
+	f.events = append(f.events, &dto.OutboxEventDB{EventId: id, Seq: int64(len(f.events) + 1), PostId: postId, Type: eventType})
+	return id
+}
+
+func (f *fakeOutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]*dto.OutboxEventDB, error) {
+	var pending []*dto.OutboxEventDB
+	for _, event := range f.events {
+		if !f.sent[event.EventId] && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeOutboxRepository) MarkEventsSent(ctx context.Context, ids []uuid.UUID) error {
+	if f.sent == nil {
+		f.sent = map[uuid.UUID]bool{}
+	}
+	for _, id := range ids {
+		f.sent[id] = true
+	}
+	return nil
+}
+
+// failingAfterSink takes limit events in total and fails the rest
+type failingAfterSink struct {
+	limit     int
+	published []uuid.UUID
+}
+
+func (s *failingAfterSink) Publish(ctx context.Context, event events.Event) error {
+	if len(s.published) >= s.limit {
+		return errors.New("broker unavailable")
+	}
+	s.published = append(s.published, event.Id)
+	return nil
+}
+
+func TestOutboxRelay_RedeliversAfterFailure(t *testing.T) {
+	postId := uuid.New()
+	rep := &fakeOutboxRepository{}
+	created := rep.add(postId, types.EventPostCreated)
+	published := rep.add(postId, types.EventPostStatusChanged)
+	sink := &failingAfterSink{}
+	relay := NewOutboxRelay(rep, sink, 100)
+
+	count, err := relay.Tick(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, count)
+	assert.Empty(t, rep.sent)
+
+	sink.limit = 2
+	count, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []uuid.UUID{created, published}, sink.published)
+
+	count, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestOutboxRelay_StopsAtFirstFailure(t *testing.T) {
+	rep := &fakeOutboxRepository{}
+	first := rep.add(uuid.New(), types.EventPostCreated)
+	second := rep.add(uuid.New(), types.EventPostCreated)
+	sink := &failingAfterSink{limit: 1}
+	relay := NewOutboxRelay(rep, sink, 100)
+
+	count, err := relay.Tick(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, rep.sent[first])
+	assert.False(t, rep.sent[second])
+
+	sink.limit = 2
+	count, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uuid.UUID{first, second}, sink.published)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/cache"
+)
+
+// postListGeneration counts the invalidations, the listings are stored under the generation they were
+// loaded in, so Invalidate is a single increment and older listings are left to expire
+const postListGeneration = "posts:published:generation"
+
+// PostListCache keeps the published posts listings for ttl. Whatever changes a published post has to
+// Invalidate it, other changes such as views and likes show up after ttl at the latest. A failing cache
+// is logged and skipped, readers get the listing from the repository then.
+type PostListCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewPostListCache returns nil for a Nop cache or a zero ttl, a nil PostListCache loads every time
+func NewPostListCache(c cache.Cache, ttl time.Duration) *PostListCache {
+	if _, nop := c.(cache.Nop); c == nil || nop || ttl <= 0 {
+		return nil
+	}
+	return &PostListCache{cache: c, ttl: ttl}
+}
+
+// Get returns the cached listing for opts or loads and stores it
+func (c *PostListCache) Get(ctx context.Context, opts dto.PostListOptions, load func() ([]*dto.GetPostResponse, error)) ([]*dto.GetPostResponse, error) {
+	if c == nil {
+		return load()
+	}
+
+	// the generation is read before the load, a listing loaded across an Invalidate lands in the old one
+	generation, ok, err := c.cache.Get(ctx, postListGeneration)
+	if err != nil {
+		slog.Warn("read posts cache", slog.String("error", err.Error()))
+		return load()
+	}
+	if !ok {
+		generation = []byte("0")
+	}
+	key := fmt.Sprintf("posts:published:%s:%s:%s:%s", generation, opts.Sort, opts.Order, strconv.Quote(opts.Tag))
+
+	data, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("read posts cache", slog.String("error", err.Error()))
+	}
+	if ok {
+		var posts dto.GetPostsResponse
+		if err := json.Unmarshal(data, &posts); err == nil {
+			c.hits.Add(1)
+			return posts, nil
+		}
+	}
+
+	c.misses.Add(1)
+	posts, err := load()
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(dto.GetPostsResponse(posts))
+	if err == nil {
+		err = c.cache.Set(ctx, key, data, c.ttl)
+	}
+	if err != nil {
+		slog.Warn("write posts cache", slog.String("error", err.Error()))
+	}
+	return posts, nil
+}
+
+// Invalidate makes the next Get of every listing load it again
+func (c *PostListCache) Invalidate() {
+	if c == nil {
+		return
+	}
+	if _, err := c.cache.Incr(context.Background(), postListGeneration); err != nil {
+		// the listings run out after ttl anyway
+		slog.Warn("invalidate posts cache", slog.String("error", err.Error()))
+	}
+}
+
+// Hits counts the listings served from the cache since the start
+func (c *PostListCache) Hits() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.hits.Load()
+}
+
+// Misses counts the listings that had to be loaded since the start
+func (c *PostListCache) Misses() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.misses.Load()
+}
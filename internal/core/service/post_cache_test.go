@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/cache"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// countingReaderRepository counts the listings that reach the repository
+type countingReaderRepository struct {
+	*fakeReaderRepository
+	listings  int
+	trendings int
+}
+
+func (f *countingReaderRepository) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
+	f.listings++
+	return f.fakeReaderRepository.GetPublishedPosts(ctx, opts)
+}
+
+func (f *countingReaderRepository) GetTrendingPosts(ctx context.Context, window time.Duration, likeWeight float64, limit int) ([]*dto.PostTrendingDB, error) {
+	f.trendings++
+	return f.fakeReaderRepository.GetTrendingPosts(ctx, window, likeWeight, limit)
+}
+
+func TestPostListCache_Disabled(t *testing.T) {
+	assert.Nil(t, NewPostListCache(nil, time.Minute))
+	assert.Nil(t, NewPostListCache(cache.Nop{}, time.Minute))
+	assert.Nil(t, NewPostListCache(cache.NewMemory(nil), 0))
+}
+
+func TestPostListCache_InvalidatedOnPublish(t *testing.T) {
+	seeded, authorId := seedStatuses(t)
+	rep := &countingReaderRepository{fakeReaderRepository: seeded}
+	listings := NewPostListCache(cache.NewMemory(nil), time.Hour)
+	reader := NewReaderService(rep, &fakeViewRecorder{}, nil, listings, nil, nil, nil)
+	// the poster works on the same posts, a publish there is what readers must see
+	poster := NewPosterService(newFakePosterRepository(seeded.posts...), fakePosterStorage{}, nil, nil, false, time.Hour, listings, nil, nil, ImageLimits{}, 0, false)
+
+	posts, err := reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []types.PostStatus{types.Published}, statusesOf(posts))
+	_, err = reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rep.listings)
+	assert.Equal(t, int64(1), listings.Hits())
+	assert.Equal(t, int64(1), listings.Misses())
+
+	draft := seeded.posts[0]
+	require.Equal(t, types.Draft, draft.Status)
+	_, err = poster.PublishPost(context.Background(), authorId, draft.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.NoError(t, err)
+
+	posts, err = reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, 2, rep.listings)
+}
+
+func TestPostListCache_StaleForTTL(t *testing.T) {
+	seeded, _ := seedStatuses(t)
+	rep := &countingReaderRepository{fakeReaderRepository: seeded}
+	now := time.Now()
+	listings := NewPostListCache(cache.NewMemory(func() time.Time { return now }), 30*time.Second)
+	reader := NewReaderService(rep, &fakeViewRecorder{}, nil, listings, nil, nil, nil)
+
+	_, err := reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	// a change that does not invalidate, like an archive straight in the database
+	for _, post := range seeded.posts {
+		post.Status = types.Archived
+	}
+
+	now = now.Add(29 * time.Second)
+	posts, err := reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, posts, 1, "served from the cache within the ttl")
+
+	now = now.Add(2 * time.Second)
+	posts, err = reader.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+	assert.Equal(t, 2, rep.listings)
+}
+
+func TestPostListCache_KeyedByOptions(t *testing.T) {
+	seeded, _ := seedStatuses(t)
+	rep := &countingReaderRepository{fakeReaderRepository: seeded}
+	reader := NewReaderService(rep, &fakeViewRecorder{}, nil, NewPostListCache(cache.NewMemory(nil), time.Hour), nil, nil, nil)
+
+	for _, opts := range []dto.PostListOptions{{}, {Tag: "go"}, {Sort: types.SortTitle}, {Tag: "go"}, {Status: types.Draft}} {
+		_, err := reader.GetPublishedPosts(context.Background(), opts)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, rep.listings)
+}
@@ -1,39 +1,128 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"io"
+	"log/slog"
 	"mime/multipart"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/storage"
 	"github.com/xkarasb/blog/pkg/types"
 )
 
 type PosterRepository interface {
-	GetPostByIdempotencyKey(idempotencyKey string) (*dto.PostDB, error)
-	GetPostById(id uuid.UUID) (*dto.PostDB, error)
-	UpdatePost(id uuid.UUID, title, content string, status types.PostStatus) (*dto.PostDB, error)
-	CreateImage(imageId, postId uuid.UUID, imageUrl string) (*dto.ImageDB, error)
-	DeleteImage(imageId uuid.UUID) (*dto.ImageDB, error)
+	GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error)
+	GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error)
+	SchedulePost(ctx context.Context, id uuid.UUID, publishAt time.Time) (*dto.PostDB, error)
+	CreateImage(ctx context.Context, imageId, postId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error)
+	CountPostImages(ctx context.Context, postId uuid.UUID) (int, error)
+	GetUserImageBytes(ctx context.Context, userId uuid.UUID) (int64, error)
+	DeleteImage(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error)
+	GetImageById(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error)
+	GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error)
+	UpdateImage(ctx context.Context, postId, imageId uuid.UUID, patch *dto.ImagePatch) (*dto.ImageDB, error)
+	ReplaceImage(ctx context.Context, postId, imageId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error)
+	ReorderImages(ctx context.Context, postId uuid.UUID, ids []uuid.UUID) ([]*dto.ImageDB, error)
+	TrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error)
+	GetTrashedPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error)
+	UntrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error)
+	GetPostViewStats(ctx context.Context, postId uuid.UUID) ([]*dto.ViewDayDB, error)
+	GetPostRevisions(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*dto.PostRevisionDB, error)
+	CountPostRevisions(ctx context.Context, postId uuid.UUID) (int, error)
+	GetPostRevision(ctx context.Context, postId, revisionId uuid.UUID) (*dto.PostRevisionDB, error)
+	ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error
+	HasImportedPost(ctx context.Context, authorId uuid.UUID, hash, title, content string) (bool, error)
+	ImportPost(ctx context.Context, authorId uuid.UUID, imported *dto.ImportedPost) (*dto.PostDB, error)
+	CreatePreviewToken(ctx context.Context, postId uuid.UUID, tokenHash string, expiresAt time.Time) error
+	DeletePreviewTokens(ctx context.Context, postId uuid.UUID) (int, error)
 }
 
-type PosterStorageRepositry interface {
-	PutImage(fileName string, file io.Reader, fileSize int64, contentType string) (string, error)
-	DeleteImage(objectName string) error
+// postTransitions lists the status changes an author may request, any other pair is ErrorServiceIncorrectData
+var postTransitions = map[types.PostStatus][]types.PostStatus{
+	types.Draft:     {types.Published, types.Scheduled},
+	types.Published: {types.Draft, types.Archived},
+	types.Archived:  {types.Draft},
+	// rescheduling moves publish_at, publishing early skips the wait
+	types.Scheduled: {types.Draft, types.Published, types.Scheduled},
+}
+
+func canTransition(from, to types.PostStatus) bool {
+	return slices.Contains(postTransitions[from], to)
 }
 
 type PosterService struct {
 	rep  PosterRepository
-	stor PosterStorageRepositry
+	stor storage.ImageStorage
+	// links is nil for a public bucket
+	links *ImageLinks
+	// stripExif removes EXIF from uploaded JPEGs before they are stored
+	stripExif bool
+	// trashRetention is how long a trashed post can be restored before TrashCleaner purges it
+	trashRetention time.Duration
+	// recorder keeps publishing and deletions, nil records nothing
+	recorder AuditRecorder
+	// listings is the cache of the published posts listings, nil when they are not cached
+	listings *PostListCache
+	// published gets every post PublishPost publishes, nil when nobody streams them
+	published *PublishHub
+	// reading counts words and reading time of edited content, nil at the default speed
+	reading *ReadingTime
+	// limits bounds the images of AddImage and AddImages
+	limits ImageLimits
+	// previewTTL is how long a preview link works
+	previewTTL time.Duration
+	// verifiedEmail lets only authors who verified their email publish or schedule posts
+	verifiedEmail bool
+}
+
+// ImageLimits bounds the images of posts, zero values leave them unbounded
+type ImageLimits struct {
+	// PerPost is the most images a post can have
+	PerPost int
+	// Bytes is the size of the largest image
+	Bytes int64
+	// Workers is how many images of a bulk upload are stored at once, below one they are stored one by one
+	Workers int
+	// Quota is how many bytes of images an author may store over all their posts
+	Quota int64
 }
 
-func NewPosterService(rep PosterRepository, stor PosterStorageRepositry) *PosterService {
-	return &PosterService{rep, stor}
+func NewPosterService(rep PosterRepository, stor storage.ImageStorage, recorder AuditRecorder, links *ImageLinks, stripExif bool, trashRetention time.Duration, listings *PostListCache, published *PublishHub, reading *ReadingTime, limits ImageLimits, previewTTL time.Duration, verifiedEmail bool) *PosterService {
+	if previewTTL <= 0 {
+		previewTTL = defaultPreviewTTL
+	}
+	return &PosterService{rep, stor, links, stripExif, trashRetention, recorder, listings, published, reading, limits, previewTTL, verifiedEmail}
 }
 
-func (s *PosterService) getPostAuthor(userId, postId uuid.UUID) (*dto.PostDB, error) {
-	postDB, err := s.rep.GetPostById(postId)
+// counted sets the stats of a patch that replaces the content
+func (s *PosterService) counted(patch *dto.PostPatch) *dto.PostPatch {
+	if patch.Content != nil {
+		stats := s.reading.Stats(*patch.Content)
+		patch.Stats = &stats
+	}
+	return patch
+}
+
+// touched invalidates the listings when a change concerned a published post, others are not listed
+func (s *PosterService) touched(statuses ...types.PostStatus) {
+	if slices.Contains(statuses, types.Published) {
+		s.listings.Invalidate()
+	}
+}
+
+func (s *PosterService) getPostAuthor(ctx context.Context, userId, postId uuid.UUID) (*dto.PostDB, error) {
+	postDB, err := s.rep.GetPostById(ctx, postId)
 
 	if err != nil {
 		return nil, err
@@ -44,101 +133,630 @@ func (s *PosterService) getPostAuthor(userId, postId uuid.UUID) (*dto.PostDB, er
 	return postDB, nil
 }
 
-func (s *PosterService) EditPost(userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error) {
-	postDB, err := s.getPostAuthor(userId, postId)
+// EditPost replaces title, content and tags of a post of the user. An edit based on an outdated
+// ExpectedUpdatedAt fails with ErrorServiceConflict and returns the post as it is now for the client to merge.
+func (s *PosterService) EditPost(ctx context.Context, userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error) {
+	patch := &dto.PostPatch{Title: &post.Title, Content: &post.Content, Excerpt: trimmed(post.Excerpt)}
+	return s.updatePost(ctx, userId, postId, patch, post.Tags, post.ExpectedUpdatedAt)
+}
+
+// PatchPost changes only the fields present in the request and answers like EditPost,
+// a request without any of them leaves the post untouched and returns it as it is.
+func (s *PosterService) PatchPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PatchPostRequest) (*dto.EditPostResponse, error) {
+	if post.Title == nil && post.Content == nil && post.Excerpt == nil && post.Tags == nil {
+		postDB, err := s.getPostAuthor(ctx, userId, postId)
+		if err != nil {
+			return nil, err
+		}
+		return editPostResponse(postDB, s.reading), nil
+	}
+
+	patch := &dto.PostPatch{Title: post.Title, Content: post.Content, Excerpt: trimmed(post.Excerpt)}
+	return s.updatePost(ctx, userId, postId, patch, post.Tags, post.ExpectedUpdatedAt)
+}
+
+func (s *PosterService) updatePost(ctx context.Context, userId, postId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.EditPostResponse, error) {
+	_, err := s.getPostAuthor(ctx, userId, postId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	postDB, err = s.rep.UpdatePost(postId, post.Title, post.Content, postDB.Status)
+	postDB, err := s.rep.UpdatePost(ctx, postId, userId, s.counted(patch), tags, expectedUpdatedAt)
+	if err == sql.ErrNoRows && expectedUpdatedAt != nil {
+		// the post was there a moment ago, it is either a newer version now or in the trash
+		current, err := s.rep.GetPostById(ctx, postId)
+		if err != nil {
+			return nil, err
+		}
+		return editPostResponse(current, s.reading), errors.ErrorServiceConflict
+	}
 	if err != nil {
 		return nil, err
 	}
+	s.touched(postDB.Status)
+
+	return editPostResponse(postDB, s.reading), nil
+}
+
+// trimmed drops the surrounding whitespace of an optional field, nil stays nil
+func trimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
 
-	postRes := &dto.EditPostResponse{
+// editPostResponse answers with postDB, reading counts a post stored without counts
+func editPostResponse(postDB *dto.PostDB, reading *ReadingTime) *dto.EditPostResponse {
+	excerpt := ""
+	if postDB.Excerpt != nil {
+		excerpt = *postDB.Excerpt
+	}
+	stats, _ := reading.statsOf(postDB)
+	return &dto.EditPostResponse{
 		PostId:         postDB.PostId,
 		AuthorId:       postDB.AuthorId,
 		IdempotencyKey: postDB.IdempotencyKey,
 		Title:          postDB.Title,
 		Content:        postDB.Content,
+		Excerpt:        excerpt,
 		Status:         postDB.Status,
+		Tags:           tagsOf(postDB),
 		CreatedAt:      postDB.CreatedAt,
 		UpdatedAt:      postDB.UpdatedAt,
+
+		WordCount:          stats.WordCount,
+		ReadingTimeMinutes: stats.ReadingTimeMinutes,
+		// the old key stays until the clients moved to idempotency_key
+		LegacyIdempotencyKey: postDB.IdempotencyKey,
 	}
-	return postRes, nil
 }
-func (s *PosterService) PublishPost(userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error) {
-	postDB, err := s.getPostAuthor(userId, postId)
+
+// GetPostRevisions pages through the earlier versions of a post of the user, latest first
+func (s *PosterService) GetPostRevisions(ctx context.Context, userId, postId uuid.UUID, limit, offset int) (*dto.GetPostRevisionsResponse, error) {
+	_, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.rep.GetPostRevisions(ctx, postId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.rep.CountPostRevisions(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.GetPostRevisionsResponse{
+		Revisions: make([]dto.PostRevisionResponse, len(revisions)),
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for i, revision := range revisions {
+		res.Revisions[i] = dto.PostRevisionResponse{
+			RevisionId: revision.RevisionId,
+			Title:      revision.Title,
+			Content:    revision.Content,
+			Status:     revision.Status,
+			EditedAt:   revision.EditedAt,
+			EditedBy:   revision.EditedBy,
+		}
+	}
+	return res, nil
+}
+
+// RestoreRevision copies the title and content of a revision back into the post, the status and tags
+// stay as they are. Like any edit it keeps the replaced version as a new revision.
+func (s *PosterService) RestoreRevision(ctx context.Context, userId, postId, revisionId uuid.UUID) (*dto.EditPostResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if post.Status != types.Published {
+	revision, err := s.rep.GetPostRevision(ctx, postId, revisionId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrorServiceRevisionNotFound
+		}
+		return nil, err
+	}
+
+	postDB, err = s.rep.UpdatePost(ctx, postId, userId, s.counted(&dto.PostPatch{Title: &revision.Title, Content: &revision.Content}), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.touched(postDB.Status)
+
+	return editPostResponse(postDB, s.reading), nil
+}
+func (s *PosterService) PublishPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(postDB.Status, post.Status) {
 		return nil, errors.ErrorServiceIncorrectData
 	}
+	if err := s.checkVerified(ctx, userId, post.Status); err != nil {
+		return nil, err
+	}
+	previous := postDB.Status
 
-	postDB, err = s.rep.UpdatePost(postId, postDB.Title, postDB.Content, post.Status)
+	if post.Status == types.Scheduled {
+		if post.PublishAt == nil || !post.PublishAt.After(time.Now()) {
+			return nil, errors.ErrorServiceIncorrectData
+		}
+		postDB, err = s.rep.SchedulePost(ctx, postId, *post.PublishAt)
+	} else {
+		postDB, err = s.rep.UpdatePost(ctx, postId, userId, &dto.PostPatch{Status: &post.Status}, nil, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
+	s.touched(previous, postDB.Status)
+	if postDB.Status == types.Published {
+		audit(ctx, s.recorder, types.AuditPublishPost, userId, postId)
+		s.published.Publish(&dto.PublishedPostEvent{
+			PostId:      postDB.PostId,
+			AuthorId:    postDB.AuthorId,
+			Title:       postDB.Title,
+			Tags:        postDB.Tags,
+			PublishedAt: postDB.UpdatedAt,
+		})
+	}
 
 	postRes := &dto.PublishPostResponse{
 		PostId: postDB.PostId,
+		Status: postDB.Status,
+	}
+	if postDB.Status == types.Scheduled {
+		postRes.PublishAt = postDB.PublishAt
 	}
 	return postRes, nil
 }
 
-func (s *PosterService) AddImage(userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error) {
-	_, err := s.getPostAuthor(userId, postId)
+// checkVerified refuses to publish or schedule for an author who has not verified their email,
+// drafts and edits need no verified email
+func (s *PosterService) checkVerified(ctx context.Context, userId uuid.UUID, status types.PostStatus) error {
+	if !s.verifiedEmail || (status != types.Published && status != types.Scheduled) {
+		return nil
+	}
+	user, err := s.rep.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerified {
+		return errors.ErrorEmailNotVerified
+	}
+	return nil
+}
+
+func (s *PosterService) AddImage(ctx context.Context, userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.imageRoom(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	if room == 0 {
+		return nil, errors.ErrorServiceImageLimit
+	}
+	quota, err := s.quotaRoom(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if quota >= 0 && fileHeader.Size > quota {
+		return nil, errors.ErrorQuotaExceeded
+	}
+
+	stored, err := s.storeImage(ctx, file, fileHeader.Size)
+	if err != nil {
+		return nil, err
+	}
+	imageRes, err := s.createImage(ctx, postId, stored)
+	if err != nil {
+		return nil, err
+	}
+	s.touched(postDB.Status)
+	return imageRes, nil
+}
 
+// AddImages adds files as images of a post of the user in the order they come. Each file is checked
+// first, one that is no image or too large fails alone, as do the valid ones past the room the post has
+// left or past the storage quota of the user. The others are stored limits.Workers at a time and then
+// added in order.
+func (s *PosterService) AddImages(ctx context.Context, userId, postId uuid.UUID, files []*multipart.FileHeader) ([]dto.ImageUpload, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.imageRoom(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.quotaRoom(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	size := fileHeader.Size
-	contentType := fileHeader.Header.Get("Content-Type")
+	uploads := make([]dto.ImageUpload, len(files))
+	var valid []int
+	for i, file := range files {
+		uploads[i].Filename = file.Filename
+		if uploads[i].Err = s.checkImage(file); uploads[i].Err != nil {
+			continue
+		}
+		if room >= 0 && len(valid) >= room {
+			uploads[i].Err = errors.ErrorServiceImageLimit
+			continue
+		}
+		if quota >= 0 {
+			if file.Size > quota {
+				uploads[i].Err = errors.ErrorQuotaExceeded
+				continue
+			}
+			quota -= file.Size
+		}
+		valid = append(valid, i)
+	}
+
+	stored := make([]storedImage, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(s.limits.Workers, 1), len(valid)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				stored[i], uploads[i].Err = s.storeImageFile(ctx, files[i])
+			}
+		}()
+	}
+	for _, i := range valid {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
+	added := false
+	for _, i := range valid {
+		if uploads[i].Err != nil {
+			continue
+		}
+		uploads[i].Image, uploads[i].Err = s.createImage(ctx, postId, stored[i])
+		added = added || uploads[i].Err == nil
+	}
+	if added {
+		s.touched(postDB.Status)
+	}
+	return uploads, nil
+}
+
+// storedImage is an image put into the storage that has no images row yet
+type storedImage struct {
+	imageId uuid.UUID
+	link    string
+	size    int64
+}
+
+// imageRoom returns how many more images the post takes, -1 without a cap. Uploads running at the same
+// time do not see each other, together they can take a post past the cap.
+func (s *PosterService) imageRoom(ctx context.Context, postId uuid.UUID) (int, error) {
+	if s.limits.PerPost <= 0 {
+		return -1, nil
+	}
+	count, err := s.rep.CountPostImages(ctx, postId)
+	if err != nil {
+		return 0, err
+	}
+	return max(s.limits.PerPost-count, 0), nil
+}
+
+// quotaRoom returns how many more bytes of images the user may store, -1 without a quota. Like imageRoom
+// it does not see uploads running at the same time, together they can take the user a little past the quota.
+func (s *PosterService) quotaRoom(ctx context.Context, userId uuid.UUID) (int64, error) {
+	if s.limits.Quota <= 0 {
+		return -1, nil
+	}
+	used, err := s.rep.GetUserImageBytes(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	return max(s.limits.Quota-used, 0), nil
+}
+
+// StorageQuota returns how many bytes of images the user stores over all their posts and how many they
+// may, a limit of 0 is no quota. Deleting images or purging posts gives the bytes back.
+func (s *PosterService) StorageQuota(ctx context.Context, userId uuid.UUID) (*dto.StorageQuotaResponse, error) {
+	used, err := s.rep.GetUserImageBytes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.StorageQuotaResponse{UsedBytes: used, LimitBytes: max(s.limits.Quota, 0)}, nil
+}
+
+// checkImage tells whether a file of a bulk upload is an image small enough, without storing it
+func (s *PosterService) checkImage(header *multipart.FileHeader) error {
+	if s.limits.Bytes > 0 && header.Size > s.limits.Bytes {
+		return errors.ErrorServiceImageTooLarge
+	}
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, _, _, err = sniffImage(file)
+	return err
+}
+
+func (s *PosterService) storeImageFile(ctx context.Context, header *multipart.FileHeader) (storedImage, error) {
+	file, err := header.Open()
+	if err != nil {
+		return storedImage{}, err
+	}
+	defer file.Close()
+	return s.storeImage(ctx, file, header.Size)
+}
+
+// storeImage puts an upload of size bytes into the storage as a new image
+func (s *PosterService) storeImage(ctx context.Context, file io.Reader, size int64) (storedImage, error) {
 	imageId, err := uuid.NewUUID()
+	if err != nil {
+		return storedImage{}, err
+	}
+	link, _, err := s.putImage(ctx, imageId, file, size)
+	if err != nil {
+		return storedImage{}, err
+	}
+	return storedImage{imageId, link, size}, nil
+}
 
+// putImage puts an upload of size bytes into the storage as <imageId>.<ext>, JPEGs without their EXIF
+// if asked to, and returns its link and object name
+func (s *PosterService) putImage(ctx context.Context, imageId uuid.UUID, file io.Reader, size int64) (string, string, error) {
+	return putUpload(ctx, s.stor, imageId.String(), file, size, s.limits.Bytes, s.stripExif)
+}
+
+// createImage adds the images row of a stored image, it goes after the other images of the post
+func (s *PosterService) createImage(ctx context.Context, postId uuid.UUID, stored storedImage) (*dto.AddImageResponse, error) {
+	imageDB, err := s.rep.CreateImage(ctx, stored.imageId, postId, stored.link, stored.size)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.links.Link(stored.link)
 	if err != nil {
 		return nil, err
 	}
+	return &dto.AddImageResponse{ImageId: imageDB.ImageId, ImageUrl: link}, nil
+}
 
-	link, err := s.stor.PutImage(imageId.String(), file, size, contentType)
+// ReplaceImage puts file in place of an image of a post of the user, the image keeps its id, position
+// and caption. A file of the same type overwrites the object, so links to it stay valid. One of another
+// type is stored next to it, the image then points to the new object and the old one is removed.
+func (s *PosterService) ReplaceImage(ctx context.Context, userId, postId, imageId uuid.UUID, file io.Reader, fileHeader *multipart.FileHeader) (*dto.ImageResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.rep.GetImageById(ctx, imageId)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrorServiceImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if image.PostId != postId {
+		return nil, errors.ErrorServiceImageNotFound
+	}
+	// only what the new file adds over the old one counts
+	quota, err := s.quotaRoom(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
-	imageDB, err := s.rep.CreateImage(imageId, postId, link)
+	if quota >= 0 && fileHeader.Size-image.SizeBytes > quota {
+		return nil, errors.ErrorQuotaExceeded
+	}
 
+	link, name, err := s.putImage(ctx, imageId, file, fileHeader.Size)
 	if err != nil {
 		return nil, err
 	}
+	old := objectName(image.ImageUrl)
+	image, err = s.rep.ReplaceImage(ctx, postId, imageId, link, fileHeader.Size)
+	if err != nil {
+		// an overwritten object cannot be put back, a new one is not needed anymore
+		if name != old {
+			s.removeImage(ctx, name)
+		}
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrorServiceImageNotFound
+		}
+		return nil, err
+	}
+	if name != old {
+		s.removeImage(ctx, old)
+	}
+	s.touched(postDB.Status)
+	return s.links.imageResponse(image)
+}
 
-	imageRes := &dto.AddImageResponse{
-		ImageId:  imageDB.ImageId,
-		ImageUrl: link,
+// removeImage deletes an object no image points to anymore. The request it was made for succeeded,
+// so a failure is only logged.
+func (s *PosterService) removeImage(ctx context.Context, name string) {
+	if err := s.stor.DeleteImage(ctx, name); err != nil {
+		slogctx.Logger(ctx).Warn("remove replaced image", slog.String("object", name), slog.String("error", err.Error()))
 	}
+}
 
-	return imageRes, nil
+// GetPostImages lists the images of a post visible to the user, which is any post of their own
+// and the published posts of others. Anything else is sql.ErrNoRows like a post that does not exist.
+func (s *PosterService) GetPostImages(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostImagesResponse, error) {
+	postDB, err := s.rep.GetPostById(ctx, postId)
+
+	if err != nil {
+		return nil, err
+	}
+	if postDB.AuthorId != userId && postDB.Status != types.Published {
+		return nil, sql.ErrNoRows
+	}
+
+	images, err := s.rep.GetPostImages(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	return s.links.imagesResponse(images)
 }
 
-func (s *PosterService) DeleteImage(userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error) {
-	_, err := s.getPostAuthor(userId, postId)
+// UpdateImage changes caption and position of an image of a post of the user
+func (s *PosterService) UpdateImage(ctx context.Context, userId, postId, imageId uuid.UUID, req *dto.UpdateImageRequest) (*dto.ImageResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+	if err != nil {
+		return nil, err
+	}
 
+	patch := &dto.ImagePatch{Caption: trimmed(req.Caption), Position: req.Position}
+	image, err := s.rep.UpdateImage(ctx, postId, imageId, patch)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrorServiceImageNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	s.touched(postDB.Status)
+	return s.links.imageResponse(image)
+}
 
-	if _, err = s.rep.DeleteImage(imageId); err != nil {
+// ReorderImages puts the images of a post of the user in the order of req, which lists all of them
+func (s *PosterService) ReorderImages(ctx context.Context, userId, postId uuid.UUID, req *dto.ReorderImagesRequest) (*dto.GetPostImagesResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+	if err != nil {
 		return nil, err
 	}
 
-	if err = s.stor.DeleteImage(imageId.String()); err != nil {
+	images, err := s.rep.ReorderImages(ctx, postId, req.ImageIds)
+	if err == errors.ErrorRepositoryImageOrder {
+		return nil, errors.ErrorServiceImageOrder
+	}
+	if err != nil {
 		return nil, err
 	}
+	s.touched(postDB.Status)
+	return s.links.imagesResponse(images)
+}
+
+// DeleteImage removes an image of a post of the user, the object to remove is named by its stored image_url
+func (s *PosterService) DeleteImage(ctx context.Context, userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := s.rep.GetImageById(ctx, imageId)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrorServiceImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// an image of another post is as missing as one that does not exist
+	if image.PostId != postId {
+		return nil, errors.ErrorServiceImageNotFound
+	}
+
+	if _, err = s.rep.DeleteImage(ctx, imageId); err != nil {
+		return nil, err
+	}
+	s.touched(postDB.Status)
+
+	if err = s.stor.DeleteImage(ctx, objectName(image.ImageUrl)); err != nil {
+		return nil, err
+	}
+	audit(ctx, s.recorder, types.AuditDeleteImage, userId, imageId)
 
 	return &dto.DeleteImageResponse{ImageId: imageId}, nil
 
 }
+
+func (s *PosterService) TrashPost(ctx context.Context, userId, postId uuid.UUID) (*dto.TrashPostResponse, error) {
+	postDB, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	postDB, err = s.rep.TrashPost(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	s.touched(postDB.Status)
+	audit(ctx, s.recorder, types.AuditDeletePost, userId, postId)
+
+	return &dto.TrashPostResponse{
+		PostId:  postDB.PostId,
+		PurgeAt: postDB.DeletedAt.Add(s.trashRetention),
+	}, nil
+}
+
+// RestorePost takes a post back from the trash, past the retention window the post is treated as gone
+func (s *PosterService) RestorePost(ctx context.Context, userId, postId uuid.UUID) (*dto.RestorePostResponse, error) {
+	postDB, err := s.rep.GetTrashedPostById(ctx, postId)
+
+	if err != nil {
+		return nil, err
+	}
+	if postDB.AuthorId != userId {
+		return nil, errors.ErrorServiceNoAccess
+	}
+	if !postDB.DeletedAt.Add(s.trashRetention).After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+
+	postDB, err = s.rep.UntrashPost(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	s.touched(postDB.Status)
+
+	return &dto.RestorePostResponse{
+		PostId: postDB.PostId,
+		Status: postDB.Status,
+	}, nil
+}
+
+func (s *PosterService) GetPostStats(ctx context.Context, userId, postId uuid.UUID) (*dto.PostStatsResponse, error) {
+	_, err := s.getPostAuthor(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.rep.GetPostViewStats(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.PostStatsResponse{
+		PostId: postId,
+		Days:   make([]dto.ViewDayResponse, len(days)),
+	}
+	for i, day := range days {
+		res.Days[i] = dto.ViewDayResponse{
+			Day:   day.Day.Format(time.DateOnly),
+			Views: day.Views,
+		}
+		res.Views += day.Views
+	}
+	return res, nil
+}
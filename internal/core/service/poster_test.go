@@ -0,0 +1,1293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakePosterRepository struct {
+	posts  map[uuid.UUID]*dto.PostDB
+	images map[uuid.UUID]*dto.ImageDB
+	// created holds the images in the order they were added
+	created []uuid.UUID
+	views   map[uuid.UUID][]*dto.ViewDayDB
+	// revisions are kept oldest first like the rows of post_revisions
+	revisions []*dto.PostRevisionDB
+	// previews may be shared with a fakeReaderRepository reading the links
+	previews fakePreviews
+	// unverified are the authors who have not verified their email
+	unverified map[uuid.UUID]bool
+}
+
+// fakePreviews holds the preview links by token hash like post_preview_tokens
+type fakePreviews map[string]*fakePreview
+
+type fakePreview struct {
+	postId    uuid.UUID
+	expiresAt time.Time
+}
+
+func newFakePosterRepository(posts ...*dto.PostDB) *fakePosterRepository {
+	rep := &fakePosterRepository{posts: map[uuid.UUID]*dto.PostDB{}, images: map[uuid.UUID]*dto.ImageDB{}, views: map[uuid.UUID][]*dto.ViewDayDB{}, previews: fakePreviews{}}
+	for _, post := range posts {
+		rep.posts[post.PostId] = post
+	}
+	return rep
+}
+
+func (f *fakePosterRepository) GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error) {
+	for _, post := range f.posts {
+		if post.IdempotencyKey == idempotencyKey {
+			return post, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// live returns a post that is not in the trash, like the deleted_at IS NULL predicate
+func (f *fakePosterRepository) live(id uuid.UUID) (*dto.PostDB, bool) {
+	post, ok := f.posts[id]
+	return post, ok && post.DeletedAt == nil
+}
+
+func (f *fakePosterRepository) GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post, ok := f.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	return &dto.UserDB{UserId: id, Role: types.Author, EmailVerified: !f.unverified[id]}, nil
+}
+
+func (f *fakePosterRepository) UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error) {
+	post, ok := f.live(id)
+	if !ok || expectedUpdatedAt != nil && !post.UpdatedAt.Equal(*expectedUpdatedAt) {
+		return nil, sql.ErrNoRows
+	}
+	f.revisions = append(f.revisions, &dto.PostRevisionDB{RevisionId: uuid.New(), PostId: id,
+		Title: post.Title, Content: post.Content, Status: post.Status, EditedAt: time.Now(), EditedBy: editorId})
+	if patch.Title != nil {
+		post.Title = *patch.Title
+	}
+	if patch.Content != nil {
+		post.Content = *patch.Content
+	}
+	if patch.Excerpt != nil {
+		post.Excerpt = nil
+		if *patch.Excerpt != "" {
+			post.Excerpt = patch.Excerpt
+		}
+	}
+	if patch.Stats != nil {
+		counted := *patch.Stats
+		post.WordCount, post.ReadingTimeMinutes = &counted.WordCount, &counted.ReadingTimeMinutes
+	}
+	if patch.Status != nil {
+		post.Status = *patch.Status
+	}
+	post.UpdatedAt = time.Now()
+	if tags != nil {
+		post.Tags = tags
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) SchedulePost(ctx context.Context, id uuid.UUID, publishAt time.Time) (*dto.PostDB, error) {
+	post, ok := f.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	post.Status, post.PublishAt = types.Scheduled, &publishAt
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) CreateImage(ctx context.Context, imageId, postId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	image := &dto.ImageDB{ImageId: imageId, PostId: postId, ImageUrl: imageUrl, SizeBytes: size}
+	f.images[imageId] = image
+	f.created = append(f.created, imageId)
+	return image, nil
+}
+
+func (f *fakePosterRepository) DeleteImage(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
+	image, ok := f.images[imageId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	delete(f.images, imageId)
+	return image, nil
+}
+
+func (f *fakePosterRepository) GetImageById(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
+	image, ok := f.images[imageId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return image, nil
+}
+
+func (f *fakePosterRepository) CountPostImages(ctx context.Context, postId uuid.UUID) (int, error) {
+	images, _ := f.GetPostImages(ctx, postId)
+	return len(images), nil
+}
+
+func (f *fakePosterRepository) GetUserImageBytes(ctx context.Context, userId uuid.UUID) (int64, error) {
+	var used int64
+	for _, image := range f.images {
+		if post, ok := f.posts[image.PostId]; ok && post.AuthorId == userId {
+			used += image.SizeBytes
+		}
+	}
+	return used, nil
+}
+
+func (f *fakePosterRepository) GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error) {
+	var images []*dto.ImageDB
+	for _, image := range f.images {
+		if image.PostId == postId {
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+// UpdateImage sets the position as given, renumbering the others is left to the database
+func (f *fakePosterRepository) UpdateImage(ctx context.Context, postId, imageId uuid.UUID, patch *dto.ImagePatch) (*dto.ImageDB, error) {
+	image, ok := f.images[imageId]
+	if !ok || image.PostId != postId {
+		return nil, sql.ErrNoRows
+	}
+	if patch.Position != nil {
+		image.Position = *patch.Position
+	}
+	if patch.Caption != nil {
+		image.Caption = patch.Caption
+	}
+	return image, nil
+}
+
+func (f *fakePosterRepository) ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error {
+	var posts []*dto.PostDB
+	for _, post := range f.posts {
+		if post.AuthorId == authorId && post.DeletedAt == nil {
+			posts = append(posts, post)
+		}
+	}
+	slices.SortFunc(posts, func(a, b *dto.PostDB) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	for _, post := range posts {
+		images, _ := f.GetPostImages(ctx, post.PostId)
+		slices.SortFunc(images, func(a, b *dto.ImageDB) int { return a.Position - b.Position })
+		if err := fn(post, images); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakePosterRepository) HasImportedPost(ctx context.Context, authorId uuid.UUID, hash, title, content string) (bool, error) {
+	for _, post := range f.posts {
+		if post.AuthorId == authorId && post.DeletedAt == nil &&
+			((post.ImportHash != nil && *post.ImportHash == hash) || (post.Title == title && post.Content == content)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakePosterRepository) CreatePreviewToken(ctx context.Context, postId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	f.previews[tokenHash] = &fakePreview{postId, expiresAt}
+	return nil
+}
+
+func (f *fakePosterRepository) DeletePreviewTokens(ctx context.Context, postId uuid.UUID) (int, error) {
+	revoked := 0
+	for hash, preview := range f.previews {
+		if preview.postId == postId {
+			if preview.expiresAt.After(time.Now()) {
+				revoked++
+			}
+			delete(f.previews, hash)
+		}
+	}
+	return revoked, nil
+}
+
+func (f *fakePosterRepository) ImportPost(ctx context.Context, authorId uuid.UUID, imported *dto.ImportedPost) (*dto.PostDB, error) {
+	now := time.Now()
+	hash := imported.Hash
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, IdempotencyKey: imported.IdempotencyKey, Title: imported.Title,
+		Content: imported.Content, Status: types.Draft, Tags: imported.Tags, ImportHash: &hash, CreatedAt: now, UpdatedAt: now}
+	if imported.Excerpt != "" {
+		excerpt := imported.Excerpt
+		post.Excerpt = &excerpt
+	}
+	f.posts[post.PostId] = post
+	for i, image := range imported.Images {
+		f.images[image.ImageId] = &dto.ImageDB{ImageId: image.ImageId, PostId: post.PostId, ImageUrl: image.ImageUrl,
+			Position: i + 1, Caption: image.Caption, SizeBytes: image.SizeBytes, CreatedAt: now, UpdatedAt: now}
+		f.created = append(f.created, image.ImageId)
+	}
+	return post, nil
+}
+
+func (f *fakePosterRepository) ReplaceImage(ctx context.Context, postId, imageId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	image, ok := f.images[imageId]
+	if !ok || image.PostId != postId {
+		return nil, sql.ErrNoRows
+	}
+	image.ImageUrl, image.SizeBytes, image.UpdatedAt = imageUrl, size, image.CreatedAt.Add(time.Minute)
+	return image, nil
+}
+
+func (f *fakePosterRepository) ReorderImages(ctx context.Context, postId uuid.UUID, ids []uuid.UUID) ([]*dto.ImageDB, error) {
+	images, _ := f.GetPostImages(ctx, postId)
+	if len(ids) != len(images) {
+		return nil, errors.ErrorRepositoryImageOrder
+	}
+	res := make([]*dto.ImageDB, len(ids))
+	for i, id := range ids {
+		image, ok := f.images[id]
+		if !ok || image.PostId != postId || slices.Contains(ids[:i], id) {
+			return nil, errors.ErrorRepositoryImageOrder
+		}
+		image.Position = i + 1
+		res[i] = image
+	}
+	return res, nil
+}
+
+func (f *fakePosterRepository) TrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post, ok := f.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	post.DeletedAt = &now
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) GetTrashedPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post, ok := f.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) UntrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	post, ok := f.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	post.DeletedAt = nil
+	copied := *post
+	return &copied, nil
+}
+
+func (f *fakePosterRepository) GetPostViewStats(ctx context.Context, postId uuid.UUID) ([]*dto.ViewDayDB, error) {
+	return f.views[postId], nil
+}
+
+func (f *fakePosterRepository) GetPostRevisions(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*dto.PostRevisionDB, error) {
+	var res []*dto.PostRevisionDB
+	for _, revision := range slices.Backward(f.revisions) {
+		if revision.PostId == postId {
+			res = append(res, revision)
+		}
+	}
+	if offset >= len(res) {
+		return nil, nil
+	}
+	return res[offset:min(offset+limit, len(res))], nil
+}
+
+func (f *fakePosterRepository) CountPostRevisions(ctx context.Context, postId uuid.UUID) (int, error) {
+	revisions, _ := f.GetPostRevisions(ctx, postId, len(f.revisions), 0)
+	return len(revisions), nil
+}
+
+func (f *fakePosterRepository) GetPostRevision(ctx context.Context, postId, revisionId uuid.UUID) (*dto.PostRevisionDB, error) {
+	for _, revision := range f.revisions {
+		if revision.PostId == postId && revision.RevisionId == revisionId {
+			return revision, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+type fakePosterStorage struct{}
+
+func (fakePosterStorage) PutImage(ctx context.Context, fileName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	return "/images/" + fileName, nil
+}
+
+func (fakePosterStorage) DeleteImage(ctx context.Context, objectName string) error {
+	return nil
+}
+
+func (fakePosterStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	return nil, "", os.ErrNotExist
+}
+
+func (fakePosterStorage) ImageExists(objectName string) (bool, error) {
+	return false, nil
+}
+
+func (fakePosterStorage) Public() bool {
+	return true
+}
+
+func (fakePosterStorage) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	return fakePresigner{}.PresignGet(objectName, ttl)
+}
+
+func (fakePosterStorage) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	return nil
+}
+
+// fakeFile is an upload held in memory
+type fakeFile struct {
+	*strings.Reader
+}
+
+func (fakeFile) Close() error {
+	return nil
+}
+
+const pngBytes = "\x89PNG\r\n\x1a\nfake"
+
+func TestPosterService_PublishPostTransitions(t *testing.T) {
+	statuses := []types.PostStatus{types.Draft, types.Published, types.Archived, types.Scheduled}
+	allowed := map[[2]types.PostStatus]bool{
+		{types.Draft, types.Published}:     true,
+		{types.Draft, types.Scheduled}:     true,
+		{types.Published, types.Draft}:     true,
+		{types.Published, types.Archived}:  true,
+		{types.Archived, types.Draft}:      true,
+		{types.Scheduled, types.Draft}:     true,
+		{types.Scheduled, types.Published}: true,
+		{types.Scheduled, types.Scheduled}: true,
+	}
+	publishAt := time.Now().Add(time.Hour)
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(fmt.Sprintf("%s to %s", from, to), func(t *testing.T) {
+				authorId := uuid.New()
+				post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: from}
+				rep := newFakePosterRepository(post)
+				s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+				resp, err := s.PublishPost(context.Background(), authorId, post.PostId, &dto.PublishPostRequest{Status: to, PublishAt: &publishAt})
+				if allowed[[2]types.PostStatus{from, to}] {
+					require.NoError(t, err)
+					assert.Equal(t, post.PostId, resp.PostId)
+					assert.Equal(t, to, resp.Status)
+					assert.Equal(t, to, rep.posts[post.PostId].Status)
+				} else {
+					assert.ErrorIs(t, err, errors.ErrorServiceIncorrectData)
+					assert.Equal(t, from, rep.posts[post.PostId].Status)
+				}
+			})
+		}
+	}
+}
+
+func TestPosterService_PublishPostForeignPost(t *testing.T) {
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: uuid.New(), Status: types.Draft}
+	s := NewPosterService(newFakePosterRepository(post), fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	_, err := s.PublishPost(context.Background(), uuid.New(), post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+
+	_, err = s.PublishPost(context.Background(), post.AuthorId, uuid.New(), &dto.PublishPostRequest{Status: types.Published})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPosterService_PublishPostUnverifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Draft}
+	rep := newFakePosterRepository(post)
+	rep.unverified = map[uuid.UUID]bool{authorId: true}
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, true)
+
+	_, err := s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	assert.ErrorIs(t, err, errors.ErrorEmailNotVerified)
+	publishAt := time.Now().Add(time.Hour)
+	_, err = s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Scheduled, PublishAt: &publishAt})
+	assert.ErrorIs(t, err, errors.ErrorEmailNotVerified, "scheduling publishes later")
+	assert.Equal(t, types.Draft, rep.posts[post.PostId].Status)
+
+	// drafts stay editable
+	_, err = s.EditPost(ctx, authorId, post.PostId, &dto.EditPostRequest{Title: "new", Content: "draft"})
+	assert.NoError(t, err)
+
+	rep.unverified[authorId] = false
+	resp, err := s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.NoError(t, err)
+	assert.Equal(t, types.Published, resp.Status)
+
+	// taking a post back needs no verified email
+	rep.unverified[authorId] = true
+	_, err = s.PublishPost(ctx, authorId, post.PostId, &dto.PublishPostRequest{Status: types.Draft})
+	assert.NoError(t, err)
+}
+
+func TestPosterService_SchedulePostInThePast(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	past := time.Now().Add(-time.Minute)
+	_, err := s.PublishPost(context.Background(), authorId, post.PostId, &dto.PublishPostRequest{Status: types.Scheduled, PublishAt: &past})
+	assert.ErrorIs(t, err, errors.ErrorServiceIncorrectData)
+	_, err = s.PublishPost(context.Background(), authorId, post.PostId, &dto.PublishPostRequest{Status: types.Scheduled})
+	assert.ErrorIs(t, err, errors.ErrorServiceIncorrectData)
+	assert.Equal(t, types.Draft, rep.posts[post.PostId].Status)
+
+	future := time.Now().Add(time.Hour)
+	resp, err := s.PublishPost(context.Background(), authorId, post.PostId, &dto.PublishPostRequest{Status: types.Scheduled, PublishAt: &future})
+	require.NoError(t, err)
+	assert.Equal(t, types.Scheduled, resp.Status)
+	require.NotNil(t, resp.PublishAt)
+	assert.True(t, future.Equal(*resp.PublishAt))
+}
+
+func TestPosterService_TrashAndRestore(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Published}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	_, err := s.TrashPost(context.Background(), uuid.New(), post.PostId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+
+	trashed, err := s.TrashPost(context.Background(), authorId, post.PostId)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), trashed.PurgeAt, time.Minute)
+
+	_, err = s.TrashPost(context.Background(), authorId, post.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "new", Content: "new"})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = s.RestorePost(context.Background(), uuid.New(), post.PostId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+
+	restored, err := s.RestorePost(context.Background(), authorId, post.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, types.Published, restored.Status)
+	assert.Nil(t, rep.posts[post.PostId].DeletedAt)
+
+	_, err = s.RestorePost(context.Background(), authorId, post.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPosterService_RestoreAfterRetention(t *testing.T) {
+	authorId := uuid.New()
+	deletedAt := time.Now().Add(-2 * time.Hour)
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft, DeletedAt: &deletedAt}
+	rep := newFakePosterRepository(post)
+
+	_, err := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false).RestorePost(context.Background(), authorId, post.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NotNil(t, rep.posts[post.PostId].DeletedAt)
+}
+
+func TestPosterService_GetPostStats(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Published}
+	rep := newFakePosterRepository(post)
+	rep.views[post.PostId] = []*dto.ViewDayDB{
+		{Day: time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), Views: 2},
+		{Day: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), Views: 3},
+	}
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	_, err := s.GetPostStats(context.Background(), uuid.New(), post.PostId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+
+	stats, err := s.GetPostStats(context.Background(), authorId, post.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.PostStatsResponse{
+		PostId: post.PostId,
+		Views:  5,
+		Days:   []dto.ViewDayResponse{{Day: "2025-01-30", Views: 2}, {Day: "2025-01-31", Views: 3}},
+	}, stats)
+
+	stats, err = s.GetPostStats(context.Background(), authorId, uuid.New())
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+}
+
+func TestPosterService_RevisionsRoundTrip(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "v1", Content: "first", Status: types.Draft}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	_, err := s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "v2", Content: "second"})
+	require.NoError(t, err)
+	_, err = s.PublishPost(context.Background(), authorId, post.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.NoError(t, err)
+
+	page, err := s.GetPostRevisions(context.Background(), authorId, post.PostId, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Revisions, 1)
+	first := page.Revisions[0]
+	assert.Equal(t, "v1", first.Title)
+	assert.Equal(t, types.Draft, first.Status)
+	assert.Equal(t, authorId, first.EditedBy)
+
+	restored, err := s.RestoreRevision(context.Background(), authorId, post.PostId, first.RevisionId)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", restored.Title)
+	assert.Equal(t, "first", restored.Content)
+	// the status is not rolled back with the text
+	assert.Equal(t, types.Published, restored.Status)
+
+	page, err = s.GetPostRevisions(context.Background(), authorId, post.PostId, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, "v2", page.Revisions[0].Title)
+
+	_, err = s.RestoreRevision(context.Background(), authorId, post.PostId, uuid.New())
+	assert.ErrorIs(t, err, errors.ErrorServiceRevisionNotFound)
+}
+
+func TestPosterService_RevisionsForeignPost(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Draft}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	_, err := s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "t2", Content: "c2"})
+	require.NoError(t, err)
+
+	_, err = s.GetPostRevisions(context.Background(), uuid.New(), post.PostId, 10, 0)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+	_, err = s.RestoreRevision(context.Background(), uuid.New(), post.PostId, rep.revisions[0].RevisionId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+	assert.Equal(t, "t2", rep.posts[post.PostId].Title)
+}
+
+func TestPosterService_EditPostStale(t *testing.T) {
+	authorId := uuid.New()
+	readAt := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "base", Content: "c", Status: types.Draft, UpdatedAt: readAt}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	first, err := s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "tab 1", Content: "c", ExpectedUpdatedAt: &readAt})
+	require.NoError(t, err)
+	assert.Equal(t, "tab 1", first.Title)
+
+	current, err := s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "tab 2", Content: "c", ExpectedUpdatedAt: &readAt})
+	assert.ErrorIs(t, err, errors.ErrorServiceConflict)
+	require.NotNil(t, current)
+	assert.Equal(t, "tab 1", current.Title)
+	assert.True(t, first.UpdatedAt.Equal(current.UpdatedAt))
+	assert.Len(t, rep.revisions, 1)
+
+	// without the field the last write wins as before
+	last, err := s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "tab 2", Content: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "tab 2", last.Title)
+
+	_, err = s.TrashPost(context.Background(), authorId, post.PostId)
+	require.NoError(t, err)
+	_, err = s.EditPost(context.Background(), authorId, post.PostId, &dto.EditPostRequest{Title: "tab 3", Content: "c", ExpectedUpdatedAt: &last.UpdatedAt})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPosterService_PatchPost(t *testing.T) {
+	authorId := uuid.New()
+	readAt := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "title", Content: "content",
+		Status: types.Published, Tags: []string{"go"}, UpdatedAt: readAt}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	title := "new title"
+	res, err := s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{Title: &title})
+	require.NoError(t, err)
+	assert.Equal(t, "new title", res.Title)
+	assert.Equal(t, "content", res.Content)
+	assert.Equal(t, types.Published, res.Status)
+	assert.Equal(t, []string{"go"}, res.Tags)
+
+	content := "new content"
+	res, err = s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{Content: &content})
+	require.NoError(t, err)
+	assert.Equal(t, "new title", res.Title)
+	assert.Equal(t, "new content", res.Content)
+	assert.Equal(t, 2, res.WordCount)
+	assert.Equal(t, 1, res.ReadingTimeMinutes)
+	assert.Len(t, rep.revisions, 2)
+
+	// nothing to change writes nothing, not even a revision
+	noop, err := s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, res, noop)
+	assert.Len(t, rep.revisions, 2)
+
+	excerpt := "  a summary "
+	res, err = s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{Excerpt: &excerpt})
+	require.NoError(t, err)
+	assert.Equal(t, "a summary", res.Excerpt)
+	assert.Equal(t, "new content", res.Content)
+	excerpt = ""
+	res, err = s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{Excerpt: &excerpt})
+	require.NoError(t, err)
+	assert.Empty(t, res.Excerpt, "back to the one taken from the content")
+	assert.Len(t, rep.revisions, 4)
+
+	_, err = s.PatchPost(context.Background(), authorId, post.PostId, &dto.PatchPostRequest{Title: &title, ExpectedUpdatedAt: &readAt})
+	assert.ErrorIs(t, err, errors.ErrorServiceConflict)
+
+	_, err = s.PatchPost(context.Background(), uuid.New(), post.PostId, &dto.PatchPostRequest{})
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+}
+
+func TestPosterService_GetPostImages(t *testing.T) {
+	authorId := uuid.New()
+	draft := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	published := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Published}
+	rep := newFakePosterRepository(draft, published)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	for _, post := range []*dto.PostDB{draft, published} {
+		_, err := rep.CreateImage(context.Background(), uuid.New(), post.PostId, "http://images/"+post.PostId.String(), 0)
+		require.NoError(t, err)
+	}
+	readerId := uuid.New()
+
+	images, err := s.GetPostImages(context.Background(), authorId, draft.PostId)
+	require.NoError(t, err)
+	require.Len(t, images.Images, 1)
+	assert.Equal(t, "http://images/"+draft.PostId.String(), images.Images[0].ImageUrl)
+
+	images, err = s.GetPostImages(context.Background(), readerId, published.PostId)
+	require.NoError(t, err)
+	assert.Len(t, images.Images, 1)
+
+	// a draft of someone else is as missing as an unknown post
+	_, err = s.GetPostImages(context.Background(), readerId, draft.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = s.GetPostImages(context.Background(), authorId, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPosterService_ImageLinks(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+
+	tests := []struct {
+		name     string
+		links    *ImageLinks
+		expected func(imageId uuid.UUID) string
+	}{
+		{
+			name:     "public bucket",
+			links:    nil,
+			expected: func(imageId uuid.UUID) string { return "/images/" + imageId.String() + ".png" },
+		},
+		{
+			name:     "private bucket",
+			links:    NewImageLinks(fakePresigner{}, time.Minute),
+			expected: func(imageId uuid.UUID) string { return "http://minio/images/" + imageId.String() + ".png?expires=1m0s" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := newFakePosterRepository(post)
+			s := NewPosterService(rep, fakePosterStorage{}, nil, tt.links, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+			header := &multipart.FileHeader{Filename: "pic.png", Size: 12, Header: textproto.MIMEHeader{"Content-Type": {"image/png"}}}
+			added, err := s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(pngBytes)}, header)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected(added.ImageId), added.ImageUrl)
+			// the stored path stays unsigned so links can be made again later
+			assert.Equal(t, "/images/"+added.ImageId.String()+".png", rep.images[added.ImageId].ImageUrl)
+
+			images, err := s.GetPostImages(context.Background(), authorId, post.PostId)
+			require.NoError(t, err)
+			require.Len(t, images.Images, 1)
+			assert.Equal(t, tt.expected(added.ImageId), images.Images[0].ImageUrl)
+		})
+	}
+}
+
+func TestPosterService_AddImageTypes(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+
+	tests := []struct {
+		name string
+		data string
+		ext  string
+		err  error
+	}{
+		{name: "jpeg", data: "\xff\xd8\xff\xe0fake", ext: ".jpg"},
+		{name: "png", data: pngBytes, ext: ".png"},
+		{name: "gif", data: "GIF89afake", ext: ".gif"},
+		{name: "webp", data: "RIFF\x00\x00\x00\x00WEBPVP8 fake", ext: ".webp"},
+		{name: "text", data: "just text", err: errors.ErrorServiceUnsupportedImage},
+		{name: "empty", data: "", err: errors.ErrorServiceUnsupportedImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := newFakePosterRepository(post)
+			stor := &recordingStorage{}
+			s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+			// the extension follows the bytes, not the name or header the client sent
+			header := &multipart.FileHeader{Filename: "upload.txt", Size: int64(len(tt.data)), Header: textproto.MIMEHeader{"Content-Type": {"text/plain"}}}
+			added, err := s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(tt.data)}, header)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Empty(t, rep.images)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "/images/"+added.ImageId.String()+tt.ext, added.ImageUrl)
+			assert.Equal(t, tt.data, stor.put[added.ImageId.String()+tt.ext])
+		})
+	}
+}
+
+// parallelStorage can be written to concurrently, it records the most puts that ran at once and fails
+// those of the content fail
+type parallelStorage struct {
+	fakePosterStorage
+	fail string
+
+	mu      sync.Mutex
+	put     int
+	running int
+	most    int
+}
+
+func (s *parallelStorage) PutImage(ctx context.Context, fileName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	s.mu.Lock()
+	s.running++
+	s.most = max(s.most, s.running)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	if string(data) == s.fail {
+		return "", errors.ErrorRepositoryStorageTimeout
+	}
+	s.mu.Lock()
+	s.put++
+	s.mu.Unlock()
+	return "/images/" + fileName, nil
+}
+
+// uploadedFiles sends contents as the "images" of a multipart form and returns their headers as a server reads them
+func uploadedFiles(t *testing.T, contents ...string) []*multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, content := range contents {
+		part, err := writer.CreateFormFile("images", fmt.Sprintf("%d.png", i))
+		require.NoError(t, err)
+		part.Write([]byte(content))
+	}
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["images"]
+}
+
+func TestPosterService_AddImages(t *testing.T) {
+	authorId := uuid.New()
+	large := pngBytes + strings.Repeat("x", 100)
+
+	tests := []struct {
+		name     string
+		limits   ImageLimits
+		existing int
+		contents []string
+		fail     string
+		errs     []error
+	}{
+		{
+			name:     "all added",
+			limits:   ImageLimits{Workers: 2},
+			contents: []string{pngBytes + "1", pngBytes + "2", pngBytes + "3", pngBytes + "4"},
+			errs:     []error{nil, nil, nil, nil},
+		},
+		{
+			name:     "failed files do not stop the others",
+			limits:   ImageLimits{Bytes: 50, Workers: 4},
+			contents: []string{pngBytes + "1", "just text", large, pngBytes + "fail", pngBytes + "2"},
+			fail:     pngBytes + "fail",
+			errs: []error{nil, errors.ErrorServiceUnsupportedImage, errors.ErrorServiceImageTooLarge,
+				errors.ErrorRepositoryStorageTimeout, nil},
+		},
+		{
+			name:     "cap reached mid-batch",
+			limits:   ImageLimits{PerPost: 3, Workers: 4},
+			existing: 1,
+			contents: []string{pngBytes + "1", "just text", pngBytes + "2", pngBytes + "3"},
+			errs:     []error{nil, errors.ErrorServiceUnsupportedImage, nil, errors.ErrorServiceImageLimit},
+		},
+		{
+			name:     "quota reached mid-batch",
+			limits:   ImageLimits{Quota: int64(2 * len(pngBytes+"1")), Workers: 2},
+			contents: []string{pngBytes + "1", "just text", pngBytes + "2", pngBytes + "3"},
+			errs:     []error{nil, errors.ErrorServiceUnsupportedImage, nil, errors.ErrorQuotaExceeded},
+		},
+		{
+			name:     "post full",
+			limits:   ImageLimits{PerPost: 1},
+			existing: 1,
+			contents: []string{pngBytes + "1"},
+			errs:     []error{errors.ErrorServiceImageLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+			rep := newFakePosterRepository(post)
+			for range tt.existing {
+				rep.CreateImage(context.Background(), uuid.New(), post.PostId, "/images/existing.png", 0)
+			}
+			stor := &parallelStorage{fail: tt.fail}
+			s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, tt.limits, 0, false)
+
+			uploads, err := s.AddImages(context.Background(), authorId, post.PostId, uploadedFiles(t, tt.contents...))
+			require.NoError(t, err)
+			require.Len(t, uploads, len(tt.contents))
+
+			added := []uuid.UUID{}
+			for i, upload := range uploads {
+				assert.Equal(t, fmt.Sprintf("%d.png", i), upload.Filename)
+				assert.ErrorIs(t, upload.Err, tt.errs[i], upload.Filename)
+				if tt.errs[i] == nil {
+					require.NotNil(t, upload.Image)
+					added = append(added, upload.Image.ImageId)
+				}
+			}
+			assert.Equal(t, added, append([]uuid.UUID{}, rep.created[tt.existing:]...), "images are added in the order of the files")
+			assert.Equal(t, len(added), stor.put)
+			assert.LessOrEqual(t, stor.most, max(tt.limits.Workers, 1))
+		})
+	}
+
+	t.Run("someone else's post", func(t *testing.T) {
+		post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+		s := NewPosterService(newFakePosterRepository(post), &parallelStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+		_, err := s.AddImages(context.Background(), uuid.New(), post.PostId, uploadedFiles(t, pngBytes))
+		assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+	})
+}
+
+func TestPosterService_AddImageLimits(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	rep := newFakePosterRepository(post)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{PerPost: 1, Bytes: 20}, 0, false)
+	add := func(data string) error {
+		header := &multipart.FileHeader{Filename: "image.png", Size: int64(len(data))}
+		_, err := s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(data)}, header)
+		return err
+	}
+
+	assert.ErrorIs(t, add(pngBytes+strings.Repeat("x", 20)), errors.ErrorServiceImageTooLarge)
+	assert.NoError(t, add(pngBytes))
+	assert.ErrorIs(t, add(pngBytes), errors.ErrorServiceImageLimit)
+	assert.Len(t, rep.images, 1)
+}
+
+func TestPosterService_StorageQuota(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	other := &dto.PostDB{PostId: uuid.New(), AuthorId: uuid.New(), Status: types.Draft}
+	rep := newFakePosterRepository(post, other)
+	// the images of others do not count
+	rep.CreateImage(context.Background(), uuid.New(), other.PostId, "/images/other.png", 1000)
+	size := int64(len(pngBytes))
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{Quota: 2 * size}, 0, false)
+	add := func(data string) (*dto.AddImageResponse, error) {
+		header := &multipart.FileHeader{Filename: "image.png", Size: int64(len(data))}
+		return s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(data)}, header)
+	}
+	used := func() int64 {
+		quota, err := s.StorageQuota(context.Background(), authorId)
+		require.NoError(t, err)
+		assert.Equal(t, 2*size, quota.LimitBytes)
+		return quota.UsedBytes
+	}
+
+	first, err := add(pngBytes)
+	require.NoError(t, err)
+	assert.Equal(t, size, used())
+
+	// one byte more than is left is refused before anything is stored, exactly what is left is not
+	_, err = add(pngBytes + "x")
+	assert.ErrorIs(t, err, errors.ErrorQuotaExceeded)
+	_, err = add(pngBytes)
+	require.NoError(t, err)
+	assert.Equal(t, 2*size, used())
+	_, err = add(pngBytes)
+	assert.ErrorIs(t, err, errors.ErrorQuotaExceeded)
+	assert.Len(t, rep.images, 3)
+
+	t.Run("deleting an image frees its bytes", func(t *testing.T) {
+		_, err := s.DeleteImage(context.Background(), authorId, post.PostId, first.ImageId)
+		require.NoError(t, err)
+		assert.Equal(t, size, used())
+
+		_, err = add(pngBytes)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no quota", func(t *testing.T) {
+		s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+		quota, err := s.StorageQuota(context.Background(), authorId)
+		require.NoError(t, err)
+		assert.Equal(t, &dto.StorageQuotaResponse{UsedBytes: 2 * size}, quota)
+
+		header := &multipart.FileHeader{Filename: "image.png", Size: 10 * size}
+		_, err = s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(pngBytes)}, header)
+		assert.NoError(t, err)
+	})
+}
+
+// failingReplaceRepository loses the image to replace once its file is stored
+type failingReplaceRepository struct {
+	*fakePosterRepository
+}
+
+func (f failingReplaceRepository) ReplaceImage(ctx context.Context, postId, imageId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestPosterService_ReplaceImage(t *testing.T) {
+	authorId := uuid.New()
+	gifBytes := "GIF89a fake"
+	setup := func() (*fakePosterRepository, *recordingStorage, *dto.PostDB, uuid.UUID) {
+		post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+		rep := newFakePosterRepository(post)
+		imageId := uuid.New()
+		rep.CreateImage(context.Background(), imageId, post.PostId, "/images/"+imageId.String()+".png", 0)
+		stor := &recordingStorage{put: map[string]string{imageId.String() + ".png": pngBytes + "old"}}
+		return rep, stor, post, imageId
+	}
+	replace := func(s *PosterService, userId, postId, imageId uuid.UUID, data string) (*dto.ImageResponse, error) {
+		header := &multipart.FileHeader{Filename: "image", Size: int64(len(data))}
+		return s.ReplaceImage(context.Background(), userId, postId, imageId, strings.NewReader(data), header)
+	}
+
+	t.Run("same type overwrites the object", func(t *testing.T) {
+		rep, stor, post, imageId := setup()
+		s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+		res, err := replace(s, authorId, post.PostId, imageId, pngBytes+"new")
+		require.NoError(t, err)
+		assert.Equal(t, imageId, res.ImageId)
+		name := imageId.String() + ".png"
+		assert.Equal(t, fmt.Sprintf("/images/%s?v=%d", name, res.UpdatedAt.UnixMilli()), res.ImageUrl)
+		assert.Equal(t, pngBytes+"new", stor.put[name])
+		assert.Empty(t, stor.deleted)
+	})
+
+	t.Run("another type swaps the object", func(t *testing.T) {
+		rep, stor, post, imageId := setup()
+		s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+		res, err := replace(s, authorId, post.PostId, imageId, gifBytes)
+		require.NoError(t, err)
+		assert.Equal(t, imageId, res.ImageId)
+		assert.Equal(t, "/images/"+imageId.String()+".gif", rep.images[imageId].ImageUrl)
+		assert.Equal(t, map[string]string{imageId.String() + ".gif": gifBytes}, stor.put)
+		assert.Equal(t, []string{imageId.String() + ".png"}, stor.deleted, "the old object is removed")
+	})
+
+	t.Run("new object removed when the image is gone", func(t *testing.T) {
+		rep, stor, post, imageId := setup()
+		s := NewPosterService(failingReplaceRepository{rep}, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+		_, err := replace(s, authorId, post.PostId, imageId, gifBytes)
+		assert.ErrorIs(t, err, errors.ErrorServiceImageNotFound)
+		assert.Equal(t, []string{imageId.String() + ".gif"}, stor.deleted)
+		assert.Equal(t, map[string]string{imageId.String() + ".png": pngBytes + "old"}, stor.put)
+	})
+
+	t.Run("only the bytes it adds count against the quota", func(t *testing.T) {
+		rep, stor, post, imageId := setup()
+		rep.images[imageId].SizeBytes = int64(len(pngBytes + "old"))
+		s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{Quota: int64(len(pngBytes + "old"))}, 0, false)
+
+		_, err := replace(s, authorId, post.PostId, imageId, pngBytes+"new!")
+		assert.ErrorIs(t, err, errors.ErrorQuotaExceeded)
+		assert.Equal(t, pngBytes+"old", stor.put[imageId.String()+".png"])
+
+		_, err = replace(s, authorId, post.PostId, imageId, pngBytes+"new")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(pngBytes+"new")), rep.images[imageId].SizeBytes)
+	})
+
+	t.Run("presigned links carry no version", func(t *testing.T) {
+		rep, stor, post, imageId := setup()
+		s := NewPosterService(rep, stor, nil, NewImageLinks(fakePresigner{}, time.Minute), false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+		res, err := replace(s, authorId, post.PostId, imageId, pngBytes+"new")
+		require.NoError(t, err)
+		assert.NotContains(t, res.ImageUrl, "v=")
+	})
+
+	tests := []struct {
+		name    string
+		limits  ImageLimits
+		userId  uuid.UUID
+		imageId func(imageId uuid.UUID) uuid.UUID
+		data    string
+		err     error
+	}{
+		{
+			name:   "someone else's post",
+			userId: uuid.New(),
+			err:    errors.ErrorServiceNoAccess,
+		},
+		{
+			name:    "unknown image",
+			userId:  authorId,
+			imageId: func(uuid.UUID) uuid.UUID { return uuid.New() },
+			err:     errors.ErrorServiceImageNotFound,
+		},
+		{
+			name:   "not an image",
+			userId: authorId,
+			data:   "just text",
+			err:    errors.ErrorServiceUnsupportedImage,
+		},
+		{
+			name:   "too large",
+			limits: ImageLimits{Bytes: 10},
+			userId: authorId,
+			err:    errors.ErrorServiceImageTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep, stor, post, imageId := setup()
+			if tt.imageId != nil {
+				imageId = tt.imageId(imageId)
+			}
+			data := tt.data
+			if data == "" {
+				data = pngBytes + "new"
+			}
+			s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, tt.limits, 0, false)
+
+			_, err := replace(s, tt.userId, post.PostId, imageId, data)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Empty(t, stor.deleted)
+			assert.Len(t, stor.put, 1, "nothing stored")
+		})
+	}
+
+	t.Run("image of another post", func(t *testing.T) {
+		rep, stor, _, imageId := setup()
+		other := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+		rep.posts[other.PostId] = other
+		s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+		_, err := replace(s, authorId, other.PostId, imageId, pngBytes+"new")
+		assert.ErrorIs(t, err, errors.ErrorServiceImageNotFound)
+		assert.Equal(t, pngBytes+"old", stor.put[imageId.String()+".png"])
+	})
+}
+
+func TestPosterService_DeleteImage(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	other := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	rep := newFakePosterRepository(post, other)
+	stor := &recordingStorage{}
+	s := NewPosterService(rep, stor, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+	withExt, legacy, foreign := uuid.New(), uuid.New(), uuid.New()
+	rep.CreateImage(context.Background(), withExt, post.PostId, "/images/"+withExt.String()+".webp", 0)
+	// uploaded before objects had an extension
+	rep.CreateImage(context.Background(), legacy, post.PostId, "/images/"+legacy.String(), 0)
+	rep.CreateImage(context.Background(), foreign, other.PostId, "/images/"+foreign.String()+".png", 0)
+
+	for _, id := range []uuid.UUID{withExt, legacy} {
+		_, err := s.DeleteImage(context.Background(), authorId, post.PostId, id)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{withExt.String() + ".webp", legacy.String()}, stor.deleted)
+
+	_, err := s.DeleteImage(context.Background(), authorId, post.PostId, foreign)
+	assert.ErrorIs(t, err, errors.ErrorServiceImageNotFound)
+	_, err = s.DeleteImage(context.Background(), authorId, post.PostId, uuid.New())
+	assert.ErrorIs(t, err, errors.ErrorServiceImageNotFound)
+	assert.Contains(t, rep.images, foreign)
+}
+
+func TestPosterService_ImageOrder(t *testing.T) {
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	other := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+	rep := newFakePosterRepository(post, other)
+	s := NewPosterService(rep, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	first, second, foreign := uuid.New(), uuid.New(), uuid.New()
+	rep.CreateImage(context.Background(), first, post.PostId, "/images/first.png", 0)
+	rep.CreateImage(context.Background(), second, post.PostId, "/images/second.png", 0)
+	rep.CreateImage(context.Background(), foreign, other.PostId, "/images/foreign.png", 0)
+
+	caption := "  A caption "
+	image, err := s.UpdateImage(context.Background(), authorId, post.PostId, first, &dto.UpdateImageRequest{Caption: &caption})
+	require.NoError(t, err)
+	assert.Equal(t, "A caption", image.Caption)
+	_, err = s.UpdateImage(context.Background(), authorId, post.PostId, foreign, &dto.UpdateImageRequest{Caption: &caption})
+	assert.ErrorIs(t, err, errors.ErrorServiceImageNotFound, "an image of another post is missing")
+	_, err = s.UpdateImage(context.Background(), uuid.New(), post.PostId, first, &dto.UpdateImageRequest{Caption: &caption})
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+
+	images, err := s.ReorderImages(context.Background(), authorId, post.PostId, &dto.ReorderImagesRequest{ImageIds: []uuid.UUID{second, first}})
+	require.NoError(t, err)
+	require.Len(t, images.Images, 2)
+	assert.Equal(t, second, images.Images[0].ImageId)
+	assert.Equal(t, 1, images.Images[0].Position)
+	assert.Equal(t, "A caption", images.Images[1].Caption)
+
+	for name, ids := range map[string][]uuid.UUID{
+		"missing":   {second},
+		"foreign":   {second, foreign},
+		"duplicate": {second, second},
+	} {
+		_, err = s.ReorderImages(context.Background(), authorId, post.PostId, &dto.ReorderImagesRequest{ImageIds: ids})
+		assert.ErrorIs(t, err, errors.ErrorServiceImageOrder, name)
+	}
+}
+
+func TestPosterService_StripExif(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/exif.jpg")
+	require.NoError(t, err)
+	require.Contains(t, string(fixture), "Exif\x00\x00")
+	require.Contains(t, string(fixture), "FixtureCam")
+
+	authorId := uuid.New()
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Status: types.Draft}
+
+	tests := []struct {
+		name      string
+		stripExif bool
+		data      []byte
+		keepsExif bool
+	}{
+		{name: "jpeg", stripExif: true, data: fixture},
+		{name: "disabled", stripExif: false, data: fixture, keepsExif: true},
+		{name: "png untouched", stripExif: true, data: []byte(pngBytes)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stor := &recordingStorage{}
+			s := NewPosterService(newFakePosterRepository(post), stor, nil, nil, tt.stripExif, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+
+			header := &multipart.FileHeader{Filename: "photo", Size: int64(len(tt.data))}
+			added, err := s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(string(tt.data))}, header)
+			require.NoError(t, err)
+			stored := stor.put[path.Base(added.ImageUrl)]
+
+			if tt.keepsExif || !bytes.HasPrefix(tt.data, []byte{0xff, 0xd8}) {
+				assert.Equal(t, string(tt.data), stored)
+				return
+			}
+			assert.NotContains(t, stored, "Exif")
+			assert.NotContains(t, stored, "FixtureCam")
+			// only the metadata is gone, the picture decodes as before
+			img, err := jpeg.Decode(strings.NewReader(stored))
+			require.NoError(t, err)
+			assert.Equal(t, image.Rect(0, 0, 16, 16), img.Bounds())
+			// the marker and the 0x70 bytes of the APP1 segment of the fixture
+			assert.Equal(t, len(tt.data)-2-0x70, len(stored))
+		})
+	}
+
+	// a jpeg cut off before its image data is not an image
+	s := NewPosterService(newFakePosterRepository(post), &recordingStorage{}, nil, nil, true, time.Hour, nil, nil, nil, ImageLimits{}, 0, false)
+	cut := fixture[:40]
+	_, err = s.AddImage(context.Background(), authorId, post.PostId, fakeFile{strings.NewReader(string(cut))}, &multipart.FileHeader{Size: int64(len(cut))})
+	assert.ErrorIs(t, err, errors.ErrorServiceUnsupportedImage)
+}
+
+func TestEditPostResponse_GoldenJSON(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	post := &dto.PostDB{
+		PostId:         uuid.MustParse("6f1c2f3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f"),
+		AuthorId:       uuid.MustParse("0b8a9c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d"),
+		IdempotencyKey: "key-1",
+		Title:          "Title",
+		Content:        "Content",
+		Status:         types.Draft,
+		CreatedAt:      created,
+		UpdatedAt:      created.Add(time.Hour),
+	}
+
+	body, err := easyjson.Marshal(editPostResponse(post, nil))
+	require.NoError(t, err)
+
+	// both spellings carry the key until clients dropped indempotency_key
+	assert.Equal(t, `{"post_id":"6f1c2f3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f","author_id":"0b8a9c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d",`+
+		`"idempotency_key":"key-1","title":"Title","content":"Content","status":"draft","tags":[],`+
+		`"created_at":"2025-03-01T10:00:00Z","updated_at":"2025-03-01T11:00:00Z",`+
+		`"word_count":1,"reading_time_minutes":1,"indempotency_key":"key-1"}`, string(body))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	for _, spelling := range []string{"idempotency_key", "indempotency_key"} {
+		assert.Equal(t, "key-1", decoded[spelling], spelling)
+	}
+}
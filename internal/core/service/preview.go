@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+// defaultPreviewTTL is how long a preview link works when NewPosterService is given none
+const defaultPreviewTTL = 72 * time.Hour
+
+// CreatePreviewToken makes a link letting anyone read the post of the author, drafts included, for previewTTL.
+// Only a hash of the token is stored, it is shown this once. Every call adds a link, earlier ones keep working.
+// The url is left to the caller, it knows the prefix the link is served under.
+func (s *PosterService) CreatePreviewToken(ctx context.Context, authorId, postId uuid.UUID) (*dto.PreviewTokenResponse, error) {
+	if _, err := s.getPostAuthor(ctx, authorId, postId); err != nil {
+		return nil, err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.previewTTL)
+	if err := s.rep.CreatePreviewToken(ctx, postId, hashToken(token), expiresAt); err != nil {
+		return nil, err
+	}
+	return &dto.PreviewTokenResponse{PostId: postId, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// RevokePreviewTokens stops every preview link of the post of the author at once
+func (s *PosterService) RevokePreviewTokens(ctx context.Context, authorId, postId uuid.UUID) (*dto.RevokePreviewTokensResponse, error) {
+	if _, err := s.getPostAuthor(ctx, authorId, postId); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.rep.DeletePreviewTokens(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RevokePreviewTokensResponse{PostId: postId, Revoked: revoked}, nil
+}
+
+// GetPreviewPost returns the post a preview link was made for whatever its status, it is not counted as a
+// view. An unknown, expired or revoked token is sql.ErrNoRows, the same as a trashed post.
+func (s *ReaderService) GetPreviewPost(ctx context.Context, token string) (*dto.GetPostResponse, error) {
+	post, err := s.rep.GetPreviewPost(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.proccessPostsToResponse(ctx, []*dto.PostUserDB{post})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
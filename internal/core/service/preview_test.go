@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// previewFixture shares the posts and preview links of an author between a poster and a reader
+type previewFixture struct {
+	authorId uuid.UUID
+	a, b     *dto.PostDB
+	posters  *fakePosterRepository
+	poster   *PosterService
+	reader   *ReaderService
+}
+
+func newPreviewFixture(ttl time.Duration) *previewFixture {
+	authorId := uuid.New()
+	a := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Draft A", Content: "First", Status: types.Draft}
+	b := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Draft B", Content: "Second", Status: types.Draft}
+	posters := newFakePosterRepository(a, b)
+	readers := &fakeReaderRepository{
+		users:    map[uuid.UUID]*dto.UserDB{authorId: {UserId: authorId, Email: "author@example.com", Role: types.Author}},
+		posts:    []*dto.PostDB{a, b},
+		previews: posters.previews,
+	}
+	return &previewFixture{
+		authorId: authorId,
+		a:        a,
+		b:        b,
+		posters:  posters,
+		poster:   NewPosterService(posters, fakePosterStorage{}, nil, nil, false, time.Hour, nil, nil, nil, ImageLimits{}, ttl, false),
+		reader:   NewReaderService(readers, nil, nil, nil, nil, nil, nil),
+	}
+}
+
+func TestPreviewToken_ReadsTheDraftItWasMadeFor(t *testing.T) {
+	f := newPreviewFixture(time.Hour)
+
+	previewA, err := f.poster.CreatePreviewToken(context.Background(), f.authorId, f.a.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, f.a.PostId, previewA.PostId)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), previewA.ExpiresAt, time.Minute)
+	assert.NotContains(t, f.posters.previews, previewA.Token, "only a hash of the token is stored")
+	previewB, err := f.poster.CreatePreviewToken(context.Background(), f.authorId, f.b.PostId)
+	require.NoError(t, err)
+	assert.NotEqual(t, previewA.Token, previewB.Token)
+
+	post, err := f.reader.GetPreviewPost(context.Background(), previewA.Token)
+	require.NoError(t, err)
+	assert.Equal(t, f.a.PostId, post.PostId, "the token of A reads A and nothing else")
+	assert.Equal(t, types.Draft, post.Status)
+	assert.Equal(t, "First", post.Content)
+
+	post, err = f.reader.GetPreviewPost(context.Background(), previewB.Token)
+	require.NoError(t, err)
+	assert.Equal(t, f.b.PostId, post.PostId)
+
+	for _, token := range []string{"", "guess", previewA.Token[1:], f.a.PostId.String()} {
+		_, err := f.reader.GetPreviewPost(context.Background(), token)
+		assert.ErrorIs(t, err, sql.ErrNoRows, token)
+	}
+
+	t.Run("trashed post", func(t *testing.T) {
+		deleted := time.Now()
+		f.b.DeletedAt = &deleted
+		defer func() { f.b.DeletedAt = nil }()
+		_, err := f.reader.GetPreviewPost(context.Background(), previewB.Token)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestPreviewToken_Expires(t *testing.T) {
+	f := newPreviewFixture(0)
+
+	preview, err := f.poster.CreatePreviewToken(context.Background(), f.authorId, f.a.PostId)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(72*time.Hour), preview.ExpiresAt, time.Minute, "zero takes the default lifetime")
+
+	stored := f.posters.previews[hashToken(preview.Token)]
+	require.NotNil(t, stored)
+	stored.expiresAt = time.Now().Add(-time.Second)
+
+	_, err = f.reader.GetPreviewPost(context.Background(), preview.Token)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	revoked, err := f.poster.RevokePreviewTokens(context.Background(), f.authorId, f.a.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, 0, revoked.Revoked, "an expired link is not counted as revoked")
+}
+
+func TestPreviewToken_Revoke(t *testing.T) {
+	f := newPreviewFixture(time.Hour)
+
+	var tokens []string
+	for range 2 {
+		preview, err := f.poster.CreatePreviewToken(context.Background(), f.authorId, f.a.PostId)
+		require.NoError(t, err)
+		tokens = append(tokens, preview.Token)
+	}
+	previewB, err := f.poster.CreatePreviewToken(context.Background(), f.authorId, f.b.PostId)
+	require.NoError(t, err)
+
+	revoked, err := f.poster.RevokePreviewTokens(context.Background(), f.authorId, f.a.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.RevokePreviewTokensResponse{PostId: f.a.PostId, Revoked: 2}, revoked)
+
+	for _, token := range tokens {
+		_, err := f.reader.GetPreviewPost(context.Background(), token)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	_, err = f.reader.GetPreviewPost(context.Background(), previewB.Token)
+	assert.NoError(t, err, "links of other posts keep working")
+}
+
+func TestPreviewToken_OnlyTheAuthor(t *testing.T) {
+	f := newPreviewFixture(time.Hour)
+	other := uuid.New()
+
+	_, err := f.poster.CreatePreviewToken(context.Background(), other, f.a.PostId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+	_, err = f.poster.RevokePreviewTokens(context.Background(), other, f.a.PostId)
+	assert.ErrorIs(t, err, errors.ErrorServiceNoAccess)
+	_, err = f.poster.CreatePreviewToken(context.Background(), f.authorId, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Empty(t, f.posters.previews)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+type ProfileRepository interface {
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	UpdateDisplayName(ctx context.Context, id uuid.UUID, displayName *string) (*dto.UserDB, error)
+	// SetAvatar returns the avatar_url it replaced, nil when the user had none
+	SetAvatar(ctx context.Context, id uuid.UUID, avatarUrl string) (*string, error)
+}
+
+// ProfileService lets users change how others see them, their display name and avatar
+type ProfileService struct {
+	rep  ProfileRepository
+	stor storage.ImageStorage
+	// links is nil for a public bucket
+	links *ImageLinks
+	// users holds the users behind access tokens, a changed user is forgotten there
+	users *UserCache
+	// listings show the authors of posts, they are dropped when a profile changes
+	listings *PostListCache
+	// stripExif removes EXIF from uploaded JPEGs before they are stored
+	stripExif bool
+	// maxBytes bounds an avatar like any image, zero lets the request limit decide
+	maxBytes int64
+}
+
+func NewProfileService(rep ProfileRepository, stor storage.ImageStorage, links *ImageLinks, users *UserCache, listings *PostListCache, stripExif bool, maxBytes int64) *ProfileService {
+	return &ProfileService{rep, stor, links, users, listings, stripExif, maxBytes}
+}
+
+// GetProfile is the profile of the user as they see it themself, with their email
+func (s *ProfileService) GetProfile(ctx context.Context, userId uuid.UUID) (*dto.UserResponse, error) {
+	user, err := s.rep.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.profileOf(user)
+}
+
+// UpdateProfile changes the display name of the user, surrounding spaces are dropped and an empty one
+// goes back to the masked email
+func (s *ProfileService) UpdateProfile(ctx context.Context, userId uuid.UUID, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	if req.DisplayName == nil {
+		return s.GetProfile(ctx, userId)
+	}
+
+	var displayName *string
+	if name := strings.TrimSpace(*req.DisplayName); name != "" {
+		displayName = &name
+	}
+	user, err := s.rep.UpdateDisplayName(ctx, userId, displayName)
+	if err != nil {
+		return nil, err
+	}
+	s.changed(userId)
+	return s.profileOf(user)
+}
+
+// SetAvatar stores file as the avatar of the user in place of the one they had. It goes through the same
+// checks as the images of posts, the replaced avatar is removed once the new one is in place.
+func (s *ProfileService) SetAvatar(ctx context.Context, userId uuid.UUID, file io.Reader, size int64) (*dto.UserResponse, error) {
+	// every upload gets its own object, so links to the old avatar never show the new one
+	link, name, err := putUpload(ctx, s.stor, storage.AvatarPrefix+uuid.NewString(), file, size, s.maxBytes, s.stripExif)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.rep.SetAvatar(ctx, userId, link)
+	if err != nil {
+		s.removeAvatar(ctx, name)
+		return nil, err
+	}
+	if previous != nil {
+		s.removeAvatar(ctx, avatarObject(*previous))
+	}
+	s.changed(userId)
+	return s.GetProfile(ctx, userId)
+}
+
+// removeAvatar deletes an object no user points to anymore, a failure is only logged
+func (s *ProfileService) removeAvatar(ctx context.Context, name string) {
+	if err := s.stor.DeleteImage(ctx, name); err != nil {
+		slogctx.Logger(ctx).Warn("remove avatar", slog.String("object", name), slog.String("error", err.Error()))
+	}
+}
+
+func (s *ProfileService) changed(userId uuid.UUID) {
+	s.users.Forget(userId)
+	s.listings.Invalidate()
+}
+
+func (s *ProfileService) profileOf(user *dto.UserDB) (*dto.UserResponse, error) {
+	avatar, err := s.links.AvatarLink(user.AvatarUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserResponse{
+		UserId:        user.UserId,
+		DisplayName:   user.Name(),
+		AvatarUrl:     avatar,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
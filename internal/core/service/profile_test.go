@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakeProfileRepository struct {
+	users map[uuid.UUID]*dto.UserDB
+	// failAvatar makes SetAvatar fail as if the row went away
+	failAvatar bool
+}
+
+func (f *fakeProfileRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeProfileRepository) UpdateDisplayName(ctx context.Context, id uuid.UUID, displayName *string) (*dto.UserDB, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	user.DisplayName = displayName
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeProfileRepository) SetAvatar(ctx context.Context, id uuid.UUID, avatarUrl string) (*string, error) {
+	user, ok := f.users[id]
+	if !ok || f.failAvatar {
+		return nil, sql.ErrNoRows
+	}
+	previous := user.AvatarUrl
+	user.AvatarUrl = &avatarUrl
+	return previous, nil
+}
+
+func newProfileService(t *testing.T) (*ProfileService, *fakeProfileRepository, *recordingStorage, uuid.UUID) {
+	t.Helper()
+	userId := uuid.New()
+	rep := &fakeProfileRepository{users: map[uuid.UUID]*dto.UserDB{
+		userId: {UserId: userId, Email: "alice@example.com", Role: types.Reader},
+	}}
+	stor := &recordingStorage{}
+	return NewProfileService(rep, stor, nil, nil, nil, false, 64), rep, stor, userId
+}
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	s, _, _, userId := newProfileService(t)
+
+	name := "  Alice  "
+	profile, err := s.UpdateProfile(context.Background(), userId, &dto.UpdateProfileRequest{DisplayName: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", profile.DisplayName)
+	assert.Equal(t, "alice@example.com", profile.Email, "the user sees their own email")
+
+	profile, err = s.UpdateProfile(context.Background(), userId, &dto.UpdateProfileRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", profile.DisplayName, "a display name left out stays")
+
+	empty := " "
+	profile, err = s.UpdateProfile(context.Background(), userId, &dto.UpdateProfileRequest{DisplayName: &empty})
+	require.NoError(t, err)
+	assert.Equal(t, "a***@example.com", profile.DisplayName)
+}
+
+func TestProfileService_SetAvatar(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\nfake"
+
+	t.Run("replacement removes the old avatar", func(t *testing.T) {
+		s, rep, stor, userId := newProfileService(t)
+
+		first, err := s.SetAvatar(context.Background(), userId, strings.NewReader(png), int64(len(png)))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(first.AvatarUrl, "/images/avatars/"), first.AvatarUrl)
+		firstObject := strings.TrimPrefix(first.AvatarUrl, "/images/")
+		assert.Contains(t, stor.put, firstObject)
+
+		second, err := s.SetAvatar(context.Background(), userId, strings.NewReader(png), int64(len(png)))
+		require.NoError(t, err)
+		assert.NotEqual(t, first.AvatarUrl, second.AvatarUrl, "a new avatar gets a new link")
+		assert.Equal(t, []string{firstObject}, stor.deleted)
+		assert.Len(t, stor.put, 1)
+		assert.Equal(t, second.AvatarUrl, *rep.users[userId].AvatarUrl)
+	})
+
+	t.Run("failed update removes the new avatar", func(t *testing.T) {
+		s, rep, stor, userId := newProfileService(t)
+		old := "/images/avatars/old.png"
+		rep.users[userId].AvatarUrl = &old
+		rep.failAvatar = true
+
+		_, err := s.SetAvatar(context.Background(), userId, strings.NewReader(png), int64(len(png)))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Empty(t, stor.put)
+		require.Len(t, stor.deleted, 1)
+		assert.NotEqual(t, "avatars/old.png", stor.deleted[0], "the avatar in place stays")
+	})
+
+	t.Run("rejected uploads store nothing", func(t *testing.T) {
+		s, _, stor, userId := newProfileService(t)
+
+		_, err := s.SetAvatar(context.Background(), userId, strings.NewReader("just some notes"), 15)
+		assert.ErrorIs(t, err, errors.ErrorServiceUnsupportedImage)
+		_, err = s.SetAvatar(context.Background(), userId, strings.NewReader(png), 65)
+		assert.ErrorIs(t, err, errors.ErrorServiceImageTooLarge)
+		assert.Empty(t, stor.put)
+	})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+// PublishHub hands the posts PosterService and PublishScheduler publish to the open streams of this process.
+// A subscriber that has buffer events waiting is dropped instead of holding up the publisher, its channel
+// is closed.
+type PublishHub struct {
+	buffer int
+
+	mu     sync.Mutex
+	subs   map[chan *dto.PublishedPostEvent]struct{}
+	closed bool
+}
+
+func NewPublishHub(buffer int) *PublishHub {
+	return &PublishHub{buffer: max(buffer, 1), subs: map[chan *dto.PublishedPostEvent]struct{}{}}
+}
+
+// Subscribe returns the events published from now on and the func that ends the subscription.
+// The channel is closed when the subscriber fell behind or the hub was closed.
+func (h *PublishHub) Subscribe() (<-chan *dto.PublishedPostEvent, func()) {
+	events := make(chan *dto.PublishedPostEvent, h.buffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(events)
+		return events, func() {}
+	}
+	h.subs[events] = struct{}{}
+	return events, func() { h.drop(events) }
+}
+
+// Publish never blocks, subscribers without room for event are dropped
+func (h *PublishHub) Publish(event *dto.PublishedPostEvent) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for events := range h.subs {
+		select {
+		case events <- event:
+		default:
+			delete(h.subs, events)
+			close(events)
+		}
+	}
+}
+
+// Subscribers counts the open subscriptions
+func (h *PublishHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close ends every subscription, later ones are closed right away
+func (h *PublishHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for events := range h.subs {
+		delete(h.subs, events)
+		close(events)
+	}
+}
+
+func (h *PublishHub) drop(events chan *dto.PublishedPostEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[events]; ok {
+		delete(h.subs, events)
+		close(events)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestPublishHub_PublishPost(t *testing.T) {
+	authorId := uuid.New()
+	draft := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Draft, Tags: []string{"go"}}
+	published := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "t", Content: "c", Status: types.Published}
+	hub := NewPublishHub(4)
+	events, cancel := hub.Subscribe()
+	defer cancel()
+	s := NewPosterService(newFakePosterRepository(draft, published), fakePosterStorage{}, nil, nil, false, time.Hour, nil, hub, nil, ImageLimits{}, 0, false)
+
+	_, err := s.PublishPost(context.Background(), authorId, draft.PostId, &dto.PublishPostRequest{Status: types.Published})
+	require.NoError(t, err)
+	// only posts becoming published are streamed
+	_, err = s.PublishPost(context.Background(), authorId, published.PostId, &dto.PublishPostRequest{Status: types.Archived})
+	require.NoError(t, err)
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, draft.PostId, event.PostId)
+	assert.Equal(t, authorId, event.AuthorId)
+	assert.Equal(t, []string{"go"}, event.Tags)
+}
+
+func TestPublishHub_Close(t *testing.T) {
+	hub := NewPublishHub(4)
+	before, _ := hub.Subscribe()
+
+	hub.Close()
+	after, cancel := hub.Subscribe()
+	cancel()
+	hub.Publish(&dto.PublishedPostEvent{PostId: uuid.New()})
+
+	_, open := <-before
+	assert.False(t, open)
+	_, open = <-after
+	assert.False(t, open)
+	assert.Zero(t, hub.Subscribers())
+}
+
+func TestPublishHub_CancelTwice(t *testing.T) {
+	hub := NewPublishHub(4)
+	_, cancel := hub.Subscribe()
+
+	cancel()
+	hub.Close()
+	cancel()
+	var nilHub *PublishHub
+	nilHub.Publish(&dto.PublishedPostEvent{})
+}
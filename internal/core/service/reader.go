@@ -1,118 +1,468 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
 type ReaderRepository interface {
-	GetPostByIdempotencyKey(idempotencyKey string) (*dto.PostDB, error)
+	GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error)
+	GetPostWithAuthorById(ctx context.Context, id uuid.UUID) (*dto.PostUserDB, error)
 	CreatePost(
+		ctx context.Context,
 		authorId uuid.UUID,
 		idempotencyKey string,
 		title,
-		content string,
+		content,
+		excerpt string,
+		stats *dto.ReadingStats,
+		tags []string,
 	) (*dto.PostDB, error)
-	GetPublishedPosts() ([]*dto.PostUserDB, error)
-	GetUserPosts(userId uuid.UUID) ([]*dto.PostUserDB, error)
-	GetPostImages(postId uuid.UUID) ([]*dto.ImageDB, error)
+	FillReadingStats(ctx context.Context, posts []*dto.PostDB) error
+	GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.PostUserDB, error)
+	GetUserPosts(ctx context.Context, userId uuid.UUID, opts dto.PostListOptions) ([]*dto.PostUserDB, error)
+	GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error)
+	GetTags(ctx context.Context) ([]*dto.TagDB, error)
+	SearchPublishedPosts(ctx context.Context, query string, limit, offset int) ([]*dto.PostSearchDB, error)
+	SearchPostsWithAuthor(ctx context.Context, authorId uuid.UUID, query string, limit, offset int) ([]*dto.PostSearchDB, error)
+	LikePost(ctx context.Context, postId, userId uuid.UUID) error
+	UnlikePost(ctx context.Context, postId, userId uuid.UUID) error
+	CountPostLikes(ctx context.Context, postId uuid.UUID) (int, error)
+	GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.PostUserDB, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	GetAuthors(ctx context.Context) ([]*dto.AuthorDB, error)
+	GetAuthor(ctx context.Context, id uuid.UUID) (*dto.AuthorDB, error)
+	GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.PostUserDB, error)
+	GetTrendingPosts(ctx context.Context, window time.Duration, likeWeight float64, limit int) ([]*dto.PostTrendingDB, error)
+	GetPreviewPost(ctx context.Context, tokenHash string) (*dto.PostUserDB, error)
+	AddReport(ctx context.Context, postId, userId uuid.UUID, reason types.ReportReason, comment string) (*dto.ReportDB, error)
+}
+
+// ViewRecorder counts a read of a post, implementations must not block
+type ViewRecorder interface {
+	Record(postId, userId uuid.UUID)
 }
 
 type ReaderService struct {
-	rep ReaderRepository
+	rep   ReaderRepository
+	views ViewRecorder
+	// links is nil for a public bucket
+	links *ImageLinks
+	// listings caches GetPublishedPosts, nil loads every time
+	listings *PostListCache
+	// content renders the Markdown of posts into content_html, nil leaves it out
+	content *ContentRenderer
+	// reading counts words and reading time of posts, nil at the default speed
+	reading *ReadingTime
+	// trending ranks and keeps the trending posts, nil ranks them every time with the default weights
+	trending *Trending
 }
 
-func NewReaderService(rep ReaderRepository) *ReaderService {
+func NewReaderService(rep ReaderRepository, views ViewRecorder, links *ImageLinks, listings *PostListCache, content *ContentRenderer, reading *ReadingTime, trending *Trending) *ReaderService {
 	return &ReaderService{
 		rep,
+		views,
+		links,
+		listings,
+		content,
+		reading,
+		trending,
 	}
 }
 
-func (s *ReaderService) NewPost(authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, error) {
-	dbPost, err := s.rep.GetPostByIdempotencyKey(post.IdempotencyKey)
+// NewPost creates a post once per idempotency key. A retry of the same author with the same title and
+// content gets the post created the first time and created false, any other reuse of the key is a conflict.
+func (s *ReaderService) NewPost(ctx context.Context, authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, bool, error) {
+	dbPost, err := s.rep.GetPostByIdempotencyKey(ctx, post.IdempotencyKey)
 
 	if dbPost != nil {
-		return nil, errors.ErrorKeyIdempotencyAlreadyUsed
+		if dbPost.AuthorId != authorId || dbPost.Title != post.Title || dbPost.Content != post.Content {
+			return nil, false, errors.ErrorKeyIdempotencyAlreadyUsed
+		}
+		return &dto.CreatePostResponse{EditPostResponse: *editPostResponse(dbPost, s.reading)}, false, nil
 	}
 
 	if err != nil && err != sql.ErrNoRows {
-		return nil, err
+		return nil, false, err
 	}
 
-	dbPost, err = s.rep.CreatePost(
+	stats := s.reading.Stats(post.Content)
+	dbPost, err = s.rep.CreatePost(ctx,
 		authorId,
 		post.IdempotencyKey,
 		post.Title,
 		post.Content,
+		strings.TrimSpace(post.Excerpt),
+		&stats,
+		post.Tags,
 	)
 
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &dto.CreatePostResponse{EditPostResponse: *editPostResponse(dbPost, s.reading)}, true, nil
+}
+
+// visiblePost returns a published post or any post of the user
+func (s *ReaderService) visiblePost(ctx context.Context, userId, postId uuid.UUID) (*dto.PostUserDB, error) {
+	post, err := s.rep.GetPostWithAuthorById(ctx, postId)
+
+	if err != nil {
+		return nil, err
+	}
+	// other people's drafts do not exist as far as the reader knows
+	if post.AuthorId != userId && post.Status != types.Published {
+		return nil, sql.ErrNoRows
+	}
+	return post, nil
+}
+
+// GetPost returns a published post or any post of the user, a read by someone else than the author counts as a view
+func (s *ReaderService) GetPost(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostResponse, error) {
+	post, err := s.visiblePost(ctx, userId, postId)
+
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorId != userId {
+		s.views.Record(postId, userId)
+	}
+
+	res, err := s.proccessPostsToResponse(ctx, []*dto.PostUserDB{post})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// LikePost likes a post the user can see, liking it again changes nothing
+func (s *ReaderService) LikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error) {
+	return s.setLike(ctx, userId, postId, true)
+}
+
+// UnlikePost takes the like of the user back, a post without their like is left as is
+func (s *ReaderService) UnlikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error) {
+	return s.setLike(ctx, userId, postId, false)
+}
+
+func (s *ReaderService) setLike(ctx context.Context, userId, postId uuid.UUID, liked bool) (*dto.LikeResponse, error) {
+	if _, err := s.visiblePost(ctx, userId, postId); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if liked {
+		err = s.rep.LikePost(ctx, postId, userId)
+	} else {
+		err = s.rep.UnlikePost(ctx, postId, userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.rep.CountPostLikes(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LikeResponse{
+		PostId:     postId,
+		Liked:      liked,
+		LikesCount: count,
+	}, nil
+}
+
+// GetLikedPosts lists the posts the user likes, an empty tag does not filter
+func (s *ReaderService) GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.GetPostResponse, error) {
+	posts, err := s.rep.GetLikedPosts(ctx, userId, tag)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.proccessPostsToResponse(ctx, posts)
+}
+
+// GetPublishedPosts lists the published posts narrowed and ordered by opts, its status is ignored.
+// The listing is the same for every reader and comes from the cache when there is one.
+func (s *ReaderService) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.GetPostResponse, error) {
+	// the status does not narrow this listing, it must not split the cache either
+	opts.Status = ""
+	return s.listings.Get(ctx, opts, func() ([]*dto.GetPostResponse, error) {
+		posts, err := s.rep.GetPublishedPosts(ctx, opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return s.proccessPostsToResponse(ctx, posts)
+	})
+}
+
+// GetAuthors lists the authors with published posts and how many they have
+func (s *ReaderService) GetAuthors(ctx context.Context) ([]*dto.AuthorResponse, error) {
+	authors, err := s.rep.GetAuthors(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.AuthorResponse, len(authors))
+	for i, author := range authors {
+		if res[i], err = s.authorResponse(author); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// GetAuthor is the public profile of an author, a user who is not an author is sql.ErrNoRows
+func (s *ReaderService) GetAuthor(ctx context.Context, authorId uuid.UUID) (*dto.AuthorResponse, error) {
+	author, err := s.rep.GetAuthor(ctx, authorId)
+
+	if err != nil {
+		return nil, err
+	}
+	return s.authorResponse(author)
+}
+
+func (s *ReaderService) authorResponse(author *dto.AuthorDB) (*dto.AuthorResponse, error) {
+	avatar, err := s.links.AvatarLink(author.AvatarUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuthorResponse{
+		UserId:      author.UserId,
+		DisplayName: dto.DisplayName(author.DisplayName, author.Email),
+		AvatarUrl:   avatar,
+		PostsCount:  author.PostsCount,
+	}, nil
+}
+
+// GetAuthorPublishedPosts lists the published posts of an author, a user who is not an author is sql.ErrNoRows
+func (s *ReaderService) GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.GetPostResponse, error) {
+	author, err := s.rep.GetUserById(ctx, authorId)
+
 	if err != nil {
 		return nil, err
 	}
+	if author.Role != types.Author {
+		return nil, sql.ErrNoRows
+	}
+
+	posts, err := s.rep.GetAuthorPublishedPosts(ctx, authorId, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.proccessPostsToResponse(ctx, posts)
+}
+
+// GetPublicPosts lists the published posts for anonymous visitors, an empty tag does not filter
+func (s *ReaderService) GetPublicPosts(ctx context.Context, tag string) ([]*dto.PublicPostResponse, error) {
+	posts, err := s.GetPublishedPosts(ctx, dto.PostListOptions{Tag: tag})
 
-	resPost := &dto.CreatePostResponse{
-		PostId: dbPost.PostId,
+	if err != nil {
+		return nil, err
 	}
 
-	return resPost, nil
+	res := make([]*dto.PublicPostResponse, len(posts))
+	for i, post := range posts {
+		res[i] = publicPostOf(post)
+	}
+	return res, nil
 }
 
-func (s *ReaderService) GetPublishedPosts() ([]*dto.GetPostResponse, error) {
-	posts, err := s.rep.GetPublishedPosts()
+// GetPublicPost returns a published post for anonymous visitors, it is not counted as a view
+// since there is no reader to count it once per day for
+func (s *ReaderService) GetPublicPost(ctx context.Context, postId uuid.UUID) (*dto.PublicPostResponse, error) {
+	// uuid.Nil is nobody's id, so only a published post is visible
+	post, err := s.visiblePost(ctx, uuid.Nil, postId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return s.proccessPostsToResponse(posts)
+	res, err := s.proccessPostsToResponse(ctx, []*dto.PostUserDB{post})
+	if err != nil {
+		return nil, err
+	}
+	return publicPostOf(res[0]), nil
+}
+
+func publicPostOf(post *dto.GetPostResponse) *dto.PublicPostResponse {
+	return &dto.PublicPostResponse{
+		PostId: post.PostId,
+		Author: dto.PublicAuthorResponse{
+			UserId:      post.Author.UserId,
+			DisplayName: post.Author.DisplayName,
+			AvatarUrl:   post.Author.AvatarUrl,
+		},
+		Title:      post.Title,
+		Content:    post.Content,
+		Images:     post.Images,
+		Tags:       post.Tags,
+		Views:      post.Views,
+		LikesCount: post.LikesCount,
+		CreatedAt:  post.CreatedAt,
+		UpdatedAt:  post.UpdatedAt,
+	}
+}
+
+// tagsOf never returns nil so responses carry an empty list instead of null
+func tagsOf(post *dto.PostDB) []string {
+	if post.Tags == nil {
+		return []string{}
+	}
+	return post.Tags
 }
 
 // union posts with images
-func (s *ReaderService) proccessPostsToResponse(posts []*dto.PostUserDB) ([]*dto.GetPostResponse, error) {
+func (s *ReaderService) proccessPostsToResponse(ctx context.Context, posts []*dto.PostUserDB) ([]*dto.GetPostResponse, error) {
 
 	res := make([]*dto.GetPostResponse, len(posts))
+	var counted []*dto.PostDB
 
 	for i, raw := range posts {
-		rawImages, err := s.rep.GetPostImages(raw.PostId)
+		rawImages, err := s.rep.GetPostImages(ctx, raw.PostId)
 		if err != nil {
 			return nil, err
 		}
 
 		images := make([]dto.AddImageResponse, len(rawImages))
 		for i, el := range rawImages {
+			link, err := s.links.ImageLink(el)
+			if err != nil {
+				return nil, err
+			}
 			images[i] = dto.AddImageResponse{
 				ImageId:  el.ImageId,
-				ImageUrl: el.ImageUrl,
+				ImageUrl: link,
+			}
+			if el.Caption != nil {
+				images[i].Caption = *el.Caption
 			}
 		}
 
+		contentHTML, err := s.content.Render(&raw.PostDB)
+		if err != nil {
+			return nil, err
+		}
+		stats, stored := s.reading.statsOf(&raw.PostDB)
+		if !stored {
+			post := raw.PostDB
+			post.WordCount, post.ReadingTimeMinutes = &stats.WordCount, &stats.ReadingTimeMinutes
+			counted = append(counted, &post)
+		}
+
+		avatar, err := s.links.AvatarLink(raw.AvatarUrl)
+		if err != nil {
+			return nil, err
+		}
+
 		res[i] = &dto.GetPostResponse{
 			PostId: raw.PostId,
+			// the email stays out, listings are seen by anyone signed in
 			Author: dto.UserResponse{
-				UserId: raw.AuthorId,
-				Email:  raw.Email,
+				UserId:      raw.AuthorId,
+				DisplayName: raw.Name(),
+				AvatarUrl:   avatar,
 			},
-			Title:     raw.Title,
-			Content:   raw.Content,
-			Status:    raw.Status,
-			Images:    images,
-			CreatedAt: raw.CreatedAt,
-			UpdatedAt: raw.UpdatedAt,
+			Title:       raw.Title,
+			Excerpt:     excerptOf(&raw.PostDB),
+			Content:     raw.Content,
+			ContentHTML: contentHTML,
+			Status:      raw.Status,
+			Images:      images,
+			Tags:        tagsOf(&raw.PostDB),
+			Views:       raw.Views,
+			LikesCount:  raw.LikesCount,
+			PublishAt:   raw.PublishAt,
+			CreatedAt:   raw.CreatedAt,
+			UpdatedAt:   raw.UpdatedAt,
+
+			WordCount:          stats.WordCount,
+			ReadingTimeMinutes: stats.ReadingTimeMinutes,
 		}
 	}
 
+	// posts written before the counts existed get them on their first read, a failure only costs counting again
+	if len(counted) > 0 {
+		if err := s.rep.FillReadingStats(ctx, counted); err != nil {
+			slog.Warn("store reading stats", slog.Int("count", len(counted)), slog.String("error", err.Error()))
+		}
+	}
 	return res, nil
 }
 
-func (s *ReaderService) GetAuthorPosts(authorId uuid.UUID) ([]*dto.GetPostResponse, error) {
-	posts, err := s.rep.GetUserPosts(authorId)
+// GetAuthorPosts lists every post of the author narrowed and ordered by opts
+func (s *ReaderService) GetAuthorPosts(ctx context.Context, authorId uuid.UUID, opts dto.PostListOptions) ([]*dto.GetPostResponse, error) {
+	posts, err := s.rep.GetUserPosts(ctx, authorId, opts)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return s.proccessPostsToResponse(posts)
+	return s.proccessPostsToResponse(ctx, posts)
+}
+
+func (s *ReaderService) GetTags(ctx context.Context) ([]*dto.TagResponse, error) {
+	tags, err := s.rep.GetTags(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		res[i] = &dto.TagResponse{
+			Name:  tag.Name,
+			Count: tag.Count,
+		}
+	}
+	return res, nil
+}
+
+// SearchPosts runs a full-text search over published posts, a non-nil authorId adds all posts of that author
+func (s *ReaderService) SearchPosts(ctx context.Context, query string, limit, offset int, authorId uuid.UUID) (*dto.SearchPostsResponse, error) {
+	var found []*dto.PostSearchDB
+	var err error
+	if authorId == uuid.Nil {
+		found, err = s.rep.SearchPublishedPosts(ctx, query, limit, offset)
+	} else {
+		found, err = s.rep.SearchPostsWithAuthor(ctx, authorId, query, limit, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]*dto.PostUserDB, len(found))
+	for i, post := range found {
+		raw[i] = &post.PostUserDB
+	}
+	posts, err := s.proccessPostsToResponse(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SearchPostsResponse{
+		Posts:  make([]dto.SearchPostResponse, len(posts)),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, post := range posts {
+		res.Posts[i] = dto.SearchPostResponse{
+			GetPostResponse: *post,
+			Score:           found[i].Rank,
+		}
+	}
+	return res, nil
 }
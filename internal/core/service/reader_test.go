@@ -0,0 +1,592 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// fakeReaderRepository filters like the queries of PostgresRepository
+type fakeReaderRepository struct {
+	users map[uuid.UUID]*dto.UserDB
+	posts []*dto.PostDB
+	// searchedAuthor records the author the last search was widened to
+	searchedAuthor uuid.UUID
+	// likes holds the users liking each post in the order they liked it
+	likes map[uuid.UUID][]uuid.UUID
+	// images of each post in the order they were added
+	images map[uuid.UUID][]*dto.ImageDB
+	// filled holds the posts handed to FillReadingStats
+	filled []*dto.PostDB
+	// scores ranks the published posts for GetTrendingPosts, posts missing score nothing
+	scores map[uuid.UUID]float64
+	// previews are the links made by a fakePosterRepository
+	previews fakePreviews
+	// reports are kept oldest first
+	reports []*dto.ReportDB
+}
+
+func (f *fakeReaderRepository) GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error) {
+	for _, post := range f.posts {
+		if post.IdempotencyKey == idempotencyKey {
+			return post, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeReaderRepository) CreatePost(ctx context.Context, authorId uuid.UUID, idempotencyKey, title, content, excerpt string, stats *dto.ReadingStats, tags []string) (*dto.PostDB, error) {
+	post := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, IdempotencyKey: idempotencyKey,
+		Title: title, Content: content, Status: types.Draft, Tags: tags, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if excerpt != "" {
+		post.Excerpt = &excerpt
+	}
+	if stats != nil {
+		counted := *stats
+		post.WordCount, post.ReadingTimeMinutes = &counted.WordCount, &counted.ReadingTimeMinutes
+	}
+	f.posts = append(f.posts, post)
+	return post, nil
+}
+
+func (f *fakeReaderRepository) FillReadingStats(ctx context.Context, posts []*dto.PostDB) error {
+	f.filled = append(f.filled, posts...)
+	return nil
+}
+
+func (f *fakeReaderRepository) selectPosts(match func(*dto.PostDB) bool) []*dto.PostUserDB {
+	var res []*dto.PostUserDB
+	for _, post := range f.posts {
+		if post.DeletedAt == nil && match(post) {
+			res = append(res, &dto.PostUserDB{PostDB: *post, UserDB: *f.users[post.AuthorId]})
+		}
+	}
+	return res
+}
+
+func (f *fakeReaderRepository) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
+	return f.selectPosts(func(post *dto.PostDB) bool { return post.Status == types.Published && hasTag(post, opts.Tag) }), nil
+}
+
+func (f *fakeReaderRepository) GetTrendingPosts(ctx context.Context, window time.Duration, likeWeight float64, limit int) ([]*dto.PostTrendingDB, error) {
+	var trending []*dto.PostTrendingDB
+	for _, post := range f.selectPosts(func(post *dto.PostDB) bool { return post.Status == types.Published }) {
+		trending = append(trending, &dto.PostTrendingDB{PostUserDB: *post, Score: f.scores[post.PostId]})
+	}
+	sort.SliceStable(trending, func(i, j int) bool { return trending[i].Score > trending[j].Score })
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+func (f *fakeReaderRepository) GetPreviewPost(ctx context.Context, tokenHash string) (*dto.PostUserDB, error) {
+	preview, ok := f.previews[tokenHash]
+	if !ok || !preview.expiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+	posts := f.selectPosts(func(post *dto.PostDB) bool { return post.PostId == preview.postId })
+	if len(posts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return posts[0], nil
+}
+
+func (f *fakeReaderRepository) GetUserPosts(ctx context.Context, userId uuid.UUID, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
+	return f.selectPosts(func(post *dto.PostDB) bool {
+		return post.AuthorId == userId && hasTag(post, opts.Tag) && (opts.Status == "" || post.Status == opts.Status)
+	}), nil
+}
+
+func (f *fakeReaderRepository) GetTags(ctx context.Context) ([]*dto.TagDB, error) {
+	counts := map[string]int{}
+	for _, post := range f.posts {
+		if post.Status == types.Published && post.DeletedAt == nil {
+			for _, tag := range post.Tags {
+				counts[tag]++
+			}
+		}
+	}
+	var tags []*dto.TagDB
+	for name, count := range counts {
+		tags = append(tags, &dto.TagDB{Name: name, Count: count})
+	}
+	return tags, nil
+}
+
+func (f *fakeReaderRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (f *fakeReaderRepository) GetAuthors(ctx context.Context) ([]*dto.AuthorDB, error) {
+	var authors []*dto.AuthorDB
+	for _, user := range f.users {
+		posts, _ := f.GetAuthorPublishedPosts(ctx, user.UserId, "")
+		if user.Role == types.Author && len(posts) > 0 {
+			authors = append(authors, &dto.AuthorDB{UserId: user.UserId, Email: user.Email, DisplayName: user.DisplayName, AvatarUrl: user.AvatarUrl, PostsCount: len(posts)})
+		}
+	}
+	return authors, nil
+}
+
+func (f *fakeReaderRepository) GetAuthor(ctx context.Context, id uuid.UUID) (*dto.AuthorDB, error) {
+	user, ok := f.users[id]
+	if !ok || user.Role != types.Author {
+		return nil, sql.ErrNoRows
+	}
+	posts, _ := f.GetAuthorPublishedPosts(ctx, id, "")
+	return &dto.AuthorDB{UserId: id, Email: user.Email, DisplayName: user.DisplayName, AvatarUrl: user.AvatarUrl, PostsCount: len(posts)}, nil
+}
+
+func (f *fakeReaderRepository) GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	return f.selectPosts(func(post *dto.PostDB) bool {
+		return post.AuthorId == authorId && post.Status == types.Published && hasTag(post, tag)
+	}), nil
+}
+
+// searchPosts matches on the title only, ranking is left to the database
+func (f *fakeReaderRepository) searchPosts(query string, limit, offset int, match func(*dto.PostDB) bool) []*dto.PostSearchDB {
+	var res []*dto.PostSearchDB
+	for i, post := range f.selectPosts(match) {
+		if strings.Contains(strings.ToLower(post.Title), strings.ToLower(query)) {
+			res = append(res, &dto.PostSearchDB{PostUserDB: *post, Rank: 1 / float64(i+1)})
+		}
+	}
+	if offset >= len(res) {
+		return nil
+	}
+	return res[offset:min(offset+limit, len(res))]
+}
+
+func (f *fakeReaderRepository) SearchPublishedPosts(ctx context.Context, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	f.searchedAuthor = uuid.Nil
+	return f.searchPosts(query, limit, offset, func(post *dto.PostDB) bool { return post.Status == types.Published }), nil
+}
+
+func (f *fakeReaderRepository) SearchPostsWithAuthor(ctx context.Context, authorId uuid.UUID, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	f.searchedAuthor = authorId
+	return f.searchPosts(query, limit, offset, func(post *dto.PostDB) bool {
+		return post.Status == types.Published || post.AuthorId == authorId
+	}), nil
+}
+
+func hasTag(post *dto.PostDB, tag string) bool {
+	return tag == "" || slices.Contains(post.Tags, tag)
+}
+
+func (f *fakeReaderRepository) GetPostWithAuthorById(ctx context.Context, id uuid.UUID) (*dto.PostUserDB, error) {
+	posts := f.selectPosts(func(post *dto.PostDB) bool { return post.PostId == id })
+	if len(posts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return posts[0], nil
+}
+
+func (f *fakeReaderRepository) LikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	if f.likes == nil {
+		f.likes = map[uuid.UUID][]uuid.UUID{}
+	}
+	if !slices.Contains(f.likes[postId], userId) {
+		f.likes[postId] = append(f.likes[postId], userId)
+	}
+	return nil
+}
+
+func (f *fakeReaderRepository) AddReport(ctx context.Context, postId, userId uuid.UUID, reason types.ReportReason, comment string) (*dto.ReportDB, error) {
+	for _, report := range f.reports {
+		if report.PostId == postId && report.UserId == userId {
+			return report, nil
+		}
+	}
+	report := &dto.ReportDB{ReportId: uuid.New(), PostId: postId, UserId: userId, Reason: reason, Comment: comment, CreatedAt: time.Now()}
+	f.reports = append(f.reports, report)
+	return report, nil
+}
+
+func (f *fakeReaderRepository) UnlikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	f.likes[postId] = slices.DeleteFunc(f.likes[postId], func(id uuid.UUID) bool { return id == userId })
+	return nil
+}
+
+func (f *fakeReaderRepository) CountPostLikes(ctx context.Context, postId uuid.UUID) (int, error) {
+	return len(f.likes[postId]), nil
+}
+
+func (f *fakeReaderRepository) GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	return f.selectPosts(func(post *dto.PostDB) bool {
+		return slices.Contains(f.likes[post.PostId], userId) &&
+			(post.Status == types.Published || post.AuthorId == userId) && hasTag(post, tag)
+	}), nil
+}
+
+func (f *fakeReaderRepository) GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error) {
+	return f.images[postId], nil
+}
+
+// fakePresigner signs a link by naming the object and how long the link lives
+type fakePresigner struct{}
+
+func (fakePresigner) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	return "http://minio/images/" + objectName + "?expires=" + ttl.String(), nil
+}
+
+type fakeViewRecorder struct {
+	views []dto.PostView
+}
+
+func (f *fakeViewRecorder) Record(postId, userId uuid.UUID) {
+	f.views = append(f.views, dto.PostView{PostId: postId, UserId: userId})
+}
+
+func seedStatuses(t *testing.T) (*fakeReaderRepository, uuid.UUID) {
+	t.Helper()
+	author := &dto.UserDB{UserId: uuid.New(), Email: "author@example.com", Role: types.Author}
+	rep := &fakeReaderRepository{users: map[uuid.UUID]*dto.UserDB{author.UserId: author}}
+	for _, status := range []types.PostStatus{types.Draft, types.Published, types.Archived} {
+		rep.posts = append(rep.posts, &dto.PostDB{PostId: uuid.New(), AuthorId: author.UserId, Title: string(status), Status: status})
+	}
+	return rep, author.UserId
+}
+
+func statusesOf(posts []*dto.GetPostResponse) []types.PostStatus {
+	res := make([]types.PostStatus, len(posts))
+	for i, post := range posts {
+		res[i] = post.Status
+	}
+	return res
+}
+
+func TestReaderService_ArchivedHiddenFromReaders(t *testing.T) {
+	rep, _ := seedStatuses(t)
+
+	posts, err := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil).GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []types.PostStatus{types.Published}, statusesOf(posts))
+}
+
+func TestReaderService_ArchivedVisibleToAuthor(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+
+	posts, err := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil).GetAuthorPosts(context.Background(), authorId, dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.PostStatus{types.Draft, types.Published, types.Archived}, statusesOf(posts))
+}
+
+func TestReaderService_TrashedHidden(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	deletedAt := time.Now()
+	for _, post := range rep.posts {
+		if post.Status == types.Published {
+			post.DeletedAt = &deletedAt
+		}
+	}
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+
+	posts, err := s.GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+
+	posts, err = s.GetAuthorPosts(context.Background(), authorId, dto.PostListOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.PostStatus{types.Draft, types.Archived}, statusesOf(posts))
+}
+
+func TestReaderService_FilterByTag(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	for _, post := range rep.posts {
+		post.Tags = pq.StringArray{"golang"}
+		if post.Status == types.Draft {
+			post.Tags = pq.StringArray{"drafts"}
+		}
+	}
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+
+	posts, err := s.GetPublishedPosts(context.Background(), dto.PostListOptions{Tag: "golang"})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, []string{"golang"}, posts[0].Tags)
+
+	posts, err = s.GetPublishedPosts(context.Background(), dto.PostListOptions{Tag: "drafts"})
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+
+	posts, err = s.GetAuthorPosts(context.Background(), authorId, dto.PostListOptions{Tag: "drafts"})
+	require.NoError(t, err)
+	assert.Equal(t, []types.PostStatus{types.Draft}, statusesOf(posts))
+
+	tags, err := s.GetTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*dto.TagResponse{{Name: "golang", Count: 1}}, tags)
+}
+
+func TestReaderService_UntaggedPostHasEmptyTags(t *testing.T) {
+	rep, _ := seedStatuses(t)
+
+	posts, err := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil).GetPublishedPosts(context.Background(), dto.PostListOptions{})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.NotNil(t, posts[0].Tags)
+	assert.Empty(t, posts[0].Tags)
+}
+
+func TestReaderService_SearchPosts(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+
+	res, err := s.SearchPosts(context.Background(), "ed", 20, 0, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, rep.searchedAuthor)
+	require.Len(t, res.Posts, 1)
+	assert.Equal(t, types.Published, res.Posts[0].Status)
+	assert.Equal(t, 1.0, res.Posts[0].Score)
+	assert.Equal(t, 20, res.Limit)
+
+	res, err = s.SearchPosts(context.Background(), "ed", 20, 0, authorId)
+	require.NoError(t, err)
+	assert.Equal(t, authorId, rep.searchedAuthor)
+	assert.ElementsMatch(t, []types.PostStatus{types.Published, types.Archived},
+		[]types.PostStatus{res.Posts[0].Status, res.Posts[1].Status})
+
+	res, err = s.SearchPosts(context.Background(), "ed", 1, 1, authorId)
+	require.NoError(t, err)
+	require.Len(t, res.Posts, 1)
+	assert.Equal(t, 1, res.Offset)
+}
+
+func TestReaderService_Excerpt(t *testing.T) {
+	authorId := uuid.New()
+	rep := &fakeReaderRepository{users: map[uuid.UUID]*dto.UserDB{authorId: {UserId: authorId, Role: types.Author}}}
+	s := NewReaderService(rep, nil, nil, nil, nil, nil, nil)
+
+	_, _, err := s.NewPost(context.Background(), authorId, &dto.CreatePostRequest{IdempotencyKey: "written", Title: "Written",
+		Content: "The content", Excerpt: " What it is about "})
+	require.NoError(t, err)
+	_, _, err = s.NewPost(context.Background(), authorId, &dto.CreatePostRequest{IdempotencyKey: "taken", Title: "Taken",
+		Content: "## Intro\n\nThe **first** words of a [post](https://example.com) " + strings.Repeat("ünïcode ", 50)})
+	require.NoError(t, err)
+
+	posts, err := s.GetAuthorPosts(context.Background(), authorId, dto.PostListOptions{})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "What it is about", posts[0].Excerpt)
+	assert.Equal(t, "The content", posts[0].Content)
+
+	taken := posts[1].Excerpt
+	assert.True(t, strings.HasPrefix(taken, "Intro The first words of a post ünïcode"), taken)
+	assert.True(t, strings.HasSuffix(taken, "ünïcode…"), taken)
+	assert.LessOrEqual(t, utf8.RuneCountInString(taken), 300)
+}
+
+func TestReaderService_ReadingStats(t *testing.T) {
+	authorId := uuid.New()
+	rep := &fakeReaderRepository{users: map[uuid.UUID]*dto.UserDB{authorId: {UserId: authorId, Role: types.Author}}}
+	s := NewReaderService(rep, nil, nil, nil, nil, NewReadingTime(2), nil)
+
+	_, _, err := s.NewPost(context.Background(), authorId, &dto.CreatePostRequest{IdempotencyKey: "new", Title: "New", Content: "Five words of *new* content"})
+	require.NoError(t, err)
+	older := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "Older", Content: "Three older words",
+		Status: types.Draft, UpdatedAt: time.Now()}
+	rep.posts = append(rep.posts, older)
+
+	posts, err := s.GetAuthorPosts(context.Background(), authorId, dto.PostListOptions{})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, 5, posts[0].WordCount)
+	assert.Equal(t, 3, posts[0].ReadingTimeMinutes)
+	assert.Equal(t, 3, posts[1].WordCount)
+	assert.Equal(t, 2, posts[1].ReadingTimeMinutes)
+
+	// only the post written before the counts existed is stored, at the version that was counted
+	require.Len(t, rep.filled, 1)
+	assert.Equal(t, older.PostId, rep.filled[0].PostId)
+	assert.Equal(t, older.UpdatedAt, rep.filled[0].UpdatedAt)
+	assert.Equal(t, 3, *rep.filled[0].WordCount)
+	assert.Nil(t, older.WordCount, "the listed post is left alone")
+}
+
+func TestReaderService_GetPost(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	views := &fakeViewRecorder{}
+	s := NewReaderService(rep, views, nil, nil, nil, nil, nil)
+	readerId := uuid.New()
+	draft, published := rep.posts[0], rep.posts[1]
+
+	_, err := s.GetPost(context.Background(), readerId, draft.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = s.GetPost(context.Background(), readerId, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	post, err := s.GetPost(context.Background(), authorId, draft.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, types.Draft, post.Status)
+
+	post, err = s.GetPost(context.Background(), readerId, published.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, published.PostId, post.PostId)
+	assert.Equal(t, []string{}, post.Tags)
+
+	_, err = s.GetPost(context.Background(), authorId, published.PostId)
+	require.NoError(t, err)
+
+	// the author reading their own posts is not counted
+	assert.Equal(t, []dto.PostView{{PostId: published.PostId, UserId: readerId}}, views.views)
+}
+
+func TestReaderService_Likes(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+	readerId := uuid.New()
+	draft, published := rep.posts[0], rep.posts[1]
+
+	_, err := s.LikePost(context.Background(), readerId, draft.PostId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	for range 2 {
+		like, err := s.LikePost(context.Background(), readerId, published.PostId)
+		require.NoError(t, err)
+		assert.Equal(t, &dto.LikeResponse{PostId: published.PostId, Liked: true, LikesCount: 1}, like)
+	}
+	like, err := s.LikePost(context.Background(), authorId, draft.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, like.LikesCount)
+
+	posts, err := s.GetLikedPosts(context.Background(), readerId, "")
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, published.PostId, posts[0].PostId)
+
+	for range 2 {
+		like, err := s.UnlikePost(context.Background(), readerId, published.PostId)
+		require.NoError(t, err)
+		assert.Equal(t, &dto.LikeResponse{PostId: published.PostId, Liked: false, LikesCount: 0}, like)
+	}
+	posts, err = s.GetLikedPosts(context.Background(), readerId, "")
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+}
+
+func TestReaderService_NewPostRetry(t *testing.T) {
+	authorId := uuid.New()
+	rep := &fakeReaderRepository{}
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+	req := &dto.CreatePostRequest{IdempotencyKey: "key", Title: "title", Content: "content", Tags: []string{"go"}}
+
+	first, created, err := s.NewPost(context.Background(), authorId, req)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, authorId, first.AuthorId)
+	assert.Equal(t, "title", first.Title)
+	assert.Equal(t, types.Draft, first.Status)
+	assert.Equal(t, 1, first.WordCount)
+
+	retry, created, err := s.NewPost(context.Background(), authorId, req)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first, retry, "a retry answers the post as created")
+	assert.Len(t, rep.posts, 1)
+
+	_, _, err = s.NewPost(context.Background(), authorId, &dto.CreatePostRequest{IdempotencyKey: "key", Title: "title", Content: "other"})
+	assert.ErrorIs(t, err, errors.ErrorKeyIdempotencyAlreadyUsed)
+	_, _, err = s.NewPost(context.Background(), uuid.New(), req)
+	assert.ErrorIs(t, err, errors.ErrorKeyIdempotencyAlreadyUsed)
+	assert.Len(t, rep.posts, 1)
+}
+
+func TestReaderService_PublicPosts(t *testing.T) {
+	rep, _ := seedStatuses(t)
+	views := &fakeViewRecorder{}
+	s := NewReaderService(rep, views, nil, nil, nil, nil, nil)
+	draft, published, archived := rep.posts[0], rep.posts[1], rep.posts[2]
+
+	posts, err := s.GetPublicPosts(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, published.PostId, posts[0].PostId)
+	assert.Equal(t, "a***@example.com", posts[0].Author.DisplayName)
+
+	for _, hidden := range []*dto.PostDB{draft, archived} {
+		_, err = s.GetPublicPost(context.Background(), hidden.PostId)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	post, err := s.GetPublicPost(context.Background(), published.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, "a***@example.com", post.Author.DisplayName)
+	assert.Empty(t, views.views)
+}
+
+func TestReaderService_Authors(t *testing.T) {
+	rep, authorId := seedStatuses(t)
+	quiet := &dto.UserDB{UserId: uuid.New(), Email: "quiet@example.com", Role: types.Author}
+	reader := &dto.UserDB{UserId: uuid.New(), Email: "reader@example.com", Role: types.Reader}
+	rep.users[quiet.UserId], rep.users[reader.UserId] = quiet, reader
+	rep.posts = append(rep.posts, &dto.PostDB{PostId: uuid.New(), AuthorId: quiet.UserId, Status: types.Draft})
+	s := NewReaderService(rep, &fakeViewRecorder{}, nil, nil, nil, nil, nil)
+
+	authors, err := s.GetAuthors(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*dto.AuthorResponse{{UserId: authorId, DisplayName: "a***@example.com", PostsCount: 1}}, authors)
+
+	posts, err := s.GetAuthorPublishedPosts(context.Background(), authorId, "")
+	require.NoError(t, err)
+	assert.Equal(t, []types.PostStatus{types.Published}, statusesOf(posts))
+
+	// an author without published posts is an empty list, anyone else is not found
+	posts, err = s.GetAuthorPublishedPosts(context.Background(), quiet.UserId, "")
+	require.NoError(t, err)
+	assert.NotNil(t, posts)
+	assert.Empty(t, posts)
+	_, err = s.GetAuthorPublishedPosts(context.Background(), reader.UserId, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = s.GetAuthorPublishedPosts(context.Background(), uuid.New(), "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestReaderService_ImageLinks(t *testing.T) {
+	rep, _ := seedStatuses(t)
+	published := rep.posts[1]
+	imageId := uuid.New()
+	rep.images = map[uuid.UUID][]*dto.ImageDB{
+		published.PostId: {{ImageId: imageId, PostId: published.PostId, ImageUrl: "/images/" + imageId.String()}},
+	}
+
+	tests := []struct {
+		name     string
+		links    *ImageLinks
+		expected string
+	}{
+		{name: "public bucket", links: nil, expected: "/images/" + imageId.String()},
+		{name: "private bucket", links: NewImageLinks(fakePresigner{}, time.Minute), expected: "http://minio/images/" + imageId.String() + "?expires=1m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewReaderService(rep, &fakeViewRecorder{}, tt.links, nil, nil, nil, nil)
+
+			post, err := s.GetPost(context.Background(), uuid.New(), published.PostId)
+			require.NoError(t, err)
+			require.Len(t, post.Images, 1)
+			assert.Equal(t, tt.expected, post.Images[0].ImageUrl)
+
+			public, err := s.GetPublicPost(context.Background(), published.PostId)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, public.Images[0].ImageUrl)
+		})
+	}
+}
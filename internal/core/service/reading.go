@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/markdown"
+)
+
+const (
+	// defaultWordsPerMinute is the reading speed of a nil ReadingTime
+	defaultWordsPerMinute = 200
+	// codeLineWords is how many words of prose take as long to read as one line of code
+	codeLineWords = 4
+)
+
+// ReadingTime counts the words of a post and the minutes it takes to read it
+type ReadingTime struct {
+	wordsPerMinute int
+}
+
+// NewReadingTime reads wordsPerMinute, anything below one reads at the default speed
+func NewReadingTime(wordsPerMinute int) *ReadingTime {
+	if wordsPerMinute < 1 {
+		wordsPerMinute = defaultWordsPerMinute
+	}
+	return &ReadingTime{wordsPerMinute: wordsPerMinute}
+}
+
+// Stats counts content, a nil ReadingTime reads at the default speed. Anything with a word or a line
+// of code in it takes at least a minute.
+func (r *ReadingTime) Stats(content string) dto.ReadingStats {
+	wordsPerMinute := defaultWordsPerMinute
+	if r != nil {
+		wordsPerMinute = r.wordsPerMinute
+	}
+
+	counts := markdown.Count(content)
+	read := counts.Words + counts.CodeLines*codeLineWords
+	return dto.ReadingStats{
+		WordCount:          counts.Words,
+		ReadingTimeMinutes: (read + wordsPerMinute - 1) / wordsPerMinute,
+	}
+}
+
+// statsOf returns the stats stored with post, or counts them when it has none yet
+func (r *ReadingTime) statsOf(post *dto.PostDB) (stats dto.ReadingStats, stored bool) {
+	if post.WordCount != nil && post.ReadingTimeMinutes != nil {
+		return dto.ReadingStats{WordCount: *post.WordCount, ReadingTimeMinutes: *post.ReadingTimeMinutes}, true
+	}
+	return r.Stats(post.Content), false
+}
@@ -0,0 +1,35 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/service"
+)
+
+func TestReadingTime(t *testing.T) {
+	long := strings.Repeat("word ", 401)
+	code := "Three words here\n\n```go\n" + strings.Repeat("x := 1\n", 100) + "```\n"
+
+	tests := []struct {
+		name    string
+		reading *service.ReadingTime
+		content string
+		want    dto.ReadingStats
+	}{
+		{"nil reads at 200 words a minute", nil, long, dto.ReadingStats{WordCount: 401, ReadingTimeMinutes: 3}},
+		{"custom speed", service.NewReadingTime(100), long, dto.ReadingStats{WordCount: 401, ReadingTimeMinutes: 5}},
+		{"no speed reads at the default", service.NewReadingTime(0), long, dto.ReadingStats{WordCount: 401, ReadingTimeMinutes: 3}},
+		{"one word takes a minute", nil, "Hi", dto.ReadingStats{WordCount: 1, ReadingTimeMinutes: 1}},
+		{"code takes time but is no words", nil, code, dto.ReadingStats{WordCount: 3, ReadingTimeMinutes: 3}},
+		{"empty", nil, "", dto.ReadingStats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.reading.Stats(tt.content))
+		})
+	}
+}
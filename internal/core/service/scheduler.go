@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type SchedulerRepository interface {
+	PublishDuePosts(ctx context.Context, now time.Time) ([]*dto.PostDB, error)
+}
+
+// PublishScheduler periodically publishes scheduled posts whose publish_at has passed.
+// It keeps no state of its own, a restarted process simply catches up on the next tick.
+type PublishScheduler struct {
+	rep SchedulerRepository
+	// listings is invalidated when a tick published anything, nil when not cached
+	listings *PostListCache
+	// published hands the posts a tick published to the open streams like PosterService does, nil for none
+	published *PublishHub
+	// clock is nil in production so the database clock decides, app servers may drift apart
+	clock func() time.Time
+}
+
+func NewPublishScheduler(rep SchedulerRepository, listings *PostListCache, published *PublishHub, clock func() time.Time) *PublishScheduler {
+	return &PublishScheduler{rep, listings, published, clock}
+}
+
+// Tick publishes every post that is due right now
+func (s *PublishScheduler) Tick(ctx context.Context) (int, error) {
+	var now time.Time
+	if s.clock != nil {
+		now = s.clock()
+	}
+	posts, err := s.rep.PublishDuePosts(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(posts) > 0 {
+		s.listings.Invalidate()
+	}
+	for _, post := range posts {
+		s.published.Publish(&dto.PublishedPostEvent{
+			PostId:      post.PostId,
+			AuthorId:    post.AuthorId,
+			Title:       post.Title,
+			Tags:        post.Tags,
+			PublishedAt: post.UpdatedAt,
+		})
+	}
+	return len(posts), nil
+}
+
+// Run is one tick of the scheduler job
+func (s *PublishScheduler) Run(ctx context.Context) error {
+	count, err := s.Tick(ctx)
+	if count > 0 {
+		slog.Info("published scheduled posts", slog.String("job", "scheduler"), slog.Int("count", count))
+	}
+	return err
+}
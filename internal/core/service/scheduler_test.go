@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type fakeSchedulerRepository struct {
+	mu    sync.Mutex
+	calls []time.Time
+	due   map[time.Time]bool
+}
+
+func (f *fakeSchedulerRepository) PublishDuePosts(ctx context.Context, now time.Time) ([]*dto.PostDB, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, now)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var posts []*dto.PostDB
+	for publishAt, published := range f.due {
+		if !published && !publishAt.After(now) {
+			f.due[publishAt] = true
+			posts = append(posts, &dto.PostDB{PostId: uuid.New(), AuthorId: uuid.New(), Title: "Due", Status: types.Published,
+				Tags: []string{"go"}, PublishAt: &publishAt, UpdatedAt: now})
+		}
+	}
+	return posts, nil
+}
+
+func (f *fakeSchedulerRepository) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func TestPublishScheduler_TickUsesClock(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := base
+	rep := &fakeSchedulerRepository{due: map[time.Time]bool{
+		base.Add(time.Minute): false,
+		base.Add(time.Hour):   false,
+	}}
+	s := NewPublishScheduler(rep, nil, nil, func() time.Time { return now })
+
+	count, err := s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	now = base.Add(2 * time.Minute)
+	count, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	now = base.Add(2 * time.Hour)
+	count, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.Equal(t, []time.Time{base, base.Add(2 * time.Minute), base.Add(2 * time.Hour)}, rep.calls)
+}
+
+func TestPublishScheduler_NilClockDefersToDatabase(t *testing.T) {
+	rep := &fakeSchedulerRepository{}
+	_, err := NewPublishScheduler(rep, nil, nil, nil).Tick(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rep.calls, 1)
+	assert.True(t, rep.calls[0].IsZero())
+}
+
+func TestPublishScheduler_Run(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rep := &fakeSchedulerRepository{due: map[time.Time]bool{now.Add(-time.Minute): false}}
+	s := NewPublishScheduler(rep, nil, nil, func() time.Time { return now })
+
+	require.NoError(t, s.Run(context.Background()))
+	require.NoError(t, s.Run(context.Background()))
+	assert.Equal(t, 2, rep.callCount())
+	assert.True(t, rep.due[now.Add(-time.Minute)])
+}
+
+func TestPublishScheduler_TickStreamsPublishedPosts(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rep := &fakeSchedulerRepository{due: map[time.Time]bool{now.Add(-time.Minute): false, now.Add(time.Hour): false}}
+	hub := NewPublishHub(4)
+	events, stop := hub.Subscribe()
+	defer stop()
+	s := NewPublishScheduler(rep, nil, hub, func() time.Time { return now })
+
+	count, err := s.Tick(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "Due", event.Title)
+		assert.Equal(t, []string{"go"}, event.Tags)
+		assert.Equal(t, now, event.PublishedAt)
+	default:
+		t.Fatal("the scheduled post was not streamed")
+	}
+
+	_, err = s.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, events, "nothing more was due")
+}
+
+func TestPublishScheduler_RunPassesContext(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rep := &fakeSchedulerRepository{due: map[time.Time]bool{now.Add(-time.Minute): false}}
+	s := NewPublishScheduler(rep, nil, nil, func() time.Time { return now })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.Run(ctx), context.Canceled, "the job being stopped reaches the repository")
+	assert.False(t, rep.due[now.Add(-time.Minute)])
+}
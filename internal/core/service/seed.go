@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/hash"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type SeedRepository interface {
+	GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error)
+	AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error)
+	VerifyEmail(ctx context.Context, id uuid.UUID, email string) error
+	GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error)
+	CreatePost(ctx context.Context, authorId uuid.UUID, idempotencyKey, title, content, excerpt string, stats *dto.ReadingStats, tags []string) (*dto.PostDB, error)
+	UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error)
+	SchedulePost(ctx context.Context, id uuid.UUID, publishAt time.Time) (*dto.PostDB, error)
+	GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error)
+	CreateImage(ctx context.Context, imageId, postId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error)
+}
+
+type SeedStorage interface {
+	PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error)
+}
+
+//go:embed seeddata/*.png
+var seedImages embed.FS
+
+// SeedOptions is how much Seed makes, every seeded user logs in with Password
+type SeedOptions struct {
+	Authors        int
+	Readers        int
+	PostsPerAuthor int
+	Password       string
+}
+
+// seedPostStatuses are given to the posts of an author in turn, the first ones get the images
+var seedPostStatuses = []types.PostStatus{types.Published, types.Published, types.Draft, types.Scheduled, types.Archived}
+
+var seedTags = []string{"go", "postgres", "design", "testing", "devops", "career", "tooling", "performance"}
+
+var loremWords = strings.Fields(`lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor
+	incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud exercitation ullamco laboris
+	nisi aliquip ex ea commodo consequat duis aute irure in reprehenderit voluptate velit esse cillum eu fugiat
+	nulla pariatur excepteur sint occaecat cupidatat non proident sunt culpa qui officia deserunt mollit anim id est laborum`)
+
+// seedNamespace derives the ids of seeded images from their post, so a second run finds them
+var seedNamespace = uuid.MustParse("6f1c3f0e-5a7b-4c1d-9e2a-3b8d7c6e5f40")
+
+// SeedService fills a development database with users, posts and images. Users are found again by
+// email and posts by idempotency key, so running it twice creates nothing the second time.
+type SeedService struct {
+	rep   SeedRepository
+	stor  SeedStorage
+	clock func() time.Time
+}
+
+func NewSeedService(rep SeedRepository, stor SeedStorage, clock func() time.Time) *SeedService {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &SeedService{rep, stor, clock}
+}
+
+func (s *SeedService) Seed(ctx context.Context, opts SeedOptions) (*dto.SeedSummary, error) {
+	if len(opts.Password) < 8 {
+		return nil, fmt.Errorf("seed: the password needs at least 8 characters")
+	}
+	passwordHash, err := hash.Hash(opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	images, err := fs.Glob(seedImages, "seeddata/*.png")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.SeedSummary{Password: opts.Password}
+	for i := range opts.Readers {
+		if _, err := s.user(ctx, fmt.Sprintf("reader%d@seed.example.com", i+1), types.Reader, passwordHash, summary); err != nil {
+			return nil, err
+		}
+	}
+	for i := range opts.Authors {
+		author, err := s.user(ctx, fmt.Sprintf("author%d@seed.example.com", i+1), types.Author, passwordHash, summary)
+		if err != nil {
+			return nil, err
+		}
+		for j := range opts.PostsPerAuthor {
+			key := fmt.Sprintf("seed:%s:%d", author.Email, j+1)
+			status := seedPostStatuses[j%len(seedPostStatuses)]
+			post, err := s.post(ctx, author, key, status, rand.New(rand.NewPCG(uint64(i), uint64(j))), summary)
+			if err != nil {
+				return nil, err
+			}
+			if j < len(images) {
+				if err := s.image(ctx, post, key, images[j], summary); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return summary, nil
+}
+
+// user finds or creates a seeded user, nobody reads the mail of seed.example.com so the email counts as verified
+func (s *SeedService) user(ctx context.Context, email string, role types.Role, passwordHash string, summary *dto.SeedSummary) (*dto.UserDB, error) {
+	user, err := s.rep.GetUserByEmail(ctx, email)
+	if err == nil {
+		summary.UsersExisting++
+		return user, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	user, err = s.rep.AddNewUser(ctx, email, passwordHash, string(role))
+	if err != nil {
+		return nil, fmt.Errorf("seed %s: %w", email, err)
+	}
+	if err := s.rep.VerifyEmail(ctx, user.UserId, email); err != nil {
+		return nil, fmt.Errorf("seed %s: %w", email, err)
+	}
+	user.EmailVerified = true
+	summary.UsersCreated++
+	return user, nil
+}
+
+// post creates the post with key, a post left a draft by an earlier run that failed still gets its status
+func (s *SeedService) post(ctx context.Context, author *dto.UserDB, key string, status types.PostStatus, rnd *rand.Rand, summary *dto.SeedSummary) (*dto.PostDB, error) {
+	post, err := s.rep.GetPostByIdempotencyKey(ctx, key)
+	switch {
+	case err == nil:
+		summary.PostsExisting++
+	case err == sql.ErrNoRows:
+		tags := rnd.Perm(len(seedTags))[:2]
+		post, err = s.rep.CreatePost(ctx, author.UserId, key, loremTitle(rnd), loremContent(rnd), "", nil, []string{seedTags[tags[0]], seedTags[tags[1]]})
+		if err != nil {
+			return nil, fmt.Errorf("seed %s: %w", key, err)
+		}
+		summary.PostsCreated++
+	default:
+		return nil, err
+	}
+
+	if post.Status != types.Draft || status == types.Draft {
+		return post, nil
+	}
+	if status == types.Scheduled {
+		return s.rep.SchedulePost(ctx, post.PostId, s.clock().Add(time.Duration(1+rnd.IntN(14))*24*time.Hour))
+	}
+	return s.rep.UpdatePost(ctx, post.PostId, author.UserId, &dto.PostPatch{Status: &status}, nil, nil)
+}
+
+func (s *SeedService) image(ctx context.Context, post *dto.PostDB, key, name string, summary *dto.SeedSummary) error {
+	existing, err := s.rep.GetPostImages(ctx, post.PostId)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	data, err := seedImages.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	imageId := uuid.NewSHA1(seedNamespace, []byte(key))
+	link, err := s.stor.PutImage(ctx, imageId.String()+path.Ext(name), bytes.NewReader(data), int64(len(data)), "image/png")
+	if err != nil {
+		return err
+	}
+	if _, err := s.rep.CreateImage(ctx, imageId, post.PostId, link, int64(len(data))); err != nil {
+		return fmt.Errorf("seed image of %s: %w", key, err)
+	}
+	summary.ImagesCreated++
+	return nil
+}
+
+func loremTitle(rnd *rand.Rand) string {
+	words := loremWords[rnd.IntN(len(loremWords)-6):]
+	title := strings.Join(words[:3+rnd.IntN(4)], " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// loremContent is a few paragraphs of a few sentences each
+func loremContent(rnd *rand.Rand) string {
+	paragraphs := make([]string, 2+rnd.IntN(3))
+	for p := range paragraphs {
+		sentences := make([]string, 3+rnd.IntN(4))
+		for i := range sentences {
+			words := make([]string, 6+rnd.IntN(10))
+			for w := range words {
+				words[w] = loremWords[rnd.IntN(len(loremWords))]
+			}
+			sentence := strings.Join(words, " ")
+			sentences[i] = strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+		}
+		paragraphs[p] = strings.Join(sentences, " ")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
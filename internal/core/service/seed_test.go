@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/testing/harness"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestSeedService_Idempotent(t *testing.T) {
+	rep := harness.NewMemoryRepository()
+	stor := harness.NewMemoryStorage(time.Now)
+	seeder := service.NewSeedService(rep, stor, nil)
+	opts := service.SeedOptions{Authors: 2, Readers: 3, PostsPerAuthor: 5, Password: "seed-password"}
+
+	first, err := seeder.Seed(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SeedSummary{Password: "seed-password", UsersCreated: 5, PostsCreated: 10, ImagesCreated: 4}, first)
+
+	second, err := seeder.Seed(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SeedSummary{Password: "seed-password", UsersExisting: 5, PostsExisting: 10}, second)
+
+	users, err := rep.CountUsers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, users)
+	objects := 0
+	require.NoError(t, stor.ListImages(context.Background(), func(storage.ImageObject) error { objects++; return nil }))
+	assert.Equal(t, 4, objects)
+
+	author, err := rep.GetUserByEmail(context.Background(), "author1@seed.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, types.Author, author.Role)
+	assert.True(t, author.EmailVerified, "seeded authors can publish")
+	posts, err := rep.GetUserPosts(context.Background(), author.UserId, dto.PostListOptions{})
+	require.NoError(t, err)
+	statuses := map[types.PostStatus]int{}
+	for _, post := range posts {
+		statuses[post.Status]++
+		assert.Len(t, post.Tags, 2)
+		assert.NotEmpty(t, post.Content)
+	}
+	assert.Equal(t, map[types.PostStatus]int{types.Published: 2, types.Draft: 1, types.Scheduled: 1, types.Archived: 1}, statuses)
+}
+
+func TestSeedService_WeakPassword(t *testing.T) {
+	seeder := service.NewSeedService(harness.NewMemoryRepository(), harness.NewMemoryStorage(time.Now), nil)
+
+	_, err := seeder.Seed(context.Background(), service.SeedOptions{Authors: 1, Password: "short"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type SubscriptionRepository interface {
+	GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error)
+	Subscribe(ctx context.Context, readerId, authorId uuid.UUID) error
+	Unsubscribe(ctx context.Context, readerId, authorId uuid.UUID) error
+	CountSubscribers(ctx context.Context, authorId uuid.UUID) (int, error)
+	GetSubscriptions(ctx context.Context, readerId uuid.UUID, limit, offset int) ([]*dto.SubscriptionDB, error)
+	CountSubscriptions(ctx context.Context, readerId uuid.UUID) (int, error)
+	GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) ([]*dto.NotificationDB, error)
+	CountNotifications(ctx context.Context, userId uuid.UUID) (total, unread int, err error)
+	MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (int64, error)
+}
+
+type SubscriptionService struct {
+	rep SubscriptionRepository
+}
+
+func NewSubscriptionService(rep SubscriptionRepository) *SubscriptionService {
+	return &SubscriptionService{rep}
+}
+
+// Subscribe lets the user follow an author, following them again changes nothing. A user who is not
+// an author is sql.ErrNoRows, the user themselves errors.ErrorServiceSelfSubscription.
+func (s *SubscriptionService) Subscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error) {
+	if userId == authorId {
+		return nil, errors.ErrorServiceSelfSubscription
+	}
+	author, err := s.rep.GetUserById(ctx, authorId)
+	if err != nil {
+		return nil, err
+	}
+	if author.Role != types.Author {
+		return nil, sql.ErrNoRows
+	}
+	if err = s.rep.Subscribe(ctx, userId, authorId); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, authorId, true)
+}
+
+// Unsubscribe stops following an author, an author the user does not follow is left as is. It does
+// not check the role, a user demoted since can still be unfollowed.
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error) {
+	if err := s.rep.Unsubscribe(ctx, userId, authorId); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, authorId, false)
+}
+
+func (s *SubscriptionService) state(ctx context.Context, authorId uuid.UUID, subscribed bool) (*dto.SubscribeResponse, error) {
+	count, err := s.rep.CountSubscribers(ctx, authorId)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SubscribeResponse{
+		AuthorId:         authorId,
+		Subscribed:       subscribed,
+		SubscribersCount: count,
+	}, nil
+}
+
+// GetSubscriptions pages through the authors the user follows, latest subscription first
+func (s *SubscriptionService) GetSubscriptions(ctx context.Context, userId uuid.UUID, limit, offset int) (*dto.SubscriptionsResponse, error) {
+	subscriptions, err := s.rep.GetSubscriptions(ctx, userId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.rep.CountSubscriptions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SubscriptionsResponse{
+		Subscriptions: make([]dto.SubscriptionResponse, len(subscriptions)),
+		Total:         total,
+		Limit:         limit,
+		Offset:        offset,
+	}
+	for i, subscription := range subscriptions {
+		res.Subscriptions[i] = dto.SubscriptionResponse{
+			Author:       dto.UserResponse{UserId: subscription.AuthorId, DisplayName: dto.DisplayName(subscription.DisplayName, subscription.Email)},
+			SubscribedAt: subscription.CreatedAt,
+		}
+	}
+	return res, nil
+}
+
+// GetNotifications pages through the notifications of the user, latest first, unread leaves out those
+// marked read already. Total counts what the page is taken from, Unread every unread notification.
+func (s *SubscriptionService) GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) (*dto.NotificationsResponse, error) {
+	notifications, err := s.rep.GetNotifications(ctx, userId, unread, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, unreadCount, err := s.rep.CountNotifications(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if unread {
+		total = unreadCount
+	}
+
+	res := &dto.NotificationsResponse{
+		Notifications: make([]dto.NotificationResponse, len(notifications)),
+		Total:         total,
+		Unread:        unreadCount,
+		Limit:         limit,
+		Offset:        offset,
+	}
+	for i, notification := range notifications {
+		res.Notifications[i] = dto.NotificationResponse{
+			NotificationId: notification.NotificationId,
+			Type:           notification.Type,
+			Post: dto.NotificationPostResponse{
+				PostId: notification.PostId,
+				Title:  notification.Title,
+				Author: dto.UserResponse{UserId: notification.AuthorId, DisplayName: dto.DisplayName(notification.AuthorDisplayName, notification.AuthorEmail)},
+			},
+			CreatedAt: notification.CreatedAt,
+			ReadAt:    notification.ReadAt,
+		}
+	}
+	return res, nil
+}
+
+// MarkNotificationsRead marks the listed notifications of the user read, all of them without ids.
+// Ids of notifications read already or of someone else are skipped.
+func (s *SubscriptionService) MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (*dto.MarkNotificationsReadResponse, error) {
+	count, err := s.rep.MarkNotificationsRead(ctx, userId, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MarkNotificationsReadResponse{Marked: int(count)}, nil
+}
+
+type NotificationRepository interface {
+	NotifySubscribers(ctx context.Context, postId uuid.UUID) (int64, error)
+}
+
+// NotificationFanout is the EventSink in front of the configured one, when a post changed to published
+// the subscribers of its author get a notification about it before the event is handed on. It runs in the
+// outbox relay, so a publish only writes its event however many subscribers there are. A sink failing
+// after the fan-out gets the event again, the notifications made the first time are kept.
+type NotificationFanout struct {
+	rep  NotificationRepository
+	next EventSink
+}
+
+func NewNotificationFanout(rep NotificationRepository, next EventSink) *NotificationFanout {
+	return &NotificationFanout{rep, next}
+}
+
+func (f *NotificationFanout) Publish(ctx context.Context, event events.Event) error {
+	if event.Type == string(types.EventPostStatusChanged) {
+		postId, err := uuid.Parse(event.Key)
+		if err != nil {
+			return err
+		}
+		count, err := f.rep.NotifySubscribers(ctx, postId)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			slog.Debug("notified subscribers", slog.String("post_id", postId.String()), slog.Int64("count", count))
+		}
+	}
+	return f.next.Publish(ctx, event)
+}
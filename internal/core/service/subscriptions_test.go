@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/testing/harness"
+	blogerrors "github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func addUser(t *testing.T, rep *harness.MemoryRepository, email string, role types.Role) *dto.UserDB {
+	t.Helper()
+	user, err := rep.AddNewUser(context.Background(), email, "hash", string(role))
+	require.NoError(t, err)
+	return user
+}
+
+func TestSubscriptionService_Subscribe(t *testing.T) {
+	rep := harness.NewMemoryRepository()
+	author := addUser(t, rep, "author@example.com", types.Author)
+	reader := addUser(t, rep, "reader@example.com", types.Reader)
+	other := addUser(t, rep, "other@example.com", types.Reader)
+	subscriptions := service.NewSubscriptionService(rep)
+
+	res, err := subscriptions.Subscribe(context.Background(), reader.UserId, author.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SubscribeResponse{AuthorId: author.UserId, Subscribed: true, SubscribersCount: 1}, res)
+
+	res, err = subscriptions.Subscribe(context.Background(), reader.UserId, author.UserId)
+	require.NoError(t, err, "subscribing again is fine")
+	assert.Equal(t, 1, res.SubscribersCount)
+
+	_, err = subscriptions.Subscribe(context.Background(), author.UserId, author.UserId)
+	assert.ErrorIs(t, err, blogerrors.ErrorServiceSelfSubscription)
+	_, err = subscriptions.Subscribe(context.Background(), reader.UserId, other.UserId)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "readers cannot be followed")
+	_, err = subscriptions.Subscribe(context.Background(), reader.UserId, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	page, err := subscriptions.GetSubscriptions(context.Background(), reader.UserId, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+	require.Len(t, page.Subscriptions, 1)
+	assert.Equal(t, dto.UserResponse{UserId: author.UserId, DisplayName: "a***@example.com"}, page.Subscriptions[0].Author)
+
+	res, err = subscriptions.Unsubscribe(context.Background(), reader.UserId, author.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.SubscribeResponse{AuthorId: author.UserId}, res)
+	_, err = subscriptions.Unsubscribe(context.Background(), reader.UserId, author.UserId)
+	assert.NoError(t, err, "unsubscribing again is fine")
+}
+
+// flakySink fails post status changes while down is set
+type flakySink struct {
+	down      bool
+	published []events.Event
+}
+
+func (s *flakySink) Publish(ctx context.Context, event events.Event) error {
+	if s.down && event.Type == string(types.EventPostStatusChanged) {
+		return errors.New("broker unavailable")
+	}
+	s.published = append(s.published, event)
+	return nil
+}
+
+func TestNotificationFanout(t *testing.T) {
+	rep := harness.NewMemoryRepository()
+	author := addUser(t, rep, "author@example.com", types.Author)
+	subscriptions := service.NewSubscriptionService(rep)
+	var readers []*dto.UserDB
+	for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+		reader := addUser(t, rep, email, types.Reader)
+		readers = append(readers, reader)
+		_, err := subscriptions.Subscribe(context.Background(), reader.UserId, author.UserId)
+		require.NoError(t, err)
+	}
+	// the third one left before the post came out
+	_, err := subscriptions.Unsubscribe(context.Background(), readers[2].UserId, author.UserId)
+	require.NoError(t, err)
+
+	sink := &flakySink{down: true}
+	relay := service.NewOutboxRelay(rep, service.NewNotificationFanout(rep, sink), 10)
+
+	post, err := rep.CreatePost(context.Background(), author.UserId, "key", "Hello", "content", "", nil, nil)
+	require.NoError(t, err)
+	published := types.Published
+	_, err = rep.UpdatePost(context.Background(), post.PostId, author.UserId, &dto.PostPatch{Status: &published}, nil, nil)
+	require.NoError(t, err)
+
+	page, err := subscriptions.GetNotifications(context.Background(), readers[0].UserId, false, 20, 0)
+	require.NoError(t, err)
+	assert.Empty(t, page.Notifications, "the publish itself notifies nobody")
+
+	// the sink fails after the fan-out, the event comes again and nobody is notified twice
+	count, err := relay.Tick(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, count, "only post.created got through")
+	sink.down = false
+	count, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, sink.published, 2, "the events still reach the sink")
+
+	for i, reader := range readers {
+		page, err := subscriptions.GetNotifications(context.Background(), reader.UserId, false, 20, 0)
+		require.NoError(t, err)
+		if i == 2 {
+			assert.Empty(t, page.Notifications)
+			continue
+		}
+		require.Len(t, page.Notifications, 1)
+		notification := page.Notifications[0]
+		assert.Equal(t, types.NotificationPostPublished, notification.Type)
+		assert.Equal(t, dto.NotificationPostResponse{
+			PostId: post.PostId,
+			Title:  "Hello",
+			Author: dto.UserResponse{UserId: author.UserId, DisplayName: "a***@example.com"},
+		}, notification.Post)
+		assert.Nil(t, notification.ReadAt)
+		assert.Equal(t, 1, page.Unread)
+	}
+
+	// a post published again after going back to draft does not notify again
+	draft := types.Draft
+	_, err = rep.UpdatePost(context.Background(), post.PostId, author.UserId, &dto.PostPatch{Status: &draft}, nil, nil)
+	require.NoError(t, err)
+	_, err = rep.UpdatePost(context.Background(), post.PostId, author.UserId, &dto.PostPatch{Status: &published}, nil, nil)
+	require.NoError(t, err)
+	_, err = relay.Tick(context.Background())
+	require.NoError(t, err)
+	page, err = subscriptions.GetNotifications(context.Background(), readers[0].UserId, false, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+}
+
+func TestSubscriptionService_MarkNotificationsRead(t *testing.T) {
+	rep := harness.NewMemoryRepository()
+	author := addUser(t, rep, "author@example.com", types.Author)
+	reader := addUser(t, rep, "reader@example.com", types.Reader)
+	other := addUser(t, rep, "other@example.com", types.Reader)
+	subscriptions := service.NewSubscriptionService(rep)
+	for _, user := range []*dto.UserDB{reader, other} {
+		_, err := subscriptions.Subscribe(context.Background(), user.UserId, author.UserId)
+		require.NoError(t, err)
+	}
+	published := types.Published
+	for _, key := range []string{"first", "second", "third"} {
+		post, err := rep.CreatePost(context.Background(), author.UserId, key, key, "content", "", nil, nil)
+		require.NoError(t, err)
+		_, err = rep.UpdatePost(context.Background(), post.PostId, author.UserId, &dto.PostPatch{Status: &published}, nil, nil)
+		require.NoError(t, err)
+		_, err = rep.NotifySubscribers(context.Background(), post.PostId)
+		require.NoError(t, err)
+	}
+
+	page, err := subscriptions.GetNotifications(context.Background(), reader.UserId, false, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 3, page.Unread)
+	require.Len(t, page.Notifications, 2)
+	assert.Equal(t, "third", page.Notifications[0].Post.Title, "latest first")
+
+	mine := page.Notifications[0].NotificationId
+	othersPage, err := subscriptions.GetNotifications(context.Background(), other.UserId, false, 20, 0)
+	require.NoError(t, err)
+	theirs := othersPage.Notifications[0].NotificationId
+
+	marked, err := subscriptions.MarkNotificationsRead(context.Background(), reader.UserId, []uuid.UUID{mine, theirs})
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked.Marked, "notifications of someone else are skipped")
+
+	page, err = subscriptions.GetNotifications(context.Background(), reader.UserId, true, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, 2, page.Unread)
+	assert.Len(t, page.Notifications, 2)
+
+	marked, err = subscriptions.MarkNotificationsRead(context.Background(), reader.UserId, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked.Marked)
+	page, err = subscriptions.GetNotifications(context.Background(), reader.UserId, false, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Zero(t, page.Unread)
+
+	othersPage, err = subscriptions.GetNotifications(context.Background(), other.UserId, false, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, othersPage.Unread)
+}
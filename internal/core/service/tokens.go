@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type TokenPurgeRepository interface {
+	PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int, error)
+	PurgePasswordResets(ctx context.Context, before time.Time, limit int) (int, error)
+	PurgePreviewTokens(ctx context.Context, before time.Time, limit int) (int, error)
+	PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// TokenPurger deletes sessions, password reset and preview tokens and idempotency keys that expired
+// more than grace ago. Each runs in batches of batchSize rows so no statement locks a large part of a table.
+type TokenPurger struct {
+	rep       TokenPurgeRepository
+	batchSize int
+	grace     time.Duration
+	// clock defaults to time.Now
+	clock func() time.Time
+
+	refreshTokens  atomic.Int64
+	passwordResets atomic.Int64
+	previewTokens  atomic.Int64
+	idempotency    atomic.Int64
+}
+
+func NewTokenPurger(rep TokenPurgeRepository, batchSize int, grace time.Duration, clock func() time.Time) *TokenPurger {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &TokenPurger{rep: rep, batchSize: batchSize, grace: grace, clock: clock}
+}
+
+// Purge removes every expired token, what it removed before a failure is counted all the same
+func (p *TokenPurger) Purge(ctx context.Context) (*dto.PurgeTokensResponse, error) {
+	before := p.clock().Add(-p.grace)
+	res := &dto.PurgeTokensResponse{}
+
+	var err error
+	res.Purged.RefreshTokens, err = p.batches(ctx, p.rep.PurgeRefreshTokens, before, &p.refreshTokens)
+	if err == nil {
+		res.Purged.PasswordResets, err = p.batches(ctx, p.rep.PurgePasswordResets, before, &p.passwordResets)
+	}
+	if err == nil {
+		res.Purged.PreviewTokens, err = p.batches(ctx, p.rep.PurgePreviewTokens, before, &p.previewTokens)
+	}
+	if err == nil {
+		res.Purged.IdempotencyKeys, err = p.batches(ctx, p.rep.PurgeIdempotencyKeys, before, &p.idempotency)
+	}
+	res.Total = p.Totals()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// batches calls purge until a batch comes back short of batchSize, so the last one found nothing more
+func (p *TokenPurger) batches(ctx context.Context, purge func(context.Context, time.Time, int) (int, error), before time.Time, total *atomic.Int64) (int64, error) {
+	var purged int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		count, err := purge(ctx, before, p.batchSize)
+		purged += int64(count)
+		total.Add(int64(count))
+		if err != nil || count == 0 || count < p.batchSize {
+			return purged, err
+		}
+	}
+}
+
+// Totals is what every purge of this instance removed since it started
+func (p *TokenPurger) Totals() dto.PurgedTokens {
+	return dto.PurgedTokens{
+		RefreshTokens:   p.refreshTokens.Load(),
+		PasswordResets:  p.passwordResets.Load(),
+		PreviewTokens:   p.previewTokens.Load(),
+		IdempotencyKeys: p.idempotency.Load(),
+	}
+}
+
+// Run is one purge of the token purger job
+func (p *TokenPurger) Run(ctx context.Context) error {
+	res, err := p.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	if purged := res.Purged; purged.RefreshTokens+purged.PasswordResets+purged.PreviewTokens+purged.IdempotencyKeys > 0 {
+		slog.Info("purged expired tokens", slog.String("job", "token_purger"),
+			slog.Int64("refresh_tokens", purged.RefreshTokens),
+			slog.Int64("password_resets", purged.PasswordResets),
+			slog.Int64("preview_tokens", purged.PreviewTokens),
+			slog.Int64("idempotency_keys", purged.IdempotencyKeys))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+// fakeTokenPurgeRepository holds a number of expired tokens of each kind and records the batches asked for
+type fakeTokenPurgeRepository struct {
+	refresh, resets, previews, keys int
+	before                          []time.Time
+	limits                          []int
+	// failAfter fails the batch after that many, -1 never
+	failAfter int
+}
+
+func (f *fakeTokenPurgeRepository) take(before time.Time, limit int, expired *int) (int, error) {
+	if f.failAfter >= 0 && len(f.limits) == f.failAfter {
+		return 0, sql.ErrConnDone
+	}
+	f.before = append(f.before, before)
+	f.limits = append(f.limits, limit)
+	count := min(limit, *expired)
+	*expired -= count
+	return count, nil
+}
+
+func (f *fakeTokenPurgeRepository) PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	return f.take(before, limit, &f.refresh)
+}
+
+func (f *fakeTokenPurgeRepository) PurgePasswordResets(ctx context.Context, before time.Time, limit int) (int, error) {
+	return f.take(before, limit, &f.resets)
+}
+
+func (f *fakeTokenPurgeRepository) PurgePreviewTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	return f.take(before, limit, &f.previews)
+}
+
+func (f *fakeTokenPurgeRepository) PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error) {
+	return f.take(before, limit, &f.keys)
+}
+
+func TestTokenPurger_Purge(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rep := &fakeTokenPurgeRepository{refresh: 7, resets: 3, previews: 0, keys: 2, failAfter: -1}
+	p := NewTokenPurger(rep, 3, 24*time.Hour, func() time.Time { return now })
+
+	res, err := p.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, dto.PurgedTokens{RefreshTokens: 7, PasswordResets: 3, IdempotencyKeys: 2}, res.Purged)
+	assert.Equal(t, res.Purged, res.Total)
+
+	// 7 refresh tokens take 3+3+1, the short batch ends them; 3 resets fill a batch so an empty one
+	// follows; no previews end after one empty batch; 2 idempotency keys fit a short one
+	assert.Equal(t, []int{3, 3, 3, 3, 3, 3, 3}, rep.limits)
+	for _, before := range rep.before {
+		assert.Equal(t, now.Add(-24*time.Hour), before)
+	}
+	assert.Zero(t, rep.refresh)
+	assert.Zero(t, rep.resets)
+	assert.Zero(t, rep.keys)
+}
+
+func TestTokenPurger_TotalsAddUp(t *testing.T) {
+	rep := &fakeTokenPurgeRepository{refresh: 2, previews: 1, failAfter: -1}
+	p := NewTokenPurger(rep, 10, time.Hour, nil)
+
+	_, err := p.Purge(context.Background())
+	require.NoError(t, err)
+	rep.resets = 4
+	res, err := p.Purge(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.PurgedTokens{PasswordResets: 4}, res.Purged)
+	assert.Equal(t, dto.PurgedTokens{RefreshTokens: 2, PasswordResets: 4, PreviewTokens: 1}, res.Total)
+	assert.Equal(t, res.Total, p.Totals())
+}
+
+func TestTokenPurger_StopsOnError(t *testing.T) {
+	rep := &fakeTokenPurgeRepository{refresh: 5, resets: 5, previews: 5, failAfter: 2}
+	p := NewTokenPurger(rep, 2, time.Hour, nil)
+
+	_, err := p.Purge(context.Background())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Equal(t, []int{2, 2}, rep.limits, "no batch follows the failed one")
+	assert.Equal(t, dto.PurgedTokens{RefreshTokens: 4}, p.Totals(), "what was purged before still counts")
+	assert.Equal(t, 5, rep.resets)
+}
+
+func TestTokenPurger_StopsWhenCancelled(t *testing.T) {
+	rep := &fakeTokenPurgeRepository{refresh: 100, failAfter: -1}
+	p := NewTokenPurger(rep, 10, time.Hour, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.Run(ctx), context.Canceled)
+	assert.Empty(t, rep.limits)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+type TrashRepository interface {
+	GetExpiredTrash(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error)
+	PurgePost(ctx context.Context, id uuid.UUID) error
+}
+
+// TrashCleaner periodically removes posts that stayed in the trash longer than the retention,
+// their images are removed from the storage first so no object outlives its row.
+type TrashCleaner struct {
+	rep       TrashRepository
+	stor      storage.ImageStorage
+	retention time.Duration
+	// clock defaults to time.Now
+	clock func() time.Time
+}
+
+func NewTrashCleaner(rep TrashRepository, stor storage.ImageStorage, retention time.Duration, clock func() time.Time) *TrashCleaner {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &TrashCleaner{rep, stor, retention, clock}
+}
+
+// Tick purges every expired post, a post whose images could not be removed is left for the next tick
+func (c *TrashCleaner) Tick(ctx context.Context) (int, error) {
+	ids, err := c.rep.GetExpiredTrash(ctx, c.clock().Add(-c.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		if err := c.purge(ctx, id); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (c *TrashCleaner) purge(ctx context.Context, postId uuid.UUID) error {
+	images, err := c.rep.GetPostImages(ctx, postId)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		if err := c.stor.DeleteImage(ctx, objectName(image.ImageUrl)); err != nil {
+			return err
+		}
+	}
+	return c.rep.PurgePost(ctx, postId)
+}
+
+// Run is one tick of the trash cleaner job
+func (c *TrashCleaner) Run(ctx context.Context) error {
+	count, err := c.Tick(ctx)
+	if count > 0 {
+		slog.Info("purged trashed posts", slog.String("job", "trash_cleaner"), slog.Int("count", count))
+	}
+	return err
+}
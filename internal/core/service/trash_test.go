@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/storage"
+)
+
+type fakeTrashRepository struct {
+	deletedAt map[uuid.UUID]time.Time
+	images    map[uuid.UUID][]*dto.ImageDB
+}
+
+func (f *fakeTrashRepository) GetExpiredTrash(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, deletedAt := range f.deletedAt {
+		if deletedAt.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeTrashRepository) GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error) {
+	return f.images[postId], nil
+}
+
+func (f *fakeTrashRepository) PurgePost(ctx context.Context, id uuid.UUID) error {
+	delete(f.deletedAt, id)
+	delete(f.images, id)
+	return nil
+}
+
+type recordingStorage struct {
+	put      map[string]string
+	modified map[string]time.Time
+	deleted  []string
+}
+
+func (s *recordingStorage) PutImage(ctx context.Context, fileName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) != fileSize {
+		return "", fmt.Errorf("%s is %d bytes, %d announced", fileName, len(data), fileSize)
+	}
+	if s.put == nil {
+		s.put = map[string]string{}
+	}
+	s.put[fileName] = string(data)
+	return "/images/" + fileName, nil
+}
+
+func (s *recordingStorage) DeleteImage(ctx context.Context, objectName string) error {
+	s.deleted = append(s.deleted, objectName)
+	delete(s.put, objectName)
+	return nil
+}
+
+func (s *recordingStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	data, ok := s.put[objectName]
+	if !ok {
+		return nil, "", os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), "application/octet-stream", nil
+}
+
+func (s *recordingStorage) ImageExists(objectName string) (bool, error) {
+	_, ok := s.put[objectName]
+	return ok, nil
+}
+
+func (s *recordingStorage) Public() bool {
+	return true
+}
+
+func (s *recordingStorage) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	return fakePresigner{}.PresignGet(objectName, ttl)
+}
+
+func (s *recordingStorage) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	for _, name := range slices.Sorted(maps.Keys(s.put)) {
+		if err := emit(storage.ImageObject{Name: name, LastModified: s.modified[name]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTrashCleaner_PurgesExpiredPosts(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expired, fresh := uuid.New(), uuid.New()
+	imageId := uuid.New()
+	image := &dto.ImageDB{ImageId: imageId, PostId: expired, ImageUrl: "/images/" + imageId.String() + ".png"}
+	rep := &fakeTrashRepository{
+		deletedAt: map[uuid.UUID]time.Time{
+			expired: now.Add(-48 * time.Hour),
+			fresh:   now.Add(-time.Hour),
+		},
+		images: map[uuid.UUID][]*dto.ImageDB{expired: {image}},
+	}
+	stor := &recordingStorage{}
+	c := NewTrashCleaner(rep, stor, 24*time.Hour, func() time.Time { return now })
+
+	count, err := c.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{image.ImageId.String() + ".png"}, stor.deleted)
+	assert.NotContains(t, rep.deletedAt, expired)
+	assert.Contains(t, rep.deletedAt, fresh)
+
+	count, err = c.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
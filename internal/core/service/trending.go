@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// defaultTrendingWindow and defaultTrendingLikeWeight rank the posts of a nil Trending
+	defaultTrendingWindow     = 7 * 24 * time.Hour
+	defaultTrendingLikeWeight = 3
+)
+
+// Trending ranks the published posts by their views and likes of the last window, a like weighs as much
+// as likeWeight views. The ranking is the same for every reader and expensive, so it is kept for ttl;
+// changes to posts show up after ttl at the latest. A nil Trending ranks over the default window and
+// keeps nothing.
+type Trending struct {
+	window     time.Duration
+	likeWeight float64
+	ttl        time.Duration
+	clock      func() time.Time
+
+	mu      sync.Mutex
+	entries map[int]cachedTrending
+	loads   singleflight.Group
+}
+
+type cachedTrending struct {
+	posts   dto.TrendingPostsResponse
+	expires time.Time
+}
+
+// NewTrending reads window and likeWeight, anything not above zero takes the default. A zero ttl
+// ranks the posts for every request.
+func NewTrending(window time.Duration, likeWeight float64, ttl time.Duration, clock func() time.Time) *Trending {
+	if window <= 0 {
+		window = defaultTrendingWindow
+	}
+	if likeWeight <= 0 {
+		likeWeight = defaultTrendingLikeWeight
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	return &Trending{window: window, likeWeight: likeWeight, ttl: ttl, clock: clock, entries: map[int]cachedTrending{}}
+}
+
+func (t *Trending) weights() (time.Duration, float64) {
+	if t == nil {
+		return defaultTrendingWindow, defaultTrendingLikeWeight
+	}
+	return t.window, t.likeWeight
+}
+
+// get returns the ranking of limit posts kept or loads it, concurrent misses share a single load
+func (t *Trending) get(limit int, load func() (dto.TrendingPostsResponse, error)) (dto.TrendingPostsResponse, error) {
+	if t == nil || t.ttl <= 0 {
+		return load()
+	}
+
+	t.mu.Lock()
+	entry, ok := t.entries[limit]
+	t.mu.Unlock()
+	if ok && t.clock().Before(entry.expires) {
+		return entry.posts, nil
+	}
+
+	loaded, err, _ := t.loads.Do(strconv.Itoa(limit), func() (interface{}, error) {
+		posts, err := load()
+		if err != nil {
+			return nil, err
+		}
+		t.mu.Lock()
+		t.entries[limit] = cachedTrending{posts, t.clock().Add(t.ttl)}
+		t.mu.Unlock()
+		return posts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loaded.(dto.TrendingPostsResponse), nil
+}
+
+// GetTrendingPosts returns up to limit published posts ranked by their recent views and likes. Posts
+// without any follow newest first, which is all there is while nothing was viewed or liked recently.
+// The ranking is shared by every reader, callers must copy a post before changing it.
+func (s *ReaderService) GetTrendingPosts(ctx context.Context, limit int) (dto.TrendingPostsResponse, error) {
+	return s.trending.get(limit, func() (dto.TrendingPostsResponse, error) {
+		window, likeWeight := s.trending.weights()
+		found, err := s.rep.GetTrendingPosts(ctx, window, likeWeight, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := make([]*dto.PostUserDB, len(found))
+		for i, post := range found {
+			raw[i] = &post.PostUserDB
+		}
+		posts, err := s.proccessPostsToResponse(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(dto.TrendingPostsResponse, len(posts))
+		for i, post := range posts {
+			res[i] = dto.TrendingPostResponse{GetPostResponse: *post, Score: found[i].Score}
+		}
+		return res, nil
+	})
+}
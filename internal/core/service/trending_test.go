@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func trendingTitles(posts dto.TrendingPostsResponse) []string {
+	res := make([]string, len(posts))
+	for i, post := range posts {
+		res[i] = post.Title
+	}
+	return res
+}
+
+func TestReaderService_GetTrendingPosts(t *testing.T) {
+	seeded, authorId := seedStatuses(t)
+	viewed := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "viewed", Status: types.Published}
+	liked := &dto.PostDB{PostId: uuid.New(), AuthorId: authorId, Title: "liked", Status: types.Published}
+	seeded.posts = append(seeded.posts, viewed, liked)
+	seeded.scores = map[uuid.UUID]float64{viewed.PostId: 2, liked.PostId: 3}
+	s := NewReaderService(seeded, nil, nil, nil, nil, nil, nil)
+
+	posts, err := s.GetTrendingPosts(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"liked", "viewed", "published"}, trendingTitles(posts), "posts without a score follow")
+	assert.Equal(t, 3.0, posts[0].Score)
+	assert.Equal(t, authorId, posts[0].Author.UserId)
+
+	posts, err = s.GetTrendingPosts(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"liked"}, trendingTitles(posts))
+}
+
+func TestTrending_Weights(t *testing.T) {
+	var none *Trending
+	window, likeWeight := none.weights()
+	assert.Equal(t, 7*24*time.Hour, window)
+	assert.Equal(t, 3.0, likeWeight)
+
+	window, likeWeight = NewTrending(0, -1, time.Minute, nil).weights()
+	assert.Equal(t, 7*24*time.Hour, window)
+	assert.Equal(t, 3.0, likeWeight)
+
+	window, likeWeight = NewTrending(time.Hour, 5, time.Minute, nil).weights()
+	assert.Equal(t, time.Hour, window)
+	assert.Equal(t, 5.0, likeWeight)
+}
+
+func TestTrending_KeptForTTL(t *testing.T) {
+	seeded, _ := seedStatuses(t)
+	rep := &countingReaderRepository{fakeReaderRepository: seeded}
+	now := time.Now()
+	s := NewReaderService(rep, nil, nil, nil, nil, nil, NewTrending(time.Hour, 3, time.Minute, func() time.Time { return now }))
+
+	posts, err := s.GetTrendingPosts(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"published"}, trendingTitles(posts))
+	for _, post := range seeded.posts {
+		post.Status = types.Published
+	}
+
+	now = now.Add(59 * time.Second)
+	posts, err = s.GetTrendingPosts(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, posts, 1, "the ranking is kept for a minute")
+	assert.Equal(t, 1, rep.trendings)
+
+	_, err = s.GetTrendingPosts(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rep.trendings, "every limit is ranked on its own")
+
+	now = now.Add(2 * time.Second)
+	posts, err = s.GetTrendingPosts(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, posts, 3)
+	assert.Equal(t, 3, rep.trendings)
+
+	t.Run("no ttl", func(t *testing.T) {
+		rep := &countingReaderRepository{fakeReaderRepository: seeded}
+		s := NewReaderService(rep, nil, nil, nil, nil, nil, NewTrending(time.Hour, 3, 0, nil))
+		for range 2 {
+			_, err := s.GetTrendingPosts(context.Background(), 10)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, rep.trendings)
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"golang.org/x/sync/singleflight"
+)
+
+// UserCache keeps the users AuthorizeUser loads for a short time, since every authorized request
+// needs one. Whatever changes a user has to Forget it, other changes show up after ttl at the latest.
+type UserCache struct {
+	ttl   time.Duration
+	size  int
+	clock func() time.Time
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedUser
+	// generation grows with every Forget, a load that started before one is not stored
+	generation uint64
+	loads      singleflight.Group
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cachedUser struct {
+	user    dto.UserDB
+	expires time.Time
+}
+
+// NewUserCache makes a cache of at most size users, a zero ttl or size turns it off
+func NewUserCache(ttl time.Duration, size int, clock func() time.Time) *UserCache {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &UserCache{ttl: ttl, size: size, clock: clock, entries: map[uuid.UUID]cachedUser{}}
+}
+
+func (c *UserCache) enabled() bool {
+	return c != nil && c.ttl > 0 && c.size > 0
+}
+
+// Get returns the cached user or loads it, concurrent misses for one user share a single load
+// that runs in the ctx of the first of them
+func (c *UserCache) Get(ctx context.Context, id uuid.UUID, load func(context.Context, uuid.UUID) (*dto.UserDB, error)) (*dto.UserDB, error) {
+	if !c.enabled() {
+		return load(ctx, id)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && c.clock().Before(entry.expires) {
+		c.hits.Add(1)
+		user := entry.user
+		return &user, nil
+	}
+
+	c.misses.Add(1)
+	loaded, err, _ := c.loads.Do(id.String(), func() (interface{}, error) {
+		user, err := load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		c.store(id, user, generation)
+		return *user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	user := loaded.(dto.UserDB)
+	return &user, nil
+}
+
+func (c *UserCache) store(id uuid.UUID, user *dto.UserDB, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	now := c.clock()
+	if len(c.entries) >= c.size {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	// still full of live users, any of them makes room
+	for key := range c.entries {
+		if len(c.entries) < c.size {
+			break
+		}
+		delete(c.entries, key)
+	}
+	c.entries[id] = cachedUser{*user, now.Add(c.ttl)}
+}
+
+// Forget drops a user, the next Get loads it again
+func (c *UserCache) Forget(id uuid.UUID) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.generation++
+	c.mu.Unlock()
+	c.loads.Forget(id.String())
+}
+
+// Hits counts the users served from the cache since the start
+func (c *UserCache) Hits() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.hits.Load()
+}
+
+// Misses counts the users that had to be loaded since the start
+func (c *UserCache) Misses() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.misses.Load()
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/jwt"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// countingAuthRepository counts the users loaded by id, optionally holding every load until release is closed
+type countingAuthRepository struct {
+	*fakeAuthRepository
+	mu      sync.Mutex
+	loads   atomic.Int64
+	release chan struct{}
+}
+
+func (f *countingAuthRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	f.loads.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, err := f.fakeAuthRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *countingAuthRepository) UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fakeAuthRepository.UpdateUserRole(ctx, id, role)
+}
+
+func newCachedAuthService(rep AuthRepository, ttl time.Duration) *AuthService {
+	return NewAuthService(rep, newFakeMailer(), nil, AuthConfig{
+		Secret:        "test-secret",
+		AccessTTL:     15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+		Tokens:        jwt.Options{Issuer: "blog", Audience: "blog-api"},
+		UserCacheTTL:  ttl,
+		UserCacheSize: 100,
+	})
+}
+
+func TestUserCache_HitsAndExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewUserCache(30*time.Second, 10, func() time.Time { return now })
+	id := uuid.New()
+	loads := 0
+	load := func(_ context.Context, id uuid.UUID) (*dto.UserDB, error) {
+		loads++
+		return &dto.UserDB{UserId: id, Role: types.Reader}, nil
+	}
+
+	for range 3 {
+		user, err := cache.Get(context.Background(), id, load)
+		require.NoError(t, err)
+		assert.Equal(t, id, user.UserId)
+	}
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, int64(2), cache.Hits())
+	assert.Equal(t, int64(1), cache.Misses())
+
+	now = now.Add(30 * time.Second)
+	_, err := cache.Get(context.Background(), id, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// callers get copies, changing one does not change the cache
+	user, _ := cache.Get(context.Background(), id, load)
+	user.Role = types.Admin
+	again, _ := cache.Get(context.Background(), id, load)
+	assert.Equal(t, types.Reader, again.Role)
+}
+
+func TestUserCache_Size(t *testing.T) {
+	cache := NewUserCache(time.Minute, 3, nil)
+	load := func(_ context.Context, id uuid.UUID) (*dto.UserDB, error) { return &dto.UserDB{UserId: id}, nil }
+
+	for range 10 {
+		_, err := cache.Get(context.Background(), uuid.New(), load)
+		require.NoError(t, err)
+	}
+	assert.Len(t, cache.entries, 3)
+}
+
+func TestUserCache_Disabled(t *testing.T) {
+	cache := NewUserCache(0, 10, nil)
+	id := uuid.New()
+	loads := 0
+	load := func(_ context.Context, id uuid.UUID) (*dto.UserDB, error) {
+		loads++
+		return &dto.UserDB{UserId: id}, nil
+	}
+
+	cache.Get(context.Background(), id, load)
+	cache.Get(context.Background(), id, load)
+	cache.Forget(id)
+	assert.Equal(t, 2, loads)
+
+	// a service without a cache may forget too
+	var none *UserCache
+	none.Forget(id)
+}
+
+func TestUserCache_ConcurrentMissesShareOneLoad(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(user), release: make(chan struct{})}
+	cache := NewUserCache(time.Minute, 10, nil)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := cache.Get(context.Background(), user.UserId, rep.GetUserById)
+			assert.NoError(t, err)
+			assert.Equal(t, user.UserId, got.UserId)
+		}()
+	}
+	// let the callers pile up behind the first load
+	time.Sleep(20 * time.Millisecond)
+	close(rep.release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), rep.loads.Load())
+}
+
+func TestUserCache_ForgetDuringLoad(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(user), release: make(chan struct{})}
+	cache := NewUserCache(time.Minute, 10, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Get(context.Background(), user.UserId, rep.GetUserById)
+	}()
+	for rep.loads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// the role changes while the old row is being read
+	cache.Forget(user.UserId)
+	close(rep.release)
+	<-done
+
+	rep.UpdateUserRole(context.Background(), user.UserId, types.Author)
+	got, err := cache.Get(context.Background(), user.UserId, rep.GetUserById)
+	require.NoError(t, err)
+	assert.Equal(t, types.Author, got.Role)
+}
+
+func TestAuthService_UserCacheInvalidation(t *testing.T) {
+	t.Run("become author", func(t *testing.T) {
+		user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: types.Reader}
+		rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(user)}
+		s := newCachedAuthService(rep, time.Minute)
+		token, _ := s.accessToken(user.UserId, uuid.Nil)
+
+		cached, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		_, err = s.BecomeAuthor(context.Background(), cached, uuid.Nil, &dto.ChangeOwnRoleRequest{Role: types.Author})
+		require.NoError(t, err)
+
+		authorized, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, types.Author, authorized.Role)
+		assert.Equal(t, int64(2), rep.loads.Load())
+	})
+
+	t.Run("admin changes the role", func(t *testing.T) {
+		user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: types.Author}
+		rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(user)}
+		s := newCachedAuthService(rep, time.Minute)
+		admin := NewAdminService(&fakeAdminRoleRepository{rep}, s.Users(), nil, nil)
+		token, _ := s.accessToken(user.UserId, uuid.Nil)
+
+		_, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		_, err = admin.ChangeUserRole(context.Background(), uuid.New(), user.UserId, &dto.ChangeRoleRequest{Role: types.Reader})
+		require.NoError(t, err)
+
+		authorized, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, types.Reader, authorized.Role)
+	})
+
+	t.Run("stale without invalidation", func(t *testing.T) {
+		user := &dto.UserDB{UserId: uuid.New(), Email: "user@example.com", Role: types.Reader}
+		rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(user)}
+		s := newCachedAuthService(rep, time.Minute)
+		token, _ := s.accessToken(user.UserId, uuid.Nil)
+
+		_, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		rep.UpdateUserRole(context.Background(), user.UserId, types.Author)
+
+		// a change made behind the services waits for the ttl
+		authorized, err := s.AuthorizeUser(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, types.Reader, authorized.Role)
+		assert.Equal(t, int64(1), rep.loads.Load())
+	})
+}
+
+// fakeAdminRoleRepository gives AdminService the role update of the auth fake
+type fakeAdminRoleRepository struct {
+	*countingAuthRepository
+}
+
+func (f *fakeAdminRoleRepository) GetUsers(ctx context.Context, limit, offset int) ([]*dto.UserDB, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminRoleRepository) CountUsers(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminRoleRepository) GetAuditEntries(ctx context.Context, filter dto.AuditFilter, limit, offset int) ([]*dto.AuditEntryDB, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminRoleRepository) CountAuditEntries(ctx context.Context, filter dto.AuditFilter) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminRoleRepository) UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminRoleRepository) GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeAdminRoleRepository) GetReportedPosts(ctx context.Context, limit, offset int) ([]*dto.ReportedPostDB, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminRoleRepository) CountReportedPosts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminRoleRepository) CountOpenReports(ctx context.Context, postId uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminRoleRepository) ResolveReports(ctx context.Context, postId, adminId uuid.UUID, action types.ModerationAction) (int, error) {
+	return 0, nil
+}
+
+func BenchmarkAuthService_AuthorizeUser(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{"uncached", 0},
+		{"cached", 30 * time.Second},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			users := make([]*dto.UserDB, 16)
+			for i := range users {
+				users[i] = &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+			}
+			rep := &countingAuthRepository{fakeAuthRepository: newFakeAuthRepository(users...)}
+			s := newCachedAuthService(rep, bench.ttl)
+			tokens := make([]string, len(users))
+			for i, user := range users {
+				tokens[i], _ = s.accessToken(user.UserId, uuid.Nil)
+			}
+
+			var next atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := s.AuthorizeUser(context.Background(), tokens[next.Add(1)%int64(len(tokens))]); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+			b.ReportMetric(float64(rep.loads.Load())/float64(b.N), "loads/op")
+		})
+	}
+}
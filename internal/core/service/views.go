@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type ViewRepository interface {
+	RecordViews(ctx context.Context, views []dto.PostView) error
+}
+
+// ViewCounter takes views off the read path: Record only queues the view and a worker writes
+// them in batches. A full queue drops the view, a lost count is cheaper than a slow read.
+type ViewCounter struct {
+	rep       ViewRepository
+	queue     chan dto.PostView
+	flushes   chan chan struct{}
+	batchSize int
+	interval  time.Duration
+	// clock defaults to time.Now
+	clock func() time.Time
+
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewViewCounter(rep ViewRepository, buffer, batchSize int, interval time.Duration, clock func() time.Time) *ViewCounter {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &ViewCounter{
+		rep:       rep,
+		queue:     make(chan dto.PostView, buffer),
+		flushes:   make(chan chan struct{}),
+		batchSize: max(batchSize, 1),
+		interval:  interval,
+		clock:     clock,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Record queues a view of the post by the user, it never blocks
+func (c *ViewCounter) Record(postId, userId uuid.UUID) {
+	view := dto.PostView{
+		PostId: postId,
+		UserId: userId,
+		Day:    c.clock().UTC().Truncate(24 * time.Hour),
+	}
+	select {
+	case c.queue <- view:
+	default:
+		slog.Warn("view queue is full, dropping view", slog.String("post_id", postId.String()), slog.String("user_id", userId.String()))
+	}
+}
+
+// Start runs the worker, it writes a batch when it is full and every interval, a non-positive
+// interval leaves only full batches, Flush and Stop
+func (c *ViewCounter) Start() {
+	if c.started.Swap(true) {
+		return
+	}
+	go c.run()
+}
+
+// Flush writes every queued view and returns once they are stored, it is a no-op before Start and after Stop
+func (c *ViewCounter) Flush() {
+	if !c.started.Load() {
+		return
+	}
+	ack := make(chan struct{})
+	select {
+	case c.flushes <- ack:
+		<-ack
+	case <-c.done:
+	}
+}
+
+// Stop writes the queued views and waits for the worker, it is safe to call more than once
+func (c *ViewCounter) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	if c.started.Load() {
+		<-c.done
+	}
+}
+
+func (c *ViewCounter) run() {
+	defer close(c.done)
+	// the views outlive the requests that read the posts, their writes belong to the worker
+	ctx := context.Background()
+
+	var tick <-chan time.Time
+	if c.interval > 0 {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	batch := make([]dto.PostView, 0, c.batchSize)
+	for {
+		select {
+		case view := <-c.queue:
+			batch = append(batch, view)
+			if len(batch) >= c.batchSize {
+				batch = c.write(ctx, batch)
+			}
+		case <-tick:
+			batch = c.write(ctx, batch)
+		case ack := <-c.flushes:
+			batch = c.write(ctx, c.drain(batch))
+			close(ack)
+		case <-c.stop:
+			c.write(ctx, c.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves every queued view into the batch without waiting for more
+func (c *ViewCounter) drain(batch []dto.PostView) []dto.PostView {
+	for {
+		select {
+		case view := <-c.queue:
+			batch = append(batch, view)
+		default:
+			return batch
+		}
+	}
+}
+
+// write stores the batch without repeats in chunks of batchSize and returns it emptied,
+// a failed chunk is logged and dropped
+func (c *ViewCounter) write(ctx context.Context, batch []dto.PostView) []dto.PostView {
+	seen := make(map[dto.PostView]bool, len(batch))
+	unique := make([]dto.PostView, 0, len(batch))
+	for _, view := range batch {
+		if !seen[view] {
+			seen[view] = true
+			unique = append(unique, view)
+		}
+	}
+	for chunk := range slices.Chunk(unique, c.batchSize) {
+		if err := c.rep.RecordViews(ctx, chunk); err != nil {
+			slog.Error("record post views", slog.String("job", "view_counter"), slog.Int("count", len(chunk)), slog.String("error", err.Error()))
+		}
+	}
+	return batch[:0]
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type fakeViewRepository struct {
+	mu      sync.Mutex
+	batches [][]dto.PostView
+}
+
+func (f *fakeViewRepository) RecordViews(ctx context.Context, views []dto.PostView) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, append([]dto.PostView(nil), views...))
+	return nil
+}
+
+func (f *fakeViewRepository) stored() [][]dto.PostView {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.batches
+}
+
+func TestViewCounter_DeduplicatesPerDay(t *testing.T) {
+	now := time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC)
+	rep := &fakeViewRepository{}
+	c := NewViewCounter(rep, 16, 16, 0, func() time.Time { return now })
+	c.Start()
+	defer c.Stop()
+	postId, userId := uuid.New(), uuid.New()
+
+	c.Record(postId, userId)
+	c.Record(postId, userId)
+	now = now.Add(2 * time.Hour)
+	c.Record(postId, userId)
+	c.Flush()
+
+	assert.Equal(t, [][]dto.PostView{{
+		{PostId: postId, UserId: userId, Day: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{PostId: postId, UserId: userId, Day: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}}, rep.stored())
+}
+
+func TestViewCounter_WritesFullBatches(t *testing.T) {
+	rep := &fakeViewRepository{}
+	c := NewViewCounter(rep, 16, 2, 0, nil)
+	c.Start()
+	defer c.Stop()
+
+	for range 5 {
+		c.Record(uuid.New(), uuid.New())
+	}
+	c.Flush()
+
+	batches := rep.stored()
+	if assert.Len(t, batches, 3) {
+		assert.Len(t, batches[0], 2)
+		assert.Len(t, batches[1], 2)
+		assert.Len(t, batches[2], 1)
+	}
+}
+
+func TestViewCounter_StopWritesQueuedViews(t *testing.T) {
+	rep := &fakeViewRepository{}
+	c := NewViewCounter(rep, 16, 16, time.Hour, nil)
+	c.Start()
+
+	c.Record(uuid.New(), uuid.New())
+	c.Stop()
+	c.Stop()
+	c.Flush()
+
+	assert.Len(t, rep.stored(), 1)
+}
+
+func TestViewCounter_DropsViewsWhenFull(t *testing.T) {
+	rep := &fakeViewRepository{}
+	c := NewViewCounter(rep, 2, 16, 0, nil)
+
+	// not started, so nothing drains the queue
+	for range 3 {
+		c.Record(uuid.New(), uuid.New())
+	}
+	c.Flush()
+	assert.Empty(t, rep.stored())
+
+	c.Start()
+	c.Stop()
+	if assert.Len(t, rep.stored(), 1) {
+		assert.Len(t, rep.stored()[0], 2)
+	}
+}
+
+func TestViewCounter_StopBeforeStart(t *testing.T) {
+	c := NewViewCounter(&fakeViewRepository{}, 1, 1, 0, nil)
+	c.Stop()
+	c.Start()
+	c.Stop()
+}
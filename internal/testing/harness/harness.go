@@ -0,0 +1,169 @@
+// Package harness boots the whole http stack (routers, middlewares, services) on an
+// ephemeral port with in-memory repositories, so end-to-end tests need neither postgres nor minio.
+package harness
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/servers"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type Harness struct {
+	Server     *servers.HttpServer
+	Repository *MemoryRepository
+	Storage    *MemoryStorage
+	Mailer     *MemoryMailer
+	// Events receives the post events, call Server.Outbox().Tick() to relay them
+	Events *MemoryEventSink
+	// Clock drives the publish scheduler, call Server.Scheduler().Tick() after moving it
+	Clock *Clock
+	// BaseURL points at the current api version, e.g. http://127.0.0.1:41234/api/v1
+	BaseURL string
+	Client  *http.Client
+}
+
+// New starts a server for the test and stops it on cleanup, configure changes the configuration before
+func New(t testing.TB, configure ...func(*servers.HttpServerConfig)) *Harness {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("harness: listen: %v", err)
+	}
+
+	rep := NewMemoryRepository()
+	clock := NewClock(time.Now())
+	stor := NewMemoryStorage(clock.Now)
+	mail := NewMemoryMailer()
+	sink := &MemoryEventSink{}
+	cfg := servers.HttpServerConfig{
+		Secret:        "harness-secret",
+		HealthTimeout: time.Second,
+
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    64 << 10,
+		UploadTimeout:     5 * time.Minute,
+		ExportTimeout:     5 * time.Minute,
+		MaxBodyBytes:      1 << 20,
+		MaxUploadBytes:    25 << 20,
+		MaxImportBytes:    8 << 20,
+		Gzip:              true,
+		GzipMinBytes:      1024,
+
+		AccessTTL:     2 * time.Hour,
+		RefreshTTL:    7 * 24 * time.Hour,
+		MaxSessions:   10,
+		UserCacheTTL:  30 * time.Second,
+		UserCacheSize: 100,
+
+		// the cheapest params the configuration accepts
+		PasswordHashMemory:      19 * 1024,
+		PasswordHashIterations:  2,
+		PasswordHashParallelism: 1,
+
+		PasswordResetTTL: time.Hour,
+		PasswordResetURL: "http://localhost/reset-password",
+		// authors publish right after registering unless a test turns RequireVerifiedEmail on
+		VerifyEmailTTL:       72 * time.Hour,
+		VerifyEmailURL:       "http://localhost/api/auth/verify-email",
+		VerifyResendInterval: time.Minute,
+
+		AccountDeletionPolicy: types.DeletionAnonymize,
+
+		// ticks are driven by the tests
+		SchedulerInterval:     0,
+		TrashRetention:        720 * time.Hour,
+		TrashCleanupInterval:  0,
+		OrphanCleanupInterval: 0,
+		OrphanMinAge:          24 * time.Hour,
+		ViewsBuffer:           64,
+		ViewsBatchSize:        16,
+		ViewsFlushInterval:    0,
+		OutboxInterval:        0,
+		OutboxBatchSize:       100,
+		TokenPurgeInterval:    0,
+		TokenPurgeGrace:       24 * time.Hour,
+		IdempotencyKeyTTL:     24 * time.Hour,
+		TokenPurgeBatchSize:   100,
+		JobsStopTimeout:       5 * time.Second,
+
+		StreamHeartbeat:   30 * time.Second,
+		StreamBuffer:      16,
+		PublicCacheMaxAge: time.Minute,
+		ImagesStripExif:   true,
+
+		ImagesPerPost:       4,
+		MaxImageBytes:       4 << 20,
+		ImagesUploadWorkers: 2,
+	}
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+	server := servers.NewHttpServer(cfg, servers.HttpServerOptions{
+		Repository:   rep,
+		ImageStorage: stor,
+		Mailer:       mail,
+		EventSink:    sink,
+		Listener:     listener,
+		Clock:        clock.Now,
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Start()
+	}()
+
+	h := &Harness{
+		Server:     server,
+		Repository: rep,
+		Storage:    stor,
+		Mailer:     mail,
+		Events:     sink,
+		Clock:      clock,
+		BaseURL:    "http://" + server.Addr() + "/api/v1",
+		Client:     &http.Client{Timeout: 5 * time.Second},
+	}
+
+	t.Cleanup(func() {
+		h.Client.CloseIdleConnections()
+		if err := server.Stop(); err != nil {
+			t.Errorf("harness: stop: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Errorf("harness: serve: %v", err)
+		}
+	})
+
+	return h
+}
+
+// Do sends a request to the api, token is sent as a bearer token when not empty
+func (h *Harness) Do(t testing.TB, method, path, token, contentType string, body io.Reader) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, h.BaseURL+path, body)
+	if err != nil {
+		t.Fatalf("harness: new request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		t.Fatalf("harness: %s %s: %v", method, path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
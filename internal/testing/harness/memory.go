@@ -0,0 +1,2140 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/events"
+	"github.com/xkarasb/blog/pkg/storage"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// MemoryRepository mimics PostgresRepository on plain maps, including sql.ErrNoRows and unique violations
+type MemoryRepository struct {
+	mu     sync.Mutex
+	users  map[uuid.UUID]*dto.UserDB
+	posts  map[uuid.UUID]*dto.PostDB
+	images map[uuid.UUID]*dto.ImageDB
+	resets map[string]passwordReset
+	// sessions are kept by id
+	sessions map[uuid.UUID]*dto.SessionDB
+	apiKeys  map[uuid.UUID]*dto.ApiKeyDB
+	// idempotency holds the claimed keys by user and key
+	idempotency map[idempotencyKey]*dto.IdempotencyKeyDB
+	// previews hold the post and expiry of each preview token hash
+	previews map[string]previewToken
+	views    map[dto.PostView]struct{}
+	// likes keep the order they were made in, like created_at of post_likes
+	likes   map[postLike]int
+	likeSeq int
+	// revisions are kept oldest first
+	revisions []*dto.PostRevisionDB
+	// audit is kept oldest first
+	audit []*dto.AuditEntryDB
+	// outbox is kept in seq order
+	outbox []*dto.OutboxEventDB
+	// reports are kept oldest first
+	reports []*dto.ReportDB
+	// subscriptions and notifications are kept oldest first
+	subscriptions []*subscription
+	notifications []*dto.NotificationDB
+}
+
+type subscription struct {
+	readerId  uuid.UUID
+	authorId  uuid.UUID
+	createdAt time.Time
+}
+
+type postLike struct {
+	postId uuid.UUID
+	userId uuid.UUID
+}
+
+type idempotencyKey struct {
+	userId uuid.UUID
+	key    string
+}
+
+type previewToken struct {
+	postId    uuid.UUID
+	expiresAt time.Time
+}
+
+type passwordReset struct {
+	userId    uuid.UUID
+	expiresAt time.Time
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:    map[uuid.UUID]*dto.UserDB{},
+		posts:    map[uuid.UUID]*dto.PostDB{},
+		images:   map[uuid.UUID]*dto.ImageDB{},
+		resets:   map[string]passwordReset{},
+		sessions: map[uuid.UUID]*dto.SessionDB{},
+		apiKeys:  map[uuid.UUID]*dto.ApiKeyDB{},
+		previews: map[string]previewToken{},
+		views:    map[dto.PostView]struct{}{},
+		likes:    map[postLike]int{},
+
+		idempotency: map[idempotencyKey]*dto.IdempotencyKeyDB{},
+	}
+}
+
+func (r *MemoryRepository) AddNewUser(ctx context.Context, email, password_hash, role string) (*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return nil, errors.ErrorRepositoryUserAlreadyExsist
+		}
+	}
+	if !validRole(types.Role(role)) {
+		return nil, errors.ErrorRepositoryBadRole
+	}
+
+	user := &dto.UserDB{
+		UserId:       uuid.New(),
+		Email:        email,
+		PasswordHash: password_hash,
+		Role:         types.Role(role),
+	}
+	r.users[user.UserId] = user
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetUserByEmail(ctx context.Context, email string) (*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) GetUserById(ctx context.Context, id uuid.UUID) (*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryRepository) VerifyEmail(ctx context.Context, id uuid.UUID, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.Email != email {
+		return sql.ErrNoRows
+	}
+	user.EmailVerified = true
+	return nil
+}
+
+func (r *MemoryRepository) GetUsers(ctx context.Context, limit, offset int) ([]*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := make([]*dto.UserDB, 0, len(r.users))
+	for _, user := range r.users {
+		copied := *user
+		users = append(users, &copied)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	users = users[offset:]
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *MemoryRepository) CountUsers(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users), nil
+}
+
+func (r *MemoryRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.users[id]; ok && user.PasswordHash == oldHash {
+		user.PasswordHash = newHash
+	}
+	return nil
+}
+
+func (r *MemoryRepository) UpdateUserRole(ctx context.Context, id uuid.UUID, role types.Role) (*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !validRole(role) {
+		return nil, errors.ErrorRepositoryBadRole
+	}
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	user.Role = role
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryRepository) UpdateDisplayName(ctx context.Context, id uuid.UUID, displayName *string) (*dto.UserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	user.DisplayName = displayName
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryRepository) SetAvatar(ctx context.Context, id uuid.UUID, avatarUrl string) (*string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	previous := user.AvatarUrl
+	user.AvatarUrl = &avatarUrl
+	return previous, nil
+}
+
+// DeleteUser removes the user like the foreign keys of users would. The deleted user the migrations add
+// is made here the first time posts are handed over to it.
+func (r *MemoryRepository) DeleteUser(ctx context.Context, id, heir uuid.UUID) (*dto.DeletedAccountDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	deleted := &dto.DeletedAccountDB{AvatarUrl: user.AvatarUrl}
+
+	if heir != uuid.Nil {
+		if _, ok := r.users[heir]; !ok && heir == dto.DeletedUserId {
+			name := "Deleted user"
+			r.users[heir] = &dto.UserDB{UserId: heir, Email: "deleted-user@invalid", Role: types.Author, DisplayName: &name}
+		}
+		for _, post := range r.posts {
+			if post.AuthorId == id {
+				post.AuthorId = heir
+			}
+		}
+		for _, revision := range r.revisions {
+			if revision.EditedBy == id {
+				revision.EditedBy = heir
+			}
+		}
+	}
+	for postId, post := range r.posts {
+		if post.AuthorId != id {
+			continue
+		}
+		for _, image := range r.images {
+			if image.PostId == postId {
+				deleted.ImageUrls = append(deleted.ImageUrls, image.ImageUrl)
+			}
+		}
+		r.dropPost(postId)
+	}
+
+	delete(r.users, id)
+	r.dropSessions(id)
+	for keyId, key := range r.apiKeys {
+		if key.UserId == id {
+			delete(r.apiKeys, keyId)
+		}
+	}
+	for token, reset := range r.resets {
+		if reset.userId == id {
+			delete(r.resets, token)
+		}
+	}
+	for key := range r.idempotency {
+		if key.userId == id {
+			delete(r.idempotency, key)
+		}
+	}
+	for view := range r.views {
+		if view.UserId == id {
+			delete(r.views, view)
+		}
+	}
+	for like := range r.likes {
+		if like.userId == id {
+			delete(r.likes, like)
+		}
+	}
+	r.revisions = slices.DeleteFunc(r.revisions, func(revision *dto.PostRevisionDB) bool {
+		return revision.EditedBy == id
+	})
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(sub *subscription) bool {
+		return sub.readerId == id || sub.authorId == id
+	})
+	r.notifications = slices.DeleteFunc(r.notifications, func(notification *dto.NotificationDB) bool {
+		return notification.UserId == id
+	})
+	r.reports = slices.DeleteFunc(r.reports, func(report *dto.ReportDB) bool {
+		return report.UserId == id
+	})
+	for _, report := range r.reports {
+		if report.ResolvedBy != nil && *report.ResolvedBy == id {
+			report.ResolvedBy = nil
+		}
+	}
+	for _, entry := range r.audit {
+		if entry.UserId != nil && *entry.UserId == id {
+			entry.UserId = nil
+		}
+	}
+	return deleted, nil
+}
+
+func (r *MemoryRepository) CreateSession(ctx context.Context, session *dto.SessionDB, max int) (*dto.SessionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	created := *session
+	created.SessionId = uuid.New()
+	created.CreatedAt = now
+	created.LastUsedAt = now
+	r.sessions[created.SessionId] = &created
+
+	if max > 0 {
+		for id, other := range r.sessions {
+			if other.UserId == session.UserId && !other.ExpiresAt.After(now) {
+				delete(r.sessions, id)
+			}
+		}
+		live := r.userSessions(session.UserId)
+		for _, other := range live[min(max, len(live)):] {
+			delete(r.sessions, other.SessionId)
+		}
+	}
+	copied := created
+	return &copied, nil
+}
+
+func (r *MemoryRepository) UseSession(ctx context.Context, tokenHash string) (*dto.SessionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash && session.ExpiresAt.After(time.Now()) {
+			session.LastUsedAt = time.Now()
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) GetSessions(ctx context.Context, userId uuid.UUID) ([]*dto.SessionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := r.userSessions(userId)
+	for i, session := range sessions {
+		copied := *session
+		sessions[i] = &copied
+	}
+	return sessions, nil
+}
+
+func (r *MemoryRepository) DeleteSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionId]
+	if !ok || session.UserId != userId {
+		return sql.ErrNoRows
+	}
+	delete(r.sessions, sessionId)
+	return nil
+}
+
+func (r *MemoryRepository) DeleteSessionByToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			delete(r.sessions, id)
+			return session.UserId, nil
+		}
+	}
+	return uuid.Nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) DeleteOtherSessions(ctx context.Context, userId, keep uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, session := range r.sessions {
+		if session.UserId == userId && id != keep {
+			delete(r.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// userSessions are the unexpired sessions of a user, the one used last first
+func (r *MemoryRepository) userSessions(userId uuid.UUID) []*dto.SessionDB {
+	var sessions []*dto.SessionDB
+	for _, session := range r.sessions {
+		if session.UserId == userId && session.ExpiresAt.After(time.Now()) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastUsedAt.Equal(sessions[j].LastUsedAt) {
+			return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+		}
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions
+}
+
+func (r *MemoryRepository) dropSessions(userId uuid.UUID) {
+	for id, session := range r.sessions {
+		if session.UserId == userId {
+			delete(r.sessions, id)
+		}
+	}
+}
+
+func (r *MemoryRepository) CreateApiKey(ctx context.Context, key *dto.ApiKeyDB) (*dto.ApiKeyDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *key
+	created.KeyId = uuid.New()
+	created.CreatedAt = time.Now()
+	created.Scopes = slices.Clone(key.Scopes)
+	r.apiKeys[created.KeyId] = &created
+	copied := created
+	return &copied, nil
+}
+
+func (r *MemoryRepository) UseApiKey(ctx context.Context, keyHash string) (*dto.ApiKeyDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.apiKeys {
+		if key.KeyHash == keyHash && (key.ExpiresAt == nil || key.ExpiresAt.After(time.Now())) {
+			now := time.Now()
+			key.LastUsedAt = &now
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) GetApiKeys(ctx context.Context, userId uuid.UUID) ([]*dto.ApiKeyDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := []*dto.ApiKeyDB{}
+	for _, key := range r.apiKeys {
+		if key.UserId == userId {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *MemoryRepository) DeleteApiKey(ctx context.Context, userId, keyId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.apiKeys[keyId]
+	if !ok || key.UserId != userId {
+		return sql.ErrNoRows
+	}
+	delete(r.apiKeys, keyId)
+	return nil
+}
+
+// validRole mirrors users_role_check
+func validRole(role types.Role) bool {
+	return role == types.Author || role == types.Reader || role == types.Admin
+}
+
+func (r *MemoryRepository) CreatePasswordReset(ctx context.Context, userId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resets[tokenHash] = passwordReset{userId, expiresAt}
+	return nil
+}
+
+func (r *MemoryRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reset, ok := r.resets[tokenHash]
+	if !ok || !reset.expiresAt.After(time.Now()) {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	user, ok := r.users[reset.userId]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	user.PasswordHash = passwordHash
+	r.dropSessions(reset.userId)
+	for hash, other := range r.resets {
+		if other.userId == reset.userId {
+			delete(r.resets, hash)
+		}
+	}
+	return reset.userId, nil
+}
+
+func (r *MemoryRepository) CreatePreviewToken(ctx context.Context, postId uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.posts[postId]; !ok {
+		return sql.ErrNoRows
+	}
+	for hash, preview := range r.previews {
+		if preview.postId == postId && !preview.expiresAt.After(time.Now()) {
+			delete(r.previews, hash)
+		}
+	}
+	r.previews[tokenHash] = previewToken{postId, expiresAt}
+	return nil
+}
+
+func (r *MemoryRepository) DeletePreviewTokens(ctx context.Context, postId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revoked := 0
+	for hash, preview := range r.previews {
+		if preview.postId == postId {
+			if preview.expiresAt.After(time.Now()) {
+				revoked++
+			}
+			delete(r.previews, hash)
+		}
+	}
+	return revoked, nil
+}
+
+func (r *MemoryRepository) GetPreviewPost(ctx context.Context, tokenHash string) (*dto.PostUserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	preview, ok := r.previews[tokenHash]
+	if !ok || !preview.expiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+	post, ok := r.posts[preview.postId]
+	if !ok || post.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.joinAuthor(post), nil
+}
+
+func (r *MemoryRepository) PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, session := range r.sessions {
+		if count == limit {
+			break
+		}
+		if session.ExpiresAt.Before(before) {
+			delete(r.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) PurgePasswordResets(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, reset := range r.resets {
+		if count == limit {
+			break
+		}
+		if reset.expiresAt.Before(before) {
+			delete(r.resets, hash)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) PurgePreviewTokens(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, preview := range r.previews {
+		if count == limit {
+			break
+		}
+		if preview.expiresAt.Before(before) {
+			delete(r.previews, hash)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, key := range r.idempotency {
+		if count == limit {
+			break
+		}
+		if key.ExpiresAt.Before(before) {
+			delete(r.idempotency, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) ClaimIdempotencyKey(ctx context.Context, key *dto.IdempotencyKeyDB, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{key.UserId, key.Key}
+	if held, ok := r.idempotency[id]; ok && held.ExpiresAt.After(now) {
+		return false, nil
+	}
+	r.idempotency[id] = &dto.IdempotencyKeyDB{UserId: key.UserId, Key: key.Key, Endpoint: key.Endpoint,
+		RequestHash: key.RequestHash, CreatedAt: now, ExpiresAt: key.ExpiresAt}
+	return true, nil
+}
+
+func (r *MemoryRepository) GetIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) (*dto.IdempotencyKeyDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	held, ok := r.idempotency[idempotencyKey{userId, key}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *held
+	return &copied, nil
+}
+
+func (r *MemoryRepository) CompleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if held, ok := r.idempotency[idempotencyKey{userId, key}]; ok {
+		held.Status, held.ContentType, held.ResponseBody = status, contentType, slices.Clone(body)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.idempotency, idempotencyKey{userId, key})
+	return nil
+}
+
+func (r *MemoryRepository) GetPostByIdempotencyKey(ctx context.Context, idempotencyKey string) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, post := range r.posts {
+		if post.IdempotencyKey == idempotencyKey {
+			copied := *post
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) CreatePost(ctx context.Context, authorId uuid.UUID, idempotencyKey, title, content, excerpt string, stats *dto.ReadingStats, tags []string) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, post := range r.posts {
+		if post.IdempotencyKey == idempotencyKey {
+			return nil, errors.ErrorRepositoryUserAlreadyExsist
+		}
+	}
+
+	now := time.Now()
+	post := &dto.PostDB{
+		PostId:         uuid.New(),
+		AuthorId:       authorId,
+		IdempotencyKey: idempotencyKey,
+		Title:          title,
+		Content:        content,
+		Excerpt:        storedExcerpt(excerpt),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Status:         types.Draft,
+		Tags:           sortedTags(tags),
+	}
+	if stats != nil {
+		counted := *stats
+		post.WordCount, post.ReadingTimeMinutes = &counted.WordCount, &counted.ReadingTimeMinutes
+	}
+	r.posts[post.PostId] = post
+	r.addPostEvent(types.EventPostCreated, post, "")
+	copied := *post
+	return &copied, nil
+}
+
+// storedExcerpt is the excerpt column as PostgresRepository writes it, an empty one is NULL
+func storedExcerpt(excerpt string) *string {
+	if excerpt == "" {
+		return nil
+	}
+	return &excerpt
+}
+
+// addPostEvent queues an event about the post like the outbox insert of PostgresRepository, callers hold mu
+func (r *MemoryRepository) addPostEvent(eventType types.EventType, post *dto.PostDB, previous types.PostStatus) {
+	payload := map[string]any{
+		"post_id":    post.PostId,
+		"author_id":  post.AuthorId,
+		"title":      post.Title,
+		"status":     post.Status,
+		"publish_at": post.PublishAt,
+	}
+	if previous != "" {
+		payload["previous_status"] = previous
+	}
+	data, _ := json.Marshal(payload)
+	r.outbox = append(r.outbox, &dto.OutboxEventDB{
+		EventId:   uuid.New(),
+		Seq:       int64(len(r.outbox) + 1),
+		PostId:    post.PostId,
+		Type:      eventType,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	})
+}
+
+// live returns a post that is not in the trash, callers hold mu
+func (r *MemoryRepository) live(id uuid.UUID) (*dto.PostDB, bool) {
+	post, ok := r.posts[id]
+	return post, ok && post.DeletedAt == nil
+}
+
+func (r *MemoryRepository) GetPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetPostWithAuthorById(ctx context.Context, id uuid.UUID) (*dto.PostUserDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return r.joinAuthor(post), nil
+}
+
+func (r *MemoryRepository) SchedulePost(ctx context.Context, id uuid.UUID, publishAt time.Time) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	previous := post.Status
+	post.Status = types.Scheduled
+	post.PublishAt = &publishAt
+	post.UpdatedAt = time.Now()
+	if previous != types.Scheduled {
+		r.addPostEvent(types.EventPostStatusChanged, post, previous)
+	}
+	copied := *post
+	return &copied, nil
+}
+
+// PublishDuePosts uses the process clock for a zero now, there is no database clock to defer to
+func (r *MemoryRepository) PublishDuePosts(ctx context.Context, now time.Time) ([]*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var published []*dto.PostDB
+	for _, post := range r.posts {
+		if post.Status == types.Scheduled && post.DeletedAt == nil && post.PublishAt != nil && !post.PublishAt.After(now) {
+			post.Status = types.Published
+			post.UpdatedAt = time.Now()
+			r.addPostEvent(types.EventPostStatusChanged, post, types.Scheduled)
+			copied := *post
+			published = append(published, &copied)
+		}
+	}
+	return published, nil
+}
+
+func (r *MemoryRepository) UpdatePost(ctx context.Context, id, editorId uuid.UUID, patch *dto.PostPatch, tags []string, expectedUpdatedAt *time.Time) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if status := patch.Status; status != nil && *status != types.Draft && *status != types.Published && *status != types.Archived && *status != types.Scheduled {
+		return nil, errors.ErrorRepositoryBadStatus
+	}
+	post, ok := r.live(id)
+	if !ok || expectedUpdatedAt != nil && !post.UpdatedAt.Equal(*expectedUpdatedAt) {
+		return nil, sql.ErrNoRows
+	}
+	r.revisions = append(r.revisions, &dto.PostRevisionDB{
+		RevisionId: uuid.New(),
+		PostId:     id,
+		Title:      post.Title,
+		Content:    post.Content,
+		Status:     post.Status,
+		EditedAt:   time.Now(),
+		EditedBy:   editorId,
+	})
+	if patch.Title != nil {
+		post.Title = *patch.Title
+	}
+	if patch.Content != nil {
+		post.Content = *patch.Content
+	}
+	if patch.Excerpt != nil {
+		post.Excerpt = storedExcerpt(*patch.Excerpt)
+	}
+	if patch.Stats != nil {
+		counted := *patch.Stats
+		post.WordCount, post.ReadingTimeMinutes = &counted.WordCount, &counted.ReadingTimeMinutes
+	}
+	previous := post.Status
+	if patch.Status != nil {
+		post.Status = *patch.Status
+	}
+	if tags != nil {
+		post.Tags = sortedTags(tags)
+	}
+	post.UpdatedAt = time.Now()
+	if post.Status != previous {
+		r.addPostEvent(types.EventPostStatusChanged, post, previous)
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *MemoryRepository) FillReadingStats(ctx context.Context, posts []*dto.PostDB) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, counted := range posts {
+		post, ok := r.posts[counted.PostId]
+		if !ok || post.WordCount != nil || !post.UpdatedAt.Equal(counted.UpdatedAt) {
+			continue
+		}
+		words, minutes := *counted.WordCount, *counted.ReadingTimeMinutes
+		post.WordCount, post.ReadingTimeMinutes = &words, &minutes
+	}
+	return nil
+}
+
+func (r *MemoryRepository) TrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.live(id)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	post.DeletedAt = &now
+	r.addPostEvent(types.EventPostDeleted, post, "")
+	copied := *post
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetTrashedPostById(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *MemoryRepository) UntrashPost(ctx context.Context, id uuid.UUID) (*dto.PostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	post.DeletedAt = nil
+	copied := *post
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetExpiredTrash(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []uuid.UUID
+	for _, post := range r.posts {
+		if post.DeletedAt != nil && post.DeletedAt.Before(before) {
+			ids = append(ids, post.PostId)
+		}
+	}
+	return ids, nil
+}
+
+// PurgePost drops the post with its images, views, likes, revisions and notifications like the ON DELETE CASCADE of them
+func (r *MemoryRepository) PurgePost(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.DeletedAt == nil {
+		return nil
+	}
+	r.dropPost(id)
+	return nil
+}
+
+// dropPost removes a post with what the foreign keys cascade to, r.mu is held
+func (r *MemoryRepository) dropPost(id uuid.UUID) {
+	delete(r.posts, id)
+	for imageId, image := range r.images {
+		if image.PostId == id {
+			delete(r.images, imageId)
+		}
+	}
+	for view := range r.views {
+		if view.PostId == id {
+			delete(r.views, view)
+		}
+	}
+	for like := range r.likes {
+		if like.postId == id {
+			delete(r.likes, like)
+		}
+	}
+	r.revisions = slices.DeleteFunc(r.revisions, func(revision *dto.PostRevisionDB) bool {
+		return revision.PostId == id
+	})
+	r.notifications = slices.DeleteFunc(r.notifications, func(notification *dto.NotificationDB) bool {
+		return notification.PostId == id
+	})
+	for token, preview := range r.previews {
+		if preview.postId == id {
+			delete(r.previews, token)
+		}
+	}
+	r.reports = slices.DeleteFunc(r.reports, func(report *dto.ReportDB) bool {
+		return report.PostId == id
+	})
+}
+
+// RecordViews skips repeats and unknown posts or users like the INSERT ... ON CONFLICT DO NOTHING
+func (r *MemoryRepository) RecordViews(ctx context.Context, views []dto.PostView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, view := range views {
+		if _, ok := r.posts[view.PostId]; !ok {
+			continue
+		}
+		if _, ok := r.users[view.UserId]; !ok {
+			continue
+		}
+		view.Day = view.Day.UTC().Truncate(24 * time.Hour)
+		r.views[view] = struct{}{}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetPostViewStats(ctx context.Context, postId uuid.UUID) ([]*dto.ViewDayDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[time.Time]int{}
+	for view := range r.views {
+		if view.PostId == postId {
+			counts[view.Day]++
+		}
+	}
+	var days []*dto.ViewDayDB
+	for day, views := range counts {
+		days = append(days, &dto.ViewDayDB{Day: day, Views: views})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Day.Before(days[j].Day)
+	})
+	return days, nil
+}
+
+func (r *MemoryRepository) LikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.posts[postId]; !ok {
+		return sql.ErrNoRows
+	}
+	like := postLike{postId, userId}
+	if _, ok := r.likes[like]; !ok {
+		r.likeSeq++
+		r.likes[like] = r.likeSeq
+	}
+	return nil
+}
+
+func (r *MemoryRepository) AddReport(ctx context.Context, postId, userId uuid.UUID, reason types.ReportReason, comment string) (*dto.ReportDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.posts[postId]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	for _, report := range r.reports {
+		if report.PostId == postId && report.UserId == userId {
+			copied := *report
+			return &copied, nil
+		}
+	}
+	report := &dto.ReportDB{ReportId: uuid.New(), PostId: postId, UserId: userId, Reason: reason, Comment: comment, CreatedAt: time.Now()}
+	r.reports = append(r.reports, report)
+	copied := *report
+	return &copied, nil
+}
+
+// reportedPosts groups the open reports of live posts like GetReportedPosts, callers hold mu
+func (r *MemoryRepository) reportedPosts() []*dto.ReportedPostDB {
+	byPost := map[uuid.UUID]*dto.ReportedPostDB{}
+	var posts []*dto.ReportedPostDB
+	// newest first, so the comments come out latest first
+	for i := len(r.reports) - 1; i >= 0; i-- {
+		report := r.reports[i]
+		post, ok := r.posts[report.PostId]
+		if report.ResolvedAt != nil || !ok || post.DeletedAt != nil {
+			continue
+		}
+		reported, ok := byPost[report.PostId]
+		if !ok {
+			reported = &dto.ReportedPostDB{PostId: post.PostId, Title: post.Title, AuthorId: post.AuthorId, Status: post.Status,
+				Comments: pq.StringArray{}, FirstReportedAt: report.CreatedAt, LastReportedAt: report.CreatedAt}
+			byPost[report.PostId] = reported
+			posts = append(posts, reported)
+		}
+		reported.Reports++
+		switch report.Reason {
+		case types.ReportSpam:
+			reported.Spam++
+		case types.ReportAbuse:
+			reported.Abuse++
+		case types.ReportCopyright:
+			reported.Copyright++
+		default:
+			reported.Other++
+		}
+		if report.Comment != "" {
+			reported.Comments = append(reported.Comments, report.Comment)
+		}
+		reported.FirstReportedAt = report.CreatedAt
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		if posts[i].Reports != posts[j].Reports {
+			return posts[i].Reports > posts[j].Reports
+		}
+		return posts[i].LastReportedAt.After(posts[j].LastReportedAt)
+	})
+	return posts
+}
+
+func (r *MemoryRepository) GetReportedPosts(ctx context.Context, limit, offset int) ([]*dto.ReportedPostDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	posts := r.reportedPosts()
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	return posts[offset:min(offset+limit, len(posts))], nil
+}
+
+func (r *MemoryRepository) CountReportedPosts(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.reportedPosts()), nil
+}
+
+func (r *MemoryRepository) CountOpenReports(ctx context.Context, postId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	open := 0
+	for _, report := range r.reports {
+		if report.PostId == postId && report.ResolvedAt == nil {
+			open++
+		}
+	}
+	return open, nil
+}
+
+func (r *MemoryRepository) ResolveReports(ctx context.Context, postId, adminId uuid.UUID, action types.ModerationAction) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	resolved := 0
+	for _, report := range r.reports {
+		if report.PostId == postId && report.ResolvedAt == nil {
+			report.ResolvedAt, report.ResolvedBy, report.Resolution = &now, &adminId, &action
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+func (r *MemoryRepository) UnlikePost(ctx context.Context, postId, userId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.likes, postLike{postId, userId})
+	return nil
+}
+
+func (r *MemoryRepository) CountPostLikes(ctx context.Context, postId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.countLikes(postId), nil
+}
+
+// countLikes callers hold mu
+func (r *MemoryRepository) countLikes(postId uuid.UUID) int {
+	count := 0
+	for like := range r.likes {
+		if like.postId == postId {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *MemoryRepository) GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	posts := r.selectPosts(func(post *dto.PostDB) bool {
+		_, liked := r.likes[postLike{post.PostId, userId}]
+		return liked && (post.Status == types.Published || post.AuthorId == userId) && hasTag(post, tag)
+	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.Slice(posts, func(i, j int) bool {
+		return r.likes[postLike{posts[i].PostId, userId}] > r.likes[postLike{posts[j].PostId, userId}]
+	})
+	return posts, nil
+}
+
+// GetUserLikes lists the likes of the user in the order they were made, the map keeps no time of them
+func (r *MemoryRepository) GetUserLikes(ctx context.Context, userId uuid.UUID) ([]*dto.ExportedLike, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var liked []postLike
+	for like := range r.likes {
+		if like.userId == userId {
+			liked = append(liked, like)
+		}
+	}
+	sort.Slice(liked, func(i, j int) bool { return r.likes[liked[i]] < r.likes[liked[j]] })
+	likes := make([]*dto.ExportedLike, len(liked))
+	for i, like := range liked {
+		likes[i] = &dto.ExportedLike{PostId: like.postId}
+	}
+	return likes, nil
+}
+
+func (r *MemoryRepository) GetPostRevisions(ctx context.Context, postId uuid.UUID, limit, offset int) ([]*dto.PostRevisionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var revisions []*dto.PostRevisionDB
+	for _, revision := range slices.Backward(r.revisions) {
+		if revision.PostId == postId {
+			copied := *revision
+			revisions = append(revisions, &copied)
+		}
+	}
+	if offset >= len(revisions) {
+		return nil, nil
+	}
+	return revisions[offset:min(offset+limit, len(revisions))], nil
+}
+
+func (r *MemoryRepository) CountPostRevisions(ctx context.Context, postId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, revision := range r.revisions {
+		if revision.PostId == postId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) GetPostRevision(ctx context.Context, postId, revisionId uuid.UUID) (*dto.PostRevisionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, revision := range r.revisions {
+		if revision.PostId == postId && revision.RevisionId == revisionId {
+			copied := *revision
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *MemoryRepository) CreateImage(ctx context.Context, imageId, postId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.images[imageId]; ok {
+		return nil, errors.ErrorRepositoryUserAlreadyExsist
+	}
+	now := time.Now()
+	image := &dto.ImageDB{
+		ImageId:   imageId,
+		PostId:    postId,
+		ImageUrl:  imageUrl,
+		Position:  len(r.postImages(postId)) + 1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		SizeBytes: size,
+	}
+	r.images[imageId] = image
+	copied := *image
+	return &copied, nil
+}
+
+func (r *MemoryRepository) DeleteImage(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	image, ok := r.images[imageId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	delete(r.images, imageId)
+	return image, nil
+}
+
+func (r *MemoryRepository) GetImageById(ctx context.Context, imageId uuid.UUID) (*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	image, ok := r.images[imageId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *image
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetExistingImageIds(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := []uuid.UUID{}
+	for _, id := range ids {
+		if _, ok := r.images[id]; ok {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+func (r *MemoryRepository) CountPostImages(ctx context.Context, postId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.postImages(postId)), nil
+}
+
+func (r *MemoryRepository) GetUserImageBytes(ctx context.Context, userId uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var used int64
+	for _, image := range r.images {
+		if post, ok := r.posts[image.PostId]; ok && post.AuthorId == userId {
+			used += image.SizeBytes
+		}
+	}
+	return used, nil
+}
+
+func (r *MemoryRepository) GetPostImages(ctx context.Context, postId uuid.UUID) ([]*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var images []*dto.ImageDB
+	for _, image := range r.postImages(postId) {
+		copied := *image
+		images = append(images, &copied)
+	}
+	return images, nil
+}
+
+func (r *MemoryRepository) UpdateImage(ctx context.Context, postId, imageId uuid.UUID, patch *dto.ImagePatch) (*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	images := r.postImages(postId)
+	at := slices.IndexFunc(images, func(image *dto.ImageDB) bool { return image.ImageId == imageId })
+	if at < 0 {
+		return nil, sql.ErrNoRows
+	}
+	image := images[at]
+	if patch.Position != nil {
+		images = slices.Insert(slices.Delete(images, at, at+1), min(*patch.Position, len(images))-1, image)
+		for i, image := range images {
+			image.Position = i + 1
+		}
+	}
+	if patch.Caption != nil {
+		image.Caption = nil
+		if *patch.Caption != "" {
+			caption := *patch.Caption
+			image.Caption = &caption
+		}
+	}
+	copied := *image
+	return &copied, nil
+}
+
+func (r *MemoryRepository) ReplaceImage(ctx context.Context, postId, imageId uuid.UUID, imageUrl string, size int64) (*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	image, ok := r.images[imageId]
+	if !ok || image.PostId != postId {
+		return nil, sql.ErrNoRows
+	}
+	image.ImageUrl, image.SizeBytes, image.UpdatedAt = imageUrl, size, time.Now()
+	copied := *image
+	return &copied, nil
+}
+
+func (r *MemoryRepository) ReorderImages(ctx context.Context, postId uuid.UUID, ids []uuid.UUID) ([]*dto.ImageDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	images := r.postImages(postId)
+	if len(ids) != len(images) {
+		return nil, errors.ErrorRepositoryImageOrder
+	}
+	for i, id := range ids {
+		image, ok := r.images[id]
+		if !ok || image.PostId != postId || slices.Contains(ids[:i], id) {
+			return nil, errors.ErrorRepositoryImageOrder
+		}
+	}
+
+	res := make([]*dto.ImageDB, len(ids))
+	for i, id := range ids {
+		r.images[id].Position = i + 1
+		copied := *r.images[id]
+		res[i] = &copied
+	}
+	return res, nil
+}
+
+// postImages returns the images of a post in their order like the ORDER BY of PostgresRepository, the caller holds mu
+func (r *MemoryRepository) postImages(postId uuid.UUID) []*dto.ImageDB {
+	var images []*dto.ImageDB
+	for _, image := range r.images {
+		if image.PostId == postId {
+			images = append(images, image)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		if !images[i].CreatedAt.Equal(images[j].CreatedAt) {
+			return images[i].CreatedAt.Before(images[j].CreatedAt)
+		}
+		return images[i].ImageId.String() < images[j].ImageId.String()
+	})
+	return images
+}
+
+func (r *MemoryRepository) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
+	return sortPosts(r.selectPosts(func(post *dto.PostDB) bool {
+		return post.Status == types.Published && hasTag(post, opts.Tag)
+	}), opts), nil
+}
+
+// GetTrendingPosts scores the views of the window by their day, a like is always recent here as the
+// memory keeps no time for it
+func (r *MemoryRepository) GetTrendingPosts(ctx context.Context, window time.Duration, likeWeight float64, limit int) ([]*dto.PostTrendingDB, error) {
+	posts := sortPosts(r.selectPosts(func(post *dto.PostDB) bool {
+		return post.Status == types.Published
+	}), dto.PostListOptions{})
+
+	r.mu.Lock()
+	scores := map[uuid.UUID]float64{}
+	now := time.Now()
+	for view := range r.views {
+		if age := now.Sub(view.Day); age < window {
+			scores[view.PostId] += 1 - age.Seconds()/window.Seconds()
+		}
+	}
+	for like := range r.likes {
+		scores[like.postId] += likeWeight
+	}
+	r.mu.Unlock()
+
+	trending := make([]*dto.PostTrendingDB, len(posts))
+	for i, post := range posts {
+		trending[i] = &dto.PostTrendingDB{PostUserDB: *post, Score: scores[post.PostId]}
+	}
+	sort.SliceStable(trending, func(i, j int) bool { return trending[i].Score > trending[j].Score })
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+func (r *MemoryRepository) GetUserPosts(ctx context.Context, userId uuid.UUID, opts dto.PostListOptions) ([]*dto.PostUserDB, error) {
+	return sortPosts(r.selectPosts(func(post *dto.PostDB) bool {
+		return post.AuthorId == userId && hasTag(post, opts.Tag) && (opts.Status == "" || post.Status == opts.Status)
+	}), opts), nil
+}
+
+func (r *MemoryRepository) ExportAuthorPosts(ctx context.Context, authorId uuid.UUID, fn func(*dto.PostDB, []*dto.ImageDB) error) error {
+	posts := sortPosts(r.selectPosts(func(post *dto.PostDB) bool {
+		return post.AuthorId == authorId
+	}), dto.PostListOptions{Order: types.Asc})
+
+	for _, post := range posts {
+		r.mu.Lock()
+		var images []*dto.ImageDB
+		for _, image := range r.postImages(post.PostId) {
+			copied := *image
+			images = append(images, &copied)
+		}
+		r.mu.Unlock()
+
+		if err := fn(&post.PostDB, images); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) HasImportedPost(ctx context.Context, authorId uuid.UUID, hash, title, content string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, post := range r.posts {
+		if post.AuthorId != authorId || post.DeletedAt != nil {
+			continue
+		}
+		if (post.ImportHash != nil && *post.ImportHash == hash) || (post.Title == title && post.Content == content) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryRepository) ImportPost(ctx context.Context, authorId uuid.UUID, imported *dto.ImportedPost) (*dto.PostDB, error) {
+	post, err := r.CreatePost(ctx, authorId, imported.IdempotencyKey, imported.Title, imported.Content, imported.Excerpt, imported.Stats, imported.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hash := imported.Hash
+	r.posts[post.PostId].ImportHash = &hash
+	post.ImportHash = &hash
+	now := time.Now()
+	for i, image := range imported.Images {
+		r.images[image.ImageId] = &dto.ImageDB{
+			ImageId:   image.ImageId,
+			PostId:    post.PostId,
+			ImageUrl:  image.ImageUrl,
+			Position:  i + 1,
+			Caption:   image.Caption,
+			SizeBytes: image.SizeBytes,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return post, nil
+}
+
+// sortPosts orders a listing like orderPosts of PostgresRepository, newest first unless opts say otherwise
+func sortPosts(posts []*dto.PostUserDB, opts dto.PostListOptions) []*dto.PostUserDB {
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		if opts.Order == types.Asc {
+			a, b = b, a
+		}
+		switch opts.Sort {
+		case types.SortTitle:
+			if a.Title != b.Title {
+				return a.Title > b.Title
+			}
+		case types.SortUpdatedAt:
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.After(b.UpdatedAt)
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+		}
+		return a.PostId.String() > b.PostId.String()
+	})
+	return posts
+}
+
+func (r *MemoryRepository) GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.PostUserDB, error) {
+	return r.selectPosts(func(post *dto.PostDB) bool {
+		return post.AuthorId == authorId && post.Status == types.Published && hasTag(post, tag)
+	}), nil
+}
+
+func (r *MemoryRepository) GetAuthors(ctx context.Context) ([]*dto.AuthorDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[uuid.UUID]int{}
+	for _, post := range r.posts {
+		if post.Status == types.Published && post.DeletedAt == nil {
+			counts[post.AuthorId]++
+		}
+	}
+	authors := []*dto.AuthorDB{}
+	for userId, count := range counts {
+		if user, ok := r.users[userId]; ok && user.Role == types.Author {
+			authors = append(authors, authorOf(user, count))
+		}
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].PostsCount != authors[j].PostsCount {
+			return authors[i].PostsCount > authors[j].PostsCount
+		}
+		return authors[i].Email < authors[j].Email
+	})
+	return authors, nil
+}
+
+func (r *MemoryRepository) GetAuthor(ctx context.Context, id uuid.UUID) (*dto.AuthorDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.Role != types.Author {
+		return nil, sql.ErrNoRows
+	}
+	count := 0
+	for _, post := range r.posts {
+		if post.AuthorId == id && post.Status == types.Published && post.DeletedAt == nil {
+			count++
+		}
+	}
+	return authorOf(user, count), nil
+}
+
+func authorOf(user *dto.UserDB, postsCount int) *dto.AuthorDB {
+	return &dto.AuthorDB{
+		UserId:      user.UserId,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		AvatarUrl:   user.AvatarUrl,
+		PostsCount:  postsCount,
+	}
+}
+
+func (r *MemoryRepository) GetTags(ctx context.Context) ([]*dto.TagDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, post := range r.posts {
+		if post.Status != types.Published || post.DeletedAt != nil {
+			continue
+		}
+		for _, tag := range post.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]*dto.TagDB, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, &dto.TagDB{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+func (r *MemoryRepository) SearchPublishedPosts(ctx context.Context, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	return r.searchPosts(query, limit, offset, func(post *dto.PostDB) bool {
+		return post.Status == types.Published
+	}), nil
+}
+
+func (r *MemoryRepository) SearchPostsWithAuthor(ctx context.Context, authorId uuid.UUID, query string, limit, offset int) ([]*dto.PostSearchDB, error) {
+	return r.searchPosts(query, limit, offset, func(post *dto.PostDB) bool {
+		return post.Status == types.Published || post.AuthorId == authorId
+	}), nil
+}
+
+// searchPosts approximates plainto_tsquery with the simple config: every word of the query must appear,
+// the rank is the number of matching words in the post
+func (r *MemoryRepository) searchPosts(query string, limit, offset int, match func(*dto.PostDB) bool) []*dto.PostSearchDB {
+	terms := searchWords(query)
+	var found []*dto.PostSearchDB
+	for _, post := range r.selectPosts(match) {
+		words := searchWords(post.Title + " " + post.Content)
+		rank := 0
+		for _, term := range terms {
+			count := 0
+			for _, word := range words {
+				if word == term {
+					count++
+				}
+			}
+			if count == 0 {
+				rank = 0
+				break
+			}
+			rank += count
+		}
+		if rank > 0 {
+			found = append(found, &dto.PostSearchDB{PostUserDB: *post, Rank: float64(rank)})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Rank != found[j].Rank {
+			return found[i].Rank > found[j].Rank
+		}
+		return found[i].CreatedAt.After(found[j].CreatedAt)
+	})
+
+	if offset >= len(found) {
+		return nil
+	}
+	found = found[offset:]
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// hasTag mirrors the tag filter of PostgresRepository, an empty tag matches every post
+func hasTag(post *dto.PostDB, tag string) bool {
+	return tag == "" || slices.Contains(post.Tags, tag)
+}
+
+func sortedTags(tags []string) pq.StringArray {
+	if len(tags) == 0 {
+		return nil
+	}
+	return slices.Sorted(slices.Values(tags))
+}
+
+func (r *MemoryRepository) selectPosts(match func(*dto.PostDB) bool) []*dto.PostUserDB {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var posts []*dto.PostUserDB
+	for _, post := range r.posts {
+		if post.DeletedAt != nil || !match(post) {
+			continue
+		}
+		posts = append(posts, r.joinAuthor(post))
+	}
+	return posts
+}
+
+// joinAuthor copies the post with its author and view count, callers hold mu
+func (r *MemoryRepository) joinAuthor(post *dto.PostDB) *dto.PostUserDB {
+	joined := &dto.PostUserDB{PostDB: *post}
+	if user, ok := r.users[post.AuthorId]; ok {
+		joined.UserDB = *user
+	}
+	for view := range r.views {
+		if view.PostId == post.PostId {
+			joined.Views++
+		}
+	}
+	joined.LikesCount = r.countLikes(post.PostId)
+	return joined
+}
+
+// MemoryStorage keeps uploaded objects in memory, stamped with the time of clock
+type MemoryStorage struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	modified map[string]time.Time
+	clock    func() time.Time
+}
+
+func NewMemoryStorage(clock func() time.Time) *MemoryStorage {
+	return &MemoryStorage{objects: map[string][]byte{}, modified: map[string]time.Time{}, clock: clock}
+}
+
+func (s *MemoryStorage) PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, file); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[objectName] = buf.Bytes()
+	s.modified[objectName] = s.clock()
+	return fmt.Sprintf("/images/%s", objectName), nil
+}
+
+func (s *MemoryStorage) DeleteImage(ctx context.Context, objectName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, objectName)
+	delete(s.modified, objectName)
+	return nil
+}
+
+func (s *MemoryStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[objectName]
+	if !ok {
+		return nil, "", os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), "application/octet-stream", nil
+}
+
+func (s *MemoryStorage) ImageExists(objectName string) (bool, error) {
+	_, ok := s.Object(objectName)
+	return ok, nil
+}
+
+// Public is true, the harness stores links as they are
+func (s *MemoryStorage) Public() bool {
+	return true
+}
+
+func (s *MemoryStorage) PresignGet(objectName string, ttl time.Duration) (string, error) {
+	return fmt.Sprintf("/images/%s?ttl=%s", objectName, ttl), nil
+}
+
+// ListImages lists the objects by name, emit runs without the lock held
+func (s *MemoryStorage) ListImages(ctx context.Context, emit func(storage.ImageObject) error) error {
+	s.mu.Lock()
+	objects := make([]storage.ImageObject, 0, len(s.objects))
+	for name := range s.objects {
+		objects = append(objects, storage.ImageObject{Name: name, LastModified: s.modified[name]})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	for _, obj := range objects {
+		if err := emit(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Object returns a stored object and whether it exists
+func (s *MemoryStorage) Object(objectName string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[objectName]
+	return data, ok
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MemoryMailer records sent mails, Mails delivers them as they are sent
+type MemoryMailer struct {
+	Mails chan Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{Mails: make(chan Mail, 16)}
+}
+
+func (m *MemoryMailer) Send(to, subject, body string) error {
+	m.Mails <- Mail{to, subject, body}
+	return nil
+}
+
+func (r *MemoryRepository) AddAuditEntry(ctx context.Context, entry *dto.AuditEntryDB) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *entry
+	copied.AuditId = uuid.New()
+	copied.CreatedAt = time.Now()
+	r.audit = append(r.audit, &copied)
+	return nil
+}
+
+func auditMatches(entry *dto.AuditEntryDB, filter dto.AuditFilter) bool {
+	if filter.UserId != nil && (entry.UserId == nil || *entry.UserId != *filter.UserId) {
+		return false
+	}
+	return filter.Action == "" || entry.Action == filter.Action
+}
+
+func (r *MemoryRepository) GetAuditEntries(ctx context.Context, filter dto.AuditFilter, limit, offset int) ([]*dto.AuditEntryDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []*dto.AuditEntryDB
+	for _, entry := range slices.Backward(r.audit) {
+		if auditMatches(entry, filter) {
+			copied := *entry
+			entries = append(entries, &copied)
+		}
+	}
+	if offset >= len(entries) {
+		return nil, nil
+	}
+	return entries[offset:min(offset+limit, len(entries))], nil
+}
+
+func (r *MemoryRepository) CountAuditEntries(ctx context.Context, filter dto.AuditFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, entry := range r.audit {
+		if auditMatches(entry, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) GetPendingEvents(ctx context.Context, limit int) ([]*dto.OutboxEventDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []*dto.OutboxEventDB
+	for _, event := range r.outbox {
+		if event.SentAt == nil && len(pending) < limit {
+			copied := *event
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (r *MemoryRepository) MarkEventsSent(ctx context.Context, ids []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range r.outbox {
+		if slices.Contains(ids, event.EventId) {
+			event.SentAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) Subscribe(ctx context.Context, readerId, authorId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[readerId]; !ok {
+		return sql.ErrNoRows
+	}
+	if _, ok := r.users[authorId]; !ok {
+		return sql.ErrNoRows
+	}
+	if readerId == authorId {
+		return errors.ErrorServiceSelfSubscription
+	}
+	if r.subscribed(readerId, authorId) {
+		return nil
+	}
+	r.subscriptions = append(r.subscriptions, &subscription{readerId, authorId, time.Now()})
+	return nil
+}
+
+// subscribed callers hold mu
+func (r *MemoryRepository) subscribed(readerId, authorId uuid.UUID) bool {
+	return slices.ContainsFunc(r.subscriptions, func(s *subscription) bool {
+		return s.readerId == readerId && s.authorId == authorId
+	})
+}
+
+func (r *MemoryRepository) Unsubscribe(ctx context.Context, readerId, authorId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s *subscription) bool {
+		return s.readerId == readerId && s.authorId == authorId
+	})
+	return nil
+}
+
+func (r *MemoryRepository) CountSubscribers(ctx context.Context, authorId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, s := range r.subscriptions {
+		if s.authorId == authorId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) GetSubscriptions(ctx context.Context, readerId uuid.UUID, limit, offset int) ([]*dto.SubscriptionDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []*dto.SubscriptionDB
+	for _, s := range slices.Backward(r.subscriptions) {
+		if s.readerId == readerId {
+			found = append(found, &dto.SubscriptionDB{
+				AuthorId:    s.authorId,
+				Email:       r.users[s.authorId].Email,
+				DisplayName: r.users[s.authorId].DisplayName,
+				CreatedAt:   s.createdAt,
+			})
+		}
+	}
+
+	if offset >= len(found) {
+		return nil, nil
+	}
+	found = found[offset:]
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *MemoryRepository) CountSubscriptions(ctx context.Context, readerId uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, s := range r.subscriptions {
+		if s.readerId == readerId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// NotifySubscribers skips posts that are not published and subscribers notified before, like the
+// INSERT ... ON CONFLICT DO NOTHING
+func (r *MemoryRepository) NotifySubscribers(ctx context.Context, postId uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[postId]
+	if !ok || post.Status != types.Published || post.DeletedAt != nil {
+		return 0, nil
+	}
+	var count int64
+	for _, s := range r.subscriptions {
+		if s.authorId != post.AuthorId {
+			continue
+		}
+		notified := slices.ContainsFunc(r.notifications, func(n *dto.NotificationDB) bool {
+			return n.UserId == s.readerId && n.PostId == postId
+		})
+		if notified {
+			continue
+		}
+		r.notifications = append(r.notifications, &dto.NotificationDB{
+			NotificationId: uuid.New(),
+			UserId:         s.readerId,
+			PostId:         postId,
+			Type:           types.NotificationPostPublished,
+			CreatedAt:      time.Now(),
+		})
+		count++
+	}
+	return count, nil
+}
+
+func (r *MemoryRepository) GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) ([]*dto.NotificationDB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []*dto.NotificationDB
+	for _, n := range slices.Backward(r.notifications) {
+		if n.UserId != userId || (unread && n.ReadAt != nil) {
+			continue
+		}
+		copied := *n
+		post := r.posts[n.PostId]
+		copied.Title = post.Title
+		copied.AuthorId = post.AuthorId
+		copied.AuthorEmail = r.users[post.AuthorId].Email
+		copied.AuthorDisplayName = r.users[post.AuthorId].DisplayName
+		found = append(found, &copied)
+	}
+
+	if offset >= len(found) {
+		return nil, nil
+	}
+	found = found[offset:]
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *MemoryRepository) CountNotifications(ctx context.Context, userId uuid.UUID) (total, unread int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range r.notifications {
+		if n.UserId != userId {
+			continue
+		}
+		total++
+		if n.ReadAt == nil {
+			unread++
+		}
+	}
+	return total, unread, nil
+}
+
+func (r *MemoryRepository) MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var count int64
+	for _, n := range r.notifications {
+		if n.UserId != userId || n.ReadAt != nil || (len(ids) > 0 && !slices.Contains(ids, n.NotificationId)) {
+			continue
+		}
+		n.ReadAt = &now
+		count++
+	}
+	return count, nil
+}
+
+// MemoryEventSink records published events in order
+type MemoryEventSink struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (s *MemoryEventSink) Publish(ctx context.Context, event events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Published returns the events published so far
+func (s *MemoryEventSink) Published() []events.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.events)
+}
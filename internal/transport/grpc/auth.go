@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/grpc/blogpb"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
+	"github.com/xkarasb/blog/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authServicePrefix starts the methods callable without a token, they are what hands tokens out
+var authServicePrefix = "/" + blogpb.AuthService_ServiceDesc.ServiceName + "/"
+
+// authInterceptor lets through calls with a valid bearer token in the authorization metadata and
+// stores their user in the context under the key AuthMiddleware uses, so both transports look alike below
+func authInterceptor(service AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, authServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		var header string
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			header = values[0]
+		}
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrorHttpNoAuth.Error())
+		}
+
+		user, err := service.AuthorizeUser(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrorHttpInvalidToken.Error())
+		}
+
+		slogctx.AddAttrs(ctx, slog.String("user_id", user.UserId.String()))
+		return handler(context.WithValue(ctx, types.CtxUser, user), req)
+	}
+}
+
+// currentUser returns the user authInterceptor stored, a call without one means the method was not covered by it
+func currentUser(ctx context.Context) (*dto.UserDB, error) {
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		slogctx.Logger(ctx).Error("no user in call context")
+		return nil, status.Error(codes.Internal, errors.ErrorHttpInternal.Error())
+	}
+	return user, nil
+}
+
+type authServer struct {
+	blogpb.UnimplementedAuthServiceServer
+	service AuthService
+}
+
+func (s *authServer) Login(ctx context.Context, req *blogpb.LoginRequest) (*blogpb.LoginResponse, error) {
+	login := &dto.LoginUserRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+	if err := utils.Validate(login); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp, err := s.service.LoginUser(ctx, login)
+	if err != nil {
+		if err == errors.ErrorServiceInvalidCredentials {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrorHttpIncorrectEmail.Error())
+		}
+		return nil, serviceError(ctx, err)
+	}
+	return &blogpb.LoginResponse{
+		UserId:       resp.Id.String(),
+		AccessToken:  resp.AccessToken,
+		ExpiresIn:    resp.ExpiresIn,
+		RefreshToken: resp.RefreshToken,
+	}, nil
+}
+
+func (s *authServer) Refresh(ctx context.Context, req *blogpb.RefreshRequest) (*blogpb.RefreshResponse, error) {
+	refresh := &dto.RefreshRequest{RefreshToken: req.GetRefreshToken()}
+	if err := utils.Validate(refresh); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp, err := s.service.RefreshToken(ctx, refresh)
+	if err != nil {
+		if err == errors.ErrorInvalidToken {
+			return nil, status.Error(codes.Unauthenticated, errors.ErrorHttpBadRefresh.Error())
+		}
+		return nil, serviceError(ctx, err)
+	}
+	return &blogpb.RefreshResponse{AccessToken: resp.AccessToken, ExpiresIn: resp.ExpiresIn}, nil
+}
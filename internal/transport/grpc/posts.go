@@ -0,0 +1,207 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/grpc/blogpb"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+	"github.com/xkarasb/blog/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type readerServer struct {
+	blogpb.UnimplementedReaderServiceServer
+	service ReaderService
+}
+
+// ListPublishedPosts lists the published posts of every author like GET /posts does for readers
+func (s *readerServer) ListPublishedPosts(ctx context.Context, req *blogpb.ListPublishedPostsRequest) (*blogpb.ListPublishedPostsResponse, error) {
+	opts := dto.PostListOptions{Tag: strings.ToLower(strings.TrimSpace(req.GetTag())), Sort: types.SortCreatedAt, Order: types.Desc}
+	if req.GetSort() != "" {
+		opts.Sort = types.PostSort(req.GetSort())
+		if opts.Sort != types.SortCreatedAt && opts.Sort != types.SortUpdatedAt && opts.Sort != types.SortTitle {
+			return nil, status.Error(codes.InvalidArgument, "sort must be one of created_at, updated_at, title")
+		}
+	}
+	if req.GetOrder() != "" {
+		opts.Order = types.SortOrder(req.GetOrder())
+		if opts.Order != types.Asc && opts.Order != types.Desc {
+			return nil, status.Error(codes.InvalidArgument, "order must be asc or desc")
+		}
+	}
+
+	posts, err := s.service.GetPublishedPosts(ctx, opts)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	resp := &blogpb.ListPublishedPostsResponse{Posts: make([]*blogpb.Post, 0, len(posts))}
+	for _, post := range posts {
+		resp.Posts = append(resp.Posts, postMessage(post))
+	}
+	return resp, nil
+}
+
+// GetPost returns a published post or any post of the caller, a read of someone else's post counts as a view
+func (s *readerServer) GetPost(ctx context.Context, req *blogpb.GetPostRequest) (*blogpb.Post, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	postId, err := uuid.Parse(req.GetPostId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+	}
+
+	post, err := s.service.GetPost(ctx, user.UserId, postId)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return postMessage(post), nil
+}
+
+type posterServer struct {
+	blogpb.UnimplementedPosterServiceServer
+	service PosterService
+}
+
+// EditPost replaces title, content and tags of a post of the calling author
+func (s *posterServer) EditPost(ctx context.Context, req *blogpb.EditPostRequest) (*blogpb.PostDetails, error) {
+	user, err := authorOnly(ctx)
+	if err != nil {
+		return nil, err
+	}
+	edit := &dto.EditPostRequest{
+		Title:             req.GetTitle(),
+		Content:           req.GetContent(),
+		Tags:              utils.NormalizeTags(req.GetTags()),
+		ExpectedUpdatedAt: timeValue(req.GetExpectedUpdatedAt()),
+	}
+	if err := utils.Validate(edit); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	postId, err := uuid.Parse(req.GetPostId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+	}
+
+	post, err := s.service.EditPost(ctx, user.UserId, postId, edit)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.ErrorServiceIncorrectData:
+			return nil, status.Error(codes.InvalidArgument, errors.ErrorHttpIncorrectStatus.Error())
+		default:
+			return nil, serviceError(ctx, err)
+		}
+	}
+	return &blogpb.PostDetails{
+		PostId:         post.PostId.String(),
+		AuthorId:       post.AuthorId.String(),
+		IdempotencyKey: post.IdempotencyKey,
+		Title:          post.Title,
+		Content:        post.Content,
+		Status:         string(post.Status),
+		Tags:           post.Tags,
+		CreatedAt:      timestamppb.New(post.CreatedAt),
+		UpdatedAt:      timestamppb.New(post.UpdatedAt),
+	}, nil
+}
+
+// PublishPost changes the status of a post of the calling author, scheduled needs publish_at
+func (s *posterServer) PublishPost(ctx context.Context, req *blogpb.PublishPostRequest) (*blogpb.PublishPostResponse, error) {
+	user, err := authorOnly(ctx)
+	if err != nil {
+		return nil, err
+	}
+	publish := &dto.PublishPostRequest{
+		Status:    types.PostStatus(req.GetStatus()),
+		PublishAt: timeValue(req.GetPublishAt()),
+	}
+	if err := utils.Validate(publish); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	postId, err := uuid.Parse(req.GetPostId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+	}
+
+	resp, err := s.service.PublishPost(ctx, user.UserId, postId, publish)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceIncorrectData, errors.ErrorRepositoryBadStatus:
+			return nil, status.Error(codes.InvalidArgument, errors.ErrorHttpIncorrectStatus.Error())
+		case errors.ErrorEmailNotVerified:
+			return nil, status.Error(codes.PermissionDenied, errors.ErrorHttpEmailNotVerified.Error())
+		default:
+			return nil, serviceError(ctx, err)
+		}
+	}
+	return &blogpb.PublishPostResponse{
+		PostId:    resp.PostId.String(),
+		Status:    string(resp.Status),
+		PublishAt: timestamp(resp.PublishAt),
+	}, nil
+}
+
+// authorOnly is the RequireRole(types.Author) of the poster calls
+func authorOnly(ctx context.Context) (*dto.UserDB, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != types.Author {
+		return nil, status.Error(codes.PermissionDenied, errors.ErrorHttpAccessDenied.Error())
+	}
+	return user, nil
+}
+
+func postMessage(post *dto.GetPostResponse) *blogpb.Post {
+	msg := &blogpb.Post{
+		PostId: post.PostId.String(),
+		Author: &blogpb.User{
+			UserId:      post.Author.UserId.String(),
+			Email:       post.Author.Email,
+			Role:        string(post.Author.Role),
+			DisplayName: post.Author.DisplayName,
+			AvatarUrl:   post.Author.AvatarUrl,
+		},
+		Title:      post.Title,
+		Content:    post.Content,
+		Status:     string(post.Status),
+		Images:     make([]*blogpb.Image, 0, len(post.Images)),
+		Tags:       post.Tags,
+		Views:      int64(post.Views),
+		LikesCount: int64(post.LikesCount),
+		PublishAt:  timestamp(post.PublishAt),
+		CreatedAt:  timestamppb.New(post.CreatedAt),
+		UpdatedAt:  timestamppb.New(post.UpdatedAt),
+	}
+	for _, image := range post.Images {
+		msg.Images = append(msg.Images, &blogpb.Image{ImageId: image.ImageId.String(), ImageUrl: image.ImageUrl})
+	}
+	return msg
+}
+
+// timestamp leaves an unset time unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeValue(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
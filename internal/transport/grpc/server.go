@@ -0,0 +1,94 @@
+// Package grpc serves the auth, reader and poster services over gRPC for internal consumers,
+// next to the HTTP api and on top of the same service layer.
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/grpc/blogpb"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthService interface {
+	LoginUser(ctx context.Context, user *dto.LoginUserRequest) (*dto.LoginUserResponse, error)
+	RefreshToken(ctx context.Context, token *dto.RefreshRequest) (*dto.RefreshResponse, error)
+	AuthorizeUser(ctx context.Context, token string) (*dto.UserDB, error)
+}
+
+type ReaderService interface {
+	GetPost(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostResponse, error)
+	GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.GetPostResponse, error)
+}
+
+type PosterService interface {
+	EditPost(ctx context.Context, userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error)
+	PublishPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error)
+}
+
+// NewServer registers the three services, every call but those of AuthService needs an access token.
+// A call runs at most requestTimeout, zero for no limit.
+func NewServer(auth AuthService, reader ReaderService, poster PosterService, requestTimeout time.Duration) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(loggingInterceptor, timeoutInterceptor(requestTimeout), authInterceptor(auth)))
+	blogpb.RegisterAuthServiceServer(server, &authServer{service: auth})
+	blogpb.RegisterReaderServiceServer(server, &readerServer{service: reader})
+	blogpb.RegisterPosterServiceServer(server, &posterServer{service: poster})
+	return server
+}
+
+// loggingInterceptor writes a record per call like the Logger middleware does per HTTP request,
+// with the user_id authInterceptor found
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx = slogctx.WithAttrs(ctx)
+	resp, err := handler(ctx, req)
+	slogctx.Logger(ctx).LogAttrs(ctx, slog.LevelInfo, "grpc request",
+		slog.String("method", info.FullMethod),
+		slog.String("code", status.Code(err).String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	)
+	return resp, err
+}
+
+// timeoutInterceptor gives the call the deadline REQUEST_TIMEOUT gives an HTTP request, unless the client set a shorter one
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// serviceError turns an error the handler does not map itself into a status, the raw error is
+// logged but not shown like the HTTP api does
+func serviceError(ctx context.Context, err error) error {
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		slogctx.Logger(ctx).Warn("request timed out", slog.String("error", err.Error()))
+		return status.Error(codes.DeadlineExceeded, errors.ErrorHttpRequestTimeout.Error())
+	case errors.Is(err, errors.ErrorRepositoryStorageTimeout):
+		slogctx.Logger(ctx).Warn("image storage", slog.String("error", err.Error()))
+		return status.Error(codes.Unavailable, errors.ErrorHttpStorageTimeout.Error())
+	case errors.Is(err, errors.ErrorRepositoryQueryTimeout):
+		slogctx.Logger(ctx).Warn("database query", slog.String("error", err.Error()))
+		return status.Error(codes.DeadlineExceeded, errors.ErrorHttpQueryTimeout.Error())
+	case err == sql.ErrNoRows:
+		return status.Error(codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+	case err == errors.ErrorServiceNoAccess:
+		return status.Error(codes.PermissionDenied, errors.ErrorHttpAccessDenied.Error())
+	default:
+		slogctx.Logger(ctx).Error("grpc call failed", slog.String("error", err.Error()))
+		return status.Error(codes.Internal, errors.ErrorHttpInternal.Error())
+	}
+}
@@ -0,0 +1,317 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/grpc/blogpb"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) LoginUser(ctx context.Context, user *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
+	args := m.Called(user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoginUserResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, token *dto.RefreshRequest) (*dto.RefreshResponse, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RefreshResponse), args.Error(1)
+}
+
+func (m *MockAuthService) AuthorizeUser(ctx context.Context, token string) (*dto.UserDB, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UserDB), args.Error(1)
+}
+
+type MockReaderService struct {
+	mock.Mock
+}
+
+func (m *MockReaderService) GetPost(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.GetPostResponse, error) {
+	args := m.Called(opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.GetPostResponse), args.Error(1)
+}
+
+type MockPosterService struct {
+	mock.Mock
+}
+
+func (m *MockPosterService) EditPost(ctx context.Context, userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error) {
+	args := m.Called(userId, postId, post)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EditPostResponse), args.Error(1)
+}
+
+func (m *MockPosterService) PublishPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error) {
+	args := m.Called(userId, postId, post)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PublishPostResponse), args.Error(1)
+}
+
+var (
+	author = &dto.UserDB{UserId: uuid.New(), Email: "author@example.com", Role: types.Author}
+	reader = &dto.UserDB{UserId: uuid.New(), Email: "reader@example.com", Role: types.Reader}
+)
+
+// services are the mocks behind a server of dial, the tokens "author" and "reader" are valid
+type services struct {
+	auth   *MockAuthService
+	reader *MockReaderService
+	poster *MockPosterService
+}
+
+// dial serves the mocks over bufconn and returns a connection to them
+func dial(t *testing.T) (*grpc.ClientConn, services) {
+	t.Helper()
+	s := services{&MockAuthService{}, &MockReaderService{}, &MockPosterService{}}
+	s.auth.On("AuthorizeUser", "author").Return(author, nil)
+	s.auth.On("AuthorizeUser", "reader").Return(reader, nil)
+	s.auth.On("AuthorizeUser", mock.Anything).Return(nil, errors.ErrorInvalidToken)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(s.auth, s.reader, s.poster, time.Minute)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, s
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertCode(t *testing.T, err error, code codes.Code, message string) {
+	t.Helper()
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, code, st.Code())
+	assert.Contains(t, st.Message(), message)
+}
+
+func TestAuthService_Login(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewAuthServiceClient(conn)
+	userId := uuid.New()
+	s.auth.On("LoginUser", &dto.LoginUserRequest{Email: "author@example.com", Password: "Password123!"}).
+		Return(&dto.LoginUserResponse{Id: userId, AccessToken: "access", ExpiresIn: 7200, RefreshToken: "refresh"}, nil)
+	s.auth.On("LoginUser", mock.Anything).Return(nil, errors.ErrorServiceInvalidCredentials)
+
+	resp, err := client.Login(context.Background(), &blogpb.LoginRequest{Email: "author@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	assert.Equal(t, userId.String(), resp.GetUserId())
+	assert.Equal(t, "access", resp.GetAccessToken())
+	assert.Equal(t, int64(7200), resp.GetExpiresIn())
+	assert.Equal(t, "refresh", resp.GetRefreshToken())
+
+	_, err = client.Login(context.Background(), &blogpb.LoginRequest{Email: "author@example.com", Password: "Wrong123!"})
+	assertCode(t, err, codes.Unauthenticated, errors.ErrorHttpIncorrectEmail.Error())
+
+	_, err = client.Login(context.Background(), &blogpb.LoginRequest{Email: "not an email", Password: "Password123!"})
+	assertCode(t, err, codes.InvalidArgument, "email: must be a valid email")
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewAuthServiceClient(conn)
+	s.auth.On("RefreshToken", &dto.RefreshRequest{RefreshToken: "refresh"}).Return(&dto.RefreshResponse{AccessToken: "access", ExpiresIn: 7200}, nil)
+	s.auth.On("RefreshToken", mock.Anything).Return(nil, errors.ErrorInvalidToken)
+
+	resp, err := client.Refresh(context.Background(), &blogpb.RefreshRequest{RefreshToken: "refresh"})
+	require.NoError(t, err)
+	assert.Equal(t, "access", resp.GetAccessToken())
+
+	_, err = client.Refresh(context.Background(), &blogpb.RefreshRequest{RefreshToken: "expired"})
+	assertCode(t, err, codes.Unauthenticated, errors.ErrorHttpBadRefresh.Error())
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewReaderServiceClient(conn)
+	s.reader.On("GetPublishedPosts", mock.Anything).Return([]*dto.GetPostResponse{}, nil)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		code    codes.Code
+		message string
+	}{
+		{"no metadata", context.Background(), codes.Unauthenticated, errors.ErrorHttpNoAuth.Error()},
+		{"other scheme", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic reader"), codes.Unauthenticated, errors.ErrorHttpNoAuth.Error()},
+		{"invalid token", withToken("forged"), codes.Unauthenticated, errors.ErrorHttpInvalidToken.Error()},
+		{"valid token", withToken("reader"), codes.OK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListPublishedPosts(tt.ctx, &blogpb.ListPublishedPostsRequest{})
+			if tt.code == codes.OK {
+				assert.NoError(t, err)
+				return
+			}
+			assertCode(t, err, tt.code, tt.message)
+		})
+	}
+}
+
+func TestReaderService_ListPublishedPosts(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewReaderServiceClient(conn)
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	post := &dto.GetPostResponse{
+		PostId:     uuid.New(),
+		Author:     dto.UserResponse{UserId: author.UserId, Email: author.Email},
+		Title:      "Go",
+		Content:    "Generics",
+		Status:     types.Published,
+		Images:     []dto.AddImageResponse{{ImageId: uuid.New(), ImageUrl: "http://localhost:9000/images/a.png"}},
+		Tags:       []string{"go"},
+		Views:      3,
+		LikesCount: 1,
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+	s.reader.On("GetPublishedPosts", dto.PostListOptions{Tag: "go", Sort: types.SortTitle, Order: types.Asc}).Return([]*dto.GetPostResponse{post}, nil)
+
+	resp, err := client.ListPublishedPosts(withToken("reader"), &blogpb.ListPublishedPostsRequest{Tag: " Go", Sort: "title", Order: "asc"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetPosts(), 1)
+	got := resp.GetPosts()[0]
+	assert.Equal(t, post.PostId.String(), got.GetPostId())
+	assert.Equal(t, author.Email, got.GetAuthor().GetEmail())
+	assert.Equal(t, "published", got.GetStatus())
+	assert.Equal(t, post.Images[0].ImageUrl, got.GetImages()[0].GetImageUrl())
+	assert.Equal(t, []string{"go"}, got.GetTags())
+	assert.Equal(t, int64(3), got.GetViews())
+	assert.Nil(t, got.GetPublishAt())
+	assert.Equal(t, created, got.GetCreatedAt().AsTime())
+
+	_, err = client.ListPublishedPosts(withToken("reader"), &blogpb.ListPublishedPostsRequest{Sort: "views"})
+	assertCode(t, err, codes.InvalidArgument, "sort must be one of")
+	_, err = client.ListPublishedPosts(withToken("reader"), &blogpb.ListPublishedPostsRequest{Order: "up"})
+	assertCode(t, err, codes.InvalidArgument, "order must be asc or desc")
+}
+
+func TestReaderService_GetPost(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewReaderServiceClient(conn)
+	postId, missingId := uuid.New(), uuid.New()
+	s.reader.On("GetPost", reader.UserId, postId).Return(&dto.GetPostResponse{PostId: postId, Title: "Go"}, nil)
+	s.reader.On("GetPost", reader.UserId, missingId).Return(nil, sql.ErrNoRows)
+
+	post, err := client.GetPost(withToken("reader"), &blogpb.GetPostRequest{PostId: postId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "Go", post.GetTitle())
+
+	_, err = client.GetPost(withToken("reader"), &blogpb.GetPostRequest{PostId: missingId.String()})
+	assertCode(t, err, codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+	_, err = client.GetPost(withToken("reader"), &blogpb.GetPostRequest{PostId: "not-a-uuid"})
+	assertCode(t, err, codes.NotFound, errors.ErrorHttpPostNotFound.Error())
+}
+
+func TestPosterService_EditPost(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewPosterServiceClient(conn)
+	postId, staleId := uuid.New(), uuid.New()
+	updated := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	s.poster.On("EditPost", author.UserId, postId, &dto.EditPostRequest{Title: "New", Content: "Body", Tags: []string{"go"}}).
+		Return(&dto.EditPostResponse{PostId: postId, AuthorId: author.UserId, IdempotencyKey: "key", Title: "New", Content: "Body", Status: types.Draft, Tags: []string{"go"}, UpdatedAt: updated}, nil)
+	s.poster.On("EditPost", author.UserId, staleId, mock.Anything).Return(&dto.EditPostResponse{PostId: staleId}, errors.ErrorServiceConflict)
+
+	resp, err := client.EditPost(withToken("author"), &blogpb.EditPostRequest{PostId: postId.String(), Title: "New", Content: "Body", Tags: []string{"Go", "go"}})
+	require.NoError(t, err)
+	assert.Equal(t, "key", resp.GetIdempotencyKey())
+	assert.Equal(t, "draft", resp.GetStatus())
+	assert.Equal(t, updated, resp.GetUpdatedAt().AsTime())
+
+	_, err = client.EditPost(withToken("author"), &blogpb.EditPostRequest{PostId: staleId.String(), Title: "New", Content: "Body", ExpectedUpdatedAt: timestamppb.New(updated)})
+	assertCode(t, err, codes.Aborted, errors.ErrorServiceConflict.Error())
+	_, err = client.EditPost(withToken("author"), &blogpb.EditPostRequest{PostId: postId.String(), Content: "Body"})
+	assertCode(t, err, codes.InvalidArgument, "title: is required")
+	_, err = client.EditPost(withToken("reader"), &blogpb.EditPostRequest{PostId: postId.String(), Title: "New", Content: "Body"})
+	assertCode(t, err, codes.PermissionDenied, errors.ErrorHttpAccessDenied.Error())
+}
+
+func TestPosterService_PublishPost(t *testing.T) {
+	conn, s := dial(t)
+	client := blogpb.NewPosterServiceClient(conn)
+	postId, otherId, draftId := uuid.New(), uuid.New(), uuid.New()
+	publishAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	s.poster.On("PublishPost", author.UserId, postId, &dto.PublishPostRequest{Status: types.Scheduled, PublishAt: &publishAt}).
+		Return(&dto.PublishPostResponse{PostId: postId, Status: types.Scheduled, PublishAt: &publishAt}, nil)
+	s.poster.On("PublishPost", author.UserId, otherId, mock.Anything).Return(nil, errors.ErrorServiceNoAccess)
+	s.poster.On("PublishPost", author.UserId, draftId, mock.Anything).Return(nil, errors.ErrorEmailNotVerified)
+
+	resp, err := client.PublishPost(withToken("author"), &blogpb.PublishPostRequest{PostId: postId.String(), Status: "scheduled", PublishAt: timestamppb.New(publishAt)})
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", resp.GetStatus())
+	assert.Equal(t, publishAt, resp.GetPublishAt().AsTime())
+
+	_, err = client.PublishPost(withToken("author"), &blogpb.PublishPostRequest{PostId: postId.String(), Status: "scheduled"})
+	assertCode(t, err, codes.InvalidArgument, "publish_at: is required")
+	_, err = client.PublishPost(withToken("author"), &blogpb.PublishPostRequest{PostId: otherId.String(), Status: "published"})
+	assertCode(t, err, codes.PermissionDenied, errors.ErrorHttpAccessDenied.Error())
+	_, err = client.PublishPost(withToken("author"), &blogpb.PublishPostRequest{PostId: draftId.String(), Status: "published"})
+	assertCode(t, err, codes.PermissionDenied, errors.ErrorHttpEmailNotVerified.Error())
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		<-ctx.Done()
+		return nil, serviceError(ctx, ctx.Err())
+	}
+	_, err := timeoutInterceptor(10*time.Millisecond)(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assertCode(t, err, codes.DeadlineExceeded, errors.ErrorHttpRequestTimeout.Error())
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type AccountService interface {
+	ExportData(ctx context.Context, userId uuid.UUID, w io.Writer) error
+	DeleteAccount(ctx context.Context, userId uuid.UUID, req *dto.DeleteAccountRequest) error
+}
+
+type AccountController struct {
+	service AccountService
+}
+
+func NewAccountController(service AccountService) *AccountController {
+	return &AccountController{service}
+}
+
+// @Summary		Export own data
+// @Description	Everything the blog keeps about you as one JSON document: your profile without credentials, your likes,
+// @Description	and your posts that are not in the trash with their images. It is streamed, an export failing on the way
+// @Description	is cut off instead of ending normally.
+// @Tags			Profile
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.AccountExport
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/users/me/export [get]
+func (c *AccountController) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.json"`, time.Now().UTC().Format(time.DateOnly)))
+	ew := &exportWriter{ResponseWriter: w}
+	err := c.service.ExportData(ctx, user.UserId, ew)
+	if err == nil {
+		return
+	}
+	if ew.wrote {
+		// the status is sent already, a client must not take what it got for a whole export
+		slogctx.Logger(ctx).Error("export cut off", slog.String("error", err.Error()))
+		panic(http.ErrAbortHandler)
+	}
+	w.Header().Del("Content-Disposition")
+	serviceError(w, r, err)
+}
+
+// @Summary		Delete account
+// @Description	Delete your account for good after entering your password again. Your likes, subscriptions,
+// @Description	notifications and avatar go with it and you are logged out everywhere. Depending on
+// @Description	ACCOUNT_DELETION_POLICY your posts stay under a "Deleted user" or are deleted with their images.
+// @Tags			Profile
+// @Accept			json
+// @Security		BearerAuth
+// @Param			request	body	dto.DeleteAccountRequest	true	"Current password"
+// @Success		204
+// @Failure		400	{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Password incorrect"
+// @Router			/users/me [delete]
+func (c *AccountController) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	req, err := bind[dto.DeleteAccountRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	if err := c.service.DeleteAccount(r.Context(), user.UserId, req); err != nil {
+		switch err {
+		case errors.ErrorServiceWrongPassword:
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpWrongPassword)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
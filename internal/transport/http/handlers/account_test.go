@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type MockAccountService struct {
+	mock.Mock
+}
+
+func (m *MockAccountService) ExportData(ctx context.Context, userId uuid.UUID, w io.Writer) error {
+	args := m.Called(userId)
+	if data := args.String(0); data != "" {
+		io.WriteString(w, data)
+	}
+	return args.Error(1)
+}
+
+func (m *MockAccountService) DeleteAccount(ctx context.Context, userId uuid.UUID, req *dto.DeleteAccountRequest) error {
+	args := m.Called(userId, req)
+	return args.Error(0)
+}
+
+func TestAccountController_DeleteAccountHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "alice@example.com", Role: types.Author}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockAccountService)
+		expectedStatus int
+	}{
+		{
+			name: "deleted",
+			body: `{"password":"Password123!"}`,
+			setupMock: func(m *MockAccountService) {
+				m.On("DeleteAccount", user.UserId, &dto.DeleteAccountRequest{Password: "Password123!"}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "wrong password",
+			body: `{"password":"nope"}`,
+			setupMock: func(m *MockAccountService) {
+				m.On("DeleteAccount", user.UserId, &dto.DeleteAccountRequest{Password: "nope"}).Return(errors.ErrorServiceWrongPassword)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "no password",
+			body:           `{}`,
+			setupMock:      func(m *MockAccountService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "broken body",
+			body:           `{"password":`,
+			setupMock:      func(m *MockAccountService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAccountService{}
+			tt.setupMock(mockService)
+			controller := NewAccountController(mockService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/me", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.DeleteAccountHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountController_ExportHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "alice@example.com", Role: types.Reader}
+
+	t.Run("streams the export", func(t *testing.T) {
+		mockService := &MockAccountService{}
+		mockService.On("ExportData", user.UserId).Return(`{"user":{},"likes":[],"posts":[]}`, nil)
+		controller := NewAccountController(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/export", nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		controller.ExportHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), `filename="account-`)
+		assert.JSONEq(t, `{"user":{},"likes":[],"posts":[]}`, rr.Body.String())
+	})
+
+	t.Run("fails before writing", func(t *testing.T) {
+		mockService := &MockAccountService{}
+		mockService.On("ExportData", user.UserId).Return("", sql.ErrConnDone)
+		controller := NewAccountController(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/export", nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		controller.ExportHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadGateway, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+	})
+}
@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/jobs"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type AdminService interface {
+	GetUsers(ctx context.Context, limit, offset int) (*dto.GetUsersResponse, error)
+	ChangeUserRole(ctx context.Context, adminId, userId uuid.UUID, req *dto.ChangeRoleRequest) (*dto.UserResponse, error)
+	SetPostStatus(ctx context.Context, adminId, postId uuid.UUID, req *dto.SetPostStatusRequest) (*dto.EditPostResponse, error)
+	GetAuditLog(ctx context.Context, filter dto.AuditFilter, limit, offset int) (*dto.GetAuditLogResponse, error)
+	GetCacheStats() *dto.CacheStatsResponse
+	GetReports(ctx context.Context, limit, offset int) (*dto.GetReportsResponse, error)
+	ResolveReports(ctx context.Context, adminId, postId uuid.UUID, req *dto.ResolveReportsRequest) (*dto.ResolveReportsResponse, error)
+}
+
+type MaintenanceService interface {
+	Clean(ctx context.Context, dryRun bool) (*dto.CleanupImagesResponse, error)
+}
+
+type TokenPurgeService interface {
+	Purge(ctx context.Context) (*dto.PurgeTokensResponse, error)
+}
+
+type JobsMonitor interface {
+	Stats() []jobs.Stats
+}
+
+type AdminController struct {
+	service     AdminService
+	maintenance MaintenanceService
+	tokens      TokenPurgeService
+	jobs        JobsMonitor
+	// settings is the redacted configuration the server runs with
+	settings map[string]string
+}
+
+func NewAdminController(service AdminService, maintenance MaintenanceService, tokens TokenPurgeService, jobs JobsMonitor, settings map[string]string) *AdminController {
+	return &AdminController{service, maintenance, tokens, jobs, settings}
+}
+
+// auditActions are the values ?action= of the audit log accepts
+var auditActions = []types.AuditAction{
+	types.AuditRegister, types.AuditLogin, types.AuditLoginFailed, types.AuditRefresh, types.AuditLogout,
+	types.AuditPasswordReset, types.AuditVerifyEmail, types.AuditPublishPost, types.AuditDeletePost, types.AuditDeleteImage,
+	types.AuditUnpublishPost, types.AuditDismissReports, types.AuditDeleteAccount,
+	types.AuditCreateApiKey, types.AuditRevokeApiKey,
+}
+
+// parsePage reads ?limit= and ?offset=, missing values fall back to the first page
+func parsePage(r *http.Request) (int, int, bool) {
+	limit, offset := defaultPageLimit, 0
+	var err error
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, false
+		}
+	}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		if offset, err = strconv.Atoi(raw); err != nil || offset < 0 {
+			return 0, 0, false
+		}
+	}
+	return limit, offset, true
+}
+
+// @Summary		List users
+// @Description	Page through every registered user
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Param			limit	query		int	false	"Page size, 1-100"	default(20)
+// @Param			offset	query		int	false	"Users to skip"		default(0)
+// @Success		200		{object}	dto.GetUsersResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/users [get]
+func (c *AdminController) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	resp, err := c.service.GetUsers(r.Context(), limit, offset)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Change user role
+// @Description	Set the role of any user, admins cannot change their own role
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			userId	path		string					true	"User ID"	format(uuid)
+// @Param			request	body		dto.ChangeRoleRequest	true	"New role"
+// @Success		200		{object}	dto.UserResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"User not found"
+// @Router			/admin/users/{userId}/role [patch]
+func (c *AdminController) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpUserNotFound)
+		return
+	}
+
+	req, err := bind[dto.ChangeRoleRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	resp, err := c.service.ChangeUserRole(r.Context(), admin.UserId, userId, req)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceNoAccess:
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
+		case errors.ErrorRepositoryBadRole:
+			jsonError(w, http.StatusBadRequest, err)
+		case sql.ErrNoRows:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpUserNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Set post status
+// @Description	Publish or unpublish any post regardless of its author
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string						true	"Post ID"	format(uuid)
+// @Param			request	body		dto.SetPostStatusRequest	true	"New status"
+// @Success		200		{object}	dto.EditPostResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/admin/posts/{postId}/status [patch]
+func (c *AdminController) SetPostStatusHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	req, err := bind[dto.SetPostStatusRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	resp, err := c.service.SetPostStatus(r.Context(), admin.UserId, postId, req)
+	if err != nil {
+		switch err {
+		case errors.ErrorRepositoryBadStatus:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
+		case sql.ErrNoRows:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Moderation queue
+// @Description	Posts with reports nobody handled yet, the most reported first, with their reports counted by reason
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Param			limit	query		int	false	"Page size, 1-100"	default(20)
+// @Param			offset	query		int	false	"Posts to skip"		default(0)
+// @Success		200		{object}	dto.GetReportsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/reports [get]
+func (c *AdminController) GetReportsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	resp, err := c.service.GetReports(r.Context(), limit, offset)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Resolve reports
+// @Description	Close the open reports of a post, dismiss leaves the post as it is and unpublish takes it back to draft. Both are kept in the audit log.
+// @Tags			Admin
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string						true	"Post ID"	format(uuid)
+// @Param			request	body		dto.ResolveReportsRequest	true	"Decision"
+// @Success		200		{object}	dto.ResolveReportsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nIncorrect status"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found\nNo open reports of the post"
+// @Router			/admin/reports/{postId}/resolve [post]
+func (c *AdminController) ResolveReportsHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	req, err := bind[dto.ResolveReportsRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	resp, err := c.service.ResolveReports(r.Context(), admin.UserId, postId, req)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceIncorrectData:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
+		case errors.ErrorServiceNoReports:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpNoReports)
+		case sql.ErrNoRows:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Clean up orphaned images
+// @Description	Remove stored images no post refers to and that are older than ORPHAN_MIN_AGE, with dry_run they are only listed
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Param			dry_run	query		bool	false	"Only list the orphans"	default(false)
+// @Success		200		{object}	dto.CleanupImagesResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		502		{object}	dto.ErrorResponse	"Storage timeout"
+// @Router			/admin/maintenance/cleanup-images [post]
+func (c *AdminController) CleanupImagesHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+			return
+		}
+	}
+
+	resp, err := c.maintenance.Clean(r.Context(), dryRun)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Purge expired tokens
+// @Description	Delete sessions, password reset and preview tokens that expired more than TOKEN_PURGE_GRACE ago, as the background job does every TOKEN_PURGE_INTERVAL
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.PurgeTokensResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/maintenance/purge-tokens [post]
+func (c *AdminController) PurgeTokensHandler(w http.ResponseWriter, r *http.Request) {
+	resp, err := c.tokens.Purge(r.Context())
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Effective configuration
+// @Description	Every setting the server runs with by its environment variable, whichever source it came from. Secrets that are set read ***
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.ConfigResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/config [get]
+func (c *AdminController) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
+	settings := c.settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.ConfigResponse{Settings: settings}, w)
+}
+
+// @Summary		Cache statistics
+// @Description	Hits, misses and hit ratio of the user and published posts caches of this instance since it started
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.CacheStatsResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/cache [get]
+func (c *AdminController) GetCacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(c.service.GetCacheStats(), w)
+}
+
+// @Summary		Background jobs
+// @Description	Runs, errors, skipped overlapping ticks and durations of the background jobs of this instance since it started
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.JobsResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/jobs [get]
+func (c *AdminController) GetJobsHandler(w http.ResponseWriter, r *http.Request) {
+	res := &dto.JobsResponse{Jobs: []dto.JobStats{}}
+	if c.jobs != nil {
+		for _, stats := range c.jobs.Stats() {
+			res.Jobs = append(res.Jobs, jobStatsResponse(stats))
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+func jobStatsResponse(stats jobs.Stats) dto.JobStats {
+	res := dto.JobStats{
+		Name:           stats.Name,
+		IntervalMs:     stats.Interval.Milliseconds(),
+		Runs:           stats.Runs,
+		Errors:         stats.Errors,
+		Skipped:        stats.Skipped,
+		Running:        stats.Running,
+		LastDurationMs: stats.LastDuration.Milliseconds(),
+		LastError:      stats.LastError,
+	}
+	if stats.Runs > 0 {
+		lastRun := stats.LastRun
+		res.LastRunAt = &lastRun
+		res.AvgDurationMs = (stats.TotalDuration / time.Duration(stats.Runs)).Milliseconds()
+	}
+	return res
+}
+
+// @Summary		Audit log
+// @Description	Page through logins, account changes, publishing and deletions, latest first
+// @Tags			Admin
+// @Produce		json
+// @Security		BearerAuth
+// @Param			user_id	query		string	false	"Only events of this user"	format(uuid)
+// @Param			action	query		string	false	"Only this action"			Enums(register, login, login_failed, refresh, logout, password_reset, publish_post, delete_post, delete_image)
+// @Param			limit	query		int		false	"Page size, 1-100"			default(20)
+// @Param			offset	query		int		false	"Events to skip"			default(0)
+// @Success		200		{object}	dto.GetAuditLogResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Router			/admin/audit [get]
+func (c *AdminController) GetAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	filter := dto.AuditFilter{Action: types.AuditAction(r.URL.Query().Get("action"))}
+	if filter.Action != "" && !slices.Contains(auditActions, filter.Action) {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	if raw := r.URL.Query().Get("user_id"); raw != "" {
+		userId, err := uuid.Parse(raw)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+			return
+		}
+		filter.UserId = &userId
+	}
+
+	resp, err := c.service.GetAuditLog(r.Context(), filter, limit, offset)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
@@ -0,0 +1,830 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/jobs"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type MockAdminService struct {
+	mock.Mock
+}
+
+func (m *MockAdminService) GetUsers(ctx context.Context, limit, offset int) (*dto.GetUsersResponse, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetUsersResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ChangeUserRole(ctx context.Context, adminId, userId uuid.UUID, req *dto.ChangeRoleRequest) (*dto.UserResponse, error) {
+	args := m.Called(adminId, userId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) SetPostStatus(ctx context.Context, adminId, postId uuid.UUID, req *dto.SetPostStatusRequest) (*dto.EditPostResponse, error) {
+	args := m.Called(adminId, postId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EditPostResponse), args.Error(1)
+}
+
+func (m *MockAdminService) GetAuditLog(ctx context.Context, filter dto.AuditFilter, limit, offset int) (*dto.GetAuditLogResponse, error) {
+	args := m.Called(filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetAuditLogResponse), args.Error(1)
+}
+
+func (m *MockAdminService) GetCacheStats() *dto.CacheStatsResponse {
+	return m.Called().Get(0).(*dto.CacheStatsResponse)
+}
+
+func (m *MockAdminService) GetReports(ctx context.Context, limit, offset int) (*dto.GetReportsResponse, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetReportsResponse), args.Error(1)
+}
+
+func (m *MockAdminService) ResolveReports(ctx context.Context, adminId, postId uuid.UUID, req *dto.ResolveReportsRequest) (*dto.ResolveReportsResponse, error) {
+	args := m.Called(adminId, postId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ResolveReportsResponse), args.Error(1)
+}
+
+type MockMaintenanceService struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceService) Clean(ctx context.Context, dryRun bool) (*dto.CleanupImagesResponse, error) {
+	args := m.Called(dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CleanupImagesResponse), args.Error(1)
+}
+
+type MockTokenPurgeService struct {
+	mock.Mock
+}
+
+func (m *MockTokenPurgeService) Purge(ctx context.Context) (*dto.PurgeTokensResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PurgeTokensResponse), args.Error(1)
+}
+
+func TestAdminController_GetUsersHandler(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+		shouldCallMock bool
+	}{
+		{
+			name:  "default page",
+			query: "",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetUsers", defaultPageLimit, 0).Return(&dto.GetUsersResponse{
+					Users: []dto.UserResponse{{UserId: userId, Email: "user@example.com", Role: types.Reader}},
+					Total: 1,
+					Limit: defaultPageLimit,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.GetUsersResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				require.Len(t, resp.Users, 1)
+				assert.Equal(t, userId, resp.Users[0].UserId)
+				assert.Equal(t, types.Reader, resp.Users[0].Role)
+				assert.Equal(t, 1, resp.Total)
+			},
+		},
+		{
+			name:  "explicit page",
+			query: "?limit=5&offset=10",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetUsers", 5, 10).Return(&dto.GetUsersResponse{Limit: 5, Offset: 10}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "limit too big",
+			query:          fmt.Sprintf("?limit=%d", maxPageLimit+1),
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpIncorrectQuery.Error())
+			},
+		},
+		{
+			name:           "negative offset",
+			query:          "?offset=-1",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not a number",
+			query:          "?limit=ten",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unexpected error",
+			query: "",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetUsers", defaultPageLimit, 0).Return(nil, fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+		{
+			name:  "query timeout",
+			query: "",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetUsers", defaultPageLimit, 0).Return(nil, fmt.Errorf("%w: %w", errors.ErrorRepositoryQueryTimeout, context.DeadlineExceeded))
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpQueryTimeout.Error()+`"}`, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAdminService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			controller.GetUsersHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetUsers", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAdminController_ChangeRoleHandler(t *testing.T) {
+	adminId := uuid.New()
+	userId := uuid.New()
+	admin := &dto.UserDB{UserId: adminId, Role: types.Admin}
+
+	tests := []struct {
+		name           string
+		userId         string
+		user           interface{}
+		requestBody    interface{}
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+	}{
+		{
+			name:        "promote to author",
+			userId:      userId.String(),
+			user:        admin,
+			requestBody: dto.ChangeRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAdminService) {
+				m.On("ChangeUserRole", adminId, userId, &dto.ChangeRoleRequest{Role: types.Author}).
+					Return(&dto.UserResponse{UserId: userId, Email: "user@example.com", Role: types.Author}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.UserResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, types.Author, resp.Role)
+			},
+		},
+		{
+			name:           "unknown role",
+			userId:         userId.String(),
+			user:           admin,
+			requestBody:    dto.ChangeRoleRequest{Role: "root"},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			userId:         userId.String(),
+			user:           admin,
+			requestBody:    "{invalid json}",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid user ID",
+			userId:         "invalid-uuid",
+			user:           admin,
+			requestBody:    dto.ChangeRoleRequest{Role: types.Author},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusNotFound,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpUserNotFound.Error())
+			},
+		},
+		{
+			name:        "user not found",
+			userId:      userId.String(),
+			user:        admin,
+			requestBody: dto.ChangeRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAdminService) {
+				m.On("ChangeUserRole", adminId, userId, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "own role",
+			userId:      adminId.String(),
+			user:        admin,
+			requestBody: dto.ChangeRoleRequest{Role: types.Reader},
+			setupMock: func(m *MockAdminService) {
+				m.On("ChangeUserRole", adminId, adminId, mock.Anything).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpAccessDenied.Error())
+			},
+		},
+		{
+			name:           "no user in context",
+			userId:         userId.String(),
+			requestBody:    dto.ChangeRoleRequest{Role: types.Author},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:        "unexpected error",
+			userId:      userId.String(),
+			user:        admin,
+			requestBody: dto.ChangeRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAdminService) {
+				m.On("ChangeUserRole", adminId, userId, mock.Anything).Return(nil, fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAdminService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{service: mockService}
+
+			var bodyBytes []byte
+			if v, ok := tt.requestBody.(string); ok {
+				bodyBytes = []byte(v)
+			} else {
+				bodyBytes, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest(http.MethodPatch, "/admin/users/"+tt.userId+"/role", bytes.NewReader(bodyBytes))
+			req.SetPathValue("userId", tt.userId)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+			}
+			rr := httptest.NewRecorder()
+			controller.ChangeRoleHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminController_SetPostStatusHandler(t *testing.T) {
+	postId := uuid.New()
+	admin := &dto.UserDB{UserId: uuid.New(), Role: types.Admin}
+
+	tests := []struct {
+		name           string
+		postId         string
+		requestBody    interface{}
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+	}{
+		{
+			name:        "force unpublish",
+			postId:      postId.String(),
+			requestBody: dto.SetPostStatusRequest{Status: types.Draft},
+			setupMock: func(m *MockAdminService) {
+				m.On("SetPostStatus", admin.UserId, postId, &dto.SetPostStatusRequest{Status: types.Draft}).
+					Return(&dto.EditPostResponse{PostId: postId, Status: types.Draft}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown status",
+			postId:         postId.String(),
+			requestBody:    dto.SetPostStatusRequest{Status: "hidden"},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid post ID",
+			postId:         "invalid-uuid",
+			requestBody:    dto.SetPostStatusRequest{Status: types.Draft},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "post not found",
+			postId:      postId.String(),
+			requestBody: dto.SetPostStatusRequest{Status: types.Draft},
+			setupMock: func(m *MockAdminService) {
+				m.On("SetPostStatus", admin.UserId, postId, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "unexpected error",
+			postId:      postId.String(),
+			requestBody: dto.SetPostStatusRequest{Status: types.Published},
+			setupMock: func(m *MockAdminService) {
+				m.On("SetPostStatus", admin.UserId, postId, mock.Anything).Return(nil, fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAdminService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{service: mockService}
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPatch, "/admin/posts/"+tt.postId+"/status", bytes.NewReader(bodyBytes))
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, admin))
+			rr := httptest.NewRecorder()
+			controller.SetPostStatusHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminController_GetReportsHandler(t *testing.T) {
+	t.Run("page", func(t *testing.T) {
+		mockService := &MockAdminService{}
+		page := &dto.GetReportsResponse{Posts: []dto.ReportedPostResponse{{PostId: uuid.New(), Reports: 3}}, Total: 1, Limit: 10, Offset: 0}
+		mockService.On("GetReports", 10, 0).Return(page, nil)
+		controller := &AdminController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.GetReportsHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/reports?limit=10", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp dto.GetReportsResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Posts[0].Reports)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		mockService := &MockAdminService{}
+		controller := &AdminController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.GetReportsHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/reports?limit=0", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockService.AssertNotCalled(t, "GetReports")
+	})
+}
+
+func TestAdminController_ResolveReportsHandler(t *testing.T) {
+	postId := uuid.New()
+	admin := &dto.UserDB{UserId: uuid.New(), Role: types.Admin}
+
+	tests := []struct {
+		name           string
+		postId         string
+		requestBody    interface{}
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+	}{
+		{
+			name:        "unpublish",
+			postId:      postId.String(),
+			requestBody: dto.ResolveReportsRequest{Action: types.ModerationUnpublish},
+			setupMock: func(m *MockAdminService) {
+				m.On("ResolveReports", admin.UserId, postId, &dto.ResolveReportsRequest{Action: types.ModerationUnpublish}).
+					Return(&dto.ResolveReportsResponse{PostId: postId, Action: types.ModerationUnpublish, Resolved: 2, Status: types.Draft}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown action",
+			postId:         postId.String(),
+			requestBody:    dto.ResolveReportsRequest{Action: "delete"},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid post ID",
+			postId:         "invalid-uuid",
+			requestBody:    dto.ResolveReportsRequest{Action: types.ModerationDismiss},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "post cannot be unpublished",
+			postId:      postId.String(),
+			requestBody: dto.ResolveReportsRequest{Action: types.ModerationUnpublish},
+			setupMock: func(m *MockAdminService) {
+				m.On("ResolveReports", admin.UserId, postId, mock.Anything).Return(nil, errors.ErrorServiceIncorrectData)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "no open reports",
+			postId:      postId.String(),
+			requestBody: dto.ResolveReportsRequest{Action: types.ModerationDismiss},
+			setupMock: func(m *MockAdminService) {
+				m.On("ResolveReports", admin.UserId, postId, mock.Anything).Return(nil, errors.ErrorServiceNoReports)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "post not found",
+			postId:      postId.String(),
+			requestBody: dto.ResolveReportsRequest{Action: types.ModerationDismiss},
+			setupMock: func(m *MockAdminService) {
+				m.On("ResolveReports", admin.UserId, postId, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAdminService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{service: mockService}
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/admin/reports/"+tt.postId+"/resolve", bytes.NewReader(bodyBytes))
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, admin))
+			rr := httptest.NewRecorder()
+			controller.ResolveReportsHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminController_CleanupImagesHandler(t *testing.T) {
+	modified := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockMaintenanceService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+		shouldCallMock bool
+	}{
+		{
+			name:  "removes by default",
+			query: "",
+			setupMock: func(m *MockMaintenanceService) {
+				m.On("Clean", false).Return(&dto.CleanupImagesResponse{Scanned: 2, Orphans: []dto.OrphanImageResponse{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.CleanupImagesResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.False(t, resp.DryRun)
+				assert.Equal(t, 2, resp.Scanned)
+			},
+		},
+		{
+			name:  "dry run",
+			query: "?dry_run=true",
+			setupMock: func(m *MockMaintenanceService) {
+				m.On("Clean", true).Return(&dto.CleanupImagesResponse{
+					DryRun:  true,
+					Scanned: 1,
+					Orphans: []dto.OrphanImageResponse{{Name: "pic.png", LastModified: modified}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.CleanupImagesResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.True(t, resp.DryRun)
+				require.Len(t, resp.Orphans, 1)
+				assert.Equal(t, "pic.png", resp.Orphans[0].Name)
+				assert.True(t, modified.Equal(resp.Orphans[0].LastModified))
+			},
+		},
+		{
+			name:           "not a bool",
+			query:          "?dry_run=maybe",
+			setupMock:      func(m *MockMaintenanceService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "storage timeout",
+			query: "",
+			setupMock: func(m *MockMaintenanceService) {
+				m.On("Clean", false).Return(nil, fmt.Errorf("%w: %w", errors.ErrorRepositoryStorageTimeout, context.DeadlineExceeded))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpStorageTimeout.Error()+`"}`, body)
+			},
+		},
+		{
+			name:  "unexpected error",
+			query: "",
+			setupMock: func(m *MockMaintenanceService) {
+				m.On("Clean", false).Return(nil, fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMaintenanceService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{maintenance: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/maintenance/cleanup-images"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			controller.CleanupImagesHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Clean", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAdminController_PurgeTokensHandler(t *testing.T) {
+	t.Run("purged", func(t *testing.T) {
+		mockService := &MockTokenPurgeService{}
+		mockService.On("Purge").Return(&dto.PurgeTokensResponse{
+			Purged: dto.PurgedTokens{RefreshTokens: 3, PreviewTokens: 1, IdempotencyKeys: 4},
+			Total:  dto.PurgedTokens{RefreshTokens: 10, PasswordResets: 2, PreviewTokens: 1, IdempotencyKeys: 4},
+		}, nil)
+		controller := &AdminController{tokens: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.PurgeTokensHandler(rr, httptest.NewRequest(http.MethodPost, "/admin/maintenance/purge-tokens", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"purged":{"refresh_tokens":3,"password_resets":0,"preview_tokens":1,"idempotency_keys":4},
+			"total":{"refresh_tokens":10,"password_resets":2,"preview_tokens":1,"idempotency_keys":4}}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("query timeout", func(t *testing.T) {
+		mockService := &MockTokenPurgeService{}
+		mockService.On("Purge").Return(nil, fmt.Errorf("%w: %w", errors.ErrorRepositoryQueryTimeout, context.DeadlineExceeded))
+		controller := &AdminController{tokens: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.PurgeTokensHandler(rr, httptest.NewRequest(http.MethodPost, "/admin/maintenance/purge-tokens", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_GetAuditLogHandler(t *testing.T) {
+	userId := uuid.New()
+	page := &dto.GetAuditLogResponse{
+		Entries: []dto.AuditEntryResponse{{AuditId: uuid.New(), UserId: &userId, Action: types.AuditLogin, IP: "203.0.113.7"}},
+		Total:   1,
+		Limit:   defaultPageLimit,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:  "whole log",
+			query: "",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetAuditLog", dto.AuditFilter{}, defaultPageLimit, 0).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:  "filtered by user and action",
+			query: "?user_id=" + userId.String() + "&action=login&limit=5&offset=10",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetAuditLog", dto.AuditFilter{UserId: &userId, Action: types.AuditLogin}, 5, 10).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "unknown action",
+			query:          "?action=drop_table",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid user id",
+			query:          "?user_id=nope",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid page",
+			query:          "?limit=0",
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "",
+			setupMock: func(m *MockAdminService) {
+				m.On("GetAuditLog", dto.AuditFilter{}, defaultPageLimit, 0).Return(nil, fmt.Errorf("database down"))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAdminService{}
+			tt.setupMock(mockService)
+			controller := &AdminController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			controller.GetAuditLogHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetAuditLog", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, rr.Body.String(), `"action":"login"`)
+			}
+		})
+	}
+}
+
+func TestAdminController_GetCacheStatsHandler(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("GetCacheStats").Return(&dto.CacheStatsResponse{
+		Posts: dto.CacheStats{Enabled: true, Hits: 3, Misses: 1, HitRatio: 0.75},
+	})
+	controller := NewAdminController(mockService, nil, nil, nil, nil)
+
+	rr := httptest.NewRecorder()
+	controller.GetCacheStatsHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/cache", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"hit_ratio":0.75`)
+	assert.Contains(t, rr.Body.String(), `"users":{"enabled":false`)
+	mockService.AssertExpectations(t)
+}
+
+type fakeJobsMonitor []jobs.Stats
+
+func (f fakeJobsMonitor) Stats() []jobs.Stats {
+	return f
+}
+
+func TestAdminController_GetJobsHandler(t *testing.T) {
+	lastRun := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	monitor := fakeJobsMonitor{
+		{Name: "orphan_cleaner"},
+		{Name: "scheduler", Interval: 30 * time.Second, Runs: 4, Errors: 1, Skipped: 2, Running: true,
+			LastRun: lastRun, LastDuration: 300 * time.Millisecond, TotalDuration: 800 * time.Millisecond, LastError: "database is down"},
+	}
+
+	tests := []struct {
+		name    string
+		monitor JobsMonitor
+		want    []dto.JobStats
+	}{
+		{
+			name:    "stats",
+			monitor: monitor,
+			want: []dto.JobStats{
+				{Name: "orphan_cleaner"},
+				{Name: "scheduler", IntervalMs: 30000, Runs: 4, Errors: 1, Skipped: 2, Running: true,
+					LastRunAt: &lastRun, LastDurationMs: 300, AvgDurationMs: 200, LastError: "database is down"},
+			},
+		},
+		{name: "no runner", monitor: nil, want: []dto.JobStats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewAdminController(nil, nil, nil, tt.monitor, nil)
+
+			rr := httptest.NewRecorder()
+			controller.GetJobsHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/jobs", nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var resp dto.JobsResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.want, resp.Jobs)
+		})
+	}
+}
+
+func TestAdminController_GetConfigHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     map[string]string
+	}{
+		{
+			name:     "settings",
+			settings: map[string]string{"PORT": "8080", "SECRET": "***"},
+			want:     map[string]string{"PORT": "8080", "SECRET": "***"},
+		},
+		{name: "none passed", settings: nil, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewAdminController(nil, nil, nil, nil, tt.settings)
+
+			rr := httptest.NewRecorder()
+			controller.GetConfigHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var resp dto.ConfigResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.want, resp.Settings)
+		})
+	}
+}
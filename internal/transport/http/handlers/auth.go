@@ -1,28 +1,129 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	json "github.com/mailru/easyjson"
 
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/apiversion"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jsonbody"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
 	"github.com/xkarasb/blog/pkg/utils"
 )
 
 type AuthService interface {
-	RegistrateUser(user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error)
-	LoginUser(user *dto.LoginUserRequest) (*dto.LoginUserResponse, error)
-	RefreshToken(token *dto.RefreshRequest) (*dto.RefreshResponse, error)
-	AuthorizeUser(token string) (*dto.UserDB, error)
+	RegistrateUser(ctx context.Context, user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error)
+	LoginUser(ctx context.Context, user *dto.LoginUserRequest) (*dto.LoginUserResponse, error)
+	RefreshToken(ctx context.Context, token *dto.RefreshRequest) (*dto.RefreshResponse, error)
+	AuthorizeUser(ctx context.Context, token string) (*dto.UserDB, error)
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error
+	BecomeAuthor(ctx context.Context, user *dto.UserDB, sessionId uuid.UUID, req *dto.ChangeOwnRoleRequest) (*dto.ChangeOwnRoleResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Sessions(ctx context.Context, userId, current uuid.UUID) (*dto.GetSessionsResponse, error)
+	RevokeSession(ctx context.Context, userId, sessionId uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userId, current uuid.UUID) (*dto.RevokeSessionsResponse, error)
+	CreateApiKey(ctx context.Context, userId uuid.UUID, req *dto.CreateApiKeyRequest) (*dto.CreateApiKeyResponse, error)
+	ApiKeys(ctx context.Context, userId uuid.UUID) (*dto.GetApiKeysResponse, error)
+	RevokeApiKey(ctx context.Context, userId, keyId uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userId uuid.UUID) (time.Duration, error)
+}
+
+const (
+	refreshCookieName = "refresh_token"
+	// csrfHeader must accompany a refresh from the cookie, a cross-site form cannot set it
+	csrfHeader = "X-Requested-With"
+)
+
+// AuthCookie is the session mode for browsers: the refresh token also goes into an HttpOnly cookie
+// scripts cannot read. The JSON bodies stay the same so other clients are not affected.
+type AuthCookie struct {
+	Enabled bool
+	// MaxAge is the lifetime of the refresh token
+	MaxAge time.Duration
 }
 
 type AuthController struct {
 	service AuthService
+	cookie  AuthCookie
+}
+
+func NewAuthController(service AuthService, cookie AuthCookie) *AuthController {
+	return &AuthController{service: service, cookie: cookie}
+}
+
+// refreshCookiePath keeps the cookie away from every request but the auth ones of the api version
+// the client uses, the router is mounted under /api and /api/v1
+func refreshCookiePath(r *http.Request) string {
+	return apiversion.FromContext(r.Context()).Prefix() + "/auth"
+}
+
+func (c *AuthController) setRefreshCookie(w http.ResponseWriter, r *http.Request, token string) {
+	if !c.cookie.Enabled {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    token,
+		Path:     refreshCookiePath(r),
+		MaxAge:   int(c.cookie.MaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func (c *AuthController) clearRefreshCookie(w http.ResponseWriter, r *http.Request) {
+	if !c.cookie.Enabled {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Path:     refreshCookiePath(r),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
 }
 
-func NewAuthController(service AuthService) *AuthController {
-	return &AuthController{service: service}
+// refreshToken reads the refresh token from the body. In cookie mode the body may be empty or leave
+// the token out, then the cookie is used if the request carries the CSRF header.
+func (c *AuthController) refreshToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	req := &dto.RefreshRequest{}
+	body, err := io.ReadAll(r.Body)
+	if err == nil && (!c.cookie.Enabled || len(bytes.TrimSpace(body)) > 0) {
+		err = jsonbody.Decode(body, req, jsonbody.IsStrict(r.Context()))
+	}
+	if err != nil {
+		bodyError(w, err)
+		return "", false
+	}
+	if req.RefreshToken != "" || !c.cookie.Enabled {
+		return req.RefreshToken, true
+	}
+
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		return "", true
+	}
+	if r.Header.Get(csrfHeader) == "" {
+		jsonError(w, http.StatusForbidden, errors.ErrorHttpMissingCsrfHeader)
+		return "", false
+	}
+	return cookie.Value, true
 }
 
 // @Summary		Registration
@@ -32,31 +133,27 @@ func NewAuthController(service AuthService) *AuthController {
 // @Produce		json
 // @Param			request	body		dto.RegistrateUserRequest	true	"Registration data"
 // @Success		200		{object}	dto.RegistrateUserResponse
-// @Failure		403		"User alredy exsist"
-// @Failure		400		"Incorrect email format\nIncorrect body"
+// @Failure		403		{object}	dto.ErrorResponse	"User alredy exsist"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect email format\nIncorrect body"
 // @Router			/auth/register [post]
 func (c *AuthController) RegisterHandler(w http.ResponseWriter, r *http.Request) {
-	reqUser := &dto.RegistrateUserRequest{}
-	if err := json.UnmarshalFromReader(r.Body, reqUser); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if err := utils.Validate(reqUser); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	reqUser, err := bind[dto.RegistrateUserRequest](r)
+	if err != nil {
+		bodyError(w, err)
 		return
 	}
 
-	resp, err := c.service.RegistrateUser(reqUser)
+	resp, err := c.service.RegistrateUser(r.Context(), reqUser)
 	if err != nil {
 		switch err {
 		case errors.ErrorRepositoryUserAlreadyExsist:
-			http.Error(w, err.Error(), http.StatusForbidden)
+			jsonError(w, http.StatusForbidden, err)
 		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
+	c.setRefreshCookie(w, r, resp.RefreshToken)
 	w.WriteHeader(http.StatusOK)
 	json.MarshalToHTTPResponseWriter(resp, w)
 }
@@ -68,63 +165,62 @@ func (c *AuthController) RegisterHandler(w http.ResponseWriter, r *http.Request)
 // @Produce		json
 // @Param			request	body		dto.LoginUserRequest	true	"Login data"
 // @Success		200		{object}	dto.LoginUserResponse
-// @Failure		400		"Incorrect body"
-// @Failure		403		"Email or password incorrect"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		403		{object}	dto.ErrorResponse	"Email or password incorrect"
 // @Router			/auth/login [post]
 func (c *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	reqUser := &dto.LoginUserRequest{}
-	if err := json.UnmarshalFromReader(r.Body, reqUser); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
-		return
-	}
-	if err := utils.Validate(reqUser); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	reqUser, err := bind[dto.LoginUserRequest](r)
+	if err != nil {
+		bodyError(w, err)
 		return
 	}
-	resp, err := c.service.LoginUser(reqUser)
+	resp, err := c.service.LoginUser(r.Context(), reqUser)
 
 	if err != nil {
 		switch err {
-		case errors.ErrorRepositoryEmailNotExsist:
-			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.ErrorServiceInvalidCredentials:
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpIncorrectEmail)
 		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
 
+	c.setRefreshCookie(w, r, resp.RefreshToken)
 	w.WriteHeader(http.StatusOK)
 	json.MarshalToHTTPResponseWriter(resp, w)
 }
 
 // @Summary		Invoke refresh token
-// @Description	Get access token by refresh token
+// @Description	Get access token by refresh token. With AUTH_COOKIE_MODE the body may leave the token out, the refresh_token cookie is used then and the X-Requested-With header is required.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
-// @Param			request	body		dto.RefreshRequest	true	"Refresh token data"
-// @Success		200		{object}	dto.RefreshResponse
-// @Failure		400		"Incorrect body\nRefresh token expired or incorrect"
+// @Param			request				body		dto.RefreshRequest	false	"Refresh token data"
+// @Param			X-Requested-With	header		string				false	"Required when the token comes from the cookie"
+// @Success		200					{object}	dto.RefreshResponse
+// @Failure		400					{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403					{object}	dto.ErrorResponse	"Missing X-Requested-With header"
 // @Router			/auth/refresh-token [post]
 func (c *AuthController) RefreshHandler(w http.ResponseWriter, r *http.Request) {
-	req := &dto.RefreshRequest{}
-	if err := json.UnmarshalFromReader(r.Body, req); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
+	token, ok := c.refreshToken(w, r)
+	if !ok {
 		return
 	}
 
+	req := &dto.RefreshRequest{RefreshToken: token}
 	if err := utils.Validate(req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		bodyError(w, err)
 		return
 	}
 
-	resp, err := c.service.RefreshToken(req)
+	resp, err := c.service.RefreshToken(r.Context(), req)
 
 	if err != nil {
 		if err == errors.ErrorInvalidToken {
-			http.Error(w, errors.ErrorHttpBadRefresh.Error(), http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpBadRefresh)
 		} else {
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
@@ -132,3 +228,415 @@ func (c *AuthController) RefreshHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.MarshalToHTTPResponseWriter(resp, w)
 }
+
+// @Summary		Logout
+// @Description	Revoke the refresh token and clear the refresh_token cookie, a token that is no longer valid is ignored
+// @Tags			Auth
+// @Accept			json
+// @Param			request				body	dto.RefreshRequest	false	"Refresh token data"
+// @Param			X-Requested-With	header	string				false	"Required when the token comes from the cookie"
+// @Success		204
+// @Failure		400	{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		403	{object}	dto.ErrorResponse	"Missing X-Requested-With header"
+// @Router			/auth/logout [post]
+func (c *AuthController) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	token, ok := c.refreshToken(w, r)
+	if !ok {
+		return
+	}
+
+	if token != "" {
+		if err := c.service.Logout(r.Context(), token); err != nil && err != errors.ErrorInvalidToken {
+			serviceError(w, r, err)
+			return
+		}
+	}
+
+	c.clearRefreshCookie(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary		Forgot password
+// @Description	Send a password reset link, the answer does not depend on whether the email is registered
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.ForgotPasswordRequest	true	"Account email"
+// @Success		200		{object}	dto.ForgotPasswordResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Router			/auth/forgot-password [post]
+func (c *AuthController) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := bind[dto.ForgotPasswordRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	// failures are only logged, a different answer would reveal registered emails
+	if err := c.service.ForgotPassword(r.Context(), req); err != nil {
+		slogctx.Logger(r.Context()).Error("forgot password", slog.String("error", err.Error()))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.ForgotPasswordResponse{
+		Message: "if the email is registered a reset link has been sent",
+	}, w)
+}
+
+// @Summary		Reset password
+// @Description	Set a new password using the emailed token, all refresh tokens are revoked
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.ResetPasswordRequest	true	"Token and new password"
+// @Success		200		{object}	dto.ResetPasswordResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nReset token expired or incorrect"
+// @Router			/auth/reset-password [post]
+func (c *AuthController) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := bind[dto.ResetPasswordRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	if err := c.service.ResetPassword(r.Context(), req); err != nil {
+		if err == errors.ErrorInvalidToken {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpBadResetToken)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.ResetPasswordResponse{
+		Message: "password has been changed",
+	}, w)
+}
+
+// @Summary		Verify email
+// @Description	Follow the link mailed at registration, authors publish only with a verified email
+// @Tags			Auth
+// @Produce		json
+// @Param			token	query		string	true	"Token of the mailed link"
+// @Success		200		{object}	dto.VerifyEmailResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Verification token expired or incorrect"
+// @Router			/auth/verify-email [get]
+func (c *AuthController) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpBadVerifyToken)
+		return
+	}
+
+	if err := c.service.VerifyEmail(r.Context(), token); err != nil {
+		if err == errors.ErrorInvalidToken {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpBadVerifyToken)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.VerifyEmailResponse{
+		Message: "email address has been verified",
+	}, w)
+}
+
+// @Summary		Resend verification
+// @Description	Mail another verification link, one per VERIFY_RESEND_INTERVAL counting the one of the registration
+// @Tags			Auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.ResendVerificationResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		409	{object}	dto.ErrorResponse	"Email already verified"
+// @Failure		429	{object}	dto.ErrorResponse	"Verification mail sent recently, Retry-After tells how long to wait"
+// @Router			/auth/verify-email/resend [post]
+func (c *AuthController) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	wait, err := c.service.ResendVerification(r.Context(), user.UserId)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceEmailVerified:
+			jsonError(w, http.StatusConflict, errors.ErrorHttpEmailVerified)
+		case errors.ErrorServiceResendTooSoon:
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			jsonError(w, http.StatusTooManyRequests, errors.ErrorHttpResendTooSoon)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.ResendVerificationResponse{
+		Message: "a verification link has been sent",
+	}, w)
+}
+
+// @Summary		Current user
+// @Description	Profile of the user the access token belongs to
+// @Tags			Auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.UserResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/auth/me [get]
+func (c *AuthController) MeHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.UserResponse{
+		UserId:        user.UserId,
+		DisplayName:   user.Name(),
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+	}, w)
+}
+
+// @Summary		Become an author
+// @Description	Switch the current reader to the author role, the answer carries a new access token
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.ChangeOwnRoleRequest	true	"Requested role, only author"
+// @Success		200		{object}	dto.ChangeOwnRoleResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Router			/auth/role [patch]
+func (c *AuthController) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	req, err := bind[dto.ChangeOwnRoleRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	sessionId, _ := r.Context().Value(types.CtxSession).(uuid.UUID)
+	resp, err := c.service.BecomeAuthor(r.Context(), user, sessionId, req)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceNoAccess:
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
+		case errors.ErrorRepositoryBadRole:
+			jsonError(w, http.StatusBadRequest, err)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		List sessions
+// @Description	Devices the user is logged in on, the one used last first; current marks the session of the access token
+// @Tags			Auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.GetSessionsResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/auth/sessions [get]
+func (c *AuthController) SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+	sessionId, _ := r.Context().Value(types.CtxSession).(uuid.UUID)
+
+	resp, err := c.service.Sessions(r.Context(), user.UserId, sessionId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Log out a session
+// @Description	Log out one device, its refresh token stops working at once and its access tokens when they expire. Logging out the current session also clears the refresh_token cookie.
+// @Tags			Auth
+// @Security		BearerAuth
+// @Param			sessionId	path	string	true	"Session ID"	format(uuid)
+// @Success		204
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404	{object}	dto.ErrorResponse	"Session not found"
+// @Router			/auth/sessions/{sessionId} [delete]
+func (c *AuthController) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	sessionId, err := uuid.Parse(r.PathValue("sessionId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpSessionNotFound)
+		return
+	}
+
+	if err := c.service.RevokeSession(r.Context(), user.UserId, sessionId); err != nil {
+		switch err {
+		case errors.ErrorServiceSessionNotFound:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpSessionNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	if current, _ := r.Context().Value(types.CtxSession).(uuid.UUID); current == sessionId {
+		c.clearRefreshCookie(w, r)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary		Log out other sessions
+// @Description	Log out every device but the one of the access token. An access token issued before sessions has none, then every device is logged out.
+// @Tags			Auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.RevokeSessionsResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/auth/sessions [delete]
+func (c *AuthController) RevokeOtherSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+	sessionId, _ := r.Context().Value(types.CtxSession).(uuid.UUID)
+
+	resp, err := c.service.RevokeOtherSessions(r.Context(), user.UserId, sessionId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Create API key
+// @Description	Make a key for machine clients like a static site generator, sent as `Authorization: ApiKey <key>`. Keys read whatever the author can, with the write scope they may change things too. The key is only in this response. Logging in is needed, API keys cannot make keys nor reach the account under /users/me.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.CreateApiKeyRequest	true	"Name, scopes and expiry"
+// @Success		201		{object}	dto.CreateApiKeyResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nexpires_at must be in the future"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied\nAPI keys cannot be used here"
+// @Router			/auth/api-keys [post]
+func (c *AuthController) CreateApiKeyHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	req, err := bind[dto.CreateApiKeyRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	resp, err := c.service.CreateApiKey(r.Context(), user.UserId, req)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceApiKeyExpired:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpApiKeyExpired)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		List API keys
+// @Description	API keys of the author, newest first, expired ones too; the keys themselves are not shown again
+// @Tags			Auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.GetApiKeysResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied\nAPI keys cannot be used here"
+// @Router			/auth/api-keys [get]
+func (c *AuthController) ApiKeysHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	resp, err := c.service.ApiKeys(r.Context(), user.UserId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Revoke API key
+// @Description	Delete an API key, requests with it are refused from now on
+// @Tags			Auth
+// @Security		BearerAuth
+// @Param			keyId	path	string	true	"API key ID"	format(uuid)
+// @Success		204
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403	{object}	dto.ErrorResponse	"Access denied\nAPI keys cannot be used here"
+// @Failure		404	{object}	dto.ErrorResponse	"API key not found"
+// @Router			/auth/api-keys/{keyId} [delete]
+func (c *AuthController) RevokeApiKeyHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	keyId, err := uuid.Parse(r.PathValue("keyId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpApiKeyNotFound)
+		return
+	}
+
+	if err := c.service.RevokeApiKey(r.Context(), user.UserId, keyId); err != nil {
+		switch err {
+		case errors.ErrorServiceApiKeyNotFound:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpApiKeyNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,17 +2,22 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	gerrors "errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/apiversion"
 	"github.com/xkarasb/blog/pkg/errors"
 	"github.com/xkarasb/blog/pkg/types"
 )
@@ -22,7 +27,7 @@ type MockAuthService struct {
 	secret string
 }
 
-func (m *MockAuthService) RegistrateUser(user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error) {
+func (m *MockAuthService) RegistrateUser(ctx context.Context, user *dto.RegistrateUserRequest) (*dto.RegistrateUserResponse, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,7 +35,7 @@ func (m *MockAuthService) RegistrateUser(user *dto.RegistrateUserRequest) (*dto.
 	return args.Get(0).(*dto.RegistrateUserResponse), args.Error(1)
 }
 
-func (m *MockAuthService) LoginUser(user *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
+func (m *MockAuthService) LoginUser(ctx context.Context, user *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -38,7 +43,7 @@ func (m *MockAuthService) LoginUser(user *dto.LoginUserRequest) (*dto.LoginUserR
 	return args.Get(0).(*dto.LoginUserResponse), args.Error(1)
 }
 
-func (m *MockAuthService) RefreshToken(user *dto.RefreshRequest) (*dto.RefreshResponse, error) {
+func (m *MockAuthService) RefreshToken(ctx context.Context, user *dto.RefreshRequest) (*dto.RefreshResponse, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -46,7 +51,7 @@ func (m *MockAuthService) RefreshToken(user *dto.RefreshRequest) (*dto.RefreshRe
 	return args.Get(0).(*dto.RefreshResponse), args.Error(1)
 }
 
-func (m *MockAuthService) AuthorizeUser(token string) (*dto.UserDB, error) {
+func (m *MockAuthService) AuthorizeUser(ctx context.Context, token string) (*dto.UserDB, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -54,6 +59,81 @@ func (m *MockAuthService) AuthorizeUser(token string) (*dto.UserDB, error) {
 	return args.Get(0).(*dto.UserDB), args.Error(1)
 }
 
+func (m *MockAuthService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) BecomeAuthor(ctx context.Context, user *dto.UserDB, sessionId uuid.UUID, req *dto.ChangeOwnRoleRequest) (*dto.ChangeOwnRoleResponse, error) {
+	args := m.Called(user, sessionId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ChangeOwnRoleResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, refreshToken string) error {
+	args := m.Called(refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) Sessions(ctx context.Context, userId, current uuid.UUID) (*dto.GetSessionsResponse, error) {
+	args := m.Called(userId, current)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetSessionsResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	args := m.Called(userId, sessionId)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendVerification(ctx context.Context, userId uuid.UUID) (time.Duration, error) {
+	args := m.Called(userId)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockAuthService) CreateApiKey(ctx context.Context, userId uuid.UUID, req *dto.CreateApiKeyRequest) (*dto.CreateApiKeyResponse, error) {
+	args := m.Called(userId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CreateApiKeyResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ApiKeys(ctx context.Context, userId uuid.UUID) (*dto.GetApiKeysResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetApiKeysResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeApiKey(ctx context.Context, userId, keyId uuid.UUID) error {
+	args := m.Called(userId, keyId)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RevokeOtherSessions(ctx context.Context, userId, current uuid.UUID) (*dto.RevokeSessionsResponse, error) {
+	args := m.Called(userId, current)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RevokeSessionsResponse), args.Error(1)
+}
+
 func TestAuthController_RegisterHandler(t *testing.T) {
 	id := uuid.New()
 	tests := []struct {
@@ -277,12 +357,12 @@ func TestAuthController_LoginHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockAuthService) {
 				m.On("LoginUser", mock.AnythingOfType("*dto.LoginUserRequest")).
-					Return(nil, errors.ErrorRepositoryEmailNotExsist)
+					Return(nil, errors.ErrorServiceInvalidCredentials)
 			},
 			expectedStatus: http.StatusForbidden,
 			shouldCallMock: true,
 			checkBody: func(t *testing.T, body string) {
-				assert.Contains(t, body, errors.ErrorRepositoryEmailNotExsist.Error())
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpIncorrectEmail.Error()+`"}`, body)
 			},
 		},
 		{
@@ -293,12 +373,12 @@ func TestAuthController_LoginHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockAuthService) {
 				m.On("LoginUser", mock.AnythingOfType("*dto.LoginUserRequest")).
-					Return(nil, errors.ErrorRepositoryEmailNotExsist)
+					Return(nil, errors.ErrorServiceInvalidCredentials)
 			},
 			expectedStatus: http.StatusForbidden,
 			shouldCallMock: true,
 			checkBody: func(t *testing.T, body string) {
-				assert.Contains(t, body, errors.ErrorRepositoryEmailNotExsist.Error())
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpIncorrectEmail.Error()+`"}`, body)
 			},
 		},
 		{
@@ -638,3 +718,849 @@ func TestAuthController_RefreshHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthController_ForgotPasswordHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:        "registered email",
+			requestBody: dto.ForgotPasswordRequest{Email: "user@example.com"},
+			setupMock: func(m *MockAuthService) {
+				m.On("ForgotPassword", mock.AnythingOfType("*dto.ForgotPasswordRequest")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:        "service failure is not revealed",
+			requestBody: dto.ForgotPasswordRequest{Email: "user@example.com"},
+			setupMock: func(m *MockAuthService) {
+				m.On("ForgotPassword", mock.AnythingOfType("*dto.ForgotPasswordRequest")).Return(gerrors.New("db is down"))
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "invalid email",
+			requestBody:    dto.ForgotPasswordRequest{Email: "not-an-email"},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			requestBody:    "{invalid json}",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	var okBody string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+			controller := &AuthController{service: mockService}
+
+			var bodyBytes []byte
+			if v, ok := tt.requestBody.(string); ok {
+				bodyBytes = []byte(v)
+			} else {
+				bodyBytes, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/forgot-password", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			controller.ForgotPasswordHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// every accepted request gets the exact same answer
+				if okBody == "" {
+					okBody = rr.Body.String()
+				}
+				assert.Equal(t, okBody, rr.Body.String())
+			}
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ForgotPassword", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthController_ResetPasswordHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+	}{
+		{
+			name:        "successful reset",
+			requestBody: dto.ResetPasswordRequest{Token: "token", NewPassword: "NewPassword1!"},
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.MatchedBy(func(req *dto.ResetPasswordRequest) bool {
+					return req.Token == "token" && req.NewPassword == "NewPassword1!"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "expired or unknown token",
+			requestBody: dto.ResetPasswordRequest{Token: "token", NewPassword: "NewPassword1!"},
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.AnythingOfType("*dto.ResetPasswordRequest")).Return(errors.ErrorInvalidToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpBadResetToken.Error())
+			},
+		},
+		{
+			name:           "short password",
+			requestBody:    dto.ResetPasswordRequest{Token: "token", NewPassword: "short"},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			requestBody:    dto.ResetPasswordRequest{NewPassword: "NewPassword1!"},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "unexpected error",
+			requestBody: dto.ResetPasswordRequest{Token: "token", NewPassword: "NewPassword1!"},
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", mock.AnythingOfType("*dto.ResetPasswordRequest")).Return(gerrors.New("db is down"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+			controller := &AuthController{service: mockService}
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/reset-password", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			controller.ResetPasswordHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthController_MeHandler(t *testing.T) {
+	user := &dto.UserDB{
+		UserId:       uuid.New(),
+		Email:        "user@example.com",
+		PasswordHash: "hashed_password",
+		Role:         types.Author,
+	}
+
+	tests := []struct {
+		name           string
+		user           interface{}
+		expectedStatus int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "authorized user",
+			user:           user,
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var resp map[string]interface{}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, user.UserId.String(), resp["user_id"])
+				assert.Equal(t, user.Email, resp["email"])
+				assert.Equal(t, string(types.Author), resp["role"])
+				assert.Equal(t, "u***@example.com", resp["display_name"])
+				assert.Len(t, resp, 4)
+				assert.NotContains(t, rr.Body.String(), "hashed_password")
+			},
+		},
+		{
+			name:           "no user in context",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "wrong type in context",
+			user:           "user",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &AuthController{service: &MockAuthService{}}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+			}
+			rr := httptest.NewRecorder()
+
+			controller.MeHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, rr)
+			}
+		})
+	}
+}
+
+func TestAuthController_ChangeRoleHandler(t *testing.T) {
+	reader := &dto.UserDB{UserId: uuid.New(), Email: "reader@example.com", Role: types.Reader}
+	sessionId := uuid.New()
+
+	tests := []struct {
+		name           string
+		user           interface{}
+		requestBody    interface{}
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+	}{
+		{
+			name:        "reader becomes author",
+			user:        reader,
+			requestBody: dto.ChangeOwnRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAuthService) {
+				m.On("BecomeAuthor", reader, sessionId, &dto.ChangeOwnRoleRequest{Role: types.Author}).
+					Return(&dto.ChangeOwnRoleResponse{Id: reader.UserId, Role: types.Author, AccessToken: "new_access_token"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.ChangeOwnRoleResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, types.Author, resp.Role)
+				assert.Equal(t, "new_access_token", resp.AccessToken)
+			},
+		},
+		{
+			name:           "only author can be requested",
+			user:           reader,
+			requestBody:    dto.ChangeOwnRoleRequest{Role: types.Admin},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "demotion is refused",
+			user:        &dto.UserDB{UserId: uuid.New(), Role: types.Author},
+			requestBody: dto.ChangeOwnRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAuthService) {
+				m.On("BecomeAuthor", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpAccessDenied.Error())
+			},
+		},
+		{
+			name:           "invalid JSON",
+			user:           reader,
+			requestBody:    "{invalid json}",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no user in context",
+			requestBody:    dto.ChangeOwnRoleRequest{Role: types.Author},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:        "unexpected error",
+			user:        reader,
+			requestBody: dto.ChangeOwnRoleRequest{Role: types.Author},
+			setupMock: func(m *MockAuthService) {
+				m.On("BecomeAuthor", mock.Anything, mock.Anything, mock.Anything).Return(nil, gerrors.New("db is down"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+			controller := &AuthController{service: mockService}
+
+			var bodyBytes []byte
+			if v, ok := tt.requestBody.(string); ok {
+				bodyBytes = []byte(v)
+			} else {
+				bodyBytes, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest(http.MethodPatch, "/api/auth/role", bytes.NewReader(bodyBytes))
+			if tt.user != nil {
+				ctx := context.WithValue(req.Context(), types.CtxUser, tt.user)
+				req = req.WithContext(context.WithValue(ctx, types.CtxSession, sessionId))
+			}
+			rr := httptest.NewRecorder()
+
+			controller.ChangeRoleHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func refreshCookie(rr *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == refreshCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestAuthController_LoginHandlerCookieMode(t *testing.T) {
+	resp := &dto.LoginUserResponse{Id: uuid.New(), AccessToken: "access", ExpiresIn: 7200, RefreshToken: "refresh"}
+
+	for _, enabled := range []bool{false, true} {
+		mockService := &MockAuthService{}
+		mockService.On("LoginUser", mock.Anything).Return(resp, nil)
+		controller := NewAuthController(mockService, AuthCookie{Enabled: enabled, MaxAge: time.Hour})
+
+		body, _ := json.Marshal(dto.LoginUserRequest{Email: "user@example.com", Password: "password"})
+		rr := httptest.NewRecorder()
+		controller.LoginHandler(rr, httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		// the body stays the same for clients that keep the token themselves
+		var got dto.LoginUserResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+		assert.Equal(t, "refresh", got.RefreshToken)
+
+		cookie := refreshCookie(rr)
+		if !enabled {
+			assert.Nil(t, cookie)
+			continue
+		}
+		require.NotNil(t, cookie)
+		assert.Equal(t, "refresh", cookie.Value)
+		assert.Equal(t, "/api/auth", cookie.Path)
+		assert.Equal(t, 3600, cookie.MaxAge)
+		assert.True(t, cookie.HttpOnly)
+		assert.True(t, cookie.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	}
+}
+
+func TestAuthController_RefreshCookiePathFollowsVersion(t *testing.T) {
+	resp := &dto.LoginUserResponse{Id: uuid.New(), AccessToken: "access", ExpiresIn: 7200, RefreshToken: "refresh"}
+	mockService := &MockAuthService{}
+	mockService.On("LoginUser", mock.Anything).Return(resp, nil)
+	controller := NewAuthController(mockService, AuthCookie{Enabled: true, MaxAge: time.Hour})
+
+	for version, path := range map[apiversion.Version]string{apiversion.Unversioned: "/api/auth", apiversion.V1: "/api/v1/auth"} {
+		body, _ := json.Marshal(dto.LoginUserRequest{Email: "user@example.com", Password: "password"})
+		req := httptest.NewRequest(http.MethodPost, path+"/login", bytes.NewReader(body))
+		req = req.WithContext(apiversion.WithVersion(req.Context(), version))
+		rr := httptest.NewRecorder()
+		controller.LoginHandler(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		cookie := refreshCookie(rr)
+		require.NotNil(t, cookie)
+		assert.Equal(t, path, cookie.Path)
+	}
+}
+
+func TestAuthController_RefreshHandlerCookieMode(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		body           string
+		cookie         string
+		csrf           bool
+		wantToken      string
+		expectedStatus int
+	}{
+		{"cookie with header", true, "", "from-cookie", true, "from-cookie", http.StatusOK},
+		{"cookie with empty object", true, "{}", "from-cookie", true, "from-cookie", http.StatusOK},
+		{"cookie without header", true, "", "from-cookie", false, "", http.StatusForbidden},
+		{"body wins over cookie", true, `{"refresh_token":"from-body"}`, "from-cookie", false, "from-body", http.StatusOK},
+		{"no cookie no body", true, "", "", true, "", http.StatusBadRequest},
+		{"cookie ignored when disabled", false, "{}", "from-cookie", true, "", http.StatusBadRequest},
+		{"empty body when disabled", false, "", "", false, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			if tt.wantToken != "" {
+				mockService.On("RefreshToken", &dto.RefreshRequest{RefreshToken: tt.wantToken}).
+					Return(&dto.RefreshResponse{AccessToken: "access", ExpiresIn: 7200}, nil)
+			}
+			controller := NewAuthController(mockService, AuthCookie{Enabled: tt.enabled, MaxAge: time.Hour})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh-token", strings.NewReader(tt.body))
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: refreshCookieName, Value: tt.cookie})
+			}
+			if tt.csrf {
+				req.Header.Set("X-Requested-With", "XMLHttpRequest")
+			}
+			rr := httptest.NewRecorder()
+			controller.RefreshHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, rr.Body.String(), errors.ErrorHttpMissingCsrfHeader.Error())
+			}
+			if tt.wantToken != "" {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "RefreshToken", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthController_LogoutHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		body           string
+		cookie         string
+		csrf           bool
+		serviceErr     error
+		wantToken      string
+		expectedStatus int
+		wantCleared    bool
+	}{
+		{"token in body", false, `{"refresh_token":"from-body"}`, "", false, nil, "from-body", http.StatusNoContent, false},
+		{"cookie with header", true, "", "from-cookie", true, nil, "from-cookie", http.StatusNoContent, true},
+		{"cookie without header", true, "", "from-cookie", false, nil, "", http.StatusForbidden, false},
+		{"no token in cookie mode", true, "", "", false, nil, "", http.StatusNoContent, true},
+		{"token no longer valid", true, "", "expired", true, errors.ErrorInvalidToken, "expired", http.StatusNoContent, true},
+		{"unexpected error", false, `{"refresh_token":"from-body"}`, "", false, fmt.Errorf("database error"), "from-body", http.StatusBadGateway, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			if tt.wantToken != "" {
+				mockService.On("Logout", tt.wantToken).Return(tt.serviceErr)
+			}
+			controller := NewAuthController(mockService, AuthCookie{Enabled: tt.enabled, MaxAge: time.Hour})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", strings.NewReader(tt.body))
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: refreshCookieName, Value: tt.cookie})
+			}
+			if tt.csrf {
+				req.Header.Set("X-Requested-With", "XMLHttpRequest")
+			}
+			rr := httptest.NewRecorder()
+			controller.LogoutHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			cookie := refreshCookie(rr)
+			if tt.wantCleared {
+				require.NotNil(t, cookie)
+				assert.Empty(t, cookie.Value)
+				assert.Less(t, cookie.MaxAge, 0)
+			} else {
+				assert.Nil(t, cookie)
+			}
+			if tt.wantToken != "" {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Logout", mock.Anything)
+			}
+		})
+	}
+}
+
+// sessionRequest carries the user and session the auth middleware puts into the context
+func sessionRequest(method, target string, user *dto.UserDB, sessionId uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	if user == nil {
+		return req
+	}
+	ctx := context.WithValue(req.Context(), types.CtxUser, user)
+	return req.WithContext(context.WithValue(ctx, types.CtxSession, sessionId))
+}
+
+func TestAuthController_SessionsHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	current := uuid.New()
+
+	t.Run("lists sessions", func(t *testing.T) {
+		mockService := &MockAuthService{}
+		mockService.On("Sessions", user.UserId, current).Return(&dto.GetSessionsResponse{Sessions: []dto.SessionResponse{
+			{SessionId: current, UserAgent: "Firefox", Current: true},
+			{SessionId: uuid.New(), UserAgent: "Mobile Safari"},
+		}}, nil)
+		controller := &AuthController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.SessionsHandler(rr, sessionRequest(http.MethodGet, "/api/auth/sessions", user, current))
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp dto.GetSessionsResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		require.Len(t, resp.Sessions, 2)
+		assert.True(t, resp.Sessions[0].Current)
+		assert.Equal(t, "Mobile Safari", resp.Sessions[1].UserAgent)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("no user in context", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		(&AuthController{service: &MockAuthService{}}).SessionsHandler(rr, sessionRequest(http.MethodGet, "/api/auth/sessions", nil, uuid.Nil))
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("unexpected error", func(t *testing.T) {
+		mockService := &MockAuthService{}
+		mockService.On("Sessions", user.UserId, current).Return(nil, gerrors.New("db is down"))
+
+		rr := httptest.NewRecorder()
+		(&AuthController{service: mockService}).SessionsHandler(rr, sessionRequest(http.MethodGet, "/api/auth/sessions", user, current))
+		assert.Equal(t, http.StatusBadGateway, rr.Code)
+	})
+}
+
+func TestAuthController_RevokeSessionHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	current, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		sessionId      string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		wantCleared    bool
+	}{
+		{
+			name:      "other session",
+			sessionId: other.String(),
+			setupMock: func(m *MockAuthService) {
+				m.On("RevokeSession", user.UserId, other).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "current session clears the cookie",
+			sessionId: current.String(),
+			setupMock: func(m *MockAuthService) {
+				m.On("RevokeSession", user.UserId, current).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			wantCleared:    true,
+		},
+		{
+			name:      "unknown session",
+			sessionId: other.String(),
+			setupMock: func(m *MockAuthService) {
+				m.On("RevokeSession", user.UserId, other).Return(errors.ErrorServiceSessionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "malformed id",
+			sessionId:      "not-a-uuid",
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "unexpected error",
+			sessionId: other.String(),
+			setupMock: func(m *MockAuthService) {
+				m.On("RevokeSession", user.UserId, other).Return(gerrors.New("db is down"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+			controller := NewAuthController(mockService, AuthCookie{Enabled: true, MaxAge: time.Hour})
+
+			req := sessionRequest(http.MethodDelete, "/api/auth/sessions/"+tt.sessionId, user, current)
+			req.SetPathValue("sessionId", tt.sessionId)
+			rr := httptest.NewRecorder()
+			controller.RevokeSessionHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusNotFound {
+				assert.Contains(t, rr.Body.String(), errors.ErrorHttpSessionNotFound.Error())
+			}
+			if tt.wantCleared {
+				cookie := refreshCookie(rr)
+				require.NotNil(t, cookie)
+				assert.Less(t, cookie.MaxAge, 0)
+			} else {
+				assert.Nil(t, refreshCookie(rr))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthController_RevokeOtherSessionsHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	current := uuid.New()
+
+	mockService := &MockAuthService{}
+	mockService.On("RevokeOtherSessions", user.UserId, current).Return(&dto.RevokeSessionsResponse{Revoked: 2}, nil)
+	controller := &AuthController{service: mockService}
+
+	rr := httptest.NewRecorder()
+	controller.RevokeOtherSessionsHandler(rr, sessionRequest(http.MethodDelete, "/api/auth/sessions", user, current))
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp dto.RevokeSessionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Revoked)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthController_CreateApiKeyHandler(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	keyId := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		checkBody      func(*testing.T, string)
+	}{
+		{
+			name: "read only key",
+			body: `{"name":"ci"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("CreateApiKey", author.UserId, &dto.CreateApiKeyRequest{Name: "ci"}).Return(&dto.CreateApiKeyResponse{
+					ApiKeyResponse: dto.ApiKeyResponse{KeyId: keyId, Name: "ci", Scopes: []types.Scope{types.ScopeRead}},
+					Key:            "blog_secret",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.CreateApiKeyResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, keyId, resp.KeyId)
+				assert.Equal(t, "blog_secret", resp.Key)
+				assert.Equal(t, []types.Scope{types.ScopeRead}, resp.Scopes)
+			},
+		},
+		{
+			name: "write scope",
+			body: `{"name":"deploy","scopes":["read","write"]}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("CreateApiKey", author.UserId, &dto.CreateApiKeyRequest{Name: "deploy", Scopes: []types.Scope{types.ScopeRead, types.ScopeWrite}}).
+					Return(&dto.CreateApiKeyResponse{Key: "blog_secret"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown scope",
+			body:           `{"name":"ci","scopes":["admin"]}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no name",
+			body:           `{"scopes":["read"]}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "expiry passed",
+			body: `{"name":"ci","expires_at":"2020-01-01T00:00:00Z"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("CreateApiKey", author.UserId, mock.Anything).Return(nil, errors.ErrorServiceApiKeyExpired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkBody: func(t *testing.T, body string) {
+				assert.Contains(t, body, errors.ErrorHttpApiKeyExpired.Error())
+			},
+		},
+		{
+			name: "unexpected error",
+			body: `{"name":"ci"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("CreateApiKey", author.UserId, mock.Anything).Return(nil, gerrors.New("db is down"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			tt.setupMock(mockService)
+			controller := &AuthController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/api-keys", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, author))
+			rr := httptest.NewRecorder()
+			controller.CreateApiKeyHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthController_ApiKeysHandler(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	mockService := &MockAuthService{}
+	mockService.On("ApiKeys", author.UserId).Return(&dto.GetApiKeysResponse{ApiKeys: []dto.ApiKeyResponse{
+		{KeyId: uuid.New(), Name: "ci", Scopes: []types.Scope{types.ScopeRead}},
+	}}, nil)
+	controller := &AuthController{service: mockService}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/api-keys", nil)
+	req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, author))
+	rr := httptest.NewRecorder()
+	controller.ApiKeysHandler(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp dto.GetApiKeysResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.ApiKeys, 1)
+	assert.Equal(t, "ci", resp.ApiKeys[0].Name)
+	assert.NotContains(t, rr.Body.String(), `"key":`)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthController_RevokeApiKeyHandler(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	keyId := uuid.New()
+
+	tests := []struct {
+		name           string
+		keyId          string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"revoked", keyId.String(), nil, http.StatusNoContent},
+		{"unknown key", keyId.String(), errors.ErrorServiceApiKeyNotFound, http.StatusNotFound},
+		{"malformed id", "not-a-uuid", nil, http.StatusNotFound},
+		{"unexpected error", keyId.String(), gerrors.New("db is down"), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			if tt.keyId == keyId.String() {
+				mockService.On("RevokeApiKey", author.UserId, keyId).Return(tt.serviceErr)
+			}
+			controller := &AuthController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/auth/api-keys/"+tt.keyId, nil)
+			req.SetPathValue("keyId", tt.keyId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, author))
+			rr := httptest.NewRecorder()
+			controller.RevokeApiKeyHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusNotFound {
+				assert.Contains(t, rr.Body.String(), errors.ErrorHttpApiKeyNotFound.Error())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthController_VerifyEmailHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"verified", "?token=signed", nil, http.StatusOK},
+		{"expired or forged", "?token=signed", errors.ErrorInvalidToken, http.StatusBadRequest},
+		{"no token", "", nil, http.StatusBadRequest},
+		{"unexpected error", "?token=signed", gerrors.New("db is down"), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			if tt.query != "" {
+				mockService.On("VerifyEmail", "signed").Return(tt.serviceErr)
+			}
+			controller := &AuthController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/verify-email"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			controller.VerifyEmailHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Contains(t, rr.Body.String(), errors.ErrorHttpBadVerifyToken.Error())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthController_ResendVerificationHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+
+	tests := []struct {
+		name           string
+		wait           time.Duration
+		serviceErr     error
+		expectedStatus int
+		retryAfter     string
+	}{
+		{"sent", 0, nil, http.StatusOK, ""},
+		{"too soon", 1500 * time.Millisecond, errors.ErrorServiceResendTooSoon, http.StatusTooManyRequests, "2"},
+		{"verified already", 0, errors.ErrorServiceEmailVerified, http.StatusConflict, ""},
+		{"unexpected error", 0, gerrors.New("db is down"), http.StatusBadGateway, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAuthService{}
+			mockService.On("ResendVerification", user.UserId).Return(tt.wait, tt.serviceErr)
+			controller := &AuthController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/verify-email/resend", nil)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.ResendVerificationHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.retryAfter, rr.Header().Get("Retry-After"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jsonbody"
+	"github.com/xkarasb/blog/pkg/utils"
+)
+
+// bind reads the JSON body of r into a new T and validates it, prepare runs in between, e.g. to normalize
+// tags before their rules are checked. Its errors are answered by bodyError.
+func bind[T any](r *http.Request, prepare ...func(*T)) (*T, error) {
+	req := new(T)
+	if err := decodeBody(r, req); err != nil {
+		return nil, err
+	}
+	for _, p := range prepare {
+		p(req)
+	}
+	if err := utils.Validate(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// decodeBody reads the JSON body of r into v, strictly if the StrictJSON middleware asked for it
+func decodeBody(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return jsonbody.Decode(data, v, jsonbody.IsStrict(r.Context()))
+}
+
+// bodyError answers a body bind refused as a dto.ErrorResponse. One cut off by BodyLimit gets 413, one
+// strict mode refused gets 400 naming the field, one whose fields broke their rules 400 listing them,
+// anything else 400 as well.
+func bodyError(w http.ResponseWriter, err error) {
+	if tooLarge(err) {
+		jsonError(w, http.StatusRequestEntityTooLarge, errors.ErrorHttpBodyTooLarge)
+		return
+	}
+	var invalid *utils.ValidationError
+	if errors.As(err, &invalid) {
+		fieldsError(w, invalid)
+		return
+	}
+	var strict *jsonbody.StrictError
+	if errors.As(err, &strict) {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectBody)
+}
+
+// fieldsError answers 400 with every field that broke a rule, so a form can show each next to its input
+func fieldsError(w http.ResponseWriter, invalid *utils.ValidationError) {
+	resp := &dto.ErrorResponse{
+		Error:  errors.ErrorHttpInvalidFields.Error(),
+		Code:   dto.CodeValidationFailed,
+		Fields: make([]dto.FieldError, len(invalid.Fields)),
+	}
+	for i, field := range invalid.Fields {
+		resp.Fields[i] = dto.FieldError{Field: field.Field, Rule: field.Rule, Message: field.Message}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+func tooLarge(err error) bool {
+	var maxBytes *http.MaxBytesError
+	return errors.As(err, &maxBytes)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jsonbody"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// assertFields checks body is the 400 of a body whose fields broke their rules
+func assertFields(t *testing.T, body string, fields ...dto.FieldError) {
+	t.Helper()
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, errors.ErrorHttpInvalidFields.Error(), resp.Error)
+	assert.Equal(t, dto.CodeValidationFailed, resp.Code)
+	assert.Equal(t, fields, resp.Fields)
+}
+
+func TestBind(t *testing.T) {
+	t.Run("decodes, prepares and validates", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{"idempotency_key":"key-1","title":"Post","content":"Text","tags":[" Go "]}`))
+		post, err := bind(req, func(post *dto.CreatePostRequest) { post.Tags = []string{"go"} })
+		require.NoError(t, err)
+		assert.Equal(t, "Post", post.Title)
+		assert.Equal(t, []string{"go"}, post.Tags)
+	})
+
+	t.Run("every broken field by its json name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"alice","password":"short"}`))
+		_, err := bind[dto.LoginUserRequest](req)
+		rr := httptest.NewRecorder()
+		bodyError(rr, err)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assertFields(t, rr.Body.String(),
+			dto.FieldError{Field: "email", Rule: "email", Message: "must be a valid email"},
+			dto.FieldError{Field: "password", Rule: "min", Message: "must be at least 8 characters long"},
+		)
+	})
+
+	t.Run("type without easyjson", func(t *testing.T) {
+		type plain struct {
+			Name string `json:"name" validate:"required"`
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"plain"}`))
+		got, err := bind[plain](req)
+		require.NoError(t, err)
+		assert.Equal(t, "plain", got.Name)
+
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		_, err = bind[plain](req)
+		assert.Error(t, err)
+	})
+}
+
+// TestBodyErrors checks that every handler reading a body answers the same broken bodies the same way
+func TestBodyErrors(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	handlers := map[string]http.HandlerFunc{
+		"create post": (&ReaderController{service: &MockReaderService{}}).CreatePostHandler,
+		"edit post":   NewPosterController(&MockPosterService{}).EditPostHandler,
+		"publish":     NewPosterController(&MockPosterService{}).PublishHandler,
+	}
+	tests := []struct {
+		name           string
+		body           string
+		strict         bool
+		limit          int64
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "broken json", body: `{"title":`, expectedStatus: http.StatusBadRequest, expectedError: errors.ErrorHttpIncorrectBody.Error()},
+		{name: "unknown field in strict mode", body: `{"bogus":1}`, strict: true, expectedStatus: http.StatusBadRequest, expectedError: `unknown field "bogus"`},
+		{name: "too large", body: strings.Repeat(" ", 64) + `{}`, limit: 16, expectedStatus: http.StatusRequestEntityTooLarge, expectedError: errors.ErrorHttpBodyTooLarge.Error()},
+		{name: "fails validation", body: `{}`, expectedStatus: http.StatusBadRequest, expectedError: errors.ErrorHttpInvalidFields.Error()},
+	}
+
+	for name, handler := range handlers {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(tt.body))
+				req.SetPathValue("postId", uuid.NewString())
+				ctx := context.WithValue(req.Context(), types.CtxUser, user)
+				if tt.strict {
+					ctx = jsonbody.WithStrict(ctx)
+				}
+				req = req.WithContext(ctx)
+				rr := httptest.NewRecorder()
+				if tt.limit > 0 {
+					req.Body = http.MaxBytesReader(rr, req.Body, tt.limit)
+				}
+				handler(rr, req)
+
+				assert.Equal(t, tt.expectedStatus, rr.Code)
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedError, resp.Error)
+			})
+		}
+	}
+}
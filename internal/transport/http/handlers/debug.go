@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"expvar"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+type DebugController struct {
+	gauges map[string]expvar.Var
+}
+
+// NewDebugController serves gauges next to the variables published to expvar. They are kept here
+// rather than published, which would panic for the second server of a process.
+func NewDebugController(gauges map[string]expvar.Var) *DebugController {
+	return &DebugController{gauges}
+}
+
+// VarsHandler answers with every expvar variable, as expvar.Handler does, and the gauges of the server
+func (c *DebugController) VarsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprint(w, "{\n")
+	first := true
+	write := func(kv expvar.KeyValue) {
+		if !first {
+			fmt.Fprint(w, ",\n")
+		}
+		first = false
+		fmt.Fprintf(w, "%q: %s", kv.Key, kv.Value)
+	}
+	expvar.Do(write)
+	for _, key := range slices.Sorted(maps.Keys(c.gauges)) {
+		write(expvar.KeyValue{Key: key, Value: c.gauges[key]})
+	}
+	fmt.Fprint(w, "\n}\n")
+}
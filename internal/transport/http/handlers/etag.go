@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	json "github.com/mailru/easyjson"
+)
+
+// bodyETag is a strong tag of the exact bytes of body
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// writeETagged answers 200 with body and etag, or 304 without a body when If-None-Match holds etag
+func writeETagged(w http.ResponseWriter, r *http.Request, etag string, body []byte) {
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// writeRevalidated answers a user's own view of posts with a weak ETag, the client keeps the body but asks
+// every time, a matching If-None-Match gets 304 and nothing is sent
+func writeRevalidated(w http.ResponseWriter, r *http.Request, v json.Marshaler) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	writeETagged(w, r, "W/"+bodyETag(body), body)
+}
+
+// etagMatches reports whether the If-None-Match header lists the tag, weak tags compare equal to strong ones
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type HealthService interface {
+	Readiness(ctx context.Context) (*dto.HealthResponse, bool)
+}
+
+type HealthController struct {
+	service HealthService
+}
+
+func NewHealthController(service HealthService) *HealthController {
+	return &HealthController{service}
+}
+
+// LivenessHandler answers 200 as long as the process is able to serve requests
+func (c *HealthController) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.HealthResponse{Status: "ok"}, w)
+}
+
+// ReadinessHandler answers 503 with the failing dependencies when postgres or storage are unreachable
+func (c *HealthController) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	resp, ready := c.service.Readiness(r.Context())
+	if ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) Readiness(ctx context.Context) (*dto.HealthResponse, bool) {
+	args := m.Called(ctx)
+	return args.Get(0).(*dto.HealthResponse), args.Bool(1)
+}
+
+func TestHealthController_LivenessHandler(t *testing.T) {
+	mockService := &MockHealthService{}
+	controller := NewHealthController(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+	controller.LivenessHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+	mockService.AssertNotCalled(t, "Readiness", mock.Anything)
+}
+
+func TestHealthController_ReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       *dto.HealthResponse
+		ready          bool
+		expectedStatus int
+	}{
+		{
+			name: "all dependencies healthy",
+			response: &dto.HealthResponse{
+				Status: "ok",
+				Checks: map[string]string{"postgres": "ok", "minio": "ok"},
+			},
+			ready:          true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "storage unreachable",
+			response: &dto.HealthResponse{
+				Status: "degraded",
+				Checks: map[string]string{"postgres": "ok", "minio": "unavailable"},
+			},
+			ready:          false,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockHealthService{}
+			mockService.On("Readiness", mock.Anything).Return(tt.response, tt.ready)
+			controller := NewHealthController(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rr := httptest.NewRecorder()
+			controller.ReadinessHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+
+			var resp dto.HealthResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, *tt.response, resp)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+type MediaStorage interface {
+	GetImage(objectName string) (io.ReadCloser, string, error)
+	VerifyLink(objectName string, query url.Values) bool
+}
+
+// MediaController serves images kept on the local filesystem, links to a private storage must be signed
+type MediaController struct {
+	storage MediaStorage
+}
+
+func NewMediaController(storage MediaStorage) *MediaController {
+	return &MediaController{storage}
+}
+
+// FileHandler serves /media/{objectName}, a private storage also wants the expires and signature of a presigned link
+func (c *MediaController) FileHandler(w http.ResponseWriter, r *http.Request) {
+	objectName := r.PathValue("objectName")
+	if !c.storage.VerifyLink(objectName, r.URL.Query()) {
+		http.Error(w, errors.ErrorHttpAccessDenied.Error(), http.StatusForbidden)
+		return
+	}
+
+	file, contentType, err := c.storage.GetImage(objectName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, errors.ErrorHttpImageNotFound.Error(), http.StatusNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		// ranges and conditional requests come for free with a seekable file
+		http.ServeContent(w, r, objectName, time.Time{}, seeker)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
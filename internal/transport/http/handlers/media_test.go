@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMediaStorage struct {
+	files map[string]string
+	// signature is the only one VerifyLink accepts, empty accepts every request
+	signature string
+}
+
+func (s *fakeMediaStorage) GetImage(objectName string) (io.ReadCloser, string, error) {
+	data, ok := s.files[objectName]
+	if !ok {
+		return nil, "", os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), "image/png", nil
+}
+
+func (s *fakeMediaStorage) VerifyLink(objectName string, query url.Values) bool {
+	return s.signature == "" || query.Get("signature") == s.signature
+}
+
+func TestMediaController_FileHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		signature      string
+		objectName     string
+		query          string
+		expectedStatus int
+	}{
+		{name: "public file", objectName: "pic.png", expectedStatus: http.StatusOK},
+		{name: "signed link", signature: "good", objectName: "pic.png", query: "?signature=good", expectedStatus: http.StatusOK},
+		{name: "bad signature", signature: "good", objectName: "pic.png", query: "?signature=bad", expectedStatus: http.StatusForbidden},
+		{name: "unsigned private file", signature: "good", objectName: "pic.png", expectedStatus: http.StatusForbidden},
+		{name: "missing file", objectName: "gone.png", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewMediaController(&fakeMediaStorage{files: map[string]string{"pic.png": "png"}, signature: tt.signature})
+
+			req := httptest.NewRequest(http.MethodGet, "/media/"+tt.objectName+tt.query, nil)
+			req.SetPathValue("objectName", tt.objectName)
+
+			rr := httptest.NewRecorder()
+			controller.FileHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+			if rr.Code == http.StatusOK {
+				assert.Equal(t, "png", rr.Body.String())
+				assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -1,25 +1,52 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	json "github.com/mailru/easyjson"
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/apiversion"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
 	"github.com/xkarasb/blog/pkg/types"
 	"github.com/xkarasb/blog/pkg/utils"
 )
 
 type PosterService interface {
-	EditPost(userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error)
-	PublishPost(userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error)
-	AddImage(userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error)
-	DeleteImage(userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error)
+	EditPost(ctx context.Context, userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error)
+	PatchPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PatchPostRequest) (*dto.EditPostResponse, error)
+	PublishPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error)
+	AddImage(ctx context.Context, userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error)
+	AddImages(ctx context.Context, userId, postId uuid.UUID, files []*multipart.FileHeader) ([]dto.ImageUpload, error)
+	DeleteImage(ctx context.Context, userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error)
+	GetPostImages(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostImagesResponse, error)
+	UpdateImage(ctx context.Context, userId, postId, imageId uuid.UUID, req *dto.UpdateImageRequest) (*dto.ImageResponse, error)
+	ReplaceImage(ctx context.Context, userId, postId, imageId uuid.UUID, file io.Reader, fileHeader *multipart.FileHeader) (*dto.ImageResponse, error)
+	ReorderImages(ctx context.Context, userId, postId uuid.UUID, req *dto.ReorderImagesRequest) (*dto.GetPostImagesResponse, error)
+	TrashPost(ctx context.Context, userId, postId uuid.UUID) (*dto.TrashPostResponse, error)
+	RestorePost(ctx context.Context, userId, postId uuid.UUID) (*dto.RestorePostResponse, error)
+	GetPostStats(ctx context.Context, userId, postId uuid.UUID) (*dto.PostStatsResponse, error)
+	GetPostRevisions(ctx context.Context, userId, postId uuid.UUID, limit, offset int) (*dto.GetPostRevisionsResponse, error)
+	RestoreRevision(ctx context.Context, userId, postId, revisionId uuid.UUID) (*dto.EditPostResponse, error)
+	ExportPosts(ctx context.Context, authorId uuid.UUID, w io.Writer) error
+	ExportPostsArchive(ctx context.Context, authorId uuid.UUID, w io.Writer) error
+	ImportPosts(ctx context.Context, authorId uuid.UUID, file io.ReaderAt, size int64) ([]dto.PostImport, error)
+	CreatePreviewToken(ctx context.Context, authorId, postId uuid.UUID) (*dto.PreviewTokenResponse, error)
+	StorageQuota(ctx context.Context, userId uuid.UUID) (*dto.StorageQuotaResponse, error)
+	RevokePreviewTokens(ctx context.Context, authorId, postId uuid.UUID) (*dto.RevokePreviewTokensResponse, error)
 }
 
+// multipartMemory is how much of an upload is held in memory, the rest goes to temporary files like with FormFile
+const multipartMemory = 32 << 20
+
 type PosterController struct {
 	service PosterService
 }
@@ -28,107 +55,340 @@ func NewPosterController(service PosterService) *PosterController {
 	return &PosterController{service}
 }
 
-// @Description	Add Image
+// @Description	Add an image, or several at once under "images". A bulk upload answers with a result per file,
+// @Description	201 when all of them were added and 207 when some were not, each of those with its error.
 // @Tags			Poster
 // @Accept			mpfd
 // @Produce		json
 // @Security		BearerAuth
 // @Param			postId	path		string	true	"Post ID"	format(uuid)
-// @Param			image	formData	file	true	"Image"
+// @Param			image	formData	file	false	"Image"
+// @Param			images	formData	[]file	false	"Images of a bulk upload"	collectionFormat(multi)
 // @Success		201		{object}	dto.AddImageResponse
-// @Failure		400		"Incorrect body\nRefresh token expired or incorrect"
-// @Failure		403		"Access denied"
-// @Failure		404		"Post not found"
-// @Router			/post/{postId}/images [post]“
+// @Header			201		{string}	Location	"URL of the image, not set for a bulk upload"
+// @Success		207		{object}	dto.AddImagesResponse	"Bulk upload, some files were not added"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Failure		409		{object}	dto.ErrorResponse	"Post already has IMAGES_PER_POST images"
+// @Failure		413		{object}	dto.QuotaExceededResponse	"Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES, or past STORAGE_QUOTA_BYTES with the quota left"
+// @Failure		415		{object}	dto.ErrorResponse	"Image must be jpeg, png, gif or webp"
+// @Failure		502		{object}	dto.ErrorResponse	"Storage timeout"
+// @Router			/posts/{postId}/images [post]“
 func (c *PosterController) AddImageHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
 		return
 	}
 
 	postId, err := uuid.Parse(r.PathValue("postId"))
 
 	if err != nil {
-		http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		if tooLarge(err) {
+			bodyError(w, err)
+			return
+		}
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+	if files := r.MultipartForm.File["images"]; len(files) > 0 {
+		c.addImages(w, r, user.UserId, postId, files)
 		return
 	}
 	file, fileHeader, err := r.FormFile("image")
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		jsonError(w, http.StatusBadGateway, err)
 		return
 	}
 
-	resp, err := c.service.AddImage(user.UserId, postId, file, fileHeader)
+	resp, err := c.service.AddImage(ctx, user.UserId, postId, file, fileHeader)
 
+	if err == errors.ErrorQuotaExceeded {
+		c.quotaExceeded(w, r, user.UserId)
+		return
+	}
 	if err != nil {
-		switch err {
-		case errors.ErrorServiceNoAccess:
-			http.Error(w, errors.ErrorHttpAccessDenied.Error(), http.StatusForbidden)
-		case errors.ErrorServiceIncorrectData:
-			http.Error(w, errors.ErrorHttpIncorrectStatus.Error(), http.StatusBadRequest)
-		case sql.ErrNoRows:
-			http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
-		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
-		}
+		addImageError(w, r, err)
 		return
 	}
 
+	w.Header().Set("Location", fmt.Sprintf("%s/posts/%s/images/%s", apiversion.FromContext(ctx).Prefix(), postId, resp.ImageId))
 	w.WriteHeader(http.StatusCreated)
 	json.MarshalToHTTPResponseWriter(resp, w)
 }
 
+// addImages answers a bulk upload with the result of every file, 201 when all were added and 207 otherwise
+func (c *PosterController) addImages(w http.ResponseWriter, r *http.Request, userId, postId uuid.UUID, files []*multipart.FileHeader) {
+	uploads, err := c.service.AddImages(r.Context(), userId, postId, files)
+	if err != nil {
+		addImageError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	res := make(dto.AddImagesResponse, len(uploads))
+	for i, upload := range uploads {
+		res[i].Filename = upload.Filename
+		if upload.Err != nil {
+			status = http.StatusMultiStatus
+			res[i].Error = uploadError(r, upload.Err).Error()
+			continue
+		}
+		res[i].ImageId, res[i].ImageUrl = &upload.Image.ImageId, upload.Image.ImageUrl
+	}
+	w.WriteHeader(status)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		Replace image
+// @Description	Put a new file in place of an image of your post, keeping its id, position and caption. A file of the same type
+// @Description	keeps the URL too, public URLs get ?v= with the time of the change so caches fetch the new file.
+// @Tags			Poster
+// @Accept			mpfd
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Param			imageId	path		string	true	"Image ID"	format(uuid)
+// @Param			image	formData	file	true	"Image"
+// @Success		200		{object}	dto.ImageResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post/Image not found"
+// @Failure		413		{object}	dto.QuotaExceededResponse	"Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES, or past STORAGE_QUOTA_BYTES with the quota left"
+// @Failure		415		{object}	dto.ErrorResponse	"Image must be jpeg, png, gif or webp"
+// @Failure		502		{object}	dto.ErrorResponse	"Storage timeout"
+// @Router			/posts/{postId}/images/{imageId} [put]
+func (c *PosterController) ReplaceImageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+	imageId, err := uuid.Parse(r.PathValue("imageId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
+		return
+	}
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		if tooLarge(err) {
+			bodyError(w, err)
+			return
+		}
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+	file, fileHeader, err := r.FormFile("image")
+	if err != nil {
+		jsonError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	resp, err := c.service.ReplaceImage(ctx, user.UserId, postId, imageId, file, fileHeader)
+	if err == errors.ErrorServiceImageNotFound {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
+		return
+	}
+	if err == errors.ErrorQuotaExceeded {
+		c.quotaExceeded(w, r, user.UserId)
+		return
+	}
+	if err != nil {
+		addImageError(w, r, err)
+		return
+	}
+
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// addImageError answers an upload the service refused as a whole
+func addImageError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case errors.ErrorServiceNoAccess:
+		jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
+	case errors.ErrorServiceUnsupportedImage:
+		jsonError(w, http.StatusUnsupportedMediaType, errors.ErrorHttpUnsupportedImage)
+	case errors.ErrorServiceImageTooLarge:
+		jsonError(w, http.StatusRequestEntityTooLarge, errors.ErrorHttpImageTooLarge)
+	case errors.ErrorServiceImageLimit:
+		jsonError(w, http.StatusConflict, errors.ErrorHttpImageLimit)
+	case errors.ErrorServiceIncorrectData:
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
+	case sql.ErrNoRows:
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+	default:
+		serviceError(w, r, err)
+	}
+}
+
+// quotaExceeded answers an upload past the storage quota of the user with how much of it is left
+func (c *PosterController) quotaExceeded(w http.ResponseWriter, r *http.Request, userId uuid.UUID) {
+	quota, err := c.service.StorageQuota(r.Context(), userId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.MarshalToHTTPResponseWriter(&dto.QuotaExceededResponse{
+		Error:          errors.ErrorHttpQuotaExceeded.Error(),
+		Code:           dto.CodeQuotaExceeded,
+		UsedBytes:      quota.UsedBytes,
+		LimitBytes:     quota.LimitBytes,
+		RemainingBytes: max(quota.LimitBytes-quota.UsedBytes, 0),
+	}, w)
+}
+
+// @Summary		Storage quota
+// @Description	Bytes of images you store over all your posts and the most you may, limit_bytes is 0 without a quota.
+// @Description	Deleting images or purging trashed posts frees their bytes.
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.StorageQuotaResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/users/me/quota [get]
+func (c *PosterController) QuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	resp, err := c.service.StorageQuota(ctx, user.UserId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// uploadError is what the client learns about a file of a bulk upload that was not added, errors it
+// is not meant to see are logged like serviceError does
+func uploadError(r *http.Request, err error) error {
+	switch {
+	case err == errors.ErrorServiceUnsupportedImage:
+		return errors.ErrorHttpUnsupportedImage
+	case err == errors.ErrorServiceImageTooLarge:
+		return errors.ErrorHttpImageTooLarge
+	case err == errors.ErrorServiceImageLimit:
+		return errors.ErrorHttpImageLimit
+	case err == errors.ErrorQuotaExceeded:
+		return errors.ErrorHttpQuotaExceeded
+	case errors.Is(err, errors.ErrorRepositoryStorageTimeout):
+		slogctx.Logger(r.Context()).Warn("image storage", slog.String("error", err.Error()))
+		return errors.ErrorHttpStorageTimeout
+	default:
+		slogctx.Logger(r.Context()).Error("add image", slog.String("error", err.Error()))
+		return errors.ErrorHttpInternal
+	}
+}
+
 // @Description	Edit post
 // @Tags			Poster
 // @Accept			json
 // @Produce		json
 // @Security		BearerAuth
 // @Param			request	body		dto.EditPostRequest	true	"Edit post data"
+// @Param			Idempotency-Key	header	string	false	"Replays the first response when sent again"
 // @Param			postId	path		string				true	"Post ID"	format(uuid)
 // @Success		200		{object}	dto.EditPostResponse
-// @Failure		400		"Incorrect body\nRefresh token expired or incorrect"
-// @Failure		403		"Access denied"
-// @Failure		404		"Post not found"
-// @Router			/post/{postId} [put]“
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Failure		409		{object}	dto.EditPostResponse	"Post changed since expected_updated_at, the body is the current post"
+// @Router			/posts/{postId} [put]“
 func (c *PosterController) EditPostHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
+		return
+	}
+	reqPost, err := bind(r, func(req *dto.EditPostRequest) { req.Tags = utils.NormalizeTags(req.Tags) })
+	if err != nil {
+		bodyError(w, err)
 		return
 	}
-	reqPost := &dto.EditPostRequest{}
-	if err := json.UnmarshalFromReader(r.Body, reqPost); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		return
 	}
 
-	if err := utils.Validate(reqPost); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	resPost, err := c.service.EditPost(ctx, user.UserId, postId, reqPost)
+	writeEdit(w, r, resPost, err)
+}
+
+// @Description	Edit some fields of a post, the ones left out keep their value
+// @Tags			Poster
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.PatchPostRequest	true	"Fields to change"
+// @Param			Idempotency-Key	header	string	false	"Replays the first response when sent again"
+// @Param			postId	path		string					true	"Post ID"	format(uuid)
+// @Success		201		{object}	dto.EditPostResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Failure		409		{object}	dto.EditPostResponse	"Post changed since expected_updated_at, the body is the current post"
+// @Router			/posts/{postId} [patch]
+func (c *PosterController) PatchPostHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+	reqPost, err := bind(r, func(req *dto.PatchPostRequest) { req.Tags = utils.NormalizeTags(req.Tags) })
+	if err != nil {
+		bodyError(w, err)
 		return
 	}
 
 	postId, err := uuid.Parse(r.PathValue("postId"))
 
 	if err != nil {
-		http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		return
 	}
 
-	resPost, err := c.service.EditPost(user.UserId, postId, reqPost)
+	resPost, err := c.service.PatchPost(ctx, user.UserId, postId, reqPost)
+	writeEdit(w, r, resPost, err)
+}
+
+// writeEdit answers an edit of a post, a conflict carries the current post for the client to merge
+func writeEdit(w http.ResponseWriter, r *http.Request, resPost *dto.EditPostResponse, err error) {
 	if err != nil {
 		switch err {
+		case errors.ErrorServiceConflict:
+			w.WriteHeader(http.StatusConflict)
+			json.MarshalToHTTPResponseWriter(resPost, w)
 		case errors.ErrorServiceNoAccess:
-			http.Error(w, errors.ErrorHttpAccessDenied.Error(), http.StatusForbidden)
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
 		case errors.ErrorServiceIncorrectData:
-			http.Error(w, errors.ErrorHttpIncorrectStatus.Error(), http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
 		case sql.ErrNoRows:
-			http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
@@ -143,43 +403,46 @@ func (c *PosterController) EditPostHandler(w http.ResponseWriter, r *http.Reques
 // @Param			postId	path		string	true	"Post ID"	format(uuid)
 // @Param			imageId	path		string	true	"Image ID"	format(uuid)
 // @Success		201		{object}	dto.DeleteImageResponse
-// @Failure		400		"Incorrect body\nRefresh token expired or incorrect"
-// @Failure		403		"Access denied"
-// @Failure		404		"Post/Image not found"
-// @Router			/post/{postId}/images/{imageId} [delete]“
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post/Image not found"
+// @Failure		502		{object}	dto.ErrorResponse	"Storage timeout"
+// @Router			/posts/{postId}/images/{imageId} [delete]“
 func (c *PosterController) DeleteImageHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
 		return
 	}
 
 	postId, err := uuid.Parse(r.PathValue("postId"))
 
 	if err != nil {
-		http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		return
 	}
 	imageId, err := uuid.Parse(r.PathValue("imageId"))
 
 	if err != nil {
-		http.Error(w, errors.ErrorHttpImageNotFound.Error(), http.StatusNotFound)
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
 		return
 	}
 
-	resp, err := c.service.DeleteImage(user.UserId, postId, imageId)
+	resp, err := c.service.DeleteImage(ctx, user.UserId, postId, imageId)
 
 	if err != nil {
 		switch err {
 		case errors.ErrorServiceNoAccess:
-			http.Error(w, errors.ErrorHttpAccessDenied.Error(), http.StatusForbidden)
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
 		case errors.ErrorServiceIncorrectData:
-			http.Error(w, errors.ErrorHttpIncorrectStatus.Error(), http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
 		case sql.ErrNoRows:
-			http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		case errors.ErrorServiceImageNotFound:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
 		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
@@ -188,60 +451,476 @@ func (c *PosterController) DeleteImageHandler(w http.ResponseWriter, r *http.Req
 	json.MarshalToHTTPResponseWriter(resp, w)
 }
 
-// @Summary		Publicate post
-// @Description	Publish post
+// @Summary		Update image
+// @Description	Change caption and position of an image of your post
 // @Tags			Poster
 // @Accept			json
 // @Produce		json
 // @Security		BearerAuth
-// @Param			request	body		dto.PublishPostRequest	true	"Publish post data"
+// @Param			request	body		dto.UpdateImageRequest	true	"Fields to change"
 // @Param			postId	path		string					true	"Post ID"	format(uuid)
-// @Success		200		{object}	dto.EditPostResponse
-// @Failure		400		"Incorrect body\nRefresh token expired or incorrect"
-// @Failure		403		"Access denied"
-// @Failure		404		"Post not found"
-// @Router			/post/{postId}/status [patch]“
+// @Param			imageId	path		string					true	"Image ID"	format(uuid)
+// @Success		200		{object}	dto.ImageResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nCaption longer than 500 characters or position below 1"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post/Image not found"
+// @Router			/posts/{postId}/images/{imageId} [patch]
+func (c *PosterController) UpdateImageHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := bind[dto.UpdateImageRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+	imageId, err := uuid.Parse(r.PathValue("imageId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
+		return
+	}
+
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.UpdateImage(r.Context(), userId, postId, imageId, req)
+	})
+}
+
+// @Summary		Reorder images
+// @Description	Put the images of your post in the order given, in one go. The list names every image of the post exactly once.
+// @Tags			Poster
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.ReorderImagesRequest	true	"Image IDs in their new order"
+// @Param			postId	path		string						true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.GetPostImagesResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nimage_ids must list every image of the post exactly once"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/images/order [put]
+func (c *PosterController) ReorderImagesHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := bind[dto.ReorderImagesRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.ReorderImages(r.Context(), userId, postId, req)
+	})
+}
+
+// missingUser answers a request that reached a handler without the user AuthMiddleware stores,
+// which only a route registered without it can cause
+func missingUser(w http.ResponseWriter, r *http.Request) {
+	slogctx.Logger(r.Context()).Error("no user in request context", slog.String("endpoint", r.URL.Path))
+	jsonError(w, http.StatusInternalServerError, errors.ErrorHttpInternal)
+}
+
+// jsonError answers with err as a dto.ErrorResponse, like the middlewares do
+func jsonError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.MarshalToHTTPResponseWriter(&dto.ErrorResponse{Error: err.Error()}, w)
+}
+
+// codedError is a jsonError with a code clients branch on instead of the message
+func codedError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.MarshalToHTTPResponseWriter(&dto.ErrorResponse{Error: err.Error(), Code: code}, w)
+}
+
+// serviceError answers a request the service failed for a reason the handler does not map itself.
+// A request whose deadline of the Timeout middleware passed gets 504, whatever call noticed it first.
+// Otherwise a storage operation cut short by the client leaving or by MINIO_OP_TIMEOUT gets 502 and
+// a query cut short by POSTGRES_QUERY_TIMEOUT gets 504, all are logged since the raw error is not shown.
+func serviceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case r.Context().Err() == context.DeadlineExceeded:
+		slogctx.Logger(r.Context()).Warn("request timed out", slog.String("error", err.Error()))
+		jsonError(w, http.StatusGatewayTimeout, errors.ErrorHttpRequestTimeout)
+	case errors.Is(err, errors.ErrorRepositoryStorageTimeout):
+		slogctx.Logger(r.Context()).Warn("image storage", slog.String("error", err.Error()))
+		jsonError(w, http.StatusBadGateway, errors.ErrorHttpStorageTimeout)
+	case errors.Is(err, errors.ErrorRepositoryQueryTimeout):
+		slogctx.Logger(r.Context()).Warn("database query", slog.String("error", err.Error()))
+		jsonError(w, http.StatusGatewayTimeout, errors.ErrorHttpQueryTimeout)
+	default:
+		jsonError(w, http.StatusBadGateway, err)
+	}
+}
+
+// @Summary		Change post status
+// @Description	Publish a draft, take a published post back to draft or archive it, archived posts can only return to draft
+// @Tags			Poster
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.PublishPostRequest	true	"Target status"
+// @Param			Idempotency-Key	header	string	false	"Replays the first response when sent again"
+// @Param			postId	path		string					true	"Post ID"	format(uuid)
+// @Success		201		{object}	dto.PublishPostResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nIncorrect status"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied\nEmail not verified, code email_not_verified"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/status [patch]“
 func (c *PosterController) PublishHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
+		return
+	}
+	reqPost, err := bind[dto.PublishPostRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	resPost, err := c.service.PublishPost(r.Context(), user.UserId, postId, reqPost)
+
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceNoAccess:
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
+		case errors.ErrorServiceIncorrectData, errors.ErrorRepositoryBadStatus:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectStatus)
+		case errors.ErrorEmailNotVerified:
+			codedError(w, http.StatusForbidden, dto.CodeEmailNotVerified, errors.ErrorHttpEmailNotVerified)
+		case sql.ErrNoRows:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.MarshalToHTTPResponseWriter(resPost, w)
+}
+
+// @Summary		Delete post
+// @Description	Move a post to the trash, it disappears from every listing and can be restored until purge_at
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.TrashPostResponse
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId} [delete]
+func (c *PosterController) TrashPostHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.TrashPost(r.Context(), userId, postId)
+	})
+}
+
+// @Summary		Restore post
+// @Description	Take a post back from the trash with the status it had, past the retention window the post is gone
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.RestorePostResponse
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/restore [post]
+func (c *PosterController) RestorePostHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.RestorePost(r.Context(), userId, postId)
+	})
+}
+
+// @Summary		Create preview link
+// @Description	Make a link anyone can read your post at without signing in, drafts included, until it expires or is revoked.
+// @Description	The token is shown only this once, every call makes another link.
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.PreviewTokenResponse
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/preview-token [post]
+func (c *PosterController) CreatePreviewTokenHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		preview, err := c.service.CreatePreviewToken(r.Context(), userId, postId)
+		if err != nil {
+			return nil, err
+		}
+		preview.Url = apiversion.FromContext(r.Context()).Prefix() + "/public/preview/" + preview.Token
+		return preview, nil
+	})
+}
+
+// @Summary		Revoke preview links
+// @Description	Stop every preview link of your post
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.RevokePreviewTokensResponse
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/preview-token [delete]
+func (c *PosterController) RevokePreviewTokensHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.RevokePreviewTokens(r.Context(), userId, postId)
+	})
+}
+
+// @Summary		Post images
+// @Description	Images of a post in their order. Readers see the images of published posts, a draft is found only by its author
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.GetPostImagesResponse
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/images [get]
+func (c *PosterController) ImagesHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.GetPostImages(r.Context(), userId, postId)
+	})
+}
+
+// @Summary		Post statistics
+// @Description	Views of your post per day, every reader counts once per day
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.PostStatsResponse
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/stats [get]
+func (c *PosterController) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.GetPostStats(r.Context(), userId, postId)
+	})
+}
+
+// @Summary		Post revisions
+// @Description	Earlier versions of your post, every edit and status change keeps the version it replaced, latest first
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"			format(uuid)
+// @Param			limit	query		int		false	"Page size, 1-100"	default(20)
+// @Param			offset	query		int		false	"Revisions to skip"	default(0)
+// @Success		200		{object}	dto.GetPostRevisionsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/revisions [get]
+func (c *PosterController) RevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.GetPostRevisions(r.Context(), userId, postId, limit, offset)
+	})
+}
+
+// @Summary		Restore revision
+// @Description	Copy title and content of a revision back into your post, the status and tags stay as they are and the replaced version becomes a new revision
+// @Tags			Poster
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId		path		string	true	"Post ID"		format(uuid)
+// @Param			revisionId	path		string	true	"Revision ID"	format(uuid)
+// @Success		200			{object}	dto.EditPostResponse
+// @Failure		403			{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404			{object}	dto.ErrorResponse	"Post not found\nRevision not found"
+// @Router			/posts/{postId}/revisions/{revisionId}/restore [post]
+func (c *PosterController) RestoreRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	revisionId, err := uuid.Parse(r.PathValue("revisionId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpRevisionNotFound)
+		return
+	}
+
+	c.handlePost(w, r, func(userId, postId uuid.UUID) (json.Marshaler, error) {
+		return c.service.RestoreRevision(r.Context(), userId, postId, revisionId)
+	})
+}
+
+// @Summary		Export posts
+// @Description	All your posts that are not in the trash with their images, as one JSON document or with format=zip as a zip archive
+// @Description	holding a directory per post with index.md, the content behind a YAML front matter, and the files of its images.
+// @Description	Both are streamed, an export failing on the way is cut off instead of ending normally.
+// @Tags			Poster
+// @Produce		json
+// @Produce		application/zip
+// @Security		BearerAuth
+// @Param			format	query		string	false	"json (default) or zip"	Enums(json, zip)
+// @Success		200		{object}	dto.PostsExport
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Router			/posts/export [get]
+func (c *PosterController) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
 		return
 	}
-	reqPost := &dto.PublishPostRequest{}
-	if err := json.UnmarshalFromReader(r.Body, reqPost); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
+
+	export, contentType, ext := c.service.ExportPosts, "application/json", "json"
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+	case "zip":
+		export, contentType, ext = c.service.ExportPostsArchive, "application/zip", "zip"
+	default:
+		jsonError(w, http.StatusBadRequest, fmt.Errorf("%w: format must be json or zip", errors.ErrorHttpIncorrectQuery))
 		return
 	}
 
-	if err := utils.Validate(reqPost); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="posts-%s.%s"`, time.Now().UTC().Format(time.DateOnly), ext))
+	ew := &exportWriter{ResponseWriter: w}
+	err := export(ctx, user.UserId, ew)
+	if err == nil {
+		return
+	}
+	if ew.wrote {
+		// the status is sent already, a client must not take what it got for a whole export
+		slogctx.Logger(ctx).Error("export cut off", slog.String("error", err.Error()))
+		panic(http.ErrAbortHandler)
+	}
+	w.Header().Del("Content-Disposition")
+	serviceError(w, r, err)
+}
+
+// exportWriter remembers whether an export has started, an error after that can only cut it off
+type exportWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(p)
+}
+
+// handlePost runs an action on a post of the current user and answers 200 with its result
+func (c *PosterController) handlePost(w http.ResponseWriter, r *http.Request, call func(userId, postId uuid.UUID) (json.Marshaler, error)) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
 		return
 	}
 
 	postId, err := uuid.Parse(r.PathValue("postId"))
 
 	if err != nil {
-		http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		return
 	}
 
-	resPost, err := c.service.PublishPost(user.UserId, postId, reqPost)
+	resp, err := call(user.UserId, postId)
 
 	if err != nil {
 		switch err {
 		case errors.ErrorServiceNoAccess:
-			http.Error(w, errors.ErrorHttpAccessDenied.Error(), http.StatusForbidden)
-		case errors.ErrorServiceIncorrectData:
-			http.Error(w, errors.ErrorHttpIncorrectStatus.Error(), http.StatusBadRequest)
+			jsonError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
 		case sql.ErrNoRows:
-			http.Error(w, errors.ErrorHttpPostNotFound.Error(), http.StatusNotFound)
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		case errors.ErrorServiceRevisionNotFound:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpRevisionNotFound)
+		case errors.ErrorServiceImageNotFound:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpImageNotFound)
+		case errors.ErrorServiceImageOrder:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpImageOrder)
 		default:
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.MarshalToHTTPResponseWriter(resPost, w)
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(resp, w)
+}
+
+// @Summary		Import posts
+// @Description	Add the posts of an export, the JSON document or the zip archive of GET /posts/export, as drafts of yours.
+// @Description	Each post is added on its own: a malformed one, one with the title and content of a post you have or one
+// @Description	failing to be stored is skipped and listed with its error. Images of a zip are uploaded again and their
+// @Description	links in the content point to the new ones, a JSON export carries no image files and keeps its links.
+// @Tags			Poster
+// @Accept			mpfd
+// @Produce		json
+// @Security		BearerAuth
+// @Param			file	formData	file	true	"Export as JSON or zip"
+// @Success		200		{object}	dto.ImportPostsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"File must be a posts export, JSON or zip"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		413		{object}	dto.ErrorResponse	"Upload larger than MAX_IMPORT_BYTES"
+// @Router			/posts/import [post]
+func (c *PosterController) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		if tooLarge(err) {
+			bodyError(w, err)
+			return
+		}
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectBody)
+		return
+	}
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, fmt.Errorf("%w: file is required", errors.ErrorHttpIncorrectBody))
+		return
+	}
+	defer file.Close()
+
+	imports, err := c.service.ImportPosts(ctx, user.UserId, file, fileHeader.Size)
+	if err == errors.ErrorServiceImportFormat {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpImportFormat)
+		return
+	}
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	res := &dto.ImportPostsResponse{Errors: []dto.ImportError{}}
+	for _, imported := range imports {
+		if imported.Err == nil {
+			res.Imported++
+			continue
+		}
+		res.Skipped++
+		res.Errors = append(res.Errors, dto.ImportError{Entry: imported.Entry, Error: importError(r, imported.Err).Error()})
+	}
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// importError is what the client learns about a post of an import that was skipped
+func importError(r *http.Request, err error) error {
+	switch {
+	case errors.Is(err, errors.ErrorServiceImportEntry):
+		return err
+	case err == errors.ErrorServiceImportDuplicate:
+		return errors.ErrorHttpImportDuplicate
+	case errors.Is(err, errors.ErrorRepositoryQueryTimeout):
+		slogctx.Logger(r.Context()).Warn("database query", slog.String("error", err.Error()))
+		return errors.ErrorHttpQueryTimeout
+	default:
+		return uploadError(r, err)
+	}
 }
@@ -6,16 +6,21 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/apiversion"
 	"github.com/xkarasb/blog/pkg/errors"
 	"github.com/xkarasb/blog/pkg/types"
 )
@@ -24,7 +29,7 @@ type MockPosterService struct {
 	mock.Mock
 }
 
-func (m *MockPosterService) EditPost(userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error) {
+func (m *MockPosterService) EditPost(ctx context.Context, userId, postId uuid.UUID, post *dto.EditPostRequest) (*dto.EditPostResponse, error) {
 	args := m.Called(userId, postId, post)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -32,7 +37,15 @@ func (m *MockPosterService) EditPost(userId, postId uuid.UUID, post *dto.EditPos
 	return args.Get(0).(*dto.EditPostResponse), args.Error(1)
 }
 
-func (m *MockPosterService) PublishPost(userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error) {
+func (m *MockPosterService) PatchPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PatchPostRequest) (*dto.EditPostResponse, error) {
+	args := m.Called(userId, postId, post)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EditPostResponse), args.Error(1)
+}
+
+func (m *MockPosterService) PublishPost(ctx context.Context, userId, postId uuid.UUID, post *dto.PublishPostRequest) (*dto.PublishPostResponse, error) {
 	args := m.Called(userId, postId, post)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -40,7 +53,7 @@ func (m *MockPosterService) PublishPost(userId, postId uuid.UUID, post *dto.Publ
 	return args.Get(0).(*dto.PublishPostResponse), args.Error(1)
 }
 
-func (m *MockPosterService) AddImage(userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error) {
+func (m *MockPosterService) AddImage(ctx context.Context, userId, postId uuid.UUID, file multipart.File, fileHeader *multipart.FileHeader) (*dto.AddImageResponse, error) {
 	args := m.Called(userId, postId, file, fileHeader)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -48,7 +61,39 @@ func (m *MockPosterService) AddImage(userId, postId uuid.UUID, file multipart.Fi
 	return args.Get(0).(*dto.AddImageResponse), args.Error(1)
 }
 
-func (m *MockPosterService) DeleteImage(userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error) {
+func (m *MockPosterService) AddImages(ctx context.Context, userId, postId uuid.UUID, files []*multipart.FileHeader) ([]dto.ImageUpload, error) {
+	args := m.Called(userId, postId, files)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.ImageUpload), args.Error(1)
+}
+
+func (m *MockPosterService) ReplaceImage(ctx context.Context, userId, postId, imageId uuid.UUID, file io.Reader, fileHeader *multipart.FileHeader) (*dto.ImageResponse, error) {
+	args := m.Called(userId, postId, imageId, file, fileHeader)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ImageResponse), args.Error(1)
+}
+
+func (m *MockPosterService) ExportPosts(ctx context.Context, authorId uuid.UUID, w io.Writer) error {
+	args := m.Called(authorId, w)
+	return args.Error(0)
+}
+
+func (m *MockPosterService) ExportPostsArchive(ctx context.Context, authorId uuid.UUID, w io.Writer) error {
+	args := m.Called(authorId, w)
+	return args.Error(0)
+}
+
+func (m *MockPosterService) ImportPosts(ctx context.Context, authorId uuid.UUID, file io.ReaderAt, size int64) ([]dto.PostImport, error) {
+	args := m.Called(authorId, size)
+	imports, _ := args.Get(0).([]dto.PostImport)
+	return imports, args.Error(1)
+}
+
+func (m *MockPosterService) DeleteImage(ctx context.Context, userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error) {
 	args := m.Called(userId, postId, imageId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -56,9 +101,98 @@ func (m *MockPosterService) DeleteImage(userId, postId, imageId uuid.UUID) (*dto
 	return args.Get(0).(*dto.DeleteImageResponse), args.Error(1)
 }
 
+func (m *MockPosterService) GetPostImages(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostImagesResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostImagesResponse), args.Error(1)
+}
+
+func (m *MockPosterService) UpdateImage(ctx context.Context, userId, postId, imageId uuid.UUID, req *dto.UpdateImageRequest) (*dto.ImageResponse, error) {
+	args := m.Called(userId, postId, imageId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ImageResponse), args.Error(1)
+}
+
+func (m *MockPosterService) ReorderImages(ctx context.Context, userId, postId uuid.UUID, req *dto.ReorderImagesRequest) (*dto.GetPostImagesResponse, error) {
+	args := m.Called(userId, postId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostImagesResponse), args.Error(1)
+}
+
+func (m *MockPosterService) TrashPost(ctx context.Context, userId, postId uuid.UUID) (*dto.TrashPostResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TrashPostResponse), args.Error(1)
+}
+
+func (m *MockPosterService) RestorePost(ctx context.Context, userId, postId uuid.UUID) (*dto.RestorePostResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RestorePostResponse), args.Error(1)
+}
+
+func (m *MockPosterService) CreatePreviewToken(ctx context.Context, authorId, postId uuid.UUID) (*dto.PreviewTokenResponse, error) {
+	args := m.Called(authorId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PreviewTokenResponse), args.Error(1)
+}
+
+func (m *MockPosterService) StorageQuota(ctx context.Context, userId uuid.UUID) (*dto.StorageQuotaResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.StorageQuotaResponse), args.Error(1)
+}
+
+func (m *MockPosterService) RevokePreviewTokens(ctx context.Context, authorId, postId uuid.UUID) (*dto.RevokePreviewTokensResponse, error) {
+	args := m.Called(authorId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RevokePreviewTokensResponse), args.Error(1)
+}
+
+func (m *MockPosterService) GetPostStats(ctx context.Context, userId, postId uuid.UUID) (*dto.PostStatsResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PostStatsResponse), args.Error(1)
+}
+
+func (m *MockPosterService) GetPostRevisions(ctx context.Context, userId, postId uuid.UUID, limit, offset int) (*dto.GetPostRevisionsResponse, error) {
+	args := m.Called(userId, postId, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostRevisionsResponse), args.Error(1)
+}
+
+func (m *MockPosterService) RestoreRevision(ctx context.Context, userId, postId, revisionId uuid.UUID) (*dto.EditPostResponse, error) {
+	args := m.Called(userId, postId, revisionId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EditPostResponse), args.Error(1)
+}
+
 func TestPosterController_EditPostHandler(t *testing.T) {
 	userId := uuid.New()
 	postId := uuid.New()
+	readAt := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
 	user := &dto.UserDB{UserId: userId, Role: types.Author}
 
 	tests := []struct {
@@ -70,6 +204,33 @@ func TestPosterController_EditPostHandler(t *testing.T) {
 		checkBody      func(*testing.T, string)
 		shouldCallMock bool
 	}{
+		{
+			name:   "stale edit",
+			postId: postId.String(),
+			requestBody: dto.EditPostRequest{
+				Title:             "Updated Title",
+				Content:           "Updated Content",
+				ExpectedUpdatedAt: &readAt,
+			},
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("EditPost", userId, parsedPostId, mock.MatchedBy(func(req *dto.EditPostRequest) bool {
+					return req.ExpectedUpdatedAt != nil && req.ExpectedUpdatedAt.Equal(readAt)
+				})).
+					Return(&dto.EditPostResponse{
+						PostId:    parsedPostId,
+						Title:     "Edited in another tab",
+						UpdatedAt: readAt.Add(time.Minute),
+					}, errors.ErrorServiceConflict)
+			},
+			expectedStatus: http.StatusConflict,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.EditPostResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, "Edited in another tab", resp.Title)
+				assert.True(t, readAt.Add(time.Minute).Equal(resp.UpdatedAt))
+			},
+		},
 		{
 			name:   "successful edit",
 			postId: postId.String(),
@@ -254,6 +415,94 @@ func TestPosterController_EditPostHandler(t *testing.T) {
 	}
 }
 
+func TestPosterController_PatchPostHandler(t *testing.T) {
+	userId := uuid.New()
+	postId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name: "title only",
+			body: `{"title":"New title"}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("PatchPost", userId, postId, mock.MatchedBy(func(req *dto.PatchPostRequest) bool {
+					return req.Title != nil && *req.Title == "New title" && req.Content == nil && req.Tags == nil
+				})).Return(&dto.EditPostResponse{PostId: postId, Title: "New title", Content: "kept"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "content only",
+			body: `{"content":"New content"}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("PatchPost", userId, postId, mock.MatchedBy(func(req *dto.PatchPostRequest) bool {
+					return req.Title == nil && req.Content != nil && *req.Content == "New content"
+				})).Return(&dto.EditPostResponse{PostId: postId, Title: "kept", Content: "New content"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "no fields",
+			body: `{}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("PatchPost", userId, postId, &dto.PatchPostRequest{}).
+					Return(&dto.EditPostResponse{PostId: postId, Title: "kept", Content: "kept"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "empty title is not a missing one",
+			body:           `{"title":""}`,
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "stale edit",
+			body: `{"title":"New title","expected_updated_at":"2025-01-31T12:00:00Z"}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("PatchPost", userId, postId, mock.AnythingOfType("*dto.PatchPostRequest")).
+					Return(&dto.EditPostResponse{PostId: postId, Title: "Edited in another tab"}, errors.ErrorServiceConflict)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "no access",
+			body: `{"content":"New content"}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("PatchPost", userId, postId, mock.AnythingOfType("*dto.PatchPostRequest")).
+					Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/post/%s", postId), strings.NewReader(tt.body))
+			req.SetPathValue("postId", postId.String())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+
+			controller.PatchPostHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusBadRequest {
+				mockService.AssertNotCalled(t, "PatchPost")
+			}
+		})
+	}
+}
+
 func TestPosterController_PublishHandler(t *testing.T) {
 	userId := uuid.New()
 	postId := uuid.New()
@@ -278,6 +527,7 @@ func TestPosterController_PublishHandler(t *testing.T) {
 				m.On("PublishPost", userId, parsedPostId, mock.AnythingOfType("*dto.PublishPostRequest")).
 					Return(&dto.PublishPostResponse{
 						PostId: parsedPostId,
+						Status: types.Published,
 					}, nil)
 			},
 			expectedStatus: http.StatusCreated,
@@ -287,7 +537,63 @@ func TestPosterController_PublishHandler(t *testing.T) {
 				err := json.Unmarshal([]byte(body), &resp)
 				assert.NoError(t, err)
 				assert.Equal(t, postId, resp.PostId)
+				assert.Equal(t, types.Published, resp.Status)
+			},
+		},
+		{
+			name:   "successful unpublish",
+			postId: postId.String(),
+			requestBody: dto.PublishPostRequest{
+				Status: types.Draft,
+			},
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("PublishPost", userId, parsedPostId, &dto.PublishPostRequest{Status: types.Draft}).
+					Return(&dto.PublishPostResponse{
+						PostId: parsedPostId,
+						Status: types.Draft,
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.PublishPostResponse
+				err := json.Unmarshal([]byte(body), &resp)
+				assert.NoError(t, err)
+				assert.Equal(t, types.Draft, resp.Status)
+			},
+		},
+		{
+			name:        "email not verified",
+			postId:      postId.String(),
+			requestBody: dto.PublishPostRequest{Status: types.Published},
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("PublishPost", userId, parsedPostId, mock.AnythingOfType("*dto.PublishPostRequest")).
+					Return(nil, errors.ErrorEmailNotVerified)
 			},
+			expectedStatus: http.StatusForbidden,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, dto.CodeEmailNotVerified, resp.Code)
+				assert.Equal(t, errors.ErrorHttpEmailNotVerified.Error(), resp.Error)
+			},
+		},
+		{
+			name:           "unknown status",
+			postId:         postId.String(),
+			requestBody:    dto.PublishPostRequest{Status: "hidden"},
+			setupMock:      func(m *MockPosterService, parsedPostId uuid.UUID) {},
+			expectedStatus: http.StatusBadRequest,
+			shouldCallMock: false,
+		},
+		{
+			name:           "scheduled without publish_at",
+			postId:         postId.String(),
+			requestBody:    dto.PublishPostRequest{Status: types.Scheduled},
+			setupMock:      func(m *MockPosterService, parsedPostId uuid.UUID) {},
+			expectedStatus: http.StatusBadRequest,
+			shouldCallMock: false,
 		},
 		{
 			name:           "invalid post ID",
@@ -416,6 +722,8 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 		expectedStatus int
 		checkBody      func(*testing.T, string)
 		shouldCallMock bool
+		// location is the Location header expected, empty for none
+		location string
 	}{
 		{
 			name:    "successful add image",
@@ -430,6 +738,7 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusCreated,
 			shouldCallMock: true,
+			location:       fmt.Sprintf("/api/v1/posts/%s/images/%s", postId, imageId),
 			checkBody: func(t *testing.T, body string) {
 				var resp dto.AddImageResponse
 				err := json.Unmarshal([]byte(body), &resp)
@@ -476,6 +785,17 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			shouldCallMock: true,
 		},
+		{
+			name:    "not an image",
+			postId:  postId.String(),
+			hasFile: true,
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("AddImage", userId, parsedPostId, mock.Anything, mock.Anything).
+					Return(nil, errors.ErrorServiceUnsupportedImage)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			shouldCallMock: true,
+		},
 		{
 			name:    "incorrect data",
 			postId:  postId.String(),
@@ -498,6 +818,44 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 			expectedStatus: http.StatusBadGateway,
 			shouldCallMock: true,
 		},
+		{
+			name:    "storage timeout",
+			postId:  postId.String(),
+			hasFile: true,
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("AddImage", userId, parsedPostId, mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: %w", errors.ErrorRepositoryStorageTimeout, context.DeadlineExceeded))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpStorageTimeout.Error()+`"}`, body)
+			},
+		},
+		{
+			name:    "quota exceeded",
+			postId:  postId.String(),
+			hasFile: true,
+			setupMock: func(m *MockPosterService, parsedPostId uuid.UUID) {
+				m.On("AddImage", userId, parsedPostId, mock.Anything, mock.Anything).
+					Return(nil, errors.ErrorQuotaExceeded)
+				m.On("StorageQuota", userId).
+					Return(&dto.StorageQuotaResponse{UsedBytes: 900, LimitBytes: 1000}, nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.QuotaExceededResponse
+				assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, dto.QuotaExceededResponse{
+					Error:          errors.ErrorHttpQuotaExceeded.Error(),
+					Code:           dto.CodeQuotaExceeded,
+					UsedBytes:      900,
+					LimitBytes:     1000,
+					RemainingBytes: 100,
+				}, resp)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -522,7 +880,8 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 				req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/post/%s/images", tt.postId), nil)
 			}
 			req.SetPathValue("postId", tt.postId)
-			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			ctx := context.WithValue(req.Context(), types.CtxUser, user)
+			req = req.WithContext(apiversion.WithVersion(ctx, apiversion.V1))
 
 			rr := httptest.NewRecorder()
 			controller.AddImageHandler(rr, req)
@@ -530,6 +889,7 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, rr.Code,
 				"Expected status %d, got %d. Response: %s",
 				tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, tt.location, rr.Header().Get("Location"))
 
 			if tt.checkBody != nil {
 				tt.checkBody(t, rr.Body.String())
@@ -544,6 +904,39 @@ func TestPosterController_AddImageHandler(t *testing.T) {
 	}
 }
 
+func TestPosterController_QuotaHandler(t *testing.T) {
+	userId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+
+	t.Run("used and limit", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("StorageQuota", userId).Return(&dto.StorageQuotaResponse{UsedBytes: 2048, LimitBytes: 524288000}, nil)
+		controller := &PosterController{service: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/quota", nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		controller.QuotaHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp dto.StorageQuotaResponse
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, dto.StorageQuotaResponse{UsedBytes: 2048, LimitBytes: 524288000}, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		controller := &PosterController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.QuotaHandler(rr, httptest.NewRequest(http.MethodGet, "/users/me/quota", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		mockService.AssertNotCalled(t, "StorageQuota")
+	})
+}
+
 func TestPosterController_DeleteImageHandler(t *testing.T) {
 	userId := uuid.New()
 	postId := uuid.New()
@@ -616,6 +1009,17 @@ func TestPosterController_DeleteImageHandler(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			shouldCallMock: true,
 		},
+		{
+			name:    "image not found",
+			postId:  postId.String(),
+			imageId: imageId.String(),
+			setupMock: func(m *MockPosterService, parsedPostId, parsedImageId uuid.UUID) {
+				m.On("DeleteImage", userId, parsedPostId, parsedImageId).
+					Return(nil, errors.ErrorServiceImageNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			shouldCallMock: true,
+		},
 		{
 			name:    "incorrect data",
 			postId:  postId.String(),
@@ -674,6 +1078,55 @@ func TestPosterController_DeleteImageHandler(t *testing.T) {
 	}
 }
 
+// slowPosterService stands for a service stuck on the storage until the request is cancelled
+type slowPosterService struct {
+	MockPosterService
+	calls int
+}
+
+func (s *slowPosterService) DeleteImage(ctx context.Context, userId, postId, imageId uuid.UUID) (*dto.DeleteImageResponse, error) {
+	s.calls++
+	<-ctx.Done()
+	return nil, fmt.Errorf("%w: %w", errors.ErrorRepositoryStorageTimeout, ctx.Err())
+}
+
+// statusCounter counts the status lines sent for a request
+type statusCounter struct {
+	*httptest.ResponseRecorder
+	statuses int
+}
+
+func (c *statusCounter) WriteHeader(code int) {
+	c.statuses++
+	c.ResponseRecorder.WriteHeader(code)
+}
+
+func TestPosterController_DeleteImageHandler_Timeout(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	service := &slowPosterService{}
+	controller := &PosterController{service: service}
+	handler := middlewares.Timeout(20 * time.Millisecond)(http.HandlerFunc(controller.DeleteImageHandler))
+
+	postId, imageId := uuid.New(), uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/posts/%s/images/%s", postId, imageId), nil)
+	req.SetPathValue("postId", postId.String())
+	req.SetPathValue("imageId", imageId.String())
+	req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+	rr := &statusCounter{ResponseRecorder: httptest.NewRecorder()}
+	start := time.Now()
+	handler.ServeHTTP(rr, req)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, service.calls)
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.Equal(t, 1, rr.statuses, "the response is written exactly once")
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var apiErr dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &apiErr))
+	assert.Equal(t, errors.ErrorHttpRequestTimeout.Error(), apiErr.Error)
+}
+
 func TestPosterController_EditPostHandler_NoUser(t *testing.T) {
 	mockService := &MockPosterService{}
 	controller := &PosterController{service: mockService}
@@ -687,8 +1140,8 @@ func TestPosterController_EditPostHandler_NoUser(t *testing.T) {
 	rr := httptest.NewRecorder()
 	controller.EditPostHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "EditPost")
 }
 
@@ -705,31 +1158,252 @@ func TestPosterController_PublishHandler_NoUser(t *testing.T) {
 	rr := httptest.NewRecorder()
 	controller.PublishHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "PublishPost")
 }
 
-func TestPosterController_AddImageHandler_NoUser(t *testing.T) {
-	mockService := &MockPosterService{}
-	controller := &PosterController{service: mockService}
-
+func TestPosterController_AddImageHandler_Bulk(t *testing.T) {
+	userId := uuid.New()
 	postId := uuid.New()
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, _ := writer.CreateFormFile("image", "test.jpg")
-	part.Write([]byte("fake image content"))
-	writer.Close()
-
-	req := httptest.NewRequest(http.MethodPost, "/post/"+postId.String()+"/images", body)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.SetPathValue("postId", postId.String())
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+	filenames := mock.MatchedBy(func(files []*multipart.FileHeader) bool {
+		return len(files) == 3 && files[0].Filename == "a.png" && files[2].Filename == "c.png"
+	})
 
-	rr := httptest.NewRecorder()
+	tests := []struct {
+		name           string
+		uploads        []dto.ImageUpload
+		err            error
+		expectedStatus int
+		expected       dto.AddImagesResponse
+	}{
+		{
+			name: "all added",
+			uploads: []dto.ImageUpload{
+				{Filename: "a.png", Image: &dto.AddImageResponse{ImageId: first, ImageUrl: "/images/a"}},
+				{Filename: "b.png", Image: &dto.AddImageResponse{ImageId: second, ImageUrl: "/images/b"}},
+				{Filename: "c.png", Image: &dto.AddImageResponse{ImageId: third, ImageUrl: "/images/c"}},
+			},
+			expectedStatus: http.StatusCreated,
+			expected: dto.AddImagesResponse{
+				{Filename: "a.png", ImageId: &first, ImageUrl: "/images/a"},
+				{Filename: "b.png", ImageId: &second, ImageUrl: "/images/b"},
+				{Filename: "c.png", ImageId: &third, ImageUrl: "/images/c"},
+			},
+		},
+		{
+			name: "some failed",
+			uploads: []dto.ImageUpload{
+				{Filename: "a.png", Image: &dto.AddImageResponse{ImageId: first, ImageUrl: "/images/a"}},
+				{Filename: "b.png", Err: errors.ErrorServiceUnsupportedImage},
+				{Filename: "c.png", Err: errors.ErrorServiceImageLimit},
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expected: dto.AddImagesResponse{
+				{Filename: "a.png", ImageId: &first, ImageUrl: "/images/a"},
+				{Filename: "b.png", Error: errors.ErrorHttpUnsupportedImage.Error()},
+				{Filename: "c.png", Error: errors.ErrorHttpImageLimit.Error()},
+			},
+		},
+		{
+			name: "past the quota",
+			uploads: []dto.ImageUpload{
+				{Filename: "a.png", Image: &dto.AddImageResponse{ImageId: first, ImageUrl: "/images/a"}},
+				{Filename: "b.png", Image: &dto.AddImageResponse{ImageId: second, ImageUrl: "/images/b"}},
+				{Filename: "c.png", Err: errors.ErrorQuotaExceeded},
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expected: dto.AddImagesResponse{
+				{Filename: "a.png", ImageId: &first, ImageUrl: "/images/a"},
+				{Filename: "b.png", ImageId: &second, ImageUrl: "/images/b"},
+				{Filename: "c.png", Error: errors.ErrorHttpQuotaExceeded.Error()},
+			},
+		},
+		{
+			name:           "no access",
+			err:            errors.ErrorServiceNoAccess,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "post not found",
+			err:            sql.ErrNoRows,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			if tt.err != nil {
+				mockService.On("AddImages", userId, postId, filenames).Return(nil, tt.err)
+			} else {
+				mockService.On("AddImages", userId, postId, filenames).Return(tt.uploads, nil)
+			}
+			controller := &PosterController{service: mockService}
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			for _, name := range []string{"a.png", "b.png", "c.png"} {
+				part, _ := writer.CreateFormFile("images", name)
+				part.Write([]byte("fake image content"))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/post/"+postId.String()+"/images", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.SetPathValue("postId", postId.String())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.AddImageHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Empty(t, rr.Header().Get("Location"), "a bulk upload made several images")
+			if tt.expected != nil {
+				var resp dto.AddImagesResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expected, resp)
+			}
+			mockService.AssertExpectations(t)
+			mockService.AssertNotCalled(t, "AddImage")
+		})
+	}
+}
+
+func TestPosterController_ReplaceImageHandler(t *testing.T) {
+	userId := uuid.New()
+	postId := uuid.New()
+	imageId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+
+	tests := []struct {
+		name           string
+		imageId        string
+		hasFile        bool
+		resp           *dto.ImageResponse
+		err            error
+		shouldCallMock bool
+		expectedStatus int
+	}{
+		{
+			name:           "replaced",
+			imageId:        imageId.String(),
+			hasFile:        true,
+			resp:           &dto.ImageResponse{ImageId: imageId, ImageUrl: "/images/" + imageId.String() + ".png?v=1", Position: 2},
+			shouldCallMock: true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid image ID",
+			imageId:        "invalid-uuid",
+			hasFile:        true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "no file provided",
+			imageId:        imageId.String(),
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "image not found",
+			imageId:        imageId.String(),
+			hasFile:        true,
+			err:            errors.ErrorServiceImageNotFound,
+			shouldCallMock: true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "no access",
+			imageId:        imageId.String(),
+			hasFile:        true,
+			err:            errors.ErrorServiceNoAccess,
+			shouldCallMock: true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "not an image",
+			imageId:        imageId.String(),
+			hasFile:        true,
+			err:            errors.ErrorServiceUnsupportedImage,
+			shouldCallMock: true,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "too large",
+			imageId:        imageId.String(),
+			hasFile:        true,
+			err:            errors.ErrorServiceImageTooLarge,
+			shouldCallMock: true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			if tt.shouldCallMock {
+				if tt.err != nil {
+					mockService.On("ReplaceImage", userId, postId, imageId, mock.Anything, mock.Anything).Return(nil, tt.err)
+				} else {
+					mockService.On("ReplaceImage", userId, postId, imageId, mock.Anything, mock.Anything).Return(tt.resp, nil)
+				}
+			}
+			controller := &PosterController{service: mockService}
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			if tt.hasFile {
+				part, _ := writer.CreateFormFile("image", "test.png")
+				part.Write([]byte("fake image content"))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/posts/%s/images/%s", postId, tt.imageId), body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.SetPathValue("postId", postId.String())
+			req.SetPathValue("imageId", tt.imageId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.ReplaceImageHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.resp != nil {
+				var resp dto.ImageResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, *tt.resp, resp)
+			}
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ReplaceImage")
+			}
+		})
+	}
+}
+
+func TestPosterController_AddImageHandler_NoUser(t *testing.T) {
+	mockService := &MockPosterService{}
+	controller := &PosterController{service: mockService}
+
+	postId := uuid.New()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("image", "test.jpg")
+	part.Write([]byte("fake image content"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/post/"+postId.String()+"/images", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.SetPathValue("postId", postId.String())
+
+	rr := httptest.NewRecorder()
 	controller.AddImageHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "AddImage")
 }
 
@@ -746,7 +1420,749 @@ func TestPosterController_DeleteImageHandler_NoUser(t *testing.T) {
 	rr := httptest.NewRecorder()
 	controller.DeleteImageHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "DeleteImage")
 }
+
+func TestPosterController_TrashAndRestoreHandlers(t *testing.T) {
+	userId := uuid.New()
+	postId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+
+	tests := []struct {
+		name           string
+		method         string
+		postId         string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:   "trash",
+			method: "TrashPost",
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("TrashPost", userId, postId).Return(&dto.TrashPostResponse{PostId: postId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:   "trash foreign post",
+			method: "TrashPost",
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("TrashPost", userId, postId).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+			shouldCallMock: true,
+		},
+		{
+			name:           "trash invalid post ID",
+			method:         "TrashPost",
+			postId:         "invalid-uuid",
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "restore",
+			method: "RestorePost",
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestorePost", userId, postId).Return(&dto.RestorePostResponse{PostId: postId, Status: types.Draft}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:   "restore expired post",
+			method: "RestorePost",
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestorePost", userId, postId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			shouldCallMock: true,
+		},
+		{
+			name:   "restore unexpected error",
+			method: "RestorePost",
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestorePost", userId, postId).Return(nil, fmt.Errorf("database error"))
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/post/"+tt.postId, nil)
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			if tt.method == "TrashPost" {
+				controller.TrashPostHandler(rr, req)
+			} else {
+				controller.RestorePostHandler(rr, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, tt.method)
+			}
+		})
+	}
+}
+
+func TestPosterController_ImagesHandler(t *testing.T) {
+	postId := uuid.New()
+	images := &dto.GetPostImagesResponse{Images: []dto.ImageResponse{{ImageId: uuid.New(), ImageUrl: "http://images/1", CreatedAt: time.Now()}}}
+
+	tests := []struct {
+		name           string
+		user           *dto.UserDB
+		postId         string
+		setupMock      func(*MockPosterService, uuid.UUID)
+		expectedStatus int
+	}{
+		{
+			name:   "author",
+			user:   &dto.UserDB{UserId: uuid.New(), Role: types.Author},
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService, userId uuid.UUID) {
+				m.On("GetPostImages", userId, postId).Return(images, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "reader",
+			user:   &dto.UserDB{UserId: uuid.New(), Role: types.Reader},
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService, userId uuid.UUID) {
+				m.On("GetPostImages", userId, postId).Return(images, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "draft of someone else",
+			user:   &dto.UserDB{UserId: uuid.New(), Role: types.Reader},
+			postId: postId.String(),
+			setupMock: func(m *MockPosterService, userId uuid.UUID) {
+				m.On("GetPostImages", userId, postId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid post id",
+			user:           &dto.UserDB{UserId: uuid.New(), Role: types.Reader},
+			postId:         "not-a-uuid",
+			setupMock:      func(m *MockPosterService, userId uuid.UUID) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService, tt.user.UserId)
+
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/post/"+tt.postId+"/images", nil)
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+
+			rr := httptest.NewRecorder()
+			controller.ImagesHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+			if rr.Code == http.StatusOK {
+				var resp dto.GetPostImagesResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, images.Images[0].ImageUrl, resp.Images[0].ImageUrl)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_UpdateImageHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId, imageId := uuid.New(), uuid.New()
+	caption, position := "A caption", 2
+
+	tests := []struct {
+		name           string
+		imageId        string
+		body           string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name:    "caption and position",
+			imageId: imageId.String(),
+			body:    `{"caption":"A caption","position":2}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("UpdateImage", user.UserId, postId, imageId, &dto.UpdateImageRequest{Caption: &caption, Position: &position}).
+					Return(&dto.ImageResponse{ImageId: imageId, Caption: caption, Position: position}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "caption too long",
+			imageId:        imageId.String(),
+			body:           `{"caption":"` + strings.Repeat("ü", 501) + `"}`,
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "position below one",
+			imageId:        imageId.String(),
+			body:           `{"position":0}`,
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid image id",
+			imageId:        "not-a-uuid",
+			body:           `{}`,
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:    "image of another post",
+			imageId: imageId.String(),
+			body:    `{}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("UpdateImage", user.UserId, postId, imageId, &dto.UpdateImageRequest{}).Return(nil, errors.ErrorServiceImageNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPatch, "/posts/"+postId.String()+"/images/"+tt.imageId, strings.NewReader(tt.body))
+			req.SetPathValue("postId", postId.String())
+			req.SetPathValue("imageId", tt.imageId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.UpdateImageHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if rr.Code == http.StatusOK {
+				var resp dto.ImageResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, caption, resp.Caption)
+				assert.Equal(t, position, resp.Position)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_ReorderImagesHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId, first, second := uuid.New(), uuid.New(), uuid.New()
+	order := &dto.ReorderImagesRequest{ImageIds: []uuid.UUID{second, first}}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name: "every image once",
+			body: `{"image_ids":["` + second.String() + `","` + first.String() + `"]}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("ReorderImages", user.UserId, postId, order).Return(&dto.GetPostImagesResponse{Images: []dto.ImageResponse{
+					{ImageId: second, Position: 1}, {ImageId: first, Position: 2},
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing or foreign image",
+			body: `{"image_ids":["` + second.String() + `","` + first.String() + `"]}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("ReorderImages", user.UserId, postId, order).Return(nil, errors.ErrorServiceImageOrder)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not an image id",
+			body:           `{"image_ids":["first"]}`,
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "post of someone else",
+			body: `{"image_ids":["` + second.String() + `","` + first.String() + `"]}`,
+			setupMock: func(m *MockPosterService) {
+				m.On("ReorderImages", user.UserId, postId, order).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPut, "/posts/"+postId.String()+"/images/order", strings.NewReader(tt.body))
+			req.SetPathValue("postId", postId.String())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.ReorderImagesHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if rr.Code == http.StatusOK {
+				var resp dto.GetPostImagesResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Images, 2)
+				assert.Equal(t, second, resp.Images[0].ImageId)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_StatsHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId := uuid.New()
+	stats := &dto.PostStatsResponse{PostId: postId, Views: 2, Days: []dto.ViewDayResponse{{Day: "2025-01-31", Views: 2}}}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name: "stats",
+			setupMock: func(m *MockPosterService) {
+				m.On("GetPostStats", user.UserId, postId).Return(stats, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "foreign post",
+			setupMock: func(m *MockPosterService) {
+				m.On("GetPostStats", user.UserId, postId).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/post/"+postId.String()+"/stats", nil)
+			req.SetPathValue("postId", postId.String())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.StatsHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_PreviewTokenHandlers(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId := uuid.New()
+	request := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/posts/"+postId.String()+"/preview-token", nil)
+		req.SetPathValue("postId", postId.String())
+		ctx := context.WithValue(req.Context(), types.CtxUser, user)
+		return req.WithContext(apiversion.WithVersion(ctx, apiversion.V1))
+	}
+
+	t.Run("create", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("CreatePreviewToken", user.UserId, postId).Return(&dto.PreviewTokenResponse{PostId: postId, Token: "secret"}, nil)
+		controller := &PosterController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.CreatePreviewTokenHandler(rr, request(http.MethodPost))
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var res dto.PreviewTokenResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "/api/v1/public/preview/secret", res.Url, "the link is served under the version of the request")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("create for a foreign post", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("CreatePreviewToken", user.UserId, postId).Return(nil, errors.ErrorServiceNoAccess)
+		controller := &PosterController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.CreatePreviewTokenHandler(rr, request(http.MethodPost))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("RevokePreviewTokens", user.UserId, postId).Return(&dto.RevokePreviewTokensResponse{PostId: postId, Revoked: 2}, nil)
+		controller := &PosterController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.RevokePreviewTokensHandler(rr, request(http.MethodDelete))
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var res dto.RevokePreviewTokensResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Revoked)
+	})
+
+	t.Run("revoke for a missing post", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("RevokePreviewTokens", user.UserId, postId).Return(nil, sql.ErrNoRows)
+		controller := &PosterController{service: mockService}
+
+		rr := httptest.NewRecorder()
+		controller.RevokePreviewTokensHandler(rr, request(http.MethodDelete))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestPosterController_RevisionsHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name:  "default page",
+			query: "",
+			setupMock: func(m *MockPosterService) {
+				m.On("GetPostRevisions", user.UserId, postId, 20, 0).Return(&dto.GetPostRevisionsResponse{Limit: 20}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "foreign post",
+			query: "?limit=5&offset=5",
+			setupMock: func(m *MockPosterService) {
+				m.On("GetPostRevisions", user.UserId, postId, 5, 5).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "bad limit",
+			query:          "?limit=1000",
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/post/"+postId.String()+"/revisions"+tt.query, nil)
+			req.SetPathValue("postId", postId.String())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.RevisionsHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_RestoreRevisionHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	postId, revisionId := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		revisionId     string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+	}{
+		{
+			name:       "restore",
+			revisionId: revisionId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestoreRevision", user.UserId, postId, revisionId).Return(&dto.EditPostResponse{PostId: postId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown revision",
+			revisionId: revisionId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestoreRevision", user.UserId, postId, revisionId).Return(nil, errors.ErrorServiceRevisionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:       "foreign post",
+			revisionId: revisionId.String(),
+			setupMock: func(m *MockPosterService) {
+				m.On("RestoreRevision", user.UserId, postId, revisionId).Return(nil, errors.ErrorServiceNoAccess)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid revision ID",
+			revisionId:     "invalid-uuid",
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/post/"+postId.String()+"/revisions/"+tt.revisionId+"/restore", nil)
+			req.SetPathValue("postId", postId.String())
+			req.SetPathValue("revisionId", tt.revisionId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.RestoreRevisionHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPosterController_ExportHandler(t *testing.T) {
+	userId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+	writes := func(body string) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			io.WriteString(args.Get(1).(io.Writer), body)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockPosterService)
+		expectedStatus int
+		contentType    string
+		attachment     string
+		body           string
+	}{
+		{
+			name:  "json by default",
+			query: "",
+			setupMock: func(m *MockPosterService) {
+				m.On("ExportPosts", userId, mock.Anything).Run(writes(`{"posts":[]}`)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			contentType:    "application/json",
+			attachment:     ".json\"",
+			body:           `{"posts":[]}`,
+		},
+		{
+			name:  "zip",
+			query: "?format=zip",
+			setupMock: func(m *MockPosterService) {
+				m.On("ExportPostsArchive", userId, mock.Anything).Run(writes("PK")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			contentType:    "application/zip",
+			attachment:     ".zip\"",
+			body:           "PK",
+		},
+		{
+			name:           "unknown format",
+			query:          "?format=tar",
+			setupMock:      func(m *MockPosterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "failing before anything is written",
+			query: "?format=zip",
+			setupMock: func(m *MockPosterService) {
+				m.On("ExportPostsArchive", userId, mock.Anything).Return(errors.ErrorRepositoryQueryTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			tt.setupMock(mockService)
+			controller := &PosterController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/export"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.ExportHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.contentType, rr.Header().Get("Content-Type"))
+				assert.True(t, strings.HasPrefix(rr.Header().Get("Content-Disposition"), `attachment; filename="posts-`))
+				assert.True(t, strings.HasSuffix(rr.Header().Get("Content-Disposition"), tt.attachment))
+				assert.Equal(t, tt.body, rr.Body.String())
+			} else {
+				assert.Empty(t, rr.Header().Get("Content-Disposition"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("failing on the way cuts the export off", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		mockService.On("ExportPostsArchive", userId, mock.Anything).Run(writes("PK")).Return(errors.ErrorRepositoryStorageTimeout)
+		controller := &PosterController{service: mockService}
+
+		req := httptest.NewRequest(http.MethodGet, "/posts/export?format=zip", nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			controller.ExportHandler(rr, req)
+		})
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		mockService := &MockPosterService{}
+		controller := &PosterController{service: mockService}
+		rr := httptest.NewRecorder()
+		controller.ExportHandler(rr, httptest.NewRequest(http.MethodGet, "/posts/export", nil))
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		mockService.AssertNotCalled(t, "ExportPosts")
+	})
+}
+
+func TestPosterController_ImportHandler(t *testing.T) {
+	userId := uuid.New()
+	user := &dto.UserDB{UserId: userId, Role: types.Author}
+	export := `{"posts":[]}`
+
+	tests := []struct {
+		name           string
+		field          string
+		imports        []dto.PostImport
+		err            error
+		expectedStatus int
+		expected       *dto.ImportPostsResponse
+	}{
+		{
+			name:  "summary",
+			field: "file",
+			imports: []dto.PostImport{
+				{Entry: "posts[0]", PostId: uuid.New()},
+				{Entry: "posts[1]", Err: fmt.Errorf("%w: title is required", errors.ErrorServiceImportEntry)},
+				{Entry: "posts[2]", Err: errors.ErrorServiceImportDuplicate},
+				{Entry: "posts[3]", Err: errors.ErrorServiceUnsupportedImage},
+				{Entry: "posts[4]", PostId: uuid.New()},
+			},
+			expectedStatus: http.StatusOK,
+			expected: &dto.ImportPostsResponse{Imported: 2, Skipped: 3, Errors: []dto.ImportError{
+				{Entry: "posts[1]", Error: "malformed post: title is required"},
+				{Entry: "posts[2]", Error: errors.ErrorHttpImportDuplicate.Error()},
+				{Entry: "posts[3]", Error: errors.ErrorHttpUnsupportedImage.Error()},
+			}},
+		},
+		{
+			name:           "nothing to import",
+			field:          "file",
+			imports:        []dto.PostImport{},
+			expectedStatus: http.StatusOK,
+			expected:       &dto.ImportPostsResponse{Errors: []dto.ImportError{}},
+		},
+		{
+			name:           "no export",
+			field:          "file",
+			err:            errors.ErrorServiceImportFormat,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no file",
+			field:          "other",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPosterService{}
+			if tt.field == "file" {
+				mockService.On("ImportPosts", userId, int64(len(export))).Return(tt.imports, tt.err)
+			}
+			controller := &PosterController{service: mockService}
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile(tt.field, "posts.json")
+			part.Write([]byte(export))
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/import", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.ImportHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expected != nil {
+				var resp dto.ImportPostsResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, *tt.expected, resp)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
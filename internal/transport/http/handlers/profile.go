@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type ProfileService interface {
+	GetProfile(ctx context.Context, userId uuid.UUID) (*dto.UserResponse, error)
+	UpdateProfile(ctx context.Context, userId uuid.UUID, req *dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	SetAvatar(ctx context.Context, userId uuid.UUID, file io.Reader, size int64) (*dto.UserResponse, error)
+}
+
+type ProfileController struct {
+	service ProfileService
+}
+
+func NewProfileController(service ProfileService) *ProfileController {
+	return &ProfileController{service}
+}
+
+// @Summary		Own profile
+// @Description	Display name, avatar, email and role of the current user
+// @Tags			Profile
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	dto.UserResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/users/me [get]
+func (c *ProfileController) GetProfileHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	res, err := c.service.GetProfile(r.Context(), user.UserId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		Update profile
+// @Description	Change the display name others see you by, up to 50 characters. An empty one shows your masked email again.
+// @Tags			Profile
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			profile	body		dto.UpdateProfileRequest	true	"Profile changes"
+// @Success		200		{object}	dto.UserResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/users/me [patch]
+func (c *ProfileController) UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	req, err := bind[dto.UpdateProfileRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	res, err := c.service.UpdateProfile(r.Context(), user.UserId, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		Upload avatar
+// @Description	Set the picture shown next to your name, it replaces the one you had. The same types and size limit as images of posts apply.
+// @Tags			Profile
+// @Accept			mpfd
+// @Produce		json
+// @Security		BearerAuth
+// @Param			avatar	formData	file	true	"Avatar"
+// @Success		200		{object}	dto.UserResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		413		{object}	dto.ErrorResponse	"Upload larger than MAX_UPLOAD_BYTES or image larger than MAX_IMAGE_BYTES"
+// @Failure		415		{object}	dto.ErrorResponse	"Image must be jpeg, png, gif or webp"
+// @Failure		502		{object}	dto.ErrorResponse	"Storage timeout"
+// @Router			/users/me/avatar [post]
+func (c *ProfileController) SetAvatarHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		if tooLarge(err) {
+			bodyError(w, err)
+			return
+		}
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	file, fileHeader, err := r.FormFile("avatar")
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	res, err := c.service.SetAvatar(r.Context(), user.UserId, file, fileHeader.Size)
+	if err != nil {
+		switch err {
+		case errors.ErrorServiceUnsupportedImage:
+			jsonError(w, http.StatusUnsupportedMediaType, errors.ErrorHttpUnsupportedImage)
+		case errors.ErrorServiceImageTooLarge:
+			jsonError(w, http.StatusRequestEntityTooLarge, errors.ErrorHttpImageTooLarge)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type MockProfileService struct {
+	mock.Mock
+}
+
+func (m *MockProfileService) GetProfile(ctx context.Context, userId uuid.UUID) (*dto.UserResponse, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UserResponse), args.Error(1)
+}
+
+func (m *MockProfileService) UpdateProfile(ctx context.Context, userId uuid.UUID, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	args := m.Called(userId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UserResponse), args.Error(1)
+}
+
+func (m *MockProfileService) SetAvatar(ctx context.Context, userId uuid.UUID, file io.Reader, size int64) (*dto.UserResponse, error) {
+	args := m.Called(userId, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UserResponse), args.Error(1)
+}
+
+func TestProfileController_UpdateProfileHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "alice@example.com", Role: types.Reader}
+	name := "Alice"
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockProfileService)
+		expectedStatus int
+	}{
+		{
+			name: "display name",
+			body: `{"display_name":"Alice"}`,
+			setupMock: func(m *MockProfileService) {
+				m.On("UpdateProfile", user.UserId, &dto.UpdateProfileRequest{DisplayName: &name}).
+					Return(&dto.UserResponse{UserId: user.UserId, DisplayName: name, Email: user.Email, Role: user.Role}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "too long",
+			body:           `{"display_name":"` + strings.Repeat("a", 51) + `"}`,
+			setupMock:      func(m *MockProfileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "broken body",
+			body:           `{"display_name":`,
+			setupMock:      func(m *MockProfileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockProfileService{}
+			tt.setupMock(mockService)
+			controller := NewProfileController(mockService)
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/me", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.UpdateProfileHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.UserResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, name, resp.DisplayName)
+				assert.Equal(t, user.Email, resp.Email, "the own email is shown")
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProfileController_SetAvatarHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "alice@example.com", Role: types.Reader}
+	content := []byte("fake image content")
+
+	tests := []struct {
+		name           string
+		field          string
+		setupMock      func(*MockProfileService)
+		expectedStatus int
+	}{
+		{
+			name:  "avatar",
+			field: "avatar",
+			setupMock: func(m *MockProfileService) {
+				m.On("SetAvatar", user.UserId, int64(len(content))).
+					Return(&dto.UserResponse{UserId: user.UserId, AvatarUrl: "/media/avatars/a.png"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "not an image",
+			field: "avatar",
+			setupMock: func(m *MockProfileService) {
+				m.On("SetAvatar", user.UserId, int64(len(content))).Return(nil, errors.ErrorServiceUnsupportedImage)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:  "too large",
+			field: "avatar",
+			setupMock: func(m *MockProfileService) {
+				m.On("SetAvatar", user.UserId, int64(len(content))).Return(nil, errors.ErrorServiceImageTooLarge)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "no avatar field",
+			field:          "image",
+			setupMock:      func(m *MockProfileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockProfileService{}
+			tt.setupMock(mockService)
+			controller := NewProfileController(mockService)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile(tt.field, "me.png")
+			part.Write(content)
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/users/me/avatar", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.SetAvatarHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+type PublicService interface {
+	GetPublicPosts(ctx context.Context, tag string) ([]*dto.PublicPostResponse, error)
+	GetPublicPost(ctx context.Context, postId uuid.UUID) (*dto.PublicPostResponse, error)
+	GetPreviewPost(ctx context.Context, token string) (*dto.GetPostResponse, error)
+	GetAuthor(ctx context.Context, authorId uuid.UUID) (*dto.AuthorResponse, error)
+}
+
+// PublicController serves published posts to anonymous visitors, its answers may be cached by anyone for maxAge
+type PublicController struct {
+	service PublicService
+	maxAge  time.Duration
+}
+
+func NewPublicController(service PublicService, maxAge time.Duration) *PublicController {
+	return &PublicController{
+		service: service,
+		maxAge:  maxAge,
+	}
+}
+
+// @Summary		Public posts
+// @Description	Published posts for visitors without an account, authors are shown by display name only
+// @Tags			Public
+// @Produce		json
+// @Param			tag	query		string	false	"Only posts with this tag"
+// @Success		200	{object}	[]dto.PublicPostResponse
+// @Success		304	"Not modified since the ETag in If-None-Match"
+// @Router			/public/posts [get]
+func (c *PublicController) PostsHandler(w http.ResponseWriter, r *http.Request) {
+	posts, err := c.service.GetPublicPosts(r.Context(), tagQuery(r))
+
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	c.writeCacheable(w, r, dto.PublicPostsResponse(posts))
+}
+
+// @Summary		Public post
+// @Description	One published post for visitors without an account, drafts and other hidden posts are not found
+// @Tags			Public
+// @Produce		json
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.PublicPostResponse
+// @Success		304		"Not modified since the ETag in If-None-Match"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/public/posts/{postId} [get]
+func (c *PublicController) PostHandler(w http.ResponseWriter, r *http.Request) {
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	post, err := c.service.GetPublicPost(r.Context(), postId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	c.writeCacheable(w, r, post)
+}
+
+// @Summary		Public author profile
+// @Description	Display name, avatar and number of published posts of an author for visitors without an account
+// @Tags			Public
+// @Produce		json
+// @Param			authorId	path		string	true	"Author ID"	format(uuid)
+// @Success		200			{object}	dto.AuthorResponse
+// @Success		304			"Not modified since the ETag in If-None-Match"
+// @Failure		404			{object}	dto.ErrorResponse	"Author not found"
+// @Router			/public/authors/{authorId} [get]
+func (c *PublicController) AuthorHandler(w http.ResponseWriter, r *http.Request) {
+	authorId, err := uuid.Parse(r.PathValue("authorId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		return
+	}
+
+	author, err := c.service.GetAuthor(r.Context(), authorId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	c.writeCacheable(w, r, author)
+}
+
+// @Summary		Preview post
+// @Description	The post a preview link was made for, whatever its status. Unknown, expired and revoked links are not found.
+// @Tags			Public
+// @Produce		json
+// @Param			token	path		string	true	"Token of the preview link"
+// @Success		200		{object}	dto.GetPostResponse
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/public/preview/{token} [get]
+func (c *PublicController) PreviewHandler(w http.ResponseWriter, r *http.Request) {
+	post, err := c.service.GetPreviewPost(r.Context(), r.PathValue("token"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	// the link may be revoked any time, nobody keeps the draft
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(post, w)
+}
+
+// writeCacheable answers with the body and an ETag of it, a request already holding that ETag gets 304 without a body
+func (c *PublicController) writeCacheable(w http.ResponseWriter, r *http.Request, v json.Marshaler) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.maxAge.Seconds())))
+	writeETagged(w, r, bodyETag(body), body)
+}
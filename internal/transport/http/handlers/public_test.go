@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+type MockPublicService struct {
+	mock.Mock
+}
+
+func (m *MockPublicService) GetPublicPosts(ctx context.Context, tag string) ([]*dto.PublicPostResponse, error) {
+	args := m.Called(tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.PublicPostResponse), args.Error(1)
+}
+
+func (m *MockPublicService) GetPublicPost(ctx context.Context, postId uuid.UUID) (*dto.PublicPostResponse, error) {
+	args := m.Called(postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PublicPostResponse), args.Error(1)
+}
+
+func (m *MockPublicService) GetPreviewPost(ctx context.Context, token string) (*dto.GetPostResponse, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostResponse), args.Error(1)
+}
+
+func (m *MockPublicService) GetAuthor(ctx context.Context, authorId uuid.UUID) (*dto.AuthorResponse, error) {
+	args := m.Called(authorId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AuthorResponse), args.Error(1)
+}
+
+func TestPublicController_PostsHandler(t *testing.T) {
+	mockService := &MockPublicService{}
+	posts := []*dto.PublicPostResponse{{PostId: uuid.New(), Author: dto.PublicAuthorResponse{DisplayName: "alice"}}}
+	mockService.On("GetPublicPosts", "golang").Return(posts, nil)
+	controller := NewPublicController(mockService, time.Minute)
+
+	// no user in the context, the handler must not need one
+	req := httptest.NewRequest(http.MethodGet, "/public/posts?tag=GoLang", nil)
+	rr := httptest.NewRecorder()
+	controller.PostsHandler(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+	etag := rr.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	var resp []dto.PublicPostResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "alice", resp[0].Author.DisplayName)
+	assert.NotContains(t, rr.Body.String(), "email")
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag} {
+		req = httptest.NewRequest(http.MethodGet, "/public/posts?tag=golang", nil)
+		req.Header.Set("If-None-Match", header)
+		rr = httptest.NewRecorder()
+		controller.PostsHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code, header)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/public/posts?tag=golang", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	rr = httptest.NewRecorder()
+	controller.PostsHandler(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPublicController_PostHandler(t *testing.T) {
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		postId         string
+		setupMock      func(*MockPublicService)
+		expectedStatus int
+	}{
+		{
+			name:   "published post",
+			postId: postId.String(),
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPublicPost", postId).Return(&dto.PublicPostResponse{PostId: postId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "draft or missing post",
+			postId: postId.String(),
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPublicPost", postId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid post ID",
+			postId:         "invalid-uuid",
+			setupMock:      func(m *MockPublicService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "unexpected error",
+			postId: postId.String(),
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPublicPost", postId).Return(nil, errors.ErrorHttpNoAuth)
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPublicService{}
+			tt.setupMock(mockService)
+			controller := NewPublicController(mockService, time.Minute)
+
+			req := httptest.NewRequest(http.MethodGet, "/public/posts/"+tt.postId, nil)
+			req.SetPathValue("postId", tt.postId)
+			rr := httptest.NewRecorder()
+			controller.PostHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.NotEmpty(t, rr.Header().Get("ETag"))
+			} else {
+				assert.Empty(t, rr.Header().Get("Cache-Control"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPublicController_AuthorHandler(t *testing.T) {
+	authorId := uuid.New()
+
+	t.Run("profile", func(t *testing.T) {
+		mockService := &MockPublicService{}
+		mockService.On("GetAuthor", authorId).Return(&dto.AuthorResponse{UserId: authorId, DisplayName: "a***@example.com", PostsCount: 1}, nil)
+		controller := NewPublicController(mockService, time.Minute)
+
+		req := httptest.NewRequest(http.MethodGet, "/public/authors/"+authorId.String(), nil)
+		req.SetPathValue("authorId", authorId.String())
+		rr := httptest.NewRecorder()
+		controller.AuthorHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.Contains(t, rr.Body.String(), `"display_name":"a***@example.com"`)
+	})
+
+	t.Run("not an author", func(t *testing.T) {
+		mockService := &MockPublicService{}
+		mockService.On("GetAuthor", authorId).Return(nil, sql.ErrNoRows)
+		controller := NewPublicController(mockService, time.Minute)
+
+		req := httptest.NewRequest(http.MethodGet, "/public/authors/"+authorId.String(), nil)
+		req.SetPathValue("authorId", authorId.String())
+		rr := httptest.NewRecorder()
+		controller.AuthorHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Empty(t, rr.Header().Get("Cache-Control"))
+	})
+}
+
+func TestPublicController_PreviewHandler(t *testing.T) {
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		setupMock      func(*MockPublicService)
+		expectedStatus int
+	}{
+		{
+			name: "draft of the link",
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPreviewPost", "token").Return(&dto.GetPostResponse{PostId: postId, Status: "draft"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "expired or revoked link",
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPreviewPost", "token").Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "unexpected error",
+			setupMock: func(m *MockPublicService) {
+				m.On("GetPreviewPost", "token").Return(nil, errors.ErrorHttpNoAuth)
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPublicService{}
+			tt.setupMock(mockService)
+			controller := NewPublicController(mockService, time.Minute)
+
+			req := httptest.NewRequest(http.MethodGet, "/public/preview/token", nil)
+			req.SetPathValue("token", "token")
+			rr := httptest.NewRecorder()
+			controller.PreviewHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+				var post dto.GetPostResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &post))
+				assert.Equal(t, postId, post.PostId)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,22 +1,43 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
 	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/apiversion"
 	"github.com/xkarasb/blog/pkg/errors"
 	"github.com/xkarasb/blog/pkg/types"
 	"github.com/xkarasb/blog/pkg/utils"
 )
 
 type ReaderService interface {
-	NewPost(authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, error)
-	GetPublishedPosts() ([]*dto.GetPostResponse, error)
-	GetAuthorPosts(authorId uuid.UUID) ([]*dto.GetPostResponse, error)
+	NewPost(ctx context.Context, authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, bool, error)
+	GetPost(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostResponse, error)
+	GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.GetPostResponse, error)
+	GetAuthorPosts(ctx context.Context, authorId uuid.UUID, opts dto.PostListOptions) ([]*dto.GetPostResponse, error)
+	GetTags(ctx context.Context) ([]*dto.TagResponse, error)
+	SearchPosts(ctx context.Context, query string, limit, offset int, authorId uuid.UUID) (*dto.SearchPostsResponse, error)
+	GetTrendingPosts(ctx context.Context, limit int) (dto.TrendingPostsResponse, error)
+	ReportPost(ctx context.Context, userId, postId uuid.UUID, req *dto.ReportPostRequest) (*dto.ReportResponse, error)
+	LikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error)
+	UnlikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error)
+	GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.GetPostResponse, error)
+	GetAuthors(ctx context.Context) ([]*dto.AuthorResponse, error)
+	GetAuthor(ctx context.Context, authorId uuid.UUID) (*dto.AuthorResponse, error)
+	GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.GetPostResponse, error)
 }
 
+// minSearchQuery is the shortest query worth sending to the full-text index
+const minSearchQuery = 2
+
 type ReaderController struct {
 	service ReaderService
 }
@@ -28,104 +49,578 @@ func NewReaderController(service ReaderService) *ReaderController {
 }
 
 // @Summary		Read post
-// @Description	Read all posts
+// @Description	Read all posts, authors get their own posts and everyone else the published ones, liked=true lists the posts you like instead
 // @Tags			Reader
 // @Accept			json
 // @Produce		json
 // @Security		BearerAuth
-// @Success		200	{object}	[]dto.GetPostResponse
-// @Failure		400	"Incorrect body\nRefresh token expired or incorrect"
-// @Failure		403	"Access denied"
-// @Failure		404	"Post not found"
+// @Param			tag		query		string	false	"Only posts with this tag"
+// @Param			liked	query		bool	false	"Only posts you like, latest like first"
+// @Param			sort	query		string	false	"Column to order by, liked posts keep the like order"	Enums(created_at, updated_at, title)	default(created_at)
+// @Param			order	query		string	false	"Direction of the order"								Enums(asc, desc)						default(desc)
+// @Param			status	query		string	false	"Only your posts with this status, authors only"		Enums(draft, published, archived, scheduled)
+// @Param			full	query		bool	false	"Content and content_html of the posts, without it they carry only the excerpt"
+// @Param			raw		query		bool	false	"Only the Markdown, without content_html"
+// @Success		200		{object}	[]dto.GetPostResponse
+// @Success		304		"Not modified since the ETag in If-None-Match"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed\nRefresh token expired or incorrect\nIncorrect query parameters"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
 // @Router			/posts [get]
 func (c *ReaderController) ViewSelectionHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
+		return
+	}
+	opts, err := listOptions(r)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+	liked, likedOk := boolQuery(r, "liked")
+	_, rawOk := boolQuery(r, "raw")
+	_, fullOk := boolQuery(r, "full")
+	if !likedOk || !rawOk || !fullOk {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	if liked {
+		c.likedView(w, r, user)
 		return
 	}
 	switch user.Role {
 	case types.Author:
-		c.authorView(w, r)
-	case types.Reader:
-		c.readerView(w, r)
+		c.authorView(w, r, opts)
+	case types.Reader, types.Admin:
+		if opts.Status != "" {
+			jsonError(w, http.StatusBadRequest, fmt.Errorf("%w: status is only for authors listing their own posts", errors.ErrorHttpIncorrectQuery))
+			return
+		}
+		c.readerView(w, r, opts)
 	default:
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		jsonError(w, http.StatusForbidden, errors.ErrorHttpIncorrectUser)
+	}
+}
+
+// boolQuery reads the query parameter name, false when it is missing. ok is false for a value that is not a bool.
+func boolQuery(r *http.Request, name string) (value, ok bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, true
+	}
+	value, err := strconv.ParseBool(raw)
+	return value, err == nil
+}
+
+// postsShown copies posts the way the request asked for them, without content_html for ?raw=true. With
+// excerpts set the posts carry only their excerpt unless the request asked for ?full=true.
+func postsShown(r *http.Request, posts []*dto.GetPostResponse, excerpts bool) dto.GetPostsResponse {
+	raw, _ := boolQuery(r, "raw")
+	full, _ := boolQuery(r, "full")
+
+	res := make(dto.GetPostsResponse, len(posts))
+	for i, post := range posts {
+		shown := *post
+		if raw {
+			shown.ContentHTML = ""
+		}
+		if excerpts && !full {
+			shown.Content = ""
+			shown.ContentHTML = ""
+		}
+		res[i] = &shown
+	}
+	return res
+}
+
+// tagQuery reads the ?tag= filter normalized the same way tags are stored
+func tagQuery(r *http.Request) string {
+	return strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
+}
+
+// listOptions reads the tag, status, sort and order of a listing, only the values listed here are accepted
+func listOptions(r *http.Request) (dto.PostListOptions, error) {
+	query := r.URL.Query()
+	opts := dto.PostListOptions{Tag: tagQuery(r), Sort: types.SortCreatedAt, Order: types.Desc}
+
+	if raw := query.Get("sort"); raw != "" {
+		opts.Sort = types.PostSort(raw)
+		if opts.Sort != types.SortCreatedAt && opts.Sort != types.SortUpdatedAt && opts.Sort != types.SortTitle {
+			return opts, fmt.Errorf("%w: sort must be one of created_at, updated_at, title", errors.ErrorHttpIncorrectQuery)
+		}
+	}
+	if raw := query.Get("order"); raw != "" {
+		opts.Order = types.SortOrder(raw)
+		if opts.Order != types.Asc && opts.Order != types.Desc {
+			return opts, fmt.Errorf("%w: order must be asc or desc", errors.ErrorHttpIncorrectQuery)
+		}
+	}
+	if raw := query.Get("status"); raw != "" {
+		opts.Status = types.PostStatus(raw)
+		if opts.Status != types.Draft && opts.Status != types.Published && opts.Status != types.Archived && opts.Status != types.Scheduled {
+			return opts, fmt.Errorf("%w: status must be one of draft, published, archived, scheduled", errors.ErrorHttpIncorrectQuery)
+		}
 	}
+	return opts, nil
 }
 
-func (c *ReaderController) readerView(w http.ResponseWriter, r *http.Request) {
-	posts, err := c.service.GetPublishedPosts()
+func (c *ReaderController) readerView(w http.ResponseWriter, r *http.Request, opts dto.PostListOptions) {
+	posts, err := c.service.GetPublishedPosts(r.Context(), opts)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		serviceError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(posts)
+	writeRevalidated(w, r, postsShown(r, posts, true))
+}
+
+func (c *ReaderController) likedView(w http.ResponseWriter, r *http.Request, user *dto.UserDB) {
+	posts, err := c.service.GetLikedPosts(r.Context(), user.UserId, tagQuery(r))
+
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	writeRevalidated(w, r, postsShown(r, posts, true))
 }
 
-func (c *ReaderController) authorView(w http.ResponseWriter, r *http.Request) {
+func (c *ReaderController) authorView(w http.ResponseWriter, r *http.Request, opts dto.PostListOptions) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
 		return
 	}
-	posts, err := c.service.GetAuthorPosts(user.UserId)
+	posts, err := c.service.GetAuthorPosts(ctx, user.UserId, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
+		serviceError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(posts)
+	writeRevalidated(w, r, postsShown(r, posts, true))
 }
 
 // @Summary		Create post
-// @Description	Create new post
+// @Description	Create new post, a retry with the same idempotency key and payload returns the post created before
 // @Tags			Poster
 // @Accept			json
 // @Produce		json
 // @Security		BearerAuth
 // @Param			request	body		dto.CreatePostRequest	true	"Create post data"
+// @Success		200		{object}	dto.CreatePostResponse	"Created by an earlier request with this key"
 // @Success		201		{object}	dto.CreatePostResponse
-// @Failure		400		"Incorrect body"
-// @Failure		403		"Incorrect user"
-// @Failure		409		"Idempotency key already used"
+// @Header			201		{string}	Location	"URL of the post"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		403		{object}	dto.ErrorResponse	"Access denied"
+// @Failure		409		{object}	dto.ErrorResponse	"Idempotency key already used for another post"
 // @Router			/posts [post]
 func (c *ReaderController) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
 	if !ok {
-		http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		missingUser(w, r)
 		return
 	}
 
-	reqPost := &dto.CreatePostRequest{}
-	if err := json.NewDecoder(r.Body).Decode(reqPost); err != nil {
-		http.Error(w, errors.ErrorHttpIncorrectBody.Error(), http.StatusBadRequest)
+	reqPost, err := bind(r, func(req *dto.CreatePostRequest) { req.Tags = utils.NormalizeTags(req.Tags) })
+	if err != nil {
+		bodyError(w, err)
 		return
+	}
+
+	resPost, created, err := c.service.NewPost(ctx, user.UserId, reqPost)
 
+	if err != nil {
+		if err == errors.ErrorKeyIdempotencyAlreadyUsed {
+			jsonError(w, http.StatusConflict, err)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
 	}
-	if err := utils.Validate(reqPost); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if created {
+		w.Header().Set("Location", apiversion.FromContext(ctx).Prefix()+"/posts/"+resPost.PostId.String())
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.MarshalToHTTPResponseWriter(resPost, w)
+
+}
+
+// @Summary		Read one post
+// @Description	Read a published post or one of your own posts, reading someone else's post counts a view once per day
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Param			raw		query		bool	false	"Only the Markdown, without content_html"
+// @Success		200		{object}	dto.GetPostResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Success		304		"Not modified since the ETag in If-None-Match"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId} [get]
+func (c *ReaderController) GetPostHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
 		return
 	}
 
-	resPost, err := c.service.NewPost(user.UserId, reqPost)
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+	raw, ok := boolQuery(r, "raw")
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
 
+	post, err := c.service.GetPost(ctx, user.UserId, postId)
 	if err != nil {
-		if err == errors.ErrorKeyIdempotencyAlreadyUsed {
-			http.Error(w, err.Error(), http.StatusConflict)
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+	if raw {
+		post.ContentHTML = ""
+	}
+
+	writeRevalidated(w, r, post)
+}
+
+// @Summary		List authors
+// @Description	Authors with at least one published post and how many they have, most published first
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	[]dto.AuthorResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/authors [get]
+func (c *ReaderController) GetAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	authors, err := c.service.GetAuthors(r.Context())
+
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(dto.AuthorsResponse(authors), w)
+}
+
+// @Summary		Author profile
+// @Description	Display name, avatar and number of published posts of an author, an author who has not published yet has 0
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			authorId	path		string	true	"Author ID"	format(uuid)
+// @Success		200			{object}	dto.AuthorResponse
+// @Failure		401			{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404			{object}	dto.ErrorResponse	"Author not found"
+// @Router			/authors/{authorId} [get]
+func (c *ReaderController) GetAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	authorId, err := uuid.Parse(r.PathValue("authorId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		return
+	}
+
+	author, err := c.service.GetAuthor(r.Context(), authorId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(author, w)
+}
+
+// @Summary		Posts of an author
+// @Description	Published posts of one author, an author who has not published yet gets an empty list
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			authorId	path		string	true	"Author ID"	format(uuid)
+// @Param			tag			query		string	false	"Only posts with this tag"
+// @Param			raw			query		bool	false	"Only the Markdown, without content_html"
+// @Success		200			{object}	[]dto.GetPostResponse
+// @Failure		400			{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401			{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404			{object}	dto.ErrorResponse	"Author not found"
+// @Router			/authors/{authorId}/posts [get]
+func (c *ReaderController) GetAuthorPostsHandler(w http.ResponseWriter, r *http.Request) {
+	authorId, err := uuid.Parse(r.PathValue("authorId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		return
+	}
+	if _, ok := boolQuery(r, "raw"); !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	posts, err := c.service.GetAuthorPublishedPosts(r.Context(), authorId, tagQuery(r))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(postsShown(r, posts, false), w)
+}
+
+// @Summary		List tags
+// @Description	Distinct tags with the number of published posts using them, most used first
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	[]dto.TagResponse
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/tags [get]
+func (c *ReaderController) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+	tags, err := c.service.GetTags(r.Context())
+
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(dto.TagsResponse(tags), w)
+}
+
+// @Summary		Search posts
+// @Description	Full-text search over title and content of published posts ordered by relevance, mine=true lets an author search their own posts of any status too
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			q		query		string	true	"Search query, at least 2 characters"
+// @Param			mine	query		bool	false	"Also match own posts of any status"
+// @Param			limit	query		int		false	"Page size, 1-100"	default(20)
+// @Param			offset	query		int		false	"Posts to skip"		default(0)
+// @Param			raw		query		bool	false	"Only the Markdown, without content_html"
+// @Success		200		{object}	dto.SearchPostsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/posts/search [get]
+func (c *ReaderController) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if utf8.RuneCountInString(query) < minSearchQuery {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	raw, ok := boolQuery(r, "raw")
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	authorId := uuid.Nil
+	if raw := r.URL.Query().Get("mine"); raw != "" {
+		mine, err := strconv.ParseBool(raw)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+			return
+		}
+		if mine && user.Role == types.Author {
+			authorId = user.UserId
+		}
+	}
+
+	res, err := c.service.SearchPosts(ctx, query, limit, offset, authorId)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+	if raw {
+		for i := range res.Posts {
+			res.Posts[i].ContentHTML = ""
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		Trending posts
+// @Description	Published posts ranked by a score of their recent views and likes, a like counting as several views and older activity less. Posts nobody viewed or liked lately follow newest first. The ranking is the same for everyone and refreshed about once a minute.
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			limit	query		int		false	"Posts to return, 1-100"	default(20)
+// @Param			full	query		bool	false	"Content and content_html of the posts, without it they carry only the excerpt"
+// @Param			raw		query		bool	false	"Only the Markdown, without content_html"
+// @Success		200		{object}	dto.TrendingPostsResponse
+// @Success		304		"Not modified since the ETag in If-None-Match"
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/posts/trending [get]
+func (c *ReaderController) TrendingHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultPageLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxPageLimit {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+			return
+		}
+	}
+	_, rawOk := boolQuery(r, "raw")
+	_, fullOk := boolQuery(r, "full")
+	if !rawOk || !fullOk {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	trending, err := c.service.GetTrendingPosts(r.Context(), limit)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	// the ranking is shared by every reader, postsShown copies the posts before trimming them
+	posts := make([]*dto.GetPostResponse, len(trending))
+	for i := range trending {
+		posts[i] = &trending[i].GetPostResponse
+	}
+	shown := postsShown(r, posts, true)
+	res := make(dto.TrendingPostsResponse, len(trending))
+	for i, post := range shown {
+		res[i] = dto.TrendingPostResponse{GetPostResponse: *post, Score: trending[i].Score}
+	}
+	writeRevalidated(w, r, res)
+}
+
+// @Summary		Like post
+// @Description	Like a published post or one of your own posts, liking it again changes nothing
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.LikeResponse
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/like [post]
+func (c *ReaderController) LikeHandler(w http.ResponseWriter, r *http.Request) {
+	c.handleLike(w, r, c.service.LikePost)
+}
+
+// @Summary		Unlike post
+// @Description	Take your like of a post back, a post you do not like is left as is
+// @Tags			Reader
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string	true	"Post ID"	format(uuid)
+// @Success		200		{object}	dto.LikeResponse
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/like [delete]
+func (c *ReaderController) UnlikeHandler(w http.ResponseWriter, r *http.Request) {
+	c.handleLike(w, r, c.service.UnlikePost)
+}
+
+// @Summary		Report post
+// @Description	Report a published post as spam or abuse to the admins. You report a post once, reporting it again keeps your first report.
+// @Tags			Reader
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			postId	path		string					true	"Post ID"	format(uuid)
+// @Param			request	body		dto.ReportPostRequest	true	"Reason and comment"
+// @Success		200		{object}	dto.ReportResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404		{object}	dto.ErrorResponse	"Post not found"
+// @Router			/posts/{postId}/report [post]
+func (c *ReaderController) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	req, err := bind[dto.ReportPostRequest](r)
+	if err != nil {
+		bodyError(w, err)
+		return
+	}
+
+	res, err := c.service.ReportPost(ctx, user.UserId, postId, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
 		} else {
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			serviceError(w, r, err)
 		}
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(resPost)
 
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+func (c *ReaderController) handleLike(w http.ResponseWriter, r *http.Request, call func(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error)) {
+	ctx := r.Context()
+	user, ok := ctx.Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	postId, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		return
+	}
+
+	res, err := call(r.Context(), user.UserId, postId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+		} else {
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
 }
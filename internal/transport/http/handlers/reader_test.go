@@ -3,48 +3,145 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/jsonbody"
 	"github.com/xkarasb/blog/pkg/types"
 )
 
+// newestFirst is what a listing without query parameters asks for
+var newestFirst = dto.PostListOptions{Sort: types.SortCreatedAt, Order: types.Desc}
+
 type MockReaderService struct {
 	mock.Mock
 }
 
-func (m *MockReaderService) NewPost(authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, error) {
+func (m *MockReaderService) NewPost(ctx context.Context, authorId uuid.UUID, post *dto.CreatePostRequest) (*dto.CreatePostResponse, bool, error) {
 	args := m.Called(authorId, post)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*dto.CreatePostResponse), args.Bool(1), args.Error(2)
+}
+
+func (m *MockReaderService) GetPublishedPosts(ctx context.Context, opts dto.PostListOptions) ([]*dto.GetPostResponse, error) {
+	args := m.Called(opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.GetPostResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetAuthorPosts(ctx context.Context, authorId uuid.UUID, opts dto.PostListOptions) ([]*dto.GetPostResponse, error) {
+	args := m.Called(authorId, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*dto.CreatePostResponse), args.Error(1)
+	return args.Get(0).([]*dto.GetPostResponse), args.Error(1)
 }
 
-func (m *MockReaderService) GetPublishedPosts() ([]*dto.GetPostResponse, error) {
+func (m *MockReaderService) GetTags(ctx context.Context) ([]*dto.TagResponse, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
+	return args.Get(0).([]*dto.TagResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetPost(ctx context.Context, userId, postId uuid.UUID) (*dto.GetPostResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetPostResponse), args.Error(1)
+}
+
+func (m *MockReaderService) ReportPost(ctx context.Context, userId, postId uuid.UUID, req *dto.ReportPostRequest) (*dto.ReportResponse, error) {
+	args := m.Called(userId, postId, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ReportResponse), args.Error(1)
+}
+
+func (m *MockReaderService) LikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LikeResponse), args.Error(1)
+}
+
+func (m *MockReaderService) UnlikePost(ctx context.Context, userId, postId uuid.UUID) (*dto.LikeResponse, error) {
+	args := m.Called(userId, postId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LikeResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetLikedPosts(ctx context.Context, userId uuid.UUID, tag string) ([]*dto.GetPostResponse, error) {
+	args := m.Called(userId, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*dto.GetPostResponse), args.Error(1)
 }
 
-func (m *MockReaderService) GetAuthorPosts(authorId uuid.UUID) ([]*dto.GetPostResponse, error) {
+func (m *MockReaderService) GetAuthor(ctx context.Context, authorId uuid.UUID) (*dto.AuthorResponse, error) {
 	args := m.Called(authorId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
+	return args.Get(0).(*dto.AuthorResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetAuthors(ctx context.Context) ([]*dto.AuthorResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.AuthorResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetAuthorPublishedPosts(ctx context.Context, authorId uuid.UUID, tag string) ([]*dto.GetPostResponse, error) {
+	args := m.Called(authorId, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*dto.GetPostResponse), args.Error(1)
 }
 
+func (m *MockReaderService) SearchPosts(ctx context.Context, query string, limit, offset int, authorId uuid.UUID) (*dto.SearchPostsResponse, error) {
+	args := m.Called(query, limit, offset, authorId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SearchPostsResponse), args.Error(1)
+}
+
+func (m *MockReaderService) GetTrendingPosts(ctx context.Context, limit int) (dto.TrendingPostsResponse, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(dto.TrendingPostsResponse), args.Error(1)
+}
+
 func TestReaderController_CreatePostHandler(t *testing.T) {
 	userId := uuid.New()
 	postId := uuid.New()
@@ -57,6 +154,8 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 		expectedStatus int
 		checkBody      func(*testing.T, string)
 		shouldCallMock bool
+		// location is the Location header expected, empty for none
+		location string
 	}{
 		{
 			name: "successful post creation",
@@ -67,17 +166,25 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockReaderService) {
 				m.On("NewPost", userId, mock.AnythingOfType("*dto.CreatePostRequest")).
-					Return(&dto.CreatePostResponse{
-						PostId: postId,
-					}, nil)
+					Return(&dto.CreatePostResponse{EditPostResponse: dto.EditPostResponse{
+						PostId: postId, AuthorId: userId, IdempotencyKey: "key-123", Title: "Test Post", Content: "Test Content",
+						Status: types.Draft, Tags: []string{}, WordCount: 2, ReadingTimeMinutes: 1,
+					}}, true, nil)
 			},
 			expectedStatus: http.StatusCreated,
 			shouldCallMock: true,
+			location:       "/api/posts/" + postId.String(),
 			checkBody: func(t *testing.T, body string) {
 				var resp dto.CreatePostResponse
 				err := json.Unmarshal([]byte(body), &resp)
 				assert.NoError(t, err)
 				assert.Equal(t, postId, resp.PostId)
+				assert.Equal(t, userId, resp.AuthorId)
+				assert.Equal(t, "Test Post", resp.Title)
+				assert.Equal(t, "Test Content", resp.Content)
+				assert.Equal(t, types.Draft, resp.Status)
+				assert.Equal(t, 2, resp.WordCount)
+				assert.Contains(t, body, `"post_id":"`+postId.String()+`"`)
 			},
 		},
 		{
@@ -99,13 +206,31 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockReaderService) {
 				m.On("NewPost", userId, mock.AnythingOfType("*dto.CreatePostRequest")).
-					Return(&dto.CreatePostResponse{
-						PostId: postId,
-					}, nil)
+					Return(&dto.CreatePostResponse{EditPostResponse: dto.EditPostResponse{PostId: postId}}, true, nil)
 			},
 			expectedStatus: http.StatusBadRequest,
 			shouldCallMock: false,
 		},
+		{
+			name: "retry of a created post",
+			requestBody: dto.CreatePostRequest{
+				IdempotencyKey: "key-123",
+				Title:          "Test Post",
+				Content:        "Test Content",
+			},
+			setupMock: func(m *MockReaderService) {
+				m.On("NewPost", userId, mock.AnythingOfType("*dto.CreatePostRequest")).
+					Return(&dto.CreatePostResponse{EditPostResponse: dto.EditPostResponse{PostId: postId, Title: "Test Post"}}, false, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+			checkBody: func(t *testing.T, body string) {
+				var resp dto.CreatePostResponse
+				assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.Equal(t, postId, resp.PostId)
+				assert.Equal(t, "Test Post", resp.Title)
+			},
+		},
 		{
 			name: "idempotency key already used",
 			requestBody: dto.CreatePostRequest{
@@ -115,7 +240,7 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockReaderService) {
 				m.On("NewPost", userId, mock.AnythingOfType("*dto.CreatePostRequest")).
-					Return(nil, errors.ErrorKeyIdempotencyAlreadyUsed)
+					Return(nil, false, errors.ErrorKeyIdempotencyAlreadyUsed)
 			},
 			expectedStatus: http.StatusConflict,
 			shouldCallMock: true,
@@ -132,11 +257,73 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 			},
 			setupMock: func(m *MockReaderService) {
 				m.On("NewPost", userId, mock.AnythingOfType("*dto.CreatePostRequest")).
-					Return(nil, errors.ErrorHttpNoAuth)
+					Return(nil, false, errors.ErrorHttpNoAuth)
 			},
 			expectedStatus: http.StatusBadGateway,
 			shouldCallMock: true,
 		},
+		{
+			name: "tags are normalized",
+			requestBody: dto.CreatePostRequest{
+				IdempotencyKey: "key-123",
+				Title:          "Test Post",
+				Content:        "Test Content",
+				Tags:           []string{" GoLang ", "golang", "DB"},
+			},
+			setupMock: func(m *MockReaderService) {
+				m.On("NewPost", userId, mock.MatchedBy(func(post *dto.CreatePostRequest) bool {
+					return slices.Equal(post.Tags, []string{"golang", "db"})
+				})).Return(&dto.CreatePostResponse{EditPostResponse: dto.EditPostResponse{PostId: postId}}, true, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			shouldCallMock: true,
+			location:       "/api/posts/" + postId.String(),
+		},
+		{
+			name: "too many tags",
+			requestBody: dto.CreatePostRequest{
+				IdempotencyKey: "key-123",
+				Title:          "Test Post",
+				Content:        "Test Content",
+				Tags:           []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			},
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			shouldCallMock: false,
+			checkBody: func(t *testing.T, body string) {
+				assertFields(t, body, dto.FieldError{Field: "tags", Rule: "max", Message: "must have at most 10 items"})
+			},
+		},
+		{
+			name: "empty tag",
+			requestBody: dto.CreatePostRequest{
+				IdempotencyKey: "key-123",
+				Title:          "Test Post",
+				Content:        "Test Content",
+				Tags:           []string{"golang", "  "},
+			},
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			shouldCallMock: false,
+			checkBody: func(t *testing.T, body string) {
+				assertFields(t, body, dto.FieldError{Field: "tags[1]", Rule: "required", Message: "is required"})
+			},
+		},
+		{
+			name: "tag too long",
+			requestBody: dto.CreatePostRequest{
+				IdempotencyKey: "key-123",
+				Title:          "Test Post",
+				Content:        "Test Content",
+				Tags:           []string{strings.Repeat("a", 51)},
+			},
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			shouldCallMock: false,
+			checkBody: func(t *testing.T, body string) {
+				assertFields(t, body, dto.FieldError{Field: "tags[0]", Rule: "max", Message: "must be at most 50 characters long"})
+			},
+		},
 		{
 			name:           "null body",
 			requestBody:    "null",
@@ -181,6 +368,7 @@ func TestReaderController_CreatePostHandler(t *testing.T) {
 				"Expected status %d, got %d. Response: %s",
 				tt.expectedStatus, rr.Code, rr.Body.String())
 
+			assert.Equal(t, tt.location, rr.Header().Get("Location"))
 			if tt.checkBody != nil {
 				tt.checkBody(t, rr.Body.String())
 			}
@@ -213,7 +401,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "author view - successful",
 			user: authorUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetAuthorPosts", userId).
+				m.On("GetAuthorPosts", userId, newestFirst).
 					Return([]*dto.GetPostResponse{
 						{
 							PostId: uuid.New(),
@@ -236,7 +424,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "reader view - successful",
 			user: readerUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetPublishedPosts").
+				m.On("GetPublishedPosts", newestFirst).
 					Return([]*dto.GetPostResponse{
 						{
 							PostId: uuid.New(),
@@ -269,7 +457,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "author view - service error",
 			user: authorUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetAuthorPosts", userId).
+				m.On("GetAuthorPosts", userId, newestFirst).
 					Return(nil, errors.ErrorHttpNoAuth)
 			},
 			expectedStatus: http.StatusBadGateway,
@@ -279,7 +467,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "reader view - service error",
 			user: readerUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetPublishedPosts").
+				m.On("GetPublishedPosts", newestFirst).
 					Return(nil, errors.ErrorHttpNoAuth)
 			},
 			expectedStatus: http.StatusBadGateway,
@@ -289,7 +477,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "author view - empty posts",
 			user: authorUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetAuthorPosts", userId).
+				m.On("GetAuthorPosts", userId, newestFirst).
 					Return([]*dto.GetPostResponse{}, nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -305,7 +493,7 @@ func TestReaderController_ViewSelectionHandler(t *testing.T) {
 			name: "reader view - empty posts",
 			user: readerUser,
 			setupMock: func(m *MockReaderService, userId uuid.UUID) {
-				m.On("GetPublishedPosts").
+				m.On("GetPublishedPosts", newestFirst).
 					Return([]*dto.GetPostResponse{}, nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -356,8 +544,8 @@ func TestReaderController_ViewSelectionHandler_NoUser(t *testing.T) {
 	rr := httptest.NewRecorder()
 	controller.ViewSelectionHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "GetAuthorPosts")
 	mockService.AssertNotCalled(t, "GetPublishedPosts")
 }
@@ -373,7 +561,1070 @@ func TestReaderController_CreatePostHandler_NoUser(t *testing.T) {
 	rr := httptest.NewRecorder()
 	controller.CreatePostHandler(rr, req)
 
-	assert.Equal(t, http.StatusForbidden, rr.Code)
-	assert.Contains(t, rr.Body.String(), errors.ErrorHttpIncorrectUser.Error())
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), errors.ErrorHttpInternal.Error())
 	mockService.AssertNotCalled(t, "NewPost")
 }
+
+func TestReaderController_CreatePostHandler_StrictJSON(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	body := `{"idempotency_key":"key-123","titel":"Test Post","title":"Test Post","content":"Test Content"}`
+
+	tests := []struct {
+		name           string
+		strict         bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "unknown field ignored", strict: false, expectedStatus: http.StatusCreated},
+		{name: "unknown field rejected", strict: true, expectedStatus: http.StatusBadRequest, expectedBody: `unknown field "titel"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			mockService.On("NewPost", user.UserId, mock.AnythingOfType("*dto.CreatePostRequest")).
+				Return(&dto.CreatePostResponse{EditPostResponse: dto.EditPostResponse{PostId: uuid.New()}}, true, nil)
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+			ctx := context.WithValue(req.Context(), types.CtxUser, user)
+			if tt.strict {
+				ctx = jsonbody.WithStrict(ctx)
+			}
+			rr := httptest.NewRecorder()
+			controller.CreatePostHandler(rr, req.WithContext(ctx))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.strict {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedBody, resp.Error)
+				mockService.AssertNotCalled(t, "NewPost")
+			}
+		})
+	}
+}
+
+func TestReaderController_TagFilter(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	mockService := &MockReaderService{}
+	mockService.On("GetPublishedPosts", dto.PostListOptions{Tag: "golang", Sort: types.SortCreatedAt, Order: types.Desc}).Return([]*dto.GetPostResponse{}, nil)
+	controller := &ReaderController{service: mockService}
+
+	req := httptest.NewRequest(http.MethodGet, "/posts?tag=%20GoLang", nil)
+	req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+	rr := httptest.NewRecorder()
+	controller.ViewSelectionHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReaderController_ListOptions(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	reader := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+
+	tests := []struct {
+		name           string
+		user           *dto.UserDB
+		query          string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "author sorts own drafts by title",
+			user:  author,
+			query: "?sort=title&order=asc&status=draft",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPosts", author.UserId, dto.PostListOptions{Status: types.Draft, Sort: types.SortTitle, Order: types.Asc}).
+					Return([]*dto.GetPostResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "reader sorts by update",
+			user:  reader,
+			query: "?sort=updated_at",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetPublishedPosts", dto.PostListOptions{Sort: types.SortUpdatedAt, Order: types.Desc}).
+					Return([]*dto.GetPostResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown sort",
+			user:           reader,
+			query:          "?sort=author_id",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"incorrect query parameters: sort must be one of created_at, updated_at, title"}`,
+		},
+		{
+			name:           "unknown order",
+			user:           author,
+			query:          "?order=up",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"incorrect query parameters: order must be asc or desc"}`,
+		},
+		{
+			name:           "unknown status",
+			user:           author,
+			query:          "?status=deleted",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"incorrect query parameters: status must be one of draft, published, archived, scheduled"}`,
+		},
+		{
+			name:           "status is for authors",
+			user:           reader,
+			query:          "?status=draft",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"incorrect query parameters: status is only for authors listing their own posts"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/posts"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+			rr := httptest.NewRecorder()
+			controller.ViewSelectionHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReaderController_GetTagsHandler(t *testing.T) {
+	mockService := &MockReaderService{}
+	mockService.On("GetTags").Return([]*dto.TagResponse{{Name: "golang", Count: 2}}, nil)
+	controller := &ReaderController{service: mockService}
+
+	rr := httptest.NewRecorder()
+	controller.GetTagsHandler(rr, httptest.NewRequest(http.MethodGet, "/tags", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var tags []dto.TagResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tags))
+	assert.Equal(t, []dto.TagResponse{{Name: "golang", Count: 2}}, tags)
+}
+
+func TestReaderController_GetAuthorsHandler(t *testing.T) {
+	authors := []*dto.AuthorResponse{{UserId: uuid.New(), DisplayName: "Alice", PostsCount: 3}}
+	mockService := &MockReaderService{}
+	mockService.On("GetAuthors").Return(authors, nil)
+	controller := &ReaderController{service: mockService}
+
+	rr := httptest.NewRecorder()
+	controller.GetAuthorsHandler(rr, httptest.NewRequest(http.MethodGet, "/authors", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []*dto.AuthorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, authors, resp)
+}
+
+func TestReaderController_GetAuthorHandler(t *testing.T) {
+	authorId := uuid.New()
+	author := &dto.AuthorResponse{UserId: authorId, DisplayName: "Alice", AvatarUrl: "/media/avatars/a.png", PostsCount: 2}
+
+	tests := []struct {
+		name           string
+		authorId       string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+	}{
+		{
+			name:     "profile",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthor", authorId).Return(author, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "not an author",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthor", authorId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			authorId:       "not-a-uuid",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/authors/"+tt.authorId, nil)
+			req.SetPathValue("authorId", tt.authorId)
+			rr := httptest.NewRecorder()
+			controller.GetAuthorHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.AuthorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, *author, resp)
+				assert.NotContains(t, rr.Body.String(), "email")
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReaderController_GetAuthorPostsHandler(t *testing.T) {
+	authorId := uuid.New()
+
+	tests := []struct {
+		name           string
+		authorId       string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "published posts",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPublishedPosts", authorId, "golang").
+					Return([]*dto.GetPostResponse{{PostId: uuid.New(), Status: types.Published}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "nothing published yet",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPublishedPosts", authorId, "golang").Return([]*dto.GetPostResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[]",
+		},
+		{
+			name:     "not an author",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPublishedPosts", authorId, "golang").Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"` + errors.ErrorHttpAuthorNotFound.Error() + `"}`,
+		},
+		{
+			name:           "invalid author ID",
+			authorId:       "invalid-uuid",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"` + errors.ErrorHttpAuthorNotFound.Error() + `"}`,
+		},
+		{
+			name:     "unexpected error",
+			authorId: authorId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPublishedPosts", authorId, "golang").Return(nil, sql.ErrConnDone)
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/authors/"+tt.authorId+"/posts?tag=GoLang", nil)
+			req.SetPathValue("authorId", tt.authorId)
+			rr := httptest.NewRecorder()
+			controller.GetAuthorPostsHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReaderController_SearchHandler(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	reader := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	page := &dto.SearchPostsResponse{
+		Posts: []dto.SearchPostResponse{{GetPostResponse: dto.GetPostResponse{PostId: uuid.New()}, Score: 0.5}},
+		Limit: 20,
+	}
+
+	tests := []struct {
+		name           string
+		user           *dto.UserDB
+		query          string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:  "published posts",
+			user:  reader,
+			query: "q=golang",
+			setupMock: func(m *MockReaderService) {
+				m.On("SearchPosts", "golang", 20, 0, uuid.Nil).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:  "author with mine",
+			user:  author,
+			query: "q=golang&mine=true&limit=5&offset=10",
+			setupMock: func(m *MockReaderService) {
+				m.On("SearchPosts", "golang", 5, 10, author.UserId).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:  "mine ignored for readers",
+			user:  reader,
+			query: "q=golang&mine=true",
+			setupMock: func(m *MockReaderService) {
+				m.On("SearchPosts", "golang", 20, 0, uuid.Nil).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "missing query",
+			user:           reader,
+			query:          "",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too short query",
+			user:           reader,
+			query:          "q=%20g%20",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bad mine flag",
+			user:           author,
+			query:          "q=golang&mine=maybe",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bad limit",
+			user:           reader,
+			query:          "q=golang&limit=0",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unexpected error",
+			user:  reader,
+			query: "q=golang",
+			setupMock: func(m *MockReaderService) {
+				m.On("SearchPosts", "golang", 20, 0, uuid.Nil).Return(nil, errors.ErrorHttpNoAuth)
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/search?"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+
+			rr := httptest.NewRecorder()
+			controller.SearchHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "SearchPosts")
+			}
+			if rr.Code == http.StatusOK {
+				var res dto.SearchPostsResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+				assert.Equal(t, 0.5, res.Posts[0].Score)
+			}
+		})
+	}
+}
+
+func TestReaderController_GetPostHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		postId         string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:   "found",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetPost", user.UserId, postId).Return(&dto.GetPostResponse{PostId: postId, Views: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:   "not found",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetPost", user.UserId, postId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			shouldCallMock: true,
+		},
+		{
+			name:           "invalid post ID",
+			postId:         "invalid-uuid",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/"+tt.postId, nil)
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			controller.GetPostHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetPost")
+			}
+		})
+	}
+}
+
+func TestReaderController_ETag(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	post := &dto.GetPostResponse{PostId: uuid.New(), Title: "Hello", Excerpt: "World", Content: "World", Status: types.Published}
+	edited := *post
+	edited.Excerpt = "World, edited"
+	edited.Content = "World, edited"
+
+	tests := []struct {
+		name  string
+		path  string
+		serve func(c *ReaderController, w http.ResponseWriter, r *http.Request)
+		mock  func(m *MockReaderService, post *dto.GetPostResponse)
+	}{
+		{
+			name:  "post list",
+			path:  "/posts",
+			serve: (*ReaderController).ViewSelectionHandler,
+			mock: func(m *MockReaderService, post *dto.GetPostResponse) {
+				m.On("GetPublishedPosts", newestFirst).Return([]*dto.GetPostResponse{post}, nil).Once()
+			},
+		},
+		{
+			name:  "single post",
+			path:  "/posts/" + post.PostId.String(),
+			serve: (*ReaderController).GetPostHandler,
+			mock: func(m *MockReaderService, post *dto.GetPostResponse) {
+				m.On("GetPost", user.UserId, post.PostId).Return(post, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			controller := &ReaderController{service: mockService}
+			get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				req.SetPathValue("postId", post.PostId.String())
+				req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+				if ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", ifNoneMatch)
+				}
+				rr := httptest.NewRecorder()
+				tt.serve(controller, rr, req)
+				return rr
+			}
+
+			tt.mock(mockService, post)
+			rr := get("")
+			require.Equal(t, http.StatusOK, rr.Code)
+			etag := rr.Header().Get("ETag")
+			assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+			assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+
+			// hit
+			tt.mock(mockService, post)
+			rr = get(etag)
+			assert.Equal(t, http.StatusNotModified, rr.Code)
+			assert.Empty(t, rr.Body.String())
+			assert.Equal(t, etag, rr.Header().Get("ETag"))
+
+			// miss
+			tt.mock(mockService, post)
+			rr = get(`W/"other"`)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.NotEmpty(t, rr.Body.String())
+
+			// an edit changes the tag, the old one is no longer current
+			tt.mock(mockService, &edited)
+			rr = get(etag)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+			assert.Contains(t, rr.Body.String(), "World, edited")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReaderController_LikeHandlers(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		method         string
+		postId         string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+		shouldCallMock bool
+	}{
+		{
+			name:   "like",
+			method: "LikePost",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("LikePost", user.UserId, postId).Return(&dto.LikeResponse{PostId: postId, Liked: true, LikesCount: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:   "like hidden post",
+			method: "LikePost",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("LikePost", user.UserId, postId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			shouldCallMock: true,
+		},
+		{
+			name:           "like invalid post ID",
+			method:         "LikePost",
+			postId:         "invalid-uuid",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "unlike",
+			method: "UnlikePost",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("UnlikePost", user.UserId, postId).Return(&dto.LikeResponse{PostId: postId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:   "unlike unexpected error",
+			method: "UnlikePost",
+			postId: postId.String(),
+			setupMock: func(m *MockReaderService) {
+				m.On("UnlikePost", user.UserId, postId).Return(nil, errors.ErrorHttpNoAuth)
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/post/"+tt.postId+"/like", nil)
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+
+			rr := httptest.NewRecorder()
+			if tt.method == "LikePost" {
+				controller.LikeHandler(rr, req)
+			} else {
+				controller.UnlikeHandler(rr, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, tt.method)
+			}
+		})
+	}
+}
+
+func TestReaderController_ReportHandler(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	postId := uuid.New()
+
+	tests := []struct {
+		name           string
+		postId         string
+		body           string
+		setupMock      func(*MockReaderService)
+		expectedStatus int
+	}{
+		{
+			name:   "report",
+			postId: postId.String(),
+			body:   `{"reason":"spam","comment":"casino links"}`,
+			setupMock: func(m *MockReaderService) {
+				m.On("ReportPost", user.UserId, postId, &dto.ReportPostRequest{Reason: types.ReportSpam, Comment: "casino links"}).
+					Return(&dto.ReportResponse{ReportId: uuid.New(), PostId: postId, Reason: types.ReportSpam}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown reason",
+			postId:         postId.String(),
+			body:           `{"reason":"boring"}`,
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing reason",
+			postId:         postId.String(),
+			body:           `{"comment":"no"}`,
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid post ID",
+			postId:         "invalid-uuid",
+			body:           `{"reason":"spam"}`,
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "draft or missing post",
+			postId: postId.String(),
+			body:   `{"reason":"abuse"}`,
+			setupMock: func(m *MockReaderService) {
+				m.On("ReportPost", user.UserId, postId, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/"+tt.postId+"/report", strings.NewReader(tt.body))
+			req.SetPathValue("postId", tt.postId)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+			rr := httptest.NewRecorder()
+			controller.ReportHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReaderController_LikedFilter(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	liked := []*dto.GetPostResponse{{PostId: uuid.New(), LikesCount: 1}}
+
+	mockService := &MockReaderService{}
+	mockService.On("GetLikedPosts", author.UserId, "golang").Return(liked, nil)
+	controller := &ReaderController{service: mockService}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/posts?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, author))
+		rr := httptest.NewRecorder()
+		controller.ViewSelectionHandler(rr, req)
+		return rr
+	}
+
+	rr := get("liked=true&tag=golang")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var posts []dto.GetPostResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &posts))
+	assert.Equal(t, liked[0].PostId, posts[0].PostId)
+
+	assert.Equal(t, http.StatusBadRequest, get("liked=maybe").Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReaderController_RawContent(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	authorId := uuid.New()
+	rendered := func() *dto.GetPostResponse {
+		return &dto.GetPostResponse{PostId: uuid.New(), Content: "**bold**", ContentHTML: "<p><strong>bold</strong></p>\n"}
+	}
+
+	tests := []struct {
+		name      string
+		pattern   string
+		target    string
+		setupMock func(*MockReaderService)
+		handler   func(*ReaderController) http.HandlerFunc
+	}{
+		{
+			name:    "listing",
+			pattern: "GET /posts",
+			target:  "/posts?full=true",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetPublishedPosts", newestFirst).Return([]*dto.GetPostResponse{rendered()}, nil)
+			},
+			handler: func(c *ReaderController) http.HandlerFunc { return c.ViewSelectionHandler },
+		},
+		{
+			name:    "one post",
+			pattern: "GET /posts/{postId}",
+			target:  "/posts/" + uuid.NewString(),
+			setupMock: func(m *MockReaderService) {
+				m.On("GetPost", user.UserId, mock.Anything).Return(rendered(), nil)
+			},
+			handler: func(c *ReaderController) http.HandlerFunc { return c.GetPostHandler },
+		},
+		{
+			name:    "posts of an author",
+			pattern: "GET /authors/{authorId}/posts",
+			target:  "/authors/" + authorId.String() + "/posts",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetAuthorPublishedPosts", authorId, "").Return([]*dto.GetPostResponse{rendered()}, nil)
+			},
+			handler: func(c *ReaderController) http.HandlerFunc { return c.GetAuthorPostsHandler },
+		},
+		{
+			name:    "search",
+			pattern: "GET /posts/search",
+			target:  "/posts/search?q=bold",
+			setupMock: func(m *MockReaderService) {
+				m.On("SearchPosts", "bold", defaultPageLimit, 0, uuid.Nil).Return(&dto.SearchPostsResponse{
+					Posts: []dto.SearchPostResponse{{GetPostResponse: *rendered(), Score: 1}},
+					Limit: defaultPageLimit,
+				}, nil)
+			},
+			handler: func(c *ReaderController) http.HandlerFunc { return c.SearchHandler },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serve := func(query string) *httptest.ResponseRecorder {
+				mockService := &MockReaderService{}
+				tt.setupMock(mockService)
+				router := http.NewServeMux()
+				router.HandleFunc(tt.pattern, tt.handler(&ReaderController{service: mockService}))
+
+				req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+				req.URL.RawQuery = strings.TrimPrefix(req.URL.RawQuery+"&"+query, "&")
+				req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+				return rr
+			}
+
+			rr := serve("")
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"content_html":"\u003cp\u003e\u003cstrong\u003ebold`)
+
+			rr = serve("raw=true")
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"content":"**bold**"`)
+			assert.NotContains(t, rr.Body.String(), "content_html")
+
+			assert.Equal(t, http.StatusBadRequest, serve("raw=maybe").Code)
+		})
+	}
+}
+
+func TestReaderController_ListExcerpts(t *testing.T) {
+	author := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	post := func() *dto.GetPostResponse {
+		return &dto.GetPostResponse{PostId: uuid.New(), Excerpt: "Short", Content: "Short and long", ContentHTML: "<p>Short and long</p>\n"}
+	}
+	mockService := &MockReaderService{}
+	mockService.On("GetAuthorPosts", author.UserId, newestFirst).Return([]*dto.GetPostResponse{post()}, nil)
+	mockService.On("GetLikedPosts", author.UserId, "").Return([]*dto.GetPostResponse{post()}, nil)
+	controller := &ReaderController{service: mockService}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/posts?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, author))
+		rr := httptest.NewRecorder()
+		controller.ViewSelectionHandler(rr, req)
+		return rr
+	}
+
+	for _, query := range []string{"", "liked=true"} {
+		rr := get(query)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var posts []dto.GetPostResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &posts))
+		assert.Equal(t, "Short", posts[0].Excerpt, query)
+		assert.NotContains(t, rr.Body.String(), `"content`, query)
+	}
+
+	rr := get("full=true")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var posts []dto.GetPostResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &posts))
+	assert.Equal(t, "Short", posts[0].Excerpt)
+	assert.Equal(t, "Short and long", posts[0].Content)
+	assert.Equal(t, "<p>Short and long</p>\n", posts[0].ContentHTML)
+
+	assert.Equal(t, http.StatusBadRequest, get("full=maybe").Code)
+	mockService.AssertExpectations(t)
+}
+
+// goldenPost has every field of a listed post set, the golden listing pins their names and order
+func goldenPost() *dto.GetPostResponse {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	publishAt := created.Add(48 * time.Hour)
+	return &dto.GetPostResponse{
+		PostId:     uuid.MustParse("6f1c2f3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f"),
+		Author:     dto.UserResponse{UserId: uuid.MustParse("0b8a9c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d"), DisplayName: "Alice", AvatarUrl: "http://localhost/avatars/a.png", Role: types.Author},
+		Title:      "Go & <JSON>",
+		Excerpt:    "Ünïcode \"quoted\" line",
+		Content:    "Ünïcode \"quoted\"\nline",
+		Status:     types.Scheduled,
+		Images:     []dto.AddImageResponse{{ImageId: uuid.MustParse("1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"), ImageUrl: "http://localhost/images/1.png"}},
+		Tags:       []string{"go", "json"},
+		Views:      12,
+		LikesCount: 3,
+		PublishAt:  &publishAt,
+		CreatedAt:  created,
+		UpdatedAt:  created.Add(time.Hour),
+
+		WordCount:          3,
+		ReadingTimeMinutes: 1,
+	}
+}
+
+const goldenListing = `[{"post_id":"6f1c2f3e-4b5a-4c6d-8e9f-0a1b2c3d4e5f",` +
+	`"author":{"user_id":"0b8a9c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d","display_name":"Alice","avatar_url":"http://localhost/avatars/a.png","role":"author"},` +
+	`"title":"Go \u0026 \u003cJSON\u003e","excerpt":"Ünïcode \"quoted\" line","content":"Ünïcode \"quoted\"\nline","status":"scheduled",` +
+	`"images":[{"image_id":"1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d","image_url":"http://localhost/images/1.png"}],` +
+	`"tags":["go","json"],"views":12,"likes_count":3,"publish_at":"2025-03-03T10:00:00Z",` +
+	`"created_at":"2025-03-01T10:00:00Z","updated_at":"2025-03-01T11:00:00Z","word_count":3,"reading_time_minutes":1},` +
+	`{"post_id":"00000000-0000-0000-0000-000000000000","author":{"user_id":"00000000-0000-0000-0000-000000000000","display_name":""},` +
+	`"title":"","excerpt":"","status":"","images":null,"tags":null,"views":0,"likes_count":0,` +
+	`"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","word_count":0,"reading_time_minutes":0}]`
+
+func TestReaderController_ListGoldenJSON(t *testing.T) {
+	posts := []*dto.GetPostResponse{goldenPost(), {}}
+	mockService := new(MockReaderService)
+	mockService.On("GetPublishedPosts", newestFirst).Return(posts, nil)
+	controller := &ReaderController{service: mockService}
+
+	req := httptest.NewRequest(http.MethodGet, "/posts?full=true", nil)
+	req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, &dto.UserDB{UserId: uuid.New(), Role: types.Reader}))
+	rr := httptest.NewRecorder()
+	controller.ViewSelectionHandler(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, goldenListing, rr.Body.String())
+
+	// the listing reads the same as encoding/json wrote it before
+	std, err := json.Marshal(posts)
+	require.NoError(t, err)
+	assert.Equal(t, string(std), rr.Body.String())
+}
+
+// BenchmarkPostListJSON compares a listing of 500 posts written by encoding/json, as the reader handlers
+// did before, with the generated easyjson code they use now
+func BenchmarkPostListJSON(b *testing.B) {
+	posts := make(dto.GetPostsResponse, 500)
+	for i := range posts {
+		post := goldenPost()
+		post.PostId = uuid.New()
+		post.Content = strings.Repeat(post.Content, 20)
+		posts[i] = post
+	}
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var body bytes.Buffer
+			if err := json.NewEncoder(&body).Encode([]*dto.GetPostResponse(posts)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("easyjson", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := easyjson.Marshal(posts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestReaderController_TrendingHandler(t *testing.T) {
+	reader := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	ranking := func() dto.TrendingPostsResponse {
+		return dto.TrendingPostsResponse{{GetPostResponse: dto.GetPostResponse{PostId: uuid.New(),
+			Content: "Body", ContentHTML: "<p>Body</p>", Excerpt: "Body"}, Score: 4.5}}
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		setupMock       func(*MockReaderService)
+		expectedStatus  int
+		shouldCallMock  bool
+		expectedContent string
+		expectedHTML    string
+	}{
+		{
+			name:  "default limit without content",
+			query: "",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetTrendingPosts", 20).Return(ranking(), nil)
+			},
+			expectedStatus: http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:  "full",
+			query: "limit=5&full=true",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetTrendingPosts", 5).Return(ranking(), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			shouldCallMock:  true,
+			expectedContent: "Body",
+			expectedHTML:    "<p>Body</p>",
+		},
+		{
+			name:  "full and raw",
+			query: "full=true&raw=true",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetTrendingPosts", 20).Return(ranking(), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			shouldCallMock:  true,
+			expectedContent: "Body",
+		},
+		{
+			name:           "bad limit",
+			query:          "limit=101",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bad full flag",
+			query:          "full=maybe",
+			setupMock:      func(m *MockReaderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unexpected error",
+			query: "",
+			setupMock: func(m *MockReaderService) {
+				m.On("GetTrendingPosts", 20).Return(nil, errors.ErrorHttpNoAuth)
+			},
+			expectedStatus: http.StatusBadGateway,
+			shouldCallMock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockReaderService{}
+			tt.setupMock(mockService)
+
+			controller := &ReaderController{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/trending?"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, reader))
+
+			rr := httptest.NewRecorder()
+			controller.TrendingHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code,
+				"Expected status %d, got %d. Response: %s",
+				tt.expectedStatus, rr.Code, rr.Body.String())
+
+			if tt.shouldCallMock {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetTrendingPosts")
+			}
+			if rr.Code == http.StatusOK {
+				var res dto.TrendingPostsResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+				require.Len(t, res, 1)
+				assert.Equal(t, 4.5, res[0].Score)
+				assert.Equal(t, "Body", res[0].Excerpt)
+				assert.Equal(t, tt.expectedContent, res[0].Content)
+				assert.Equal(t, tt.expectedHTML, res[0].ContentHTML)
+				assert.NotEmpty(t, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
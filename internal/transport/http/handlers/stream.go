@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+type PublishedPostsHub interface {
+	Subscribe() (<-chan *dto.PublishedPostEvent, func())
+}
+
+type StreamController struct {
+	hub PublishedPostsHub
+	// heartbeat is how often a stream gets a comment, proxies close connections that stay silent
+	heartbeat time.Duration
+}
+
+func NewStreamController(hub PublishedPostsHub, heartbeat time.Duration) *StreamController {
+	return &StreamController{hub, heartbeat}
+}
+
+// @Summary		Stream of published posts
+// @Description	Server-Sent Events, a post.published event with the post as data whenever one is published, and a comment every heartbeat interval.
+// @Description	The stream ends when the client falls behind or the server stops, EventSource reconnects by itself.
+// @Tags			Posts
+// @Produce		text/event-stream
+// @Security		BearerAuth
+// @Success		200	{object}	dto.PublishedPostEvent	"data of every post.published event"
+// @Failure		401	{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		500	{object}	dto.ErrorResponse	"Streaming not supported"
+// @Router			/posts/stream [get]
+func (c *StreamController) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	events, cancel := c.hub.Subscribe()
+	defer cancel()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// nginx would hold the events back in its buffer
+	header.Set("X-Accel-Buffering", "no")
+	c.extendDeadline(r, rc)
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		slogctx.Logger(r.Context()).Error("response cannot be streamed", slog.String("error", err.Error()))
+		return
+	}
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var frame []byte
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// the client fell behind or the server stops
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				slogctx.Logger(r.Context()).Error("published post event", slog.String("error", err.Error()))
+				continue
+			}
+			frame = fmt.Appendf(nil, "id: %s\nevent: post.published\ndata: %s\n\n", event.PostId, data)
+		case <-heartbeat.C:
+			frame = []byte(": heartbeat\n\n")
+		}
+
+		c.extendDeadline(r, rc)
+		if _, err := w.Write(frame); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// extendDeadline gives the next write two heartbeats, so a client that reads nothing is cut off
+// while the server wide WRITE_TIMEOUT does not end every stream
+func (c *StreamController) extendDeadline(r *http.Request, rc *http.ResponseController) {
+	err := rc.SetWriteDeadline(time.Now().Add(2 * c.heartbeat))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slogctx.Logger(r.Context()).Warn("write deadline not extended", slog.String("error", err.Error()))
+	}
+}
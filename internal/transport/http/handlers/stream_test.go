@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/core/service"
+)
+
+// flushRecorder reports every Flush on flushes. With release set a Flush then waits for a value
+// from it, like a write to a client that does not read.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes chan struct{}
+	release chan struct{}
+}
+
+func (r *flushRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.flushes <- struct{}{}
+	if r.release != nil {
+		<-r.release
+	}
+}
+
+// stream runs StreamHandler until cancel is called, done is closed once it returned
+func stream(t *testing.T, hub PublishedPostsHub, heartbeat time.Duration, release chan struct{}) (rec *flushRecorder, cancel func(), done chan struct{}) {
+	t.Helper()
+	rec = &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushes: make(chan struct{}, 16), release: release}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req := httptest.NewRequest(http.MethodGet, "/posts/stream", nil).WithContext(ctx)
+
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		NewStreamController(hub, heartbeat).StreamHandler(rec, req)
+	}()
+	return rec, cancel, done
+}
+
+func waitFlush(t *testing.T, rec *flushRecorder) {
+	t.Helper()
+	select {
+	case <-rec.flushes:
+	case <-time.After(time.Second):
+		t.Fatal("no flush")
+	}
+}
+
+func waitDone(t *testing.T, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream still open")
+	}
+}
+
+func TestStreamController_Events(t *testing.T) {
+	hub := service.NewPublishHub(4)
+	rec, cancel, done := stream(t, hub, time.Hour, nil)
+	waitFlush(t, rec)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	postId := uuid.New()
+	publishedAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	hub.Publish(&dto.PublishedPostEvent{PostId: postId, Title: "Hello", Tags: []string{"go"}, PublishedAt: publishedAt})
+	waitFlush(t, rec)
+
+	lines := strings.Split(rec.Body.String(), "\n")
+	require.Len(t, lines, 5, "one event and the blank line ending it")
+	assert.Equal(t, "id: "+postId.String(), lines[0])
+	assert.Equal(t, "event: post.published", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "data: {"))
+	assert.Contains(t, lines[2], `"title":"Hello"`)
+	assert.Contains(t, lines[2], `"published_at":"2025-12-01T10:00:00Z"`)
+	assert.Equal(t, []string{"", ""}, lines[3:])
+
+	cancel()
+	waitDone(t, done)
+	assert.Zero(t, hub.Subscribers(), "a client that left is unsubscribed")
+}
+
+func TestStreamController_Heartbeat(t *testing.T) {
+	rec, cancel, done := stream(t, service.NewPublishHub(4), 10*time.Millisecond, nil)
+	waitFlush(t, rec)
+	waitFlush(t, rec)
+	waitFlush(t, rec)
+	cancel()
+	waitDone(t, done)
+
+	assert.True(t, strings.HasPrefix(rec.Body.String(), ": heartbeat\n\n: heartbeat\n\n"))
+}
+
+func TestStreamController_SlowClientEvicted(t *testing.T) {
+	hub := service.NewPublishHub(1)
+	release := make(chan struct{}, 1)
+	// the headers go out, everything after hangs until release is closed
+	release <- struct{}{}
+	rec, _, done := stream(t, hub, time.Hour, release)
+	waitFlush(t, rec)
+
+	// the handler takes the first event and hangs sending it, the second fills the buffer
+	hub.Publish(&dto.PublishedPostEvent{PostId: uuid.New(), Title: "first"})
+	waitFlush(t, rec)
+	hub.Publish(&dto.PublishedPostEvent{PostId: uuid.New(), Title: "second"})
+	assert.Equal(t, 1, hub.Subscribers())
+	hub.Publish(&dto.PublishedPostEvent{PostId: uuid.New(), Title: "third"})
+	assert.Zero(t, hub.Subscribers(), "the client fell behind")
+
+	// what was buffered is still sent, then the stream ends
+	close(release)
+	waitDone(t, done)
+	assert.Contains(t, rec.Body.String(), `"title":"first"`)
+	assert.Contains(t, rec.Body.String(), `"title":"second"`)
+	assert.NotContains(t, rec.Body.String(), `"title":"third"`)
+}
+
+func TestStreamController_ServerStop(t *testing.T) {
+	hub := service.NewPublishHub(4)
+	rec, _, done := stream(t, hub, time.Hour, nil)
+	waitFlush(t, rec)
+
+	hub.Close()
+	waitDone(t, done)
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type SubscriptionService interface {
+	Subscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error)
+	Unsubscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error)
+	GetSubscriptions(ctx context.Context, userId uuid.UUID, limit, offset int) (*dto.SubscriptionsResponse, error)
+	GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) (*dto.NotificationsResponse, error)
+	MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (*dto.MarkNotificationsReadResponse, error)
+}
+
+type SubscriptionController struct {
+	service SubscriptionService
+}
+
+func NewSubscriptionController(service SubscriptionService) *SubscriptionController {
+	return &SubscriptionController{service}
+}
+
+// @Summary		Subscribe to author
+// @Description	Get a notification whenever the author publishes a post, subscribing again changes nothing
+// @Tags			Subscriptions
+// @Produce		json
+// @Security		BearerAuth
+// @Param			authorId	path		string	true	"Author ID"	format(uuid)
+// @Success		200			{object}	dto.SubscribeResponse
+// @Failure		400			{object}	dto.ErrorResponse	"Cannot subscribe to yourself"
+// @Failure		401			{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404			{object}	dto.ErrorResponse	"Author not found"
+// @Router			/authors/{authorId}/subscribe [post]
+func (c *SubscriptionController) SubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	c.handleSubscription(w, r, c.service.Subscribe)
+}
+
+// @Summary		Unsubscribe from author
+// @Description	Stop the notifications about the posts of an author, an author you do not follow is left as is
+// @Tags			Subscriptions
+// @Produce		json
+// @Security		BearerAuth
+// @Param			authorId	path		string	true	"Author ID"	format(uuid)
+// @Success		200			{object}	dto.SubscribeResponse
+// @Failure		401			{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Failure		404			{object}	dto.ErrorResponse	"Author not found"
+// @Router			/authors/{authorId}/subscribe [delete]
+func (c *SubscriptionController) UnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	c.handleSubscription(w, r, c.service.Unsubscribe)
+}
+
+func (c *SubscriptionController) handleSubscription(w http.ResponseWriter, r *http.Request, call func(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error)) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	authorId, err := uuid.Parse(r.PathValue("authorId"))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		return
+	}
+
+	res, err := call(r.Context(), user.UserId, authorId)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			jsonError(w, http.StatusNotFound, errors.ErrorHttpAuthorNotFound)
+		case errors.ErrorServiceSelfSubscription:
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpSelfSubscription)
+		default:
+			serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		List subscriptions
+// @Description	Page through the authors you follow, latest subscription first
+// @Tags			Subscriptions
+// @Produce		json
+// @Security		BearerAuth
+// @Param			limit	query		int	false	"Page size, 1-100"		default(20)
+// @Param			offset	query		int	false	"Subscriptions to skip"	default(0)
+// @Success		200		{object}	dto.SubscriptionsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/subscriptions [get]
+func (c *SubscriptionController) GetSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+
+	res, err := c.service.GetSubscriptions(r.Context(), user.UserId, limit, offset)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		List notifications
+// @Description	Page through the posts published by authors you follow, latest first, unread=true leaves out those marked read
+// @Tags			Subscriptions
+// @Produce		json
+// @Security		BearerAuth
+// @Param			unread	query		bool	false	"Only unread notifications"
+// @Param			limit	query		int		false	"Page size, 1-100"		default(20)
+// @Param			offset	query		int		false	"Notifications to skip"	default(0)
+// @Success		200		{object}	dto.NotificationsResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect query parameters"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/notifications [get]
+func (c *SubscriptionController) GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+	limit, offset, ok := parsePage(r)
+	if !ok {
+		jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+		return
+	}
+	unread := false
+	if raw := r.URL.Query().Get("unread"); raw != "" {
+		var err error
+		if unread, err = strconv.ParseBool(raw); err != nil {
+			jsonError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectQuery)
+			return
+		}
+	}
+
+	res, err := c.service.GetNotifications(r.Context(), user.UserId, unread, limit, offset)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
+
+// @Summary		Mark notifications read
+// @Description	Mark the listed notifications read, a request without body or ids marks all of them
+// @Tags			Subscriptions
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		dto.MarkNotificationsReadRequest	false	"Notifications to mark, at most 100"
+// @Success		200		{object}	dto.MarkNotificationsReadResponse
+// @Failure		400		{object}	dto.ErrorResponse	"Incorrect body\nBroken rules, listed in fields with code validation_failed"
+// @Failure		401		{object}	dto.ErrorResponse	"Missing or invalid access token"
+// @Router			/notifications/read [post]
+func (c *SubscriptionController) MarkNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+	if !ok {
+		missingUser(w, r)
+		return
+	}
+
+	req := &dto.MarkNotificationsReadRequest{}
+	if r.ContentLength != 0 {
+		bound, err := bind[dto.MarkNotificationsReadRequest](r)
+		if err != nil {
+			bodyError(w, err)
+			return
+		}
+		req = bound
+	}
+
+	res, err := c.service.MarkNotificationsRead(r.Context(), user.UserId, req.NotificationIds)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(res, w)
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type MockSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionService) Subscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error) {
+	args := m.Called(userId, authorId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SubscribeResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionService) Unsubscribe(ctx context.Context, userId, authorId uuid.UUID) (*dto.SubscribeResponse, error) {
+	args := m.Called(userId, authorId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SubscribeResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionService) GetSubscriptions(ctx context.Context, userId uuid.UUID, limit, offset int) (*dto.SubscriptionsResponse, error) {
+	args := m.Called(userId, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.SubscriptionsResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionService) GetNotifications(ctx context.Context, userId uuid.UUID, unread bool, limit, offset int) (*dto.NotificationsResponse, error) {
+	args := m.Called(userId, unread, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationsResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionService) MarkNotificationsRead(ctx context.Context, userId uuid.UUID, ids []uuid.UUID) (*dto.MarkNotificationsReadResponse, error) {
+	args := m.Called(userId, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MarkNotificationsReadResponse), args.Error(1)
+}
+
+// serveSubscriptions runs a request as user through the routes of the controller
+func serveSubscriptions(controller *SubscriptionController, user *dto.UserDB, method, target, body string) *httptest.ResponseRecorder {
+	router := http.NewServeMux()
+	router.HandleFunc("POST /authors/{authorId}/subscribe", controller.SubscribeHandler)
+	router.HandleFunc("DELETE /authors/{authorId}/subscribe", controller.UnsubscribeHandler)
+	router.HandleFunc("GET /subscriptions", controller.GetSubscriptionsHandler)
+	router.HandleFunc("GET /notifications", controller.GetNotificationsHandler)
+	router.HandleFunc("POST /notifications/read", controller.MarkNotificationsReadHandler)
+
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSubscriptionController_Subscribe(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	authorId := uuid.New()
+
+	tests := []struct {
+		name           string
+		method         string
+		authorId       string
+		setupMock      func(*MockSubscriptionService)
+		expectedStatus int
+	}{
+		{
+			name:     "subscribe",
+			method:   http.MethodPost,
+			authorId: authorId.String(),
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("Subscribe", user.UserId, authorId).Return(&dto.SubscribeResponse{AuthorId: authorId, Subscribed: true, SubscribersCount: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "subscribe to yourself",
+			method:   http.MethodPost,
+			authorId: user.UserId.String(),
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("Subscribe", user.UserId, user.UserId).Return(nil, errors.ErrorServiceSelfSubscription)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "subscribe to non author",
+			method:   http.MethodPost,
+			authorId: authorId.String(),
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("Subscribe", user.UserId, authorId).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid author ID",
+			method:         http.MethodPost,
+			authorId:       "invalid-uuid",
+			setupMock:      func(m *MockSubscriptionService) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "unsubscribe",
+			method:   http.MethodDelete,
+			authorId: authorId.String(),
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("Unsubscribe", user.UserId, authorId).Return(&dto.SubscribeResponse{AuthorId: authorId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSubscriptionService{}
+			tt.setupMock(mockService)
+
+			rr := serveSubscriptions(NewSubscriptionController(mockService), user, tt.method, "/authors/"+tt.authorId+"/subscribe", "")
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var res dto.SubscribeResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+				assert.Equal(t, authorId, res.AuthorId)
+				assert.Equal(t, tt.method == http.MethodPost, res.Subscribed)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionController_GetSubscriptions(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	mockService := &MockSubscriptionService{}
+	mockService.On("GetSubscriptions", user.UserId, 10, 20).Return(&dto.SubscriptionsResponse{
+		Subscriptions: []dto.SubscriptionResponse{{Author: dto.UserResponse{UserId: uuid.New(), Email: "author@example.com"}}},
+		Total:         21,
+		Limit:         10,
+		Offset:        20,
+	}, nil)
+	controller := NewSubscriptionController(mockService)
+
+	rr := serveSubscriptions(controller, user, http.MethodGet, "/subscriptions?limit=10&offset=20", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res dto.SubscriptionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, 21, res.Total)
+	assert.Equal(t, "author@example.com", res.Subscriptions[0].Author.Email)
+
+	assert.Equal(t, http.StatusBadRequest, serveSubscriptions(controller, user, http.MethodGet, "/subscriptions?limit=500", "").Code)
+	assert.Equal(t, http.StatusInternalServerError, serveSubscriptions(controller, nil, http.MethodGet, "/subscriptions", "").Code, "the auth middleware was skipped")
+	mockService.AssertExpectations(t)
+}
+
+func TestSubscriptionController_GetNotifications(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	mockService := &MockSubscriptionService{}
+	mockService.On("GetNotifications", user.UserId, false, defaultPageLimit, 0).Return(&dto.NotificationsResponse{
+		Notifications: []dto.NotificationResponse{},
+		Limit:         defaultPageLimit,
+	}, nil)
+	mockService.On("GetNotifications", user.UserId, true, 5, 0).Return(&dto.NotificationsResponse{
+		Notifications: []dto.NotificationResponse{{NotificationId: uuid.New(), Type: types.NotificationPostPublished}},
+		Total:         1,
+		Unread:        1,
+		Limit:         5,
+	}, nil)
+	controller := NewSubscriptionController(mockService)
+
+	rr := serveSubscriptions(controller, user, http.MethodGet, "/notifications", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"notifications":[]`)
+
+	rr = serveSubscriptions(controller, user, http.MethodGet, "/notifications?unread=true&limit=5", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res dto.NotificationsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Unread)
+	require.Len(t, res.Notifications, 1)
+	assert.Nil(t, res.Notifications[0].ReadAt)
+
+	assert.Equal(t, http.StatusBadRequest, serveSubscriptions(controller, user, http.MethodGet, "/notifications?unread=maybe", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serveSubscriptions(controller, user, http.MethodGet, "/notifications?offset=-1", "").Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSubscriptionController_MarkNotificationsRead(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	id := uuid.New()
+	many := make([]string, 101)
+	for i := range many {
+		many[i] = `"` + uuid.NewString() + `"`
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockSubscriptionService)
+		expectedStatus int
+	}{
+		{
+			name: "listed",
+			body: `{"notification_ids":["` + id.String() + `"]}`,
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("MarkNotificationsRead", user.UserId, []uuid.UUID{id}).Return(&dto.MarkNotificationsReadResponse{Marked: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "all without body",
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("MarkNotificationsRead", user.UserId, []uuid.UUID(nil)).Return(&dto.MarkNotificationsReadResponse{Marked: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "all with empty object",
+			body: `{}`,
+			setupMock: func(m *MockSubscriptionService) {
+				m.On("MarkNotificationsRead", user.UserId, []uuid.UUID(nil)).Return(&dto.MarkNotificationsReadResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			body:           `{"notification_ids":["nope"]}`,
+			setupMock:      func(m *MockSubscriptionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many ids",
+			body:           `{"notification_ids":[` + strings.Join(many, ",") + `]}`,
+			setupMock:      func(m *MockSubscriptionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSubscriptionService{}
+			tt.setupMock(mockService)
+
+			rr := serveSubscriptions(NewSubscriptionController(mockService), user, http.MethodPost, "/notifications/read", tt.body)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
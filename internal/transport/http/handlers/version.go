@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	json "github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/version"
+)
+
+type VersionController struct {
+	info version.Info
+}
+
+func NewVersionController(info version.Info) *VersionController {
+	return &VersionController{info}
+}
+
+// VersionHandler answers with the build of the server and the Go version it was built with, anyone may
+// ask. Like the probes it lives outside /api and so outside the swagger docs.
+func (c *VersionController) VersionHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.MarshalToHTTPResponseWriter(&dto.VersionResponse{
+		Version:   c.info.Version,
+		Commit:    c.info.Commit,
+		BuildDate: c.info.Date,
+		GoVersion: c.info.GoVersion,
+	}, w)
+}
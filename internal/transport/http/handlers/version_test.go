@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/pkg/version"
+)
+
+func TestVersionController_VersionHandler(t *testing.T) {
+	controller := NewVersionController(version.Info{Version: "v1.4.0", Commit: "5f06797", Date: "2026-10-15T12:00:00Z", GoVersion: "go1.24.5"})
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	rr := httptest.NewRecorder()
+	controller.VersionHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"version":"v1.4.0","commit":"5f06797","build_date":"2026-10-15T12:00:00Z","go_version":"go1.24.5"}`, rr.Body.String())
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/xkarasb/blog/pkg/apiversion"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// APIVersion stores the version the api was mounted for in the request context. Under the unversioned
+// alias a path starting with something like /v2/ asks for a version this server does not have, it gets
+// 404 instead of being read as a post route.
+func APIVersion(version apiversion.Version) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if version == apiversion.Unversioned {
+				segment, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+				if apiversion.LooksLike(segment) {
+					writeError(w, http.StatusNotFound, errors.ErrorHttpUnknownVersion)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(apiversion.WithVersion(r.Context(), version)))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/apiversion"
+)
+
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    apiversion.Version
+		path       string
+		wantStatus int
+	}{
+		{"versioned", apiversion.V1, "/posts", http.StatusOK},
+		{"alias", apiversion.Unversioned, "/posts", http.StatusOK},
+		{"version further down the path", apiversion.Unversioned, "/posts/v2", http.StatusOK},
+		{"unknown version", apiversion.Unversioned, "/v2/posts", http.StatusNotFound},
+		{"unknown version root", apiversion.Unversioned, "/v10", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen apiversion.Version
+			called := false
+			handler := APIVersion(tt.version)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				seen = apiversion.FromContext(r.Context())
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.True(t, called)
+				assert.Equal(t, tt.version, seen)
+				return
+			}
+			assert.False(t, called)
+			var body dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, "unknown api version", body.Error)
+		})
+	}
+}
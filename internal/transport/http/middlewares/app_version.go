@@ -0,0 +1,14 @@
+package middlewares
+
+import "net/http"
+
+// AppVersion names the build that answered in X-App-Version on every response, so it shows up in HAR
+// captures and proxy logs of a bug report
+func AppVersion(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-App-Version", version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
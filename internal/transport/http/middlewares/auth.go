@@ -2,16 +2,22 @@ package middlewares
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/google/uuid"
+	json "github.com/mailru/easyjson"
 	"github.com/xkarasb/blog/internal/core/dto"
 	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
 	"github.com/xkarasb/blog/pkg/types"
 )
 
 type AuthService interface {
-	AuthorizeUser(token string) (*dto.UserDB, error)
+	AuthorizeSession(ctx context.Context, token string) (*dto.UserDB, uuid.UUID, error)
+	AuthorizeApiKey(ctx context.Context, key string) (*dto.UserDB, []types.Scope, error)
 }
 
 type AuthMiddlewareManager struct {
@@ -22,45 +28,110 @@ func NewAuthMiddlewareManager(service AuthService) *AuthMiddlewareManager {
 	return &AuthMiddlewareManager{service}
 }
 
+// AuthMiddleware lets through requests with a valid bearer token and stores their user and session in
+// the context. Without one the answer is 401, so clients know to log in again.
+//
+// An `ApiKey` instead of a bearer token stores the scopes of the key in place of the session. What a
+// key may do is up to the routes: each one names the scope it needs with RequireScope, or takes no
+// keys at all with RequireSession.
 func (m *AuthMiddlewareManager) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		auth_header := r.Header.Get("Authorization")
-		if auth_header == "" {
-			http.Error(w, errors.ErrorHttpNoAuth.Error(), http.StatusForbidden)
+		scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !found || token == "" {
+			unauthorized(w, `Bearer`, errors.ErrorHttpNoAuth)
 			return
 		}
 
-		rawToken := strings.Split(auth_header, " ")
-		if len(rawToken) != 2 {
-			http.Error(w, errors.ErrorHttpNoAuth.Error(), http.StatusForbidden)
+		var user *dto.UserDB
+		ctx := r.Context()
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			var sessionId uuid.UUID
+			var err error
+			user, sessionId, err = m.service.AuthorizeSession(ctx, token)
+			if err != nil {
+				unauthorized(w, `Bearer error="invalid_token"`, errors.ErrorHttpInvalidToken)
+				return
+			}
+			ctx = context.WithValue(ctx, types.CtxSession, sessionId)
+		case strings.EqualFold(scheme, "ApiKey"):
+			var scopes []types.Scope
+			var err error
+			user, scopes, err = m.service.AuthorizeApiKey(ctx, token)
+			if err != nil {
+				unauthorized(w, `ApiKey error="invalid_token"`, errors.ErrorHttpInvalidApiKey)
+				return
+			}
+			ctx = context.WithValue(ctx, types.CtxScopes, scopes)
+		default:
+			unauthorized(w, `Bearer`, errors.ErrorHttpNoAuth)
 			return
 		}
-		token := rawToken[1]
-		user, err := m.service.AuthorizeUser(token)
 
-		if err != nil {
-			http.Error(w, errors.ErrorHttpNoAuth.Error(), http.StatusForbidden)
+		slogctx.AddAttrs(r.Context(), slog.String("user_id", user.UserId.String()))
+		ctx = context.WithValue(ctx, types.CtxUser, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireSession answers 403 to requests made with an API key. It guards what only the user may do
+// after logging in, like managing the keys themselves, so a leaked key cannot make more.
+func (m *AuthMiddlewareManager) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, apiKey := r.Context().Value(types.CtxScopes).([]types.Scope); apiKey {
+			writeError(w, http.StatusForbidden, errors.ErrorHttpSessionRequired)
 			return
 		}
-
-		ctx := context.WithValue(r.Context(), types.CtxUser, user)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r)
 	})
 }
 
-func (m *AuthMiddlewareManager) AuthorOnlyMiddleware(next http.Handler) http.Handler {
+// RequireScope answers 403 to requests made with an API key lacking scope, logins pass. It wraps
+// single routes like RequireRole, reads need types.ScopeRead and writes types.ScopeWrite.
+func (m *AuthMiddlewareManager) RequireScope(scope types.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scopes, apiKey := r.Context().Value(types.CtxScopes).([]types.Scope); apiKey && !slices.Contains(scopes, scope) {
+				writeError(w, http.StatusForbidden, errors.ErrorHttpInsufficientScope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole answers 403 to users of another role. It wraps single routes, so a group of routes
+// under one path can mix what every user may do with what only some roles may. It runs behind
+// AuthMiddleware, a request without a user means the route was wired wrong and gets 500.
+func (m *AuthMiddlewareManager) RequireRole(role types.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return m.roleOnly(role, next)
+	}
+}
+
+func (m *AuthMiddlewareManager) roleOnly(role types.Role, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		userRaw := ctx.Value(types.CtxUser)
-		user, ok := userRaw.(*dto.UserDB)
+		user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
 		if !ok {
-			http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+			slogctx.Logger(r.Context()).Error("role check without authenticated user", slog.String("endpoint", r.URL.Path))
+			writeError(w, http.StatusInternalServerError, errors.ErrorHttpInternal)
 			return
 		}
-		if user.Role == types.Author {
-			next.ServeHTTP(w, r)
-		} else {
-			http.Error(w, errors.ErrorHttpIncorrectUser.Error(), http.StatusForbidden)
+		if user.Role != role {
+			writeError(w, http.StatusForbidden, errors.ErrorHttpAccessDenied)
+			return
 		}
+		next.ServeHTTP(w, r)
 	})
 }
+
+func unauthorized(w http.ResponseWriter, challenge string, err error) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeError(w, http.StatusUnauthorized, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.MarshalToHTTPResponseWriter(&dto.ErrorResponse{Error: err.Error()}, w)
+}
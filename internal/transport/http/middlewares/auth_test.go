@@ -0,0 +1,222 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestAuthMiddlewareManager_RoleMiddlewares(t *testing.T) {
+	m := NewAuthMiddlewareManager(nil)
+
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		user       interface{}
+		wantStatus int
+	}{
+		{"admin passes admin only", m.RequireRole(types.Admin), &dto.UserDB{UserId: uuid.New(), Role: types.Admin}, http.StatusOK},
+		{"author stopped by admin only", m.RequireRole(types.Admin), &dto.UserDB{UserId: uuid.New(), Role: types.Author}, http.StatusForbidden},
+		{"reader stopped by admin only", m.RequireRole(types.Admin), &dto.UserDB{UserId: uuid.New(), Role: types.Reader}, http.StatusForbidden},
+		{"no user fails admin only", m.RequireRole(types.Admin), nil, http.StatusInternalServerError},
+		{"author passes author only", m.RequireRole(types.Author), &dto.UserDB{UserId: uuid.New(), Role: types.Author}, http.StatusOK},
+		{"admin stopped by author only", m.RequireRole(types.Author), &dto.UserDB{UserId: uuid.New(), Role: types.Admin}, http.StatusForbidden},
+		{"reader stopped by author only", m.RequireRole(types.Author), &dto.UserDB{UserId: uuid.New(), Role: types.Reader}, http.StatusForbidden},
+		{"no user fails author only", m.RequireRole(types.Author), nil, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := tt.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, tt.user))
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, called)
+			if tt.wantStatus == http.StatusForbidden {
+				var body dto.ErrorResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.Equal(t, errors.ErrorHttpAccessDenied.Error(), body.Error)
+			}
+		})
+	}
+}
+
+type fakeAuthService struct {
+	users    map[string]*dto.UserDB
+	sessions map[string]uuid.UUID
+	apiKeys  map[string][]types.Scope
+	// keyUser owns every key of apiKeys
+	keyUser *dto.UserDB
+}
+
+func (f *fakeAuthService) AuthorizeApiKey(ctx context.Context, key string) (*dto.UserDB, []types.Scope, error) {
+	scopes, ok := f.apiKeys[key]
+	if !ok {
+		return nil, nil, stderrors.New("key is revoked")
+	}
+	return f.keyUser, scopes, nil
+}
+
+func (f *fakeAuthService) AuthorizeSession(ctx context.Context, token string) (*dto.UserDB, uuid.UUID, error) {
+	user, ok := f.users[token]
+	if !ok {
+		return nil, uuid.Nil, stderrors.New("token is expired")
+	}
+	return user, f.sessions[token], nil
+}
+
+func TestAuthMiddlewareManager_AuthMiddleware(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Reader}
+	sessionId := uuid.New()
+	m := NewAuthMiddlewareManager(&fakeAuthService{
+		users:    map[string]*dto.UserDB{"valid": user},
+		sessions: map[string]uuid.UUID{"valid": sessionId},
+	})
+
+	tests := []struct {
+		name          string
+		header        string
+		wantStatus    int
+		wantChallenge string
+		wantError     error
+	}{
+		{"valid token", "Bearer valid", http.StatusOK, "", nil},
+		{"lower case scheme", "bearer valid", http.StatusOK, "", nil},
+		{"no header", "", http.StatusUnauthorized, `Bearer`, errors.ErrorHttpNoAuth},
+		{"no token", "Bearer", http.StatusUnauthorized, `Bearer`, errors.ErrorHttpNoAuth},
+		{"other scheme", "Basic valid", http.StatusUnauthorized, `Bearer`, errors.ErrorHttpNoAuth},
+		{"expired token", "Bearer expired", http.StatusUnauthorized, `Bearer error="invalid_token"`, errors.ErrorHttpInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *dto.UserDB
+			var gotSession uuid.UUID
+			handler := m.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = r.Context().Value(types.CtxUser).(*dto.UserDB)
+				gotSession, _ = r.Context().Value(types.CtxSession).(uuid.UUID)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantChallenge, rr.Header().Get("WWW-Authenticate"))
+			if tt.wantError == nil {
+				assert.Equal(t, user, got)
+				assert.Equal(t, sessionId, gotSession)
+				return
+			}
+			assert.Nil(t, got)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			var body dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError.Error(), body.Error)
+		})
+	}
+}
+
+func TestAuthMiddlewareManager_ApiKeys(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	m := NewAuthMiddlewareManager(&fakeAuthService{
+		users:    map[string]*dto.UserDB{"session": user},
+		sessions: map[string]uuid.UUID{"session": uuid.New()},
+		apiKeys: map[string][]types.Scope{
+			"blog_read":  {types.ScopeRead},
+			"blog_write": {types.ScopeRead, types.ScopeWrite},
+		},
+		keyUser: user,
+	})
+
+	tests := []struct {
+		name          string
+		method        string
+		scope         types.Scope
+		header        string
+		wantStatus    int
+		wantChallenge string
+		wantError     error
+	}{
+		{"read key reads", http.MethodGet, types.ScopeRead, "ApiKey blog_read", http.StatusOK, "", nil},
+		{"lower case scheme", http.MethodHead, types.ScopeRead, "apikey blog_read", http.StatusOK, "", nil},
+		{"read key cannot write", http.MethodPost, types.ScopeWrite, "ApiKey blog_read", http.StatusForbidden, "", errors.ErrorHttpInsufficientScope},
+		{"read key cannot delete", http.MethodDelete, types.ScopeWrite, "ApiKey blog_read", http.StatusForbidden, "", errors.ErrorHttpInsufficientScope},
+		{"the route decides, not the method", http.MethodPost, types.ScopeRead, "ApiKey blog_read", http.StatusOK, "", nil},
+		{"write key writes", http.MethodPatch, types.ScopeWrite, "ApiKey blog_write", http.StatusOK, "", nil},
+		{"revoked key", http.MethodGet, types.ScopeRead, "ApiKey blog_revoked", http.StatusUnauthorized, `ApiKey error="invalid_token"`, errors.ErrorHttpInvalidApiKey},
+		{"key as bearer token", http.MethodGet, types.ScopeRead, "Bearer blog_read", http.StatusUnauthorized, `Bearer error="invalid_token"`, errors.ErrorHttpInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scopes []types.Scope
+			var session any
+			handler := m.AuthMiddleware(m.RequireScope(tt.scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				scopes, _ = r.Context().Value(types.CtxScopes).([]types.Scope)
+				session = r.Context().Value(types.CtxSession)
+			})))
+
+			req := httptest.NewRequest(tt.method, "/api/posts", nil)
+			req.Header.Set("Authorization", tt.header)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantChallenge, rr.Header().Get("WWW-Authenticate"))
+			if tt.wantError == nil {
+				assert.NotEmpty(t, scopes)
+				assert.Nil(t, session, "a key has no session")
+				return
+			}
+			var body dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError.Error(), body.Error)
+		})
+	}
+
+	t.Run("logins need no scope", func(t *testing.T) {
+		handler := m.AuthMiddleware(m.RequireScope(types.ScopeWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+		req := httptest.NewRequest(http.MethodPost, "/api/posts", nil)
+		req.Header.Set("Authorization", "Bearer session")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("session only", func(t *testing.T) {
+		for header, want := range map[string]int{"ApiKey blog_write": http.StatusForbidden, "Bearer session": http.StatusOK} {
+			handler := m.AuthMiddleware(m.RequireSession(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/api-keys", nil)
+			req.Header.Set("Authorization", header)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, want, rr.Code, header)
+			if want == http.StatusForbidden {
+				assert.Contains(t, rr.Body.String(), errors.ErrorHttpSessionRequired.Error())
+			}
+		}
+	})
+}
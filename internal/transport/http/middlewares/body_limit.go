@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// BodyLimit stops reading a request body after limit bytes, the handler then answers 413. A body
+// announced larger by Content-Length is refused before the handler runs, zero leaves bodies unlimited.
+// Nested limits never raise each other, the smallest one wins.
+func BodyLimit(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				// the unread body makes the server close the connection after answering
+				writeError(w, http.StatusRequestEntityTooLarge, errors.ErrorHttpBodyTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
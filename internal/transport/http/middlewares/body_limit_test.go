@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	json "github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int64
+		body       string
+		streamed   bool
+		wantStatus int
+		wantRead   string
+	}{
+		{name: "within limit", limit: 8, body: "12345678", wantStatus: http.StatusOK, wantRead: "12345678"},
+		{name: "declared too large", limit: 8, body: "123456789", wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "streamed too large", limit: 8, body: "123456789", streamed: true, wantStatus: http.StatusRequestEntityTooLarge, wantRead: "12345678"},
+		{name: "no limit", limit: 0, body: strings.Repeat("a", 1<<10), streamed: true, wantStatus: http.StatusOK, wantRead: strings.Repeat("a", 1<<10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var read string
+			handler := BodyLimit(tt.limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, err := io.ReadAll(r.Body)
+				read = string(data)
+				if err != nil {
+					var tooLarge *http.MaxBytesError
+					assert.ErrorAs(t, err, &tooLarge)
+					writeError(w, http.StatusRequestEntityTooLarge, err)
+				}
+			}))
+
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.streamed {
+				body = struct{ io.Reader }{body}
+			}
+			req := httptest.NewRequest(http.MethodPost, "/posts", body)
+			if tt.streamed {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRead, read)
+			if tt.wantStatus == http.StatusRequestEntityTooLarge && !tt.streamed {
+				var apiErr dto.ErrorResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &apiErr))
+				assert.Equal(t, "request body too large", apiErr.Error)
+			}
+		})
+	}
+}
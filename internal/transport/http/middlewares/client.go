@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/xkarasb/blog/pkg/clientctx"
+)
+
+// maxUserAgentLength keeps the audit log from storing whatever a client sends
+const maxUserAgentLength = 512
+
+// ClientInfo stores the address and user agent of the caller in the request context. Behind a proxy
+// trustProxy takes the address from X-Real-IP, set it only when the proxy overwrites that header.
+func ClientInfo(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := clientctx.Client{
+				IP:        clientIP(r, trustProxy),
+				UserAgent: r.UserAgent(),
+			}
+			if len(client.UserAgent) > maxUserAgentLength {
+				client.UserAgent = client.UserAgent[:maxUserAgentLength]
+			}
+			next.ServeHTTP(w, r.WithContext(clientctx.WithClient(r.Context(), client)))
+		})
+	}
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+			return ip.String()
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
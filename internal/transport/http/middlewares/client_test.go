@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/pkg/clientctx"
+)
+
+func TestClientInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		userAgent  string
+		trustProxy bool
+		expected   clientctx.Client
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:51234",
+			userAgent:  "curl/8.0",
+			expected:   clientctx.Client{IP: "203.0.113.7", UserAgent: "curl/8.0"},
+		},
+		{
+			name:       "proxy header ignored without trust",
+			remoteAddr: "10.0.0.2:51234",
+			realIP:     "203.0.113.7",
+			expected:   clientctx.Client{IP: "10.0.0.2"},
+		},
+		{
+			name:       "proxy header trusted",
+			remoteAddr: "10.0.0.2:51234",
+			realIP:     "203.0.113.7",
+			trustProxy: true,
+			expected:   clientctx.Client{IP: "203.0.113.7"},
+		},
+		{
+			name:       "malformed proxy header",
+			remoteAddr: "10.0.0.2:51234",
+			realIP:     "203.0.113.7, 10.0.0.1",
+			trustProxy: true,
+			expected:   clientctx.Client{IP: "10.0.0.2"},
+		},
+		{
+			name:       "long user agent is cut",
+			remoteAddr: "[2001:db8::1]:443",
+			userAgent:  strings.Repeat("a", maxUserAgentLength+10),
+			expected:   clientctx.Client{IP: "2001:db8::1", UserAgent: strings.Repeat("a", maxUserAgentLength)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen clientctx.Client
+			handler := ClientInfo(tt.trustProxy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = clientctx.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if tt.userAgent != "" {
+				req.Header.Set("User-Agent", tt.userAgent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, seen)
+		})
+	}
+}
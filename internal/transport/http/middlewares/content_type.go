@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+const (
+	MediaTypeJSON      = "application/json"
+	MediaTypeMultipart = "multipart/form-data"
+)
+
+// ContentType answers 415 to a request whose body is none of mediaTypes, parameters such as charset are
+// not compared. A body without Content-Type is refused too, requests without a body always pass.
+func ContentType(mediaTypes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// a chunked body has an unknown length of -1
+			if r.ContentLength != 0 {
+				mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+				if err != nil || !slices.Contains(mediaTypes, mediaType) {
+					writeError(w, http.StatusUnsupportedMediaType, errors.ErrorHttpUnsupportedMediaType)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	json "github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		contentType string
+		body        string
+		chunked     bool
+		wantStatus  int
+	}{
+		{name: "json", allowed: []string{MediaTypeJSON}, contentType: "application/json", body: "{}", wantStatus: http.StatusNoContent},
+		{name: "json with charset", allowed: []string{MediaTypeJSON}, contentType: "Application/JSON; charset=utf-8", body: "{}", wantStatus: http.StatusNoContent},
+		{name: "text", allowed: []string{MediaTypeJSON}, contentType: "text/plain", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "multipart for json", allowed: []string{MediaTypeJSON}, contentType: "multipart/form-data; boundary=x", body: "--x--", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing with body", allowed: []string{MediaTypeJSON}, body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed", allowed: []string{MediaTypeJSON}, contentType: "application/", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing chunked", allowed: []string{MediaTypeJSON}, body: "{}", chunked: true, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing without body", allowed: []string{MediaTypeJSON}, wantStatus: http.StatusNoContent},
+		{name: "other without body", allowed: []string{MediaTypeJSON}, contentType: "text/plain", wantStatus: http.StatusNoContent},
+		{name: "multipart", allowed: []string{MediaTypeMultipart}, contentType: "multipart/form-data; boundary=x", body: "--x--", wantStatus: http.StatusNoContent},
+		{name: "json for multipart", allowed: []string{MediaTypeMultipart}, contentType: "application/json", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := ContentType(tt.allowed...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusNoContent, called)
+			if tt.wantStatus == http.StatusUnsupportedMediaType {
+				var apiErr dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &apiErr))
+				assert.Equal(t, "unsupported content type", apiErr.Error)
+			}
+		})
+	}
+}
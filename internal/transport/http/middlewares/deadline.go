@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+// Deadline gives a route its own read and write deadline instead of the server wide timeouts,
+// uploads of large images need longer than any other request
+func Deadline(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rc := http.NewResponseController(w)
+			deadline := time.Now().Add(timeout)
+			if err := rc.SetReadDeadline(deadline); err != nil {
+				slogctx.Logger(r.Context()).Warn("read deadline not extended", slog.String("error", err.Error()))
+			}
+			if err := rc.SetWriteDeadline(deadline); err != nil {
+				slogctx.Logger(r.Context()).Warn("write deadline not extended", slog.String("error", err.Error()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
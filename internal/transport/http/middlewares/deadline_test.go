@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadline(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/slow", slow)
+	mux.Handle("/upload", Deadline(time.Second)(slow))
+
+	// wrapped like the api router, so the deadline has to reach the connection through them
+	server := httptest.NewUnstartedServer(Logger(Recover(mux)))
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/upload")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+
+	resp, err = http.Get(server.URL + "/slow")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	assert.Error(t, err, "without Deadline the server wide write timeout applies")
+}
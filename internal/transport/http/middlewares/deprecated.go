@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Deprecated marks the /post/ aliases kept for older clients, Link names the /posts/ path replacing the one called
+func Deprecated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		// RequestURI still has the /api prefix the routers never see
+		path, _, _ := strings.Cut(r.RequestURI, "?")
+		w.Header().Set("Link", "<"+strings.Replace(path, "/post/", "/posts/", 1)+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// RequireDocsAccess keeps the api docs to operators: requests with the basic auth credentials user and
+// password get through, as do requests with the bearer token of an admin. Without user basic auth is
+// off. Anyone else gets 401, with a Basic challenge when it is on so browsers ask for the credentials.
+func (m *AuthMiddlewareManager) RequireDocsAccess(user, password string) func(http.Handler) http.Handler {
+	challenge := `Bearer`
+	if user != "" {
+		challenge = `Basic realm="docs", charset="UTF-8"`
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if gotUser, gotPassword, ok := r.BasicAuth(); ok && user != "" {
+				// both are compared, so the answer takes as long whichever of them was wrong
+				userOk, passwordOk := equalSecret(gotUser, user), equalSecret(gotPassword, password)
+				if userOk && passwordOk {
+					next.ServeHTTP(w, r)
+					return
+				}
+				unauthorized(w, challenge, errors.ErrorHttpNoAuth)
+				return
+			}
+			if m.admitAdmin(w, r, challenge) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
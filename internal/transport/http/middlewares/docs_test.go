@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestAuthMiddlewareManager_RequireDocsAccess(t *testing.T) {
+	m := NewAuthMiddlewareManager(&fakeAuthService{
+		users: map[string]*dto.UserDB{
+			"admin":  {UserId: uuid.New(), Role: types.Admin},
+			"author": {UserId: uuid.New(), Role: types.Author},
+		},
+	})
+	basic := `Basic realm="docs", charset="UTF-8"`
+
+	tests := []struct {
+		name          string
+		user          string
+		request       func(r *http.Request)
+		wantStatus    int
+		wantChallenge string
+	}{
+		{"no credentials", "docs", func(r *http.Request) {}, http.StatusUnauthorized, basic},
+		{"basic auth", "docs", func(r *http.Request) { r.SetBasicAuth("docs", "s3cret") }, http.StatusOK, ""},
+		{"wrong password", "docs", func(r *http.Request) { r.SetBasicAuth("docs", "guess") }, http.StatusUnauthorized, basic},
+		{"wrong user", "docs", func(r *http.Request) { r.SetBasicAuth("admin", "s3cret") }, http.StatusUnauthorized, basic},
+		{"admin token", "docs", func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin") }, http.StatusOK, ""},
+		{"author token", "docs", func(r *http.Request) { r.Header.Set("Authorization", "Bearer author") }, http.StatusForbidden, ""},
+		{"expired token", "docs", func(r *http.Request) { r.Header.Set("Authorization", "Bearer expired") }, http.StatusUnauthorized, basic},
+		{"basic auth off", "", func(r *http.Request) { r.SetBasicAuth("", "") }, http.StatusUnauthorized, `Bearer`},
+		{"admin token without basic auth", "", func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin") }, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := m.RequireDocsAccess(tt.user, "s3cret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+			tt.request(req)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, called)
+			assert.Equal(t, tt.wantChallenge, rr.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
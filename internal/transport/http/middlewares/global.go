@@ -3,6 +3,9 @@ package middlewares
 import (
 	"log/slog"
 	"net/http"
+	"time"
+
+	"github.com/xkarasb/blog/pkg/slogctx"
 )
 
 func JSONHandler(next http.Handler) http.Handler {
@@ -22,13 +25,26 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Logger writes a record per request once it was answered, with the user_id AuthMiddleware
+// found on the way and the request_id of RequestId
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
 		rw := &responseWriter{
 			w, 200,
 		}
 
-		next.ServeHTTP(rw, r)
-		slog.Info("http request", slog.String("method", r.Method), slog.String("endpoint", r.URL.Path), slog.Int("status", rw.statusCode))
+		ctx := slogctx.WithAttrs(r.Context())
+		next.ServeHTTP(rw, r.WithContext(ctx))
+		slogctx.Logger(ctx).LogAttrs(ctx, slog.LevelInfo, "http request",
+			slog.String("method", r.Method),
+			slog.String("path", RedactPath(r.URL.Path)),
+			slog.Int("status", rw.statusCode),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		)
 	})
 }
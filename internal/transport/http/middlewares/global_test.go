@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestLogger(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Email: "author@example.com", Role: types.Author,
+		PasswordHash: "hash"}
+	m := NewAuthMiddlewareManager(&fakeAuthService{users: map[string]*dto.UserDB{"valid": user}})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantUser   bool
+	}{
+		{name: "authenticated", token: "valid", wantStatus: http.StatusCreated, wantUser: true},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+			handler := RequestId(Logger(m.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user := r.Context().Value(types.CtxUser).(*dto.UserDB)
+				slogctx.Logger(r.Context()).Info("from service", slog.Any("user", user))
+				w.WriteHeader(http.StatusCreated)
+			}))))
+
+			req := httptest.NewRequest(http.MethodPost, "/posts?draft=1", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			require.Equal(t, tt.wantStatus, rr.Code)
+
+			var records []map[string]interface{}
+			for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+				var record map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(line), &record))
+				records = append(records, record)
+			}
+			request := records[len(records)-1]
+			assert.Equal(t, "http request", request["msg"])
+			assert.Equal(t, http.MethodPost, request["method"])
+			assert.Equal(t, "/posts", request["path"])
+			assert.Equal(t, float64(tt.wantStatus), request["status"])
+			assert.Contains(t, request, "duration_ms")
+			assert.Equal(t, rr.Header().Get(RequestIdHeader), request["request_id"])
+			if !tt.wantUser {
+				require.Len(t, records, 1)
+				assert.NotContains(t, request, "user_id")
+				return
+			}
+			assert.Equal(t, user.UserId.String(), request["user_id"])
+
+			require.Len(t, records, 2)
+			service := records[0]
+			assert.Equal(t, user.UserId.String(), service["user_id"])
+			assert.Equal(t, map[string]interface{}{
+				"user_id": user.UserId.String(), "email": user.Email, "role": string(types.Author),
+			}, service["user"])
+			assert.NotContains(t, logs.String(), "refresh")
+			assert.NotContains(t, logs.String(), "hash")
+		})
+	}
+}
+
+func TestLogger_RedactsPreviewToken(t *testing.T) {
+	logs := captureLogs(t)
+	var token string
+	handler := Logger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, token, _ = strings.Cut(r.URL.Path, "/public/preview/")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/public/preview/s3cr3t-token", nil))
+	assert.Equal(t, "s3cr3t-token", token, "the handler still gets the token")
+	assert.NotContains(t, logs.String(), "s3cr3t-token")
+	assert.Contains(t, logs.String(), `"path":"/public/preview/{token}"`)
+}
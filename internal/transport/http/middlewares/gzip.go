@@ -0,0 +1,178 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gzipWriters keeps writers between requests, a new one allocates several hundred KB
+var gzipWriters = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// compressedTypes are answered as they are, compressing them again only costs time
+var compressedTypes = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/gzip", "application/x-gzip", "application/zip", "application/zstd", "application/octet-stream",
+}
+
+// Gzip compresses responses of clients that accept it. Bodies shorter than minSize are sent as they are,
+// so are already compressed content types and responses carrying a Content-Encoding of their own.
+func Gzip(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize, status: http.StatusOK}
+			// a panic leaves the buffered body to Recover, which answers 500 instead
+			next.ServeHTTP(gw, r)
+			gw.close()
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, q=0 refuses it
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		if weight, err := strconv.ParseFloat(q, 64); err == nil && weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter holds the body back until minSize bytes decide whether it is compressed,
+// the status is sent along with that decision
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.decided {
+		return
+	}
+	w.status = code
+	// informational answers go out at once, bodyless ones have nothing to compress
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		w.start(false, nil)
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if len(w.buf)+len(p) < w.minSize {
+			w.buf = append(w.buf, p...)
+			return len(p), nil
+		}
+		// p itself is not copied, only what came before it
+		if err := w.start(true, p); err != nil {
+			return 0, err
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what was written so far, a streamed body is compressed however short it starts
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.start(true, nil)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to move its deadlines
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// start writes the status and the buffered body, compressed when compress is set and the response allows it.
+// next is the write that comes right after, it only helps to sniff the content type.
+func (w *gzipResponseWriter) start(compress bool, next []byte) error {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf)+len(next) > 0 {
+		// sniffed here, the underlying writer would see only gzip
+		header.Set("Content-Type", http.DetectContentType(append(w.buf, next[:min(len(next), 512)]...)))
+	}
+	if compress && header.Get("Content-Encoding") == "" && !isCompressedType(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		// the length written by the handler is the one of the plain body
+		header.Del("Content-Length")
+		// the compressed body is another representation, its tag can only be weak
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+	return err
+}
+
+// close sends a body that stayed below minSize and finishes a compressed one
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		w.start(false, nil)
+	}
+	if w.gz == nil {
+		return
+	}
+	w.gz.Close()
+	w.gz.Reset(io.Discard)
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+}
+
+func isCompressedType(contentType string) bool {
+	for _, prefix := range compressedTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	json "github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+)
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	reader, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGzip(t *testing.T) {
+	long := strings.Repeat("compressible ", 200)
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        http.HandlerFunc
+		wantGzip       bool
+		wantBody       string
+		wantStatus     int
+	}{
+		{
+			name:           "json written by easyjson",
+			acceptEncoding: "gzip, deflate, br",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				json.MarshalToHTTPResponseWriter(&dto.ErrorResponse{Error: long}, w)
+			},
+			wantGzip:   true,
+			wantBody:   `{"error":"` + long + `"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:           "below the threshold",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				json.MarshalToHTTPResponseWriter(&dto.ErrorResponse{Error: "short"}, w)
+			},
+			wantBody:   `{"error":"short"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "threshold reached over several writes",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				for range 4 {
+					w.Write([]byte(long[:300]))
+				}
+			},
+			wantGzip:   true,
+			wantBody:   strings.Repeat(long[:300], 4),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not accepted",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(long))
+			},
+			wantBody:   long,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "refused with q=0",
+			acceptEncoding: "gzip;q=0, br",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(long))
+			},
+			wantBody:   long,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "already compressed type",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Write([]byte(long))
+			},
+			wantBody:   long,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "encoded by the handler",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				w.Write([]byte(long))
+			},
+			wantBody:   long,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "no content",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			Gzip(1024)(tt.handler).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			if !tt.wantGzip {
+				assert.NotEqual(t, "gzip", rec.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+				return
+			}
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+			assert.Empty(t, rec.Header().Get("Content-Length"), "the plain length must not be sent")
+			assert.Less(t, rec.Body.Len(), len(tt.wantBody))
+			assert.Equal(t, tt.wantBody, gunzip(t, rec.Body))
+		})
+	}
+}
+
+func TestGzip_ETagWeakened(t *testing.T) {
+	handler := Gzip(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte(strings.Repeat("a", 64)))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/public/posts", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, `W/"abc"`, rec.Header().Get("ETag"))
+}
+
+func TestGzip_Flush(t *testing.T) {
+	handler := Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first "))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("second"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "first second", gunzip(t, rec.Body))
+}
+
+func TestGzip_PanicLeftToRecover(t *testing.T) {
+	handler := Recover(Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		panic("boom")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.NotContains(t, rec.Body.String(), "partial")
+}
+
+func BenchmarkGzip(b *testing.B) {
+	body := []byte(strings.Repeat(`{"title":"post","content":"long and compressible"},`, 2000))
+	handler := Gzip(1024)(JSONHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
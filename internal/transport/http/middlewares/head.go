@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Head answers HEAD like GET without the body. The body is counted instead of sent, so Content-Length
+// is the one a GET gets also when the server would have had to stream it in chunks.
+func Head(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hw := &headResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(hw, r)
+		hw.send(true)
+	})
+}
+
+// headResponseWriter holds the status back until the whole body was counted
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+	sent   bool
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	// informational answers are followed by the real one
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.length += len(p)
+	return len(p), nil
+}
+
+// Flush sends the headers right away, a stream has no length to wait for
+func (w *headResponseWriter) Flush() {
+	w.send(false)
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// send writes the status once, with the counted Content-Length when counted is set and the handler set none
+func (w *headResponseWriter) send(counted bool) {
+	if w.sent {
+		return
+	}
+	w.sent = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	bodyless := w.status == http.StatusNoContent || w.status == http.StatusNotModified
+	if counted && !bodyless && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.length))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
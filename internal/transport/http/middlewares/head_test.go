@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHead(t *testing.T) {
+	// longer than the buffer the server sends a Content-Length from by itself
+	long := strings.Repeat("a long body ", 1000)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /long", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < len(long); i += 100 {
+			io.WriteString(w, long[i:min(i+100, len(long))])
+		}
+	})
+	mux.HandleFunc("GET /sized", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "3")
+		io.WriteString(w, "abc")
+	})
+	mux.HandleFunc("GET /unchanged", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	mux.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, ": hello\n\n")
+		require.NoError(t, http.NewResponseController(w).Flush())
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(Head(mux))
+	defer server.Close()
+
+	head := func(path string) *http.Response {
+		resp, err := http.Head(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("counted body", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/long")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, long, string(body))
+
+		resp = head("/long")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(len(long)), resp.ContentLength)
+	})
+
+	t.Run("length of the handler", func(t *testing.T) {
+		assert.Equal(t, int64(3), head("/sized").ContentLength)
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		resp := head("/unchanged")
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Content-Length"))
+	})
+
+	t.Run("stream gets its headers", func(t *testing.T) {
+		resp := head("/stream")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(-1), resp.ContentLength)
+	})
+
+	t.Run("other methods untouched", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		Head(mux).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/sized", nil))
+		assert.Equal(t, "abc", rr.Body.String())
+	})
+}
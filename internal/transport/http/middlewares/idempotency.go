@@ -0,0 +1,157 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// IdempotencyKeyHeader names the key a client sends to make a write safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKey is the longest key the idempotency_keys table takes
+const maxIdempotencyKey = 255
+
+type IdempotencyStore interface {
+	Begin(ctx context.Context, userId uuid.UUID, key, endpoint, hash string) (*dto.IdempotencyKeyDB, error)
+	Complete(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, userId uuid.UUID, key string) error
+}
+
+// Idempotent makes writes sent with an Idempotency-Key safe to retry. The first request under a key of
+// the user runs and its response is stored, the same request again gets that response replayed with
+// Idempotent-Replayed. The key sent with another method, path or body, or while the first request still
+// runs, is 409. A response of 500 or above, no response at all or a panic is not stored, a retry runs the
+// request again.
+//
+// It goes after the auth middleware, requests without a key and reads pass untouched.
+func Idempotent(store IdempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			user, ok := r.Context().Value(types.CtxUser).(*dto.UserDB)
+			if key == "" || !ok || r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKey {
+				writeError(w, http.StatusBadRequest, errors.ErrorHttpIdempotencyKeyTooLong)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytes *http.MaxBytesError
+				if errors.As(err, &maxBytes) {
+					writeError(w, http.StatusRequestEntityTooLarge, errors.ErrorHttpBodyTooLarge)
+					return
+				}
+				writeError(w, http.StatusBadRequest, errors.ErrorHttpIncorrectBody)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			hash := requestHash(r.Method, r.URL.Path, body)
+			held, err := store.Begin(ctx, user.UserId, key, r.Method+" "+r.URL.Path, hash)
+			if err != nil {
+				slogctx.Logger(ctx).Error("claim idempotency key", slog.String("error", err.Error()))
+				writeError(w, http.StatusInternalServerError, errors.ErrorHttpInternal)
+				return
+			}
+			if held != nil {
+				replay(w, held, hash)
+				return
+			}
+
+			// the client may be gone already, what the request did is to be remembered all the same
+			ctx = context.WithoutCancel(ctx)
+			rw := &recordingWriter{ResponseWriter: w}
+			answered := false
+			defer func() {
+				if answered {
+					return
+				}
+				// a panicking handler goes on panicking to the recover middleware, its key is let go on
+				// the way so that a retry runs the request again instead of being 409 until the key expires
+				if err := store.Release(ctx, user.UserId, key); err != nil {
+					slogctx.Logger(ctx).Error("release idempotency key", slog.String("error", err.Error()))
+				}
+			}()
+			next.ServeHTTP(rw, r)
+			answered = true
+
+			// a handler that wrote nothing has no response to replay
+			if rw.status == 0 || rw.status >= http.StatusInternalServerError {
+				err = store.Release(ctx, user.UserId, key)
+			} else {
+				err = store.Complete(ctx, user.UserId, key, rw.status, w.Header().Get("Content-Type"), rw.body.Bytes())
+			}
+			if err != nil {
+				slogctx.Logger(ctx).Error("store idempotency key", slog.String("error", err.Error()))
+			}
+		})
+	}
+}
+
+// requestHash tells requests apart that came with the same key
+func requestHash(method, path string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(method + " " + path + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// replay answers a request whose key is held by an earlier one
+func replay(w http.ResponseWriter, held *dto.IdempotencyKeyDB, hash string) {
+	switch {
+	case held.RequestHash != hash:
+		writeError(w, http.StatusConflict, errors.ErrorHttpIdempotencyKeyReused)
+	case held.Status == 0:
+		writeError(w, http.StatusConflict, errors.ErrorHttpIdempotencyInProgress)
+	default:
+		if held.ContentType != "" {
+			w.Header().Set("Content-Type", held.ContentType)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(held.Status)
+		w.Write(held.ResponseBody)
+	}
+}
+
+// recordingWriter keeps a copy of the response it passes on
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to move its deadlines
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,213 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// fakeIdempotencyStore keeps the keys in a map without expiry
+type fakeIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]*dto.IdempotencyKeyDB
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{keys: map[string]*dto.IdempotencyKeyDB{}}
+}
+
+func (f *fakeIdempotencyStore) Begin(ctx context.Context, userId uuid.UUID, key, endpoint, hash string) (*dto.IdempotencyKeyDB, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if held, ok := f.keys[userId.String()+key]; ok {
+		copied := *held
+		return &copied, nil
+	}
+	f.keys[userId.String()+key] = &dto.IdempotencyKeyDB{UserId: userId, Key: key, Endpoint: endpoint, RequestHash: hash}
+	return nil, nil
+}
+
+func (f *fakeIdempotencyStore) Complete(ctx context.Context, userId uuid.UUID, key string, status int, contentType string, body []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	held := f.keys[userId.String()+key]
+	held.Status, held.ContentType, held.ResponseBody = status, contentType, body
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Release(ctx context.Context, userId uuid.UUID, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.keys, userId.String()+key)
+	return nil
+}
+
+func TestIdempotent(t *testing.T) {
+	user := &dto.UserDB{UserId: uuid.New(), Role: types.Author}
+	send := func(handler http.Handler, method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+	// counting answers with the body it read and how often it ran
+	counting := func(status int) (http.Handler, *int) {
+		runs := 0
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			runs++
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]any{"body": string(body), "run": runs})
+		}), &runs
+	}
+	errorOf := func(t *testing.T, rr *httptest.ResponseRecorder) string {
+		var body dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		return body.Error
+	}
+
+	t.Run("a repeat gets the first response", func(t *testing.T) {
+		next, runs := counting(http.StatusCreated)
+		handler := Idempotent(newFakeIdempotencyStore())(next)
+
+		first := send(handler, http.MethodPatch, "/posts/1/status", "publish-1", `{"status":"published"}`)
+		second := send(handler, http.MethodPatch, "/posts/1/status", "publish-1", `{"status":"published"}`)
+
+		assert.Equal(t, 1, *runs)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.JSONEq(t, `{"body":"{\"status\":\"published\"}","run":1}`, second.Body.String(), "the handler still read the body")
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("another body under the key", func(t *testing.T) {
+		next, runs := counting(http.StatusOK)
+		handler := Idempotent(newFakeIdempotencyStore())(next)
+
+		send(handler, http.MethodPut, "/posts/1", "edit-1", `{"title":"a"}`)
+		rr := send(handler, http.MethodPut, "/posts/1", "edit-1", `{"title":"b"}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, errors.ErrorHttpIdempotencyKeyReused.Error(), errorOf(t, rr))
+
+		rr = send(handler, http.MethodPut, "/posts/2", "edit-1", `{"title":"a"}`)
+		assert.Equal(t, http.StatusConflict, rr.Code, "another post is another request")
+		assert.Equal(t, 1, *runs)
+	})
+
+	t.Run("first request still running", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		var rr *httptest.ResponseRecorder
+		handler := Idempotent(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rr = send(Idempotent(store)(http.NotFoundHandler()), http.MethodPut, "/posts/1", "edit-1", `{}`)
+		}))
+
+		send(handler, http.MethodPut, "/posts/1", "edit-1", `{}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, errors.ErrorHttpIdempotencyInProgress.Error(), errorOf(t, rr))
+	})
+
+	t.Run("server errors are not kept", func(t *testing.T) {
+		next, runs := counting(http.StatusBadGateway)
+		store := newFakeIdempotencyStore()
+		handler := Idempotent(store)(next)
+
+		send(handler, http.MethodDelete, "/posts/1", "trash-1", "")
+		rr := send(handler, http.MethodDelete, "/posts/1", "trash-1", "")
+		assert.Equal(t, 2, *runs)
+		assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+		assert.Empty(t, store.keys)
+	})
+
+	t.Run("a panic lets the key go", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		handler := Idempotent(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		assert.PanicsWithValue(t, "boom", func() {
+			send(handler, http.MethodPost, "/posts", "create-1", `{}`)
+		}, "the panic goes on to the recover middleware")
+		assert.Empty(t, store.keys)
+
+		next, runs := counting(http.StatusCreated)
+		rr := send(Idempotent(store)(next), http.MethodPost, "/posts", "create-1", `{}`)
+		assert.Equal(t, http.StatusCreated, rr.Code, "a retry runs instead of being 409")
+		assert.Equal(t, 1, *runs)
+	})
+
+	t.Run("no response is not kept", func(t *testing.T) {
+		store := newFakeIdempotencyStore()
+		handler := Idempotent(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		send(handler, http.MethodDelete, "/posts/1", "trash-1", "")
+		assert.Empty(t, store.keys)
+	})
+
+	t.Run("client errors are kept", func(t *testing.T) {
+		next, runs := counting(http.StatusConflict)
+		handler := Idempotent(newFakeIdempotencyStore())(next)
+
+		send(handler, http.MethodPut, "/posts/1", "edit-1", `{}`)
+		rr := send(handler, http.MethodPut, "/posts/1", "edit-1", `{}`)
+		assert.Equal(t, 1, *runs)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("without a key or for reads", func(t *testing.T) {
+		next, runs := counting(http.StatusOK)
+		store := newFakeIdempotencyStore()
+		handler := Idempotent(store)(next)
+
+		send(handler, http.MethodPut, "/posts/1", "", `{}`)
+		send(handler, http.MethodPut, "/posts/1", "", `{}`)
+		send(handler, http.MethodGet, "/posts/1/stats", "stats-1", "")
+		send(handler, http.MethodGet, "/posts/1/stats", "stats-1", "")
+		assert.Equal(t, 4, *runs)
+		assert.Empty(t, store.keys)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		next, runs := counting(http.StatusOK)
+		handler := Idempotent(newFakeIdempotencyStore())(next)
+
+		rr := send(handler, http.MethodPut, "/posts/1", strings.Repeat("k", 256), `{}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, errors.ErrorHttpIdempotencyKeyTooLong.Error(), errorOf(t, rr))
+		assert.Zero(t, *runs)
+	})
+
+	t.Run("body over the limit", func(t *testing.T) {
+		next, runs := counting(http.StatusOK)
+		handler := Idempotent(newFakeIdempotencyStore())(next)
+
+		req := httptest.NewRequest(http.MethodPut, "/posts/1", strings.NewReader(strings.Repeat("x", 64)))
+		req.Header.Set(IdempotencyKeyHeader, "edit-1")
+		req = req.WithContext(context.WithValue(req.Context(), types.CtxUser, user))
+		rr := httptest.NewRecorder()
+		req.Body = http.MaxBytesReader(rr, req.Body, 16)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Zero(t, *runs)
+	})
+}
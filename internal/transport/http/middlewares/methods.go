@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// Routes tells the methods the path of a request is served for, nil for a path it does not know
+type Routes interface {
+	Allow(r *http.Request) []string
+}
+
+// Methods answers OPTIONS with the methods routes serve the path for, and a method they do not serve it
+// for with 405, both with Allow. Paths none of routes knows go on to next. It goes before auth, the
+// methods of a path are no secret and a client probing them has no token yet.
+func Methods(routes ...Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var allow []string
+			for _, route := range routes {
+				allow = append(allow, route.Allow(r)...)
+			}
+			if len(allow) == 0 || (r.Method != http.MethodOptions && slices.Contains(allow, r.Method)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allow = append(allow, http.MethodOptions)
+			slices.Sort(allow)
+			w.Header().Set("Allow", strings.Join(slices.Compact(allow), ", "))
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			writeError(w, http.StatusMethodNotAllowed, errors.ErrorHttpMethodNotAllowed)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// fakeRoutes serves every path in it for the methods listed
+type fakeRoutes map[string][]string
+
+func (f fakeRoutes) Allow(r *http.Request) []string {
+	return f[r.URL.Path]
+}
+
+func TestMethods(t *testing.T) {
+	routes := fakeRoutes{"/posts": {"GET", "HEAD", "POST"}}
+	other := fakeRoutes{"/posts": {"DELETE"}, "/tags": {"GET", "HEAD"}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := Methods(routes, other)(next)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "options", method: http.MethodOptions, path: "/posts", wantStatus: http.StatusNoContent, wantAllow: "DELETE, GET, HEAD, OPTIONS, POST"},
+		{name: "options of one route group", method: http.MethodOptions, path: "/tags", wantStatus: http.StatusNoContent, wantAllow: "GET, HEAD, OPTIONS"},
+		{name: "served method", method: http.MethodPost, path: "/posts", wantStatus: http.StatusTeapot},
+		{name: "method of another group", method: http.MethodDelete, path: "/posts", wantStatus: http.StatusTeapot},
+		{name: "head", method: http.MethodHead, path: "/tags", wantStatus: http.StatusTeapot},
+		{name: "method not served", method: http.MethodPut, path: "/posts", wantStatus: http.StatusMethodNotAllowed, wantAllow: "DELETE, GET, HEAD, OPTIONS, POST"},
+		{name: "unknown path", method: http.MethodPut, path: "/nope", wantStatus: http.StatusTeapot},
+		{name: "options of unknown path", method: http.MethodOptions, path: "/nope", wantStatus: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantAllow, rr.Header().Get("Allow"))
+			if tt.wantStatus == http.StatusMethodNotAllowed {
+				assert.JSONEq(t, `{"error":"`+errors.ErrorHttpMethodNotAllowed.Error()+`"}`, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func TestAuthMiddlewareManager_RequireOperator(t *testing.T) {
+	m := NewAuthMiddlewareManager(&fakeAuthService{
+		users: map[string]*dto.UserDB{
+			"admin":  {UserId: uuid.New(), Role: types.Admin},
+			"author": {UserId: uuid.New(), Role: types.Author},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{"no header", "operator-token", "", http.StatusUnauthorized},
+		{"operator token", "operator-token", "Bearer operator-token", http.StatusOK},
+		{"lower case scheme", "operator-token", "bearer operator-token", http.StatusOK},
+		{"wrong token", "operator-token", "Bearer operator", http.StatusUnauthorized},
+		{"token in basic auth", "operator-token", "Basic operator-token", http.StatusUnauthorized},
+		{"admin", "operator-token", "Bearer admin", http.StatusOK},
+		{"author", "operator-token", "Bearer author", http.StatusForbidden},
+		{"no operator token configured", "", "Bearer ", http.StatusUnauthorized},
+		{"admin without operator token", "", "Bearer admin", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := m.RequireOperator(tt.token)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, called)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+type headerTracker struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *headerTracker) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *headerTracker) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to move its deadlines
+func (w *headerTracker) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Recover turns a panic in a handler into a logged 500 instead of a reset connection.
+// http.ErrAbortHandler is re-raised, it is the documented way to abort a response.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &headerTracker{ResponseWriter: w}
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			slogctx.Logger(r.Context()).Error("panic recovered",
+				slog.Any("panic", rec),
+				slog.String("method", r.Method),
+				slog.String("endpoint", RedactPath(r.URL.Path)),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			if tw.wroteHeader {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, errors.ErrorHttpInternal)
+		}()
+
+		next.ServeHTTP(tw, r)
+	})
+}
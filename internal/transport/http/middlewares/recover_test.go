@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+func newPanickingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		var user *dto.UserDB
+		_ = user.Email
+	})
+	mux.HandleFunc("/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(RequestId(Logger(Recover(mux))))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecover(t *testing.T) {
+	logs := captureLogs(t)
+	server := newPanickingServer(t)
+
+	resp, err := http.Get(server.URL + "/panic")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var body dto.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, errors.ErrorHttpInternal.Error(), body.Error)
+
+	assert.Contains(t, logs.String(), `"msg":"panic recovered"`)
+	assert.Contains(t, logs.String(), "nil pointer dereference")
+	assert.Contains(t, logs.String(), `"request_id":"`+resp.Header.Get(RequestIdHeader)+`"`)
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL + "/ok")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestRecover_AbortHandlerIsNotRecovered(t *testing.T) {
+	logs := captureLogs(t)
+	server := newPanickingServer(t)
+
+	resp, err := http.Get(server.URL + "/abort")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	assert.Error(t, err)
+	assert.NotContains(t, logs.String(), "panic recovered")
+
+	resp, err = http.Get(server.URL + "/ok")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
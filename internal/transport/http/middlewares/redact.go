@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// secretSegments are the paths whose next segment is a secret, a preview token lets anyone holding
+// it read the draft
+var secretSegments = []string{"/public/preview/"}
+
+// RedactPath hides the secrets a path carries behind their placeholder of the route, for logs and spans
+func RedactPath(path string) string {
+	for _, segment := range secretSegments {
+		before, secret, ok := strings.Cut(path, segment)
+		if !ok || secret == "" {
+			continue
+		}
+		_, rest, found := strings.Cut(secret, "/")
+		if found {
+			rest = "/" + rest
+		}
+		path = before + segment + "{token}" + rest
+	}
+	return path
+}
+
+// originalURL is what RedactURL replaced in a request, Unredact puts it back
+type originalURL struct {
+	url        *url.URL
+	requestURI string
+}
+
+// RedactURL passes next the request with RedactPath applied to its URL, so that the tracing it wraps
+// records no secrets. The handler traced goes behind Unredact to get the real URL back.
+func RedactURL(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := RedactPath(r.URL.Path)
+		if path == r.URL.Path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), types.CtxURL, originalURL{r.URL, r.RequestURI})
+		redacted := r.WithContext(ctx)
+		redacted.URL = &url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host, Path: path, RawQuery: r.URL.RawQuery}
+		redacted.RequestURI = redacted.URL.RequestURI()
+		next.ServeHTTP(w, redacted)
+	})
+}
+
+// Unredact undoes RedactURL for the handlers past the tracing
+func Unredact(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		original, ok := r.Context().Value(types.CtxURL).(originalURL)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		restored := r.WithContext(r.Context())
+		restored.URL, restored.RequestURI = original.url, original.requestURI
+		next.ServeHTTP(w, restored)
+	})
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactPath(t *testing.T) {
+	tests := map[string]string{
+		"/public/preview/secret":        "/public/preview/{token}",
+		"/api/v1/public/preview/secret": "/api/v1/public/preview/{token}",
+		"/public/preview/secret/extra":  "/public/preview/{token}/extra",
+		"/public/preview/":              "/public/preview/",
+		"/public/posts/7":               "/public/posts/7",
+		"/posts/7/preview":              "/posts/7/preview",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, RedactPath(path), path)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	var seen, served string
+	handler := RedactURL(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.URL.String() + " " + r.RequestURI
+		Unredact(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served = r.URL.String() + " " + r.RequestURI
+		})).ServeHTTP(w, r)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/public/preview/secret?x=1", nil))
+	assert.Equal(t, "/api/v1/public/preview/%7Btoken%7D?x=1 /api/v1/public/preview/%7Btoken%7D?x=1", seen)
+	assert.Equal(t, "/api/v1/public/preview/secret?x=1 /api/v1/public/preview/secret?x=1", served, "the handler gets the token")
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/public/posts?x=1", nil))
+	assert.Equal(t, seen, served, "paths without secrets pass as they are")
+}
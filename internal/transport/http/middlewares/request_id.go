@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+// incoming ids longer than that are replaced, they end up in every log line
+const maxRequestIdLength = 128
+
+func RequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIdHeader)
+		if !validRequestId(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIdHeader, id)
+		next.ServeHTTP(w, r.WithContext(slogctx.WithRequestId(r.Context(), id)))
+	})
+}
+
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/pkg/slogctx"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return buf
+}
+
+func TestRequestId(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "incoming id is kept", incoming: "abc-123", keep: true},
+		{name: "missing id is generated", incoming: ""},
+		{name: "id with spaces is replaced", incoming: "abc 123\nfake log line"},
+		{name: "too long id is replaced", incoming: strings.Repeat("a", maxRequestIdLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+
+			var seen string
+			handler := RequestId(Logger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = slogctx.RequestId(r.Context())
+				slogctx.Logger(r.Context()).Info("from service")
+				w.WriteHeader(http.StatusTeapot)
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIdHeader, tt.incoming)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			id := rr.Header().Get(RequestIdHeader)
+			assert.Equal(t, seen, id)
+			if tt.keep {
+				assert.Equal(t, tt.incoming, id)
+			} else {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			require.Len(t, lines, 2)
+			for _, line := range lines {
+				var record map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(line), &record))
+				assert.Equal(t, id, record["request_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import "net/http"
+
+// SecurityHeaders are sent with every response, an empty value leaves its header out
+type SecurityHeaders struct {
+	ContentTypeOptions    string
+	FrameOptions          string
+	ReferrerPolicy        string
+	ContentSecurityPolicy string
+}
+
+// SecureHeaders keeps browsers from sniffing, framing or running what the server answers, an image
+// uploaded as SVG or HTML included. Headers set before, or by the handler itself, are kept.
+func SecureHeaders(headers SecurityHeaders) func(http.Handler) http.Handler {
+	values := map[string]string{
+		"X-Content-Type-Options":  headers.ContentTypeOptions,
+		"X-Frame-Options":         headers.FrameOptions,
+		"Referrer-Policy":         headers.ReferrerPolicy,
+		"Content-Security-Policy": headers.ContentSecurityPolicy,
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			for name, value := range values {
+				if value != "" && header.Get(name) == "" {
+					header.Set(name, value)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+func TestSecureHeaders(t *testing.T) {
+	headers := SecurityHeaders{
+		ContentTypeOptions:    "nosniff",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		ContentSecurityPolicy: "default-src 'none'",
+	}
+
+	tests := []struct {
+		name    string
+		headers SecurityHeaders
+		handler http.HandlerFunc
+		want    map[string]string
+	}{
+		{
+			name:    "answer",
+			headers: headers,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			want: map[string]string{
+				"X-Content-Type-Options":  "nosniff",
+				"X-Frame-Options":         "DENY",
+				"Referrer-Policy":         "no-referrer",
+				"Content-Security-Policy": "default-src 'none'",
+			},
+		},
+		{
+			name:    "error",
+			headers: headers,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeError(w, http.StatusNotFound, errors.ErrorHttpPostNotFound)
+			},
+			want: map[string]string{
+				"X-Frame-Options":         "DENY",
+				"Content-Security-Policy": "default-src 'none'",
+			},
+		},
+		{
+			name:    "handler keeps its own",
+			headers: headers,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Security-Policy", "default-src 'self'")
+				w.WriteHeader(http.StatusOK)
+			},
+			want: map[string]string{
+				"X-Frame-Options":         "DENY",
+				"Content-Security-Policy": "default-src 'self'",
+			},
+		},
+		{
+			name:    "empty left out",
+			headers: SecurityHeaders{ContentTypeOptions: "nosniff"},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			want: map[string]string{
+				"X-Content-Type-Options":  "nosniff",
+				"X-Frame-Options":         "",
+				"Content-Security-Policy": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			SecureHeaders(tt.headers)(tt.handler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+			for name, value := range tt.want {
+				assert.Equal(t, value, rr.Header().Get(name), name)
+			}
+		})
+	}
+}
+
+func TestSecureHeaders_SetBefore(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	outer := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	outer(SecureHeaders(SecurityHeaders{FrameOptions: "DENY"})(http.HandlerFunc(handler))).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "SAMEORIGIN", rr.Header().Get("X-Frame-Options"))
+	assert.Len(t, rr.Header().Values("X-Frame-Options"), 1)
+}
@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/xkarasb/blog/pkg/jsonbody"
+)
+
+// StrictJSON makes the handlers decode request bodies with jsonbody in strict mode, it does nothing unless strict
+func StrictJSON(strict bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !strict {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(jsonbody.WithStrict(r.Context())))
+		})
+	}
+}
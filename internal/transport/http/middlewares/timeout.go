@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// Timeout cancels the context of a request after timeout, so the storage and database calls made for it
+// give up instead of holding the connection. Handlers answer a cancelled request themselves, one that
+// returns without writing anything gets 504 here. Zero leaves requests unbounded.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			if !tw.wrote && ctx.Err() == context.DeadlineExceeded {
+				writeError(w, http.StatusGatewayTimeout, errors.ErrorHttpRequestTimeout)
+			}
+		})
+	}
+}
+
+// timeoutResponseWriter remembers whether the handler answered, the 504 of Timeout is never sent on top
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	// informational answers are followed by the real one
+	if code >= http.StatusOK {
+		w.wrote = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutResponseWriter) Write(p []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *timeoutResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
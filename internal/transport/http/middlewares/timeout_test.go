@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	json "github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+// headerCounter counts the status lines a handler chain sends
+type headerCounter struct {
+	*httptest.ResponseRecorder
+	headers int
+}
+
+func (c *headerCounter) WriteHeader(code int) {
+	c.headers++
+	c.ResponseRecorder.WriteHeader(code)
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		handler     http.HandlerFunc
+		wantStatus  int
+		wantTimeout bool
+	}{
+		{
+			name:    "answered in time",
+			timeout: time.Second,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "handler answers the cancelled request",
+			timeout: 10 * time.Millisecond,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				writeError(w, http.StatusGatewayTimeout, errors.ErrorHttpRequestTimeout)
+			},
+			wantStatus:  http.StatusGatewayTimeout,
+			wantTimeout: true,
+		},
+		{
+			name:    "handler returns without an answer",
+			timeout: 10 * time.Millisecond,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			wantStatus:  http.StatusGatewayTimeout,
+			wantTimeout: true,
+		},
+		{
+			name:    "late answer is kept",
+			timeout: 10 * time.Millisecond,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				w.WriteHeader(http.StatusOK)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "no timeout",
+			timeout: 0,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, ok := r.Context().Deadline()
+				assert.False(t, ok)
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &headerCounter{ResponseRecorder: httptest.NewRecorder()}
+			Timeout(tt.timeout)(tt.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, 1, rec.headers, "the response is written exactly once")
+			if tt.wantTimeout {
+				var apiErr dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &apiErr))
+				assert.Equal(t, "request timed out", apiErr.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/xkarasb/blog/internal/core/jobs"
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+)
+
+func GetAdminRouter(service *service.AdminService, orphans *service.OrphanCleaner, tokens *service.TokenPurger, jobs *jobs.Runner, settings map[string]string) *Router {
+	controller := handlers.NewAdminController(service, orphans, tokens, jobs, settings)
+	router := NewRouter()
+
+	router.HandleFunc("GET /admin/users", controller.GetUsersHandler)
+	router.HandleFunc("GET /admin/audit", controller.GetAuditLogHandler)
+	router.HandleFunc("GET /admin/config", controller.GetConfigHandler)
+	router.HandleFunc("GET /admin/cache", controller.GetCacheStatsHandler)
+	router.HandleFunc("GET /admin/jobs", controller.GetJobsHandler)
+	router.HandleFunc("PATCH /admin/users/{userId}/role", controller.ChangeRoleHandler)
+	router.HandleFunc("PATCH /admin/posts/{postId}/status", controller.SetPostStatusHandler)
+	router.HandleFunc("GET /admin/reports", controller.GetReportsHandler)
+	router.HandleFunc("POST /admin/reports/{postId}/resolve", controller.ResolveReportsHandler)
+	router.HandleFunc("POST /admin/maintenance/cleanup-images", controller.CleanupImagesHandler)
+	router.HandleFunc("POST /admin/maintenance/purge-tokens", controller.PurgeTokensHandler)
+
+	return router
+}
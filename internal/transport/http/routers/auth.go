@@ -2,18 +2,39 @@ package routers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/xkarasb/blog/internal/core/service"
 	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
-func GetAuthRouter(service *service.AuthService) *http.ServeMux {
-	controller := handlers.NewAuthController(service)
-	router := http.NewServeMux()
+func GetAuthRouter(service *service.AuthService, authMiddlewareManager *middlewares.AuthMiddlewareManager, cookieMode bool, refreshTTL time.Duration) *Router {
+	controller := handlers.NewAuthController(service, handlers.AuthCookie{Enabled: cookieMode, MaxAge: refreshTTL})
+	router := NewRouter()
 
 	router.HandleFunc("POST /auth/register", controller.RegisterHandler)
 	router.HandleFunc("POST /auth/login", controller.LoginHandler)
 	router.HandleFunc("POST /auth/refresh-token", controller.RefreshHandler)
+	router.HandleFunc("POST /auth/logout", controller.LogoutHandler)
+	router.HandleFunc("POST /auth/forgot-password", controller.ForgotPasswordHandler)
+	router.HandleFunc("POST /auth/reset-password", controller.ResetPasswordHandler)
+	router.HandleFunc("GET /auth/verify-email", controller.VerifyEmailHandler)
+	router.Handle("GET /auth/me", authMiddlewareManager.AuthMiddleware(authMiddlewareManager.RequireScope(types.ScopeRead)(http.HandlerFunc(controller.MeHandler))))
+	// what a login may do but an API key may not
+	sessionOnly := func(handler http.Handler) http.Handler {
+		return authMiddlewareManager.AuthMiddleware(authMiddlewareManager.RequireSession(handler))
+	}
+	authorOnly := authMiddlewareManager.RequireRole(types.Author)
+	router.Handle("PATCH /auth/role", sessionOnly(http.HandlerFunc(controller.ChangeRoleHandler)))
+	router.Handle("POST /auth/verify-email/resend", sessionOnly(http.HandlerFunc(controller.ResendVerificationHandler)))
+	router.Handle("GET /auth/sessions", sessionOnly(http.HandlerFunc(controller.SessionsHandler)))
+	router.Handle("DELETE /auth/sessions", sessionOnly(http.HandlerFunc(controller.RevokeOtherSessionsHandler)))
+	router.Handle("DELETE /auth/sessions/{sessionId}", sessionOnly(http.HandlerFunc(controller.RevokeSessionHandler)))
+	router.Handle("POST /auth/api-keys", sessionOnly(authorOnly(http.HandlerFunc(controller.CreateApiKeyHandler))))
+	router.Handle("GET /auth/api-keys", sessionOnly(authorOnly(http.HandlerFunc(controller.ApiKeysHandler))))
+	router.Handle("DELETE /auth/api-keys/{keyId}", sessionOnly(authorOnly(http.HandlerFunc(controller.RevokeApiKeyHandler))))
 
 	return router
 }
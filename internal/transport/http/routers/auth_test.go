@@ -0,0 +1,47 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/internal/core/dto"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/errors"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// keyAuthService knows one API key, with scopes
+type keyAuthService struct {
+	scopes []types.Scope
+}
+
+func (k keyAuthService) AuthorizeSession(ctx context.Context, token string) (*dto.UserDB, uuid.UUID, error) {
+	return nil, uuid.Nil, errors.ErrorHttpInvalidToken
+}
+
+func (k keyAuthService) AuthorizeApiKey(ctx context.Context, key string) (*dto.UserDB, []types.Scope, error) {
+	return &dto.UserDB{UserId: uuid.New(), Role: types.Author}, k.scopes, nil
+}
+
+func TestGetAuthRouter_MeNeedsReadScope(t *testing.T) {
+	m := middlewares.NewAuthMiddlewareManager(keyAuthService{scopes: []types.Scope{types.ScopeWrite}})
+	// the key is refused before the service is asked
+	router := GetAuthRouter(nil, m, false, time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+	req.Header.Set("Authorization", "ApiKey blog_write")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	var body dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, errors.ErrorHttpInsufficientScope.Error(), body.Error)
+}
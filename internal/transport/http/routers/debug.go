@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"expvar"
+	"net/http/pprof"
+
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+)
+
+// GetDebugRouter serves the profiles of net/http/pprof under /debug/pprof/, e.g. heap, goroutine,
+// profile and trace, and the expvar variables with gauges at /debug/vars
+func GetDebugRouter(gauges map[string]expvar.Var) *Router {
+	controller := handlers.NewDebugController(gauges)
+	router := NewRouter()
+
+	router.HandleFunc("GET /debug/pprof/", pprof.Index)
+	router.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	router.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	router.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	router.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	router.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	router.HandleFunc("GET /debug/vars", controller.VarsHandler)
+
+	return router
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+)
+
+func GetHealthRouter(service *service.HealthService) *Router {
+	controller := handlers.NewHealthController(service)
+	router := NewRouter()
+
+	router.HandleFunc("GET /healthz", controller.LivenessHandler)
+	router.HandleFunc("GET /readyz", controller.ReadinessHandler)
+
+	return router
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"github.com/xkarasb/blog/internal/core/repository"
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+)
+
+func GetMediaRouter(storage *repository.FSRepository) *Router {
+	controller := handlers.NewMediaController(storage)
+	router := NewRouter()
+
+	// avatars are one directory down, a presigned link escapes the slash and a public one does not
+	router.HandleFunc("GET "+repository.MediaPrefix+"{objectName...}", controller.FileHandler)
+
+	return router
+}
@@ -2,19 +2,82 @@ package routers
 
 import (
 	"net/http"
+	"time"
 
-	"github.com/xkarasb/blog/internal/core/service"
 	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
-func GetPosterRouter(service *service.PosterService) *http.ServeMux {
-	controller := handlers.NewPosterController(service)
-	router := http.NewServeMux()
+// addPosterRoutes registers the routes changing a post, they are for authors only. Readers list images too,
+// the service hides drafts of others, and every user sees their storage quota after logging in. Uploads are
+// added by addUploadRoutes.
+// The writes of authors take an Idempotency-Key.
+func addPosterRoutes(router *Router, controller *handlers.PosterController, authMiddlewareManager *middlewares.AuthMiddlewareManager, idempotency middlewares.IdempotencyStore, bodyLimit int64) {
+	idempotent := middlewares.Idempotent(idempotency)
+	read, _ := scoped(authMiddlewareManager)
+	// the scope goes before the key is claimed, a refused request has nothing to replay
+	writes := authMiddlewareManager.RequireScope(types.ScopeWrite)
+	author := func(handler http.HandlerFunc) http.Handler {
+		return authMiddlewareManager.RequireRole(types.Author)(writes(middlewares.BodyLimit(bodyLimit)(idempotent(handler))))
+	}
+	authorReads := func(handler http.HandlerFunc) http.Handler {
+		return authMiddlewareManager.RequireRole(types.Author)(read(handler))
+	}
 
-	router.HandleFunc("POST /post/{postId}/images", controller.AddImageHandler)
-	router.HandleFunc("PUT /post/{postId}", controller.EditPostHandler)
-	router.HandleFunc("DELETE /post/{postId}/images/{imageId}", controller.DeleteImageHandler)
-	router.HandleFunc("PATCH /post/{postId}/status", controller.PublishHandler)
+	handlePost(router, "GET", "/images", read(controller.ImagesHandler))
+	router.Handle("GET /users/me/quota", authMiddlewareManager.RequireSession(http.HandlerFunc(controller.QuotaHandler)))
+	handlePost(router, "PUT", "", author(controller.EditPostHandler))
+	handlePost(router, "PATCH", "", author(controller.PatchPostHandler))
+	handlePost(router, "PATCH", "/images/{imageId}", author(controller.UpdateImageHandler))
+	handlePost(router, "PUT", "/images/order", author(controller.ReorderImagesHandler))
+	handlePost(router, "DELETE", "/images/{imageId}", author(controller.DeleteImageHandler))
+	handlePost(router, "PATCH", "/status", author(controller.PublishHandler))
+	handlePost(router, "DELETE", "", author(controller.TrashPostHandler))
+	handlePost(router, "POST", "/restore", author(controller.RestorePostHandler))
+	handlePost(router, "GET", "/stats", authorReads(controller.StatsHandler))
+	handlePost(router, "POST", "/preview-token", author(controller.CreatePreviewTokenHandler))
+	handlePost(router, "DELETE", "/preview-token", author(controller.RevokePreviewTokensHandler))
+	handlePost(router, "GET", "/revisions", authorReads(controller.RevisionsHandler))
+	handlePost(router, "POST", "/revisions/{revisionId}/restore", author(controller.RestoreRevisionHandler))
+}
+
+// addUploadRoutes registers the image uploads, their body and time limits are their own
+func addUploadRoutes(router *Router, controller *handlers.PosterController, authMiddlewareManager *middlewares.AuthMiddlewareManager, uploadLimit int64, uploadTimeout time.Duration) {
+	// not author, its JSON limit would cut images off
+	multipart := middlewares.ContentType(middlewares.MediaTypeMultipart)
+	_, write := scoped(authMiddlewareManager)
+	upload := func(handler http.HandlerFunc) http.Handler {
+		limited := authMiddlewareManager.RequireRole(types.Author)(multipart(middlewares.BodyLimit(uploadLimit)(write(handler))))
+		if uploadTimeout > 0 {
+			limited = middlewares.Deadline(uploadTimeout)(middlewares.Timeout(uploadTimeout)(limited))
+		}
+		return limited
+	}
+
+	handlePost(router, "POST", "/images", upload(controller.AddImageHandler))
+	handlePost(router, "PUT", "/images/{imageId}", upload(controller.ReplaceImageHandler))
+}
+
+// addExportRoutes registers the export of the posts of an author, it streams for up to exportTimeout
+// instead of the request timeout
+func addExportRoutes(router *Router, controller *handlers.PosterController, authMiddlewareManager *middlewares.AuthMiddlewareManager, exportTimeout time.Duration) {
+	read, _ := scoped(authMiddlewareManager)
+	export := authMiddlewareManager.RequireRole(types.Author)(read(controller.ExportHandler))
+	if exportTimeout > 0 {
+		export = middlewares.Deadline(exportTimeout)(middlewares.Timeout(exportTimeout)(export))
+	}
+	router.Handle("GET /posts/export", export)
+}
 
-	return router
+// addImportRoutes registers the import of an export, its body limit is importLimit and it gets uploadTimeout
+// like the image uploads
+func addImportRoutes(router *Router, controller *handlers.PosterController, authMiddlewareManager *middlewares.AuthMiddlewareManager, importLimit int64, uploadTimeout time.Duration) {
+	multipart := middlewares.ContentType(middlewares.MediaTypeMultipart)
+	_, write := scoped(authMiddlewareManager)
+	imports := authMiddlewareManager.RequireRole(types.Author)(multipart(middlewares.BodyLimit(importLimit)(write(controller.ImportHandler))))
+	if uploadTimeout > 0 {
+		imports = middlewares.Deadline(uploadTimeout)(middlewares.Timeout(uploadTimeout)(imports))
+	}
+	router.Handle("POST /posts/import", imports)
 }
@@ -0,0 +1,68 @@
+package routers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// GetPostsRouter serves posts, tags, authors, subscriptions, notifications, profiles and accounts to authenticated users,
+// every route checks its own role.
+// Routes of a single post live under /posts/{postId}, the /post/{postId} paths are deprecated aliases.
+// Bodies other than JSON, or multipart for uploads, get 415. JSON bodies are cut off after bodyLimit bytes
+// uploads after uploadLimit and imports after importLimit. Requests are cancelled after requestTimeout,
+// uploads and imports get uploadTimeout instead to be read and answered, zero leaves them unbounded. The stream of published posts runs
+// until the client leaves, with a comment every heartbeat. Exports get exportTimeout.
+func GetPostsRouter(reader *service.ReaderService, poster *service.PosterService, subscriptions *service.SubscriptionService, profiles *service.ProfileService, accounts *service.AccountService, published *service.PublishHub, authMiddlewareManager *middlewares.AuthMiddlewareManager, idempotency middlewares.IdempotencyStore, bodyLimit, uploadLimit, importLimit int64, requestTimeout, uploadTimeout, exportTimeout, heartbeat time.Duration) *Router {
+	routes := NewRouter()
+	posterController := handlers.NewPosterController(poster)
+	addReaderRoutes(routes, handlers.NewReaderController(reader), authMiddlewareManager, bodyLimit)
+	addPosterRoutes(routes, posterController, authMiddlewareManager, idempotency, bodyLimit)
+	addSubscriptionRoutes(routes, handlers.NewSubscriptionController(subscriptions), authMiddlewareManager, bodyLimit)
+	profileController := handlers.NewProfileController(profiles)
+	addProfileRoutes(routes, profileController, authMiddlewareManager, bodyLimit)
+	accountController := handlers.NewAccountController(accounts)
+	addAccountRoutes(routes, accountController, authMiddlewareManager, bodyLimit)
+
+	router := NewRouter()
+	api := middlewares.Timeout(requestTimeout)(middlewares.ContentType(middlewares.MediaTypeJSON)(routes))
+	router.Mount("/", api, routes)
+	// uploads stay outside the request timeout, a deadline set there could not be extended for them
+	addUploadRoutes(router, posterController, authMiddlewareManager, uploadLimit, uploadTimeout)
+	addAvatarRoutes(router, profileController, authMiddlewareManager, uploadLimit, uploadTimeout)
+	addImportRoutes(router, posterController, authMiddlewareManager, importLimit, uploadTimeout)
+	// the order of images is JSON, its path would be taken for an image to replace otherwise
+	router.Handle("PUT /posts/{postId}/images/order", api)
+	router.Handle("PUT /post/{postId}/images/order", api)
+	// exports stream for longer than the request timeout as well
+	addExportRoutes(router, posterController, authMiddlewareManager, exportTimeout)
+	addAccountExportRoutes(router, accountController, authMiddlewareManager, exportTimeout)
+	// the stream as well, it is open for as long as the client wants
+	read, _ := scoped(authMiddlewareManager)
+	router.Handle("GET /posts/stream", read(handlers.NewStreamController(published, heartbeat).StreamHandler))
+
+	return router
+}
+
+// scoped returns the guards of the routes API keys may use, read for those reading and write for
+// those changing anything. Routes keys may not use at all go behind RequireSession instead.
+func scoped(authMiddlewareManager *middlewares.AuthMiddlewareManager) (read, write func(http.HandlerFunc) http.Handler) {
+	guard := func(scope types.Scope) func(http.HandlerFunc) http.Handler {
+		require := authMiddlewareManager.RequireScope(scope)
+		return func(handler http.HandlerFunc) http.Handler {
+			return require(handler)
+		}
+	}
+	return guard(types.ScopeRead), guard(types.ScopeWrite)
+}
+
+// handlePost registers a route of a single post, path follows /posts/{postId}
+func handlePost(router *Router, method, path string, handler http.Handler) {
+	router.Handle(method+" /posts/{postId}"+path, handler)
+	// older clients get a release to move over, the aliases go away after it
+	router.Handle(method+" /post/{postId}"+path, middlewares.Deprecated(handler))
+}
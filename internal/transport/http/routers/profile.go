@@ -0,0 +1,39 @@
+package routers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+)
+
+// addProfileRoutes registers the own profile of the user, every user has one. The account of the user is
+// theirs alone, API keys get nowhere under /users/me.
+func addProfileRoutes(router *Router, controller *handlers.ProfileController, authMiddlewareManager *middlewares.AuthMiddlewareManager, bodyLimit int64) {
+	router.Handle("GET /users/me", authMiddlewareManager.RequireSession(http.HandlerFunc(controller.GetProfileHandler)))
+	router.Handle("PATCH /users/me", authMiddlewareManager.RequireSession(middlewares.BodyLimit(bodyLimit)(http.HandlerFunc(controller.UpdateProfileHandler))))
+}
+
+// addAvatarRoutes registers the avatar upload, it gets the limit and timeout of image uploads
+func addAvatarRoutes(router *Router, controller *handlers.ProfileController, authMiddlewareManager *middlewares.AuthMiddlewareManager, uploadLimit int64, uploadTimeout time.Duration) {
+	var upload http.Handler = middlewares.ContentType(middlewares.MediaTypeMultipart)(middlewares.BodyLimit(uploadLimit)(http.HandlerFunc(controller.SetAvatarHandler)))
+	if uploadTimeout > 0 {
+		upload = middlewares.Deadline(uploadTimeout)(middlewares.Timeout(uploadTimeout)(upload))
+	}
+	router.Handle("POST /users/me/avatar", authMiddlewareManager.RequireSession(upload))
+}
+
+// addAccountRoutes registers the deletion of the own account, every user may delete theirs
+func addAccountRoutes(router *Router, controller *handlers.AccountController, authMiddlewareManager *middlewares.AuthMiddlewareManager, bodyLimit int64) {
+	router.Handle("DELETE /users/me", authMiddlewareManager.RequireSession(middlewares.BodyLimit(bodyLimit)(http.HandlerFunc(controller.DeleteAccountHandler))))
+}
+
+// addAccountExportRoutes registers the export of the own data, it gets the timeout of exports
+func addAccountExportRoutes(router *Router, controller *handlers.AccountController, authMiddlewareManager *middlewares.AuthMiddlewareManager, exportTimeout time.Duration) {
+	var export http.Handler = http.HandlerFunc(controller.ExportHandler)
+	if exportTimeout > 0 {
+		export = middlewares.Deadline(exportTimeout)(middlewares.Timeout(exportTimeout)(export))
+	}
+	router.Handle("GET /users/me/export", authMiddlewareManager.RequireSession(export))
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"time"
+
+	"github.com/xkarasb/blog/internal/core/service"
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+)
+
+func GetPublicRouter(service *service.ReaderService, maxAge time.Duration) *Router {
+	controller := handlers.NewPublicController(service, maxAge)
+	router := NewRouter()
+
+	router.HandleFunc("GET /public/posts", controller.PostsHandler)
+	router.HandleFunc("GET /public/posts/{postId}", controller.PostHandler)
+	router.HandleFunc("GET /public/preview/{token}", controller.PreviewHandler)
+	router.HandleFunc("GET /public/authors/{authorId}", controller.AuthorHandler)
+
+	return router
+}
@@ -1,19 +1,24 @@
 package routers
 
 import (
-	"net/http"
-
-	"github.com/xkarasb/blog/internal/core/service"
 	"github.com/xkarasb/blog/internal/transport/http/handlers"
 	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+	"github.com/xkarasb/blog/pkg/types"
 )
 
-func GetReaderRouter(service *service.ReaderService, authMiddlewareManager *middlewares.AuthMiddlewareManager) *http.ServeMux {
-	controller := handlers.NewReaderController(service)
-	router := http.NewServeMux()
-
-	router.HandleFunc("GET /posts", controller.ViewSelectionHandler)
-	router.Handle("POST /posts", authMiddlewareManager.AuthorOnlyMiddleware(http.HandlerFunc(controller.CreatePostHandler)))
-
-	return router
+// addReaderRoutes registers what every user may do, only creating a post needs an author
+func addReaderRoutes(router *Router, controller *handlers.ReaderController, authMiddlewareManager *middlewares.AuthMiddlewareManager, bodyLimit int64) {
+	read, write := scoped(authMiddlewareManager)
+	router.Handle("GET /posts", read(controller.ViewSelectionHandler))
+	router.Handle("GET /posts/search", read(controller.SearchHandler))
+	router.Handle("GET /posts/trending", read(controller.TrendingHandler))
+	router.Handle("GET /posts/{postId}", read(controller.GetPostHandler))
+	router.Handle("GET /tags", read(controller.GetTagsHandler))
+	router.Handle("GET /authors", read(controller.GetAuthorsHandler))
+	router.Handle("GET /authors/{authorId}", read(controller.GetAuthorHandler))
+	router.Handle("GET /authors/{authorId}/posts", read(controller.GetAuthorPostsHandler))
+	handlePost(router, "POST", "/like", write(controller.LikeHandler))
+	handlePost(router, "DELETE", "/like", write(controller.UnlikeHandler))
+	handlePost(router, "POST", "/report", middlewares.BodyLimit(bodyLimit)(write(controller.ReportHandler)))
+	router.Handle("POST /posts", authMiddlewareManager.RequireRole(types.Author)(middlewares.BodyLimit(bodyLimit)(write(controller.CreatePostHandler))))
 }
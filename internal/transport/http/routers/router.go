@@ -0,0 +1,57 @@
+package routers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router is a ServeMux that keeps the methods its patterns were registered for, so Allow can tell
+// which of them serve a path. Routers mounted under it are asked as well.
+type Router struct {
+	*http.ServeMux
+	methods []string
+	mounted []*Router
+}
+
+func NewRouter() *Router {
+	return &Router{ServeMux: http.NewServeMux()}
+}
+
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	if method, _, ok := strings.Cut(pattern, " "); ok && !slices.Contains(rt.methods, method) {
+		rt.methods = append(rt.methods, method)
+	}
+	rt.ServeMux.Handle(pattern, handler)
+}
+
+func (rt *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// Mount serves pattern with handler, which passes the requests on to sub after its middlewares
+func (rt *Router) Mount(pattern string, handler http.Handler, sub *Router) {
+	rt.Handle(pattern, handler)
+	rt.mounted = append(rt.mounted, sub)
+}
+
+// Allow lists the methods the path of r is served for, HEAD along with GET. Patterns without a method
+// only mount other handlers and are left out, nil means no route knows the path.
+func (rt *Router) Allow(r *http.Request) []string {
+	var allow []string
+	for _, method := range rt.methods {
+		probe := *r
+		probe.Method = method
+		if _, pattern := rt.Handler(&probe); strings.HasPrefix(pattern, method+" ") {
+			allow = append(allow, method)
+		}
+	}
+	if slices.Contains(allow, http.MethodGet) {
+		allow = append(allow, http.MethodHead)
+	}
+	for _, sub := range rt.mounted {
+		allow = append(allow, sub.Allow(r)...)
+	}
+	slices.Sort(allow)
+	return slices.Compact(allow)
+}
@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Allow(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	inner := NewRouter()
+	inner.HandleFunc("GET /posts/{postId}", ok)
+	inner.HandleFunc("DELETE /posts/{postId}", ok)
+	router := NewRouter()
+	router.HandleFunc("GET /posts/stream", ok)
+	router.HandleFunc("PUT /posts/{postId}/images/{imageId}", ok)
+	router.Mount("/", inner, inner)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/posts/1", want: []string{"DELETE", "GET", "HEAD"}},
+		{path: "/posts/stream", want: []string{"DELETE", "GET", "HEAD"}},
+		{path: "/posts/1/images/2", want: []string{"PUT"}},
+		{path: "/posts", want: nil},
+		{path: "/nope", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, router.Allow(httptest.NewRequest(http.MethodOptions, tt.path, nil)))
+		})
+	}
+}
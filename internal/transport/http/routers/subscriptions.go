@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/internal/transport/http/middlewares"
+)
+
+// addSubscriptionRoutes registers following authors and the notifications about their posts, every user may
+func addSubscriptionRoutes(router *Router, controller *handlers.SubscriptionController, authMiddlewareManager *middlewares.AuthMiddlewareManager, bodyLimit int64) {
+	read, write := scoped(authMiddlewareManager)
+	router.Handle("POST /authors/{authorId}/subscribe", write(controller.SubscribeHandler))
+	router.Handle("DELETE /authors/{authorId}/subscribe", write(controller.UnsubscribeHandler))
+	router.Handle("GET /subscriptions", read(controller.GetSubscriptionsHandler))
+	router.Handle("GET /notifications", read(controller.GetNotificationsHandler))
+	router.Handle("POST /notifications/read", middlewares.BodyLimit(bodyLimit)(write(controller.MarkNotificationsReadHandler)))
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"github.com/xkarasb/blog/internal/transport/http/handlers"
+	"github.com/xkarasb/blog/pkg/version"
+)
+
+func GetVersionRouter(info version.Info) *Router {
+	controller := handlers.NewVersionController(info)
+	router := NewRouter()
+
+	router.HandleFunc("GET /version", controller.VersionHandler)
+
+	return router
+}
@@ -0,0 +1,3 @@
+UPDATE posts SET status = 'draft' WHERE status = 'archived';
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('draft', 'published'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_scheduled;
+UPDATE posts SET status = 'draft' WHERE status = 'scheduled';
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('draft', 'published', 'archived'));
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
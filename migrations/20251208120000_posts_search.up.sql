@@ -0,0 +1,2 @@
+-- 'simple' keeps words as they are, posts are written in more than one language
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (to_tsvector('simple', title || ' ' || content));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS post_views (
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    viewed_on DATE NOT NULL,
+    PRIMARY KEY (post_id, user_id, viewed_on),
+    CONSTRAINT fk_post_views_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_post_views_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS post_likes (
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id),
+    CONSTRAINT fk_post_likes_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_post_likes_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_likes_user ON post_likes (user_id, created_at DESC);
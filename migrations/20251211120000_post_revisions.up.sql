@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    revision_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    edited_by UUID NOT NULL,
+    CONSTRAINT fk_post_revisions_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_post_revisions_editor
+        FOREIGN KEY (edited_by)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions (post_id, edited_at DESC);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    event_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    seq BIGSERIAL NOT NULL UNIQUE,
+    post_id UUID NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (seq) WHERE sent_at IS NULL;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS subscriptions (
+    reader_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (reader_id, author_id),
+    CONSTRAINT subscriptions_not_self CHECK (reader_id <> author_id),
+    CONSTRAINT fk_subscriptions_reader
+        FOREIGN KEY (reader_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_subscriptions_author
+        FOREIGN KEY (author_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+);
+
+-- the fan-out looks the subscribers of an author up
+CREATE INDEX IF NOT EXISTS idx_subscriptions_author ON subscriptions (author_id);
+
+-- one notification per user and post, a post published again does not notify twice
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, post_id),
+    CONSTRAINT fk_notifications_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
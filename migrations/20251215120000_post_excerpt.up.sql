@@ -0,0 +1,2 @@
+ALTER TABLE posts ADD COLUMN excerpt TEXT;
+ALTER TABLE posts ADD CONSTRAINT posts_excerpt_check CHECK (char_length(excerpt) <= 300);
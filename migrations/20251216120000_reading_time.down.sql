@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS update_posts_modtime ON posts;
+CREATE TRIGGER update_posts_modtime
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_modified_column();
+DROP FUNCTION IF EXISTS update_posts_modified_column();
+
+ALTER TABLE posts DROP COLUMN IF EXISTS reading_time_minutes;
+ALTER TABLE posts DROP COLUMN IF EXISTS word_count;
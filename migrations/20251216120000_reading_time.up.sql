@@ -0,0 +1,20 @@
+ALTER TABLE posts ADD COLUMN word_count INTEGER;
+ALTER TABLE posts ADD COLUMN reading_time_minutes INTEGER;
+
+-- posts written before have NULL counts until they are first read, filling them in is not an edit
+CREATE OR REPLACE FUNCTION update_posts_modified_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'word_count' - 'reading_time_minutes' = to_jsonb(OLD) - 'word_count' - 'reading_time_minutes' THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_posts_modtime ON posts;
+CREATE TRIGGER update_posts_modtime
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_posts_modified_column();
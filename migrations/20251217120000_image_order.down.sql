@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_images_post_position;
+ALTER TABLE images DROP CONSTRAINT IF EXISTS images_caption_check;
+ALTER TABLE images DROP COLUMN IF EXISTS caption;
+ALTER TABLE images DROP COLUMN IF EXISTS position;
@@ -0,0 +1,13 @@
+ALTER TABLE images ADD COLUMN position INTEGER;
+ALTER TABLE images ADD COLUMN caption TEXT;
+ALTER TABLE images ADD CONSTRAINT images_caption_check CHECK (char_length(caption) <= 500);
+
+-- images added so far keep the order they were added in
+UPDATE images i SET position = o.position
+FROM (SELECT image_id, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at, image_id) AS position FROM images) o
+WHERE i.image_id = o.image_id;
+
+ALTER TABLE images ALTER COLUMN position SET NOT NULL;
+ALTER TABLE images ALTER COLUMN position SET DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_images_post_position ON images (post_id, position);
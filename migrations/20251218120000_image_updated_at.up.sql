@@ -0,0 +1,7 @@
+ALTER TABLE images ADD COLUMN updated_at TIMESTAMP;
+
+-- images added so far were never replaced
+UPDATE images SET updated_at = created_at;
+
+ALTER TABLE images ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE images ALTER COLUMN updated_at SET DEFAULT NOW();
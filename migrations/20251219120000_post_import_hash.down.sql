@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_author_import_hash;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS import_hash;
@@ -0,0 +1,4 @@
+-- hash of the title and content an imported post came with, importing it again is skipped
+ALTER TABLE posts ADD COLUMN import_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_posts_author_import_hash ON posts (author_id, import_hash) WHERE import_hash IS NOT NULL;
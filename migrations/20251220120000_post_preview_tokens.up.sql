@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS post_preview_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    post_id UUID NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_post_preview_tokens_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_preview_tokens_post ON post_preview_tokens(post_id);
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS post_reports (
+    report_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason VARCHAR(16) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID,
+    resolution VARCHAR(16),
+    CONSTRAINT post_reports_once UNIQUE (post_id, user_id),
+    CONSTRAINT post_reports_reason_check CHECK (reason IN ('spam', 'abuse', 'copyright', 'other')),
+    CONSTRAINT post_reports_resolution_check CHECK (resolution IN ('dismiss', 'unpublish')),
+    CONSTRAINT fk_post_reports_post
+        FOREIGN KEY (post_id)
+        REFERENCES posts(post_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_post_reports_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_post_reports_resolved_by
+        FOREIGN KEY (resolved_by)
+        REFERENCES users(user_id)
+        ON DELETE SET NULL
+);
+
+-- the queue reads only the reports nobody handled yet
+CREATE INDEX IF NOT EXISTS idx_post_reports_open ON post_reports (post_id) WHERE resolved_at IS NULL;
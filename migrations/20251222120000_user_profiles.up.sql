@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(50);
+-- what the storage returned for the object under avatars/, like image_url of images
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT;
@@ -0,0 +1,13 @@
+CREATE OR REPLACE FUNCTION update_posts_modified_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'word_count' - 'reading_time_minutes' = to_jsonb(OLD) - 'word_count' - 'reading_time_minutes' THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- the posts kept from deleted accounts go with it
+DELETE FROM users WHERE user_id = '00000000-0000-0000-0000-00000000dead';
@@ -0,0 +1,17 @@
+-- posts of deleted accounts are kept under this user when ACCOUNT_DELETION_POLICY is anonymize,
+-- without a password hash nobody can log in as it
+INSERT INTO users (user_id, email, password_hash, role, refresh_token, refresh_token_expiry_time, display_name)
+VALUES ('00000000-0000-0000-0000-00000000dead', 'deleted-user@invalid', '', 'author', '', NOW(), 'Deleted user')
+ON CONFLICT DO NOTHING;
+
+-- handing a post over to the deleted user is not an edit either
+CREATE OR REPLACE FUNCTION update_posts_modified_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'word_count' - 'reading_time_minutes' - 'author_id' = to_jsonb(OLD) - 'word_count' - 'reading_time_minutes' - 'author_id' THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_preview_tokens_expires;
+DROP INDEX IF EXISTS idx_password_resets_expires;
+DROP INDEX IF EXISTS idx_users_refresh_token_expiry;
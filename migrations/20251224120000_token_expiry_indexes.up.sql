@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_users_refresh_token_expiry ON users(refresh_token_expiry_time) WHERE refresh_token <> '';
+CREATE INDEX IF NOT EXISTS idx_password_resets_expires ON password_resets(expires_at);
+CREATE INDEX IF NOT EXISTS idx_post_preview_tokens_expires ON post_preview_tokens(expires_at);
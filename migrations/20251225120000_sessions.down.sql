@@ -0,0 +1,6 @@
+-- refresh tokens cannot be recovered from their hashes, everybody logs in again
+ALTER TABLE users ADD COLUMN IF NOT EXISTS refresh_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS refresh_token_expiry_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+CREATE INDEX IF NOT EXISTS idx_users_refresh_token_expiry ON users(refresh_token_expiry_time) WHERE refresh_token <> '';
+
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    session_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    -- only the SHA-256 of the refresh token is kept, a leaked table does not log anyone in
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_sessions_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions (user_id, last_used_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires ON sessions (expires_at);
+
+-- the single refresh token of every user becomes their first session, nobody is logged out
+INSERT INTO sessions (user_id, token_hash, expires_at)
+SELECT user_id, encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex'), refresh_token_expiry_time
+FROM users
+WHERE refresh_token IS NOT NULL AND refresh_token <> '' AND refresh_token_expiry_time IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS idx_users_refresh_token_expiry;
+ALTER TABLE users DROP COLUMN IF EXISTS refresh_token;
+ALTER TABLE users DROP COLUMN IF EXISTS refresh_token_expiry_time;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- only the SHA-256 of the key is kept, it is shown once when the key is made
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{read}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_api_keys_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+    CONSTRAINT api_keys_scopes_check CHECK (scopes <@ ARRAY['read', 'write']::TEXT[])
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id, created_at DESC);
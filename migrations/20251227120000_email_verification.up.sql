@@ -0,0 +1,5 @@
+-- authors publish once they followed the link mailed at registration
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- accounts from before verification keep publishing
+UPDATE users SET email_verified = TRUE;
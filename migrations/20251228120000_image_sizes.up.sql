@@ -0,0 +1,2 @@
+-- images uploaded so far were not measured, they count as empty against the storage quota
+ALTER TABLE images ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0;
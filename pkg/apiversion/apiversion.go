@@ -0,0 +1,47 @@
+// Package apiversion carries the api version a request was routed to, so handlers can change
+// what they answer in a newer version without breaking clients of an older one.
+package apiversion
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type Version string
+
+const (
+	// Unversioned is a request to the /api alias, it is answered like Current for now
+	Unversioned Version = ""
+	V1          Version = "v1"
+
+	// Current is what /api serves
+	Current = V1
+)
+
+// pattern is what a version segment looks like, also for versions this server does not know
+var pattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// Prefix is the path the api of the version is mounted under
+func (v Version) Prefix() string {
+	if v == Unversioned {
+		return "/api"
+	}
+	return "/api/" + string(v)
+}
+
+// LooksLike reports whether segment has the form of a version, e.g. v2
+func LooksLike(segment string) bool {
+	return pattern.MatchString(segment)
+}
+
+func WithVersion(ctx context.Context, version Version) context.Context {
+	return context.WithValue(ctx, types.CtxAPIVersion, version)
+}
+
+// FromContext returns the version stored by WithVersion, Unversioned outside a request
+func FromContext(ctx context.Context) Version {
+	version, _ := ctx.Value(types.CtxAPIVersion).(Version)
+	return version
+}
@@ -0,0 +1,157 @@
+// Package cache keeps serialized values for a short while in Redis, or nowhere when no Redis is configured.
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CacheConfig struct {
+	// RedisAddr is host:port of the Redis server, empty for no cache
+	RedisAddr     string        `env:"CACHE_REDIS_ADDR" env-default:""`
+	RedisPassword string        `env:"CACHE_REDIS_PASSWORD" env-default:"" secret:"true"`
+	RedisDB       int           `env:"CACHE_REDIS_DB" env-default:"0"`
+	Timeout       time.Duration `env:"CACHE_TIMEOUT" env-default:"200ms"`
+}
+
+// Cache is safe for concurrent use. Callers treat errors as misses, a cache is never the only copy.
+type Cache interface {
+	// Get returns ok false for a key that is missing or expired
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr adds one to the counter at key and returns it, a missing counter starts at zero. Get reads
+	// it in decimal. Counters do not expire.
+	Incr(ctx context.Context, key string) (int64, error)
+	Close() error
+}
+
+// New returns a Redis cache, or a Nop one when CACHE_REDIS_ADDR is empty
+func New(cfg CacheConfig) Cache {
+	if cfg.RedisAddr == "" {
+		return Nop{}
+	}
+	return NewRedis(cfg)
+}
+
+// Nop keeps nothing, every Get misses
+type Nop struct{}
+
+func (Nop) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (Nop) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (Nop) Incr(ctx context.Context, key string) (int64, error) {
+	return 0, nil
+}
+
+func (Nop) Close() error {
+	return nil
+}
+
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects lazily, an unreachable server fails the calls after Timeout rather than the start
+func NewRedis(cfg CacheConfig) *Redis {
+	return &Redis{redis.NewClient(&redis.Options{
+		Addr:         cfg.RedisAddr,
+		Password:     cfg.RedisPassword,
+		DB:           cfg.RedisDB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	})}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *Redis) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
+}
+
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+// Memory keeps the values in the process, for tests and single instance setups
+type Memory struct {
+	// clock defaults to time.Now
+	clock   func() time.Time
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value []byte
+	// expires is zero for counters
+	expires time.Time
+}
+
+func NewMemory(clock func() time.Time) *Memory {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &Memory{clock: clock, entries: map[string]memoryEntry{}}
+}
+
+func (c *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !entry.expires.IsZero() && !c.clock().Before(entry.expires) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryEntry{value, c.clock().Add(ttl)}
+	return nil
+}
+
+func (c *Memory) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var counter int64
+	if entry, ok := c.entries[key]; ok {
+		var err error
+		if counter, err = strconv.ParseInt(string(entry.value), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	counter++
+	c.entries[key] = memoryEntry{value: []byte(strconv.FormatInt(counter, 10))}
+	return counter, nil
+}
+
+func (c *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package clientctx carries who sent a request, so layers below the handlers
+// can record it without taking the request itself.
+package clientctx
+
+import (
+	"context"
+
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, types.CtxClient, client)
+}
+
+// FromContext returns the client stored by WithClient, empty outside a request
+func FromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(types.CtxClient).(Client)
+	return client
+}
@@ -3,31 +3,93 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
 )
 
 type PostgresConfig struct {
 	Username string `env:"POSTGRES_USER" env-default:"postgres"`
-	Password string `env:"POSTGRES_PASSWORD" env-default:"123"`
+	Password string `env:"POSTGRES_PASSWORD" env-default:"123" secret:"true"`
 	Host     string `env:"POSTGRES_HOST" env-default:"localhost"`
 	Port     string `env:"POSTGRES_PORT" env-default:"5432"`
 	DbName   string `env:"POSTGRES_DB" env-default:"users"`
+
+	// pool limits, zero keeps the database/sql default
+	MaxOpenConns    int           `env:"POSTGRES_MAX_OPEN_CONNS" env-default:"25"`
+	MaxIdleConns    int           `env:"POSTGRES_MAX_IDLE_CONNS" env-default:"5"`
+	ConnMaxLifetime time.Duration `env:"POSTGRES_CONN_MAX_LIFETIME" env-default:"30m"`
+
+	// QueryTimeout bounds the queries of a request, maintenance work brings its own context
+	QueryTimeout time.Duration `env:"POSTGRES_QUERY_TIMEOUT" env-default:"5s"`
+
+	// StartupTimeout is how long New waits for the database to accept connections, zero tries once
+	StartupTimeout time.Duration `env:"POSTGRES_STARTUP_TIMEOUT" env-default:"30s"`
+	// RetryInterval is the first pause between attempts, it doubles up to eight times its value
+	RetryInterval time.Duration `env:"POSTGRES_RETRY_INTERVAL" env-default:"1s"`
 }
 
 type DB struct {
 	*sqlx.DB
+	QueryTimeout time.Duration
 }
 
 func New(config PostgresConfig) (*DB, error) {
 	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
 		config.Username, config.Password, config.Host, config.Port, config.DbName)
-	db, err := sqlx.Connect("postgres", dsn)
+	db, err := sqlx.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := db.Conn(context.Background()); err != nil {
+	configurePool(db, config)
+
+	if err := waitReady(db.PingContext, config.StartupTimeout, config.RetryInterval); err != nil {
+		db.Close()
 		return nil, err
 	}
-	return &DB{db}, nil
+	return &DB{db, config.QueryTimeout}, nil
+}
+
+func configurePool(db *sqlx.DB, config PostgresConfig) {
+	if config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+}
+
+// waitReady pings until the database answers or timeout runs out, a database that is still
+// starting next to the server is the usual case in docker and kubernetes
+func waitReady(ping func(ctx context.Context) error, timeout, interval time.Duration) error {
+	if timeout <= 0 {
+		return ping(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	delay := interval
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("postgres is not ready after %s: %w", timeout, err)
+		}
+
+		slog.Info("postgres is not ready, retrying", slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.String("error", err.Error()))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postgres is not ready after %s: %w", timeout, err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, 8*interval)
+	}
 }
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurePool(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+
+	configurePool(db, PostgresConfig{})
+	assert.Zero(t, db.Stats().MaxOpenConnections, "zero keeps the unlimited default")
+
+	configurePool(db, PostgresConfig{MaxOpenConns: 7, MaxIdleConns: 3, ConnMaxLifetime: time.Minute})
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+}
+
+func TestWaitReady(t *testing.T) {
+	refused := errors.New("connection refused")
+
+	t.Run("retries until the database answers", func(t *testing.T) {
+		calls := 0
+		err := waitReady(func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return refused
+			}
+			return nil
+		}, time.Second, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		calls := 0
+		err := waitReady(func(ctx context.Context) error {
+			calls++
+			return refused
+		}, 50*time.Millisecond, 5*time.Millisecond)
+		assert.ErrorIs(t, err, refused)
+		assert.Greater(t, calls, 1)
+	})
+
+	t.Run("zero timeout tries once", func(t *testing.T) {
+		calls := 0
+		err := waitReady(func(ctx context.Context) error {
+			calls++
+			return refused
+		}, 0, time.Millisecond)
+		assert.ErrorIs(t, err, refused)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestNew_ClosedPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	listener.Close()
+
+	start := time.Now()
+	_, err = New(PostgresConfig{
+		Username:       "blog",
+		Password:       "blog",
+		Host:           "127.0.0.1",
+		Port:           port,
+		DbName:         "blog",
+		StartupTimeout: 300 * time.Millisecond,
+		RetryInterval:  20 * time.Millisecond,
+	})
+	assert.Error(t, err)
+	assert.ErrorContains(t, err, "not ready after 300ms")
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+}
@@ -5,19 +5,93 @@ import "errors"
 var (
 	ErrorRepositoryUserAlreadyExsist = errors.New("user already exsist")
 	ErrorServiceEmailInvalid         = errors.New("invalid email")
-	ErrorRepositoryEmailNotExsist    = errors.New("email not exsist")
 	ErrorRepositoryBadRole           = errors.New("bad role")
+	ErrorRepositoryBadStatus         = errors.New("bad status")
 	ErrorInvalidToken                = errors.New("invalid token")
 	ErrorKeyIdempotencyAlreadyUsed   = errors.New("key idempotency already used")
 	ErrorServiceNoAccess             = errors.New("no access to content")
 	ErrorServiceIncorrectData        = errors.New("incorrect data")
 	ErrorHttpIncorrectUser           = errors.New("incorrect user")
 	ErrorHttpNoAuth                  = errors.New("no authorization provided")
+	ErrorHttpInvalidToken            = errors.New("access token invalid or expired")
 	ErrorHttpIncorrectBody           = errors.New("incorrect body")
 	ErrorHttpIncorrectEmail          = errors.New("email or password incorrect")
 	ErrorHttpBadRefresh              = errors.New("refresh token expired or incorrect")
+	ErrorHttpMissingCsrfHeader       = errors.New("missing X-Requested-With header")
 	ErrorHttpPostNotFound            = errors.New("post not found")
 	ErrorHttpImageNotFound           = errors.New("image not found")
 	ErrorHttpAccessDenied            = errors.New("access denied")
 	ErrorHttpIncorrectStatus         = errors.New("incorrect status")
+	ErrorServiceBackupInconsistent   = errors.New("backup is inconsistent")
+	ErrorHttpInternal                = errors.New("internal server error")
+	ErrorHttpBadResetToken           = errors.New("reset token expired or incorrect")
+	ErrorHttpUserNotFound            = errors.New("user not found")
+	ErrorHttpIncorrectQuery          = errors.New("incorrect query parameters")
+	ErrorServiceRevisionNotFound     = errors.New("no such revision of the post")
+	ErrorHttpRevisionNotFound        = errors.New("revision not found")
+	ErrorServiceConflict             = errors.New("post was changed since it was read")
+	ErrorHttpAuthorNotFound          = errors.New("author not found")
+	ErrorServiceImageNotFound        = errors.New("no such image of the post")
+	ErrorServiceUnsupportedImage     = errors.New("unsupported image type")
+	ErrorHttpUnsupportedImage        = errors.New("image must be jpeg, png, gif or webp")
+	ErrorRepositoryStorageTimeout    = errors.New("storage operation canceled or timed out")
+	ErrorHttpStorageTimeout          = errors.New("storage timeout")
+	ErrorRepositoryQueryTimeout      = errors.New("database query timed out")
+	ErrorHttpQueryTimeout            = errors.New("database timeout")
+	ErrorHttpBodyTooLarge            = errors.New("request body too large")
+	ErrorHttpUnknownVersion          = errors.New("unknown api version")
+	ErrorHttpRequestTimeout          = errors.New("request timed out")
+	ErrorHttpUnsupportedMediaType    = errors.New("unsupported content type")
+	ErrorServiceSelfSubscription     = errors.New("users cannot subscribe to themselves")
+	ErrorHttpSelfSubscription        = errors.New("cannot subscribe to yourself")
+	ErrorRepositoryImageOrder        = errors.New("image order does not match the images of the post")
+	ErrorServiceImageOrder           = errors.New("order must list every image of the post once")
+	ErrorHttpImageOrder              = errors.New("image_ids must list every image of the post exactly once")
+	ErrorServiceImageLimit           = errors.New("post has the most images allowed")
+	ErrorHttpImageLimit              = errors.New("post already has the most images allowed")
+	ErrorServiceImageTooLarge        = errors.New("image larger than allowed")
+	ErrorHttpImageTooLarge           = errors.New("image larger than MAX_IMAGE_BYTES")
+	ErrorServiceImportFormat         = errors.New("not a posts export")
+	ErrorHttpImportFormat            = errors.New("file must be a posts export, JSON or zip")
+	ErrorServiceImportEntry          = errors.New("malformed post")
+	ErrorServiceImportDuplicate      = errors.New("post with the same title and content exists")
+	ErrorHttpImportDuplicate         = errors.New("a post with the same title and content is there already")
+	ErrorServiceNoReports            = errors.New("post has no open reports")
+	ErrorHttpNoReports               = errors.New("no open reports of the post")
+	ErrorServiceWrongPassword        = errors.New("password does not match")
+	ErrorHttpWrongPassword           = errors.New("password incorrect")
+	ErrorServiceInvalidCredentials   = errors.New("email or password does not match")
+	ErrorServiceSessionNotFound      = errors.New("no such session of the user")
+	ErrorHttpSessionNotFound         = errors.New("session not found")
+	ErrorServiceApiKeyNotFound       = errors.New("no such api key of the user")
+	ErrorHttpApiKeyNotFound          = errors.New("api key not found")
+	ErrorServiceApiKeyExpired        = errors.New("api key expiry has passed")
+	ErrorHttpApiKeyExpired           = errors.New("expires_at must be in the future")
+	ErrorHttpInvalidApiKey           = errors.New("api key invalid, revoked or expired")
+	ErrorHttpInsufficientScope       = errors.New("api key lacks the scope of the route")
+	ErrorHttpSessionRequired         = errors.New("api keys cannot be used here, log in instead")
+	ErrorEmailNotVerified            = errors.New("email address not verified")
+	ErrorHttpEmailNotVerified        = errors.New("verify your email address before publishing")
+	ErrorServiceEmailVerified        = errors.New("email address verified already")
+	ErrorHttpEmailVerified           = errors.New("email already verified")
+	ErrorServiceResendTooSoon        = errors.New("verification mail sent recently")
+	ErrorHttpResendTooSoon           = errors.New("verification mail sent recently, try again later")
+	ErrorHttpBadVerifyToken          = errors.New("verification token expired or incorrect")
+	ErrorQuotaExceeded               = errors.New("images would exceed the storage quota")
+	ErrorHttpQuotaExceeded           = errors.New("upload exceeds your STORAGE_QUOTA_BYTES")
+	ErrorHttpIdempotencyKeyTooLong   = errors.New("Idempotency-Key must be at most 255 characters")
+	ErrorHttpIdempotencyKeyReused    = errors.New("Idempotency-Key was already used for another request")
+	ErrorHttpIdempotencyInProgress   = errors.New("a request with this Idempotency-Key is still running")
+	ErrorHttpMethodNotAllowed        = errors.New("method not allowed")
+	ErrorHttpInvalidFields           = errors.New("some fields are invalid")
 )
+
+// Is reports whether err wraps target, so callers importing this package need not import the standard one too
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in the chain of err that matches target, see errors.As
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	SinkLog  = "log"
+	SinkNATS = "nats"
+)
+
+type EventsConfig struct {
+	Sink    string `env:"EVENTS_SINK" env-default:"log"`
+	NATSURL string `env:"NATS_URL" env-default:"nats://localhost:4222" secret:"true"`
+	// Subject prefixes the event type, e.g. blog.post.created
+	Subject string        `env:"NATS_SUBJECT" env-default:"blog"`
+	Timeout time.Duration `env:"NATS_TIMEOUT" env-default:"5s"`
+}
+
+// Event is a change the relay hands to a sink, Key is the post it is about
+type Event struct {
+	Id        uuid.UUID
+	Type      string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type Sink interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+// New returns the sink EVENTS_SINK names
+func New(cfg EventsConfig) (Sink, error) {
+	switch cfg.Sink {
+	case SinkLog:
+		return &LogSink{}, nil
+	case SinkNATS:
+		return NewNATSSink(cfg)
+	default:
+		return nil, fmt.Errorf("unknown EVENTS_SINK %q, use %s or %s", cfg.Sink, SinkLog, SinkNATS)
+	}
+}
+
+// LogSink writes events to the log instead of sending them, meant for development
+type LogSink struct{}
+
+func (s *LogSink) Publish(ctx context.Context, event Event) error {
+	slog.InfoContext(ctx, "event",
+		slog.String("event_id", event.Id.String()),
+		slog.String("type", event.Type),
+		slog.String("key", event.Key),
+		slog.String("payload", string(event.Payload)),
+	)
+	return nil
+}
+
+func (s *LogSink) Close() error {
+	return nil
+}
+
+// NATSSink publishes every event on Subject.Type. A publish returns once the server got it, a JetStream
+// stream over the subjects keeps them for consumers that are away and drops redeliveries by Nats-Msg-Id.
+type NATSSink struct {
+	conn    *nats.Conn
+	subject string
+	timeout time.Duration
+}
+
+func NewNATSSink(cfg EventsConfig) (*NATSSink, error) {
+	conn, err := nats.Connect(cfg.NATSURL, nats.Name("blog"), nats.Timeout(cfg.Timeout))
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+	return &NATSSink{conn, cfg.Subject, cfg.Timeout}, nil
+}
+
+func (s *NATSSink) Publish(ctx context.Context, event Event) error {
+	msg := nats.NewMsg(s.subject + "." + event.Type)
+	msg.Header.Set(nats.MsgIdHdr, event.Id.String())
+	msg.Header.Set("Blog-Post-Id", event.Key)
+	msg.Data = event.Payload
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// the flush waits for the server, a message lost on the way fails here instead of silently
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.conn.FlushWithContext(ctx)
+}
+
+// Close sends what is still buffered before it disconnects
+func (s *NATSSink) Close() error {
+	return s.conn.Drain()
+}
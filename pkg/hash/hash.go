@@ -1,16 +1,114 @@
+// Package hash hashes passwords with argon2id. Hashes made earlier with bcrypt are still checked, so
+// users keep logging in while their hashes are replaced.
 package hash
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-func HashPassword(str string) (string, error) {
-	hashedStr, err := bcrypt.GenerateFromPassword([]byte(str), bcrypt.DefaultCost)
-	if err != nil {
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+	saltLength     = 16
+	keyLength      = 32
+
+	// MinMemory and MinIterations are the weakest argon2id settings the OWASP password storage cheat sheet
+	// lists, the configuration refuses anything below
+	MinMemory     = 19 * 1024
+	MinIterations = 2
+)
+
+var (
+	ErrUnknownScheme = errors.New("unknown password hash scheme")
+	ErrMalformedHash = errors.New("malformed password hash")
+)
+
+// Params of argon2id, Memory is in KiB
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultParams hash the passwords of users not made by the server, like the admins of the CLI
+var DefaultParams = Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2}
+
+// Hash returns the argon2id hash of password in the PHC string format, with a random salt
+func (p Params) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(hashedStr), nil
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, keyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// NeedsRehash tells whether hash was made by another scheme or other params than p
+func (p Params) NeedsRehash(hash string) bool {
+	params, _, _, err := parseArgon2id(hash)
+	return err != nil || params != p
+}
+
+// Hash hashes password with DefaultParams
+func Hash(password string) (string, error) {
+	return DefaultParams.Hash(password)
+}
+
+// Compare checks password against hash, telling argon2id from bcrypt by the prefix of hash
+func Compare(hash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		params, salt, key, err := parseArgon2id(hash)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	default:
+		return false, ErrUnknownScheme
+	}
 }
 
-func CheckPasswordHash(str, hash string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(str))
-	return err == nil, err
+// parseArgon2id reads $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func parseArgon2id(hash string) (Params, []byte, []byte, error) {
+	var params Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+	return params, salt, key, nil
 }
@@ -0,0 +1,110 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keep the tests fast, they are below the minimums on purpose
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestParams_HashAndCompare(t *testing.T) {
+	hashed, err := testParams.Hash("Password123!")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hashed, "$argon2id$v=19$m=1024,t=1,p=1$"), hashed)
+
+	ok, err := Compare(hashed, "Password123!")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = Compare(hashed, "password123!")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	again, err := testParams.Hash("Password123!")
+	require.NoError(t, err)
+	assert.NotEqual(t, hashed, again, "every hash gets its own salt")
+}
+
+func TestCompare_Schemes(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Password123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	current, err := testParams.Hash("Password123!")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+		wantErr  error
+	}{
+		{name: "bcrypt", hash: string(legacy), password: "Password123!", want: true},
+		{name: "bcrypt wrong password", hash: string(legacy), password: "wrong", want: false},
+		{name: "bcrypt 2b", hash: "$2b$" + string(legacy)[4:], password: "Password123!", want: true},
+		{name: "argon2id", hash: current, password: "Password123!", want: true},
+		{name: "argon2id wrong password", hash: current, password: "wrong", want: false},
+		{name: "unknown scheme", hash: "$scrypt$whatever", password: "Password123!", wantErr: ErrUnknownScheme},
+		{name: "plain text", hash: "Password123!", password: "Password123!", wantErr: ErrUnknownScheme},
+		{name: "broken argon2id", hash: "$argon2id$v=19$m=1024,t=1$salt$key", password: "Password123!", wantErr: ErrMalformedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Compare(tt.hash, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, ok)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestParams_NeedsRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Password123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	current, err := testParams.Hash("Password123!")
+	require.NoError(t, err)
+
+	assert.True(t, testParams.NeedsRehash(string(legacy)), "bcrypt hashes are replaced")
+	assert.False(t, testParams.NeedsRehash(current))
+	assert.True(t, Params{Memory: 2048, Iterations: 1, Parallelism: 1}.NeedsRehash(current), "so are hashes of other params")
+	assert.True(t, testParams.NeedsRehash("$argon2id$garbage"))
+}
+
+func TestParseArgon2id(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		want    Params
+		wantErr bool
+	}{
+		{name: "params", hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5a2V5", want: Params{Memory: 65536, Iterations: 3, Parallelism: 2}},
+		{name: "other version", hash: "$argon2id$v=16$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5a2V5", wantErr: true},
+		{name: "argon2i", hash: "$argon2i$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$a2V5a2V5", wantErr: true},
+		{name: "missing parallelism", hash: "$argon2id$v=19$m=65536,t=3$c2FsdHNhbHQ$a2V5a2V5", wantErr: true},
+		{name: "zero memory", hash: "$argon2id$v=19$m=0,t=3,p=2$c2FsdHNhbHQ$a2V5a2V5", wantErr: true},
+		{name: "bad salt", hash: "$argon2id$v=19$m=65536,t=3,p=2$not base64!$a2V5a2V5", wantErr: true},
+		{name: "empty key", hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHQ$", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, salt, key, err := parseArgon2id(tt.hash)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrMalformedHash)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, params)
+			assert.Equal(t, "saltsalt", string(salt))
+			assert.Equal(t, "keykey", string(key))
+		})
+	}
+}
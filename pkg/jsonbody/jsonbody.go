@@ -0,0 +1,149 @@
+// Package jsonbody decodes request bodies, strictly when the server runs with STRICT_JSON, so a
+// misspelt field is reported to the client instead of being dropped without a word.
+package jsonbody
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/mailru/easyjson"
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+// StrictError is a body strict mode rejects although it is valid JSON, Error names the offending field
+type StrictError struct {
+	msg string
+}
+
+func (e *StrictError) Error() string {
+	return e.msg
+}
+
+func WithStrict(ctx context.Context) context.Context {
+	return context.WithValue(ctx, types.CtxStrictJSON, true)
+}
+
+// IsStrict reports whether WithStrict was called for the request
+func IsStrict(ctx context.Context) bool {
+	strict, _ := ctx.Value(types.CtxStrictJSON).(bool)
+	return strict
+}
+
+// Decode reads data into v, with easyjson when v was generated for it and encoding/json otherwise. In strict
+// mode it first rejects keys v has no field for, keys given twice in one object and anything behind the
+// value with a StrictError, then v decodes as it would otherwise.
+func Decode(data []byte, v any, strict bool) error {
+	if strict {
+		if err := check(data, v); err != nil {
+			return err
+		}
+	}
+	if unmarshaler, ok := v.(easyjson.Unmarshaler); ok {
+		return easyjson.Unmarshal(data, unmarshaler)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func check(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	keys, err := walk(dec)
+	if err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &StrictError{msg: "unexpected data after the JSON object"}
+	}
+
+	fields := fieldNames(reflect.TypeOf(v))
+	if fields == nil {
+		return nil
+	}
+	for _, key := range keys {
+		if _, ok := fields[key]; !ok {
+			return &StrictError{msg: fmt.Sprintf("unknown field %q", key)}
+		}
+	}
+	return nil
+}
+
+// walk reads one value from dec and returns the keys of it if it is an object,
+// a key repeated in this or any nested object is an error
+func walk(dec *json.Decoder) ([]string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+	switch token {
+	case json.Delim('['):
+		for dec.More() {
+			if _, err := walk(dec); err != nil {
+				return nil, err
+			}
+		}
+		_, err = dec.Token()
+		return nil, syntaxError(err)
+	case json.Delim('{'):
+		var keys []string
+		seen := map[string]bool{}
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, syntaxError(err)
+			}
+			key := token.(string)
+			if seen[key] {
+				return nil, &StrictError{msg: fmt.Sprintf("duplicate field %q", key)}
+			}
+			seen[key] = true
+			keys = append(keys, key)
+			if _, err := walk(dec); err != nil {
+				return nil, err
+			}
+		}
+		_, err = dec.Token()
+		return keys, syntaxError(err)
+	}
+	return nil, nil
+}
+
+// syntaxError keeps a body that is no JSON at all an ordinary decoding error
+func syntaxError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// fieldNames returns the keys the struct t points to takes, by their json tag like easyjson reads them,
+// or nil if t is no struct
+func fieldNames(t reflect.Type) map[string]struct{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := map[string]struct{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields[name] = struct{}{}
+	}
+	return fields
+}
@@ -0,0 +1,110 @@
+package jsonbody
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// note is decoded the way easyjson generates it, unknown keys are skipped
+type note struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags,omitempty"`
+	Draft bool     `json:"-"`
+}
+
+func (n *note) UnmarshalEasyJSON(in *jlexer.Lexer) {
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "title":
+			n.Title = in.String()
+		case "tags":
+			n.Tags = nil
+			in.Delim('[')
+			for !in.IsDelim(']') {
+				n.Tags = append(n.Tags, in.String())
+				in.WantComma()
+			}
+			in.Delim(']')
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		// laxTitle is what the lax mode decodes, as the handlers always did
+		laxTitle   string
+		laxErr     bool
+		wantStrict string
+	}{
+		{name: "known fields", body: `{"title":"Go","tags":["a","b"]}`, laxTitle: "Go"},
+		{name: "whitespace after object", body: "{\"title\":\"Go\"}\n", laxTitle: "Go"},
+		{name: "unknown field", body: `{"titel":"Go"}`, laxTitle: "", wantStrict: `unknown field "titel"`},
+		{name: "ignored field", body: `{"title":"Go","Draft":true}`, laxTitle: "Go", wantStrict: `unknown field "Draft"`},
+		{name: "duplicate field", body: `{"title":"Go","title":"Rust"}`, laxTitle: "Rust", wantStrict: `duplicate field "title"`},
+		{name: "second object", body: `{"title":"Go"}{"title":"Rust"}`, laxTitle: "Go", wantStrict: "unexpected data after the JSON object"},
+		{name: "trailing garbage", body: `{"title":"Go"} x`, laxTitle: "Go", wantStrict: "unexpected data after the JSON object"},
+		{name: "not json", body: `{"title":`, laxErr: true, wantStrict: "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" lax", func(t *testing.T) {
+			n := &note{}
+			err := Decode([]byte(tt.body), n, false)
+			if tt.laxErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.laxTitle, n.Title)
+		})
+
+		t.Run(tt.name+" strict", func(t *testing.T) {
+			n := &note{}
+			err := Decode([]byte(tt.body), n, true)
+			if tt.wantStrict == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "Go", n.Title)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantStrict)
+			// a body that is no JSON stays an ordinary error, the handlers answer it as before
+			var strictErr *StrictError
+			assert.Equal(t, !tt.laxErr, errors.As(err, &strictErr))
+		})
+	}
+}
+
+func TestDecode_WithoutEasyJSON(t *testing.T) {
+	type plain struct {
+		Title string `json:"title"`
+	}
+
+	p := &plain{}
+	require.NoError(t, Decode([]byte(`{"title":"Go","titel":"Rust"}`), p, false))
+	assert.Equal(t, "Go", p.Title)
+
+	err := Decode([]byte(`{"title":"Go","titel":"Rust"}`), &plain{}, true)
+	var strictErr *StrictError
+	assert.True(t, errors.As(err, &strictErr))
+	assert.Error(t, Decode([]byte(`{"title":`), &plain{}, false))
+}
+
+func TestIsStrict(t *testing.T) {
+	assert.False(t, IsStrict(context.Background()))
+	assert.True(t, IsStrict(WithStrict(context.Background())))
+}
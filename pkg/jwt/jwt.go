@@ -1,57 +1,183 @@
 package jwt
 
 import (
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/xkarasb/blog/pkg/errors"
 )
 
-func NewAccessToken(id uuid.UUID, secret string, ttl time.Duration) string {
-	token := jwt.New(jwt.SigningMethodHS512)
-	token.Claims = jwt.MapClaims{
-		"sub": id,
-		"exp": time.Now().Add(ttl).Unix(),
-		"iat": time.Now().Unix(),
+// Keys sign new tokens with Primary. During a rotation Previous, the secret Primary replaced,
+// still verifies the tokens signed before it.
+type Keys struct {
+	Primary  string
+	Previous string
+}
+
+// kid names a secret in the token header without revealing it
+func kid(secret string) string {
+	sum := sha256.Sum256([]byte("kid:" + secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (k Keys) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	token.Header["kid"] = kid(k.Primary)
+	return token.SignedString([]byte(k.Primary))
+}
+
+// verification picks the secret named by kid, a token without one was signed before kids and may use either
+func (k Keys) verification(t *jwt.Token) (interface{}, error) {
+	secrets := []string{k.Primary}
+	if k.Previous != "" {
+		secrets = append(secrets, k.Previous)
 	}
-	tokenString, _ := token.SignedString([]byte(secret))
-	return tokenString
+
+	id, ok := t.Header["kid"].(string)
+	if !ok {
+		set := jwt.VerificationKeySet{}
+		for _, secret := range secrets {
+			set.Keys = append(set.Keys, []byte(secret))
+		}
+		return set, nil
+	}
+	for _, secret := range secrets {
+		if id == kid(secret) {
+			return []byte(secret), nil
+		}
+	}
+	return nil, errors.ErrorInvalidToken
+}
+
+// Options are the standard claims a deployment puts into its tokens and expects back
+type Options struct {
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew allowed when checking exp and nbf
+	Leeway time.Duration
+	// AllowLegacy accepts tokens minted before iss, aud and jti were set, a token carrying any of them is checked in full
+	AllowLegacy bool
+	// Clock defaults to time.Now
+	Clock func() time.Time
 }
 
-func NewRefreshToken(email, secret string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS512)
-	token.Claims = jwt.MapClaims{
-		"sub": email,
-		"exp": time.Now().Add(time.Hour * 24 * 7).Unix(),
-		"iat": time.Now().Unix(),
+func (o Options) now() time.Time {
+	if o.Clock == nil {
+		return time.Now()
 	}
-	return token.SignedString([]byte(secret))
+	return o.Clock()
 }
 
-func ValidateToken(accessToken, secret string) (*jwt.MapClaims, error) {
-	token, err := jwt.Parse(accessToken, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+func (o Options) claims(sub any, ttl time.Duration) jwt.MapClaims {
+	now := o.now()
+	return jwt.MapClaims{
+		"sub": sub,
+		"iss": o.Issuer,
+		"aud": o.Audience,
+		"jti": uuid.NewString(),
+		"exp": now.Add(ttl).Unix(),
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+	}
+}
+
+func NewAccessToken(id uuid.UUID, keys Keys, ttl time.Duration, opts Options) string {
+	tokenString, _ := keys.sign(opts.claims(id, ttl))
+	return tokenString
+}
+
+// NewSessionAccessToken is an access token naming the session it was issued for in sid
+func NewSessionAccessToken(id, sessionId uuid.UUID, keys Keys, ttl time.Duration, opts Options) string {
+	claims := opts.claims(id, ttl)
+	claims["sid"] = sessionId.String()
+	tokenString, _ := keys.sign(claims)
+	return tokenString
+}
 
+// SessionId is the sid of validated claims, uuid.Nil for tokens issued without one
+func SessionId(claims *jwt.MapClaims) uuid.UUID {
+	raw, _ := (*claims)["sid"].(string)
+	id, err := uuid.Parse(raw)
 	if err != nil {
-		return nil, err
+		return uuid.Nil
 	}
+	return id
+}
 
-	if token == nil {
-		return nil, fmt.Errorf("invalid token")
+func NewRefreshToken(email string, keys Keys, ttl time.Duration, opts Options) (string, error) {
+	return keys.sign(opts.claims(email, ttl))
+}
+
+// derive is the keys of tokens made for purpose, a token signed with them never validates as an access or refresh token
+func (k Keys) derive(purpose string) Keys {
+	derived := Keys{Primary: purpose + ":" + k.Primary}
+	if k.Previous != "" {
+		derived.Previous = purpose + ":" + k.Previous
 	}
+	return derived
+}
 
-	if !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+// NewEmailToken proves whoever holds it reads the mail of email, the user it was sent to is sub
+func NewEmailToken(id uuid.UUID, email string, keys Keys, ttl time.Duration, opts Options) (string, error) {
+	claims := opts.claims(id, ttl)
+	claims["email"] = email
+	return keys.derive("email").sign(claims)
+}
+
+// ValidateEmailToken returns the user and email of a token of NewEmailToken, every failure is errors.ErrorInvalidToken
+func ValidateEmailToken(token string, keys Keys, opts Options) (uuid.UUID, string, error) {
+	opts.AllowLegacy = false
+	claims, err := ValidateToken(token, keys.derive("email"), opts)
+	if err != nil {
+		return uuid.Nil, "", err
 	}
+	sub, _ := claims.GetSubject()
+	id, err := uuid.Parse(sub)
+	email, _ := (*claims)["email"].(string)
+	if err != nil || email == "" {
+		return uuid.Nil, "", errors.ErrorInvalidToken
+	}
+	return id, email, nil
+}
 
-	data, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+// ValidateToken checks the signature against either key, the lifetime and the standard claims of a token,
+// every failure is errors.ErrorInvalidToken
+func ValidateToken(accessToken string, keys Keys, opts Options) (*jwt.MapClaims, error) {
+	data := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, data, keys.verification,
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithTimeFunc(opts.now),
+	)
+	if err != nil {
+		return nil, errors.ErrorInvalidToken
+	}
+
+	if opts.AllowLegacy && isLegacy(data) {
+		return &data, nil
+	}
+	if iss, _ := data.GetIssuer(); iss != opts.Issuer {
+		return nil, errors.ErrorInvalidToken
+	}
+	if aud, _ := data.GetAudience(); !slices.Contains(aud, opts.Audience) {
+		return nil, errors.ErrorInvalidToken
+	}
+	if jti, _ := data["jti"].(string); jti == "" {
+		return nil, errors.ErrorInvalidToken
 	}
 	return &data, nil
 }
+
+func isLegacy(claims jwt.MapClaims) bool {
+	for _, name := range []string{"iss", "aud", "jti"} {
+		if _, ok := claims[name]; ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xkarasb/blog/pkg/errors"
+)
+
+const testSecret = "test-secret"
+
+var testKeys = Keys{Primary: testSecret}
+
+func sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestValidateToken(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	opts := Options{Issuer: "blog", Audience: "blog-api", Leeway: 30 * time.Second, Clock: func() time.Time { return now }}
+	legacy := opts
+	legacy.AllowLegacy = true
+
+	full := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"sub": uuid.NewString(),
+			"iss": "blog",
+			"aud": "blog-api",
+			"jti": uuid.NewString(),
+			"exp": now.Add(time.Hour).Unix(),
+			"nbf": now.Unix(),
+			"iat": now.Unix(),
+		}
+		if change != nil {
+			change(claims)
+		}
+		return claims
+	}
+	old := jwt.MapClaims{"sub": uuid.NewString(), "exp": now.Add(time.Hour).Unix(), "iat": now.Unix()}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		opts   Options
+		valid  bool
+	}{
+		{"all claims", full(nil), opts, true},
+		{"audience list", full(func(c jwt.MapClaims) { c["aud"] = []string{"admin", "blog-api"} }), opts, true},
+		{"expired within leeway", full(func(c jwt.MapClaims) { c["exp"] = now.Add(-29 * time.Second).Unix() }), opts, true},
+		{"expired past leeway", full(func(c jwt.MapClaims) { c["exp"] = now.Add(-31 * time.Second).Unix() }), opts, false},
+		{"not before within leeway", full(func(c jwt.MapClaims) { c["nbf"] = now.Add(29 * time.Second).Unix() }), opts, true},
+		{"not before past leeway", full(func(c jwt.MapClaims) { c["nbf"] = now.Add(31 * time.Second).Unix() }), opts, false},
+		{"no expiry", full(func(c jwt.MapClaims) { delete(c, "exp") }), opts, false},
+		{"wrong audience", full(func(c jwt.MapClaims) { c["aud"] = "staging-api" }), opts, false},
+		{"wrong issuer", full(func(c jwt.MapClaims) { c["iss"] = "staging" }), opts, false},
+		{"no id", full(func(c jwt.MapClaims) { delete(c, "jti") }), opts, false},
+		{"legacy token", old, opts, false},
+		{"legacy token with grace", old, legacy, true},
+		{"partial claims with grace", full(func(c jwt.MapClaims) { delete(c, "aud"); delete(c, "jti") }), legacy, false},
+		{"wrong audience with grace", full(func(c jwt.MapClaims) { c["aud"] = "staging-api" }), legacy, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ValidateToken(sign(t, tt.claims), testKeys, tt.opts)
+			if !tt.valid {
+				assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+				assert.Nil(t, claims)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.claims["sub"], (*claims)["sub"])
+		})
+	}
+}
+
+func TestValidateToken_Signature(t *testing.T) {
+	opts := Options{Issuer: "blog", Audience: "blog-api"}
+	token := NewAccessToken(uuid.New(), testKeys, time.Hour, opts)
+
+	_, err := ValidateToken(token, Keys{Primary: "other-secret"}, opts)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, opts.claims(uuid.New(), time.Hour)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = ValidateToken(unsigned, testKeys, opts)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+}
+
+func TestNewAccessToken_Claims(t *testing.T) {
+	opts := Options{Issuer: "blog", Audience: "blog-api"}
+	id := uuid.New()
+
+	first, err := ValidateToken(NewAccessToken(id, testKeys, time.Hour, opts), testKeys, opts)
+	require.NoError(t, err)
+	second, err := ValidateToken(NewAccessToken(id, testKeys, time.Hour, opts), testKeys, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, id.String(), (*first)["sub"])
+	assert.Equal(t, "blog", (*first)["iss"])
+	assert.NotEqual(t, (*first)["jti"], (*second)["jti"])
+}
+
+func TestNewSessionAccessToken(t *testing.T) {
+	opts := Options{Issuer: "blog", Audience: "blog-api"}
+	id, sessionId := uuid.New(), uuid.New()
+
+	claims, err := ValidateToken(NewSessionAccessToken(id, sessionId, testKeys, time.Hour, opts), testKeys, opts)
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), (*claims)["sub"])
+	assert.Equal(t, sessionId, SessionId(claims))
+
+	claims, err = ValidateToken(NewAccessToken(id, testKeys, time.Hour, opts), testKeys, opts)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, SessionId(claims), "tokens issued without a session have none")
+}
+
+func TestEmailToken(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	opts := Options{Issuer: "blog", Audience: "blog-api", Clock: func() time.Time { return now }}
+	id := uuid.New()
+
+	token, err := NewEmailToken(id, "author@example.com", testKeys, time.Hour, opts)
+	require.NoError(t, err)
+	gotId, email, err := ValidateEmailToken(token, testKeys, opts)
+	require.NoError(t, err)
+	assert.Equal(t, id, gotId)
+	assert.Equal(t, "author@example.com", email)
+
+	_, err = ValidateToken(token, testKeys, opts)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken, "an email token is no access token")
+	_, _, err = ValidateEmailToken(NewAccessToken(id, testKeys, time.Hour, opts), testKeys, opts)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken, "an access token is no email token")
+
+	rotating := Keys{Primary: "new-secret", Previous: testSecret}
+	_, _, err = ValidateEmailToken(token, rotating, opts)
+	assert.NoError(t, err, "links mailed before a rotation keep working")
+
+	later := opts
+	later.Clock = func() time.Time { return now.Add(2 * time.Hour) }
+	_, _, err = ValidateEmailToken(token, testKeys, later)
+	assert.ErrorIs(t, err, errors.ErrorInvalidToken, "expired")
+}
+
+func TestValidateToken_Rotation(t *testing.T) {
+	opts := Options{Issuer: "blog", Audience: "blog-api"}
+	old := Keys{Primary: "old-secret"}
+	rotating := Keys{Primary: "new-secret", Previous: "old-secret"}
+	rotated := Keys{Primary: "new-secret"}
+
+	signedOld := NewAccessToken(uuid.New(), old, time.Hour, opts)
+	signedNew := NewAccessToken(uuid.New(), rotating, time.Hour, opts)
+	// tokens minted before kid headers were added
+	noKid, err := jwt.NewWithClaims(jwt.SigningMethodHS512, opts.claims(uuid.New(), time.Hour)).SignedString([]byte("old-secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		keys  Keys
+		valid bool
+	}{
+		{"old token while previous is set", signedOld, rotating, true},
+		{"old token once previous is cleared", signedOld, rotated, false},
+		{"new token while previous is set", signedNew, rotating, true},
+		{"new token once previous is cleared", signedNew, rotated, true},
+		{"new token on a server not rotated yet", signedNew, old, false},
+		{"token without kid while previous is set", noKid, rotating, true},
+		{"token without kid once previous is cleared", noKid, rotated, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateToken(tt.token, tt.keys, opts)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errors.ErrorInvalidToken)
+			}
+		})
+	}
+}
+
+func TestNewAccessToken_SignsWithPrimary(t *testing.T) {
+	token := NewAccessToken(uuid.New(), Keys{Primary: "new-secret", Previous: "old-secret"}, time.Hour, Options{})
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, kid("new-secret"), parsed.Header["kid"])
+	assert.NotEqual(t, kid("old-secret"), parsed.Header["kid"])
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Redacted replaces the value of a secret attribute
+const Redacted = "[REDACTED]"
+
+// secretKeys are parts of attribute keys whose values never reach the output, password_hash and refresh_token included
+var secretKeys = []string{"password", "token", "secret"}
+
+type LoggingConfig struct {
+	// Level is debug, info, warn or error, optionally with an offset as in warn+2
+	Level  string `env:"LOG_LEVEL" env-default:"info"`
+	Format string `env:"LOG_FORMAT" env-default:"text"`
+	// AddSource adds the file and line of the log call to every record
+	AddSource bool `env:"LOG_ADD_SOURCE" env-default:"FALSE"`
+}
+
+// NewHandler builds the handler writing records of cfg.Level and above to w in cfg.Format,
+// secrets are redacted by Redact
+func NewHandler(cfg LoggingConfig, w io.Writer) (slog.Handler, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("unknown LOG_LEVEL %q, use debug, info, warn or error", cfg.Level)
+	}
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		AddSource:   cfg.AddSource,
+		ReplaceAttr: Redact,
+	}
+
+	switch strings.ToLower(cfg.Format) {
+	case FormatText:
+		return slog.NewTextHandler(w, opts), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown LOG_FORMAT %q, use %s or %s", cfg.Format, FormatText, FormatJSON)
+	}
+}
+
+// Redact is a slog.HandlerOptions.ReplaceAttr hiding the values of attributes named after a password,
+// a token or a secret, nested in groups or not
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	return a
+}
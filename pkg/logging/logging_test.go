@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler, err := NewHandler(LoggingConfig{Level: "info", Format: "json"}, buf)
+	require.NoError(t, err)
+	logger := slog.New(handler)
+
+	logger.Debug("hidden")
+	logger.Info("user logged in",
+		slog.String("user_id", "42"),
+		slog.String("password", "hunter2"),
+		slog.String("refresh_token", "rt"),
+		slog.Group("request", slog.String("Authorization_Token", "bearer"), slog.Int("status", 200)),
+	)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "user logged in", record["msg"])
+	assert.Equal(t, "42", record["user_id"])
+	assert.Equal(t, Redacted, record["password"])
+	assert.Equal(t, Redacted, record["refresh_token"])
+	assert.Equal(t, map[string]interface{}{"Authorization_Token": Redacted, "status": float64(200)}, record["request"])
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, record, "source")
+}
+
+func TestNewHandler_Text(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler, err := NewHandler(LoggingConfig{Level: "DEBUG", Format: "TEXT", AddSource: true}, buf)
+	require.NoError(t, err)
+
+	slog.New(handler).With(slog.String("access_token", "at")).Debug("refresh", slog.String("email", "a@example.com"))
+
+	out := buf.String()
+	assert.Contains(t, out, "level=DEBUG")
+	assert.Contains(t, out, "msg=refresh")
+	assert.Contains(t, out, "email=a@example.com")
+	assert.Contains(t, out, "access_token="+Redacted)
+	assert.Contains(t, out, "source=")
+	assert.NotContains(t, out, "=at ")
+}
+
+func TestNewHandler_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  LoggingConfig
+		want string
+	}{
+		{name: "level", cfg: LoggingConfig{Level: "verbose", Format: "text"}, want: "LOG_LEVEL"},
+		{name: "format", cfg: LoggingConfig{Level: "info", Format: "logfmt"}, want: "LOG_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHandler(tt.cfg, &bytes.Buffer{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
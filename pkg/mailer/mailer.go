@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type MailerConfig struct {
+	SMTPHost     string `env:"SMTP_HOST" env-default:""`
+	SMTPPort     int    `env:"SMTP_PORT" env-default:"587"`
+	SMTPUser     string `env:"SMTP_USER" env-default:""`
+	SMTPPassword string `env:"SMTP_PASSWORD" env-default:"" secret:"true"`
+	From         string `env:"SMTP_FROM" env-default:"noreply@localhost"`
+}
+
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// New returns an SMTP mailer, or a logging one when no SMTP host is configured
+func New(cfg MailerConfig) Mailer {
+	if cfg.SMTPHost == "" {
+		return &LogMailer{}
+	}
+	return NewSMTPMailer(cfg)
+}
+
+type SMTPMailer struct {
+	cfg  MailerConfig
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(cfg MailerConfig) *SMTPMailer {
+	var auth smtp.Auth
+	if cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return &SMTPMailer{cfg, auth}
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.cfg.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := net.JoinHostPort(m.cfg.SMTPHost, strconv.Itoa(m.cfg.SMTPPort))
+	return smtp.SendMail(addr, m.auth, m.cfg.From, []string{to}, []byte(msg))
+}
+
+// LogMailer writes mails to the log instead of sending them, meant for development.
+// Bodies carry reset links, they are logged only at debug level.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	slog.Info("mail", slog.String("to", to), slog.String("subject", subject))
+	slog.Debug("mail body", slog.String("to", to), slog.String("body", body))
+	return nil
+}
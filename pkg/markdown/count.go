@@ -0,0 +1,25 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Counts are the words of a text as PlainText has them and the lines of its code blocks
+type Counts struct {
+	Words     int
+	CodeLines int
+}
+
+// Count counts the words and code lines of source. A word is a run of characters between spaces with
+// at least one letter or digit in it, so a dash standing on its own is none and "что-то" is one.
+func Count(source string) Counts {
+	plain, codeLines := plainText(source)
+	words := 0
+	for _, field := range strings.Fields(plain) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) >= 0 {
+			words++
+		}
+	}
+	return Counts{Words: words, CodeLines: codeLines}
+}
@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCount_Fixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    Counts
+	}{
+		// 30 words of prose, the dash, the image and the raw HTML are none, the blank code line is not counted
+		{"english.md", Counts{Words: 30, CodeLines: 3}},
+		// "что-то" and "2025" are one word each, the dash is none, the indented block is code
+		{"russian.md", Counts{Words: 20, CodeLines: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			source, err := os.ReadFile("testdata/" + tt.fixture)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, Count(string(source)))
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   Counts
+	}{
+		{"empty", "", Counts{}},
+		{"only markup", "---\n\n**", Counts{}},
+		{"punctuation only", "- — … !", Counts{}},
+		{"line breaks", "one\ntwo  \nthree", Counts{Words: 3}},
+		{"emphasis inside a word", "in*credi*ble", Counts{Words: 1}},
+		{"cyrillic with punctuation", "Привет, мир! Как дела?", Counts{Words: 4}},
+		{"only code", "```\na\n\nb\n```", Counts{CodeLines: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Count(tt.source))
+		})
+	}
+}
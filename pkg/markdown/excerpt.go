@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ellipsis ends an excerpt that was cut short
+const ellipsis = "…"
+
+// Excerpt returns the text of source without any Markdown, cut to at most limit characters. A cut
+// text ends at the last whole word that fits and an ellipsis, only a single word longer than limit
+// is cut within. Characters are runes, an accent written as a combining mark counts on its own.
+func Excerpt(source string, limit int) string {
+	if limit < 1 {
+		return ""
+	}
+	plain := strings.Join(strings.Fields(PlainText(source)), " ")
+	runes := []rune(plain)
+	if len(runes) <= limit {
+		return plain
+	}
+
+	// one character is kept for the ellipsis, the cut is at a word boundary when the next one is a space
+	end := limit - 1
+	if runes[end] != ' ' {
+		word := end
+		for word > 0 && runes[word] != ' ' {
+			word--
+		}
+		if word > 0 {
+			end = word
+		} else {
+			// a word longer than limit is cut within, but not between a letter and its accents
+			for end > 0 && unicode.Is(unicode.Mn, runes[end]) {
+				end--
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:end]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + ellipsis
+}
@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	source := "# Title\n\nSome *emphasis*, **strong** and `code`.\n\n- one\n- two\n\n```go\nx := 1\n```\n\n" +
+		"![diagram](/media/1.png) [a link](https://example.com) <b>raw</b> &amp; \\*stars\\* <https://auto.example.com>"
+
+	assert.Equal(t, "Title Some emphasis, strong and code. one two a link raw & *stars* https://auto.example.com",
+		strings.Join(strings.Fields(PlainText(source)), " "))
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		limit  int
+		want   string
+	}{
+		{"short text is kept", "Hello *world*", 20, "Hello world"},
+		{"exactly the limit", "Hello world", 11, "Hello world"},
+		{"cut at the last whole word", "Hello wonderful world", 12, "Hello…"},
+		{"cut right before a space", "Hello world again", 12, "Hello world…"},
+		{"punctuation before the cut is dropped", "One, two, three", 10, "One, two…"},
+		{"whitespace is collapsed", "one\n\n\ntwo   three", 20, "one two three"},
+		{"cyrillic", "Привет, прекрасный мир", 16, "Привет…"},
+		{"emoji", "🎉🎉 🎉🎉🎉 🎉", 6, "🎉🎉…"},
+		{"one long word is cut within", "日本語のテキストです", 5, "日本語の…"},
+		{"accents stay with their letter", "e\u0301e\u0301e\u0301 e\u0301e\u0301", 6, "e\u0301e\u0301…"},
+		{"markup does not count", "**bold** [link](https://example.com/a/very/long/path) end", 14, "bold link end"},
+		{"only markup", "![image](/media/1.png)", 10, ""},
+		{"no room", "Hello", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Excerpt(tt.source, tt.limit)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, utf8.ValidString(got), "a rune was split")
+			assert.LessOrEqual(t, utf8.RuneCountInString(got), tt.limit)
+		})
+	}
+}
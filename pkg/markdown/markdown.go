@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// Renderer turns CommonMark into HTML safe to put into a page as is. Raw HTML of the source is dropped
+// by the renderer already, the sanitizer then keeps only paragraphs, links, code, headings, lists and
+// basic emphasis, and images hosted by the blog itself.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+// New makes a renderer keeping the images at a path of the api host and those on imageHosts, a host
+// may carry a port like minio:9000
+func New(imageHosts []string) *Renderer {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("p", "br", "hr", "em", "strong", "blockquote", "pre", "code", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "li")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	policy.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+
+	policy.AllowStandardURLs()
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.RequireNoFollowOnFullyQualifiedLinks(true)
+	policy.AllowAttrs("src").Matching(imageSource(imageHosts)).OnElements("img")
+	policy.AllowAttrs("alt", "title").OnElements("img")
+
+	return &Renderer{md: goldmark.New(), policy: policy}
+}
+
+// imageSource matches a path on the api host or a link to one of hosts. //host and /\host are links
+// to another host for browsers, a path must not start with either.
+func imageSource(hosts []string) *regexp.Regexp {
+	pattern := `^/[^/\\]`
+	if len(hosts) > 0 {
+		quoted := make([]string, len(hosts))
+		for i, host := range hosts {
+			quoted[i] = regexp.QuoteMeta(strings.ToLower(host))
+		}
+		pattern += `|^https?://(` + strings.Join(quoted, "|") + `)/`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+// Render converts source and sanitizes the result
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return r.policy.Sanitize(buf.String()), nil
+}
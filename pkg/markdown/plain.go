@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// PlainText returns the words of source as a reader sees them: text of paragraphs, headings,
+// lists, links and inline code. Code blocks, images and raw HTML are left out.
+func PlainText(source string) string {
+	plain, _ := plainText(source)
+	return plain
+}
+
+// plainText is PlainText together with the number of lines in the code blocks it left out,
+// blank ones not counted
+func plainText(source string) (string, int) {
+	src := []byte(source)
+	doc := goldmark.DefaultParser().Parse(text.NewReader(src))
+
+	var b strings.Builder
+	codeLines := 0
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			// blocks are apart even when the source has them on adjacent lines
+			if node.Type() == ast.TypeBlock {
+				b.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+		switch node := node.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := node.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				if len(bytes.TrimSpace(line.Value(src))) > 0 {
+					codeLines++
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			b.Write(util.UnescapePunctuations(node.Segment.Value(src)))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		case *ast.AutoLink:
+			b.Write(node.Label(src))
+		}
+		return ast.WalkContinue, nil
+	})
+	return html.UnescapeString(b.String()), codeLines
+}
@@ -0,0 +1,62 @@
+// Package slogctx carries request scoped values into slog records,
+// so any layer holding a context can log with the request id.
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/xkarasb/blog/pkg/types"
+)
+
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, types.CtxReqId, id)
+}
+
+// RequestId returns the id stored by WithRequestId or an empty string
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(types.CtxReqId).(string)
+	return id
+}
+
+// requestAttrs collects what inner layers learn about a request, e.g. the user once authenticated
+type requestAttrs struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// WithAttrs makes room for AddAttrs further down the chain. The attributes are shared with ctx itself,
+// so the layer calling WithAttrs logs them too once the request was handled.
+func WithAttrs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, types.CtxLogAttrs, &requestAttrs{})
+}
+
+// AddAttrs attaches attrs to every record Logger makes for the request from now on,
+// they are dropped when no layer above called WithAttrs
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	if ra, ok := ctx.Value(types.CtxLogAttrs).(*requestAttrs); ok {
+		ra.mu.Lock()
+		ra.attrs = append(ra.attrs, attrs...)
+		ra.mu.Unlock()
+	}
+}
+
+// Logger returns the default logger with the request id and the attributes of AddAttrs attached when ctx has them
+func Logger(ctx context.Context) *slog.Logger {
+	var args []any
+	if id := RequestId(ctx); id != "" {
+		args = append(args, slog.String("request_id", id))
+	}
+	if ra, ok := ctx.Value(types.CtxLogAttrs).(*requestAttrs); ok {
+		ra.mu.Lock()
+		for _, attr := range ra.attrs {
+			args = append(args, attr)
+		}
+		ra.mu.Unlock()
+	}
+	if len(args) == 0 {
+		return slog.Default()
+	}
+	return slog.Default().With(args...)
+}
@@ -12,19 +12,27 @@ import (
 
 type MinIOConfig struct {
 	Endpoint   string `env:"MINIO_ENDPOINT" env-default:"localhost:9000"`
-	AccessKey  string `env:"MINIO_ACCESSKEY" env-default:"minioadmin"`
-	Secret     string `env:"MINIO_SECRET" env-default:"minioadmin"`
+	AccessKey  string `env:"MINIO_ACCESSKEY" env-default:"minioadmin" secret:"true"`
+	Secret     string `env:"MINIO_SECRET" env-default:"minioadmin" secret:"true"`
 	UseSSL     bool   `env:"MINIO_SSL" env-default:"FALSE"`
 	BucketName string `env:"MINIO_BUCKET" env-default:"images"`
+	// OpTimeout bounds a single upload or removal, zero leaves them to the request alone
+	OpTimeout time.Duration `env:"MINIO_OP_TIMEOUT" env-default:"1m"`
+	// MaxAttempts is how often an upload or removal is tried before a transient failure is reported
+	MaxAttempts int `env:"MINIO_MAX_ATTEMPTS" env-default:"3"`
 }
 
 type MinIOClient struct {
 	Client     *minio.Client
 	BucketName string
-	config     MinIOConfig
+	// Public buckets get a policy letting anyone read the objects
+	Public      bool
+	OpTimeout   time.Duration
+	MaxAttempts int
+	config      MinIOConfig
 }
 
-func NewMinIOClient(cfg MinIOConfig) (*MinIOClient, error) {
+func NewMinIOClient(cfg MinIOConfig, public bool) (*MinIOClient, error) {
 	client, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.Secret, ""),
 		Secure: cfg.UseSSL,
@@ -34,9 +42,12 @@ func NewMinIOClient(cfg MinIOConfig) (*MinIOClient, error) {
 	}
 
 	mc := &MinIOClient{
-		Client:     client,
-		BucketName: cfg.BucketName,
-		config:     cfg,
+		Client:      client,
+		BucketName:  cfg.BucketName,
+		Public:      public,
+		OpTimeout:   cfg.OpTimeout,
+		MaxAttempts: cfg.MaxAttempts,
+		config:      cfg,
 	}
 
 	if err := mc.ensureBucketExists(); err != nil {
@@ -64,6 +75,12 @@ func (mc *MinIOClient) ensureBucketExists() error {
 
 		log.Printf("Bucket '%s' created successfully", mc.BucketName)
 	}
+
+	if !mc.Public {
+		// an empty policy drops the public read left over from running with a public bucket
+		mc.Client.SetBucketPolicy(ctx, mc.BucketName, "")
+		return nil
+	}
 	policy := fmt.Sprintf(
 		`{
     "Version": "2012-10-17",
@@ -81,3 +98,15 @@ func (mc *MinIOClient) ensureBucketExists() error {
 
 	return nil
 }
+
+// PingContext reports whether the storage is reachable and the bucket is still there
+func (mc *MinIOClient) PingContext(ctx context.Context) error {
+	exists, err := mc.Client.BucketExists(ctx, mc.BucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", mc.BucketName)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Config has no keys, credentials come from the default AWS chain: environment, shared config or an IAM role
+type S3Config struct {
+	Region string `env:"S3_REGION" env-default:"us-east-1"`
+	Bucket string `env:"S3_BUCKET" env-default:"images"`
+	// Endpoint replaces AWS, e.g. MinIO for compatibility testing, objects are then addressed by path
+	Endpoint string `env:"S3_ENDPOINT"`
+	// BaseURL is put in front of object names for links of a public bucket, e.g. a CloudFront distribution
+	BaseURL string `env:"S3_BASE_URL"`
+}
+
+type S3Client struct {
+	Client  *s3.Client
+	Presign *s3.PresignClient
+	Bucket  string
+	// BaseURL ends without a slash
+	BaseURL string
+}
+
+func NewS3Client(ctx context.Context, cfg S3Config) (*S3Client, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3Client{
+		Client:  client,
+		Presign: s3.NewPresignClient(client),
+		Bucket:  cfg.Bucket,
+		BaseURL: baseURL(cfg),
+	}, nil
+}
+
+// baseURL is where public objects are read: the configured one, the bucket under a custom endpoint
+// or the virtual-hosted address of the bucket on AWS
+func baseURL(cfg S3Config) string {
+	switch {
+	case cfg.BaseURL != "":
+		return strings.TrimSuffix(cfg.BaseURL, "/")
+	case cfg.Endpoint != "":
+		return strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	default:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	}
+}
+
+// PingContext reports whether the bucket is reachable with the credentials at hand
+func (c *S3Client) PingContext(ctx context.Context) error {
+	_, err := c.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(c.Bucket)})
+	return err
+}
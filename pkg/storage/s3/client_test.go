@@ -0,0 +1,25 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      S3Config
+		expected string
+	}{
+		{name: "aws", cfg: S3Config{Region: "eu-west-1", Bucket: "images"}, expected: "https://images.s3.eu-west-1.amazonaws.com"},
+		{name: "cloudfront", cfg: S3Config{Region: "eu-west-1", Bucket: "images", BaseURL: "https://d1.cloudfront.net/"}, expected: "https://d1.cloudfront.net"},
+		{name: "custom endpoint", cfg: S3Config{Bucket: "images", Endpoint: "http://localhost:9000"}, expected: "http://localhost:9000/images"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, baseURL(tt.cfg))
+		})
+	}
+}
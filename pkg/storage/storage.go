@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	BackendMinIO = "minio"
+	BackendS3    = "s3"
+	BackendFS    = "fs"
+)
+
+// Config picks where uploaded images are kept and how clients reach them
+type Config struct {
+	Backend string `env:"STORAGE_BACKEND" env-default:"minio"`
+	// Dir is the root directory of the fs backend
+	Dir string `env:"STORAGE_DIR" env-default:"./media"`
+	// Public lets anyone read the images, otherwise they are reachable only through presigned links valid for PresignTTL
+	Public     bool          `env:"PUBLIC_BUCKET" env-default:"TRUE"`
+	PresignTTL time.Duration `env:"MINIO_PRESIGN_TTL" env-default:"15m"`
+}
+
+// AvatarPrefix keeps the avatars of users apart from the images of posts, ListImages never sees them
+const AvatarPrefix = "avatars/"
+
+// ImageObject is an object as the storage lists it
+type ImageObject struct {
+	Name         string
+	LastModified time.Time
+}
+
+// ImageStorage keeps the images of posts. PutImage returns what is stored as image_url: a link for a public
+// storage, the object name for a private one which is read through PresignGet. Uploads and removals stop
+// when ctx is done and then fail with an error wrapping errors.ErrorRepositoryStorageTimeout.
+type ImageStorage interface {
+	PutImage(ctx context.Context, objectName string, file io.Reader, fileSize int64, contentType string) (string, error)
+	DeleteImage(ctx context.Context, objectName string) error
+	GetImage(objectName string) (io.ReadCloser, string, error)
+	ImageExists(objectName string) (bool, error)
+	Public() bool
+	PresignGet(objectName string, ttl time.Duration) (string, error)
+	// ListImages passes every object at the top level of the storage to emit and stops at the first error it returns
+	ListImages(ctx context.Context, emit func(ImageObject) error) error
+}
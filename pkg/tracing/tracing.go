@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector, e.g. http://localhost:4318, tracing is off without it
+	Endpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" env-default:""`
+	// SamplingRatio is the share of traces started here that are kept, a sampled caller is always followed
+	SamplingRatio float64 `env:"OTEL_SAMPLING_RATIO" env-default:"1"`
+	ServiceName   string  `env:"OTEL_SERVICE_NAME" env-default:"blog"`
+}
+
+// New returns a provider exporting to the configured collector, or a no-op one when no endpoint is configured.
+// The provider is also made the global one, repositories start their spans from it.
+// The returned func flushes the spans still queued, call it on shutdown.
+func New(ctx context.Context, cfg TracingConfig) (trace.TracerProvider, func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg.Endpoint == "" {
+		provider := noop.NewTracerProvider()
+		otel.SetTracerProvider(provider)
+		return provider, func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, nil, err
+	}
+	resource, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider, provider.Shutdown, nil
+}
+
+// Enabled reports whether spans of provider go anywhere, a disabled one is left out of the request chain
+func Enabled(provider trace.TracerProvider) bool {
+	if provider == nil {
+		return false
+	}
+	_, disabled := provider.(noop.TracerProvider)
+	return !disabled
+}
+
+// Start opens a span of the named component under the span in ctx. The global provider is looked up
+// on every call, so spans follow the provider set last.
+func Start(ctx context.Context, component, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(component).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// End marks the span failed when err is set and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
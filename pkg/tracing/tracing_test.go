@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestNew(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name        string
+		cfg         TracingConfig
+		wantEnabled bool
+	}{
+		{name: "no endpoint", cfg: TracingConfig{SamplingRatio: 1, ServiceName: "blog"}},
+		{name: "collector", cfg: TracingConfig{Endpoint: "http://127.0.0.1:4318", SamplingRatio: 0, ServiceName: "blog"}, wantEnabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, shutdown, err := New(context.Background(), tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, Enabled(provider))
+			assert.Equal(t, provider, otel.GetTracerProvider())
+
+			_, span := Start(context.Background(), "test", "op")
+			assert.Equal(t, tt.wantEnabled, span.SpanContext().IsValid())
+			End(span, nil)
+			// a ratio of zero samples nothing, so shutting down never reaches the collector
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestEnabled_Nil(t *testing.T) {
+	assert.False(t, Enabled(nil))
+}
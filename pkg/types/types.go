@@ -2,12 +2,79 @@ package types
 
 type Role string //	@name	TypeUserRole
 type ContextKey string
-type PostStatus string //	@name	TypePostStatus
+type PostStatus string  //	@name	TypePostStatus
+type PostSort string    //	@name	TypePostSort
+type SortOrder string   //	@name	TypeSortOrder
+type AuditAction string //	@name	TypeAuditAction
+type EventType string
+type NotificationType string //	@name	TypeNotificationType
+type ReportReason string     //	@name	TypeReportReason
+type ModerationAction string //	@name	TypeModerationAction
+type DeletionPolicy string
+type Scope string //	@name	TypeApiKeyScope
 
 const (
-	Author    Role       = "author"
-	Reader    Role       = "reader"
-	CtxUser   ContextKey = "user"
-	Draft     PostStatus = "draft"     //	@name	DraftStatus
-	Published PostStatus = "published" //	@name	PublishedStatus
+	Author        Role       = "author"
+	Reader        Role       = "reader"
+	Admin         Role       = "admin"
+	CtxUser       ContextKey = "user"
+	CtxSession    ContextKey = "session"
+	CtxScopes     ContextKey = "scopes"
+	CtxReqId      ContextKey = "request_id"
+	CtxClient     ContextKey = "client"
+	CtxAPIVersion ContextKey = "api_version"
+	CtxLogAttrs   ContextKey = "log_attrs"
+	CtxStrictJSON ContextKey = "strict_json"
+	CtxURL        ContextKey = "url"
+	Draft         PostStatus = "draft"     //	@name	DraftStatus
+	Published     PostStatus = "published" //	@name	PublishedStatus
+	Archived      PostStatus = "archived"  //	@name	ArchivedStatus
+	Scheduled     PostStatus = "scheduled" //	@name	ScheduledStatus
+
+	SortCreatedAt PostSort  = "created_at"
+	SortUpdatedAt PostSort  = "updated_at"
+	SortTitle     PostSort  = "title"
+	Asc           SortOrder = "asc"
+	Desc          SortOrder = "desc"
+
+	AuditRegister      AuditAction = "register"
+	AuditLogin         AuditAction = "login"
+	AuditLoginFailed   AuditAction = "login_failed"
+	AuditRefresh       AuditAction = "refresh"
+	AuditLogout        AuditAction = "logout"
+	AuditPasswordReset AuditAction = "password_reset"
+	AuditVerifyEmail   AuditAction = "verify_email"
+	AuditPublishPost   AuditAction = "publish_post"
+	AuditDeletePost    AuditAction = "delete_post"
+	AuditDeleteImage   AuditAction = "delete_image"
+	// moderation of reported posts, the admin is the user and the post the target
+	AuditUnpublishPost  AuditAction = "unpublish_post"
+	AuditDismissReports AuditAction = "dismiss_reports"
+	// the deleted user is the target, nobody is left to be the user
+	AuditDeleteAccount AuditAction = "delete_account"
+	// the key is the target
+	AuditCreateApiKey AuditAction = "create_api_key"
+	AuditRevokeApiKey AuditAction = "revoke_api_key"
+
+	EventPostCreated       EventType = "post.created"
+	EventPostStatusChanged EventType = "post.status_changed"
+	EventPostDeleted       EventType = "post.deleted"
+
+	NotificationPostPublished NotificationType = "post.published"
+
+	ReportSpam      ReportReason = "spam"
+	ReportAbuse     ReportReason = "abuse"
+	ReportCopyright ReportReason = "copyright"
+	ReportOther     ReportReason = "other"
+
+	ModerationDismiss   ModerationAction = "dismiss"
+	ModerationUnpublish ModerationAction = "unpublish"
+
+	// what an API key may do, read covers GET, HEAD and OPTIONS and every key has it
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+
+	// what becomes of the posts of a deleted account
+	DeletionAnonymize DeletionPolicy = "anonymize"
+	DeletionDelete    DeletionPolicy = "delete"
 )
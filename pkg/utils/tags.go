@@ -0,0 +1,21 @@
+package utils
+
+import "strings"
+
+// NormalizeTags trims and lowercases tags and drops repeats, empty tags are kept for validation to reject
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
@@ -1,8 +1,101 @@
 package utils
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/validator/v10"
+)
+
+// validate caches the rules of every struct it has seen, fields are named by their json tag
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError is a rule a field broke, Field is its json name and, inside lists, its index, e.g. tags[2]
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// ValidationError lists every field of a struct that broke one of its rules
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks s against its validate tags, broken rules come back as a *ValidationError
 func Validate(s interface{}) error {
-	validate := validator.New()
-	return validate.Struct(s)
+	err := validate.Struct(s)
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	fields := make([]FieldError, len(invalid))
+	for i, fieldErr := range invalid {
+		fields[i] = FieldError{Field: fieldName(fieldErr), Rule: fieldErr.Tag(), Message: ruleMessage(fieldErr)}
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// fieldName is the path of the field below the struct, e.g. tags[2] or a nested settings.theme
+func fieldName(fieldErr validator.FieldError) string {
+	_, name, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return name
+}
+
+// ruleMessage says what the rule wants in words a form can show next to the field
+func ruleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "min":
+		return bound("at least", fieldErr.Kind(), param)
+	case "max":
+		return bound("at most", fieldErr.Kind(), param)
+	}
+	return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+}
+
+// bound words min and max, a length for strings and lists and a value for numbers
+func bound(limit string, kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		return "must be " + limit + " " + param + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "must have " + limit + " " + param + " items"
+	}
+	return "must be " + limit + " " + param
 }
@@ -0,0 +1,50 @@
+// Package version tells what the binary was built from. The release build sets it with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/xkarasb/blog/pkg/version.Version=v1.4.0 \
+//		-X github.com/xkarasb/blog/pkg/version.Commit=$(git rev-parse HEAD) \
+//		-X github.com/xkarasb/blog/pkg/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// unset stands in for anything the build did not set, so it reads as a development build
+const unset = "dev"
+
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+// Info is the build of the running binary
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+}
+
+// Get returns the build info, "dev" for what was not set. Without -ldflags the commit and date come
+// from the version control info go build records when it runs inside a checkout.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, Date: Date, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			}
+		}
+	}
+	for _, value := range []*string{&info.Version, &info.Commit, &info.Date} {
+		if *value == "" {
+			*value = unset
+		}
+	}
+	return info
+}
@@ -0,0 +1,24 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		// test binaries carry no version control info, what -ldflags would set stays dev
+		assert.Equal(t, Info{Version: "dev", Commit: "dev", Date: "dev", GoVersion: runtime.Version()}, Get())
+	})
+
+	t.Run("set at build time", func(t *testing.T) {
+		defer func(version, commit, date string) {
+			Version, Commit, Date = version, commit, date
+		}(Version, Commit, Date)
+		Version, Commit, Date = "v1.4.0", "5f06797", "2026-10-15T12:00:00Z"
+
+		assert.Equal(t, Info{Version: "v1.4.0", Commit: "5f06797", Date: "2026-10-15T12:00:00Z", GoVersion: runtime.Version()}, Get())
+	})
+}